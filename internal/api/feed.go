@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// feedLimit is the default number of entries in the Atom feed.
+const feedLimit = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// handleFeed serves recent captures as an Atom feed, newest first. It takes
+// the same filters as /events and /search (q, domain, source, browser,
+// since, until, limit).
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !r.URL.Query().Has("limit") {
+		q.Limit = feedLimit
+	}
+	q.Sort = storage.SortNewest
+
+	events, err := s.store.SearchEvents(r.Context(), q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+
+	feed := atomFeed{
+		ID:     "urn:chronicle:feed",
+		Title:  "Chronicle captures",
+		Author: atomPerson{Name: "Chronicle"},
+		Links:  []atomLink{{Href: selfURL(r), Rel: "self"}},
+	}
+	updated := time.Unix(0, 0)
+	for i := range events {
+		e := &events[i]
+		if e.Timestamp.After(updated) {
+			updated = e.Timestamp
+		}
+		feed.Entries = append(feed.Entries, toAtomEntry(e))
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header)) //nolint:errcheck
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	enc.Encode(feed) //nolint:errcheck
+}
+
+func toAtomEntry(e *storage.Event) atomEntry {
+	title := e.Title
+	if title == "" {
+		title = e.URL
+	}
+	ts := e.Timestamp.UTC().Format(time.RFC3339)
+	entry := atomEntry{
+		ID:        "urn:chronicle:event:" + e.ID,
+		Title:     title,
+		Link:      atomLink{Href: e.URL},
+		Published: ts,
+		Updated:   ts,
+		Summary:   e.URL,
+	}
+	for _, term := range []string{e.Domain, e.Source} {
+		if term != "" {
+			entry.Categories = append(entry.Categories, atomCategory{Term: term})
+		}
+	}
+	return entry
+}
+
+// selfURL reconstructs the feed's own URL without the token parameter, so
+// it is never echoed back into the document.
+func selfURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	v := r.URL.Query()
+	v.Del("token")
+	u := scheme + "://" + r.Host + r.URL.Path
+	if enc := v.Encode(); enc != "" {
+		u += "?" + enc
+	}
+	return u
+}
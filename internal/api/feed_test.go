@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getFeed(t *testing.T, url string, header bool) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if header {
+		req.Header.Set("Authorization", "Bearer "+testToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func TestAPI_Feed(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	resp, body := getFeed(t, srv.URL+"/feed.atom", true)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(body, &feed))
+	assert.Equal(t, "Chronicle captures", feed.Title)
+	require.Len(t, feed.Entries, 3)
+	assert.Equal(t, "Go Blog", feed.Entries[0].Title, "newest first")
+	assert.Equal(t, "https://go.dev/blog", feed.Entries[0].Link.Href)
+	assert.Contains(t, feed.Entries[0].ID, "urn:chronicle:event:CHR-")
+	assert.Equal(t, feed.Entries[0].Updated, feed.Updated)
+	assert.Equal(t, []atomCategory{{Term: "go.dev"}, {Term: "extension"}}, feed.Entries[0].Categories)
+}
+
+func TestAPI_FeedFiltersAndQueryToken(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	resp, body := getFeed(t, srv.URL+"/feed.atom?domain=github.com&limit=1&token="+testToken, false)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(body, &feed))
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, "GitHub X", feed.Entries[0].Title)
+	require.Len(t, feed.Links, 1)
+	assert.NotContains(t, feed.Links[0].Href, testToken, "token is not echoed into the feed")
+	assert.Contains(t, feed.Links[0].Href, "domain=github.com")
+}
+
+func TestAPI_FeedRequiresToken(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	resp, _ := getFeed(t, srv.URL+"/feed.atom?token=wrong", false)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = getFeed(t, srv.URL+"/stats?token="+testToken, false)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "query tokens are only for the feed")
+}
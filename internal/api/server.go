@@ -0,0 +1,277 @@
+// Package api implements Chronicle's read-only HTTP query API. It is
+// separate from the ingest daemon and never writes to the store.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/openapi"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// maxLimit caps the number of events a single request may return.
+const maxLimit = 500
+
+// Server serves read-only queries over a Store.
+type Server struct {
+	store storage.Store
+	token string
+}
+
+// NewServer creates an API server. Every request must present token as a
+// bearer token.
+func NewServer(store storage.Store, token string) *Server {
+	return &Server{store: store, token: token}
+}
+
+// Handler returns the HTTP handler with all routes and auth applied.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /events/{id}", s.handleEvent)
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /feed.atom", s.handleFeed)
+	mux.HandleFunc("GET "+openapi.Path, openapi.Handler)
+	return s.requireToken(mux)
+}
+
+// requireToken rejects requests without a matching bearer token. The feed
+// also accepts the token as a token query parameter, since most feed
+// readers cannot send custom headers. The OpenAPI document is public.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == openapi.Path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if got == "" && r.URL.Path == "/feed.atom" {
+			got = r.URL.Query().Get("token")
+		}
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// eventJSON is the API representation of an event.
+type eventJSON struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	RawTitle     string `json:"raw_title,omitempty"`
+	Domain       string `json:"domain"`
+	Timestamp    string `json:"timestamp"`
+	Source       string `json:"source"`
+	Browser      string `json:"browser,omitempty"`
+	Lang         string `json:"lang,omitempty"`
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	ReadSeconds  int64  `json:"read_seconds,omitempty"`
+	Visits       int    `json:"visits,omitempty"`
+	HasBody      bool   `json:"has_body"`
+	HasEmbedding bool   `json:"has_embedding"`
+	Body         string `json:"body,omitempty"`
+}
+
+type eventListJSON struct {
+	Count  int         `json:"count"`
+	Total  int64       `json:"total"`
+	Query  string      `json:"query,omitempty"`
+	Events []eventJSON `json:"events"`
+}
+
+type domainCountJSON struct {
+	Domain string `json:"domain"`
+	Count  int64  `json:"count"`
+}
+
+type statsJSON struct {
+	TotalEvents  int64             `json:"total_events"`
+	TotalContent int64             `json:"total_content"`
+	OldestEvent  string            `json:"oldest_event,omitempty"`
+	NewestEvent  string            `json:"newest_event,omitempty"`
+	TopDomains   []domainCountJSON `json:"top_domains"`
+	Sources      []valueCountJSON  `json:"sources"`
+	Browsers     []valueCountJSON  `json:"browsers"`
+}
+
+type valueCountJSON struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func toValueCountsJSON(counts []storage.ValueCount) []valueCountJSON {
+	out := make([]valueCountJSON, len(counts))
+	for i, vc := range counts {
+		out[i] = valueCountJSON{Name: vc.Value, Count: vc.Count}
+	}
+	return out
+}
+
+func toEventJSON(e *storage.Event) eventJSON {
+	return eventJSON{
+		ID:           e.ID,
+		URL:          e.URL,
+		Title:        e.Title,
+		RawTitle:     e.RawTitle,
+		Domain:       e.Domain,
+		Timestamp:    e.Timestamp.UTC().Format(time.RFC3339),
+		Source:       e.Source,
+		Browser:      e.Browser,
+		Lang:         e.Lang,
+		CanonicalURL: e.CanonicalURL,
+		ReadSeconds:  int64(e.ReadTime / time.Second),
+		Visits:       e.Visits,
+		HasBody:      e.HasBody,
+		HasEmbedding: e.HasEmbed,
+	}
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.Query = ""
+	s.writeEvents(w, r.Context(), q)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if q.Query == "" {
+		writeError(w, http.StatusBadRequest, "q parameter is required")
+		return
+	}
+	s.writeEvents(w, r.Context(), q)
+}
+
+func (s *Server) writeEvents(w http.ResponseWriter, ctx context.Context, q storage.SearchQuery) {
+	events, err := s.store.SearchEvents(ctx, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+	total, err := s.store.CountEvents(ctx, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+
+	out := eventListJSON{Count: len(events), Total: total, Query: q.Query, Events: make([]eventJSON, len(events))}
+	for i := range events {
+		out.Events[i] = toEventJSON(&events[i])
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	event, err := s.store.GetEvent(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("event %s not found", id))
+		return
+	}
+
+	out := toEventJSON(event)
+	if event.HasBody {
+		if content, err := s.store.GetContent(r.Context(), id); err == nil {
+			out.Body = content.Body
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.store.GetStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "stats failed")
+		return
+	}
+
+	out := statsJSON{
+		TotalEvents:  stats.TotalEvents,
+		TotalContent: stats.TotalContent,
+		TopDomains:   make([]domainCountJSON, len(stats.TopDomains)),
+		Sources:      toValueCountsJSON(stats.Sources),
+		Browsers:     toValueCountsJSON(stats.Browsers),
+	}
+	if stats.TotalEvents > 0 {
+		out.OldestEvent = stats.OldestEvent.UTC().Format(time.RFC3339)
+		out.NewestEvent = stats.NewestEvent.UTC().Format(time.RFC3339)
+	}
+	for i, d := range stats.TopDomains {
+		out.TopDomains[i] = domainCountJSON{Domain: d.Domain, Count: d.Count}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseQuery builds a SearchQuery from URL parameters: q, domain, source,
+// browser, lang, keyword, since/until (RFC 3339), limit, and offset.
+func parseQuery(r *http.Request) (storage.SearchQuery, error) {
+	v := r.URL.Query()
+	q := storage.SearchQuery{
+		Query:   v.Get("q"),
+		Domain:  v.Get("domain"),
+		Source:  v.Get("source"),
+		Browser: v.Get("browser"),
+		Lang:    strings.ToLower(v.Get("lang")),
+		Keyword: storage.NormalizeKeyword(v.Get("keyword")),
+		Limit:   50,
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &q.Since}, {"until", &q.Until}} {
+		if raw := v.Get(p.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s: use RFC 3339 (e.g., 2026-01-02T15:04:05Z)", p.name)
+			}
+			*p.dst = t
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"limit", &q.Limit}, {"offset", &q.Offset}} {
+		if raw := v.Get(p.name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid %s: must be a non-negative integer", p.name)
+			}
+			*p.dst = n
+		}
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+
+	return q, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/openapi"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+const testToken = "secret-token"
+
+// setupServer creates a migrated in-memory store seeded with a few events
+// and returns a test HTTP server in front of it.
+func setupServer(t *testing.T) (*httptest.Server, *storage.SQLiteStore) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, store.AddEventWithContent(ctx, &storage.Event{URL: "https://go.dev/blog", Title: "Go Blog", Source: "extension", Timestamp: now.Add(-time.Hour)}, "blog body"))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://github.com/x", Title: "GitHub X", Source: "manual", Timestamp: now.Add(-2 * time.Hour)}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://github.com/y", Title: "GitHub Y", Source: "extension", Timestamp: now.Add(-48 * time.Hour)}))
+
+	srv := httptest.NewServer(NewServer(store, testToken).Handler())
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestAPI_RequiresToken(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	resp, err := http.Get(srv.URL + "/stats")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/stats", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestAPI_EmptyTokenRejectsEverything(t *testing.T) {
+	srv := httptest.NewServer(NewServer(nil, "").Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/stats")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestAPI_ListEvents(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	var out eventListJSON
+	status := get(t, srv, "/events?domain=github.com", &out)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, out.Count)
+	assert.Equal(t, "GitHub X", out.Events[0].Title, "events should be newest first")
+}
+
+func TestAPI_ListEventsSinceAndLimit(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	since := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	var out eventListJSON
+	status := get(t, srv, "/events?since="+since+"&limit=1", &out)
+	assert.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, out.Count)
+	assert.Equal(t, int64(2), out.Total, "total ignores the limit")
+	assert.Equal(t, "Go Blog", out.Events[0].Title)
+}
+
+func TestAPI_GetEvent(t *testing.T) {
+	srv, store := setupServer(t)
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Query: "blog"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	var out eventJSON
+	status := get(t, srv, "/events/"+events[0].ID, &out)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "blog body", out.Body)
+
+	var errOut map[string]string
+	status = get(t, srv, "/events/CHR-missing", &errOut)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, errOut["error"], "not found")
+}
+
+func TestAPI_Search(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	var out eventListJSON
+	status := get(t, srv, "/search?q=github", &out)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, out.Count)
+	assert.Equal(t, "github", out.Query)
+
+	status = get(t, srv, "/search", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_Stats(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	var out statsJSON
+	status := get(t, srv, "/stats", &out)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(3), out.TotalEvents)
+	assert.Equal(t, int64(1), out.TotalContent)
+	assert.Equal(t, "github.com", out.TopDomains[0].Domain)
+}
+
+func TestAPI_BadParameters(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, srv, "/events?since=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, get(t, srv, "/events?limit=-1", nil))
+}
+
+func TestAPI_RejectsWrites(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/events/CHR-x", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestOpenAPI_PublicAndRouted(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	resp, err := http.Get(srv.URL + openapi.Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	// Every documented query endpoint must be routed by this server; capture
+	// endpoints belong to the daemon.
+	for path, item := range doc.Paths {
+		raw, ok := item["get"]
+		if !ok {
+			continue
+		}
+		var op struct {
+			Tags []string `json:"tags"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &op))
+		if slices.Contains(op.Tags, "capture") {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodGet, srv.URL+strings.Replace(path, "{id}", "CHR-00000000", 1)+"?q=go", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NotEqual(t, "404 page not found\n", string(body), path)
+		assert.NotEqual(t, http.StatusMethodNotAllowed, resp.StatusCode, path)
+	}
+}
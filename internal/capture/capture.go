@@ -0,0 +1,152 @@
+// Package capture stores captured pages. Every way into Chronicle (the
+// add, clip, capture and pull commands, the ingest daemon, and the RPC,
+// MCP and gRPC servers) captures through a Pipeline, so each runs the same
+// steps in the same order: pre-capture hooks, content hash, store, then
+// post-capture hooks, webhooks and the OnCapture callback.
+package capture
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/runnerr0/chronicle/internal/webhook"
+)
+
+// ErrExcluded is returned, wrapped with the domain, when the store skips
+// an event because its domain is excluded.
+var ErrExcluded = errors.New("excluded by exclusion rules")
+
+// Pipeline captures events into a store.
+type Pipeline struct {
+	store     storage.Store
+	hooks     *hook.Runner
+	webhooks  *webhook.Dispatcher
+	onCapture func(ctx context.Context, e *storage.Event, body string)
+	warn      func(err error)
+
+	async   bool
+	pending sync.WaitGroup // background Stored calls
+}
+
+// New returns a Pipeline over store with no hooks or webhooks.
+func New(store storage.Store) *Pipeline {
+	return &Pipeline{
+		store:    store,
+		hooks:    hook.New(config.HooksConfig{}),
+		webhooks: webhook.New(nil),
+	}
+}
+
+// SetHooks runs hooks before and after each capture.
+func (p *Pipeline) SetHooks(hooks *hook.Runner) {
+	p.hooks = hooks
+}
+
+// SetWebhooks delivers each stored event to webhooks.
+func (p *Pipeline) SetWebhooks(webhooks *webhook.Dispatcher) {
+	p.webhooks = webhooks
+}
+
+// SetOnCapture calls fn with each stored event and its body, after the
+// post-capture hooks and webhooks.
+func (p *Pipeline) SetOnCapture(fn func(ctx context.Context, e *storage.Event, body string)) {
+	p.onCapture = fn
+}
+
+// SetWarn calls fn with each failed post-capture hook and webhook
+// delivery, besides logging it.
+func (p *Pipeline) SetWarn(fn func(err error)) {
+	p.warn = fn
+}
+
+// SetAsync makes Capture return as soon as the event is stored, running
+// the post-capture hooks, webhooks and OnCapture callback in the
+// background, so a server can answer without waiting on them. Wait waits
+// for them to finish.
+func (p *Pipeline) SetAsync(async bool) {
+	p.async = async
+}
+
+// Wait waits for the background steps of earlier captures to finish.
+func (p *Pipeline) Wait() {
+	p.pending.Wait()
+}
+
+// Capture stores e with body, if any. A pre-capture hook's veto is
+// returned wrapping hook.ErrVetoed, an excluded domain wrapping
+// ErrExcluded, and a store error wrapping it, e.g. storage.ErrBodyTooLarge.
+// Once e is stored, failures of the later steps are only reported.
+func (p *Pipeline) Capture(ctx context.Context, e *storage.Event, body string) error {
+	if err := p.Prepare(ctx, e, &body); err != nil {
+		return err
+	}
+	var err error
+	if body != "" {
+		err = p.store.AddEventWithContent(ctx, e, body)
+	} else {
+		err = p.store.AddEvent(ctx, e)
+	}
+	if err != nil {
+		return fmt.Errorf("storing event: %w", err)
+	}
+	if e.ID == "" {
+		return fmt.Errorf("domain %q is %w", e.Domain, ErrExcluded)
+	}
+	if p.async {
+		// The steps outlive the caller's request, so they keep only its
+		// values, not its cancellation, and get their own copy of e.
+		stored := *e
+		p.pending.Add(1)
+		go func() {
+			defer p.pending.Done()
+			p.Stored(context.WithoutCancel(ctx), &stored, body)
+		}()
+		return nil
+	}
+	p.Stored(ctx, e, body)
+	return nil
+}
+
+// Prepare runs the steps before storing: the pre-capture hooks, which may
+// rewrite e and body, then e's content hash. Batch importers call it for
+// each event and Stored for each one AddEvents stored.
+func (p *Pipeline) Prepare(ctx context.Context, e *storage.Event, body *string) error {
+	if err := p.hooks.Pre(ctx, e, body); err != nil {
+		return err
+	}
+	if *body != "" {
+		e.ContentHash = fmt.Sprintf("%x", sha256.Sum256([]byte(*body)))
+	}
+	return nil
+}
+
+// Stored runs the steps after storing e: the post-capture hooks, the
+// webhooks, then the OnCapture callback. Failures are reported, not
+// returned, since e is stored either way.
+func (p *Pipeline) Stored(ctx context.Context, e *storage.Event, body string) {
+	for _, err := range p.hooks.Post(ctx, e, body) {
+		slog.Warn("hook failed", "event", e.ID, "err", err)
+		p.report(err)
+	}
+	for _, err := range p.webhooks.Notify(ctx, e) {
+		slog.Warn("webhook delivery failed", "event", e.ID, "err", err)
+		p.report(err)
+	}
+	if p.onCapture != nil {
+		p.onCapture(ctx, e, body)
+	}
+}
+
+// report passes err to the warn callback, if any.
+func (p *Pipeline) report(err error) {
+	if p.warn != nil {
+		p.warn(err)
+	}
+}
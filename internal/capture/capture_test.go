@@ -0,0 +1,198 @@
+package capture
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/runnerr0/chronicle/internal/webhook"
+)
+
+func openTestStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// writeHook writes an executable shell script and returns its path.
+func writeHook(t *testing.T, name, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return path
+}
+
+func TestPipeline_Capture(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	var delivered []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhook.Payload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		delivered = append(delivered, p.ID)
+	}))
+	defer srv.Close()
+
+	postLog := filepath.Join(t.TempDir(), "post.log")
+	pre := writeHook(t, "pre", `sed 's/"title":"Draft"/"title":"Final"/'`+"\n")
+	post := writeHook(t, "post", "cat >> "+postLog+"\n")
+
+	p := New(store)
+	p.SetHooks(hook.New(config.HooksConfig{PreCapture: []string{pre}, PostCapture: []string{post}}))
+	p.SetWebhooks(webhook.New([]config.WebhookConfig{{URL: srv.URL}}))
+	var captured []string
+	p.SetOnCapture(func(ctx context.Context, e *storage.Event, body string) {
+		captured = append(captured, e.ID+" "+body)
+	})
+
+	e := &storage.Event{URL: "https://go.dev/blog/", Title: "Draft", Source: "manual", Timestamp: time.Now()}
+	require.NoError(t, p.Capture(ctx, e, "The Go Blog"))
+	require.NotEmpty(t, e.ID)
+	assert.Equal(t, "Final", e.Title)
+	assert.Len(t, e.ContentHash, 64)
+	assert.Equal(t, []string{e.ID}, delivered)
+	assert.Equal(t, []string{e.ID + " The Go Blog"}, captured)
+	logged, err := os.ReadFile(postLog)
+	require.NoError(t, err)
+	assert.Contains(t, string(logged), e.ID)
+
+	stored, err := store.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Final", stored.Title)
+	content, err := store.GetContent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "The Go Blog", content.Body)
+}
+
+func TestPipeline_Rejected(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	var captured int
+	onCapture := func(ctx context.Context, e *storage.Event, body string) { captured++ }
+
+	p := New(store)
+	p.SetOnCapture(onCapture)
+	e := &storage.Event{URL: "https://chase.com/login", Title: "Bank", Source: "manual", Timestamp: time.Now()}
+	err := p.Capture(ctx, e, "")
+	assert.ErrorIs(t, err, ErrExcluded)
+	assert.ErrorContains(t, err, `domain "chase.com" is excluded`)
+
+	veto := writeHook(t, "veto", "echo 'private page' >&2\nexit 1\n")
+	p.SetHooks(hook.New(config.HooksConfig{PreCapture: []string{veto}}))
+	e = &storage.Event{URL: "https://go.dev/", Title: "Go", Source: "manual", Timestamp: time.Now()}
+	err = p.Capture(ctx, e, "")
+	assert.ErrorIs(t, err, hook.ErrVetoed)
+	assert.ErrorContains(t, err, "private page")
+
+	assert.Zero(t, captured)
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+}
+
+func TestPipeline_Async(t *testing.T) {
+	store := openTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	var captured []string
+	p := New(store)
+	p.SetAsync(true)
+	p.SetOnCapture(func(ctx context.Context, e *storage.Event, body string) {
+		<-release
+		assert.NoError(t, ctx.Err())
+		captured = append(captured, e.ID)
+	})
+
+	e := &storage.Event{URL: "https://go.dev/", Title: "Go", Source: "extension", Timestamp: time.Now()}
+	require.NoError(t, p.Capture(ctx, e, ""))
+	require.NotEmpty(t, e.ID)
+	cancel() // as when the request that captured e is answered
+	close(release)
+	p.Wait()
+	assert.Equal(t, []string{e.ID}, captured)
+}
+
+func TestImport(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	veto := writeHook(t, "veto", "grep -q vetoed && exit 1\nexit 0\n")
+	p := New(store)
+	p.SetHooks(hook.New(config.HooksConfig{PreCapture: []string{veto}}))
+	var captured []string
+	p.SetOnCapture(func(ctx context.Context, e *storage.Event, body string) {
+		captured = append(captured, e.Title)
+	})
+
+	records := make(chan int)
+	go func() {
+		defer close(records)
+		for i := 0; i < 20; i++ {
+			records <- i
+		}
+	}()
+	parse := func(ctx context.Context, i int) (*storage.Event, string, error) {
+		// Later records parse sooner, yet are stored in order.
+		time.Sleep(time.Duration(20-i) * time.Millisecond)
+		e := &storage.Event{URL: fmt.Sprintf("https://go.dev/%d", i), Title: fmt.Sprint(i), Source: "import", Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		switch i {
+		case 3:
+			return nil, "", errors.New("bad record")
+		case 5:
+			e.URL = "https://chase.com/login"
+		case 7:
+			e.Title = "vetoed"
+		}
+		return e, fmt.Sprintf("body %d", i), nil
+	}
+	var stored []string
+	errs := map[int]error{}
+	Import(ctx, p, records, 4, 3, parse, func(i int, e *storage.Event, err error) {
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		require.NotEmpty(t, e.ID)
+		stored = append(stored, e.Title)
+	})
+
+	require.Len(t, errs, 3)
+	assert.EqualError(t, errs[3], "bad record")
+	assert.ErrorIs(t, errs[5], ErrExcluded)
+	assert.ErrorIs(t, errs[7], hook.ErrVetoed)
+	want := []string{"0", "1", "2", "4", "6", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19"}
+	assert.Equal(t, want, stored)
+	assert.Equal(t, want, captured)
+
+	events, err := store.SearchEvents(ctx, storage.SearchQuery{Sort: storage.SortOldest, Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, events, len(want))
+	for i, e := range events {
+		assert.Equal(t, want[i], e.Title)
+		assert.Len(t, e.ContentHash, 64)
+	}
+}
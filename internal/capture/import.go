@@ -0,0 +1,99 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Import captures many records through p, as the bulk importers do. A pool
+// of workers goroutines (one per CPU when workers < 1) turns each record
+// into an event and body with parse and prepares it (see Prepare), while
+// a single writer stores the events in the order the records arrive,
+// batchSize per transaction (see storage.Store.AddEvents), and runs Stored
+// on each one stored.
+//
+// done is called for each record, from the goroutine calling Import, with
+// the event once stored or the error that kept it out: parse's, Prepare's
+// (a veto wrapping hook.ErrVetoed), the store's, or one wrapping
+// ErrExcluded for an excluded domain. Import returns once records is
+// closed and every record is done.
+func Import[T any](ctx context.Context, p *Pipeline, records <-chan T, workers, batchSize int,
+	parse func(ctx context.Context, rec T) (*storage.Event, string, error),
+	done func(rec T, e *storage.Event, err error)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	batchSize = max(batchSize, 1)
+
+	type parsed struct {
+		event *storage.Event
+		body  string
+		err   error
+	}
+	type job struct {
+		rec T
+		out chan parsed
+	}
+
+	// Each job goes to the workers and, in order, to the writer, which
+	// waits for the job's result; the queue's buffer lets the workers run
+	// that far ahead of it.
+	jobs := make(chan job)
+	queue := make(chan job, 4*workers)
+	go func() {
+		defer close(queue)
+		defer close(jobs)
+		for rec := range records {
+			j := job{rec: rec, out: make(chan parsed, 1)}
+			queue <- j
+			jobs <- j
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range jobs {
+				e, body, err := parse(ctx, j.rec)
+				if err == nil {
+					err = p.Prepare(ctx, e, &body)
+				}
+				j.out <- parsed{event: e, body: body, err: err}
+			}
+		}()
+	}
+
+	var pending []job
+	var captures []storage.Capture
+	flush := func() {
+		for i, err := range p.store.AddEvents(ctx, captures) {
+			j, c := pending[i], captures[i]
+			switch {
+			case err != nil:
+				done(j.rec, nil, err)
+			case c.Event.ID == "":
+				done(j.rec, nil, fmt.Errorf("domain %q is %w", c.Event.Domain, ErrExcluded))
+			default:
+				p.Stored(ctx, c.Event, c.Body)
+				done(j.rec, c.Event, nil)
+			}
+		}
+		pending, captures = pending[:0], captures[:0]
+	}
+	for j := range queue {
+		r := <-j.out
+		if r.err != nil {
+			done(j.rec, nil, r.err)
+			continue
+		}
+		pending = append(pending, j)
+		captures = append(captures, storage.Capture{Event: r.event, Body: r.body, WithBody: r.body != ""})
+		if len(captures) >= batchSize {
+			flush()
+		}
+	}
+	if len(captures) > 0 {
+		flush()
+	}
+}
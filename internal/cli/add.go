@@ -2,32 +2,43 @@ package cli
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"time"
 
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/hook"
 	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/runnerr0/chronicle/internal/webpage"
 )
 
+// fetchTimeout bounds add --fetch's download of the page.
+const fetchTimeout = 30 * time.Second
+
 // Execute implements the go-flags Commander interface for AddCommand.
 func (c *AddCommand) Execute(args []string) error {
 	if c.URL == "" {
 		return fmt.Errorf("--url is required for add command")
 	}
-	if c.Title == "" {
+	if c.Title == "" && !c.Fetch {
 		return fmt.Errorf("--title is required for add command")
 	}
 
-	store, db, err := openDefaultStore()
+	store, db, err := openStore(c.globals)
 	if err != nil {
 		return fmt.Errorf("opening database: %w", err)
 	}
 	defer store.Close()
 	defer db.Close()
 
+	cfg := loadConfig(c.globals)
+	c.webhooks = cfg.Webhooks
+	c.notifications = cfg.Notifications
+	c.hooks = cfg.Hooks
 	return c.executeWithStore(store)
 }
 
@@ -54,62 +65,100 @@ func (c *AddCommand) executeWithStore(store *storage.SQLiteStore) error {
 		body = string(data)
 	}
 
+	// Check exclusion before calling store (store silently skips, but we want
+	// an explicit error for the CLI user)
+	domain := parsed.Hostname()
+	if store.IsExcluded(domain) {
+		return fmt.Errorf("domain %q is %w by exclusion rules", domain, ErrExcluded)
+	}
+
 	ctx := context.Background()
 
 	event := &storage.Event{
 		URL:       c.URL,
 		Title:     c.Title,
 		Browser:   c.BrowserName,
-		Source:    "manual",
+		Source:    c.source,
 		Timestamp: time.Now(),
 	}
-
-	// Compute content hash for dedup if body is present
-	if body != "" {
-		hash := sha256.Sum256([]byte(body))
-		event.ContentHash = fmt.Sprintf("%x", hash)
+	if event.Source == "" {
+		event.Source = "manual"
 	}
 
-	// Check exclusion before calling store (store silently skips, but we want
-	// an explicit error for the CLI user)
-	domain := parsed.Hostname()
-	if store.IsExcluded(domain) {
-		return fmt.Errorf("domain %q is excluded by exclusion rules", domain)
+	if c.Fetch {
+		page, err := webpage.Fetch(ctx, &http.Client{Timeout: fetchTimeout}, c.URL)
+		if err != nil {
+			return err
+		}
+		if event.Title == "" {
+			event.Title = page.Title
+		}
+		if event.Title == "" {
+			return fmt.Errorf("the page has no title; pass --title")
+		}
+		if body == "" {
+			body = page.Text
+		}
+		event.Meta = &page.Meta
+		event.CanonicalURL = page.Canonical
 	}
 
-	if body != "" {
-		err = store.AddEventWithContent(ctx, event, body)
-	} else {
-		err = store.AddEvent(ctx, event)
-	}
-	if err != nil {
-		return fmt.Errorf("storing event: %w", err)
+	pipeline := newPipeline(c.globals, store, c.hooks, c.webhooks, c.notifications)
+	err = pipeline.Capture(ctx, event, body)
+	switch {
+	case errors.Is(err, hook.ErrVetoed):
+		return fmt.Errorf("capture %w: %v", ErrExcluded, err)
+	case errors.Is(err, capture.ErrExcluded):
+		// A pre-capture hook moved the event to an excluded domain.
+		return fmt.Errorf("domain %q is %w by exclusion rules", event.Domain, ErrExcluded)
+	case err != nil:
+		return err
 	}
 
 	// Output confirmation
 	if c.globals.JSON {
 		out := map[string]interface{}{
-			"id":    event.ID,
-			"url":   event.URL,
-			"title": event.Title,
-			"ts":    event.Timestamp.Format(time.RFC3339),
-			"body":  body != "",
-			"embed": false,
+			"id":     event.ID,
+			"url":    event.URL,
+			"title":  event.Title,
+			"ts":     event.Timestamp.Format(time.RFC3339),
+			"body":   body != "",
+			"embed":  false,
+			"visits": event.Visits,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(out)
 	}
 
+	if c.globals.Quiet {
+		fmt.Println(event.ID)
+		return nil
+	}
+
 	hasBody := "no"
 	if body != "" {
 		hasBody = "yes"
 	}
 
+	if event.Visits > 1 {
+		// Deduplicated: the capture counted as another visit to an event.
+		fmt.Printf("Recorded visit %d to event %s\n", event.Visits, event.ID)
+		return nil
+	}
+
 	fmt.Printf("Added event %s (%s)\n", event.ID, event.Timestamp.Format(time.RFC3339))
 	fmt.Printf("  URL: %s\n", event.URL)
 	fmt.Printf("  Title: %s\n", event.Title)
 	fmt.Printf("  Body: %s\n", hasBody)
+	if m := event.Meta; m != nil {
+		if m.Author != "" {
+			fmt.Printf("  Author: %s\n", m.Author)
+		}
+		if !m.Published.IsZero() {
+			fmt.Printf("  Published: %s\n", m.Published.Format("2006-01-02"))
+		}
+	}
 	fmt.Printf("  Embedding: %s\n", "no")
 
 	return nil
@@ -7,14 +7,19 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 )
 
@@ -311,3 +316,118 @@ func TestAddCommand_JSONOutput(t *testing.T) {
 	assert.Equal(t, false, result["body"])
 	assert.Equal(t, false, result["embed"])
 }
+
+func TestAddCommand_NotifiesWebhooks(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	var payloads []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var p map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &p))
+		payloads = append(payloads, p)
+	}))
+	defer srv.Close()
+
+	cmd := &AddCommand{
+		URL:         "https://blog.example.com/post",
+		Title:       "Hooked",
+		Body:        "some text",
+		BrowserName: "manual",
+		globals:     &GlobalFlags{Quiet: true},
+		webhooks: []config.WebhookConfig{
+			{URL: srv.URL, Domains: []string{"example.com"}},
+			{URL: srv.URL, Domains: []string{"github.com"}},
+		},
+	}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+
+	require.Len(t, payloads, 1, "only the matching webhook is called")
+	assert.Equal(t, strings.TrimSpace(output), payloads[0]["id"])
+	assert.Equal(t, "https://blog.example.com/post", payloads[0]["url"])
+	assert.Equal(t, "blog.example.com", payloads[0]["domain"])
+	assert.Equal(t, "manual", payloads[0]["source"])
+	assert.Equal(t, true, payloads[0]["has_body"])
+}
+
+func TestAddCommand_WebhookFailureStillStores(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cmd := &AddCommand{
+		URL:         "https://example.com/a",
+		Title:       "A",
+		BrowserName: "manual",
+		globals:     &GlobalFlags{Quiet: true},
+		webhooks:    []config.WebhookConfig{{URL: srv.URL}},
+	}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalEvents)
+}
+
+func TestAddCommand_Hooks(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	dir := t.TempDir()
+
+	pre := filepath.Join(dir, "pre")
+	require.NoError(t, os.WriteFile(pre, []byte("#!/bin/sh\nsed 's/\"title\":\"Draft\"/\"title\":\"Final\"/'\n"), 0755))
+	forwarded := filepath.Join(dir, "forwarded.json")
+	post := filepath.Join(dir, "post")
+	require.NoError(t, os.WriteFile(post, []byte("#!/bin/sh\ncat > "+forwarded+"\n"), 0755))
+
+	cmd := &AddCommand{
+		URL:         "https://example.com/a",
+		Title:       "Draft",
+		BrowserName: "manual",
+		globals:     &GlobalFlags{Quiet: true},
+		hooks:       config.HooksConfig{PreCapture: []string{pre}, PostCapture: []string{post}},
+	}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	id := strings.TrimSpace(output)
+
+	e, err := store.GetEvent(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Final", e.Title)
+	data, err := os.ReadFile(forwarded)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"id":"`+id+`"`)
+}
+
+func TestAddCommand_HookVeto(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	veto := filepath.Join(t.TempDir(), "veto")
+	require.NoError(t, os.WriteFile(veto, []byte("#!/bin/sh\necho 'private page' >&2\nexit 1\n"), 0755))
+
+	cmd := &AddCommand{
+		URL:         "https://example.com/a",
+		Title:       "A",
+		BrowserName: "manual",
+		globals:     &GlobalFlags{Quiet: true},
+		hooks:       config.HooksConfig{PreCapture: []string{veto}},
+	}
+	err := cmd.executeWithStore(store)
+	require.ErrorIs(t, err, ErrExcluded)
+	assert.Contains(t, err.Error(), "private page")
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/api"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Execute implements the go-flags Commander interface for APICommand.
+func (c *APICommand) Execute(args []string) error {
+	cfg := loadConfig(c.globals)
+
+	token, generated, err := c.resolveToken(cfg)
+	if err != nil {
+		return err
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	host := cfg.API.Host
+	if c.Host != "" {
+		host = c.Host
+	}
+	port := cfg.API.Port
+	if c.Port != 0 {
+		port = c.Port
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           api.NewServer(storage.WithStatsCache(store, statsCacheTTL), token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	slog.Info("api server listening", "addr", addr)
+	noticef(c.globals, "Chronicle API listening on http://%s (read-only)\n", addr)
+	if generated {
+		fmt.Fprintf(os.Stderr, "Generated API token: %s\n", token)
+	}
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("api server: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// resolveToken picks the API token: --token, then api.auth_token, otherwise
+// a random token generated for this run.
+func (c *APICommand) resolveToken(cfg *config.Config) (token string, generated bool, err error) {
+	if c.Token != "" {
+		return c.Token, false, nil
+	}
+	if cfg.API.AuthToken != "" {
+		return cfg.API.AuthToken, false, nil
+	}
+
+	token, err = randomToken()
+	if err != nil {
+		return "", false, fmt.Errorf("generate API token: %w", err)
+	}
+	return token, true, nil
+}
+
+// randomToken returns a random 32-hex-character bearer token.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func TestAPIResolveToken_FlagWins(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AuthToken = "from-config"
+
+	cmd := &APICommand{Token: "from-flag"}
+	token, generated, err := cmd.resolveToken(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "from-flag", token)
+	assert.False(t, generated)
+}
+
+func TestAPIResolveToken_Config(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AuthToken = "from-config"
+
+	token, generated, err := (&APICommand{}).resolveToken(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "from-config", token)
+	assert.False(t, generated)
+}
+
+func TestAPIResolveToken_Generated(t *testing.T) {
+	token, generated, err := (&APICommand{}).resolveToken(config.DefaultConfig())
+	require.NoError(t, err)
+	assert.True(t, generated)
+	assert.Len(t, token, 32)
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// maxAroundScan caps how many events in the window are considered before
+// keeping the --limit nearest.
+const maxAroundScan = 10000
+
+type aroundJSON struct {
+	At            string            `json:"at"`
+	WindowSeconds int64             `json:"window_seconds"`
+	Events        []aroundEventJSON `json:"events"`
+}
+
+type aroundEventJSON struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	Domain        string `json:"domain"`
+	Timestamp     string `json:"timestamp"`
+	OffsetSeconds int64  `json:"offset_seconds"`
+}
+
+// Execute implements the go-flags Commander interface for AroundCommand.
+func (c *AroundCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, args, time.Now())
+}
+
+// executeWithStore lists events around the time in args against a
+// provided store (used by tests).
+func (c *AroundCommand) executeWithStore(store *storage.SQLiteStore, args []string, now time.Time) error {
+	spec := strings.Join(args, " ")
+	if strings.TrimSpace(spec) == "" {
+		return fmt.Errorf("a time is required, e.g. chronicle around \"tuesday 3pm\"")
+	}
+	at, err := parseTimeSpec(spec, now)
+	if err != nil {
+		return fmt.Errorf("invalid time %q: %w", spec, err)
+	}
+	window, err := parseDuration(c.Window)
+	if err != nil {
+		return fmt.Errorf("invalid --window: %w", err)
+	}
+	if window <= 0 {
+		return fmt.Errorf("--window must be positive")
+	}
+	if c.Limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	ctx := context.Background()
+	q := storage.SearchQuery{Since: at.Add(-window), Until: at.Add(window), Limit: maxAroundScan, Sort: storage.SortOldest}
+	var events []storage.Event
+	domains := c.Domain
+	if len(domains) == 0 {
+		domains = []Domain{""}
+	}
+	for _, d := range domains {
+		q.Domain = string(d)
+		found, err := store.SearchEvents(ctx, q)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		events = append(events, found...)
+	}
+	events = nearest(events, at, c.Limit)
+
+	if c.globals != nil && c.globals.JSON {
+		out := aroundJSON{At: at.UTC().Format(time.RFC3339), WindowSeconds: int64(window / time.Second), Events: []aroundEventJSON{}}
+		for _, e := range events {
+			out.Events = append(out.Events, aroundEventJSON{
+				ID:            e.ID,
+				URL:           e.URL,
+				Title:         e.Title,
+				Domain:        e.Domain,
+				Timestamp:     e.Timestamp.UTC().Format(time.RFC3339),
+				OffsetSeconds: int64(e.Timestamp.Sub(at) / time.Second),
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	local := at.Local()
+	if len(events) == 0 {
+		infof(c.globals, "No events within %s of %s\n", c.Window, local.Format("Mon 2006-01-02 15:04"))
+		return nil
+	}
+	infof(c.globals, "Events within %s of %s\n\n", c.Window, local.Format("Mon 2006-01-02 15:04"))
+	marked := false
+	for _, e := range events {
+		if !marked && !e.Timestamp.Before(at) {
+			fmt.Printf("  ── %s ──\n", local.Format("15:04"))
+			marked = true
+		}
+		fmt.Printf("  %s  %6s  %s", e.Timestamp.Local().Format("15:04"), formatOffset(e.Timestamp.Sub(at)), e.Title)
+		if e.Domain != "" {
+			fmt.Printf(" — %s", e.Domain)
+		}
+		fmt.Printf("\n                 %s\n", e.URL)
+	}
+	if !marked {
+		fmt.Printf("  ── %s ──\n", local.Format("15:04"))
+	}
+	return nil
+}
+
+// nearest keeps the limit events closest to at, in time order.
+func nearest(events []storage.Event, at time.Time, limit int) []storage.Event {
+	if len(events) > limit {
+		sort.SliceStable(events, func(i, j int) bool {
+			return absDuration(events[i].Timestamp.Sub(at)) < absDuration(events[j].Timestamp.Sub(at))
+		})
+		events = events[:limit]
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	return events
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// formatOffset renders d compactly with a sign: -48m, +5m, +1h20m.
+func formatOffset(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%s%dm", sign, m)
+	case m == 0:
+		return fmt.Sprintf("%s%dh", sign, h)
+	default:
+		return fmt.Sprintf("%s%dh%02dm", sign, h, m)
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedAroundEvents(t *testing.T, store *storage.SQLiteStore, at time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	for _, ev := range []struct {
+		url, title string
+		offset     time.Duration
+	}{
+		{"https://example.com/too-early", "Too early", -3 * time.Hour},
+		{"https://news.example.com/a", "Morning news", -50 * time.Minute},
+		{"https://docs.example.com/b", "Docs I half remember", -5 * time.Minute},
+		{"https://news.example.com/c", "After lunch read", 20 * time.Minute},
+		{"https://example.com/too-late", "Too late", 2 * time.Hour},
+	} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: ev.url, Title: ev.title, Source: "manual", Timestamp: at.Add(ev.offset)}))
+	}
+}
+
+func TestAround_ListsEventsInWindow(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.Local) // Wednesday
+	at := time.Date(2026, 3, 17, 12, 0, 0, 0, time.Local)   // Tuesday noon
+	seedAroundEvents(t, store, at)
+
+	cmd := &AroundCommand{Window: "1h", Limit: 50, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"tuesday", "noon"}, now))
+	})
+
+	assert.Contains(t, output, "Events within 1h of Tue 2026-03-17 12:00")
+	assert.Contains(t, output, "11:10    -50m  Morning news — news.example.com")
+	assert.Contains(t, output, "11:55     -5m  Docs I half remember")
+	assert.Contains(t, output, "  ── 12:00 ──\n  12:20    +20m  After lunch read")
+	assert.NotContains(t, output, "Too early")
+	assert.NotContains(t, output, "Too late")
+}
+
+func TestAround_LimitKeepsNearest(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.Local)
+	at := time.Date(2026, 3, 17, 12, 0, 0, 0, time.Local)
+	seedAroundEvents(t, store, at)
+
+	cmd := &AroundCommand{Window: "4h", Limit: 2, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"tuesday 12:00"}, now))
+	})
+
+	var out aroundJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, int64(4*3600), out.WindowSeconds)
+	require.Len(t, out.Events, 2)
+	assert.Equal(t, "Docs I half remember", out.Events[0].Title)
+	assert.Equal(t, int64(-300), out.Events[0].OffsetSeconds)
+	assert.Equal(t, "After lunch read", out.Events[1].Title)
+}
+
+func TestAround_DomainAndErrors(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.Local)
+	at := time.Date(2026, 3, 17, 12, 0, 0, 0, time.Local)
+	seedAroundEvents(t, store, at)
+
+	cmd := &AroundCommand{Window: "2h", Limit: 50, Domain: []Domain{"news.example.com"}, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"tuesday noon"}, now))
+	})
+	assert.Contains(t, output, "Morning news")
+	assert.NotContains(t, output, "Docs I half remember")
+
+	cmd = &AroundCommand{Window: "1h", Limit: 50, globals: &GlobalFlags{}}
+	assert.ErrorContains(t, cmd.executeWithStore(store, nil, now), "a time is required")
+	assert.ErrorContains(t, cmd.executeWithStore(store, []string{"someday"}, now), `invalid time "someday"`)
+	cmd.Window = "soon"
+	assert.ErrorContains(t, cmd.executeWithStore(store, []string{"noon"}, now), "invalid --window")
+}
+
+func TestFormatOffset(t *testing.T) {
+	assert.Equal(t, "-48m", formatOffset(-48*time.Minute))
+	assert.Equal(t, "+0m", formatOffset(10*time.Second))
+	assert.Equal(t, "+2h", formatOffset(2*time.Hour))
+	assert.Equal(t, "-1h05m", formatOffset(-65*time.Minute))
+}
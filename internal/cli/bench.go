@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// benchJSON is the JSON output structure for the bench command.
+type benchJSON struct {
+	Events          int     `json:"events"`
+	Seed            int64   `json:"seed"`
+	InsertSeconds   float64 `json:"insert_seconds"`
+	InsertPerSecond float64 `json:"insert_per_second"`
+	Queries         int     `json:"queries"`
+	SearchP50Ms     float64 `json:"search_p50_ms"`
+	SearchP95Ms     float64 `json:"search_p95_ms"`
+	SearchMaxMs     float64 `json:"search_max_ms"`
+	Pruned          int64   `json:"pruned"`
+	PruneSeconds    float64 `json:"prune_seconds"`
+	Database        string  `json:"database,omitempty"`
+}
+
+// benchResult holds the measurements from one bench run.
+type benchResult struct {
+	events  int
+	insert  time.Duration
+	queries []time.Duration // sorted
+	pruned  int64
+	prune   time.Duration
+}
+
+// Execute implements the go-flags Commander interface for BenchCommand.
+func (c *BenchCommand) Execute(args []string) error {
+	if c.SeedFake <= 0 {
+		return fmt.Errorf("--seed-fake must be positive")
+	}
+
+	path := c.Keep
+	if path == "" {
+		dir, err := os.MkdirTemp("", "chronicle-bench-")
+		if err != nil {
+			return fmt.Errorf("create scratch directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		path = filepath.Join(dir, "bench.db")
+	} else if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists; --keep needs a new path", path)
+	}
+
+	// Use the configured pragmas so tuning changes show up in the numbers.
+	store, db, err := openStoreAt(path, storagePragmas(loadConfig(c.globals)))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	res, err := c.run(context.Background(), store, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.print(res)
+}
+
+// run seeds store with synthetic events, then times searches and a prune
+// of everything older than the default retention.
+func (c *BenchCommand) run(ctx context.Context, store *storage.SQLiteStore, now time.Time) (*benchResult, error) {
+	gen := storage.NewFakeGenerator(c.Seed, now)
+	res := &benchResult{events: c.SeedFake}
+
+	prog := newProgress(c.globals, "Inserting", c.SeedFake)
+	start := time.Now()
+	for i := 0; i < c.SeedFake; i++ {
+		event, body := gen.Next()
+		var err error
+		if body != "" {
+			err = store.AddEventWithContent(ctx, event, body)
+		} else {
+			err = store.AddEvent(ctx, event)
+		}
+		if err != nil {
+			return nil, err
+		}
+		prog.Add(1)
+	}
+	res.insert = time.Since(start)
+	prog.Finish()
+
+	for i := 0; i < c.Queries; i++ {
+		q := storage.SearchQuery{Query: gen.Query(), Limit: 10}
+		start := time.Now()
+		if _, err := store.SearchEvents(ctx, q); err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		res.queries = append(res.queries, time.Since(start))
+	}
+	sort.Slice(res.queries, func(i, j int) bool { return res.queries[i] < res.queries[j] })
+
+	start = time.Now()
+	pruned, err := store.PruneExpired(ctx, now.AddDate(0, 0, -defaultRetentionDays))
+	if err != nil {
+		return nil, err
+	}
+	res.pruned = pruned
+	res.prune = time.Since(start)
+
+	return res, nil
+}
+
+// percentile returns the p-th percentile (0-100) of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted) - 1) * p / 100
+	return sorted[i]
+}
+
+func (c *BenchCommand) print(res *benchResult) error {
+	perSec := float64(res.events) / res.insert.Seconds()
+	p50, p95, max := percentile(res.queries, 50), percentile(res.queries, 95), percentile(res.queries, 100)
+
+	if c.globals != nil && c.globals.JSON {
+		ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(benchJSON{
+			Events:          res.events,
+			Seed:            c.Seed,
+			InsertSeconds:   res.insert.Seconds(),
+			InsertPerSecond: perSec,
+			Queries:         len(res.queries),
+			SearchP50Ms:     ms(p50),
+			SearchP95Ms:     ms(p95),
+			SearchMaxMs:     ms(max),
+			Pruned:          res.pruned,
+			PruneSeconds:    res.prune.Seconds(),
+			Database:        c.Keep,
+		})
+	}
+
+	fmt.Printf("Benchmark: %s synthetic events (seed %d)\n\n", formatNumber(int64(res.events)), c.Seed)
+	fmt.Printf("Insert:  %s in %s (%.0f events/s)\n", formatNumber(int64(res.events)), res.insert.Round(time.Millisecond), perSec)
+	if len(res.queries) > 0 {
+		fmt.Printf("Search:  %d queries, p50 %s, p95 %s, max %s\n", len(res.queries), p50.Round(time.Microsecond), p95.Round(time.Microsecond), max.Round(time.Microsecond))
+	}
+	fmt.Printf("Prune:   %s events older than %d days in %s\n", formatNumber(res.pruned), defaultRetentionDays, res.prune.Round(time.Millisecond))
+	if c.Keep != "" {
+		infof(c.globals, "\nDatabase kept at %s\n", c.Keep)
+	}
+	return nil
+}
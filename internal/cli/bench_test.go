@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBench_Run(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &BenchCommand{SeedFake: 300, Queries: 10, Seed: 7, globals: &GlobalFlags{Quiet: true}}
+	res, err := cmd.run(context.Background(), store, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, res.events)
+	assert.Len(t, res.queries, 10)
+	// Events span 90 days, so roughly two thirds fall outside 30-day retention.
+	assert.Greater(t, res.pruned, int64(100))
+	assert.Less(t, res.pruned, int64(300))
+}
+
+func TestBench_KeepJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.db")
+
+	output := captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"--json", "--quiet", "bench", "--seed-fake", "50", "--queries", "5", "--keep", path}))
+	})
+
+	var out benchJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, 50, out.Events)
+	assert.Equal(t, 5, out.Queries)
+	assert.Equal(t, path, out.Database)
+	assert.FileExists(t, path)
+
+	err := RunWithArgs("test", []string{"bench", "--seed-fake", "10", "--keep", path})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestPercentile(t *testing.T) {
+	d := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(d, 50))
+	assert.Equal(t, time.Duration(9), percentile(d, 95))
+	assert.Equal(t, time.Duration(10), percentile(d, 100))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func TestBench_RejectsNonPositive(t *testing.T) {
+	err := RunWithArgs("test", []string{"bench", "--seed-fake", "0"})
+	require.Error(t, err)
+}
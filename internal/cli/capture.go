@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// captureRecord is one line of capture input.
+type captureRecord struct {
+	URL          string    `json:"url"`
+	Title        string    `json:"title"`
+	Timestamp    time.Time `json:"timestamp"`
+	TS           time.Time `json:"ts"`
+	Source       string    `json:"source"`
+	Browser      string    `json:"browser"`
+	CanonicalURL string    `json:"canonical_url"`
+	Body         string    `json:"body"`
+}
+
+// captureResult summarizes a capture run.
+type captureResult struct {
+	Lines    int            `json:"lines"`
+	Captured int            `json:"captured"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Errors   []captureError `json:"errors,omitempty"`
+}
+
+// captureError is a line that could not be captured.
+type captureError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// captureLine is one non-blank line of capture input.
+type captureLine struct {
+	n    int
+	data []byte
+}
+
+// Execute implements the go-flags Commander interface for CaptureCommand.
+func (c *CaptureCommand) Execute(args []string) error {
+	if c.BatchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	in := c.stdin
+	if in == nil {
+		in = os.Stdin
+	}
+	if c.Args.File != "-" {
+		f, err := os.Open(c.Args.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer store.Close()
+	defer db.Close()
+
+	cfg := loadConfig(c.globals)
+	c.webhooks = cfg.Webhooks
+	c.notifications = cfg.Notifications
+	c.hooks = cfg.Hooks
+	return c.executeWithStore(store, in)
+}
+
+// executeWithStore captures the events read from r into a provided store
+// (used by tests). Lines are parsed by a pool of workers and stored in
+// order, --batch-size per transaction.
+func (c *CaptureCommand) executeWithStore(store *storage.SQLiteStore, r io.Reader) error {
+	ctx := context.Background()
+	pipeline := newPipeline(c.globals, store, c.hooks, c.webhooks, c.notifications)
+
+	lines := make(chan captureLine)
+	var readErr error
+	go func() {
+		defer close(lines)
+		br := bufio.NewReader(r)
+		for n := 1; ; n++ {
+			data, err := br.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				readErr = fmt.Errorf("reading line %d: %w", n, err)
+				return
+			}
+			if data = bytes.TrimSpace(data); len(data) > 0 {
+				lines <- captureLine{n: n, data: data}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}()
+
+	var res captureResult
+	prog := newProgress(c.globals, "Capturing", 0)
+	capture.Import(ctx, pipeline, lines, c.Workers, c.BatchSize, c.parseLine, func(l captureLine, e *storage.Event, err error) {
+		prog.Add(1)
+		res.Lines++
+		switch {
+		case errors.Is(err, hook.ErrVetoed), errors.Is(err, capture.ErrExcluded):
+			res.Skipped++
+		case err != nil:
+			res.Failed++
+			res.Errors = append(res.Errors, captureError{Line: l.n, Error: err.Error()})
+			if c.globals == nil || !c.globals.JSON {
+				fmt.Fprintf(os.Stderr, "line %d: %v\n", l.n, err)
+			}
+		default:
+			res.Captured++
+		}
+	})
+	prog.Finish()
+	if readErr != nil {
+		return readErr
+	}
+	slog.Info("capture finished", "lines", res.Lines, "captured", res.Captured, "skipped", res.Skipped, "failed", res.Failed)
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	} else {
+		infof(c.globals, "Captured %d events from %d lines\n", res.Captured, res.Lines)
+		if res.Skipped > 0 {
+			infof(c.globals, "Skipped %d events that were excluded or vetoed by a hook\n", res.Skipped)
+		}
+	}
+	if res.Failed > 0 {
+		return fmt.Errorf("%d of %d lines could not be captured", res.Failed, res.Lines)
+	}
+	return nil
+}
+
+// parseLine decodes one line into an event and its body.
+func (c *CaptureCommand) parseLine(ctx context.Context, l captureLine) (*storage.Event, string, error) {
+	var rec captureRecord
+	if err := json.Unmarshal(l.data, &rec); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	if rec.URL == "" {
+		return nil, "", fmt.Errorf("missing url")
+	}
+	if u, err := url.ParseRequestURI(rec.URL); err != nil || u.Host == "" {
+		return nil, "", fmt.Errorf("invalid URL: %s", rec.URL)
+	}
+
+	e := &storage.Event{
+		URL:          rec.URL,
+		Title:        rec.Title,
+		Timestamp:    rec.Timestamp,
+		Source:       rec.Source,
+		Browser:      rec.Browser,
+		CanonicalURL: rec.CanonicalURL,
+	}
+	if e.Title == "" {
+		e.Title = rec.URL
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = rec.TS
+	}
+	if e.Source == "" {
+		e.Source = c.Source
+	}
+	if e.Source == "" {
+		e.Source = "import"
+	}
+	return e, rec.Body, nil
+}
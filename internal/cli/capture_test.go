@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+const captureInput = `{"url": "https://example.com/tides", "title": "Tides", "timestamp": "2026-03-02T09:30:00Z", "body": "The sea rises twice a day."}
+{"url": "https://example.com/moon", "ts": "2026-03-02T10:00:00Z", "source": "irc-log", "browser": "weechat"}
+
+not json
+{"title": "No URL"}
+{"url": "example.com/relative"}
+{"url": "https://chase.com/login", "title": "Bank"}
+{"url": "https://example.com/stars"}`
+
+func TestCapture_BatchesAndReportsBadLines(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &CaptureCommand{Source: "import", BatchSize: 2, globals: &GlobalFlags{JSON: true}}
+	var err error
+	output := captureOutput(t, func() {
+		err = cmd.executeWithStore(store, strings.NewReader(captureInput))
+	})
+	assert.EqualError(t, err, "3 of 7 lines could not be captured")
+
+	var res captureResult
+	require.NoError(t, json.Unmarshal([]byte(output), &res))
+	assert.Equal(t, 7, res.Lines)
+	assert.Equal(t, 3, res.Captured)
+	assert.Equal(t, 1, res.Skipped)
+	assert.Equal(t, 3, res.Failed)
+	require.Len(t, res.Errors, 3)
+	assert.Equal(t, 4, res.Errors[0].Line)
+	assert.Contains(t, res.Errors[0].Error, "invalid JSON")
+	assert.Equal(t, captureError{Line: 5, Error: "missing url"}, res.Errors[1])
+	assert.Equal(t, captureError{Line: 6, Error: "invalid URL: example.com/relative"}, res.Errors[2])
+
+	ctx := context.Background()
+	events, err := store.SearchEvents(ctx, storage.SearchQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	byURL := map[string]storage.Event{}
+	for _, e := range events {
+		byURL[e.URL] = e
+	}
+
+	tides := byURL["https://example.com/tides"]
+	assert.Equal(t, "import", tides.Source)
+	assert.True(t, tides.HasBody)
+	assert.NotEmpty(t, tides.ContentHash)
+	assert.True(t, tides.Timestamp.Equal(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)))
+
+	moon := byURL["https://example.com/moon"]
+	assert.Equal(t, "irc-log", moon.Source)
+	assert.Equal(t, "weechat", moon.Browser)
+	assert.Equal(t, "https://example.com/moon", moon.Title)
+	assert.True(t, moon.Timestamp.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)))
+
+	assert.WithinDuration(t, time.Now(), byURL["https://example.com/stars"].Timestamp, time.Minute)
+}
+
+func TestCapture_HumanSummary(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &CaptureCommand{Source: "script", BatchSize: 100, globals: &GlobalFlags{}}
+	input := `{"url": "https://example.com/a"}` + "\n" + `{"url": "https://chase.com/"}` + "\n"
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, strings.NewReader(input)))
+	})
+	assert.Contains(t, output, "Captured 1 events from 2 lines")
+	assert.Contains(t, output, "Skipped 1 events that were excluded or vetoed by a hook")
+
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Source: "script", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
@@ -2,47 +2,174 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	goflags "github.com/jessevdk/go-flags"
 )
 
 // commands holds references to all subcommand structs for inspection/testing.
 type commands struct {
-	Status *StatusCommand
-	Search *SearchCommand
-	Open   *OpenCommand
-	Add    *AddCommand
-	Ingest *IngestCommand
-	Prune  *PruneCommand
-	Purge  *PurgeCommand
+	Status      *StatusCommand
+	Search      *SearchCommand
+	Open        *OpenCommand
+	Add         *AddCommand
+	Ingest      *IngestCommand
+	Prune       *PruneCommand
+	Purge       *PurgeCommand
+	Summarize   *SummarizeCommand
+	Digest      *DigestCommand
+	Pipe        *PipeCommand
+	Context     *ContextCommand
+	Mcp         *McpCommand
+	API         *APICommand
+	Version     *VersionCommand
+	Completion  *CompletionCommand
+	Profile     *ProfileCommand
+	Config      *ConfigCommand
+	Patterns    *PatternsCommand
+	FabricSetup *FabricSetupCommand
+	Bench       *BenchCommand
+	Stats       *StatsCommand
+	Searches    *SearchesCommand
+	Report      *ReportCommand
+	Duplicates  *DuplicatesCommand
+	Export      *ExportCommand
+	Replicate   *ReplicateCommand
+	Sync        *SyncCommand
+	RPC         *RPCCommand
+	GRPC        *GRPCCommand
+	Around      *AroundCommand
+	Resurface   *ResurfaceCommand
+	Domains     *DomainsCommand
+	Migrate     *MigrateCommand
+	Links       *LinksCommand
+	Pull        *PullCommand
+	Jobs        *JobsCommand
+	Clip        *ClipCommand
+	Capture     *CaptureCommand
+	Embed       *EmbedCommand
+	Exclude     *ExcludeCommand
+	Doctor      *DoctorCommand
+	MigrateEnc  *MigrateEncryptCommand
 }
 
 // buildParser constructs the go-flags parser with all subcommands registered.
 func buildParser(version string) (*goflags.Parser, *GlobalFlags, *commands) {
 	var globals GlobalFlags
 
-	parser := goflags.NewParser(&globals, goflags.Default)
+	// Errors are printed by RunWithArgs so they can be rendered as JSON.
+	parser := goflags.NewParser(&globals, goflags.HelpFlag|goflags.PassDoubleDash)
 	parser.Name = "chronicle"
 	parser.LongDescription = "Privacy-first local browsing history capture, search, and recall for fabric."
 
 	cmds := &commands{
-		Status: &StatusCommand{globals: &globals, version: version},
-		Search: &SearchCommand{globals: &globals, version: version},
-		Open:   &OpenCommand{globals: &globals, version: version},
-		Add:    &AddCommand{globals: &globals, version: version},
-		Ingest: &IngestCommand{globals: &globals, version: version},
-		Prune:  &PruneCommand{globals: &globals, version: version},
-		Purge:  &PurgeCommand{globals: &globals, version: version},
+		Status:     &StatusCommand{globals: &globals, version: version},
+		Search:     &SearchCommand{globals: &globals, version: version},
+		Open:       &OpenCommand{globals: &globals, version: version},
+		Add:        &AddCommand{globals: &globals, version: version},
+		Ingest:     &IngestCommand{globals: &globals, version: version},
+		Prune:      &PruneCommand{globals: &globals, version: version},
+		Purge:      &PurgeCommand{globals: &globals, version: version},
+		Summarize:  &SummarizeCommand{globals: &globals, version: version},
+		Digest:     &DigestCommand{globals: &globals, version: version},
+		Pipe:       &PipeCommand{globals: &globals, version: version},
+		Context:    &ContextCommand{globals: &globals, version: version},
+		Mcp:        &McpCommand{globals: &globals, version: version},
+		API:        &APICommand{globals: &globals, version: version},
+		Version:    &VersionCommand{globals: &globals, version: version},
+		Completion: &CompletionCommand{globals: &globals, version: version},
+		Profile: &ProfileCommand{
+			List:   ProfileListCommand{globals: &globals},
+			Create: ProfileCreateCommand{globals: &globals},
+		},
+		Config: &ConfigCommand{
+			Set:  ConfigSetCommand{globals: &globals},
+			Show: ConfigShowCommand{globals: &globals},
+		},
+		Patterns:    &PatternsCommand{globals: &globals, version: version},
+		FabricSetup: &FabricSetupCommand{globals: &globals, version: version},
+		Bench:       &BenchCommand{globals: &globals, version: version},
+		Stats:       &StatsCommand{globals: &globals, version: version},
+		Searches:    &SearchesCommand{globals: &globals, version: version},
+		Report:      &ReportCommand{globals: &globals, version: version},
+		Duplicates:  &DuplicatesCommand{globals: &globals, version: version},
+		Export:      &ExportCommand{globals: &globals, version: version},
+		Replicate:   &ReplicateCommand{globals: &globals, version: version},
+		Sync:        &SyncCommand{globals: &globals, version: version},
+		RPC:         &RPCCommand{globals: &globals, version: version},
+		GRPC:        &GRPCCommand{globals: &globals, version: version},
+		Around:      &AroundCommand{globals: &globals, version: version},
+		Resurface:   &ResurfaceCommand{globals: &globals, version: version},
+		Domains:     &DomainsCommand{globals: &globals, version: version},
+		Migrate: &MigrateCommand{
+			Status: MigrateStatusCommand{globals: &globals},
+			Up:     MigrateUpCommand{globals: &globals},
+			Down:   MigrateDownCommand{globals: &globals},
+		},
+		Links: &LinksCommand{globals: &globals, version: version},
+		Pull:  &PullCommand{globals: &globals, version: version},
+		Jobs: &JobsCommand{
+			List: JobsListCommand{globals: &globals},
+			Run:  JobsRunCommand{globals: &globals},
+		},
+		Clip:    &ClipCommand{globals: &globals, version: version},
+		Capture: &CaptureCommand{globals: &globals, version: version},
+		Embed:   &EmbedCommand{globals: &globals, version: version},
+		Exclude: &ExcludeCommand{
+			Add:    ExcludeAddCommand{globals: &globals},
+			Remove: ExcludeRemoveCommand{globals: &globals},
+			List:   ExcludeListCommand{globals: &globals},
+			Test:   ExcludeTestCommand{globals: &globals},
+		},
+		Doctor:     &DoctorCommand{globals: &globals},
+		MigrateEnc: &MigrateEncryptCommand{globals: &globals},
 	}
 
 	parser.AddCommand("status", "Show ingestion health and statistics", "Show ingestion health, database statistics, and configuration summary.", cmds.Status)
 	parser.AddCommand("search", "Search captured events", "Search captured events by keyword, with optional filters.", cmds.Search)
 	parser.AddCommand("open", "Print stored content of an event", "Print the full stored content of a specific event.", cmds.Open)
 	parser.AddCommand("add", "Manually ingest a URL/title/body", "Manually ingest a URL/title/body into Chronicle.", cmds.Add)
-	parser.AddCommand("ingest", "Start the Chronicle daemon", "Start the Chronicle daemon (local HTTP service).", cmds.Ingest)
+	parser.AddCommand("ingest", "Start the Chronicle daemon", "Start the Chronicle daemon, the local HTTP service browser extensions send captured pages to (POST /events), on daemon.host and daemon.port. Pages are captured as the capture settings say: incognito pages (capture.exclude_incognito), denylisted pages (capture.denylist_domains and capture.denylist_regex, matched against the URL) and pages outside a non-empty capture.allowlist_domains are refused, and with capture.mode metadata_only, the default, only pages on capture.body_capture_domains keep their text; full keeps every page's text. It runs in the background unless --foreground is given, and runs the jobs configured under jobs on their schedule. Requests must present daemon.auth_token as a bearer token; when it is unset, the first start generates one and saves it to the config file.", cmds.Ingest)
 	parser.AddCommand("prune", "Apply TTL pruning", "Apply TTL pruning to remove old events.", cmds.Prune)
 	parser.AddCommand("purge", "Delete ALL Chronicle data", "Delete ALL Chronicle data. Destructive operation with safety prompt.", cmds.Purge)
+	parser.AddCommand("summarize", "Summarize an event with fabric", "Pipe the stored body of an event through a fabric pattern and print the result. With --pending, generate the short stored summaries shown in search results for every body that lacks one.", cmds.Summarize)
+	parser.AddCommand("digest", "Write a daily/weekly digest", "Aggregate a period's captures by domain and write a markdown digest, optionally with a fabric-written narrative.", cmds.Digest)
+	parser.AddCommand("pipe", "Stream matching events to stdout or a command", "Run a search and stream matching events, with metadata headers and bodies, to stdout or into a command.", cmds.Pipe)
+	parser.AddCommand("context", "Build an LLM context block", "Select the most relevant events for a topic and pack their content into a token-budgeted context block for LLM prompts. Events are ranked by hybrid search, keyword and embedding together, when embeddings.enabled is set, else (or when the query cannot be embedded) by keyword.", cmds.Context)
+	parser.AddCommand("mcp", "Run as an MCP server over stdio", "Serve the Model Context Protocol over stdio so agents can search and read browsing history.", cmds.Mcp)
+	parser.AddCommand("api", "Serve the read-only query API", "Serve a token-authenticated, read-only HTTP API for events, search, and stats (separate from the ingest daemon). The OpenAPI document is served unauthenticated at /openapi.json.", cmds.API)
+	parser.AddCommand("version", "Show version and build information", "Show binary version, commit, build date, Go and SQLite versions, schema version, and config path.", cmds.Version)
+	parser.AddCommand("completion", "Print a shell completion script", "Print a bash or zsh completion script. Event IDs complete from recent history, e.g. `chronicle open --id CHR-<TAB>`.", cmds.Completion)
+	parser.AddCommand("profile", "Manage separate profiles", "List and create profiles. Each profile has its own config, database, and vector directory; select one with --profile or CHRONICLE_PROFILE.", cmds.Profile)
+	parser.AddCommand("config", "Inspect and edit configuration", "Inspect and edit the config file. Edits preserve comments and key order.", cmds.Config)
+	parser.AddCommand("patterns", "List available fabric patterns", "List the fabric patterns in fabric.patterns_dir with a short description, for use with summarize/digest/pipe --pattern.", cmds.Patterns)
+	parser.AddCommand("fabric-setup", "Register Chronicle as a fabric extension", "Write a fabric extension config so prompts and patterns can pull relevant history with {{ext:chronicle:context:QUERY}}. fabric --context only reads static files, so the extension is how history is pulled in on demand.", cmds.FabricSetup)
+	parser.AddCommand("bench", "Benchmark storage on synthetic data", "Fill a scratch database with synthetic events and measure insert throughput, search latency, and prune time. Your own database is never touched.", cmds.Bench)
+	parser.AddCommand("stats", "Show when browsing happens", "Break captured events down by hour of day and weekday in local time, as a heat table and weekday totals.", cmds.Stats)
+	parser.AddCommand("searches", "Review recorded searches", "List recent or most repeated searches from the local search history. Recording is off unless search.record_history is enabled; history never leaves the database.", cmds.Searches)
+	parser.AddCommand("report", "Write a daily/weekly review report", "Write a markdown review of a period: activity by day, top domains, long reads, newly visited domains, and pinned pages captured in it. Long reads are ranked by the estimated reading time of their captured text, standing in for time spent on the page, which is not recorded.", cmds.Report)
+	parser.AddCommand("duplicates", "Report duplicate and near-duplicate events", "Report clusters of events sharing a content hash, the same normalized URL, or highly similar titles on one domain. Read-only: nothing is deleted.", cmds.Duplicates)
+	parser.AddCommand("export", "Export events to other tools", "Export captured events, optionally with their bodies. --format jsonl writes one JSON object per line, in the form chronicle capture reads back; csv writes one row per event under a header row; md writes one markdown document with a section per day; obsidian writes one note per event plus daily index notes into a vault folder; org writes one org file with a heading per day; parquet writes one table row per event for DuckDB or pandas.", cmds.Export)
+	parser.AddCommand("replicate", "Back up the database to a replica", "Continuously snapshot the database to a directory or S3-compatible bucket (credentials from the standard AWS_* environment), keeping storage.replica_retain snapshots. --once takes a single snapshot; --list shows them; --restore writes the newest snapshot at or before --at to a new file.", cmds.Replicate)
+	parser.AddCommand("sync", "Sync events with other devices", "Exchange events with other machines through a shared folder (Dropbox, Syncthing, a network share). Each device writes its new events there and imports everyone else's, skipping events it already has (same time, content hash and normalized URL), so all devices converge on one history. Run it on each machine, e.g. from cron.", cmds.Sync)
+	parser.AddCommand("rpc", "Serve JSON-RPC 2.0 over stdio", "Answer newline-delimited JSON-RPC 2.0 requests on stdin with responses on stdout, for editor plugins and scripts that don't want to run an HTTP daemon. Methods: search (query, domain, source, browser, since, until, sort, limit, offset), get (id), add (url, title, body, browser), stats, and ping. Parameters are passed by name; batches are supported.", cmds.RPC)
+	parser.AddCommand("grpc", "Serve the gRPC API", "Serve the chronicle.v1.Chronicle gRPC service (Capture, streaming Search, and Watch for newly captured events) for high-throughput integrations. gRPC needs HTTP/2, so it is served over TLS: give --cert/--key (or grpc.cert_file/grpc.key_file), or a self-signed certificate is generated and its fingerprint printed. Calls must send the token as \"authorization: Bearer <token>\" metadata. The service definition is chronicle.proto in the source tree.", cmds.GRPC)
+	parser.AddCommand("around", "List events captured near a time", "List events captured within --window either side of a point in time, e.g. `chronicle around \"thursday noon\" --window 2h`, for finding a page you remember seeing then but not by name. The time takes the same forms as --since, plus a time of day.", cmds.Around)
+	parser.AddCommand("resurface", "Rediscover older captures", "Show a random sample of pages captured between --since and --min-age ago, one per URL, favouring ones that held your attention: pages with a captured body (larger counts more, standing in for dwell time) and pages visited repeatedly. Each run picks afresh; --seed repeats a selection.", cmds.Resurface)
+	parser.AddCommand("domains", "List captured domains", "List every captured domain with its event count, busiest first. An optional argument keeps only domains starting with it, e.g. `chronicle domains git`. The same list completes --domain values in the shell.", cmds.Domains)
+	parser.AddCommand("migrate", "Inspect and change the schema version", "List schema migrations, apply pending ones, or revert applied ones, e.g. before going back to an older chronicle. Every other command applies pending migrations when it opens the database, so run down from the binary you are leaving. Reverting drops the tables and columns a migration added, with their data.", cmds.Migrate)
+	parser.AddCommand("links", "List links between captured pages", "List the outbound links found in a captured body (--id), or the captured pages whose bodies link to a URL (--to). Links are extracted from markdown, HTML anchors and bare URLs when a body is stored, resolved against the page's URL, without #fragments.", cmds.Links)
+	parser.AddCommand("pull", "Capture events from a source adapter", "Fetch events from an ingestion source and store the new ones, skipping events already captured (same time and normalized URL). Sources are configured under sources with a name, an adapter type and its options; built-in types are rss (option url: an RSS or Atom feed) and shell (option path: a bash or zsh history file with timestamps). Pulled events are stored with the source's name as their source. Run it from cron to keep a feed captured.", cmds.Pull)
+	parser.AddCommand("jobs", "List and run scheduled maintenance jobs", "Jobs configured under jobs run on their schedule while the daemon (chronicle ingest) is up: prune applies retention, backup snapshots to storage.replica_url, vacuum reclaims space, embed generates missing embeddings (see embed), and digest writes a digest into its dir option. Schedules are cron expressions (\"0 3 * * *\", local time), @hourly, @daily, @weekly, @monthly, or @every with a duration. list shows each job's next run, and its last result when the daemon is running; run runs one now.", cmds.Jobs)
+	parser.AddCommand("clip", "Capture URLs copied to the clipboard", "Watch the system clipboard and offer to capture each URL copied to it, such as links shared in chat apps that never reach the browser extension. Each new URL is offered once with a y/N prompt, or captured straight away with --auto; --fetch downloads the page for its title and text, otherwise the URL is the title. Captures go through the same exclusions and hooks as add and are stored with source clipboard. Needs pbpaste (macOS), wl-paste, xclip or xsel (Linux), or PowerShell (Windows).", cmds.Clip)
+	parser.AddCommand("capture", "Bulk-capture JSONL events from stdin or a file", "Read one JSON event per line from a file, or from stdin with -, and store them in batches: chronicle capture - < events.jsonl. Lines are parsed by a pool of --workers goroutines and stored in order, --batch-size per transaction. Each event needs a url and may set title (default: the URL), timestamp (RFC 3339, default: now; ts is accepted too), source, browser, canonical_url and body. Pre- and post-capture hooks run as for add, and excluded domains are skipped. A line that cannot be parsed or stored is reported on stderr with its line number and the rest are still captured; the command fails if any line did.", cmds.Capture)
+	parser.AddCommand("embed", "Generate embeddings for semantic search", "Embed every event that has no embedding from the configured model, newest first, with embeddings.provider (ollama, using embeddings.model at embeddings.ollama_url), embeddings.batch_size events per request. The text embedded is the title with the stored summary, or the body when there is no summary; with embeddings.content_only, events without a body are skipped. Embeddings are stored as they are made, so an interrupted run resumes where it stopped. Changing the model embeds everything again. Vectors are kept in the database with storage.vector_store set to sqlite, the default, or with flat in a file under storage.vector_dir (next to the database unless absolute), which semantic search then reads; vectors already in the database move there the next time chronicle opens it. lancedb is not supported yet. Requires embeddings.enabled.", cmds.Embed)
+	parser.AddCommand("exclude", "Manage exclusion rules", "Exclusion rules keep pages from matching domains out of the history: captures from them are skipped by add, capture, clip, pull and the daemon. A domain rule matches that exact hostname; a --regex rule matches any hostname it matches. The schema seeds default rules for banking, auth, healthcare and tax sites, which list marks as default and remove can drop like any other. test shows whether a URL would be excluded and by which rule. A running daemon applies changes after it restarts.", cmds.Exclude)
+	parser.AddCommand("doctor", "Check the database for inconsistencies", "Check that the full-text index matches the events table: every event should be indexed, and no index entry should outlive its event. Databases written by older versions, which stored an event and its index entry separately, can have events that keyword search never finds. --rebuild-fts repairs this by rebuilding the index from the stored events and bodies in one transaction. doctor fails when it finds a problem it did not repair.", cmds.Doctor)
+	parser.AddCommand("migrate-encrypt", "Encrypt the bodies of an existing database", "Set the database up for encryption at rest and encrypt every captured body stored as plaintext, in one transaction, with AES-256-GCM under a key derived from the passphrase (PBKDF2-HMAC-SHA256). The passphrase comes from storage.encryption: the environment variable named by passphrase_env, or the OS keychain item keychain_service with key_source keychain. Once a database is encrypted every command needs the passphrase to open it. Summaries are encrypted too. Nothing derived from an encrypted body is kept in plaintext: bodies are dropped from the full-text index, so keyword search matches titles and URLs only, and the keywords and outbound links extracted from them are deleted and no longer extracted. Titles, URLs and embeddings stay plaintext. The database is vacuumed afterwards so no plaintext bodies linger in free pages; replicas and sync folders made earlier still hold them.", cmds.MigrateEnc)
 
 	return parser, &globals, cmds
 }
@@ -70,7 +197,29 @@ func RunWithArgs(version string, args []string) error {
 		}
 	}
 
-	parser, _, _ := buildParser(version)
+	parser, globals, _ := buildParser(version)
+	parser.CommandHandler = func(cmd goflags.Commander, args []string) error {
+		if cmd == nil {
+			return nil
+		}
+		stop := startLogging(globals, "")
+		defer stop()
+
+		var path []string
+		for c := parser.Active; c != nil; c = c.Active {
+			path = append(path, c.Name)
+		}
+		name := strings.Join(path, " ")
+		start := time.Now()
+		slog.Debug("command started", "command", name, "args", args)
+		err := cmd.Execute(args)
+		if err != nil {
+			slog.Error("command failed", "command", name, "elapsed", time.Since(start), "err", err)
+		} else {
+			slog.Debug("command finished", "command", name, "elapsed", time.Since(start))
+		}
+		return err
+	}
 
 	var err error
 	if args != nil {
@@ -82,9 +231,11 @@ func RunWithArgs(version string, args []string) error {
 	if err != nil {
 		if flagsErr, ok := err.(*goflags.Error); ok {
 			if flagsErr.Type == goflags.ErrHelp {
+				fmt.Println(err)
 				return nil
 			}
 		}
+		printError(os.Stderr, err, globals.JSON)
 		return err
 	}
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	goflags "github.com/jessevdk/go-flags"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -71,6 +72,8 @@ func TestAddSubcommandRecognized(t *testing.T) {
 
 func TestIngestSubcommandRecognized(t *testing.T) {
 	parser, _, _ := buildParser("test")
+	// Only parsing is under test; running would start the daemon.
+	parser.CommandHandler = func(goflags.Commander, []string) error { return nil }
 	_, err := parser.ParseArgs([]string{"ingest"})
 	assert.NoError(t, err)
 }
@@ -158,7 +161,7 @@ func TestSearchDomainFlag(t *testing.T) {
 	p, _, c := buildParser("test")
 	_, err := p.ParseArgs([]string{"search", "--domain", "github.com", "query"})
 	require.NoError(t, err)
-	assert.Equal(t, []string{"github.com"}, c.Search.Domain)
+	assert.Equal(t, []Domain{"github.com"}, c.Search.Domain)
 }
 
 func TestPruneDryRunFlag(t *testing.T) {
@@ -169,7 +172,7 @@ func TestPruneDryRunFlag(t *testing.T) {
 }
 
 func TestAllSubcommandsExist(t *testing.T) {
-	expected := []string{"status", "search", "open", "add", "ingest", "prune", "purge"}
+	expected := []string{"status", "search", "open", "add", "ingest", "prune", "purge", "summarize", "digest", "pipe", "context", "mcp", "api", "version", "completion", "profile", "config", "patterns", "fabric-setup", "bench", "stats", "searches", "report", "duplicates", "export", "replicate", "sync", "rpc", "grpc", "around", "resurface", "domains", "migrate", "links", "pull", "jobs", "clip", "capture", "embed", "exclude", "doctor", "migrate-encrypt"}
 	parser, _, _ := buildParser("test")
 
 	for _, name := range expected {
@@ -185,6 +188,7 @@ func TestHelpFlagDoesNotError(t *testing.T) {
 
 func TestIngestForegroundFlag(t *testing.T) {
 	p, _, c := buildParser("test")
+	p.CommandHandler = func(goflags.Commander, []string) error { return nil }
 	_, err := p.ParseArgs([]string{"ingest", "--foreground"})
 	require.NoError(t, err)
 	assert.True(t, c.Ingest.Foreground)
@@ -200,6 +204,8 @@ func TestOpenFormatFlagDefault(t *testing.T) {
 
 func TestSearchSemanticFlag(t *testing.T) {
 	p, _, c := buildParser("test")
+	// Only parsing is under test; running needs embeddings enabled.
+	p.CommandHandler = func(goflags.Commander, []string) error { return nil }
 	_, err := p.ParseArgs([]string{"search", "--semantic", "query"})
 	require.NoError(t, err)
 	assert.True(t, c.Search.Semantic)
@@ -207,6 +213,9 @@ func TestSearchSemanticFlag(t *testing.T) {
 
 func TestSearchBrowserFlag(t *testing.T) {
 	p, _, c := buildParser("test")
+	// Only parsing is under test; running would check --browser against
+	// whatever the default database holds.
+	p.CommandHandler = func(goflags.Commander, []string) error { return nil }
 	_, err := p.ParseArgs([]string{"search", "--browser", "chrome", "query"})
 	require.NoError(t, err)
 	assert.Equal(t, []string{"chrome"}, c.Search.Browser)
@@ -254,6 +263,7 @@ func TestSearchHasEmbeddingFlag(t *testing.T) {
 
 func TestIngestPortFlag(t *testing.T) {
 	p, _, c := buildParser("test")
+	p.CommandHandler = func(goflags.Commander, []string) error { return nil }
 	_, err := p.ParseArgs([]string{"ingest", "--port", "9999"})
 	require.NoError(t, err)
 	assert.Equal(t, 9999, c.Ingest.Port)
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/clipboard"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// clipURL matches the http(s) URLs in copied text.
+var clipURL = regexp.MustCompile(`https?://[^\s<>"'()\[\]{}]+`)
+
+// Execute implements the go-flags Commander interface for ClipCommand.
+func (c *ClipCommand) Execute(args []string) error {
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval < 100*time.Millisecond {
+		return fmt.Errorf("invalid --interval %q: want a duration of at least 100ms", c.Interval)
+	}
+	if err := clipboard.Available(); err != nil {
+		return err
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer store.Close()
+	defer db.Close()
+
+	cfg := loadConfig(c.globals)
+	c.webhooks = cfg.Webhooks
+	c.notifications = cfg.Notifications
+	c.hooks = cfg.Hooks
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	noticef(c.globals, "Watching the clipboard for URLs (Ctrl-C to stop)\n")
+	return c.watch(ctx, store, clipboard.Read, interval)
+}
+
+// watch reads the clipboard every interval until ctx is cancelled and
+// offers each URL newly copied to it, once. Whatever is on the clipboard
+// when watching starts is left alone.
+func (c *ClipCommand) watch(ctx context.Context, store *storage.SQLiteStore, read func() (string, error), interval time.Duration) error {
+	var answers *bufio.Scanner
+	offered := map[string]bool{}
+	last, lastErr := "", ""
+	if text, err := read(); err == nil {
+		last = text
+		for _, u := range clipURLs(text) {
+			offered[u] = true
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		text, err := read()
+		if err != nil {
+			// An empty clipboard is an error for some tools; log each
+			// new failure once.
+			if err.Error() != lastErr {
+				slog.Debug("reading the clipboard failed", "err", err)
+				lastErr = err.Error()
+			}
+			continue
+		}
+		lastErr = ""
+		if text == last {
+			continue
+		}
+		last = text
+
+		for _, u := range clipURLs(text) {
+			if offered[u] {
+				continue
+			}
+			offered[u] = true
+			if !c.Auto {
+				if answers == nil {
+					in := c.stdin
+					if in == nil {
+						in = os.Stdin
+					}
+					answers = bufio.NewScanner(in)
+				}
+				ok, err := askCapture(answers, u)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			c.capture(store, u)
+		}
+	}
+}
+
+// askCapture asks on stderr whether to capture u and reads the answer.
+func askCapture(answers *bufio.Scanner, u string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "Capture %s? [y/N] ", u)
+	if !answers.Scan() {
+		if err := answers.Err(); err != nil {
+			return false, fmt.Errorf("reading answer: %w", err)
+		}
+		return false, fmt.Errorf("no answer on stdin; use --auto to capture without asking")
+	}
+	answer := strings.TrimSpace(strings.ToLower(answers.Text()))
+	return answer == "y" || answer == "yes", nil
+}
+
+// capture stores u the way add does. Failures are reported and watching
+// goes on.
+func (c *ClipCommand) capture(store *storage.SQLiteStore, u string) {
+	add := &AddCommand{
+		URL:           u,
+		Fetch:         c.Fetch,
+		globals:       c.globals,
+		version:       c.version,
+		webhooks:      c.webhooks,
+		notifications: c.notifications,
+		hooks:         c.hooks,
+		source:        "clipboard",
+	}
+	if !c.Fetch {
+		add.Title = u
+	}
+	err := add.executeWithStore(store)
+	switch {
+	case errors.Is(err, ErrExcluded):
+		noticef(c.globals, "Skipped %s: %v\n", u, err)
+	case err != nil:
+		slog.Warn("clipboard capture failed", "url", u, "err", err)
+		noticef(c.globals, "Warning: could not capture %s: %v\n", u, err)
+	}
+}
+
+// clipURLs returns the http(s) URLs in copied text, each once, in order.
+func clipURLs(text string) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, m := range clipURL.FindAllString(text, -1) {
+		m = strings.TrimRight(m, ".,;:!?*_")
+		if u, err := url.Parse(m); err != nil || u.Host == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		urls = append(urls, m)
+	}
+	return urls
+}
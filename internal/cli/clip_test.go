@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// fakeClipboard returns each text in turn, then cancels the watch.
+func fakeClipboard(cancel context.CancelFunc, texts ...string) func() (string, error) {
+	return func() (string, error) {
+		if len(texts) == 0 {
+			cancel()
+			return "", errors.New("done")
+		}
+		text := texts[0]
+		texts = texts[1:]
+		if text == "<error>" {
+			return "", errors.New("Nothing is copied")
+		}
+		return text, nil
+	}
+}
+
+func TestClipURLs(t *testing.T) {
+	assert.Equal(t, []string{"https://go.dev/blog/", "http://example.com/a?b=c"},
+		clipURLs("Read https://go.dev/blog/. Also (http://example.com/a?b=c) and https://go.dev/blog/!"))
+	assert.Empty(t, clipURLs("no links, just ftp://example.com and https://"))
+}
+
+func TestClip_AutoCapturesNewURLs(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	read := fakeClipboard(cancel,
+		"https://before.example/start",
+		"see https://go.dev/blog/ in #general",
+		"see https://go.dev/blog/ in #general",
+		"<error>",
+		"https://before.example/start",
+		"plain text",
+		"https://news.example/story and https://go.dev/blog/",
+	)
+
+	cmd := &ClipCommand{Auto: true, globals: &GlobalFlags{Quiet: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.watch(ctx, store, read, time.Millisecond))
+	})
+	assert.Equal(t, 2, strings.Count(output, "CHR-"), output)
+
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Source: "clipboard", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	var urls []string
+	for _, e := range events {
+		urls = append(urls, e.URL)
+		assert.Equal(t, e.URL, e.Title)
+	}
+	assert.ElementsMatch(t, []string{"https://go.dev/blog/", "https://news.example/story"}, urls)
+}
+
+func TestClip_AsksBeforeCapturing(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	read := fakeClipboard(cancel,
+		"",
+		"https://go.dev/doc/",
+		"https://skip.example/",
+		"https://chase.com/login",
+	)
+
+	cmd := &ClipCommand{globals: &GlobalFlags{Quiet: true}, stdin: strings.NewReader("y\nn\nyes\n")}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.watch(ctx, store, read, time.Millisecond))
+	})
+
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Source: "clipboard", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "https://go.dev/doc/", events[0].URL)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cmd.stdin = strings.NewReader("")
+	err = cmd.watch(ctx, store, fakeClipboard(cancel, "", "https://go.dev/ref/spec"), time.Millisecond)
+	assert.ErrorContains(t, err, "use --auto")
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// ANSI SGR sequences used by themes.
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiUnderline = "\033[4m"
+	ansiYellow    = "\033[33m"
+	ansiBlue      = "\033[34m"
+	ansiMagenta   = "\033[35m"
+)
+
+// theme maps output roles to the SGR sequences that render them.
+type theme struct {
+	Title  string
+	Domain string
+	Match  string
+}
+
+// themes are the built-in color themes, selected by output.theme.
+var themes = map[string]theme{
+	"default": {Title: ansiBold, Domain: ansiDim, Match: ansiBold + ansiYellow},
+	"light":   {Title: ansiBold + ansiBlue, Domain: ansiDim, Match: ansiBold + ansiMagenta},
+	"mono":    {Title: ansiBold, Domain: ansiDim, Match: ansiUnderline},
+}
+
+// palette renders styled text. The zero value renders plain text.
+type palette struct {
+	enabled bool
+	theme   theme
+}
+
+// newPalette resolves the color mode (--color, then output.color) and theme
+// for output written to f.
+func newPalette(globals *GlobalFlags, cfg *config.Config, f *os.File) palette {
+	mode := cfg.Output.Color
+	if globals != nil && globals.Color != "" {
+		mode = globals.Color
+	}
+	if globals != nil && globals.JSON {
+		mode = "never"
+	}
+
+	t, ok := themes[cfg.Output.Theme]
+	if !ok {
+		t = themes["default"]
+	}
+	return palette{enabled: colorEnabled(mode, f), theme: t}
+}
+
+// colorEnabled reports whether mode allows color on f. In auto mode color
+// requires a terminal, TERM other than "dumb", and NO_COLOR unset or empty
+// (https://no-color.org).
+func colorEnabled(mode string, f *os.File) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device such as a TTY.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (p palette) wrap(style, s string) string {
+	if !p.enabled || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// title styles an event title, highlighting any of terms within it.
+func (p palette) title(s string, terms []string) string {
+	if !p.enabled {
+		return s
+	}
+	re := termsPattern(terms)
+	if re == nil {
+		return p.wrap(p.theme.Title, s)
+	}
+	// Re-open the title style after each match so the rest stays bold.
+	hl := re.ReplaceAllStringFunc(s, func(m string) string {
+		return ansiReset + p.theme.Match + m + ansiReset + p.theme.Title
+	})
+	return p.wrap(p.theme.Title, hl)
+}
+
+// domain styles a domain name.
+func (p palette) domain(s string) string {
+	return p.wrap(p.theme.Domain, s)
+}
+
+// highlightTerms extracts the words of a search query worth highlighting,
+// dropping FTS operators and quoting.
+func highlightTerms(query string) []string {
+	var terms []string
+	for _, f := range strings.Fields(query) {
+		f = strings.Trim(f, `"'()*`)
+		switch strings.ToUpper(f) {
+		case "", "AND", "OR", "NOT", "NEAR":
+			continue
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// termsPattern compiles terms into a case-insensitive alternation, or nil
+// if there is nothing to match.
+func termsPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
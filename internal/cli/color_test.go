@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func TestColorEnabled_Modes(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.True(t, colorEnabled("always", f))
+	assert.False(t, colorEnabled("never", f))
+	assert.False(t, colorEnabled("auto", f), "regular files are not terminals")
+}
+
+func TestNewPalette_NoColorAndJSON(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	t.Setenv("NO_COLOR", "1")
+	p := newPalette(&GlobalFlags{}, cfg, os.Stdout)
+	assert.False(t, p.enabled)
+
+	p = newPalette(&GlobalFlags{Color: "always"}, cfg, os.Stdout)
+	assert.True(t, p.enabled, "--color always overrides NO_COLOR")
+
+	p = newPalette(&GlobalFlags{Color: "always", JSON: true}, cfg, os.Stdout)
+	assert.False(t, p.enabled, "JSON output is never colored")
+}
+
+func TestNewPalette_ConfigTheme(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Output.Color = "always"
+	cfg.Output.Theme = "mono"
+
+	p := newPalette(&GlobalFlags{}, cfg, os.Stdout)
+	assert.True(t, p.enabled)
+	assert.Equal(t, themes["mono"], p.theme)
+
+	cfg.Output.Theme = "no-such-theme"
+	assert.Equal(t, themes["default"], newPalette(&GlobalFlags{}, cfg, os.Stdout).theme)
+}
+
+func TestPalette_Title(t *testing.T) {
+	p := palette{enabled: true, theme: themes["default"]}
+
+	out := p.title("Go Concurrency", []string{"go"})
+	assert.Equal(t, ansiBold+ansiReset+ansiBold+ansiYellow+"Go"+ansiReset+ansiBold+" Concurrency"+ansiReset, out)
+
+	assert.Equal(t, "plain", palette{}.title("plain", []string{"plain"}))
+	assert.Equal(t, ansiDim+"go.dev"+ansiReset, p.domain("go.dev"))
+}
+
+func TestHighlightTerms(t *testing.T) {
+	assert.Equal(t, []string{"rust", "async"}, highlightTerms(`"rust" AND async*`))
+	assert.Empty(t, highlightTerms(""))
+}
+
+func TestSearch_ColorAlways(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{
+		Since:   "30d",
+		Limit:   10,
+		globals: &GlobalFlags{},
+		style:   palette{enabled: true, theme: themes["default"]},
+	}
+
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB"}))
+	})
+
+	assert.Contains(t, output, ansiYellow+"LanceDB"+ansiReset)
+	assert.Contains(t, output, ansiDim+"lancedb.github.io"+ansiReset)
+}
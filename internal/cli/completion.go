@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	goflags "github.com/jessevdk/go-flags"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// completionCandidates caps how many recent events are offered when
+// completing an event ID.
+const completionCandidates = 200
+
+// EventID is a flag value holding a Chronicle event ID. It completes from
+// the most recent events in the database.
+type EventID string
+
+// Complete implements goflags.Completer. It never creates or migrates the
+// database; any failure yields no completions.
+func (e *EventID) Complete(match string) []goflags.Completion {
+	return completeEventIDsFor(completionGlobals(os.Args[1:]), match)
+}
+
+// completeEventIDsFor opens the database selected by globals read-only and
+// completes event IDs from it.
+func completeEventIDsFor(globals *GlobalFlags, match string) []goflags.Completion {
+	return completeFrom(globals, func(store *storage.SQLiteStore) []goflags.Completion {
+		return completeEventIDs(context.Background(), store, match)
+	})
+}
+
+// Domain is a flag value holding a domain name. It completes from the
+// domains in the database.
+type Domain string
+
+// Complete implements goflags.Completer, like EventID.Complete.
+func (d *Domain) Complete(match string) []goflags.Completion {
+	return completeFrom(completionGlobals(os.Args[1:]), func(store *storage.SQLiteStore) []goflags.Completion {
+		return completeDomains(context.Background(), store, match)
+	})
+}
+
+// domainValues converts repeated --domain values to strings.
+func domainValues(domains []Domain) []string {
+	out := make([]string, len(domains))
+	for i, d := range domains {
+		out[i] = string(d)
+	}
+	return out
+}
+
+// completeDomains returns captured domains starting with match, busiest
+// first, with event counts as descriptions.
+func completeDomains(ctx context.Context, store *storage.SQLiteStore, match string) []goflags.Completion {
+	domains, err := store.ListDomains(ctx, match)
+	if err != nil {
+		return nil
+	}
+	var items []goflags.Completion
+	for _, d := range domains {
+		items = append(items, goflags.Completion{Item: d.Domain, Description: formatNumber(d.Count)})
+	}
+	return items
+}
+
+// completeFrom opens the database selected by globals read-only and
+// completes from it with fn.
+func completeFrom(globals *GlobalFlags, fn func(store *storage.SQLiteStore) []goflags.Completion) []goflags.Completion {
+	db, _, err := openExistingDB(globals)
+	if err != nil {
+		return nil
+	}
+	defer db.Close()
+
+	store, err := storage.NewSQLiteStore(db)
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+
+	return fn(store)
+}
+
+// completeEventIDs returns recent event IDs starting with match (case-
+// insensitive), newest first, with titles as descriptions.
+func completeEventIDs(ctx context.Context, store *storage.SQLiteStore, match string) []goflags.Completion {
+	events, err := store.SearchEvents(ctx, storage.SearchQuery{Limit: completionCandidates})
+	if err != nil {
+		return nil
+	}
+
+	prefix := strings.ToUpper(match)
+	var items []goflags.Completion
+	for _, ev := range events {
+		if strings.HasPrefix(strings.ToUpper(ev.ID), prefix) {
+			items = append(items, goflags.Completion{Item: ev.ID, Description: ev.Title})
+		}
+	}
+	return items
+}
+
+// completionGlobals recovers --config, --db-path and --profile from raw
+// arguments (falling back to CHRONICLE_PROFILE).
+// go-flags does not populate option values while completing, so the
+// completer has to find them itself.
+func completionGlobals(args []string) *GlobalFlags {
+	globals := &GlobalFlags{Profile: os.Getenv("CHRONICLE_PROFILE")}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		switch name {
+		case "--config":
+			globals.Config = value
+		case "--db-path":
+			globals.DBPath = value
+		case "--profile":
+			globals.Profile = value
+		}
+	}
+	return globals
+}
+
+// Execute implements the go-flags Commander interface for CompletionCommand.
+func (c *CompletionCommand) Execute(args []string) error {
+	switch c.Shell {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print("autoload -U +X bashcompinit && bashcompinit\n" + bashCompletion)
+	default:
+		return fmt.Errorf("unsupported shell %q (use bash or zsh)", c.Shell)
+	}
+	return nil
+}
+
+// bashCompletion delegates to go-flags' built-in completion, which the
+// binary performs when GO_FLAGS_COMPLETION is set.
+const bashCompletion = `_chronicle() {
+    local args=("${COMP_WORDS[@]:1:$COMP_CWORD}")
+    local IFS=$'\n'
+    COMPREPLY=($(GO_FLAGS_COMPLETION=1 "${COMP_WORDS[0]}" "${args[@]}"))
+    return 0
+}
+complete -F _chronicle chronicle
+`
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestCompleteEventIDs_FiltersByPrefix(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+
+	a := &storage.Event{URL: "https://a.com", Title: "Page A", Source: "manual"}
+	b := &storage.Event{URL: "https://b.com", Title: "Page B", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, a))
+	require.NoError(t, store.AddEvent(ctx, b))
+
+	all := completeEventIDs(ctx, store, "CHR-")
+	assert.Len(t, all, 2)
+
+	only := completeEventIDs(ctx, store, a.ID)
+	require.Len(t, only, 1)
+	assert.Equal(t, a.ID, only[0].Item)
+	assert.Equal(t, "Page A", only[0].Description)
+
+	lower := completeEventIDs(ctx, store, "chr-")
+	assert.Len(t, lower, 2, "prefix match should be case-insensitive")
+}
+
+func TestEventIDComplete_UsesDBPathFromArgs(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "complete.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	event := &storage.Event{URL: "https://example.com", Title: "Example", Source: "manual"}
+	require.NoError(t, store.AddEvent(context.Background(), event))
+	store.Close()
+	db.Close()
+
+	globals := completionGlobals([]string{"--db-path", dbPath, "open", "--id", "CHR-"})
+	assert.Equal(t, dbPath, globals.DBPath)
+
+	items := completeEventIDsFor(globals, "CHR-")
+	require.Len(t, items, 1)
+	assert.Equal(t, event.ID, items[0].Item)
+
+	globals = completionGlobals([]string{"--db-path=" + dbPath, "--config", "/tmp/c.yaml", "open"})
+	assert.Equal(t, dbPath, globals.DBPath)
+	assert.Equal(t, "/tmp/c.yaml", globals.Config)
+}
+
+func TestEventIDComplete_MissingDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "missing.db")
+	assert.Empty(t, completeEventIDsFor(&GlobalFlags{DBPath: dbPath}, "CHR-"))
+	assert.NoFileExists(t, dbPath)
+}
+
+func TestCompletionCommand_Scripts(t *testing.T) {
+	output := captureOutput(t, func() {
+		require.NoError(t, (&CompletionCommand{Shell: "bash"}).Execute(nil))
+	})
+	assert.Contains(t, output, "GO_FLAGS_COMPLETION=1")
+	assert.Contains(t, output, "complete -F _chronicle chronicle")
+
+	output = captureOutput(t, func() {
+		require.NoError(t, (&CompletionCommand{Shell: "zsh"}).Execute(nil))
+	})
+	assert.Contains(t, output, "bashcompinit")
+
+	err := (&CompletionCommand{Shell: "fish"}).Execute(nil)
+	assert.Error(t, err)
+}
+
+func TestCompleteDomains(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	ctx := context.Background()
+
+	items := completeDomains(ctx, store, "g")
+	require.Len(t, items, 1)
+	assert.Equal(t, "github.com", items[0].Item)
+	assert.Equal(t, "1", items[0].Description)
+
+	assert.Empty(t, completeDomains(ctx, store, "nowhere"))
+	assert.Len(t, completeDomains(ctx, store, ""), 5)
+}
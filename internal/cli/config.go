@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// Execute implements the go-flags Commander interface for ConfigSetCommand.
+func (c *ConfigSetCommand) Execute(args []string) error {
+	if c.globals != nil && c.globals.Profile != "" && c.globals.Config == "" && !config.ProfileExists(c.globals.Profile) {
+		return fmt.Errorf("profile %q does not exist (create it with: chronicle profile create %s)", c.globals.Profile, c.globals.Profile)
+	}
+
+	path, err := configFilePath(c.globals)
+	if err != nil {
+		return err
+	}
+
+	if err := config.Set(path, c.Args.Key, c.Args.Value); err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]string{
+			"key":   c.Args.Key,
+			"value": c.Args.Value,
+			"file":  path,
+		})
+	}
+
+	infof(c.globals, "Set %s = %s in %s\n", c.Args.Key, c.Args.Value, path)
+	return nil
+}
+
+// configValueJSON is one entry of `config show --json`.
+type configValueJSON struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Origin string `json:"origin,omitempty"`
+}
+
+// Execute implements the go-flags Commander interface for ConfigShowCommand.
+func (c *ConfigShowCommand) Execute(args []string) error {
+	cfg, origins, err := loadLayeredConfig(c.globals)
+	if err != nil {
+		return err
+	}
+	return c.write(os.Stdout, cfg, origins)
+}
+
+// write prints the effective values of cfg, one "key = value" per line,
+// annotated with their origin under --origin.
+func (c *ConfigShowCommand) write(w io.Writer, cfg *config.Config, origins config.Origins) error {
+	leaves, err := config.Leaves(cfg)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]configValueJSON, len(leaves))
+		for i, kv := range leaves {
+			out[i] = configValueJSON{Key: kv[0], Value: kv[1]}
+			if c.Origin {
+				out[i].Origin = origins.Of(kv[0]).String()
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	for _, kv := range leaves {
+		if c.Origin {
+			fmt.Fprintf(w, "%s = %s\t# %s\n", kv[0], kv[1], origins.Of(kv[0]))
+		} else {
+			fmt.Fprintf(w, "%s = %s\n", kv[0], kv[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func TestConfigSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# mine\nretention:\n  days: 30\n"), 0644))
+
+	output := captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"--config", path, "config", "set", "retention.days", "7"}))
+	})
+	assert.Contains(t, output, "Set retention.days = 7")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 7, cfg.Retention.Days)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# mine")
+}
+
+func TestConfigSet_UnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := RunWithArgs("test", []string{"--config", path, "config", "set", "nope.key", "1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown config key")
+}
+
+func TestConfigShowOrigin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	userPath, err := config.DefaultPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(userPath), 0755))
+	require.NoError(t, os.WriteFile(userPath, []byte("retention:\n  days: 14\n"), 0644))
+
+	work := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(work, config.LocalConfigName), []byte("search:\n  default_limit: 25\n"), 0644))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(work))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	output := captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"config", "show", "--origin"}))
+	})
+	assert.Contains(t, output, "retention.days = 14\t# user ("+userPath+")")
+	assert.Contains(t, output, "search.default_limit = 25\t# local (.chronicle.yaml)")
+	assert.Contains(t, output, "retention.prune_interval_hours = 24\t# default")
+}
+
+func TestConfigShowExplicitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("api:\n  port: 9000\n"), 0644))
+
+	output := captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"--config", path, "config", "show"}))
+	})
+	assert.Contains(t, output, "api.port = 9000\n")
+	assert.NotContains(t, output, "#")
+}
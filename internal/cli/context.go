@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// charsPerToken is the rough characters-per-token ratio used to estimate
+// token counts without a tokenizer.
+const charsPerToken = 4
+
+// contextEntry is one event packed into the context block.
+type contextEntry struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Captured  string `json:"captured"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated"`
+}
+
+// contextJSON is the JSON output structure for the context command.
+type contextJSON struct {
+	Query  string         `json:"query"`
+	Budget int            `json:"budget"`
+	Tokens int            `json:"tokens"`
+	Events []contextEntry `json:"events"`
+}
+
+// Execute implements the go-flags Commander interface for ContextCommand.
+func (c *ContextCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	c.embedCfg = loadConfig(c.globals).Embeddings
+	return c.executeWithStore(store, args)
+}
+
+// executeWithStore builds the context block against a provided store (used by tests).
+func (c *ContextCommand) executeWithStore(store *storage.SQLiteStore, args []string) error {
+	query := c.Query
+	if query == "" && len(args) > 0 {
+		query = strings.Join(args, " ")
+	}
+	if query == "" {
+		return fmt.Errorf("--query is required for context command")
+	}
+	if c.Budget <= 0 {
+		return fmt.Errorf("--budget must be positive")
+	}
+
+	since, _, err := resolveTimeRange(c.Since, "", time.Now())
+	if err != nil {
+		return err
+	}
+
+	sq := storage.SearchQuery{
+		Query:   query,
+		Since:   since,
+		Limit:   c.Limit,
+		Domains: domainValues(c.Domain),
+	}
+
+	ctx := context.Background()
+	results, err := c.search(ctx, store, sq)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	// No entry can use more than the whole budget, so only that much of
+	// each body is read.
+	previewBytes := c.Budget * charsPerToken * utf8.UTFMax
+	var ids []string
+	for _, e := range results {
+		if e.HasBody {
+			ids = append(ids, e.ID)
+		}
+	}
+	previews, err := store.GetContentPreviews(ctx, ids, previewBytes)
+	if err != nil {
+		return err
+	}
+	bodies := make([]string, len(results))
+	for i, e := range results {
+		if c, ok := previews[e.ID]; ok {
+			bodies[i] = c.Body
+		}
+	}
+
+	entries, used := packContext(results, bodies, c.Budget)
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(contextJSON{
+			Query:  query,
+			Budget: c.Budget,
+			Tokens: used,
+			Events: entries,
+		})
+	}
+
+	fmt.Printf("<chronicle_context query=%q events=\"%d\" tokens=\"%d\">\n", query, len(entries), used)
+	for _, e := range entries {
+		fmt.Printf("<event id=%q title=%q url=%q captured=%q>\n", e.ID, e.Title, e.URL, e.Captured)
+		if e.Content != "" {
+			fmt.Println(e.Content)
+		}
+		fmt.Println("</event>")
+	}
+	fmt.Println("</chronicle_context>")
+	return nil
+}
+
+// search finds the events for the context block: by hybrid search (see
+// storage.HybridSearch) when embeddings are enabled, else, or when the
+// query cannot be embedded, by keyword.
+func (c *ContextCommand) search(ctx context.Context, store *storage.SQLiteStore, sq storage.SearchQuery) ([]storage.Event, error) {
+	if c.embedder == nil && !c.embedCfg.Enabled {
+		return store.SearchEvents(ctx, sq)
+	}
+	model, vector, err := embedQuery(ctx, c.embedder, c.embedCfg, sq.Query)
+	if err != nil {
+		noticef(c.globals, "Warning: %v; using keyword search\n", err)
+		return store.SearchEvents(ctx, sq)
+	}
+	found, err := store.HybridSearch(ctx, sq, model, vector)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]storage.Event, len(found))
+	for i, r := range found {
+		results[i] = r.Event
+	}
+	return results, nil
+}
+
+// packContext fits events into the token budget in rank order. Each event's
+// header is always included; bodies are trimmed to a fair share of what
+// remains, so budget unused by short bodies carries over to later events.
+// Events whose header alone no longer fits are dropped.
+func packContext(events []storage.Event, bodies []string, budget int) ([]contextEntry, int) {
+	entries := []contextEntry{}
+	remaining := budget
+
+	for i, e := range events {
+		entry := contextEntry{
+			ID:       e.ID,
+			Title:    e.Title,
+			URL:      e.URL,
+			Captured: e.Timestamp.UTC().Format(time.RFC3339),
+		}
+
+		header := estimateTokens(entry.ID + entry.Title + entry.URL + entry.Captured)
+		if header > remaining {
+			break
+		}
+		remaining -= header
+
+		share := remaining / (len(events) - i)
+		body := strings.TrimSpace(bodies[i])
+		if estimateTokens(body) > share {
+			body = truncateToTokens(body, share)
+			entry.Truncated = true
+		}
+		entry.Content = body
+		remaining -= estimateTokens(body)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, budget - remaining
+}
+
+// estimateTokens approximates the token count of s.
+func estimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + charsPerToken - 1) / charsPerToken
+}
+
+// truncateToTokens cuts s to roughly the given token count, preferring a word
+// boundary, and marks the cut with an ellipsis.
+func truncateToTokens(s string, tokens int) string {
+	maxRunes := tokens*charsPerToken - 1
+	if maxRunes <= 0 {
+		return ""
+	}
+
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndexAny(cut, " \n\t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
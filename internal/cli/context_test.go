@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func seedContextEvents(t *testing.T, store *storage.SQLiteStore) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	long := &storage.Event{URL: "https://sqlite.org/wal.html", Title: "SQLite WAL mode", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, long, strings.Repeat("write ahead logging ", 500)))
+
+	short := &storage.Event{URL: "https://sqlite.org/fts5.html", Title: "SQLite FTS5", Source: "extension", Timestamp: now.Add(-2 * time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, short, "Full-text search extension."))
+
+	other := &storage.Event{URL: "https://go.dev/", Title: "Go", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, other))
+}
+
+func TestContext_EmitsStructuredBlock(t *testing.T) {
+	store := setupSearchStore(t)
+	seedContextEvents(t, store)
+
+	cmd := &ContextCommand{Query: "sqlite", Budget: 8000, Since: "7d", Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+
+	assert.True(t, strings.HasPrefix(output, `<chronicle_context query="sqlite" events="2"`))
+	assert.Contains(t, output, `title="SQLite FTS5"`)
+	assert.Contains(t, output, "Full-text search extension.")
+	assert.Contains(t, output, "</chronicle_context>")
+	assert.NotContains(t, output, `title="Go"`)
+}
+
+func TestContext_RespectsBudget(t *testing.T) {
+	store := setupSearchStore(t)
+	seedContextEvents(t, store)
+
+	cmd := &ContextCommand{Query: "sqlite", Budget: 200, Since: "7d", Limit: 20, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+
+	var result contextJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.LessOrEqual(t, result.Tokens, 200)
+	require.Len(t, result.Events, 2)
+
+	for _, e := range result.Events {
+		if e.Title == "SQLite WAL mode" {
+			assert.True(t, e.Truncated)
+			assert.True(t, strings.HasSuffix(e.Content, "…"))
+		} else {
+			assert.False(t, e.Truncated)
+			assert.Equal(t, "Full-text search extension.", e.Content)
+		}
+	}
+}
+
+// downEmbedder fails like an embeddings server that is not running.
+type downEmbedder struct{}
+
+func (downEmbedder) Embed(context.Context, []string) ([][]float32, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (downEmbedder) Model() string { return "fake:down" }
+
+func TestContext_HybridSearch(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	embedder := &topicEmbedder{}
+	embed := &EmbedCommand{globals: &GlobalFlags{Quiet: true}, embedder: embedder}
+	require.NoError(t, embed.executeWithStore(store, config.DefaultConfig().Embeddings))
+
+	titles := func(output string) []string {
+		var result contextJSON
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		var out []string
+		for _, e := range result.Events {
+			out = append(out, e.Title)
+		}
+		return out
+	}
+
+	// No title has the words, but the embeddings match.
+	cmd := &ContextCommand{Query: "vector databases", Budget: 8000, Since: "30d", Limit: 2, globals: &GlobalFlags{JSON: true}, embedder: embedder}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+	assert.ElementsMatch(t, []string{"LanceDB Getting Started", "ChromaDB vs LanceDB"}, titles(output))
+
+	// When the query cannot be embedded, it is a keyword search.
+	cmd = &ContextCommand{Query: "lancedb", Budget: 8000, Since: "30d", Limit: 5, globals: &GlobalFlags{JSON: true}, embedder: downEmbedder{}}
+	stderr := captureStderr(t, func() {
+		output = captureOutput(t, func() {
+			require.NoError(t, cmd.executeWithStore(store, nil))
+		})
+	})
+	assert.Contains(t, stderr, "Warning: embedding the query: connection refused; using keyword search")
+	assert.ElementsMatch(t, []string{"LanceDB Getting Started", "ChromaDB vs LanceDB"}, titles(output))
+
+	// So it is with embeddings disabled.
+	cmd = &ContextCommand{Query: "vector databases", Budget: 8000, Since: "30d", Limit: 5, globals: &GlobalFlags{JSON: true}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+	assert.Empty(t, titles(output))
+}
+
+func TestContext_RequiresQuery(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &ContextCommand{Budget: 8000, globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--query is required")
+}
+
+func TestPackContext_CarriesUnusedBudgetForward(t *testing.T) {
+	events := []storage.Event{{ID: "CHR-1"}, {ID: "CHR-2"}}
+	bodies := []string{"tiny", strings.Repeat("x", 4000)}
+
+	entries, used := packContext(events, bodies, 500)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "tiny", entries[0].Content)
+	assert.True(t, entries[1].Truncated)
+	assert.Greater(t, estimateTokens(entries[1].Content), 250, "second event should get more than half the budget")
+	assert.LessOrEqual(t, used, 500)
+}
+
+func TestTruncateToTokens_PrefersWordBoundary(t *testing.T) {
+	out := truncateToTokens("alpha beta gamma delta epsilon", 4)
+	assert.Equal(t, "alpha beta…", out)
+}
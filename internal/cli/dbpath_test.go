@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDBPathHonoredByAllCommands runs each database-backed command against
+// a temp database given only via --db-path.
+func TestDBPathHonoredByAllCommands(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "chronicle.db")
+	run := func(args ...string) string {
+		t.Helper()
+		args = append(args, "--config", "/dev/null", "--db-path", dbPath)
+		return captureOutput(t, func() {
+			require.NoError(t, RunWithArgs("test", args))
+		})
+	}
+
+	run("add", "--url", "https://example.com/dbpath", "--title", "DB Path Probe")
+
+	var results jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(run("search", "--json", "Probe")), &results))
+	assert.Equal(t, 1, results.Count)
+
+	var status statusJSON
+	require.NoError(t, json.Unmarshal([]byte(run("status", "--json")), &status))
+	assert.Equal(t, dbPath, status.DatabasePath)
+	assert.Equal(t, int64(1), status.TotalEvents)
+
+	assert.Contains(t, run("prune", "--force"), "No events to prune")
+	assert.Contains(t, run("purge", "--domain", "example.com", "--force"), "Purged 1 events")
+
+	require.NoError(t, json.Unmarshal([]byte(run("status", "--json")), &status))
+	assert.Equal(t, int64(0), status.TotalEvents)
+}
+
+func TestResolveDBPath_Priority(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("storage:\n  path: "+dir+"\n  sqlite_file: custom.db\n"), 0644))
+
+	path, err := resolveDBPath(&GlobalFlags{Config: cfgPath})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "custom.db"), path)
+
+	path, err = resolveDBPath(&GlobalFlags{Config: cfgPath, DBPath: "/tmp/override.db"})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/override.db", path)
+}
+
+func TestOpenExistingDB_Missing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "none.db")
+
+	_, dbPath, err := openExistingDB(&GlobalFlags{DBPath: missing})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, missing, dbPath)
+
+	_, statErr := os.Stat(missing)
+	assert.True(t, os.IsNotExist(statErr), "must not create the database")
+}
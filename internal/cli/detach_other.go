@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package cli
+
+import "syscall"
+
+// detachedProcAttr has nothing to add where sessions are not supported.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package cli
+
+import "syscall"
+
+// detachedProcess is the DETACHED_PROCESS creation flag: the daemon gets
+// no console.
+const detachedProcess = 0x00000008
+
+// detachedProcAttr starts the daemon without a console and in its own
+// process group, so it outlives the console that started it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess}
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/fabric"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// digestJSON is the JSON output structure for the digest command.
+type digestJSON struct {
+	Path    string `json:"path"`
+	Period  string `json:"period"`
+	Since   string `json:"since"`
+	Until   string `json:"until"`
+	Events  int    `json:"events"`
+	Domains int    `json:"domains"`
+}
+
+// digestGroup is one domain's section of a digest.
+type digestGroup struct {
+	Domain string
+	Events []storage.Event
+}
+
+// Execute implements the go-flags Commander interface for DigestCommand.
+func (c *DigestCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, loadConfig(c.globals), time.Now())
+}
+
+// executeWithStore builds the digest for the period ending at now against a
+// provided store and config (used by tests).
+func (c *DigestCommand) executeWithStore(store *storage.SQLiteStore, cfg *config.Config, now time.Time) error {
+	period, err := digestPeriod(c.Period)
+	if err != nil {
+		return err
+	}
+	since := now.Add(-period)
+
+	limit := c.Limit
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	ctx := context.Background()
+	events, err := store.SearchEvents(ctx, storage.SearchQuery{
+		Since: since,
+		Until: now,
+		Limit: limit,
+	})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	groups := groupByDomain(events)
+
+	var narrative string
+	if c.Pattern != "" && len(events) > 0 {
+		fab, err := fabric.New(cfg.Fabric)
+		if err != nil {
+			return err
+		}
+		var listing strings.Builder
+		writeDigestGroups(&listing, groups)
+		narrative, err = fab.Run(ctx, c.Pattern, listing.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	path := c.Output
+	if path == "" {
+		path = fmt.Sprintf("chronicle-digest-%s-%s.md", c.Period, now.Local().Format("2006-01-02"))
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create digest file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	writeDigest(w, c.Period, since, now, events, groups, narrative)
+
+	if path == "-" {
+		return nil
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(digestJSON{
+			Path:    path,
+			Period:  c.Period,
+			Since:   since.UTC().Format(time.RFC3339),
+			Until:   now.UTC().Format(time.RFC3339),
+			Events:  len(events),
+			Domains: len(groups),
+		})
+	}
+
+	infof(c.globals, "Wrote %s digest (%d events, %d domains) to %s\n", c.Period, len(events), len(groups), path)
+	return nil
+}
+
+// digestPeriod maps a --period value to its duration.
+func digestPeriod(period string) (time.Duration, error) {
+	switch period {
+	case "day":
+		return 24 * time.Hour, nil
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid --period %q (use day or week)", period)
+	}
+}
+
+// groupByDomain buckets events by domain, largest group first, with events
+// inside each group in chronological order.
+func groupByDomain(events []storage.Event) []digestGroup {
+	index := make(map[string]int)
+	var groups []digestGroup
+	for _, e := range events {
+		i, ok := index[e.Domain]
+		if !ok {
+			i = len(groups)
+			index[e.Domain] = i
+			groups = append(groups, digestGroup{Domain: e.Domain})
+		}
+		groups[i].Events = append(groups[i].Events, e)
+	}
+
+	for _, g := range groups {
+		sort.Slice(g.Events, func(a, b int) bool {
+			return g.Events[a].Timestamp.Before(g.Events[b].Timestamp)
+		})
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		if len(groups[a].Events) != len(groups[b].Events) {
+			return len(groups[a].Events) > len(groups[b].Events)
+		}
+		return groups[a].Domain < groups[b].Domain
+	})
+
+	return groups
+}
+
+// writeDigest renders the full markdown digest.
+func writeDigest(w io.Writer, period string, since, until time.Time, events []storage.Event, groups []digestGroup, narrative string) {
+	fmt.Fprintf(w, "# Chronicle %s digest: %s – %s\n\n", period,
+		since.Local().Format("2006-01-02"), until.Local().Format("2006-01-02"))
+	fmt.Fprintf(w, "%d captures across %d domains.\n\n", len(events), len(groups))
+
+	if narrative = strings.TrimSpace(narrative); narrative != "" {
+		fmt.Fprintln(w, "## Summary")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, narrative)
+		fmt.Fprintln(w)
+	}
+
+	writeDigestGroups(w, groups)
+}
+
+// writeDigestGroups renders one markdown section per domain.
+func writeDigestGroups(w io.Writer, groups []digestGroup) {
+	for _, g := range groups {
+		domain := g.Domain
+		if domain == "" {
+			domain = "(no domain)"
+		}
+		fmt.Fprintf(w, "## %s (%d)\n\n", domain, len(g.Events))
+		for _, e := range g.Events {
+			title := e.Title
+			if title == "" {
+				title = e.URL
+			}
+			fmt.Fprintf(w, "- [%s](%s) — %s\n", title, e.URL, e.Timestamp.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func seedDigestEvents(t *testing.T, store *storage.SQLiteStore, now time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	events := []struct {
+		url, title string
+		ago        time.Duration
+	}{
+		{"https://github.com/golang/go", "Go repo", 2 * time.Hour},
+		{"https://github.com/mattn/go-sqlite3", "go-sqlite3", 30 * time.Hour},
+		{"https://news.ycombinator.com/", "Hacker News", 3 * 24 * time.Hour},
+		{"https://old.example.com/", "Too old", 10 * 24 * time.Hour},
+	}
+	for _, ev := range events {
+		e := &storage.Event{URL: ev.url, Title: ev.title, Source: "extension", Timestamp: now.Add(-ev.ago)}
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+}
+
+func TestDigest_WeekGroupsByDomain(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	out := filepath.Join(t.TempDir(), "digest.md")
+	cmd := &DigestCommand{Period: "week", Output: out, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, config.DefaultConfig(), now))
+	})
+	assert.Contains(t, output, "3 events, 2 domains")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	md := string(data)
+
+	assert.Contains(t, md, "# Chronicle week digest")
+	assert.Contains(t, md, "## github.com (2)")
+	assert.Contains(t, md, "## news.ycombinator.com (1)")
+	assert.Contains(t, md, "[Go repo](https://github.com/golang/go)")
+	assert.NotContains(t, md, "Too old")
+	assert.Less(t, strings.Index(md, "github.com"), strings.Index(md, "news.ycombinator.com"))
+}
+
+func TestDigest_DayPeriod(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	cmd := &DigestCommand{Period: "day", Output: "-", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, config.DefaultConfig(), now))
+	})
+
+	assert.Contains(t, output, "1 captures across 1 domains")
+	assert.Contains(t, output, "Go repo")
+	assert.NotContains(t, output, "go-sqlite3")
+}
+
+func TestDigest_WithFabricNarrative(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	cmd := &DigestCommand{Period: "week", Output: "-", Pattern: "summarize", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, fakeFabric(t), now))
+	})
+
+	assert.Contains(t, output, "## Summary")
+	assert.Contains(t, output, "pattern=summarize")
+}
+
+func TestDigest_JSONOutput(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	out := filepath.Join(t.TempDir(), "digest.md")
+	cmd := &DigestCommand{Period: "week", Output: out, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, config.DefaultConfig(), now))
+	})
+
+	var result digestJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, out, result.Path)
+	assert.Equal(t, 3, result.Events)
+	assert.Equal(t, 2, result.Domains)
+}
+
+func TestDigest_InvalidPeriod(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &DigestCommand{Period: "fortnight", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, config.DefaultConfig(), time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --period")
+}
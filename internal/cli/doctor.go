@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// doctorJSON is the JSON output structure for doctor.
+type doctorJSON struct {
+	Events      int64 `json:"events"`
+	FTSMissing  int64 `json:"fts_missing"`
+	FTSOrphaned int64 `json:"fts_orphaned"`
+	Rebuilt     bool  `json:"fts_rebuilt"`
+	Indexed     int64 `json:"fts_indexed,omitempty"`
+}
+
+// errFTSInconsistent is returned by doctor when the full-text index is out
+// of step with the events table and --rebuild-fts was not given.
+var errFTSInconsistent = errors.New("full-text index is inconsistent; run chronicle doctor --rebuild-fts")
+
+// Execute implements the go-flags Commander interface for DoctorCommand.
+func (c *DoctorCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore checks, and with --rebuild-fts repairs, a provided store
+// (used by tests).
+func (c *DoctorCommand) executeWithStore(store *storage.SQLiteStore) error {
+	ctx := context.Background()
+	check, err := store.CheckFTS(ctx)
+	if err != nil {
+		return err
+	}
+	out := doctorJSON{Events: check.Events, FTSMissing: check.Missing, FTSOrphaned: check.Orphaned}
+
+	if c.RebuildFTS {
+		n, err := store.RebuildFTS(ctx)
+		if err != nil {
+			return err
+		}
+		out.Rebuilt, out.Indexed = true, n
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		if check.OK() {
+			infof(c.globals, "Full-text index: OK (%s events)\n", formatNumber(check.Events))
+		} else {
+			fmt.Printf("Full-text index: %s of %s events not indexed, %s stale entries\n",
+				formatNumber(check.Missing), formatNumber(check.Events), formatNumber(check.Orphaned))
+		}
+		if out.Rebuilt {
+			infof(c.globals, "Rebuilt the full-text index (%s events)\n", formatNumber(out.Indexed))
+		}
+	}
+
+	if !check.OK() && !out.Rebuilt {
+		return errFTSInconsistent
+	}
+	return nil
+}
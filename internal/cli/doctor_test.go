@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDoctorStore returns a store holding one event whose FTS entry is
+// missing.
+func setupDoctorStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	e := &storage.Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "manual"}
+	require.NoError(t, store.AddEvent(context.Background(), e))
+	_, err = db.Exec(`DELETE FROM events_fts WHERE event_id = ?`, e.ID)
+	require.NoError(t, err)
+	return store
+}
+
+func TestDoctor_ReportsAndRebuilds(t *testing.T) {
+	store := setupDoctorStore(t)
+
+	cmd := &DoctorCommand{globals: &GlobalFlags{}}
+	var err error
+	output := captureOutput(t, func() {
+		err = cmd.executeWithStore(store)
+	})
+	assert.ErrorIs(t, err, errFTSInconsistent)
+	assert.Equal(t, "Full-text index: 1 of 1 events not indexed, 0 stale entries\n", output)
+
+	cmd.RebuildFTS = true
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	assert.Contains(t, output, "Rebuilt the full-text index (1 events)\n")
+
+	cmd.RebuildFTS = false
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	assert.Equal(t, "Full-text index: OK (1 events)\n", output)
+}
+
+func TestDoctor_JSON(t *testing.T) {
+	store := setupDoctorStore(t)
+
+	cmd := &DoctorCommand{RebuildFTS: true, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	var out doctorJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, doctorJSON{Events: 1, FTSMissing: 1, Rebuilt: true, Indexed: 1}, out)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Execute implements the go-flags Commander interface for DomainsCommand.
+func (c *DomainsCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, args)
+}
+
+// executeWithStore lists domains from a provided store (used by tests).
+func (c *DomainsCommand) executeWithStore(store *storage.SQLiteStore, args []string) error {
+	if c.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	prefix := strings.Join(args, "")
+	domains, err := store.ListDomains(context.Background(), prefix)
+	if err != nil {
+		return err
+	}
+	total := len(domains)
+	if c.Limit > 0 && len(domains) > c.Limit {
+		domains = domains[:c.Limit]
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]domainCountJSON, len(domains))
+		for i, d := range domains {
+			out[i] = domainCountJSON{Domain: d.Domain, Count: d.Count}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if total == 0 {
+		if prefix != "" {
+			infof(c.globals, "No domains starting with %q\n", prefix)
+		} else {
+			infof(c.globals, "No domains captured yet\n")
+		}
+		return nil
+	}
+	if len(domains) < total {
+		infof(c.globals, "Top %d of %d domains:\n\n", len(domains), total)
+	} else {
+		infof(c.globals, "%d domains:\n\n", total)
+	}
+	width := 0
+	for _, d := range domains {
+		width = max(width, len(d.Domain))
+	}
+	for _, d := range domains {
+		fmt.Printf("  %-*s  %s\n", width, d.Domain, formatNumber(d.Count))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomains_Human(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &DomainsCommand{Limit: 2, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+	assert.Contains(t, output, "Top 2 of 5 domains:")
+	assert.Contains(t, output, "  blog.example.com  1\n")
+	assert.Contains(t, output, "  docs.python.org   1\n")
+	assert.NotContains(t, output, "news.ycombinator.com")
+
+	cmd = &DomainsCommand{globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"nowhere"}))
+	})
+	assert.Contains(t, output, `No domains starting with "nowhere"`)
+}
+
+func TestDomains_JSONPrefix(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &DomainsCommand{Limit: 50, globals: &GlobalFlags{JSON: true}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"L"}))
+	})
+	var out []domainCountJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, []domainCountJSON{{Domain: "lancedb.github.io", Count: 1}}, out)
+}
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// maxDuplicateScan caps how many events near-duplicate detection loads.
+const maxDuplicateScan = 50000
+
+// Duplicate cluster kinds.
+const (
+	dupContent = "content"
+	dupURL     = "url"
+	dupTitle   = "title"
+)
+
+// dupCluster is a group of events that look like duplicates of each other.
+type dupCluster struct {
+	Kind   string
+	Key    string // the shared hash, normalized URL, or first title
+	Events []storage.Event
+}
+
+type dupClusterJSON struct {
+	Kind   string         `json:"kind"`
+	Key    string         `json:"key"`
+	Events []dupEventJSON `json:"events"`
+}
+
+type dupEventJSON struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Captured string `json:"captured"`
+}
+
+// Execute implements the go-flags Commander interface for DuplicatesCommand.
+func (c *DuplicatesCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore reports duplicates against a provided store (used by
+// tests).
+func (c *DuplicatesCommand) executeWithStore(store *storage.SQLiteStore, now time.Time) error {
+	if c.Similarity <= 0 || c.Similarity > 1 {
+		return fmt.Errorf("invalid --similarity %v (use a value in (0, 1])", c.Similarity)
+	}
+	if c.Limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	since, _, err := resolveTimeRange(c.Since, "", now)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	shared, err := store.SharedContentEvents(ctx, since)
+	if err != nil {
+		return err
+	}
+	events, err := store.SearchEvents(ctx, storage.SearchQuery{Since: since, Until: now, Limit: maxDuplicateScan, Sort: storage.SortOldest})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	clusters := map[string][]dupCluster{
+		dupContent: contentClusters(shared),
+		dupURL:     urlClusters(events),
+		dupTitle:   titleClusters(events, c.Similarity),
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := []dupClusterJSON{}
+		for _, kind := range []string{dupContent, dupURL, dupTitle} {
+			for i, cl := range clusters[kind] {
+				if i == c.Limit {
+					break
+				}
+				j := dupClusterJSON{Kind: cl.Kind, Key: cl.Key}
+				for _, e := range cl.Events {
+					j.Events = append(j.Events, dupEventJSON{ID: e.ID, Title: e.Title, URL: e.URL, Captured: e.Timestamp.UTC().Format(time.RFC3339)})
+				}
+				out = append(out, j)
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	headings := map[string]string{
+		dupContent: "Identical content",
+		dupURL:     "Same URL",
+		dupTitle:   "Similar titles",
+	}
+	found := 0
+	for _, kind := range []string{dupContent, dupURL, dupTitle} {
+		list := clusters[kind]
+		if len(list) == 0 {
+			continue
+		}
+		found += len(list)
+		word := "clusters"
+		if len(list) == 1 {
+			word = "cluster"
+		}
+		fmt.Printf("%s (%d %s)\n", headings[kind], len(list), word)
+		for i, cl := range list {
+			if i == c.Limit {
+				fmt.Printf("  …and %d more\n", len(list)-c.Limit)
+				break
+			}
+			fmt.Printf("  %d × %s\n", len(cl.Events), cl.Key)
+			for _, e := range cl.Events {
+				fmt.Printf("      %s  %s  %s\n", e.ID, e.Timestamp.Local().Format("2006-01-02 15:04"), e.URL)
+			}
+		}
+		fmt.Println()
+	}
+	if found == 0 {
+		infof(c.globals, "No duplicates found (since %s)\n", c.Since)
+	}
+	return nil
+}
+
+// contentClusters splits events ordered by content_hash into one cluster
+// per hash.
+func contentClusters(events []storage.Event) []dupCluster {
+	var out []dupCluster
+	for _, e := range events {
+		if n := len(out); n > 0 && out[n-1].Key == e.ContentHash {
+			out[n-1].Events = append(out[n-1].Events, e)
+			continue
+		}
+		out = append(out, dupCluster{Kind: dupContent, Key: e.ContentHash, Events: []storage.Event{e}})
+	}
+	for i := range out {
+		if title := out[i].Events[0].Title; title != "" {
+			out[i].Key = fmt.Sprintf("%.12s… %q", out[i].Key, title)
+		}
+	}
+	sortClusters(out)
+	return out
+}
+
+// urlClusters groups events whose page URLs (canonical when declared)
+// normalize to the same page.
+func urlClusters(events []storage.Event) []dupCluster {
+	index := map[string]int{}
+	var all []dupCluster
+	for _, e := range events {
+		key := normalizeDupURL(e.PageURL())
+		i, ok := index[key]
+		if !ok {
+			i = len(all)
+			index[key] = i
+			all = append(all, dupCluster{Kind: dupURL, Key: key})
+		}
+		all[i].Events = append(all[i].Events, e)
+	}
+	return multiEventClusters(all)
+}
+
+// titleClusters groups events on the same domain whose titles share at
+// least threshold of their words (Jaccard), linking transitively. Events
+// with the same normalized URL are left to urlClusters.
+func titleClusters(events []storage.Event, threshold float64) []dupCluster {
+	byDomain := map[string][]int{}
+	tokens := make([]map[string]bool, len(events))
+	for i, e := range events {
+		tokens[i] = titleTokens(e.Title)
+		if len(tokens[i]) >= 2 {
+			byDomain[e.Domain] = append(byDomain[e.Domain], i)
+		}
+	}
+
+	parent := make([]int, len(events))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, idx := range byDomain {
+		for a := 0; a < len(idx); a++ {
+			for b := a + 1; b < len(idx); b++ {
+				i, j := idx[a], idx[b]
+				if normalizeDupURL(events[i].PageURL()) == normalizeDupURL(events[j].PageURL()) {
+					continue
+				}
+				if jaccard(tokens[i], tokens[j]) >= threshold {
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	index := map[int]int{}
+	var all []dupCluster
+	for i, e := range events {
+		if len(tokens[i]) < 2 {
+			continue
+		}
+		root := find(i)
+		k, ok := index[root]
+		if !ok {
+			k = len(all)
+			index[root] = k
+			all = append(all, dupCluster{Kind: dupTitle, Key: fmt.Sprintf("%q", e.Title)})
+		}
+		all[k].Events = append(all[k].Events, e)
+	}
+	return multiEventClusters(all)
+}
+
+// multiEventClusters keeps clusters with more than one event, largest
+// first.
+func multiEventClusters(all []dupCluster) []dupCluster {
+	var out []dupCluster
+	for _, cl := range all {
+		if len(cl.Events) > 1 {
+			out = append(out, cl)
+		}
+	}
+	sortClusters(out)
+	return out
+}
+
+// sortClusters orders clusters largest first, then by key.
+func sortClusters(list []dupCluster) {
+	sort.SliceStable(list, func(a, b int) bool {
+		if len(list[a].Events) != len(list[b].Events) {
+			return len(list[a].Events) > len(list[b].Events)
+		}
+		return list[a].Key < list[b].Key
+	})
+}
+
+// normalizeDupURL reduces a URL to the parts that identify the page: host
+// without a "www.", "m.", "mobile." or "amp." prefix, path without a
+// trailing slash or AMP suffix, and the query minus tracking and AMP
+// parameters. Scheme and fragment are dropped.
+func normalizeDupURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	host := strings.ToLower(u.Host)
+	for _, prefix := range []string{"www.", "m.", "mobile.", "amp."} {
+		if rest, ok := strings.CutPrefix(host, prefix); ok && strings.Contains(rest, ".") {
+			host = rest
+			break
+		}
+	}
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/amp"), ".amp")
+
+	q := u.Query()
+	for key := range q {
+		if strings.HasPrefix(key, "utm_") || key == "fbclid" || key == "gclid" || key == "ref" || key == "amp" {
+			q.Del(key)
+		}
+	}
+	if enc := q.Encode(); enc != "" {
+		return host + path + "?" + enc
+	}
+	return host + path
+}
+
+// titleTokens lowercases a title and splits it into its set of words.
+func titleTokens(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// jaccard returns the size of the intersection of a and b over their union.
+func jaccard(a, b map[string]bool) float64 {
+	inter := 0
+	for w := range a {
+		if b[w] {
+			inter++
+		}
+	}
+	union := len(a) + len(b) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
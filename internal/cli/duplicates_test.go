@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func seedDuplicateEvents(t *testing.T, store *storage.SQLiteStore, now time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	events := []struct {
+		url, title, hash string
+		ago              time.Duration
+	}{
+		{"https://blog.example.com/post", "Understanding SQLite WAL mode", "abc123", 3 * time.Hour},
+		{"https://mirror.example.org/post", "Understanding SQLite WAL mode (mirror)", "abc123", 2 * time.Hour},
+		{"https://www.go.dev/doc/?utm_source=x", "Documentation", "", 5 * time.Hour},
+		{"http://go.dev/doc#install", "Documentation", "", 4 * time.Hour},
+		{"https://news.example.com/a/1", "Go 1.23 released with iterators", "", 6 * time.Hour},
+		{"https://news.example.com/a/2", "Go 1.23 Released With Iterators!", "", 1 * time.Hour},
+		{"https://news.example.com/a/3", "Something else entirely", "", 1 * time.Hour},
+	}
+	for _, ev := range events {
+		e := &storage.Event{URL: ev.url, Title: ev.title, Source: "extension", ContentHash: ev.hash, Timestamp: now.Add(-ev.ago)}
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+}
+
+func TestDuplicates_Human(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Now()
+	seedDuplicateEvents(t, store, now)
+
+	cmd := &DuplicatesCommand{Since: "90d", Similarity: 0.8, Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+
+	assert.Contains(t, output, "Identical content (1 cluster)")
+	assert.Contains(t, output, `2 × abc123… "Understanding SQLite WAL mode"`)
+	assert.Contains(t, output, "Same URL (1 cluster)")
+	assert.Contains(t, output, "2 × go.dev/doc")
+	assert.Contains(t, output, "Similar titles (1 cluster)")
+	assert.Contains(t, output, `2 × "Go 1.23 released with iterators"`)
+	assert.NotContains(t, output, "Something else entirely")
+}
+
+func TestDuplicates_JSON(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Now()
+	seedDuplicateEvents(t, store, now)
+
+	cmd := &DuplicatesCommand{Since: "90d", Similarity: 0.8, Limit: 20, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+
+	var out []dupClusterJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out, 3)
+	assert.Equal(t, []string{dupContent, dupURL, dupTitle}, []string{out[0].Kind, out[1].Kind, out[2].Kind})
+	assert.Equal(t, "https://news.example.com/a/1", out[2].Events[0].URL, "oldest first")
+}
+
+func TestDuplicates_NoneAndValidation(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &DuplicatesCommand{Since: "90d", Similarity: 0.8, Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, time.Now()))
+	})
+	assert.Equal(t, "No duplicates found (since 90d)\n", output)
+
+	cmd.Similarity = 1.5
+	assert.Error(t, cmd.executeWithStore(store, time.Now()))
+}
+
+func TestNormalizeDupURL(t *testing.T) {
+	assert.Equal(t, "go.dev/doc", normalizeDupURL("https://www.Go.dev/doc/#top"))
+	assert.Equal(t, "go.dev/doc?q=1", normalizeDupURL("http://go.dev/doc?utm_medium=x&q=1&fbclid=y"))
+	assert.Equal(t, "not a url", normalizeDupURL("not a url"))
+
+	for _, variant := range []string{
+		"https://m.example.com/story",
+		"https://mobile.example.com/story/",
+		"https://amp.example.com/story",
+		"https://example.com/story/amp",
+		"https://example.com/story?amp=1",
+	} {
+		assert.Equal(t, "example.com/story", normalizeDupURL(variant), variant)
+	}
+	assert.Equal(t, "m.com/story", normalizeDupURL("https://m.com/story"), "a bare domain keeps its name")
+}
+
+func TestJaccard(t *testing.T) {
+	a := titleTokens("Go 1.23 released")
+	b := titleTokens("go 1.23 RELEASED!")
+	assert.Equal(t, 1.0, jaccard(a, b))
+	assert.InDelta(t, 0.5, jaccard(titleTokens("a b c"), titleTokens("b c d")), 0.001)
+	assert.Equal(t, 0.0, jaccard(nil, nil))
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/embeddings"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// embedJSON is the JSON output structure for the embed command.
+type embedJSON struct {
+	Model    string `json:"model"`
+	Embedded int    `json:"embedded"`
+	Skipped  int    `json:"skipped"`
+}
+
+// Execute implements the go-flags Commander interface for EmbedCommand.
+func (c *EmbedCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, loadConfig(c.globals).Embeddings)
+}
+
+// executeWithStore embeds the pending events in store (used by tests).
+func (c *EmbedCommand) executeWithStore(store *storage.SQLiteStore, cfg config.EmbeddingsConfig) error {
+	if c.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	embedder := c.embedder
+	if embedder == nil {
+		var err error
+		if embedder, err = newEmbedder(cfg); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	events, err := store.EventsWithoutEmbedding(ctx, embedder.Model(), cfg.ContentOnly, c.Limit)
+	if err != nil {
+		return err
+	}
+	bar := newProgress(c.globals, "Embedding", len(events))
+	embedded, skipped, err := embedEvents(ctx, store, embedder, events, cfg.BatchSize, bar.Add)
+	bar.Finish()
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(embedJSON{Model: embedder.Model(), Embedded: embedded, Skipped: skipped})
+	}
+	if len(events) == 0 {
+		infof(c.globals, "No events need an embedding\n")
+		return nil
+	}
+	infof(c.globals, "Embedded %d events with %s", embedded, embedder.Model())
+	if skipped > 0 {
+		infof(c.globals, " (%d skipped: no text)", skipped)
+	}
+	infof(c.globals, "\n")
+	return nil
+}
+
+// newEmbedder returns the embeddings.provider embedder, if embeddings are
+// enabled.
+func newEmbedder(cfg config.EmbeddingsConfig) (embeddings.Embedder, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("embeddings are disabled (set embeddings.enabled in the config)")
+	}
+	return embeddings.New(cfg)
+}
+
+// embedQuery embeds query with embedder, or when it is nil one made from
+// cfg, and returns the embedding and the model that made it.
+func embedQuery(ctx context.Context, embedder embeddings.Embedder, cfg config.EmbeddingsConfig, query string) (string, []float32, error) {
+	if embedder == nil {
+		var err error
+		if embedder, err = newEmbedder(cfg); err != nil {
+			return "", nil, err
+		}
+	}
+	vectors, err := embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return "", nil, fmt.Errorf("embedding the query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return "", nil, fmt.Errorf("embedding the query: got %d embeddings", len(vectors))
+	}
+	return embedder.Model(), vectors[0], nil
+}
+
+// embedEvents embeds and stores the text of each of events (see
+// storage.EmbeddingText), batchSize per request, reporting each event done
+// to progress. Events without text are skipped. Embeddings are stored as
+// each batch returns, so an interrupted run keeps what it made.
+func embedEvents(ctx context.Context, store *storage.SQLiteStore, embedder embeddings.Embedder, events []storage.Event, batchSize int, progress func(int)) (embedded, skipped int, err error) {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	for len(events) > 0 {
+		batch := events[:min(len(events), batchSize)]
+		events = events[len(batch):]
+
+		var ids, texts []string
+		for _, e := range batch {
+			text, err := store.EmbeddingText(ctx, e.ID)
+			if err != nil {
+				return embedded, skipped, err
+			}
+			if text == "" {
+				skipped++
+				continue
+			}
+			ids = append(ids, e.ID)
+			texts = append(texts, text)
+		}
+		if len(texts) > 0 {
+			vectors, err := embedder.Embed(ctx, texts)
+			if err != nil {
+				return embedded, skipped, fmt.Errorf("embedding %d events: %w", len(texts), err)
+			}
+			if len(vectors) != len(texts) {
+				return embedded, skipped, fmt.Errorf("embedding %d events: got %d embeddings", len(texts), len(vectors))
+			}
+			for i, id := range ids {
+				if err := store.SetEmbedding(ctx, id, embedder.Model(), vectors[i]); err != nil {
+					return embedded, skipped, err
+				}
+				embedded++
+			}
+		}
+		progress(len(batch))
+	}
+	return embedded, skipped, nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// topicEmbedder embeds text as counts of words about three topics: vector
+// databases, programming languages, and news.
+type topicEmbedder struct {
+	calls int
+}
+
+func (e *topicEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	topics := map[string]int{
+		"vector": 0, "lancedb": 0, "chromadb": 0, "databases": 0,
+		"go": 1, "python": 1, "programming": 1,
+		"news": 2, "hacker": 2,
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, 3)
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			if t, ok := topics[w]; ok {
+				v[t]++
+			}
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (e *topicEmbedder) Model() string { return "fake:topics" }
+
+func TestEmbed_EmbedsPendingEvents(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	cfg := config.DefaultConfig().Embeddings
+	cfg.BatchSize = 2
+
+	embedder := &topicEmbedder{}
+	cmd := &EmbedCommand{globals: &GlobalFlags{}, embedder: embedder}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	assert.Contains(t, output, "Embedded 5 events with fake:topics")
+	assert.Equal(t, 3, embedder.calls, "two events per request")
+
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	assert.Contains(t, output, "No events need an embedding")
+
+	// With content_only, events without a body are left alone.
+	store = setupSearchStore(t)
+	seedSearchEvents(t, store)
+	require.NoError(t, store.AddEventWithContent(context.Background(),
+		&storage.Event{URL: "https://example.com/notes", Title: "Notes", Source: "manual"}, "Vector databases compared."))
+	cfg.ContentOnly = true
+	cmd = &EmbedCommand{globals: &GlobalFlags{}, embedder: &topicEmbedder{}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	assert.Contains(t, output, "Embedded 1 events")
+}
+
+func TestEmbed_Disabled(t *testing.T) {
+	store := setupSearchStore(t)
+	cmd := &EmbedCommand{globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, config.DefaultConfig().Embeddings)
+	assert.EqualError(t, err, "embeddings are disabled (set embeddings.enabled in the config)")
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// excludeRuleJSON is one rule in exclude list's JSON output.
+type excludeRuleJSON struct {
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Reason  string `json:"reason,omitempty"`
+	Default bool   `json:"default"`
+}
+
+// excludeTestJSON is the JSON output structure for exclude test.
+type excludeTestJSON struct {
+	URL      string `json:"url"`
+	Domain   string `json:"domain"`
+	Excluded bool   `json:"excluded"`
+	Rule     string `json:"rule,omitempty"`
+}
+
+// exclusionType is the rule type selected by --regex.
+func exclusionType(regex bool) string {
+	if regex {
+		return storage.ExclusionRegex
+	}
+	return storage.ExclusionDomain
+}
+
+// Execute implements the go-flags Commander interface for ExcludeAddCommand.
+func (c *ExcludeAddCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore adds the rule to store (used by tests).
+func (c *ExcludeAddCommand) executeWithStore(store *storage.SQLiteStore) error {
+	rule := storage.Exclusion{Type: exclusionType(c.Regex), Value: c.Args.Rule, Reason: c.Reason}
+	if err := store.AddExclusion(context.Background(), rule); err != nil {
+		return err
+	}
+	infof(c.globals, "Excluding %s %s\n", rule.Type, strings.ToLower(strings.TrimSpace(c.Args.Rule)))
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for ExcludeRemoveCommand.
+func (c *ExcludeRemoveCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore removes the rule from store (used by tests).
+func (c *ExcludeRemoveCommand) executeWithStore(store *storage.SQLiteStore) error {
+	ruleType := exclusionType(c.Regex)
+	if err := store.RemoveExclusion(context.Background(), ruleType, c.Args.Rule); err != nil {
+		return err
+	}
+	infof(c.globals, "Removed %s rule %s\n", ruleType, c.Args.Rule)
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for ExcludeListCommand.
+func (c *ExcludeListCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore lists the rules in store (used by tests).
+func (c *ExcludeListCommand) executeWithStore(store *storage.SQLiteStore) error {
+	rules, err := store.Exclusions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]excludeRuleJSON, len(rules))
+		for i, r := range rules {
+			out[i] = excludeRuleJSON{Type: r.Type, Value: r.Value, Reason: r.Reason, Default: r.Default}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(rules) == 0 {
+		fmt.Println("No exclusion rules.")
+		return nil
+	}
+	fmt.Printf("%-7s %-8s %-32s %s\n", "TYPE", "ORIGIN", "RULE", "REASON")
+	for _, r := range rules {
+		origin := "user"
+		if r.Default {
+			origin = "default"
+		}
+		fmt.Printf("%-7s %-8s %-32s %s\n", r.Type, origin, r.Value, r.Reason)
+	}
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for ExcludeTestCommand.
+func (c *ExcludeTestCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore checks the URL against store's rules (used by tests).
+// An excluded URL is reported as an ErrExcluded error, so scripts can
+// branch on the exit code.
+func (c *ExcludeTestCommand) executeWithStore(store *storage.SQLiteStore) error {
+	parsed, err := url.ParseRequestURI(c.Args.URL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid URL: %s", c.Args.URL)
+	}
+	domain := parsed.Hostname()
+	rule, excluded := store.ExclusionRule(domain)
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(excludeTestJSON{URL: c.Args.URL, Domain: domain, Excluded: excluded, Rule: rule}); err != nil {
+			return err
+		}
+	} else if !excluded {
+		fmt.Printf("%s is not excluded\n", domain)
+	}
+	if excluded {
+		return fmt.Errorf("domain %q is %w by rule %q", domain, ErrExcluded, rule)
+	}
+	return nil
+}
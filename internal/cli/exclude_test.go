@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclude_AddTestRemove(t *testing.T) {
+	store := setupSearchStore(t)
+	globals := &GlobalFlags{}
+
+	add := &ExcludeAddCommand{Reason: "work", globals: globals}
+	add.Args.Rule = "Intranet.Example.com"
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, add.executeWithStore(store))
+	})
+	assert.Equal(t, "Excluding domain intranet.example.com\n", output)
+
+	test := &ExcludeTestCommand{globals: globals}
+	test.Args.URL = "https://intranet.example.com/payroll"
+	err := test.executeWithStore(store)
+	require.ErrorIs(t, err, ErrExcluded)
+	assert.Contains(t, err.Error(), `by rule "intranet.example.com"`)
+
+	list := &ExcludeListCommand{globals: globals}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, list.executeWithStore(store))
+	})
+	assert.Contains(t, output, "TYPE    ORIGIN   RULE")
+	assert.Regexp(t, `domain\s+user\s+intranet\.example\.com\s+work`, output)
+	assert.Regexp(t, `domain\s+default\s+chase\.com`, output)
+
+	remove := &ExcludeRemoveCommand{globals: globals}
+	remove.Args.Rule = "intranet.example.com"
+	captureSearchOutput(t, func() {
+		require.NoError(t, remove.executeWithStore(store))
+	})
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, test.executeWithStore(store))
+	})
+	assert.Equal(t, "intranet.example.com is not excluded\n", output)
+
+	assert.ErrorIs(t, remove.executeWithStore(store), storage.ErrExclusionNotFound)
+}
+
+func TestExclude_RegexJSON(t *testing.T) {
+	store := setupSearchStore(t)
+	globals := &GlobalFlags{JSON: true}
+
+	add := &ExcludeAddCommand{Regex: true, globals: &GlobalFlags{Quiet: true}}
+	add.Args.Rule = `\.internal$`
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, add.executeWithStore(store))
+	})
+	assert.Empty(t, output)
+
+	test := &ExcludeTestCommand{globals: globals}
+	test.Args.URL = "http://wiki.internal/start"
+	output = captureSearchOutput(t, func() {
+		assert.ErrorIs(t, test.executeWithStore(store), ErrExcluded)
+	})
+	var result excludeTestJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, excludeTestJSON{URL: "http://wiki.internal/start", Domain: "wiki.internal", Excluded: true, Rule: `\.internal$`}, result)
+
+	test.Args.URL = "not a url"
+	assert.EqualError(t, test.executeWithStore(store), "invalid URL: not a url")
+
+	list := &ExcludeListCommand{globals: globals}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, list.executeWithStore(store))
+	})
+	var rules []excludeRuleJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &rules))
+	assert.Equal(t, excludeRuleJSON{Type: "regex", Value: `\.internal$`}, rules[len(rules)-1])
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	goflags "github.com/jessevdk/go-flags"
+)
+
+// Process exit codes, so scripts can branch on outcomes without parsing
+// output.
+const (
+	ExitOK       = 0 // success
+	ExitError    = 1 // any other failure
+	ExitNotFound = 2 // the requested event does not exist
+	ExitExcluded = 3 // the URL's domain is blocked by exclusion rules
+)
+
+// Sentinel errors mapped to dedicated exit codes. Wrap them with %w so the
+// message stays readable: fmt.Errorf("event %w: %s", ErrNotFound, id).
+var (
+	ErrNotFound = errors.New("not found")
+	ErrExcluded = errors.New("excluded")
+)
+
+// ExitCode maps an error returned by Run or RunWithArgs to a process exit
+// code.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, ErrExcluded):
+		return ExitExcluded
+	default:
+		return ExitError
+	}
+}
+
+// errorJSON is the body of the object written to stderr when a command
+// fails under --json.
+type errorJSON struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// errorCode classifies err for machine consumers.
+func errorCode(err error) string {
+	var flagsErr *goflags.Error
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrExcluded):
+		return "excluded"
+	case errors.As(err, &flagsErr):
+		return "usage"
+	default:
+		return "error"
+	}
+}
+
+// printError reports a command failure to w: as a {"error": {...}} object
+// when asJSON is set, otherwise as the plain error message.
+func printError(w io.Writer, err error, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintln(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]errorJSON{ //nolint:errcheck
+		"error": {
+			Code:     errorCode(err),
+			Message:  err.Error(),
+			ExitCode: ExitCode(err),
+		},
+	})
+}
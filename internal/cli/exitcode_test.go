@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitOK, ExitCode(nil))
+	assert.Equal(t, ExitError, ExitCode(errors.New("boom")))
+	assert.Equal(t, ExitNotFound, ExitCode(fmt.Errorf("event %w: CHR-x", ErrNotFound)))
+	assert.Equal(t, ExitExcluded, ExitCode(fmt.Errorf("outer: %w", fmt.Errorf("domain %w", ErrExcluded))))
+}
+
+func TestExitCode_OpenNotFound(t *testing.T) {
+	dbPath, _ := setupOpenTestDB(t)
+
+	_, err := captureOpenOutput(t, []string{"open", "--id", "CHR-nonexistent", "--db-path", dbPath})
+	require.Error(t, err)
+	assert.Equal(t, "event not found: CHR-nonexistent", err.Error())
+	assert.Equal(t, ExitNotFound, ExitCode(err))
+}
+
+func TestExitCode_AddExcluded(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &AddCommand{URL: "https://chase.com/login", Title: "Chase", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store)
+	require.Error(t, err)
+	assert.Equal(t, ExitExcluded, ExitCode(err))
+}
+
+func TestQuiet_AddPrintsOnlyID(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &AddCommand{URL: "https://example.com/q", Title: "Quiet", globals: &GlobalFlags{Quiet: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+
+	assert.Regexp(t, `^CHR-\S+\n$`, output)
+}
+
+func TestQuiet_SearchOmitsHeader(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{Quiet: true}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB"}))
+	})
+	assert.Contains(t, output, "LanceDB Getting Started")
+	assert.NotContains(t, output, "Found")
+
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"nonexistentterm12345"}))
+	})
+	assert.Empty(t, output)
+}
+
+func TestQuietFlag(t *testing.T) {
+	parser, globals, _ := buildParser("test")
+	_, err := parser.ParseArgs([]string{"--quiet", "version"})
+	require.NoError(t, err)
+	assert.True(t, globals.Quiet)
+}
+
+func TestPrintError_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	printError(&buf, errors.New("boom"), false)
+	assert.Equal(t, "boom\n", buf.String())
+}
+
+func TestPrintError_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	printError(&buf, fmt.Errorf("event %w: CHR-x", ErrNotFound), true)
+
+	var out struct {
+		Error errorJSON `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, "not_found", out.Error.Code)
+	assert.Equal(t, "event not found: CHR-x", out.Error.Message)
+	assert.Equal(t, ExitNotFound, out.Error.ExitCode)
+}
+
+func TestPrintError_UsageCode(t *testing.T) {
+	parser, _, _ := buildParser("test")
+	_, err := parser.ParseArgs([]string{"--json", "no-such-command"})
+	require.Error(t, err)
+	assert.Equal(t, "usage", errorCode(err))
+}
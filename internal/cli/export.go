@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// exportPageSize is how many events export reads from the store at a time.
+const exportPageSize = 1000
+
+// exportJSON is the JSON output structure for the export command.
+type exportJSON struct {
+	Format string `json:"format"`
+	Path   string `json:"path"`
+	Events int    `json:"events"`
+}
+
+// exportItem is one event handed to an exporter, with its body when
+// --include-body is set and one was captured.
+type exportItem struct {
+	Event storage.Event
+	Body  string
+}
+
+// Execute implements the go-flags Commander interface for ExportCommand.
+func (c *ExportCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore exports against a provided store (used by tests).
+func (c *ExportCommand) executeWithStore(store *storage.SQLiteStore, now time.Time) error {
+	if c.Format == "" {
+		return fmt.Errorf("--format is required")
+	}
+	if c.Out == "" {
+		return fmt.Errorf("--out is required for --format %s", c.Format)
+	}
+
+	if err := applyDayFilter(c.Today, c.On, &c.Since, &c.Until, now); err != nil {
+		return err
+	}
+	since, until, err := resolveTimeRange(c.Since, c.Until, now)
+	if err != nil {
+		return err
+	}
+	q := storage.SearchQuery{Since: since, Until: until, Domain: string(c.Domain), Sort: storage.SortOldest}
+	switch c.Format {
+	case "obsidian", "org", "jsonl", "csv", "md", "parquet":
+	default:
+		return fmt.Errorf("unsupported export format %q", c.Format)
+	}
+
+	total, err := store.CountEvents(context.Background(), q)
+	if err != nil {
+		return fmt.Errorf("count events: %w", err)
+	}
+	// With --out -, stdout carries the export, so progress goes to stderr.
+	prog := newProgress(c.globals, "Exporting", int(total))
+	each := c.eachItem(store, q, prog)
+
+	var n int
+	switch c.Format {
+	case "obsidian":
+		n, err = exportObsidian(c.Out, each)
+	case "org":
+		n, err = c.exportFile(func(w io.Writer) (int, error) {
+			return exportOrg(w, each)
+		})
+	case "jsonl":
+		n, err = c.exportFile(func(w io.Writer) (int, error) {
+			return exportJSONL(w, each)
+		})
+	case "csv":
+		n, err = c.exportFile(func(w io.Writer) (int, error) {
+			return exportCSV(w, each, c.IncludeBody)
+		})
+	case "md":
+		n, err = c.exportFile(func(w io.Writer) (int, error) {
+			return exportMarkdown(w, each)
+		})
+	case "parquet":
+		n, err = c.exportFile(func(w io.Writer) (int, error) {
+			return exportParquet(w, each, c.IncludeBody)
+		})
+	}
+	prog.Finish()
+	if err != nil {
+		return err
+	}
+
+	if c.Out == "-" {
+		return nil
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(exportJSON{Format: c.Format, Path: c.Out, Events: n})
+	}
+	infof(c.globals, "Exported %d events to %s\n", n, c.Out)
+	return nil
+}
+
+// exportFile runs a single-file exporter against --out, which may be "-"
+// for stdout.
+func (c *ExportCommand) exportFile(export func(io.Writer) (int, error)) (int, error) {
+	if c.Out == "-" {
+		return export(os.Stdout)
+	}
+	f, err := os.Create(c.Out)
+	if err != nil {
+		return 0, fmt.Errorf("create export file: %w", err)
+	}
+	n, err := export(f)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("write export file: %w", cerr)
+	}
+	return n, err
+}
+
+// eachItem returns an iterator over the events matching q, oldest first,
+// paging through the store so large histories are never loaded at once,
+// and counting each event exported on prog. Iteration stops early if fn
+// returns an error, which is passed through.
+func (c *ExportCommand) eachItem(store *storage.SQLiteStore, q storage.SearchQuery, prog *progress) func(fn func(exportItem) error) error {
+	return func(fn func(exportItem) error) error {
+		ctx := context.Background()
+		emit := func(item exportItem) error {
+			if err := fn(item); err != nil {
+				return err
+			}
+			prog.Add(1)
+			return nil
+		}
+		q.Limit = exportPageSize
+		for q.Offset = 0; ; q.Offset += exportPageSize {
+			events, err := store.SearchEvents(ctx, q)
+			if err != nil {
+				return fmt.Errorf("search failed: %w", err)
+			}
+			if c.IncludeBody {
+				err = eachWithBody(ctx, store, events, func(e *storage.Event, body string) error {
+					return emit(exportItem{Event: *e, Body: body})
+				})
+			} else {
+				for _, e := range events {
+					if err = emit(exportItem{Event: e}); err != nil {
+						break
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if len(events) < exportPageSize {
+				return nil
+			}
+		}
+	}
+}
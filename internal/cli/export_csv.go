@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// csvHeader is the header row of a CSV export. The body column is
+// appended when --include-body is set.
+var csvHeader = []string{"id", "timestamp", "url", "title", "domain", "source", "browser", "lang", "canonical_url", "content_hash"}
+
+// exportCSV writes events as RFC 4180 CSV with a header row.
+func exportCSV(w io.Writer, each func(func(exportItem) error) error, includeBody bool) (int, error) {
+	cw := csv.NewWriter(w)
+	header := csvHeader
+	if includeBody {
+		header = append(header[:len(header):len(header)], "body")
+	}
+	if err := cw.Write(header); err != nil {
+		return 0, fmt.Errorf("write csv export: %w", err)
+	}
+
+	n := 0
+	err := each(func(item exportItem) error {
+		e := item.Event
+		row := []string{
+			e.ID, e.Timestamp.UTC().Format(time.RFC3339), e.URL, e.Title, e.Domain,
+			e.Source, e.Browser, e.Lang, e.CanonicalURL, e.ContentHash,
+		}
+		if includeBody {
+			row = append(row, item.Body)
+		}
+		n++
+		return cw.Write(row)
+	})
+	if err != nil {
+		return n, err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return n, fmt.Errorf("write csv export: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonlRecord is one line of a JSONL export. Its url, title, timestamp,
+// source, browser, canonical_url and body fields are the ones chronicle
+// capture reads, so an export can be captured into another database.
+type jsonlRecord struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Domain       string `json:"domain"`
+	Timestamp    string `json:"timestamp"`
+	Source       string `json:"source"`
+	Browser      string `json:"browser,omitempty"`
+	Lang         string `json:"lang,omitempty"`
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	ContentHash  string `json:"content_hash,omitempty"`
+	Body         string `json:"body,omitempty"`
+}
+
+// exportJSONL writes one JSON object per event per line.
+func exportJSONL(w io.Writer, each func(func(exportItem) error) error) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+
+	n := 0
+	err := each(func(item exportItem) error {
+		e := item.Event
+		if err := enc.Encode(jsonlRecord{
+			ID:           e.ID,
+			URL:          e.URL,
+			Title:        e.Title,
+			Domain:       e.Domain,
+			Timestamp:    e.Timestamp.UTC().Format(time.RFC3339),
+			Source:       e.Source,
+			Browser:      e.Browser,
+			Lang:         e.Lang,
+			CanonicalURL: e.CanonicalURL,
+			ContentHash:  e.ContentHash,
+			Body:         item.Body,
+		}); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := bw.Flush(); err != nil {
+		return n, fmt.Errorf("write jsonl export: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// exportMarkdown writes events as one markdown document: a heading per
+// day, and under it a list item per event linking its URL, followed by
+// its body as a quote when exported.
+func exportMarkdown(w io.Writer, each func(func(exportItem) error) error) (int, error) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Chronicle export")
+
+	n := 0
+	lastDay := ""
+	err := each(func(item exportItem) error {
+		e := item.Event
+		at := e.Timestamp.Local()
+		if day := at.Format("2006-01-02"); day != lastDay {
+			fmt.Fprintf(bw, "\n## %s\n\n", at.Format("2006-01-02 Monday"))
+			lastDay = day
+		}
+
+		title := e.Title
+		if title == "" {
+			title = e.URL
+		}
+		meta := at.Format("15:04")
+		if e.Domain != "" {
+			meta = e.Domain + " · " + meta
+		}
+		if e.Source != "" {
+			meta += " · " + e.Source
+		}
+		fmt.Fprintf(bw, "- [%s](%s) — %s\n", mdLinkText(title), mdLinkTarget(e.URL), meta)
+
+		if body := strings.TrimSpace(item.Body); body != "" {
+			fmt.Fprintln(bw)
+			for _, line := range strings.Split(body, "\n") {
+				fmt.Fprintln(bw, strings.TrimRight("  > "+line, " "))
+			}
+			fmt.Fprintln(bw)
+		}
+
+		n++
+		return bw.Flush()
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := bw.Flush(); err != nil {
+		return n, fmt.Errorf("write markdown export: %w", err)
+	}
+	return n, nil
+}
+
+// mdLinkText makes s safe as markdown link text, where brackets would end
+// the link and a newline would break the list item.
+func mdLinkText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "\n", " ").Replace(s)
+}
+
+// mdLinkTarget makes a URL safe as a markdown link destination, where
+// spaces and parentheses would end it early.
+func mdLinkTarget(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
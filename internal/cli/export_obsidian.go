@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// obsidianNote is the YAML frontmatter of an exported event note.
+type obsidianNote struct {
+	Title    string   `yaml:"title"`
+	Aliases  []string `yaml:"aliases,omitempty"`
+	URL      string   `yaml:"url"`
+	Domain   string   `yaml:"domain,omitempty"`
+	Source   string   `yaml:"source,omitempty"`
+	Browser  string   `yaml:"browser,omitempty"`
+	Captured string   `yaml:"captured"`
+	ID       string   `yaml:"chronicle_id"`
+	Tags     []string `yaml:"tags"`
+}
+
+// obsidianDaily is the YAML frontmatter of a daily index note.
+type obsidianDaily struct {
+	Date string   `yaml:"date"`
+	Tags []string `yaml:"tags"`
+}
+
+// obsidianLink is a daily note's entry for one event.
+type obsidianLink struct {
+	At     time.Time
+	ID     string
+	Title  string
+	Domain string
+}
+
+// exportObsidian writes a vault folder under dir: events/<id>.md holds one
+// note per event and daily/<date>.md links each day's events, so every
+// event note backlinks to its day. Re-exporting overwrites the same files.
+func exportObsidian(dir string, each func(func(exportItem) error) error) (int, error) {
+	eventsDir := filepath.Join(dir, "events")
+	dailyDir := filepath.Join(dir, "daily")
+	for _, d := range []string{eventsDir, dailyDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return 0, fmt.Errorf("create export directory: %w", err)
+		}
+	}
+
+	days := map[string][]obsidianLink{}
+	n := 0
+	err := each(func(item exportItem) error {
+		e := item.Event
+		day := e.Timestamp.Local().Format("2006-01-02")
+		title := e.Title
+		if title == "" {
+			title = e.URL
+		}
+
+		var b strings.Builder
+		note := obsidianNote{
+			Title:    title,
+			URL:      e.URL,
+			Domain:   e.Domain,
+			Source:   e.Source,
+			Browser:  e.Browser,
+			Captured: e.Timestamp.Local().Format(time.RFC3339),
+			ID:       e.ID,
+			Tags:     obsidianTags(e.Domain, e.Source),
+		}
+		if e.Title != "" {
+			note.Aliases = []string{e.Title}
+		}
+		if err := writeFrontmatter(&b, note); err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "# %s\n\n", title)
+		fmt.Fprintf(&b, "<%s>\n\n", e.URL)
+		fmt.Fprintf(&b, "Captured [[%s]] at %s", day, e.Timestamp.Local().Format("15:04"))
+		if e.Domain != "" {
+			fmt.Fprintf(&b, " from %s", e.Domain)
+		}
+		fmt.Fprintln(&b)
+		if body := strings.TrimSpace(item.Body); body != "" {
+			fmt.Fprintf(&b, "\n## Content\n\n%s\n", body)
+		}
+
+		path := filepath.Join(eventsDir, e.ID+".md")
+		if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("write note: %w", err)
+		}
+
+		days[day] = append(days[day], obsidianLink{At: e.Timestamp, ID: e.ID, Title: title, Domain: e.Domain})
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	for day, links := range days {
+		if err := writeObsidianDaily(filepath.Join(dailyDir, day+".md"), day, links); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// writeObsidianDaily writes the index note for one day, listing its events
+// in time order.
+func writeObsidianDaily(path, day string, links []obsidianLink) error {
+	sort.SliceStable(links, func(a, b int) bool { return links[a].At.Before(links[b].At) })
+
+	var b strings.Builder
+	if err := writeFrontmatter(&b, obsidianDaily{Date: day, Tags: []string{"chronicle", "chronicle/daily"}}); err != nil {
+		return err
+	}
+	fmt.Fprintf(&b, "# %s\n\n", day)
+	for _, l := range links {
+		fmt.Fprintf(&b, "- %s [[%s|%s]]", l.At.Local().Format("15:04"), l.ID, wikiLinkText(l.Title))
+		if l.Domain != "" {
+			fmt.Fprintf(&b, " — %s", l.Domain)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("write daily note: %w", err)
+	}
+	return nil
+}
+
+// writeFrontmatter writes v as a YAML frontmatter block.
+func writeFrontmatter(b *strings.Builder, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode frontmatter: %w", err)
+	}
+	b.WriteString("---\n")
+	b.Write(data)
+	b.WriteString("---\n\n")
+	return nil
+}
+
+// obsidianTags builds an event's tags. Obsidian tags cannot contain dots,
+// so domains become e.g. site/github-com.
+func obsidianTags(domain, source string) []string {
+	tags := []string{"chronicle"}
+	if domain != "" {
+		tags = append(tags, "site/"+strings.ReplaceAll(domain, ".", "-"))
+	}
+	if source != "" {
+		tags = append(tags, "source/"+source)
+	}
+	return tags
+}
+
+// wikiLinkText makes s safe as the display text of a [[target|text]] link.
+func wikiLinkText(s string) string {
+	return strings.NewReplacer("|", "-", "[", "(", "]", ")", "\n", " ").Replace(s)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// exportOrg writes events as an org-mode outline: a top-level heading per
+// day, and under it one heading per event linking its URL, tagged with its
+// site and source. Metadata goes in a PROPERTIES drawer and the body, when
+// exported, in a CONTENT drawer.
+func exportOrg(w io.Writer, each func(func(exportItem) error) error) (int, error) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#+TITLE: Chronicle export")
+	fmt.Fprintln(bw, "#+STARTUP: overview")
+
+	n := 0
+	lastDay := ""
+	err := each(func(item exportItem) error {
+		e := item.Event
+		at := e.Timestamp.Local()
+		if day := at.Format("2006-01-02"); day != lastDay {
+			fmt.Fprintf(bw, "\n* %s\n", at.Format("2006-01-02 Monday"))
+			lastDay = day
+		}
+
+		title := e.Title
+		if title == "" {
+			title = e.URL
+		}
+		fmt.Fprintf(bw, "** [[%s][%s]]", orgLinkTarget(e.URL), orgLinkText(title))
+		if tags := orgTags(e.Domain, e.Source); tags != "" {
+			fmt.Fprintf(bw, " %s", tags)
+		}
+		fmt.Fprintln(bw)
+
+		fmt.Fprintln(bw, ":PROPERTIES:")
+		fmt.Fprintf(bw, ":ID:       %s\n", e.ID)
+		fmt.Fprintf(bw, ":URL:      %s\n", e.URL)
+		fmt.Fprintf(bw, ":CAPTURED: %s\n", at.Format("[2006-01-02 Mon 15:04]"))
+		if e.Domain != "" {
+			fmt.Fprintf(bw, ":DOMAIN:   %s\n", e.Domain)
+		}
+		if e.Source != "" {
+			fmt.Fprintf(bw, ":SOURCE:   %s\n", e.Source)
+		}
+		if e.Browser != "" {
+			fmt.Fprintf(bw, ":BROWSER:  %s\n", e.Browser)
+		}
+		fmt.Fprintln(bw, ":END:")
+
+		if body := strings.TrimSpace(item.Body); body != "" {
+			fmt.Fprintln(bw, ":CONTENT:")
+			for _, line := range strings.Split(body, "\n") {
+				fmt.Fprintln(bw, orgEscapeLine(line))
+			}
+			fmt.Fprintln(bw, ":END:")
+		}
+
+		n++
+		return bw.Flush()
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := bw.Flush(); err != nil {
+		return n, fmt.Errorf("write org export: %w", err)
+	}
+	return n, nil
+}
+
+// orgTags renders an event's org tags, e.g. ":github_com:extension:". Org
+// tags allow only letters, digits, _, @, # and %, so anything else becomes _.
+func orgTags(domain, source string) string {
+	var tags []string
+	for _, t := range []string{domain, source} {
+		if t == "" {
+			continue
+		}
+		tags = append(tags, strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("_@#%", r):
+				return r
+			}
+			return '_'
+		}, t))
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return ":" + strings.Join(tags, ":") + ":"
+}
+
+// orgLinkTarget escapes a URL for use inside [[...]], where brackets and
+// backslashes would end or confuse the link.
+func orgLinkTarget(u string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(u)
+}
+
+// orgLinkText makes s safe as org link description text.
+func orgLinkText(s string) string {
+	return strings.NewReplacer("[", "(", "]", ")", "\n", " ").Replace(s)
+}
+
+// orgEscapeLine keeps a body line from being read as org structure: lines
+// starting with "*" would become headings and ":END:" would close the
+// drawer early, so they are prefixed with a comma as org does in blocks.
+func orgEscapeLine(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(line, "*") || strings.HasPrefix(trimmed, "#+") || strings.EqualFold(trimmed, ":END:") {
+		return "," + line
+	}
+	return line
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/runnerr0/chronicle/internal/parquet"
+)
+
+// parquetColumns is the schema of a Parquet export, one row per event. The
+// body column is appended when --include-body is set.
+var parquetColumns = []parquet.Column{
+	{Name: "id", Type: parquet.String},
+	{Name: "ts", Type: parquet.Timestamp},
+	{Name: "url", Type: parquet.String},
+	{Name: "title", Type: parquet.String},
+	{Name: "domain", Type: parquet.String},
+	{Name: "source", Type: parquet.String},
+	{Name: "browser", Type: parquet.String},
+	{Name: "content_hash", Type: parquet.String},
+	{Name: "has_body", Type: parquet.Bool},
+	{Name: "has_embedding", Type: parquet.Bool},
+}
+
+// exportParquet writes events as a Parquet table for DuckDB, pandas and
+// other analytics tools. Missing strings are written as empty rather than
+// null.
+func exportParquet(w io.Writer, each func(func(exportItem) error) error, includeBody bool) (int, error) {
+	columns := parquetColumns
+	if includeBody {
+		columns = append(columns[:len(columns):len(columns)], parquet.Column{Name: "body", Type: parquet.String})
+	}
+	pw, err := parquet.NewWriter(w, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	err = each(func(item exportItem) error {
+		e := item.Event
+		row := []interface{}{
+			e.ID, e.Timestamp, e.URL, e.Title, e.Domain, e.Source, e.Browser,
+			e.ContentHash, e.HasBody, e.HasEmbed,
+		}
+		if includeBody {
+			row = append(row, item.Body)
+		}
+		n++
+		return pw.Write(row...)
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, pw.Close()
+}
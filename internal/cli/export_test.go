@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestExportObsidian(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://blog.example.com/a|b", Title: "Pipes | and [brackets]: a story", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "The body text."))
+
+	dir := t.TempDir()
+	cmd := &ExportCommand{Format: "obsidian", Out: dir, Since: "7d", IncludeBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Equal(t, fmt.Sprintf("Exported 4 events to %s\n", dir), output)
+
+	notes, err := filepath.Glob(filepath.Join(dir, "events", "*.md"))
+	require.NoError(t, err)
+	assert.Len(t, notes, 4)
+
+	data, err := os.ReadFile(filepath.Join(dir, "events", withBody.ID+".md"))
+	require.NoError(t, err)
+	note := string(data)
+	day := withBody.Timestamp.Local().Format("2006-01-02")
+	assert.Contains(t, note, "---\ntitle: 'Pipes | and [brackets]: a story'\n")
+	assert.Contains(t, note, "url: https://blog.example.com/a|b\n")
+	assert.Contains(t, note, "chronicle_id: "+withBody.ID+"\n")
+	assert.Contains(t, note, "tags:\n    - chronicle\n    - site/blog-example-com\n    - source/manual\n")
+	assert.Contains(t, note, "Captured [["+day+"]]")
+	assert.Contains(t, note, "## Content\n\nThe body text.\n")
+
+	data, err = os.ReadFile(filepath.Join(dir, "daily", day+".md"))
+	require.NoError(t, err)
+	daily := string(data)
+	assert.Contains(t, daily, "date: \""+day+"\"")
+	assert.Contains(t, daily, "[["+withBody.ID+"|Pipes - and (brackets): a story]] — blog.example.com")
+}
+
+func TestExportObsidian_WithoutBodiesAndJSON(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+
+	e := &storage.Event{URL: "https://example.com/", Title: "Example", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, e, "Secret body"))
+
+	dir := t.TempDir()
+	cmd := &ExportCommand{Format: "obsidian", Out: dir, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var res exportJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &res))
+	assert.Equal(t, exportJSON{Format: "obsidian", Path: dir, Events: 1}, res)
+
+	data, err := os.ReadFile(filepath.Join(dir, "events", e.ID+".md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Secret body")
+}
+
+func TestExport_PagesThroughAllEvents(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; i < exportPageSize+5; i++ {
+		e := &storage.Event{URL: fmt.Sprintf("https://example.com/%d", i), Title: "E", Source: "manual", Timestamp: now.Add(-time.Duration(i) * time.Minute)}
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+
+	cmd := &ExportCommand{Format: "obsidian", Out: t.TempDir(), globals: &GlobalFlags{}}
+	var seen []string
+	var progress bytes.Buffer
+	prog := startProgress(&progress, "Exporting", exportPageSize+5, false, fakeClock(time.Second))
+	err := cmd.eachItem(store, storage.SearchQuery{Sort: storage.SortOldest}, prog)(func(item exportItem) error {
+		seen = append(seen, item.Event.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, seen, exportPageSize+5)
+	unique := map[string]bool{}
+	for _, id := range seen {
+		unique[id] = true
+	}
+	assert.Len(t, unique, exportPageSize+5)
+	prog.Finish()
+	assert.Contains(t, progress.String(), fmt.Sprintf("Exporting: %d done", exportPageSize+5))
+}
+
+func TestExport_RequiresFormatAndOut(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	err := (&ExportCommand{globals: &GlobalFlags{}}).executeWithStore(store, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--format is required")
+
+	err = (&ExportCommand{Format: "obsidian", globals: &GlobalFlags{}}).executeWithStore(store, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--out is required")
+}
+
+func TestExportOrg(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://blog.example.com/post", Title: "A [bracketed] post", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "Intro\n* not a heading\n:END:\nOutro"))
+
+	out := filepath.Join(t.TempDir(), "history.org")
+	cmd := &ExportCommand{Format: "org", Out: out, Since: "7d", IncludeBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Equal(t, fmt.Sprintf("Exported 4 events to %s\n", out), output)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	org := string(data)
+
+	assert.True(t, strings.HasPrefix(org, "#+TITLE: Chronicle export\n"))
+	day := withBody.Timestamp.Local()
+	assert.Contains(t, org, "\n* "+day.Format("2006-01-02 Monday")+"\n")
+	assert.Contains(t, org, "** [[https://blog.example.com/post][A (bracketed) post]] :blog_example_com:manual:\n")
+	assert.Contains(t, org, ":ID:       "+withBody.ID+"\n")
+	assert.Contains(t, org, ":CAPTURED: "+day.Format("[2006-01-02 Mon 15:04]")+"\n")
+	assert.Contains(t, org, ":CONTENT:\nIntro\n,* not a heading\n,:END:\nOutro\n:END:\n")
+	assert.Contains(t, org, "** [[https://github.com/golang/go][Go repo]] :github_com:extension:\n")
+
+	// Day headings appear once each, oldest first.
+	assert.Equal(t, 1, strings.Count(org, "\n* "+day.Format("2006-01-02 Monday")+"\n"))
+	assert.Less(t, strings.Index(org, "Hacker News"), strings.Index(org, "Go repo"))
+}
+
+func TestExportOrg_Stdout(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	cmd := &ExportCommand{Format: "org", Out: "-", Since: "7d", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.True(t, strings.HasPrefix(output, "#+TITLE: Chronicle export\n"))
+	assert.NotContains(t, output, "Exported")
+}
+
+func TestExportParquet(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://blog.example.com/post", Title: "A post", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "parquet body text"))
+
+	out := filepath.Join(t.TempDir(), "history.parquet")
+	cmd := &ExportCommand{Format: "parquet", Out: out, Since: "7d", IncludeBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Equal(t, fmt.Sprintf("Exported 4 events to %s\n", out), output)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.True(t, len(data) > 8)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	// Values are stored PLAIN and uncompressed, so they appear verbatim.
+	for _, s := range []string{withBody.ID, "https://github.com/golang/go", "news.ycombinator.com", "parquet body text", "has_embedding", "body"} {
+		assert.Contains(t, string(data), s)
+	}
+	assert.NotContains(t, string(data), "old.example.com", "outside --since")
+}
+
+func TestExportJSONL_CapturesBack(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://blog.example.com/post?a=1&b=<2>", Title: "A post", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "jsonl body\ntext"))
+
+	out := filepath.Join(t.TempDir(), "history.jsonl")
+	cmd := &ExportCommand{Format: "jsonl", Out: out, Since: "7d", Domain: "blog.example.com", IncludeBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Equal(t, fmt.Sprintf("Exported 1 events to %s\n", out), output)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"url":"https://blog.example.com/post?a=1&b=<2>"`, "no HTML escaping")
+	var rec jsonlRecord
+	require.NoError(t, json.Unmarshal(data, &rec))
+	assert.Equal(t, withBody.ID, rec.ID)
+	assert.Equal(t, "jsonl body\ntext", rec.Body)
+	assert.Equal(t, withBody.Timestamp.UTC().Format(time.RFC3339), rec.Timestamp)
+
+	other, cleanupOther := testStore(t)
+	defer cleanupOther()
+	capture := &CaptureCommand{Source: "import", BatchSize: 10, globals: &GlobalFlags{Quiet: true}}
+	require.NoError(t, capture.executeWithStore(other, strings.NewReader(string(data))))
+	events, err := other.SearchEvents(ctx, storage.SearchQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "A post", events[0].Title)
+	assert.Equal(t, "manual", events[0].Source)
+	assert.True(t, events[0].Timestamp.Equal(withBody.Timestamp.Truncate(time.Second)))
+	content, err := other.GetContent(ctx, events[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "jsonl body\ntext", content.Body)
+}
+
+func TestExportCSV(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://blog.example.com/post", Title: `Quotes "and", commas`, Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "line one\nline two"))
+
+	cmd := &ExportCommand{Format: "csv", Out: "-", Since: "7d", IncludeBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	rows, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5, "header and four events")
+	assert.Equal(t, append(csvHeader[:len(csvHeader):len(csvHeader)], "body"), rows[0])
+	assert.Equal(t, "Hacker News", rows[1][3], "oldest first")
+	last := rows[4]
+	assert.Equal(t, withBody.ID, last[0])
+	assert.Equal(t, `Quotes "and", commas`, last[3])
+	assert.Equal(t, "line one\nline two", last[10])
+
+	cmd = &ExportCommand{Format: "csv", Out: "-", Since: "7d", globals: &GlobalFlags{}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.True(t, strings.HasPrefix(output, strings.Join(csvHeader, ",")+"\n"))
+	assert.NotContains(t, output, "line one")
+}
+
+func TestExportMarkdown(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	withBody := &storage.Event{URL: "https://en.wikipedia.org/wiki/Go_(game)", Title: "Go [game]", Source: "manual", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "An abstract strategy game.\n\nTwo players."))
+
+	out := filepath.Join(t.TempDir(), "history.md")
+	cmd := &ExportCommand{Format: "md", Out: out, Since: "7d", IncludeBody: true, globals: &GlobalFlags{}}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	md := string(data)
+
+	at := withBody.Timestamp.Local()
+	assert.True(t, strings.HasPrefix(md, "# Chronicle export\n"))
+	assert.Contains(t, md, "\n## "+at.Format("2006-01-02 Monday")+"\n")
+	assert.Contains(t, md, `- [Go \[game\]](https://en.wikipedia.org/wiki/Go_%28game%29) — en.wikipedia.org · `+at.Format("15:04")+" · manual\n")
+	assert.Contains(t, md, "\n  > An abstract strategy game.\n  >\n  > Two players.\n")
+	assert.Less(t, strings.Index(md, "Hacker News"), strings.Index(md, "Go repo"))
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/fabric"
+)
+
+// fabricSetupJSON is the JSON output structure for the fabric-setup command.
+type fabricSetupJSON struct {
+	Path       string `json:"path"`
+	Registered bool   `json:"registered"`
+}
+
+// Execute implements the go-flags Commander interface for FabricSetupCommand.
+func (c *FabricSetupCommand) Execute(args []string) error {
+	return c.executeWithConfig(loadConfig(c.globals))
+}
+
+// executeWithConfig writes the extension config using cfg to find fabric
+// (used by tests).
+func (c *FabricSetupCommand) executeWithConfig(cfg *config.Config) error {
+	exe := c.executable
+	if exe == "" {
+		var err error
+		if exe, err = os.Executable(); err != nil {
+			return fmt.Errorf("locating chronicle executable: %w", err)
+		}
+	}
+
+	extraArgs, err := c.forwardedArgs()
+	if err != nil {
+		return err
+	}
+	data := fabric.ExtensionConfig(exe, c.version, extraArgs)
+
+	if c.Print {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	path, err := fabric.ExtensionConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating fabric extensions directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing fabric extension config: %w", err)
+	}
+
+	if c.Register {
+		fab, err := fabric.New(cfg.Fabric)
+		if err != nil {
+			return err
+		}
+		if err := fab.Register(context.Background(), path); err != nil {
+			return err
+		}
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(fabricSetupJSON{Path: path, Registered: c.Register})
+	}
+
+	infof(c.globals, "Wrote fabric extension config to %s\n", path)
+	if !c.Register {
+		infof(c.globals, "Register it with: fabric --addextension %s\n", path)
+	}
+	infof(c.globals, "Then pull history into any prompt or pattern with: {{ext:%s:context:your topic}}\n", fabric.ExtensionName)
+	return nil
+}
+
+// forwardedArgs returns the global flags that select this Chronicle
+// instance, so fabric queries the same profile and database. Paths are made
+// absolute because fabric runs from its own working directory.
+func (c *FabricSetupCommand) forwardedArgs() ([]string, error) {
+	if c.globals == nil {
+		return nil, nil
+	}
+	var args []string
+	for _, f := range []struct{ flag, value string }{
+		{"--config", c.globals.Config},
+		{"--db-path", c.globals.DBPath},
+	} {
+		if f.value == "" {
+			continue
+		}
+		abs, err := filepath.Abs(f.value)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, f.flag, abs)
+	}
+	if c.globals.Profile != "" {
+		args = append(args, "--profile", c.globals.Profile)
+	}
+	return args, nil
+}
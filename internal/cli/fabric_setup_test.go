@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/fabric"
+)
+
+func TestFabricSetup_WritesExtensionConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cmd := &FabricSetupCommand{globals: &GlobalFlags{Profile: "work"}, version: "1.2.3", executable: "/usr/local/bin/chronicle"}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithConfig(config.DefaultConfig()))
+	})
+
+	path, err := fabric.ExtensionConfigPath()
+	require.NoError(t, err)
+	assert.Contains(t, output, "Wrote fabric extension config to "+path)
+	assert.Contains(t, output, "fabric --addextension "+path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var ext struct {
+		Name       string `yaml:"name"`
+		Executable string `yaml:"executable"`
+		Version    string `yaml:"version"`
+		Operations map[string]struct {
+			CmdTemplate string `yaml:"cmd_template"`
+		} `yaml:"operations"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &ext))
+	assert.Equal(t, "chronicle", ext.Name)
+	assert.Equal(t, "/usr/local/bin/chronicle", ext.Executable)
+	assert.Equal(t, "1.2.3", ext.Version)
+	assert.Equal(t, "{{executable}} --profile work context --query {{value}}", ext.Operations["context"].CmdTemplate)
+}
+
+func TestFabricSetup_Print(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cmd := &FabricSetupCommand{Print: true, globals: &GlobalFlags{}, version: "test", executable: "/bin/chronicle"}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithConfig(config.DefaultConfig()))
+	})
+
+	assert.Contains(t, output, "name: chronicle")
+	assert.NoDirExists(t, filepath.Join(home, ".config", "fabric", "extensions"))
+}
+
+func TestFabricSetup_Register(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	log := filepath.Join(t.TempDir(), "args")
+	bin := filepath.Join(t.TempDir(), "fabric")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho \"$@\" > "+log+"\n"), 0755))
+
+	cfg := config.DefaultConfig()
+	cfg.Fabric.Binary = bin
+	cmd := &FabricSetupCommand{Register: true, globals: &GlobalFlags{}, version: "test", executable: "/bin/chronicle"}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithConfig(cfg))
+	})
+	assert.NotContains(t, output, "Register it with")
+
+	path, err := fabric.ExtensionConfigPath()
+	require.NoError(t, err)
+	args, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "--addextension "+path+"\n", string(args))
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// maxFilterSuggestDistance is the furthest a mistyped --source or
+// --browser value may be from a known one to be suggested.
+const maxFilterSuggestDistance = 2
+
+// checkFilters validates --source and --browser values against those in
+// the database (see checkFilterValue).
+func checkFilters(ctx context.Context, store storage.Store, source string, browsers []string) error {
+	if source != "" {
+		sources, err := store.ListSources(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkFilterValue("--source", source, sources); err != nil {
+			return err
+		}
+	}
+	if len(browsers) > 0 {
+		known, err := store.ListBrowsers(ctx)
+		if err != nil {
+			return err
+		}
+		for _, b := range browsers {
+			if err := checkFilterValue("--browser", b, known); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkFilterValue returns an error when value, given for flag, matches no
+// event in the database, suggesting the closest known value or listing
+// them all. Nothing is rejected while known is empty, so filters work on a
+// new database.
+func checkFilterValue(flag, value string, known []storage.ValueCount) error {
+	if value == "" || len(known) == 0 {
+		return nil
+	}
+	names := make([]string, len(known))
+	best, bestDist := "", maxFilterSuggestDistance+1
+	for i, vc := range known {
+		if vc.Value == value {
+			return nil
+		}
+		names[i] = vc.Value
+		if d := storage.EditDistance(strings.ToLower(value), strings.ToLower(vc.Value)); d < bestDist {
+			best, bestDist = vc.Value, d
+		}
+	}
+	if best != "" {
+		return fmt.Errorf("no events with %s %q; did you mean %q?", flag, value, best)
+	}
+	return fmt.Errorf("no events with %s %q (have: %s)", flag, value, strings.Join(names, ", "))
+}
+
+// checkLang validates a --lang code against the languages detected in the
+// database. Unlike checkFilterValue it never suggests a near match: every
+// two-letter code is within two edits of every other.
+func checkLang(ctx context.Context, store storage.Store, lang string) error {
+	if lang == "" {
+		return nil
+	}
+	known, err := store.ListLanguages(ctx)
+	if err != nil || len(known) == 0 {
+		return err
+	}
+	names := make([]string, len(known))
+	for i, vc := range known {
+		if vc.Value == lang {
+			return nil
+		}
+		names[i] = fmt.Sprintf("%s (%s)", vc.Value, storage.LanguageName(vc.Value))
+	}
+	return fmt.Errorf("no events with --lang %q (have: %s)", lang, strings.Join(names, ", "))
+}
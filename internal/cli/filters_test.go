@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestCheckFilterValue(t *testing.T) {
+	known := []storage.ValueCount{{Value: "chrome", Count: 3}, {Value: "firefox", Count: 1}}
+
+	assert.NoError(t, checkFilterValue("--browser", "chrome", known))
+	assert.NoError(t, checkFilterValue("--browser", "", known))
+	assert.NoError(t, checkFilterValue("--browser", "edge", nil), "an empty database accepts anything")
+
+	assert.EqualError(t, checkFilterValue("--browser", "chorme", known), `no events with --browser "chorme"; did you mean "chrome"?`)
+	assert.EqualError(t, checkFilterValue("--browser", "Firefox", known), `no events with --browser "Firefox"; did you mean "firefox"?`)
+	assert.EqualError(t, checkFilterValue("--browser", "edge", known), `no events with --browser "edge" (have: chrome, firefox)`)
+}
+
+func TestSearch_UnknownFilterValues(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Browser: []string{"safary"}, globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `did you mean "safari"?`)
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Source: "extention", globals: &GlobalFlags{}}
+	err = cmd.executeWithStore(store, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `did you mean "extension"?`)
+}
+
+func TestSearch_Lang(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	ctx := context.Background()
+	de := &storage.Event{URL: "https://heise.example/lancedb", Title: "LanceDB im Test", Source: "extension"}
+	require.NoError(t, store.AddEventWithContent(ctx, de, "Die Datenbank ist schnell, und sie ist nicht schwer zu bedienen. Wir haben sie mit Python getestet."))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Lang: "DE", globals: &GlobalFlags{JSON: true}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB", "im"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 1)
+	assert.Equal(t, de.ID, out.Results[0].ID)
+	assert.Equal(t, "de", out.Results[0].Lang)
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Lang: "fr", globals: &GlobalFlags{}}
+	assert.EqualError(t, cmd.executeWithStore(store, nil), `no events with --lang "fr" (have: de (German))`)
+}
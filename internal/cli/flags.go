@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"io"
 
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/embeddings"
 	"github.com/runnerr0/chronicle/internal/storage"
 )
 
@@ -11,13 +13,18 @@ import (
 type GlobalFlags struct {
 	Config  string `long:"config" description:"Path to config file" default:""`
 	DBPath  string `long:"db-path" description:"Override database file path"`
+	Profile string `long:"profile" env:"CHRONICLE_PROFILE" description:"Use a separate profile's config and database"`
 	JSON    bool   `long:"json" description:"Output in JSON format"`
-	Verbose bool   `long:"verbose" description:"Enable verbose output"`
+	Verbose bool   `long:"verbose" description:"Trace debug logging (database path, SQL timings, exclusions) to stderr"`
+	Quiet   bool   `long:"quiet" description:"Suppress informational output"`
+	Color   string `long:"color" description:"Colorize output (default: output.color from config, else auto)" choice:"auto" choice:"always" choice:"never"`
 	Version bool   `long:"version" description:"Show version and exit"`
 }
 
 // StatusCommand — show ingestion health, database stats, config summary.
 type StatusCommand struct {
+	Watch string `long:"watch" optional:"yes" optional-value:"5s" description:"Refresh the display every interval (default 5s) and show ingest rate"`
+
 	globals *GlobalFlags
 	version string
 }
@@ -25,26 +32,49 @@ type StatusCommand struct {
 // SearchCommand — search captured events by keyword with filters.
 type SearchCommand struct {
 	Query        string   `short:"q" long:"query" description:"Search query terms"`
-	Since        string   `long:"since" description:"Only events newer than duration (e.g., 7d, 24h, 2w)" default:"30d"`
-	Until        string   `long:"until" description:"Only events older than duration"`
-	Domain       []string `long:"domain" description:"Filter by domain (repeatable)"`
+	In           []string `long:"in" choice:"title" choice:"url" choice:"body" description:"Only match the query in this field (repeatable; default: title, url and body)"`
+	BodyOnly     bool     `long:"body-only" description:"Only match the query in captured page content (same as --in body)"`
+	NoSynonyms   bool     `long:"no-synonyms" description:"Match the query as typed, without search.synonyms expansion"`
+	Since        string   `long:"since" description:"Only events newer than this (e.g., 7d, 24h, yesterday, last week, monday, 2 weeks ago, 2025-01-01) (default: search.default_since, 30d)"`
+	Until        string   `long:"until" description:"Only events older than this: a date (whole day included), time or phrase, or a duration after --since"`
+	Today        bool     `long:"today" description:"Only events from today, local time (replaces --since/--until)"`
+	On           string   `long:"on" description:"Only events on this local day (e.g., 2025-03-01, yesterday; replaces --since/--until)"`
+	Domain       []Domain `long:"domain" description:"Filter by domain (repeatable)"`
 	Source       string   `long:"source" description:"Filter by source (extension/manual/import)"`
 	Browser      []string `long:"browser" description:"Filter by browser (repeatable)"`
+	Lang         string   `long:"lang" description:"Only events whose body is in this language, as an ISO 639-1 code (e.g., de); its stopwords are ignored in the query"`
+	MinReadTime  string   `long:"min-read-time" description:"Only events whose body takes at least this long to read (e.g., 5m)"`
+	Keyword      string   `long:"keyword" description:"Only events with this extracted keyword, or a keyphrase containing it (see chronicle open)"`
 	HasBody      bool     `long:"has-body" description:"Only events with captured body content"`
 	HasEmbedding bool     `long:"has-embedding" description:"Only events with generated embeddings"`
-	Semantic     bool     `long:"semantic" description:"Use semantic search (requires embeddings enabled)"`
-	Hybrid       bool     `long:"hybrid" description:"Use hybrid search: keyword + semantic"`
-	Limit        int      `long:"limit" description:"Maximum results" default:"10"`
+	Semantic     bool     `long:"semantic" description:"Rank events by meaning, comparing the query's embedding with theirs (requires embeddings enabled; see chronicle embed)"`
+	Hybrid       bool     `long:"hybrid" description:"Rank by keyword and semantic matches together, fused by reciprocal rank (requires embeddings enabled; --json shows each result's score)"`
+	Limit        int      `long:"limit" description:"Maximum results (default: search.default_limit, 10)"`
 	Offset       int      `long:"offset" description:"Skip first N results" default:"0"`
+	UniqueURL    bool     `long:"unique-url" description:"Collapse repeat visits of the same page into one result with a visit count"`
+	Sort         string   `long:"sort" description:"Result order (default: search.default_sort, relevance)" choice:"relevance" choice:"newest" choice:"oldest"`
+	Output       string   `long:"output" description:"Output format (default: search.default_output, human)" choice:"human" choice:"json" choice:"urls" choice:"alfred" choice:"raycast"`
+	Explain      bool     `long:"explain" hidden:"yes" description:"Print the generated SQL, parameters, and query plan instead of results"`
 
-	globals *GlobalFlags
-	version string
+	globals  *GlobalFlags
+	version  string
+	style    palette
+	history  bool                // search.record_history: log searches and use them as ranking hints
+	synonyms [][]string          // search.synonyms: groups of words a query expands with
+	embedder embeddings.Embedder // --semantic: embeds the query; nil means one from the embeddings config
+	embedCfg config.EmbeddingsConfig
+
+	suggestion string                         // "did you mean" query when a text search found nothing
+	total      int64                          // events matching the search across all pages; 0 with --unique-url
+	visits     map[string]urlVisits           // --unique-url: repeat visits, by the ID of the result shown
+	summaries  map[string]string              // stored summaries of the results, by event ID
+	scores     map[string]storage.HybridScore // --hybrid: how each result ranked, by event ID
 }
 
 // OpenCommand — print the full stored content of a specific event.
 type OpenCommand struct {
-	ID     string `long:"id" description:"Event ID (required)"`
-	Format string `long:"format" description:"Output format: full | md | raw | url | title | body | metadata | json" default:"full"`
+	ID     EventID `long:"id" description:"Event ID (required)"`
+	Format string  `long:"format" description:"Output format: full | md | raw | url | title | body | metadata | json" default:"full"`
 
 	globals *GlobalFlags
 	version string
@@ -53,21 +83,26 @@ type OpenCommand struct {
 // AddCommand — manually ingest a URL/title/body into Chronicle.
 type AddCommand struct {
 	URL         string `long:"url" description:"URL to record (required)"`
-	Title       string `long:"title" description:"Page title (required)"`
+	Title       string `long:"title" description:"Page title (required unless --fetch finds one)"`
 	BodyFile    string `long:"body-file" description:"Path to file containing body content"`
 	Body        string `long:"body" description:"Inline body text"`
 	BrowserName string `long:"browser" description:"Source browser label" default:"manual"`
 	Embed       bool   `long:"embed" description:"Generate embedding immediately"`
+	Fetch       bool   `long:"fetch" description:"Download the page for its title, text, and metadata (author, published date, OpenGraph); --title and --body take precedence"`
 
-	globals *GlobalFlags
-	version string
+	globals       *GlobalFlags
+	version       string
+	webhooks      []config.WebhookConfig     // webhooks: notified of the new event
+	notifications config.NotificationsConfig // notifications: desktop alert for watched domains
+	hooks         config.HooksConfig         // hooks: run before and after the capture
+	source        string                     // event source; "manual" when empty
 }
 
 // IngestCommand — start the Chronicle daemon (local HTTP service).
 type IngestCommand struct {
 	Foreground bool   `long:"foreground" description:"Run in foreground (don't daemonize)"`
-	Port       int    `long:"port" description:"Override daemon port"`
-	LogLevel   string `long:"log-level" description:"Override log level"`
+	Port       int    `long:"port" description:"Override daemon port (daemon.port)"`
+	LogLevel   string `long:"log-level" description:"Override log level: debug, info, warn or error (logging.level)"`
 
 	globals *GlobalFlags
 	version string
@@ -75,9 +110,11 @@ type IngestCommand struct {
 
 // PruneCommand — apply TTL pruning to remove old events.
 type PruneCommand struct {
-	OlderThan string `long:"older-than" description:"Override retention period (e.g., 30d, 7d, 24h)"`
-	DryRun    bool   `long:"dry-run" description:"Show what would be pruned without deleting"`
-	Force     bool   `long:"force" description:"Skip confirmation prompt"`
+	OlderThan    string `long:"older-than" description:"Override retention period (e.g., 30d, 7d, 24h)"`
+	DryRun       bool   `long:"dry-run" description:"Show what would be pruned without deleting"`
+	Force        bool   `long:"force" description:"Skip confirmation prompt"`
+	Domain       Domain `long:"domain" description:"Only prune events from this domain (and its subdomains)"`
+	ShowSchedule bool   `long:"show-schedule" description:"Show when retention was last applied and when it runs next"`
 
 	globals *GlobalFlags
 	version string
@@ -85,14 +122,465 @@ type PruneCommand struct {
 	// Testing hooks (not exposed via CLI flags)
 	store storage.Store
 	stdin io.Reader
+	cfg   *config.Config
 }
 
 // PurgeCommand — delete ALL Chronicle data with safety confirmation.
 type PurgeCommand struct {
-	All   bool `long:"all" description:"Required flag to confirm purge intent"`
-	Force bool `long:"force" description:"Skip safety confirmation prompt"`
+	All    bool   `long:"all" description:"Required flag to confirm purge intent"`
+	Domain Domain `long:"domain" description:"Only purge events from this domain (and its subdomains)"`
+	Source string `long:"source" description:"Only purge events from this source (extension/manual/import)"`
+	Force  bool   `long:"force" description:"Skip safety confirmation prompt"`
 
 	globals *GlobalFlags
 	version string
 	db      *sql.DB // injectable for testing; nil means open default DB
 }
+
+// SummarizeCommand — run a stored event body through a fabric pattern, or
+// generate the stored summaries of bodies that lack one.
+type SummarizeCommand struct {
+	ID      EventID `long:"id" description:"Event ID (required unless --pending)"`
+	Pattern string  `long:"pattern" description:"Fabric pattern to apply" default:"summarize"`
+	Save    bool    `long:"save" description:"Store the result as a new event linked to the same URL"`
+	Store   bool    `long:"store" description:"Store the result as the event's summary, shown in search results and used as its embedding text"`
+	Pending bool    `long:"pending" description:"Generate and store a summary for every event with a body but no summary, with summaries.provider (ollama or fabric)"`
+	Limit   int     `long:"limit" description:"With --pending, summarize at most this many events, newest first (0: all)" default:"0"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// DigestCommand — write a markdown digest of a period's captures.
+type DigestCommand struct {
+	Period  string `long:"period" description:"Digest period: day | week" default:"week"`
+	Output  string `short:"o" long:"output" description:"Output file path (\"-\" for stdout; default chronicle-digest-<period>-<date>.md)"`
+	Pattern string `long:"pattern" description:"Fabric pattern for an LLM-written narrative (omit to skip)"`
+	Limit   int    `long:"limit" description:"Maximum events to include" default:"1000"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// ReportCommand — write a markdown review of a period's browsing.
+type ReportCommand struct {
+	Period string `long:"period" description:"Report period: day | week" default:"week"`
+	Out    string `short:"o" long:"out" description:"Output file path (\"-\" for stdout; default chronicle-report-<period>-<date>.md)"`
+	Limit  int    `long:"limit" description:"Maximum entries per section" default:"10"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// PipeCommand — stream matching events with their bodies to stdout or a command.
+type PipeCommand struct {
+	Query   string   `short:"q" long:"query" description:"Search query terms"`
+	Since   string   `long:"since" description:"Only events newer than this (e.g., 7d, 24h, yesterday, last week, 2025-01-01)" default:"30d"`
+	Until   string   `long:"until" description:"Only events older than this: a date (whole day included), time or phrase, or a duration after --since"`
+	Today   bool     `long:"today" description:"Only events from today, local time (replaces --since/--until)"`
+	On      string   `long:"on" description:"Only events on this local day (e.g., 2025-03-01, yesterday; replaces --since/--until)"`
+	Domain  []Domain `long:"domain" description:"Filter by domain (repeatable)"`
+	Source  string   `long:"source" description:"Filter by source (extension/manual/import)"`
+	HasBody bool     `long:"has-body" description:"Only events with captured body content"`
+	Limit   int      `long:"limit" description:"Maximum events" default:"20"`
+	Exec    string   `long:"exec" description:"Shell command to stream into instead of stdout (e.g., \"fabric -p extract_wisdom\")"`
+	Pattern string   `long:"pattern" description:"Run the stream through this fabric pattern and print the result"`
+
+	globals *GlobalFlags
+	version string
+	cfg     *config.Config
+}
+
+// ContextCommand — pack the most relevant events into an LLM context block.
+type ContextCommand struct {
+	Query  string   `short:"q" long:"query" description:"Topic to gather context for"`
+	Budget int      `long:"budget" description:"Approximate token budget for the context block" default:"8000"`
+	Since  string   `long:"since" description:"Only events newer than this (e.g., 7d, 24h, yesterday, last week, 2025-01-01)" default:"30d"`
+	Domain []Domain `long:"domain" description:"Filter by domain (repeatable)"`
+	Limit  int      `long:"limit" description:"Maximum events to consider" default:"20"`
+
+	globals  *GlobalFlags
+	version  string
+	embedder embeddings.Embedder // embeds the query for hybrid search; nil means one from the embeddings config
+	embedCfg config.EmbeddingsConfig
+}
+
+// McpCommand — serve the Model Context Protocol over stdio.
+type McpCommand struct {
+	globals *GlobalFlags
+	version string
+}
+
+// APICommand — serve the read-only HTTP query API.
+type APICommand struct {
+	Host  string `long:"host" description:"Override API listen host"`
+	Port  int    `long:"port" description:"Override API port"`
+	Token string `long:"token" description:"Bearer token clients must present (default: api.auth_token, or a generated one)"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// VersionCommand — report build, runtime, and schema information.
+type VersionCommand struct {
+	globals *GlobalFlags
+	version string
+}
+
+// ProfileCommand — manage separate profiles (e.g. work and personal).
+type ProfileCommand struct {
+	List   ProfileListCommand   `command:"list" description:"List profiles"`
+	Create ProfileCreateCommand `command:"create" description:"Create a profile"`
+}
+
+// ProfileListCommand — list created profiles.
+type ProfileListCommand struct {
+	globals *GlobalFlags
+}
+
+// ProfileCreateCommand — create a profile with its own config and database.
+type ProfileCreateCommand struct {
+	Args struct {
+		Name string `positional-arg-name:"name" description:"Profile name"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// MigrateCommand — inspect and move the database schema version.
+type MigrateCommand struct {
+	Status MigrateStatusCommand `command:"status" description:"List schema migrations and whether each is applied"`
+	Up     MigrateUpCommand     `command:"up" description:"Apply pending migrations"`
+	Down   MigrateDownCommand   `command:"down" description:"Revert applied migrations"`
+}
+
+// MigrateStatusCommand — list migrations and their state.
+type MigrateStatusCommand struct {
+	globals *GlobalFlags
+}
+
+// MigrateUpCommand — apply pending migrations.
+type MigrateUpCommand struct {
+	To int `long:"to" description:"Stop at this schema version (default: latest)"`
+
+	globals *GlobalFlags
+}
+
+// MigrateDownCommand — revert applied migrations.
+type MigrateDownCommand struct {
+	To    int  `long:"to" description:"Schema version to revert to (default: the previous version)"`
+	Force bool `long:"force" description:"Skip confirmation prompt"`
+
+	globals *GlobalFlags
+	stdin   io.Reader // for testing; defaults to os.Stdin
+}
+
+// ConfigCommand — inspect and edit the config file.
+type ConfigCommand struct {
+	Set  ConfigSetCommand  `command:"set" description:"Set a config value, preserving comments"`
+	Show ConfigShowCommand `command:"show" description:"Show the effective config merged from all layers"`
+}
+
+// ConfigShowCommand — print every effective config value.
+type ConfigShowCommand struct {
+	Origin bool `long:"origin" description:"Show which layer (default, system, user, local) set each value"`
+
+	globals *GlobalFlags
+}
+
+// ConfigSetCommand — set a dotted config key (e.g. retention.days).
+type ConfigSetCommand struct {
+	Args struct {
+		Key   string `positional-arg-name:"key" description:"Dotted config key, e.g. retention.days"`
+		Value string `positional-arg-name:"value" description:"New value (comma-separated for lists)"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// PatternsCommand — list fabric patterns from fabric.patterns_dir.
+type PatternsCommand struct {
+	globals *GlobalFlags
+	version string
+}
+
+// FabricSetupCommand — write (and optionally register) the fabric extension config.
+type FabricSetupCommand struct {
+	Print    bool `long:"print" description:"Print the extension config instead of writing it"`
+	Register bool `long:"register" description:"Also run fabric --addextension on the written config"`
+
+	globals    *GlobalFlags
+	version    string
+	executable string // overrides os.Executable (for testing)
+}
+
+// BenchCommand — measure storage performance on a scratch database.
+type BenchCommand struct {
+	SeedFake int    `long:"seed-fake" description:"Number of synthetic events to insert" default:"10000"`
+	Queries  int    `long:"queries" description:"Number of searches to time" default:"100"`
+	Seed     int64  `long:"seed" description:"Random seed for reproducible data" default:"1"`
+	Keep     string `long:"keep" description:"Write the benchmark database to this path (must not exist) instead of a temporary file"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// StatsCommand — show when browsing happens by hour and weekday.
+type StatsCommand struct {
+	Since   string `long:"since" description:"Only count events newer than this (e.g., 7d, 24h, yesterday, 2025-01-01)" default:"30d"`
+	Heatmap bool   `long:"heatmap" description:"Show a calendar heatmap of events per day over the past year"`
+	SVG     string `long:"svg" description:"With --heatmap, also write the heatmap as an SVG image to this path"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// AroundCommand — list events captured near a point in time.
+type AroundCommand struct {
+	Window string   `long:"window" description:"How far either side of the time to look (e.g., 30m, 2h)" default:"1h"`
+	Domain []Domain `long:"domain" description:"Filter by domain (repeatable)"`
+	Limit  int      `long:"limit" description:"Maximum events, nearest first" default:"50"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// ResurfaceCommand — rediscover a random sample of older captures.
+type ResurfaceCommand struct {
+	Since  string `long:"since" description:"How far back to look (e.g., 1y, 6 months ago, 2025-01-01)" default:"1y"`
+	MinAge string `long:"min-age" description:"Skip captures newer than this duration" default:"30d"`
+	Count  int    `long:"count" description:"How many captures to resurface" default:"5"`
+	Seed   int64  `long:"seed" description:"Random seed, to repeat a selection (default: random)"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// DomainsCommand — list captured domains with event counts.
+type DomainsCommand struct {
+	Limit int `long:"limit" description:"Maximum domains to list (0 for all)" default:"50"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// LinksCommand — list a capture's outbound links, or the captures linking
+// to a URL.
+type LinksCommand struct {
+	ID    EventID `long:"id" description:"List the links in this event's body"`
+	To    string  `long:"to" description:"List captured pages whose bodies link to this URL"`
+	Limit int     `long:"limit" description:"Maximum pages to list with --to (0 for all)" default:"50"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// JobsCommand — inspect and run the daemon's scheduled jobs.
+type JobsCommand struct {
+	List JobsListCommand `command:"list" description:"List scheduled jobs with their next and last runs"`
+	Run  JobsRunCommand  `command:"run" description:"Run a scheduled job now"`
+}
+
+// JobsListCommand — list the scheduled jobs.
+type JobsListCommand struct {
+	globals *GlobalFlags
+}
+
+// JobsRunCommand — run one scheduled job immediately.
+type JobsRunCommand struct {
+	Args struct {
+		Name string `positional-arg-name:"name" description:"Job name, as shown by jobs list"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// ExcludeCommand — manage the rules that keep domains out of the history.
+type ExcludeCommand struct {
+	Add    ExcludeAddCommand    `command:"add" description:"Exclude a domain, or hostnames matching a regex"`
+	Remove ExcludeRemoveCommand `command:"remove" description:"Remove an exclusion rule, default or not"`
+	List   ExcludeListCommand   `command:"list" description:"List the exclusion rules"`
+	Test   ExcludeTestCommand   `command:"test" description:"Show whether a URL would be excluded, and by which rule"`
+}
+
+// ExcludeAddCommand — add an exclusion rule.
+type ExcludeAddCommand struct {
+	Regex  bool   `long:"regex" description:"The rule is a regular expression matched against hostnames"`
+	Reason string `long:"reason" description:"Why the rule exists, shown by exclude list"`
+	Args   struct {
+		Rule string `positional-arg-name:"rule" description:"Domain (e.g., bank.example.com) or, with --regex, a regular expression"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// ExcludeRemoveCommand — remove an exclusion rule.
+type ExcludeRemoveCommand struct {
+	Regex bool `long:"regex" description:"The rule is a regular expression"`
+	Args  struct {
+		Rule string `positional-arg-name:"rule" description:"Domain or regular expression, as shown by exclude list"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// ExcludeListCommand — list the exclusion rules.
+type ExcludeListCommand struct {
+	globals *GlobalFlags
+}
+
+// ExcludeTestCommand — check a URL against the exclusion rules.
+type ExcludeTestCommand struct {
+	Args struct {
+		URL string `positional-arg-name:"url" description:"URL to check"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals *GlobalFlags
+}
+
+// DoctorCommand — check the database for inconsistencies and repair them.
+type DoctorCommand struct {
+	RebuildFTS bool `long:"rebuild-fts" description:"Rebuild the full-text index from the stored events and bodies"`
+
+	globals *GlobalFlags
+}
+
+// MigrateEncryptCommand — encrypt the bodies of an existing database.
+type MigrateEncryptCommand struct {
+	globals *GlobalFlags
+}
+
+// PullCommand — capture events from an ingestion source adapter.
+type PullCommand struct {
+	Source  string   `long:"source" description:"Source to pull: a name under sources in the config, or an adapter type"`
+	Options []string `long:"option" short:"o" description:"Adapter option as key=value, overriding the config (repeatable)"`
+	List    bool     `long:"list" description:"List the configured sources and adapter types"`
+
+	globals       *GlobalFlags
+	version       string
+	webhooks      []config.WebhookConfig     // webhooks: notified of each new event
+	notifications config.NotificationsConfig // notifications: desktop alert for watched domains
+	hooks         config.HooksConfig         // hooks: run before and after each capture
+}
+
+// CaptureCommand — bulk-ingest newline-delimited JSON events.
+type CaptureCommand struct {
+	Source    string `long:"source" description:"Source recorded for events that don't name one" default:"import"`
+	BatchSize int    `long:"batch-size" description:"Events stored per transaction" default:"100"`
+	Workers   int    `long:"workers" description:"Lines parsed at once, each running the pre-capture hooks (0: one per CPU)" default:"0"`
+	Args      struct {
+		File string `positional-arg-name:"file" description:"JSONL file to read, or - for stdin"`
+	} `positional-args:"yes" required:"yes"`
+
+	globals       *GlobalFlags
+	version       string
+	stdin         io.Reader                  // read for "-"; os.Stdin when nil
+	webhooks      []config.WebhookConfig     // webhooks: notified of each new event
+	notifications config.NotificationsConfig // notifications: desktop alert for watched domains
+	hooks         config.HooksConfig         // hooks: run before and after each capture
+}
+
+// ClipCommand — watch the clipboard and capture copied URLs.
+type ClipCommand struct {
+	Auto     bool   `long:"auto" description:"Capture copied URLs without asking"`
+	Fetch    bool   `long:"fetch" description:"Download each page for its title, text, and metadata"`
+	Interval string `long:"interval" description:"How often to check the clipboard" default:"1s"`
+
+	globals       *GlobalFlags
+	version       string
+	stdin         io.Reader                  // answers to the capture prompts; os.Stdin when nil
+	webhooks      []config.WebhookConfig     // webhooks: notified of each new event
+	notifications config.NotificationsConfig // notifications: desktop alert for watched domains
+	hooks         config.HooksConfig         // hooks: run before and after each capture
+}
+
+// SearchesCommand — review the opt-in search history.
+type SearchesCommand struct {
+	Limit int  `long:"limit" description:"Maximum searches to show" default:"20"`
+	Top   bool `long:"top" description:"Show the most repeated queries instead of recent searches"`
+	Clear bool `long:"clear" description:"Delete all recorded searches"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// DuplicatesCommand — report clusters of duplicate and near-duplicate events.
+type DuplicatesCommand struct {
+	Since      string  `long:"since" description:"Only consider events newer than this (e.g., 30d, 2w, last month)" default:"90d"`
+	Similarity float64 `long:"similarity" description:"Minimum title word overlap (0-1) for near-duplicate titles" default:"0.8"`
+	Limit      int     `long:"limit" description:"Maximum clusters to show per kind" default:"20"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// ExportCommand — write captured events out in other formats.
+type ExportCommand struct {
+	Format      string `long:"format" description:"Export format" choice:"jsonl" choice:"csv" choice:"md" choice:"obsidian" choice:"org" choice:"parquet"`
+	Out         string `short:"o" long:"out" description:"Output path: a directory for obsidian, else a file (\"-\" for stdout)"`
+	Since       string `long:"since" description:"Only events newer than this (e.g., 7d, yesterday, 2025-01-01; default: all)"`
+	Until       string `long:"until" description:"Only events older than this: a date (whole day included), time or phrase, or a duration after --since"`
+	Today       bool   `long:"today" description:"Only events from today, local time (replaces --since/--until)"`
+	On          string `long:"on" description:"Only events on this local day (e.g., 2025-03-01, yesterday; replaces --since/--until)"`
+	Domain      Domain `long:"domain" description:"Only events from this domain"`
+	IncludeBody bool   `long:"include-body" description:"Include captured page bodies"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// ReplicateCommand — back up the database to a replica, or restore from one.
+type ReplicateCommand struct {
+	To       string `long:"to" description:"Replica: a directory, file://path or s3://bucket/prefix (default: storage.replica_url)"`
+	Interval string `long:"interval" description:"Time between snapshots, e.g. 10m, 1h (default: storage.replica_interval_minutes)"`
+	Once     bool   `long:"once" description:"Take one snapshot and exit"`
+	List     bool   `long:"list" description:"List the replica's snapshots"`
+	Restore  string `long:"restore" description:"Restore a snapshot into this new file (never the live database)"`
+	At       string `long:"at" description:"With --restore: use the newest snapshot at or before this RFC 3339 time or duration ago (e.g. 2h)"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// SyncCommand — exchange events with other devices through a shared folder.
+type SyncCommand struct {
+	Dir string `long:"dir" description:"Shared sync folder, e.g. in Dropbox or Syncthing (default: sync.dir)"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// GRPCCommand — serve the gRPC API (Capture, Search, Watch) over TLS.
+type GRPCCommand struct {
+	Host  string `long:"host" description:"Override gRPC listen host"`
+	Port  int    `long:"port" description:"Override gRPC port"`
+	Token string `long:"token" description:"Bearer token clients must present (default: grpc.auth_token, or a generated one)"`
+	Cert  string `long:"cert" description:"PEM certificate file (default: grpc.cert_file, or a generated self-signed one)"`
+	Key   string `long:"key" description:"PEM private key file for --cert (default: grpc.key_file)"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// RPCCommand — serve search, get, add and stats as JSON-RPC 2.0 over stdio.
+type RPCCommand struct {
+	globals *GlobalFlags
+	version string
+}
+
+// CompletionCommand — print a shell completion script.
+type CompletionCommand struct {
+	Shell string `long:"shell" description:"Shell to generate completion for: bash | zsh" default:"bash"`
+
+	globals *GlobalFlags
+	version string
+}
+
+// EmbedCommand — generate the embeddings semantic search compares.
+type EmbedCommand struct {
+	Limit int `long:"limit" description:"Embed at most this many events, newest first (0: all)" default:"0"`
+
+	globals  *GlobalFlags
+	version  string
+	embedder embeddings.Embedder // nil means one from the embeddings config
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/grpcapi"
+)
+
+// Execute implements the go-flags Commander interface for GRPCCommand.
+func (c *GRPCCommand) Execute(args []string) error {
+	cfg := loadConfig(c.globals)
+
+	token, generated, err := c.resolveToken(cfg)
+	if err != nil {
+		return err
+	}
+
+	host := cfg.GRPC.Host
+	if c.Host != "" {
+		host = c.Host
+	}
+	port := cfg.GRPC.Port
+	if c.Port != 0 {
+		port = c.Port
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	cert, fingerprint, err := c.loadCert(cfg, host)
+	if err != nil {
+		return err
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("grpc server: %w", err)
+	}
+
+	service := grpcapi.NewServer(store, token)
+	service.SetPipeline(newPipeline(c.globals, store, cfg.Hooks, cfg.Webhooks, cfg.Notifications))
+	srv := service.GRPCServer(grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	slog.Info("grpc server listening", "addr", addr, "self_signed", fingerprint != "")
+	noticef(c.globals, "Chronicle gRPC listening on %s (TLS)\n", addr)
+	if fingerprint != "" {
+		fmt.Fprintf(os.Stderr, "Self-signed certificate SHA-256: %s\n", fingerprint)
+	}
+	if generated {
+		fmt.Fprintf(os.Stderr, "Generated gRPC token: %s\n", token)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("grpc server: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Watch streams only end when clients hang up, so don't wait long.
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		srv.Stop()
+	}
+	return nil
+}
+
+// resolveToken picks the gRPC token: --token, then grpc.auth_token,
+// otherwise a random token generated for this run.
+func (c *GRPCCommand) resolveToken(cfg *config.Config) (token string, generated bool, err error) {
+	if c.Token != "" {
+		return c.Token, false, nil
+	}
+	if cfg.GRPC.AuthToken != "" {
+		return cfg.GRPC.AuthToken, false, nil
+	}
+
+	token, err = randomToken()
+	if err != nil {
+		return "", false, fmt.Errorf("generate gRPC token: %w", err)
+	}
+	return token, true, nil
+}
+
+// loadCert loads --cert/--key (or grpc.cert_file/grpc.key_file), or
+// generates a self-signed certificate for host and returns its
+// fingerprint.
+func (c *GRPCCommand) loadCert(cfg *config.Config, host string) (tls.Certificate, string, error) {
+	certFile, keyFile := cfg.GRPC.CertFile, cfg.GRPC.KeyFile
+	if c.Cert != "" {
+		certFile, keyFile = c.Cert, c.Key
+	}
+
+	if certFile == "" && keyFile == "" {
+		cert, fingerprint, err := grpcapi.SelfSignedCert(host, time.Now())
+		if err != nil {
+			return tls.Certificate{}, "", fmt.Errorf("self-signed certificate: %w", err)
+		}
+		return cert, fingerprint, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return tls.Certificate{}, "", fmt.Errorf("a certificate and key must be given together")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("load certificate: %w", err)
+	}
+	return cert, "", nil
+}
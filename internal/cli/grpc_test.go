@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func TestGRPCResolveToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GRPC.AuthToken = "from-config"
+
+	token, generated, err := (&GRPCCommand{Token: "from-flag"}).resolveToken(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "from-flag", token)
+	assert.False(t, generated)
+
+	token, _, err = (&GRPCCommand{}).resolveToken(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "from-config", token)
+
+	token, generated, err = (&GRPCCommand{}).resolveToken(config.DefaultConfig())
+	require.NoError(t, err)
+	assert.True(t, generated)
+	assert.Len(t, token, 32)
+}
+
+func TestGRPCLoadCert_SelfSigned(t *testing.T) {
+	cert, fingerprint, err := (&GRPCCommand{}).loadCert(config.DefaultConfig(), "127.0.0.1")
+	require.NoError(t, err)
+	assert.Len(t, fingerprint, 64)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.NoError(t, leaf.VerifyHostname("127.0.0.1"))
+	assert.NoError(t, leaf.VerifyHostname("localhost"))
+}
+
+func TestGRPCLoadCert_NeedsBothFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GRPC.CertFile = "/tmp/cert.pem"
+
+	_, _, err := (&GRPCCommand{}).loadCert(cfg, "127.0.0.1")
+	assert.ErrorContains(t, err, "together")
+
+	_, _, err = (&GRPCCommand{Cert: "/nonexistent/cert.pem", Key: "/nonexistent/key.pem"}).loadCert(cfg, "127.0.0.1")
+	assert.ErrorContains(t, err, "load certificate")
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// heatmapWeeks is how many week columns the calendar heatmap spans.
+const heatmapWeeks = 53
+
+// heatmapJSON is the JSON output structure for stats --heatmap.
+type heatmapJSON struct {
+	Since string         `json:"since"`
+	Until string         `json:"until"`
+	Total int64          `json:"total"`
+	Max   int64          `json:"max"`
+	Days  []dayCountJSON `json:"days"`
+}
+
+// heatmapColors are the SVG fill colors per level, from no events up.
+var heatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// heatmapEmpty marks days inside the range with no events in the terminal
+// render, so they stand apart from the blank cells after today.
+const heatmapEmpty = '·'
+
+// heatmap renders events per day for the past year as a calendar, one
+// column per week starting on Monday.
+func (c *StatsCommand) heatmap(store storage.Store, now time.Time) error {
+	days, err := heatmapDays(context.Background(), store, now)
+	if err != nil {
+		return err
+	}
+
+	if c.SVG != "" {
+		f, err := os.Create(c.SVG)
+		if err != nil {
+			return fmt.Errorf("create svg file: %w", err)
+		}
+		writeHeatmapSVG(f, days)
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("write svg file: %w", err)
+		}
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := heatmapJSON{
+			Since: days[0].Day.Format("2006-01-02"),
+			Until: days[len(days)-1].Day.Format("2006-01-02"),
+			Days:  make([]dayCountJSON, len(days)),
+		}
+		for i, d := range days {
+			out.Days[i] = dayCountJSON{Date: d.Day.Format("2006-01-02"), Count: d.Count}
+			out.Total += d.Count
+			if d.Count > out.Max {
+				out.Max = d.Count
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	writeHeatmap(os.Stdout, days)
+	if c.SVG != "" {
+		infof(c.globals, "\nWrote heatmap SVG to %s\n", c.SVG)
+	}
+	return nil
+}
+
+// heatmapDays returns daily counts from the Monday starting the first week
+// column through now.
+func heatmapDays(ctx context.Context, store storage.Store, now time.Time) ([]storage.DayCount, error) {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	sinceMonday := (int(today.Weekday()) + 6) % 7
+	start := today.AddDate(0, 0, -sinceMonday-7*(heatmapWeeks-1))
+
+	days, err := store.EventsPerDay(ctx, start, now)
+	if err != nil {
+		return nil, fmt.Errorf("heatmap: %w", err)
+	}
+	return days, nil
+}
+
+// writeHeatmap renders days as a terminal calendar: weekday rows, week
+// columns, month labels on top, and a legend.
+func writeHeatmap(w io.Writer, days []storage.DayCount) {
+	var total, busiest int64
+	for _, d := range days {
+		total += d.Count
+		if d.Count > busiest {
+			busiest = d.Count
+		}
+	}
+	weeks := (len(days) + 6) / 7
+
+	months := []rune(strings.Repeat(" ", weeks+3))
+	lastMonth := time.Month(0)
+	for col := 0; col < weeks; col++ {
+		m := days[col*7].Day.Month()
+		if m != lastMonth && (col == 0 || months[col-1] == ' ') {
+			copy(months[col:], []rune(m.String()[:3]))
+		}
+		lastMonth = m
+	}
+	fmt.Fprintf(w, "     %s\n", strings.TrimRight(string(months), " "))
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for row := 0; row < 7; row++ {
+		line := make([]rune, 0, weeks)
+		for col := 0; col < weeks; col++ {
+			i := col*7 + row
+			switch {
+			case i >= len(days):
+				line = append(line, ' ')
+			case days[i].Count == 0:
+				line = append(line, heatmapEmpty)
+			default:
+				line = append(line, heatShade(days[i].Count, busiest))
+			}
+		}
+		fmt.Fprintf(w, "%-3s  %s\n", labels[row], strings.TrimRight(string(line), " "))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s events in the last year · Less %c%s More\n",
+		formatNumber(total), heatmapEmpty, string(heatShades[1:]))
+}
+
+// writeHeatmapSVG renders days as a GitHub-style SVG calendar.
+func writeHeatmapSVG(w io.Writer, days []storage.DayCount) {
+	const (
+		cell = 10
+		step = 13
+		left = 30
+		top  = 20
+	)
+	var busiest int64
+	for _, d := range days {
+		if d.Count > busiest {
+			busiest = d.Count
+		}
+	}
+	weeks := (len(days) + 6) / 7
+	width := left + weeks*step
+	height := top + 7*step
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="9">`+"\n", width, height)
+
+	lastMonth := time.Month(0)
+	for col := 0; col < weeks; col++ {
+		if m := days[col*7].Day.Month(); m != lastMonth {
+			fmt.Fprintf(w, `  <text x="%d" y="%d" fill="#767676">%s</text>`+"\n", left+col*step, top-8, m.String()[:3])
+			lastMonth = m
+		}
+	}
+	for row, label := range []string{"Mon", "Wed", "Fri"} {
+		fmt.Fprintf(w, `  <text x="0" y="%d" fill="#767676">%s</text>`+"\n", top+row*2*step+cell-1, label)
+	}
+
+	for i, d := range days {
+		col, row := i/7, i%7
+		word := "events"
+		if d.Count == 1 {
+			word = "event"
+		}
+		fmt.Fprintf(w, `  <rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d %s</title></rect>`+"\n",
+			left+col*step, top+row*step, cell, cell,
+			heatmapColors[heatLevel(d.Count, busiest, len(heatmapColors))],
+			d.Day.Format("2006-01-02"), d.Count, word)
+	}
+	fmt.Fprintln(w, "</svg>")
+}
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -11,41 +13,286 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/encrypt"
+	"github.com/runnerr0/chronicle/internal/keychain"
 	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/runnerr0/chronicle/internal/vectorstore"
 )
 
+// defaultRetentionDays applies when retention.days is unset or invalid.
 const defaultRetentionDays = 30
 
-// defaultDBPath returns the default Chronicle database path using the config system.
-func defaultDBPath() string {
-	cfg, err := config.LoadOrCreate()
+// statsCacheTTL is how long long-running servers (api, mcp) reuse stats.
+const statsCacheTTL = 10 * time.Second
+
+// infof prints an informational message to stdout unless --quiet is set.
+func infof(globals *GlobalFlags, format string, args ...interface{}) {
+	if globals != nil && globals.Quiet {
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// noticef is infof for stderr notes that accompany primary output.
+func noticef(globals *GlobalFlags, format string, args ...interface{}) {
+	if globals != nil && globals.Quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// loadConfig returns the effective configuration selected by the global
+// flags (see loadLayeredConfig). Unreadable config falls back to built-in
+// defaults (the profile's defaults under --profile, so profiles never
+// share a database).
+func loadConfig(globals *GlobalFlags) *config.Config {
+	cfg, _, err := loadLayeredConfig(globals)
 	if err != nil {
-		cfg = config.DefaultConfig()
+		if globals != nil && globals.Config == "" && globals.Profile != "" {
+			return config.ProfileDefaults(globals.Profile)
+		}
+		return config.DefaultConfig()
 	}
+	return cfg
+}
 
-	storagePath := cfg.Storage.Path
-	if strings.HasPrefix(storagePath, "~") {
-		home, err := os.UserHomeDir()
+// loadLayeredConfig merges the config layers selected by the global flags
+// and reports where each value came from. --config names a single file
+// that replaces all layers; otherwise the system file, the user (or
+// --profile) file, and ./.chronicle.yaml are merged in that order; the
+// last may only set config.LocalConfigKeys.
+func loadLayeredConfig(globals *GlobalFlags) (*config.Config, config.Origins, error) {
+	switch {
+	case globals != nil && globals.Config != "":
+		if _, err := os.Stat(globals.Config); err != nil {
+			return nil, nil, fmt.Errorf("reading config file: %w", err)
+		}
+		return config.LoadLayers(config.DefaultConfig(), []config.Layer{{Name: "config", Path: globals.Config}})
+	case globals != nil && globals.Profile != "":
+		if !config.ProfileExists(globals.Profile) {
+			return nil, nil, fmt.Errorf("profile %q does not exist", globals.Profile)
+		}
+		path, err := config.ProfileConfigPath(globals.Profile)
 		if err != nil {
-			return "chronicle.db"
+			return nil, nil, err
 		}
-		storagePath = filepath.Join(home, storagePath[1:])
+		layers := config.StandardLayers(path)
+		layers[1].Name = "profile"
+		return config.LoadLayers(config.ProfileDefaults(globals.Profile), layers)
+	default:
+		// Create the user config on first run, as before layering existed.
+		if _, err := config.LoadOrCreate(); err != nil {
+			return nil, nil, err
+		}
+		path, err := config.DefaultPath()
+		if err != nil {
+			return nil, nil, err
+		}
+		return config.LoadLayers(config.DefaultConfig(), config.StandardLayers(path))
+	}
+}
+
+// configFilePath returns the config file selected by the global flags:
+// --config, then the --profile config, then the default location.
+func configFilePath(globals *GlobalFlags) (string, error) {
+	switch {
+	case globals != nil && globals.Config != "":
+		return globals.Config, nil
+	case globals != nil && globals.Profile != "":
+		return config.ProfileConfigPath(globals.Profile)
+	default:
+		return config.DefaultPath()
+	}
+}
+
+// resolveDBPath is the single resolver for the database path used by every
+// command. Priority: --db-path, then the config selected by --config or
+// --profile, then the default config.
+func resolveDBPath(globals *GlobalFlags) (string, error) {
+	if globals != nil && globals.DBPath != "" {
+		return globals.DBPath, nil
+	}
+	if globals != nil && globals.Profile != "" {
+		if err := config.ValidateProfileName(globals.Profile); err != nil {
+			return "", err
+		}
+		if !config.ProfileExists(globals.Profile) {
+			return "", fmt.Errorf("profile %q does not exist (create it with: chronicle profile create %s)", globals.Profile, globals.Profile)
+		}
+	}
+
+	dbPath, err := loadConfig(globals).DBPath()
+	if err != nil {
+		return "", fmt.Errorf("resolve database path: %w", err)
 	}
+	return dbPath, nil
+}
 
-	return filepath.Join(storagePath, cfg.Storage.SQLiteFile)
+// openExistingDB opens the database selected by the global flags read-only,
+// without creating or migrating it. It returns os.ErrNotExist (wrapped) if
+// there is no database yet.
+func openExistingDB(globals *GlobalFlags) (*sql.DB, string, error) {
+	dbPath, err := resolveDBPath(globals)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, dbPath, fmt.Errorf("database %s: %w", dbPath, err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	if err != nil {
+		return nil, dbPath, fmt.Errorf("open database: %w", err)
+	}
+	return db, dbPath, nil
 }
 
-// openDefaultStore opens the default Chronicle database, runs migrations,
-// and returns a ready-to-use store and the underlying *sql.DB.
-func openDefaultStore() (*storage.SQLiteStore, *sql.DB, error) {
-	dbPath := defaultDBPath()
+// openStore opens the database selected by the global flags (see
+// resolveDBPath), runs migrations, and returns a ready-to-use store and the
+// underlying *sql.DB.
+func openStore(globals *GlobalFlags) (*storage.SQLiteStore, *sql.DB, error) {
+	dbPath, err := resolveDBPath(globals)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := loadConfig(globals)
+	limit, err := bodyLimit(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	store, db, err := openStoreAt(dbPath, storagePragmas(cfg))
+	if err != nil {
+		return nil, nil, err
+	}
+	store.SetBodyLimit(limit)
+	store.SetDedupeWindow(time.Duration(cfg.Capture.DedupeIntervalSeconds) * time.Second)
+	if err := setupEncryption(store, db, cfg.Storage.Encryption); err != nil {
+		store.Close()
+		db.Close()
+		return nil, nil, err
+	}
+	if err := setupVectorStore(store, cfg, filepath.Dir(dbPath)); err != nil {
+		store.Close()
+		db.Close()
+		return nil, nil, err
+	}
+	return store, db, nil
+}
+
+// setupVectorStore opens the storage.vector_store backend for store's
+// embeddings. sqlite, the default, keeps them in the database itself, so
+// needs nothing. lancedb has no backend yet; a config naming it is refused
+// only with embeddings enabled, since otherwise no vectors are made.
+func setupVectorStore(store *storage.SQLiteStore, cfg *config.Config, dbDir string) error {
+	switch cfg.Storage.VectorStore {
+	case "", "sqlite":
+		return nil
+	case "lancedb":
+		if cfg.Embeddings.Enabled {
+			return fmt.Errorf("storage.vector_store lancedb is not supported yet; set it to sqlite or flat")
+		}
+		return nil
+	case "flat":
+		dir, err := cfg.VectorPath(dbDir)
+		if err != nil {
+			return fmt.Errorf("resolve vector directory: %w", err)
+		}
+		vs, err := vectorstore.OpenFlat(dir)
+		if err != nil {
+			return err
+		}
+		return store.SetVectorStore(context.Background(), vs)
+	default:
+		return fmt.Errorf("invalid storage.vector_store %q (want sqlite or flat)", cfg.Storage.VectorStore)
+	}
+}
+
+// setupEncryption gives store the cipher for its bodies. An encrypted
+// database always needs the passphrase, whatever the config says; a
+// plaintext one is set up for encryption when storage.encryption.enabled
+// is on, and bodies stored before that stay plaintext until
+// migrate-encrypt.
+func setupEncryption(store *storage.SQLiteStore, db *sql.DB, enc config.EncryptionConfig) error {
+	ctx := context.Background()
+	encrypted, err := storage.Encrypted(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !encrypted && !enc.Enabled {
+		return nil
+	}
+	passphrase, err := encryptionPassphrase(enc)
+	if err != nil {
+		return err
+	}
+	var c *encrypt.Cipher
+	if encrypted {
+		c, err = storage.UnlockEncryption(ctx, db, passphrase)
+	} else {
+		c, err = storage.InitEncryption(ctx, db, passphrase)
+	}
+	if err != nil {
+		return err
+	}
+	store.SetCipher(c)
+	return nil
+}
+
+// encryptionPassphrase reads the passphrase from where
+// storage.encryption.key_source says it is kept.
+func encryptionPassphrase(enc config.EncryptionConfig) (string, error) {
+	switch enc.KeySource {
+	case "", "passphrase":
+		passphrase := os.Getenv(enc.PassphraseEnv)
+		if enc.PassphraseEnv == "" || passphrase == "" {
+			return "", fmt.Errorf("database encryption needs a passphrase: set $%s", enc.PassphraseEnv)
+		}
+		return passphrase, nil
+	case "keychain":
+		passphrase, err := keychain.Lookup(enc.KeychainService)
+		if err != nil {
+			return "", fmt.Errorf("read encryption passphrase from keychain: %w", err)
+		}
+		return passphrase, nil
+	default:
+		return "", fmt.Errorf("invalid storage.encryption.key_source %q (want passphrase or keychain)", enc.KeySource)
+	}
+}
+
+// bodyLimit maps capture.max_body_bytes and capture.body_overflow to a
+// storage body limit.
+func bodyLimit(cfg *config.Config) (storage.BodyLimit, error) {
+	limit := storage.BodyLimit{MaxBytes: cfg.Capture.MaxBodyBytes}
+	switch cfg.Capture.BodyOverflow {
+	case "", "truncate":
+	case "reject":
+		limit.Reject = true
+	default:
+		return limit, fmt.Errorf("invalid capture.body_overflow %q (want truncate or reject)", cfg.Capture.BodyOverflow)
+	}
+	return limit, nil
+}
+
+// storagePragmas maps the storage config section to SQLite pragmas.
+func storagePragmas(cfg *config.Config) storage.Pragmas {
+	return storage.Pragmas{
+		CacheSizeMB: cfg.Storage.SQLiteCacheSizeMB,
+		MmapSizeMB:  cfg.Storage.SQLiteMmapSizeMB,
+		Synchronous: cfg.Storage.SQLiteSynchronous,
+		TempStore:   cfg.Storage.SQLiteTempStore,
+	}
+}
+
+// openStoreAt opens (creating if needed) and migrates the database at
+// dbPath, applying pragmas to every connection.
+func openStoreAt(dbPath string, pragmas storage.Pragmas) (*storage.SQLiteStore, *sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, nil, fmt.Errorf("create database directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	start := time.Now()
+	db, err := storage.OpenDB(dbPath, pragmas)
 	if err != nil {
 		return nil, nil, fmt.Errorf("open database: %w", err)
 	}
@@ -62,10 +309,11 @@ func openDefaultStore() (*storage.SQLiteStore, *sql.DB, error) {
 		return nil, nil, fmt.Errorf("create store: %w", err)
 	}
 
+	slog.Debug("database opened", "path", dbPath, "elapsed", time.Since(start))
 	return store, db, nil
 }
 
-// parseDuration parses a human-friendly duration string like "30d", "7d", "24h", "2w".
+// parseDuration parses a human-friendly duration string like "30d", "7d", "24h", "2w", "1y".
 func parseDuration(s string) (time.Duration, error) {
 	if s == "" {
 		return 0, fmt.Errorf("invalid duration: empty string")
@@ -90,15 +338,55 @@ func parseDuration(s string) (time.Duration, error) {
 		return time.Duration(n) * time.Hour, nil
 	case 'w':
 		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	case 'y':
+		return time.Duration(n) * 365 * 24 * time.Hour, nil
 	case 'm':
 		return time.Duration(n) * time.Minute, nil
 	case 's':
 		return time.Duration(n) * time.Second, nil
 	default:
-		return 0, fmt.Errorf("invalid duration: %q (use d, h, w, m, or s suffix)", s)
+		return 0, fmt.Errorf("invalid duration: %q (use y, w, d, h, m, or s suffix)", s)
 	}
 }
 
+// resolveTimeRange converts --since/--until values (see parseTimeSpec) into
+// absolute times relative to now. Empty strings yield zero times (no bound).
+// Two forms of --until read differently: a date alone includes that whole
+// day, and a duration is a span measured forward from --since, so
+// "--since 2025-01-01 --until 7d" covers the first week of January.
+func resolveTimeRange(sinceStr, untilStr string, now time.Time) (since, until time.Time, err error) {
+	if sinceStr != "" {
+		if since, err = parseTimeSpec(sinceStr, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since value %q: %w", sinceStr, err)
+		}
+	}
+
+	switch {
+	case untilStr == "":
+	case isDurationSpec(untilStr):
+		if since.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until value %q: a duration is measured from --since, so give --since too (or use a date or a phrase like \"7 days ago\")", untilStr)
+		}
+		dur, err := parseDuration(strings.TrimSpace(untilStr))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until value %q: %w", untilStr, err)
+		}
+		until = since.Add(dur)
+	default:
+		if until, err = parseTimeSpec(untilStr, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until value %q: %w", untilStr, err)
+		}
+		if isDateSpec(untilStr) {
+			until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--until %q is before --since %q", untilStr, sinceStr)
+	}
+	return since, until, nil
+}
+
 // formatDurationHuman formats a duration into a human-readable string like "30 days".
 func formatDurationHuman(d time.Duration) string {
 	days := int(d.Hours() / 24)
@@ -117,3 +405,46 @@ func formatDurationHuman(d time.Duration) string {
 	}
 	return d.String()
 }
+
+// formatReadTime formats an estimated reading time in whole minutes, the
+// way articles state it: "4 min read", or "<1 min read" for short bodies.
+func formatReadTime(d time.Duration) string {
+	if d < time.Minute {
+		return "<1 min read"
+	}
+	return fmt.Sprintf("%d min read", int(d.Round(time.Minute)/time.Minute))
+}
+
+// bodyBatchSize is how many bodies eachWithBody loads per query, bounding
+// memory while avoiding a lookup per event.
+const bodyBatchSize = 100
+
+// eachWithBody calls fn for each event in order with its captured body
+// ("" if none), loading bodies bodyBatchSize events at a time.
+func eachWithBody(ctx context.Context, store *storage.SQLiteStore, events []storage.Event, fn func(e *storage.Event, body string) error) error {
+	for len(events) > 0 {
+		batch := events[:min(len(events), bodyBatchSize)]
+		events = events[len(batch):]
+
+		var ids []string
+		for _, e := range batch {
+			if e.HasBody {
+				ids = append(ids, e.ID)
+			}
+		}
+		contents, err := store.GetContents(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			body := ""
+			if c, ok := contents[batch[i].ID]; ok {
+				body = c.Body
+			}
+			if err := fn(&batch[i], body); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -1,11 +1,194 @@
 package cli
 
-import "fmt"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/daemon"
+	"github.com/runnerr0/chronicle/internal/logging"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// daemonStartTimeout bounds how long ingest waits for a daemon started in
+// the background to answer.
+const daemonStartTimeout = 5 * time.Second
 
 // Execute implements the go-flags Commander interface for IngestCommand.
 func (c *IngestCommand) Execute(args []string) error {
-	// TODO: Implement daemon start (P1-7)
-	fmt.Println("Chronicle daemon starting... (stub)")
-	fmt.Println("Not yet implemented. See Phase 1 tasks.")
+	if c.LogLevel != "" {
+		if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+			return err
+		}
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid --port %d", c.Port)
+	}
+	cfg := loadConfig(c.globals)
+	if c.Port != 0 {
+		cfg.Daemon.Port = c.Port
+	}
+	if err := c.ensureToken(cfg); err != nil {
+		return err
+	}
+	if !c.Foreground {
+		return c.detach(cfg)
+	}
+	if c.LogLevel != "" {
+		defer startLogging(c.globals, c.LogLevel)()
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	addr := daemonAddr(cfg)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	noticef(c.globals, "Chronicle daemon listening on http://%s\n", addr)
+	return c.serve(ctx, ln, store, db, cfg)
+}
+
+// serve runs the daemon on ln, with the configured jobs on their
+// schedule, until ctx is cancelled.
+func (c *IngestCommand) serve(ctx context.Context, ln net.Listener, store *storage.SQLiteStore, db *sql.DB, cfg *config.Config) error {
+	jobs, err := buildScheduler(cfg, store, db)
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	srv := daemon.NewServer(store, cfg.Daemon.AuthToken)
+	srv.SetMaxRequestSize(cfg.Daemon.MaxRequestSize)
+	if err := srv.SetCapturePolicy(cfg.Capture); err != nil {
+		return err
+	}
+	// Webhooks and notifications run after the extension has its answer.
+	pipeline := newPipeline(c.globals, store, cfg.Hooks, cfg.Webhooks, cfg.Notifications)
+	pipeline.SetAsync(true)
+	defer pipeline.Wait()
+	srv.SetPipeline(pipeline)
+	srv.SetJobs(jobs)
+
+	httpSrv := &http.Server{
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Run(ctx)
+		close(jobsDone)
+	}()
+	defer func() { <-jobsDone }()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpSrv.Serve(ln) }()
+	slog.Info("daemon listening", "addr", ln.Addr().String(), "jobs", len(jobs.Status()))
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("daemon: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		// Connections still busy after the grace period are cut off.
+		slog.Warn("daemon shutdown timed out; closing connections", "err", err)
+		return httpSrv.Close()
+	}
+	return nil
+}
+
+// ensureToken generates daemon.auth_token and saves it to the config file
+// when none is set, so the daemon never takes captures from callers that
+// cannot read the config.
+func (c *IngestCommand) ensureToken(cfg *config.Config) error {
+	if cfg.Daemon.AuthToken != "" {
+		return nil
+	}
+	token, err := randomToken()
+	if err != nil {
+		return fmt.Errorf("generate daemon token: %w", err)
+	}
+	path, err := configFilePath(c.globals)
+	if err != nil {
+		return err
+	}
+	if err := config.Set(path, "daemon.auth_token", token); err != nil {
+		return fmt.Errorf("save daemon token: %w", err)
+	}
+	cfg.Daemon.AuthToken = token
+	noticef(c.globals, "Generated daemon.auth_token %s (saved to %s); enter it in your browser extension\n", token, path)
 	return nil
 }
+
+// detach starts the daemon as a background process running this command
+// with --foreground, and waits until it answers.
+func (c *IngestCommand) detach(cfg *config.Config) error {
+	addr := daemonAddr(cfg)
+	if checkDaemon(cfg) {
+		return fmt.Errorf("a daemon is already running on %s", addr)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find chronicle executable: %w", err)
+	}
+	args := append(append([]string{}, os.Args[1:]...), "--foreground")
+	cmd := exec.Command(exe, args...)
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	deadline := time.After(daemonStartTimeout)
+	for {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("the daemon exited during startup (%v); run chronicle ingest --foreground to see why", err)
+		case <-deadline:
+			return fmt.Errorf("the daemon (pid %d) did not answer on %s within %s", cmd.Process.Pid, addr, daemonStartTimeout)
+		case <-tick.C:
+			if checkDaemon(cfg) {
+				infof(c.globals, "Chronicle daemon started (pid %d), listening on http://%s\n", cmd.Process.Pid, addr)
+				return cmd.Process.Release()
+			}
+		}
+	}
+}
+
+// daemonAddr is the host:port the daemon listens on.
+func daemonAddr(cfg *config.Config) string {
+	return net.JoinHostPort(cfg.Daemon.Host, strconv.Itoa(cfg.Daemon.Port))
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestIngest_ServesCapturesAndJobs(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cfg := config.DefaultConfig()
+	cfg.Daemon.AuthToken = "secret"
+	cfg.Jobs = []config.JobConfig{{Name: "nightly-prune", Type: "prune", Schedule: "0 3 * * *"}}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	base := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- (&IngestCommand{globals: &GlobalFlags{Quiet: true}}).serve(ctx, ln, store, nil, cfg) }()
+
+	body, _ := json.Marshal(map[string]string{"url": "https://go.dev/blog/", "title": "The Go Blog", "browser": "chrome"})
+	req, err := http.NewRequest(http.MethodPost, base+"/events", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Source: "extension", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "chrome", events[0].Browser)
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	cfg.Daemon.Host = host
+	cfg.Daemon.Port, _ = strconv.Atoi(port)
+	assert.True(t, checkDaemon(cfg))
+	jobs, err := fetchDaemonJobs(cfg)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "nightly-prune", jobs[0].Name)
+
+	// Idle keep-alive connections, including any the transport dialed
+	// but never used, would hold up the daemon's graceful shutdown.
+	http.DefaultClient.CloseIdleConnections()
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop")
+	}
+	assert.False(t, checkDaemon(cfg))
+}
+
+func TestIngest_GeneratesToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	c := &IngestCommand{globals: &GlobalFlags{Config: path, Quiet: true}}
+
+	cfg := config.DefaultConfig()
+	require.NoError(t, c.ensureToken(cfg))
+	assert.Len(t, cfg.Daemon.AuthToken, 32)
+	saved, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Daemon.AuthToken, saved.Daemon.AuthToken)
+
+	// Later starts keep the saved token.
+	require.NoError(t, c.ensureToken(saved))
+	assert.Equal(t, cfg.Daemon.AuthToken, saved.Daemon.AuthToken)
+}
+
+func TestIngest_RejectsBadJobs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Jobs = []config.JobConfig{{Type: "reindex", Schedule: "@daily"}}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	err = (&IngestCommand{globals: &GlobalFlags{}}).serve(context.Background(), ln, nil, nil, cfg)
+	assert.ErrorContains(t, err, `unknown job type "reindex"`)
+}
+
+func TestIngest_Flags(t *testing.T) {
+	err := (&IngestCommand{LogLevel: "loud", globals: &GlobalFlags{}}).Execute(nil)
+	assert.ErrorContains(t, err, `invalid log level "loud"`)
+
+	err = (&IngestCommand{Port: 70000, globals: &GlobalFlags{}}).Execute(nil)
+	assert.EqualError(t, err, "invalid --port 70000")
+}
+
+func TestIngest_AlreadyRunning(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	cfg := config.DefaultConfig()
+	cfg.Daemon.Host = host
+	cfg.Daemon.Port, _ = strconv.Atoi(port)
+
+	err = (&IngestCommand{globals: &GlobalFlags{}}).detach(cfg)
+	assert.ErrorContains(t, err, "a daemon is already running on "+srv.Listener.Addr().String())
+}
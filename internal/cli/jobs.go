@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/replicate"
+	"github.com/runnerr0/chronicle/internal/scheduler"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// jobsListJSON is the JSON output structure for jobs list.
+type jobsListJSON struct {
+	DaemonRunning bool                  `json:"daemon_running"`
+	Jobs          []scheduler.JobStatus `json:"jobs"`
+}
+
+// retentionJobName names the prune job added when none is configured.
+const retentionJobName = "retention"
+
+// buildScheduler schedules the jobs configured under jobs against store
+// and db. Jobs without a name take their type's. Unless a prune job is
+// configured, retention is applied by one named retention every
+// retention.prune_interval_hours, if set (see pruneJob).
+func buildScheduler(cfg *config.Config, store *storage.SQLiteStore, db *sql.DB) (*scheduler.Scheduler, error) {
+	s := scheduler.New()
+	for _, jc := range cfg.Jobs {
+		name := jc.Name
+		if name == "" {
+			name = jc.Type
+		}
+		run, err := jobFunc(jc, cfg, store, db)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", name, err)
+		}
+		if err := s.Add(name, jc.Type, jc.Schedule, run); err != nil {
+			return nil, err
+		}
+	}
+
+	jc, implicit, ok := pruneJob(cfg)
+	if !ok || !implicit {
+		return s, nil
+	}
+	var last *storage.PruneRecord
+	if store != nil {
+		var err error
+		if last, err = store.LastPrune(context.Background()); err != nil {
+			return nil, fmt.Errorf("read last prune: %w", err)
+		}
+	}
+	first, err := nextPrune(jc, implicit, last, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if first.IsZero() {
+		first = time.Now()
+	}
+	run, err := jobFunc(jc, cfg, store, db)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AddFirst(jc.Name, jc.Type, jc.Schedule, first, run); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// pruneJob returns the job that applies retention in the daemon: the
+// first prune job configured, else (implicit) the retention job every
+// retention.prune_interval_hours. ok is false when there is neither, and
+// retention is only applied by chronicle prune.
+func pruneJob(cfg *config.Config) (jc config.JobConfig, implicit, ok bool) {
+	for _, jc := range cfg.Jobs {
+		if jc.Type == "prune" {
+			if jc.Name == "" {
+				jc.Name = jc.Type
+			}
+			return jc, false, true
+		}
+	}
+	if hours := cfg.Retention.PruneIntervalHours; hours > 0 {
+		return config.JobConfig{Name: retentionJobName, Type: "prune", Schedule: fmt.Sprintf("@every %dh", hours)}, true, true
+	}
+	return config.JobConfig{}, false, false
+}
+
+// nextPrune returns when jc, from pruneJob, next runs. A configured job
+// runs when its schedule next falls due; the implicit one an interval
+// after the last prune, or as soon as the daemon starts if there has been
+// none, for which it returns the zero time.
+func nextPrune(jc config.JobConfig, implicit bool, last *storage.PruneRecord, now time.Time) (time.Time, error) {
+	sched, err := scheduler.Parse(jc.Schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("job %s: %w", jc.Name, err)
+	}
+	switch {
+	case !implicit:
+		return sched.Next(now), nil
+	case last != nil:
+		return sched.Next(last.At), nil
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// jobFunc returns the function that runs a job of jc's type.
+func jobFunc(jc config.JobConfig, cfg *config.Config, store *storage.SQLiteStore, db *sql.DB) (scheduler.RunFunc, error) {
+	switch jc.Type {
+	case "prune":
+		return func(ctx context.Context) (string, error) {
+			days := retentionDays(cfg)
+			pruned, err := store.PruneExpired(ctx, time.Now().Add(-time.Duration(days)*24*time.Hour))
+			if err != nil {
+				return "", fmt.Errorf("prune failed: %w", err)
+			}
+			if err := store.RecordPrune(ctx, storage.PruneRecord{At: time.Now(), Count: pruned}); err != nil {
+				return "", fmt.Errorf("record prune: %w", err)
+			}
+			notifyPrune(nil, cfg.Notifications, pruned)
+			return fmt.Sprintf("pruned %d events older than %d days", pruned, days), nil
+		}, nil
+
+	case "vacuum":
+		return func(ctx context.Context) (string, error) {
+			if err := store.Vacuum(ctx); err != nil {
+				return "", err
+			}
+			return "vacuumed the database", nil
+		}, nil
+
+	case "backup":
+		if cfg.Storage.ReplicaURL == "" {
+			return nil, fmt.Errorf("backup needs storage.replica_url")
+		}
+		replica, err := replicate.Open(cfg.Storage.ReplicaURL)
+		if err != nil {
+			return nil, err
+		}
+		r := replicate.New(db, replica, cfg.Storage.ReplicaRetain)
+		return func(ctx context.Context) (string, error) {
+			snap, uploaded, err := r.Snapshot(ctx, time.Now())
+			if err != nil {
+				return "", err
+			}
+			if !uploaded {
+				return "no changes since the last snapshot", nil
+			}
+			return "snapshot " + snap.Name, nil
+		}, nil
+
+	case "embed":
+		embedder, err := newEmbedder(cfg.Embeddings)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (string, error) {
+			events, err := store.EventsWithoutEmbedding(ctx, embedder.Model(), cfg.Embeddings.ContentOnly, 0)
+			if err != nil {
+				return "", err
+			}
+			embedded, _, err := embedEvents(ctx, store, embedder, events, cfg.Embeddings.BatchSize, func(int) {})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("embedded %d events", embedded), nil
+		}, nil
+
+	case "digest":
+		dir := jc.Options["dir"]
+		if dir == "" {
+			return nil, fmt.Errorf("digest needs the dir option")
+		}
+		period := jc.Options["period"]
+		if period == "" {
+			period = "week"
+		}
+		if _, err := digestPeriod(period); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (string, error) {
+			now := time.Now()
+			d := &DigestCommand{
+				Period:  period,
+				Output:  filepath.Join(dir, fmt.Sprintf("chronicle-digest-%s-%s.md", period, now.Local().Format("2006-01-02"))),
+				Pattern: jc.Options["pattern"],
+				Limit:   1000,
+				globals: &GlobalFlags{Quiet: true},
+			}
+			if err := d.executeWithStore(store, cfg, now); err != nil {
+				return "", err
+			}
+			return "wrote " + d.Output, nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown job type %q (want prune, backup, vacuum, embed or digest)", jc.Type)
+	}
+}
+
+// Execute implements the go-flags Commander interface for JobsListCommand.
+func (c *JobsListCommand) Execute(args []string) error {
+	cfg := loadConfig(c.globals)
+	return c.executeWithConfig(cfg, time.Now())
+}
+
+// executeWithConfig lists the jobs in cfg (used by tests). A running
+// daemon's view wins, since it has the last results.
+func (c *JobsListCommand) executeWithConfig(cfg *config.Config, now time.Time) error {
+	sched, err := buildScheduler(cfg, nil, nil)
+	if err != nil {
+		return err
+	}
+	out := jobsListJSON{Jobs: sched.Status()}
+	if live, err := fetchDaemonJobs(cfg); err == nil {
+		out.DaemonRunning, out.Jobs = true, live
+	}
+	if out.Jobs == nil {
+		out.Jobs = []scheduler.JobStatus{}
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(out.Jobs) == 0 {
+		fmt.Println("No jobs configured (add them under jobs in the config).")
+		return nil
+	}
+	fmt.Printf("%-16s %-7s %-14s %-17s %s\n", "NAME", "TYPE", "SCHEDULE", "NEXT", "LAST")
+	for _, j := range out.Jobs {
+		fmt.Printf("%-16s %-7s %-14s %-17s %s\n", j.Name, j.Type, j.Schedule, describeJobNext(j, now), describeJobLast(j))
+	}
+	if !out.DaemonRunning {
+		fmt.Println()
+		fmt.Println("The daemon is not running; jobs only run on schedule while chronicle ingest is up.")
+	}
+	return nil
+}
+
+// describeJobNext renders a job's next run for human output.
+func describeJobNext(j scheduler.JobStatus, now time.Time) string {
+	switch {
+	case j.Running:
+		return "running"
+	case j.Next == nil:
+		return "never"
+	case !j.Next.After(now):
+		return "due now"
+	default:
+		return j.Next.Local().Format("2006-01-02 15:04")
+	}
+}
+
+// describeJobLast renders a job's last run for human output.
+func describeJobLast(j scheduler.JobStatus) string {
+	if j.LastRun == nil {
+		return "never"
+	}
+	at := j.LastRun.Local().Format("2006-01-02 15:04")
+	if j.LastError != "" {
+		return at + " failed: " + j.LastError
+	}
+	return at + " ok: " + j.LastResult
+}
+
+// fetchDaemonJobs asks the daemon configured in cfg for its jobs' status.
+func fetchDaemonJobs(cfg *config.Config) ([]scheduler.JobStatus, error) {
+	req, err := http.NewRequest(http.MethodGet, "http://"+daemonAddr(cfg)+"/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Daemon.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Daemon.AuthToken)
+	}
+	client := &http.Client{Timeout: 1 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("daemon status: %s", resp.Status)
+	}
+	var status struct {
+		Jobs []scheduler.JobStatus `json:"jobs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("daemon status: %w", err)
+	}
+	return status.Jobs, nil
+}
+
+// Execute implements the go-flags Commander interface for JobsRunCommand.
+func (c *JobsRunCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, db, loadConfig(c.globals))
+}
+
+// executeWithStore runs the job against a provided store (used by tests).
+func (c *JobsRunCommand) executeWithStore(store *storage.SQLiteStore, db *sql.DB, cfg *config.Config) error {
+	sched, err := buildScheduler(cfg, store, db)
+	if err != nil {
+		return err
+	}
+	st, err := sched.RunNow(context.Background(), c.Args.Name)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(st); err != nil {
+			return err
+		}
+	}
+	if st.LastError != "" {
+		return fmt.Errorf("job %s failed: %s", st.Name, st.LastError)
+	}
+	if c.globals == nil || !c.globals.JSON {
+		infof(c.globals, "Ran %s in %s: %s\n", st.Name, st.LastDuration, st.LastResult)
+	}
+	return nil
+}
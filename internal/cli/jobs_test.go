@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/scheduler"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestBuildScheduler_ValidatesJobs(t *testing.T) {
+	tests := []struct {
+		job  config.JobConfig
+		want string
+	}{
+		{config.JobConfig{Type: "reindex", Schedule: "@daily"}, `job reindex: unknown job type "reindex"`},
+		{config.JobConfig{Name: "weekly", Type: "digest", Schedule: "@weekly"}, "job weekly: digest needs the dir option"},
+		{config.JobConfig{Type: "digest", Schedule: "@weekly", Options: map[string]string{"dir": "/tmp", "period": "fortnight"}}, "fortnight"},
+		{config.JobConfig{Type: "backup", Schedule: "@daily"}, "backup needs storage.replica_url"},
+		{config.JobConfig{Type: "vacuum", Schedule: "every sunday"}, "invalid schedule"},
+		{config.JobConfig{Type: "embed", Schedule: "@hourly"}, "embeddings are disabled"},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.Jobs = []config.JobConfig{tt.job}
+		_, err := buildScheduler(cfg, nil, nil)
+		assert.ErrorContains(t, err, tt.want)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Jobs = []config.JobConfig{
+		{Type: "prune", Schedule: "0 3 * * *"},
+		{Type: "vacuum", Schedule: "0 4 * * 0"},
+	}
+	sched, err := buildScheduler(cfg, nil, nil)
+	require.NoError(t, err)
+	jobs := sched.Status()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "prune", jobs[0].Name)
+	assert.Equal(t, "vacuum", jobs[1].Name)
+}
+
+func TestBuildScheduler_ImplicitRetentionJob(t *testing.T) {
+	_, store := setupPruneTest(t, 0, 0)
+	cfg := config.DefaultConfig()
+	cfg.Jobs = []config.JobConfig{{Type: "vacuum", Schedule: "@weekly"}}
+
+	sched, err := buildScheduler(cfg, store, nil)
+	require.NoError(t, err)
+	jobs := sched.Status()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "retention", jobs[0].Name)
+	assert.Equal(t, "prune", jobs[0].Type)
+	assert.Equal(t, "@every 24h", jobs[0].Schedule)
+	require.NotNil(t, jobs[0].Next)
+	assert.WithinDuration(t, time.Now(), *jobs[0].Next, time.Minute, "never pruned, so it runs on start")
+
+	last := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, store.RecordPrune(context.Background(), storage.PruneRecord{At: last, Count: 1}))
+	sched, err = buildScheduler(cfg, store, nil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, last.Add(24*time.Hour), *sched.Status()[0].Next, time.Second)
+
+	cfg.Retention.PruneIntervalHours = 0
+	sched, err = buildScheduler(cfg, store, nil)
+	require.NoError(t, err)
+	assert.Len(t, sched.Status(), 1)
+}
+
+func TestJobsRun_PrunesAndVacuums(t *testing.T) {
+	_, store := setupPruneTest(t, 5, 3)
+	cfg := config.DefaultConfig()
+	cfg.Jobs = []config.JobConfig{
+		{Name: "nightly-prune", Type: "prune", Schedule: "0 3 * * *"},
+		{Type: "vacuum", Schedule: "@weekly"},
+	}
+
+	output := captureOutput(t, func() {
+		cmd := &JobsRunCommand{globals: &GlobalFlags{}}
+		cmd.Args.Name = "nightly-prune"
+		require.NoError(t, cmd.executeWithStore(store, nil, cfg))
+	})
+	assert.Contains(t, output, "Ran nightly-prune in")
+	assert.Contains(t, output, "pruned 5 events older than 30 days")
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalEvents)
+
+	output = captureOutput(t, func() {
+		cmd := &JobsRunCommand{globals: &GlobalFlags{}}
+		cmd.Args.Name = "vacuum"
+		require.NoError(t, cmd.executeWithStore(store, nil, cfg))
+	})
+	assert.Contains(t, output, "vacuumed the database")
+
+	cmd := &JobsRunCommand{globals: &GlobalFlags{}}
+	cmd.Args.Name = "backup"
+	assert.ErrorContains(t, cmd.executeWithStore(store, nil, cfg), `no job named "backup"`)
+}
+
+// unusedPort returns a local port nothing is listening on.
+func unusedPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	return port
+}
+
+func TestJobsList_DaemonNotRunning(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Daemon.Host, cfg.Daemon.Port = "127.0.0.1", unusedPort(t)
+	cfg.Jobs = []config.JobConfig{{Name: "nightly-prune", Type: "prune", Schedule: "0 3 * * *"}}
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	output := captureOutput(t, func() {
+		require.NoError(t, (&JobsListCommand{globals: &GlobalFlags{}}).executeWithConfig(cfg, now))
+	})
+	assert.Contains(t, output, "nightly-prune")
+	assert.Contains(t, output, "0 3 * * *")
+	assert.Contains(t, output, "never")
+	assert.Contains(t, output, "The daemon is not running")
+
+	cfg.Jobs = nil
+	output = captureOutput(t, func() {
+		require.NoError(t, (&JobsListCommand{globals: &GlobalFlags{}}).executeWithConfig(cfg, now))
+	})
+	assert.Contains(t, output, "retention")
+	assert.Contains(t, output, "@every 24h")
+
+	cfg.Retention.PruneIntervalHours = 0
+	output = captureOutput(t, func() {
+		require.NoError(t, (&JobsListCommand{globals: &GlobalFlags{}}).executeWithConfig(cfg, now))
+	})
+	assert.Contains(t, output, "No jobs configured")
+}
+
+func TestJobsList_UsesDaemonStatus(t *testing.T) {
+	last := time.Date(2026, 3, 2, 3, 0, 0, 0, time.Local)
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"jobs": []scheduler.JobStatus{{
+				Name: "nightly-prune", Type: "prune", Schedule: "0 3 * * *",
+				Runs: 1, LastRun: &last, LastResult: "pruned 12 events older than 30 days",
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	cfg := config.DefaultConfig()
+	cfg.Daemon.Host = host
+	cfg.Daemon.Port, _ = strconv.Atoi(port)
+	cfg.Daemon.AuthToken = "secret"
+	cfg.Jobs = []config.JobConfig{{Name: "nightly-prune", Type: "prune", Schedule: "0 3 * * *"}}
+
+	output := captureOutput(t, func() {
+		require.NoError(t, (&JobsListCommand{globals: &GlobalFlags{JSON: true}}).executeWithConfig(cfg, time.Now()))
+	})
+	assert.Equal(t, "Bearer secret", gotAuth)
+
+	var out jobsListJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.True(t, out.DaemonRunning)
+	require.Len(t, out.Jobs, 1)
+	assert.Equal(t, 1, out.Jobs[0].Runs)
+	assert.Equal(t, "pruned 12 events older than 30 days", out.Jobs[0].LastResult)
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// alfredItem is one row of Alfred's Script Filter JSON format.
+type alfredItem struct {
+	UID          string      `json:"uid,omitempty"`
+	Title        string      `json:"title"`
+	Subtitle     string      `json:"subtitle"`
+	Arg          string      `json:"arg,omitempty"`
+	Autocomplete string      `json:"autocomplete,omitempty"`
+	Valid        *bool       `json:"valid,omitempty"`
+	QuickLookURL string      `json:"quicklookurl,omitempty"`
+	Icon         *alfredIcon `json:"icon,omitempty"`
+	Text         *alfredText `json:"text,omitempty"`
+}
+
+type alfredIcon struct {
+	Path string `json:"path"`
+}
+
+type alfredText struct {
+	Copy      string `json:"copy"`
+	LargeType string `json:"largetype"`
+}
+
+// alfredIconPath is resolved by Alfred relative to the workflow folder, so
+// workflows can ship their own icon under this name.
+const alfredIconPath = "icon.png"
+
+// printAlfred writes results as Alfred Script Filter JSON. With no results
+// a single non-actionable item says so, since Alfred otherwise falls back
+// to its default web searches.
+func printAlfred(query string, results []storage.Event) error {
+	items := make([]alfredItem, 0, len(results))
+	for _, e := range results {
+		items = append(items, alfredItem{
+			UID:          e.ID,
+			Title:        launcherTitle(e),
+			Subtitle:     launcherSubtitle(e),
+			Arg:          e.PageURL(),
+			Autocomplete: e.Title,
+			QuickLookURL: e.PageURL(),
+			Icon:         &alfredIcon{Path: alfredIconPath},
+			Text:         &alfredText{Copy: e.PageURL(), LargeType: e.PageURL()},
+		})
+	}
+	if len(items) == 0 {
+		invalid := false
+		items = append(items, alfredItem{
+			Title:    "No results",
+			Subtitle: launcherEmptySubtitle(query),
+			Valid:    &invalid,
+			Icon:     &alfredIcon{Path: alfredIconPath},
+		})
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(struct {
+		Items []alfredItem `json:"items"`
+	}{items})
+}
+
+// raycastItem is one row of the list JSON read by Raycast script-filter
+// extensions, which mirrors Raycast's List.Item properties.
+type raycastItem struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Subtitle    string             `json:"subtitle"`
+	Arg         string             `json:"arg"`
+	Icon        string             `json:"icon"`
+	Accessories []raycastAccessory `json:"accessories,omitempty"`
+}
+
+type raycastAccessory struct {
+	Text string `json:"text"`
+}
+
+// raycastIcon is a Raycast built-in icon name.
+const raycastIcon = "globe-16"
+
+// printRaycast writes results as Raycast list JSON. An empty list lets
+// Raycast show its own empty view.
+func printRaycast(results []storage.Event) error {
+	items := make([]raycastItem, 0, len(results))
+	for _, e := range results {
+		items = append(items, raycastItem{
+			ID:          e.ID,
+			Title:       launcherTitle(e),
+			Subtitle:    e.Domain,
+			Arg:         e.PageURL(),
+			Icon:        raycastIcon,
+			Accessories: []raycastAccessory{{Text: e.Timestamp.Local().Format("2006-01-02")}},
+		})
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(struct {
+		Items []raycastItem `json:"items"`
+	}{items})
+}
+
+// launcherTitle is the event title, falling back to the URL for pages
+// captured without one.
+func launcherTitle(e storage.Event) string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return e.PageURL()
+}
+
+// launcherSubtitle is a one-line "domain · date" summary.
+func launcherSubtitle(e storage.Event) string {
+	sub := e.Timestamp.Local().Format("2006-01-02 15:04")
+	if e.Domain != "" {
+		sub = e.Domain + " · " + sub
+	}
+	return sub
+}
+
+func launcherEmptySubtitle(query string) string {
+	if query == "" {
+		return "Nothing in your history matches"
+	}
+	return fmt.Sprintf("Nothing in your history matches %q", query)
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+type linkJSON struct {
+	URL  string `json:"url"`
+	Text string `json:"text,omitempty"`
+}
+
+type linkingEventJSON struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Domain    string `json:"domain"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Execute implements the go-flags Commander interface for LinksCommand.
+func (c *LinksCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store)
+}
+
+// executeWithStore lists links from a provided store (used by tests).
+func (c *LinksCommand) executeWithStore(store *storage.SQLiteStore) error {
+	ctx := context.Background()
+	switch {
+	case (c.ID == "") == (c.To == ""):
+		return fmt.Errorf("exactly one of --id or --to is required")
+	case c.ID != "":
+		return c.linksFrom(ctx, store)
+	default:
+		return c.linksTo(ctx, store)
+	}
+}
+
+// linksFrom prints the outbound links of the --id event.
+func (c *LinksCommand) linksFrom(ctx context.Context, store *storage.SQLiteStore) error {
+	event, err := store.GetEvent(ctx, string(c.ID))
+	if err != nil {
+		return fmt.Errorf("event %w: %s", ErrNotFound, c.ID)
+	}
+	links, err := store.LinksFrom(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]linkJSON, len(links))
+		for i, l := range links {
+			out[i] = linkJSON{URL: l.URL, Text: l.Text}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(links) == 0 {
+		if event.HasBody {
+			infof(c.globals, "No links in %s (%s)\n", event.ID, event.Title)
+		} else {
+			infof(c.globals, "No body captured for %s (%s), so no links\n", event.ID, event.Title)
+		}
+		return nil
+	}
+	infof(c.globals, "%d links from %s (%s):\n\n", len(links), event.ID, event.Title)
+	for _, l := range links {
+		if l.Text != "" {
+			fmt.Printf("  %s\n    %s\n", l.Text, l.URL)
+		} else {
+			fmt.Printf("  %s\n", l.URL)
+		}
+	}
+	return nil
+}
+
+// linksTo prints the captured pages that link to --to.
+func (c *LinksCommand) linksTo(ctx context.Context, store *storage.SQLiteStore) error {
+	if c.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	events, err := store.LinksTo(ctx, c.To, c.Limit)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]linkingEventJSON, len(events))
+		for i, e := range events {
+			out[i] = linkingEventJSON{
+				ID:        e.ID,
+				URL:       e.URL,
+				Title:     e.Title,
+				Domain:    e.Domain,
+				Timestamp: e.Timestamp.UTC().Format(time.RFC3339),
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(events) == 0 {
+		infof(c.globals, "No captured pages link to %s\n", c.To)
+		return nil
+	}
+	infof(c.globals, "%d captured pages link to %s:\n\n", len(events), c.To)
+	for _, e := range events {
+		fmt.Printf("  %s  %s", e.Timestamp.Local().Format("2006-01-02 15:04"), e.Title)
+		if e.Domain != "" {
+			fmt.Printf(" — %s", e.Domain)
+		}
+		fmt.Printf("\n                    %s  %s\n", e.ID, e.URL)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestLinks(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+
+	post := &storage.Event{URL: "https://blog.example/post", Title: "A post", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, post, "Read [the FTS5 docs](https://sqlite.org/fts5.html) and https://go.dev/blog/."))
+	notes := &storage.Event{URL: "https://notes.example/", Title: "Notes", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, notes, `<a href="https://sqlite.org/fts5.html#overview">FTS5</a>`))
+	bare := &storage.Event{URL: "https://bare.example/", Title: "Bare", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, bare))
+
+	out := captureOutput(t, func() {
+		require.NoError(t, (&LinksCommand{ID: EventID(post.ID), globals: &GlobalFlags{}}).executeWithStore(store))
+	})
+	assert.Equal(t, "2 links from "+post.ID+" (A post):\n\n"+
+		"  the FTS5 docs\n    https://sqlite.org/fts5.html\n"+
+		"  https://go.dev/blog/\n", out)
+
+	out = captureOutput(t, func() {
+		require.NoError(t, (&LinksCommand{ID: EventID(bare.ID), globals: &GlobalFlags{}}).executeWithStore(store))
+	})
+	assert.Contains(t, out, "No body captured")
+
+	out = captureOutput(t, func() {
+		cmd := &LinksCommand{To: "https://sqlite.org/fts5.html", Limit: 50, globals: &GlobalFlags{JSON: true}}
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	var linking []linkingEventJSON
+	require.NoError(t, json.Unmarshal([]byte(out), &linking))
+	require.Len(t, linking, 2)
+	assert.ElementsMatch(t, []string{post.ID, notes.ID}, []string{linking[0].ID, linking[1].ID})
+
+	out = captureOutput(t, func() {
+		require.NoError(t, (&LinksCommand{To: "https://nowhere.example/", globals: &GlobalFlags{}}).executeWithStore(store))
+	})
+	assert.Equal(t, "No captured pages link to https://nowhere.example/\n", out)
+
+	assert.EqualError(t, (&LinksCommand{globals: &GlobalFlags{}}).executeWithStore(store), "exactly one of --id or --to is required")
+	err := (&LinksCommand{ID: "CHR-missing", globals: &GlobalFlags{}}).executeWithStore(store)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/runnerr0/chronicle/internal/logging"
+)
+
+// startLogging installs the default slog logger configured by the logging
+// section, writing next to the selected database so each profile keeps its
+// own log. level, if set, overrides logging.level; --verbose raises the
+// level to debug and also traces to stderr. It returns a func that closes the log and restores the previous logger.
+// Logging problems are reported but never stop the command.
+func startLogging(globals *GlobalFlags, level string) func() {
+	prev, prevOut, prevFlags := slog.Default(), log.Writer(), log.Flags()
+
+	dbPath, dbErr := resolveDBPath(globals)
+	dir := ""
+	if dbErr == nil {
+		dir = filepath.Dir(dbPath)
+	}
+	cfg := loadConfig(globals).Logging
+	if dir == "" && cfg.File != "stderr" && !filepath.IsAbs(cfg.File) {
+		cfg.File = ""
+	}
+	if level != "" {
+		cfg.Level = level
+	}
+	verbose := globals != nil && globals.Verbose
+	if verbose {
+		cfg.Level = "debug"
+	}
+
+	logger, closer, err := logging.Open(cfg, dir)
+	if err != nil {
+		noticef(globals, "Warning: logging disabled: %v\n", err)
+		return func() {}
+	}
+	if verbose && logging.Path(cfg, dir) != "stderr" {
+		stderr := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		logger = slog.New(logging.Tee(logger.Handler(), stderr))
+	}
+	slog.SetDefault(logger)
+
+	if dbErr != nil {
+		slog.Debug("database path unresolved", "err", dbErr)
+	} else {
+		slog.Debug("resolved database", "path", dbPath)
+	}
+	return func() {
+		// SetDefault also redirected the standard log package; undo that too.
+		slog.SetDefault(prev)
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+		closer.Close() //nolint:errcheck
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartLogging_WritesNextToDatabase(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("logging:\n  level: debug\n  format: json\n  file: chronicle.log\n"), 0o600))
+
+	prev := slog.Default()
+	stop := startLogging(&GlobalFlags{Config: cfgPath, DBPath: filepath.Join(dir, "db", "chronicle.db")}, "")
+	slog.Debug("resolved database", "path", "x")
+	stop()
+	assert.Same(t, prev, slog.Default(), "the previous logger is restored")
+
+	data, err := os.ReadFile(filepath.Join(dir, "db", "chronicle.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"resolved database"`)
+}
+
+func TestRunWithArgs_LogsFailedCommands(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("logging:\n  file: chronicle.log\n"), 0o600))
+
+	captureOutput(t, func() {
+		err := RunWithArgs("test", []string{"--config", cfgPath, "--db-path", filepath.Join(dir, "chronicle.db"), "add", "--url", "not a url", "--title", "x"})
+		require.Error(t, err)
+	})
+
+	data, err := os.ReadFile(filepath.Join(dir, "chronicle.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `level=ERROR msg="command failed" command=add`)
+	assert.Contains(t, string(data), "invalid URL")
+}
+
+func TestStartLogging_BadConfigOnlyWarns(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("logging:\n  level: chatty\n"), 0o600))
+
+	prev := slog.Default()
+	stop := startLogging(&GlobalFlags{Config: cfgPath, DBPath: filepath.Join(dir, "chronicle.db"), Quiet: true}, "")
+	defer stop()
+	assert.Same(t, prev, slog.Default())
+}
+
+func TestRunWithArgs_VerboseTracesToStderr(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("logging:\n  level: warn\n  file: chronicle.log\n"), 0o600))
+	dbPath := filepath.Join(dir, "chronicle.db")
+
+	stderr := captureStderr(t, func() {
+		captureOutput(t, func() {
+			err := RunWithArgs("test", []string{"--config", cfgPath, "--db-path", dbPath, "--verbose", "add", "--url", "https://chase.com/login", "--title", "Bank"})
+			require.ErrorContains(t, err, "excluded")
+			require.NoError(t, RunWithArgs("test", []string{"--config", cfgPath, "--db-path", dbPath, "--verbose", "search", "bank"}))
+		})
+	})
+
+	assert.Contains(t, stderr, `msg="resolved database" path=`+dbPath)
+	assert.Contains(t, stderr, `msg="database opened"`)
+	assert.Contains(t, stderr, `msg="domain excluded" domain=chase.com`)
+	assert.Contains(t, stderr, `msg=query sql=`)
+	assert.Contains(t, stderr, `msg="command finished" command=search`)
+
+	// The file log is raised to debug as well.
+	data, err := os.ReadFile(filepath.Join(dir, "chronicle.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `msg="domain excluded"`)
+}
+
+func TestRunWithArgs_QuietWithoutVerbose(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("logging:\n  file: chronicle.log\n"), 0o600))
+
+	stderr := captureStderr(t, func() {
+		captureOutput(t, func() {
+			require.NoError(t, RunWithArgs("test", []string{"--config", cfgPath, "--db-path", filepath.Join(dir, "chronicle.db"), "search", "bank"}))
+		})
+	})
+	assert.NotContains(t, stderr, "msg=")
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+type migrateStatusJSON struct {
+	Version    int             `json:"version"`
+	Latest     int             `json:"latest"`
+	Migrations []migrationJSON `json:"migrations"`
+}
+
+type migrationJSON struct {
+	Version    int    `json:"version"`
+	Name       string `json:"name"`
+	Applied    bool   `json:"applied"`
+	AppliedAt  string `json:"applied_at,omitempty"`
+	Reversible bool   `json:"reversible"`
+}
+
+// openMigrationDB opens the database selected by the global flags without
+// applying migrations, so migrate sees and changes the schema as it is.
+func openMigrationDB(globals *GlobalFlags) (*sql.DB, error) {
+	dbPath, err := resolveDBPath(globals)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+	db, err := storage.OpenDB(dbPath, storagePragmas(loadConfig(globals)))
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+// Execute implements the go-flags Commander interface for MigrateStatusCommand.
+func (c *MigrateStatusCommand) Execute(args []string) error {
+	db, err := openMigrationDB(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return c.run(db)
+}
+
+func (c *MigrateStatusCommand) run(db *sql.DB) error {
+	runner := storage.NewMigrationRunner(db)
+	status, err := runner.Status()
+	if err != nil {
+		return err
+	}
+	version, err := storage.SchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := migrateStatusJSON{Version: version, Latest: runner.LatestVersion(), Migrations: []migrationJSON{}}
+		for _, m := range status {
+			mj := migrationJSON{Version: m.Version, Name: m.Name, Applied: m.Applied, Reversible: m.Reversible}
+			if m.Applied {
+				mj.AppliedAt = m.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			out.Migrations = append(out.Migrations, mj)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("Schema: v%d (latest v%d)\n\n", version, runner.LatestVersion())
+	width := 0
+	for _, m := range status {
+		width = max(width, len(m.Name))
+	}
+	for _, m := range status {
+		mark, state := " ", "pending"
+		if m.Applied {
+			mark, state = "✓", "applied "+m.AppliedAt.Local().Format("2006-01-02 15:04")
+		}
+		if !m.Reversible {
+			state += " (irreversible)"
+		}
+		fmt.Printf("  %s %3d  %-*s  %s\n", mark, m.Version, width, m.Name, state)
+	}
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for MigrateUpCommand.
+func (c *MigrateUpCommand) Execute(args []string) error {
+	db, err := openMigrationDB(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return c.run(db)
+}
+
+func (c *MigrateUpCommand) run(db *sql.DB) error {
+	runner := storage.NewMigrationRunner(db)
+	target := c.To
+	if target == 0 {
+		target = runner.LatestVersion()
+	}
+	if target < 0 || target > runner.LatestVersion() {
+		return fmt.Errorf("--to %d is not a known schema version (latest is %d)", target, runner.LatestVersion())
+	}
+	before, err := storage.SchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if err := runner.Up(target); err != nil {
+		return err
+	}
+	after, err := storage.SchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if after == before {
+		infof(c.globals, "Schema already at v%d; nothing to apply.\n", after)
+	} else {
+		infof(c.globals, "Migrated schema from v%d to v%d.\n", before, after)
+	}
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for MigrateDownCommand.
+func (c *MigrateDownCommand) Execute(args []string) error {
+	db, err := openMigrationDB(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return c.run(db)
+}
+
+func (c *MigrateDownCommand) run(db *sql.DB) error {
+	runner := storage.NewMigrationRunner(db)
+	status, err := runner.Status()
+	if err != nil {
+		return err
+	}
+	current, err := storage.SchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	target := c.To
+	if target == 0 {
+		target = current - 1
+	}
+	if target < 1 || target > current {
+		return fmt.Errorf("--to %d is outside the applied schema versions 1 to %d", target, current)
+	}
+
+	var names []string
+	for i := len(status) - 1; i >= 0; i-- {
+		if m := status[i]; m.Applied && m.Version > target {
+			names = append(names, fmt.Sprintf("%d (%s)", m.Version, m.Name))
+		}
+	}
+	if len(names) == 0 {
+		infof(c.globals, "Schema already at v%d; nothing to revert.\n", current)
+		return nil
+	}
+
+	if !c.Force {
+		fmt.Printf("Reverting migrations %s.\n", strings.Join(names, ", "))
+		fmt.Println("Tables and columns they added are dropped along with their data.")
+		fmt.Print("Proceed? [y/N] ")
+
+		reader := c.stdin
+		if reader == nil {
+			reader = os.Stdin
+		}
+		scanner := bufio.NewScanner(reader)
+		scanner.Scan()
+		answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := runner.Down(target); err != nil {
+		return err
+	}
+	infof(c.globals, "Reverted schema from v%d to v%d.\n", current, target)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// migrateEncryptJSON is the JSON output structure for migrate-encrypt.
+type migrateEncryptJSON struct {
+	Encrypted int64 `json:"encrypted"`
+}
+
+// Execute implements the go-flags Commander interface for MigrateEncryptCommand.
+func (c *MigrateEncryptCommand) Execute(args []string) error {
+	passphrase, err := encryptionPassphrase(loadConfig(c.globals).Storage.Encryption)
+	if err != nil {
+		return err
+	}
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, db, passphrase)
+}
+
+// executeWithStore encrypts the bodies of a provided store (used by tests).
+func (c *MigrateEncryptCommand) executeWithStore(store *storage.SQLiteStore, db *sql.DB, passphrase string) error {
+	ctx := context.Background()
+	cipher, err := storage.InitEncryption(ctx, db, passphrase)
+	if err != nil {
+		return err
+	}
+	store.SetCipher(cipher)
+
+	total, err := store.PlaintextBodies(ctx)
+	if err != nil {
+		return err
+	}
+	bar := newProgress(c.globals, "Encrypting", int(total))
+	n, err := store.EncryptBodies(ctx, bar.Add)
+	bar.Finish()
+	if err != nil {
+		return err
+	}
+	// The plaintext bodies linger in free pages until the file is rebuilt.
+	if err := store.Vacuum(ctx); err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(migrateEncryptJSON{Encrypted: n})
+	}
+	infof(c.globals, "Encrypted %s bodies\n", formatNumber(n))
+	return nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateEncrypt_EncryptsBodies(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	e := &storage.Event{URL: "https://tides.example.com", Title: "Tide tables", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, e, "High water at the harbor is at noon"))
+
+	cmd := &MigrateEncryptCommand{globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, "correct horse"))
+	})
+	var out migrateEncryptJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, int64(1), out.Encrypted)
+
+	var stored string
+	require.NoError(t, db.QueryRow(`SELECT body FROM content WHERE event_id = ?`, e.ID).Scan(&stored))
+	assert.NotContains(t, stored, "harbor")
+	content, err := store.GetContent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "High water at the harbor is at noon", content.Body)
+
+	// The database now needs the passphrase it was encrypted with.
+	t.Setenv("CHRONICLE_TEST_PASSPHRASE", "wrong horse")
+	enc := config.EncryptionConfig{KeySource: "passphrase", PassphraseEnv: "CHRONICLE_TEST_PASSPHRASE"}
+	assert.ErrorIs(t, setupEncryption(store, db, enc), storage.ErrWrongPassphrase)
+}
+
+func TestEncryptionPassphrase(t *testing.T) {
+	t.Setenv("CHRONICLE_TEST_PASSPHRASE", "")
+	enc := config.EncryptionConfig{KeySource: "passphrase", PassphraseEnv: "CHRONICLE_TEST_PASSPHRASE"}
+	_, err := encryptionPassphrase(enc)
+	assert.EqualError(t, err, "database encryption needs a passphrase: set $CHRONICLE_TEST_PASSPHRASE")
+
+	t.Setenv("CHRONICLE_TEST_PASSPHRASE", "correct horse")
+	passphrase, err := encryptionPassphrase(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "correct horse", passphrase)
+
+	_, err = encryptionPassphrase(config.EncryptionConfig{KeySource: "vault"})
+	assert.EqualError(t, err, `invalid storage.encryption.key_source "vault" (want passphrase or keychain)`)
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func openMigrateTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := storage.OpenDB(filepath.Join(t.TempDir(), "migrate.db"), storage.Pragmas{})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func schemaVersion(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	v, err := storage.SchemaVersion(db)
+	require.NoError(t, err)
+	return v
+}
+
+func TestMigrate_UpToAndStatus(t *testing.T) {
+	db := openMigrateTestDB(t)
+	latest := storage.NewMigrationRunner(db).LatestVersion()
+
+	output := captureOutput(t, func() {
+		require.NoError(t, (&MigrateUpCommand{To: 2, globals: &GlobalFlags{}}).run(db))
+	})
+	assert.Contains(t, output, "Migrated schema from v0 to v2.")
+
+	output = captureOutput(t, func() {
+		require.NoError(t, (&MigrateStatusCommand{globals: &GlobalFlags{}}).run(db))
+	})
+	assert.Contains(t, output, "Schema: v2 (latest v")
+	assert.Regexp(t, `✓   1  initial_schema\s+applied \S+ \S+ \(irreversible\)`, output)
+	assert.Regexp(t, `    3  content_truncation\s+pending\n`, output)
+
+	captureOutput(t, func() {
+		require.NoError(t, (&MigrateUpCommand{globals: &GlobalFlags{}}).run(db))
+	})
+	assert.Equal(t, latest, schemaVersion(t, db))
+
+	output = captureOutput(t, func() {
+		require.NoError(t, (&MigrateStatusCommand{globals: &GlobalFlags{JSON: true}}).run(db))
+	})
+	var out migrateStatusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, latest, out.Version)
+	require.Len(t, out.Migrations, latest)
+	assert.False(t, out.Migrations[0].Reversible)
+	assert.NotEmpty(t, out.Migrations[latest-1].AppliedAt)
+
+	err := (&MigrateUpCommand{To: latest + 1, globals: &GlobalFlags{}}).run(db)
+	assert.ErrorContains(t, err, "not a known schema version")
+}
+
+func TestMigrate_Down(t *testing.T) {
+	db := openMigrateTestDB(t)
+	runner := storage.NewMigrationRunner(db)
+	require.NoError(t, runner.Run())
+	latest := runner.LatestVersion()
+
+	// Declining the prompt leaves the schema alone.
+	output := captureOutput(t, func() {
+		cmd := &MigrateDownCommand{globals: &GlobalFlags{}, stdin: strings.NewReader("n\n")}
+		require.NoError(t, cmd.run(db))
+	})
+	assert.Contains(t, output, "Reverting migrations 19 (embedding_vectors).")
+	assert.Contains(t, output, "Aborted.")
+	assert.Equal(t, latest, schemaVersion(t, db))
+
+	output = captureOutput(t, func() {
+		cmd := &MigrateDownCommand{globals: &GlobalFlags{}, stdin: strings.NewReader("y\n")}
+		require.NoError(t, cmd.run(db))
+	})
+	assert.Contains(t, output, "Reverted schema from v19 to v18.")
+	assert.Equal(t, latest-1, schemaVersion(t, db))
+
+	captureOutput(t, func() {
+		require.NoError(t, (&MigrateDownCommand{To: 4, Force: true, globals: &GlobalFlags{}}).run(db))
+	})
+	assert.Equal(t, 4, schemaVersion(t, db))
+
+	err := (&MigrateDownCommand{To: 5, Force: true, globals: &GlobalFlags{}}).run(db)
+	assert.ErrorContains(t, err, "outside the applied schema versions 1 to 4")
+}
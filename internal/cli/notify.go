@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/notify"
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/runnerr0/chronicle/internal/webhook"
+)
+
+// desktopNotify shows a desktop notification; tests replace it.
+var desktopNotify = notify.Send
+
+// newPipeline returns the capture pipeline for store with the configured
+// hooks, webhooks and capture notifications. Failed deliveries are
+// reported as warnings on stderr.
+func newPipeline(globals *GlobalFlags, store storage.Store, hooks config.HooksConfig, webhooks []config.WebhookConfig, notifications config.NotificationsConfig) *capture.Pipeline {
+	p := capture.New(store)
+	p.SetHooks(hook.New(hooks))
+	p.SetWebhooks(webhook.New(webhooks))
+	p.SetOnCapture(func(ctx context.Context, e *storage.Event, body string) {
+		notifyCapture(globals, notifications, e)
+	})
+	p.SetWarn(func(err error) {
+		noticef(globals, "Warning: %v\n", err)
+	})
+	return p
+}
+
+// notifyCapture shows a notification for e when notifications are enabled
+// and e's domain is one of the watched domains. Failures are only reported.
+func notifyCapture(globals *GlobalFlags, cfg config.NotificationsConfig, e *storage.Event) {
+	if !cfg.Enabled || !inDomains(e.Domain, cfg.Domains) {
+		return
+	}
+	title := e.Title
+	if title == "" {
+		title = e.URL
+	}
+	if err := desktopNotify("Captured "+e.Domain, title); err != nil {
+		slog.Warn("notification failed", "err", err)
+		noticef(globals, "Warning: notification failed: %v\n", err)
+	}
+}
+
+// notifyPrune shows a notification when retention removed more events than
+// the configured threshold.
+func notifyPrune(globals *GlobalFlags, cfg config.NotificationsConfig, pruned int64) {
+	if !cfg.Enabled || cfg.PruneThreshold <= 0 || pruned <= cfg.PruneThreshold {
+		return
+	}
+	msg := fmt.Sprintf("Retention removed %d events from your history.", pruned)
+	if err := desktopNotify("Chronicle pruned history", msg); err != nil {
+		slog.Warn("notification failed", "err", err)
+		noticef(globals, "Warning: notification failed: %v\n", err)
+	}
+}
+
+// inDomains reports whether domain is one of domains or a subdomain of one.
+func inDomains(domain string, domains []string) bool {
+	domain = strings.ToLower(domain)
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "."))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+type sentNotification struct{ title, message string }
+
+// captureNotifications replaces desktopNotify for the test, recording what
+// would have been shown.
+func captureNotifications(t *testing.T) *[]sentNotification {
+	t.Helper()
+	var sent []sentNotification
+	old := desktopNotify
+	t.Cleanup(func() { desktopNotify = old })
+	desktopNotify = func(title, message string) error {
+		sent = append(sent, sentNotification{title, message})
+		return nil
+	}
+	return &sent
+}
+
+func TestAdd_NotifiesWatchedDomains(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	sent := captureNotifications(t)
+
+	notifications := config.NotificationsConfig{Enabled: true, Domains: []string{"github.com"}}
+	for _, u := range []string{"https://docs.github.com/actions", "https://example.com/"} {
+		cmd := &AddCommand{URL: u, Title: "Page " + u, BrowserName: "manual", globals: &GlobalFlags{Quiet: true}, notifications: notifications}
+		captureOutput(t, func() {
+			require.NoError(t, cmd.executeWithStore(store))
+		})
+	}
+
+	require.Len(t, *sent, 1)
+	assert.Equal(t, sentNotification{"Captured docs.github.com", "Page https://docs.github.com/actions"}, (*sent)[0])
+}
+
+func TestAdd_NotificationsDisabled(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	sent := captureNotifications(t)
+
+	cmd := &AddCommand{URL: "https://github.com/x", Title: "X", BrowserName: "manual", globals: &GlobalFlags{Quiet: true},
+		notifications: config.NotificationsConfig{Domains: []string{"github.com"}}}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store))
+	})
+	assert.Empty(t, *sent)
+}
+
+func TestPrune_NotifiesAboveThreshold(t *testing.T) {
+	sent := captureNotifications(t)
+
+	cmd, _ := setupPruneTest(t, 5, 3)
+	cmd.Force = true
+	cmd.cfg.Notifications = config.NotificationsConfig{Enabled: true, PruneThreshold: 4}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	require.Len(t, *sent, 1)
+	assert.Equal(t, "Retention removed 5 events from your history.", (*sent)[0].message)
+
+	cmd, _ = setupPruneTest(t, 5, 3)
+	cmd.Force = true
+	cmd.cfg.Notifications = config.NotificationsConfig{Enabled: true, PruneThreshold: 5}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Len(t, *sent, 1, "at the threshold is not above it")
+}
+
+func TestNotify_FailureIsOnlyAWarning(t *testing.T) {
+	old := desktopNotify
+	t.Cleanup(func() { desktopNotify = old })
+	desktopNotify = func(string, string) error { return errors.New("no display") }
+
+	cmd, _ := setupPruneTest(t, 5, 3)
+	cmd.Force = true
+	cmd.cfg.Notifications = config.NotificationsConfig{Enabled: true, PruneThreshold: 1}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+}
+
+func TestInDomains(t *testing.T) {
+	assert.True(t, inDomains("github.com", []string{"github.com"}))
+	assert.True(t, inDomains("gist.github.com", []string{".GitHub.com"}))
+	assert.False(t, inDomains("notgithub.com", []string{"github.com"}))
+	assert.False(t, inDomains("github.com", nil))
+}
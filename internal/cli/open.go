@@ -2,16 +2,15 @@ package cli
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
-	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 )
 
@@ -21,54 +20,43 @@ func (c *OpenCommand) Execute(args []string) error {
 		return fmt.Errorf("--id is required for open command")
 	}
 
-	// Resolve DB path
-	dbPath, err := resolveDBPath(c.globals)
+	store, db, err := openStore(c.globals)
 	if err != nil {
 		return err
 	}
-
-	// Open DB and run migrations
-	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
-	if err != nil {
-		return fmt.Errorf("open database: %w", err)
-	}
 	defer db.Close()
-
-	runner := storage.NewMigrationRunner(db)
-	if err := runner.Run(); err != nil {
-		return fmt.Errorf("run migrations: %w", err)
-	}
-
-	store, err := storage.NewSQLiteStore(db)
-	if err != nil {
-		return fmt.Errorf("init store: %w", err)
-	}
 	defer store.Close()
 
 	ctx := context.Background()
 
 	// Get event
-	event, err := store.GetEvent(ctx, c.ID)
+	event, err := store.GetEvent(ctx, string(c.ID))
 	if err != nil {
-		return fmt.Errorf("event not found: %s", c.ID)
+		return fmt.Errorf("event %w: %s", ErrNotFound, c.ID)
 	}
 
 	// Get content (may not exist)
-	content, _ := store.GetContent(ctx, c.ID)
+	content, _ := store.GetContent(ctx, string(c.ID))
 	bodyText := ""
 	if content != nil {
 		bodyText = content.Body
 	}
+	if event.Meta, err = store.GetPageMeta(ctx, event.ID); err != nil {
+		return err
+	}
+	if event.Keywords, err = store.KeywordsFor(ctx, event.ID); err != nil {
+		return err
+	}
 
 	// JSON output (--json global flag)
 	if c.globals.JSON {
-		return c.outputJSON(event, bodyText)
+		return c.outputJSON(event, bodyText, content)
 	}
 
 	// Format-specific output
 	switch c.Format {
 	case "url":
-		fmt.Println(event.URL)
+		fmt.Println(event.PageURL())
 	case "title":
 		fmt.Println(event.Title)
 	case "body", "raw":
@@ -80,24 +68,56 @@ func (c *OpenCommand) Execute(args []string) error {
 	case "metadata":
 		return c.outputMetadata(event)
 	case "json":
-		return c.outputJSON(event, bodyText)
+		return c.outputJSON(event, bodyText, content)
 	case "md":
 		c.outputMarkdown(event, bodyText)
 	default: // "full"
-		c.outputFull(event, bodyText)
+		c.outputFull(event, bodyText, content)
 	}
 
 	return nil
 }
 
-func (c *OpenCommand) outputFull(event *storage.Event, body string) {
+func (c *OpenCommand) outputFull(event *storage.Event, body string, content *storage.Content) {
 	fmt.Println(event.ID)
 	fmt.Printf("Title:     %s\n", event.Title)
+	if event.RawTitle != "" {
+		fmt.Printf("Raw title: %s\n", event.RawTitle)
+	}
 	fmt.Printf("URL:       %s\n", event.URL)
+	if event.CanonicalURL != "" {
+		fmt.Printf("Canonical: %s\n", event.CanonicalURL)
+	}
 	fmt.Printf("Domain:    %s\n", event.Domain)
 	fmt.Printf("Captured:  %s\n", event.Timestamp.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Source:    %s\n", event.Source)
 	fmt.Printf("Browser:   %s\n", event.Browser)
+	if event.Lang != "" {
+		fmt.Printf("Language:  %s (%s)\n", storage.LanguageName(event.Lang), event.Lang)
+	}
+	if event.ReadTime > 0 {
+		fmt.Printf("Reading:   %s\n", formatReadTime(event.ReadTime))
+	}
+	if m := event.Meta; m != nil {
+		if m.Author != "" {
+			fmt.Printf("Author:    %s\n", m.Author)
+		}
+		if !m.Published.IsZero() {
+			fmt.Printf("Published: %s\n", m.Published.Format("2006-01-02"))
+		}
+		if m.OGTitle != "" && m.OGTitle != event.Title {
+			fmt.Printf("OG title:  %s\n", m.OGTitle)
+		}
+		if m.OGDescription != "" {
+			fmt.Printf("Summary:   %s\n", m.OGDescription)
+		}
+		if m.OGImage != "" {
+			fmt.Printf("Image:     %s\n", m.OGImage)
+		}
+	}
+	if len(event.Keywords) > 0 {
+		fmt.Printf("Keywords:  %s\n", strings.Join(event.Keywords, ", "))
+	}
 	fmt.Println()
 	fmt.Println("--- Content ---")
 	if body == "" {
@@ -105,24 +125,55 @@ func (c *OpenCommand) outputFull(event *storage.Event, body string) {
 	} else {
 		fmt.Println(body)
 	}
+	if content != nil && content.Truncated {
+		fmt.Printf("\n[Truncated: stored %s of %s bytes]\n", formatNumber(int64(len(body))), formatNumber(content.OriginalSize))
+	}
 }
 
 func (c *OpenCommand) outputMarkdown(event *storage.Event, body string) {
-	fmt.Println("---")
-	fmt.Printf("id: %s\n", event.ID)
-	fmt.Printf("title: %s\n", event.Title)
-	fmt.Printf("url: %s\n", event.URL)
-	fmt.Printf("domain: %s\n", event.Domain)
-	fmt.Printf("captured: %s\n", event.Timestamp.Format("2006-01-02T15:04:05Z"))
-	fmt.Printf("source: %s\n", event.Source)
-	fmt.Printf("browser: %s\n", event.Browser)
-	fmt.Println("---")
+	writeEventMarkdown(os.Stdout, event, body)
+}
+
+// writeEventMarkdown renders an event as YAML frontmatter followed by its body.
+func writeEventMarkdown(w io.Writer, event *storage.Event, body string) {
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "id: %s\n", event.ID)
+	fmt.Fprintf(w, "title: %s\n", event.Title)
+	if event.RawTitle != "" {
+		fmt.Fprintf(w, "raw_title: %s\n", event.RawTitle)
+	}
+	fmt.Fprintf(w, "url: %s\n", event.URL)
+	if event.CanonicalURL != "" {
+		fmt.Fprintf(w, "canonical: %s\n", event.CanonicalURL)
+	}
+	fmt.Fprintf(w, "domain: %s\n", event.Domain)
+	fmt.Fprintf(w, "captured: %s\n", event.Timestamp.Format("2006-01-02T15:04:05Z"))
+	fmt.Fprintf(w, "source: %s\n", event.Source)
+	fmt.Fprintf(w, "browser: %s\n", event.Browser)
+	if m := event.Meta; m != nil {
+		if m.Author != "" {
+			fmt.Fprintf(w, "author: %s\n", m.Author)
+		}
+		if !m.Published.IsZero() {
+			fmt.Fprintf(w, "published: %s\n", m.Published.UTC().Format("2006-01-02T15:04:05Z"))
+		}
+		if m.OGDescription != "" {
+			fmt.Fprintf(w, "description: %s\n", m.OGDescription)
+		}
+		if m.OGImage != "" {
+			fmt.Fprintf(w, "image: %s\n", m.OGImage)
+		}
+	}
+	if len(event.Keywords) > 0 {
+		fmt.Fprintf(w, "keywords: [%s]\n", strings.Join(event.Keywords, ", "))
+	}
+	fmt.Fprintln(w, "---")
 	if body == "" {
-		fmt.Println()
-		fmt.Println("No content captured")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No content captured")
 	} else {
-		fmt.Println()
-		fmt.Println(body)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, body)
 	}
 }
 
@@ -141,13 +192,29 @@ func (c *OpenCommand) outputMetadata(event *storage.Event) error {
 	if event.ContentHash != "" {
 		meta["content_hash"] = event.ContentHash
 	}
+	if event.RawTitle != "" {
+		meta["raw_title"] = event.RawTitle
+	}
+	if event.CanonicalURL != "" {
+		meta["canonical_url"] = event.CanonicalURL
+	}
+	if event.Lang != "" {
+		meta["lang"] = event.Lang
+	}
+	if event.ReadTime > 0 {
+		meta["read_seconds"] = int64(event.ReadTime / time.Second)
+	}
+	addPageMeta(meta, event.Meta)
+	if len(event.Keywords) > 0 {
+		meta["keywords"] = event.Keywords
+	}
 
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(meta)
 }
 
-func (c *OpenCommand) outputJSON(event *storage.Event, body string) error {
+func (c *OpenCommand) outputJSON(event *storage.Event, body string, content *storage.Content) error {
 	result := map[string]interface{}{
 		"id":        event.ID,
 		"title":     event.Title,
@@ -160,46 +227,51 @@ func (c *OpenCommand) outputJSON(event *storage.Event, body string) error {
 		"has_embed": event.HasEmbed,
 		"body":      body,
 	}
+	if content != nil && content.Truncated {
+		result["truncated"] = true
+		result["original_size"] = content.OriginalSize
+	}
 	if event.ContentHash != "" {
 		result["content_hash"] = event.ContentHash
 	}
+	if event.RawTitle != "" {
+		result["raw_title"] = event.RawTitle
+	}
+	if event.CanonicalURL != "" {
+		result["canonical_url"] = event.CanonicalURL
+	}
+	if event.Lang != "" {
+		result["lang"] = event.Lang
+	}
+	if event.ReadTime > 0 {
+		result["read_seconds"] = int64(event.ReadTime / time.Second)
+	}
+	addPageMeta(result, event.Meta)
+	if len(event.Keywords) > 0 {
+		result["keywords"] = event.Keywords
+	}
 
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(result)
 }
 
-// resolveDBPath determines the SQLite database file path.
-// Priority: --db-path flag > config file > default config.
-func resolveDBPath(globals *GlobalFlags) (string, error) {
-	if globals.DBPath != "" {
-		return globals.DBPath, nil
+// addPageMeta adds the page metadata fields that are set to a JSON object.
+func addPageMeta(out map[string]interface{}, m *storage.PageMeta) {
+	if m == nil {
+		return
 	}
-
-	var cfg *config.Config
-	var err error
-
-	if globals.Config != "" {
-		cfg, err = config.Load(globals.Config)
-		if err != nil {
-			// If config specified but unreadable, use defaults
-			cfg = config.DefaultConfig()
-		}
-	} else {
-		cfg, err = config.LoadOrCreate()
-		if err != nil {
-			cfg = config.DefaultConfig()
+	for key, value := range map[string]string{
+		"og_title":       m.OGTitle,
+		"og_description": m.OGDescription,
+		"og_image":       m.OGImage,
+		"author":         m.Author,
+	} {
+		if value != "" {
+			out[key] = value
 		}
 	}
-
-	storagePath := cfg.Storage.Path
-	if strings.HasPrefix(storagePath, "~") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("resolve home dir: %w", err)
-		}
-		storagePath = filepath.Join(home, storagePath[1:])
+	if !m.Published.IsZero() {
+		out["published"] = m.Published.UTC().Format("2006-01-02T15:04:05Z")
 	}
-
-	return filepath.Join(storagePath, cfg.Storage.SQLiteFile), nil
 }
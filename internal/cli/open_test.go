@@ -5,7 +5,10 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -235,3 +238,138 @@ func TestOpenEventWithoutContent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, output, "No content captured")
 }
+
+func TestOpen_ShowsTruncation(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("capture:\n  max_body_bytes: 5\n"), 0644))
+	base := []string{"--config", cfgPath, "--db-path", filepath.Join(dir, "chronicle.db")}
+
+	out, err := captureOpenOutput(t, append(base, "--quiet", "add", "--url", "https://example.com/long", "--title", "Long", "--body", "0123456789"))
+	require.NoError(t, err)
+	id := strings.TrimSpace(out)
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", id))
+	require.NoError(t, err)
+	assert.Contains(t, out, "01234\n")
+	assert.NotContains(t, out, "56789")
+	assert.Contains(t, out, "[Truncated: stored 5 of 10 bytes]")
+
+	out, err = captureOpenOutput(t, append(base, "--json", "open", "--id", id))
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, true, result["truncated"])
+	assert.Equal(t, float64(10), result["original_size"])
+}
+
+func TestAdd_RejectsOversizedBody(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("capture:\n  max_body_bytes: 5\n  body_overflow: reject\n"), 0644))
+
+	_, err := captureOpenOutput(t, []string{"--config", cfgPath, "--db-path", filepath.Join(dir, "chronicle.db"),
+		"add", "--url", "https://example.com/long", "--title", "Long", "--body", "0123456789"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrBodyTooLarge)
+}
+
+func TestOpen_ShowsFetchedPageMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Tides</title>
+			<meta name="author" content="Ada Lovelace">
+			<meta property="article:published_time" content="2025-06-01T08:30:00Z">
+			<meta property="og:description" content="Why the sea rises.">
+			<meta property="og:image" content="https://example.com/tide.png">
+			</head><body><p>The moon pulls the oceans.</p></body></html>`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	base := []string{"--config", "/dev/null", "--db-path", filepath.Join(dir, "chronicle.db")}
+
+	out, err := captureOpenOutput(t, append(base, "add", "--url", srv.URL+"/tides", "--fetch"))
+	require.NoError(t, err)
+	assert.Contains(t, out, "Title: Tides")
+	assert.Contains(t, out, "Body: yes")
+	assert.Contains(t, out, "Author: Ada Lovelace")
+
+	out, err = captureOpenOutput(t, append(base, "--json", "search", "--since", "1h", "Tides"))
+	require.NoError(t, err)
+	var found jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &found))
+	require.Len(t, found.Results, 1)
+	id := found.Results[0].ID
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", id))
+	require.NoError(t, err)
+	assert.Contains(t, out, "Author:    Ada Lovelace\n")
+	assert.Contains(t, out, "Published: 2025-06-01\n")
+	assert.Contains(t, out, "Summary:   Why the sea rises.\n")
+	assert.Contains(t, out, "Image:     https://example.com/tide.png\n")
+	assert.Contains(t, out, "The moon pulls the oceans.")
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", id, "--format", "metadata"))
+	require.NoError(t, err)
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &meta))
+	assert.Equal(t, "Ada Lovelace", meta["author"])
+	assert.Equal(t, "2025-06-01T08:30:00Z", meta["published"])
+	assert.NotContains(t, meta, "og_title")
+
+	_, err = captureOpenOutput(t, append(base, "add", "--url", srv.URL+"/other"))
+	assert.EqualError(t, err, "--title is required for add command")
+}
+
+func TestOpen_ShowsKeywordsAndSearchKeyword(t *testing.T) {
+	dir := t.TempDir()
+	base := []string{"--config", "/dev/null", "--db-path", filepath.Join(dir, "chronicle.db")}
+
+	_, err := captureOpenOutput(t, append(base, "add", "--url", "https://example.com/tides", "--title", "Tides",
+		"--body", "Tidal forces raise the sea. Tidal forces also slow the Earth."))
+	require.NoError(t, err)
+	_, err = captureOpenOutput(t, append(base, "add", "--url", "https://example.com/go", "--title", "Go",
+		"--body", "Goroutines make concurrent programs simple."))
+	require.NoError(t, err)
+
+	out, err := captureOpenOutput(t, append(base, "--json", "search", "--since", "1h", "--keyword", "Tidal  Forces"))
+	require.NoError(t, err)
+	var found jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &found))
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "Tides", found.Results[0].Title)
+	id := found.Results[0].ID
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", id))
+	require.NoError(t, err)
+	assert.Contains(t, out, "Keywords:  tidal forces raise, tidal forces, earth, sea, slow\n")
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", id, "--format", "md"))
+	require.NoError(t, err)
+	assert.Contains(t, out, "keywords: [tidal forces raise, tidal forces, earth, sea, slow]\n")
+}
+
+func TestOpen_ShowsRawTitle(t *testing.T) {
+	dir := t.TempDir()
+	base := []string{"--config", "/dev/null", "--db-path", filepath.Join(dir, "chronicle.db")}
+
+	_, err := captureOpenOutput(t, append(base, "add", "--url", "https://news.ycombinator.com/item?id=1",
+		"--title", "Show HN: Chronicle | Hacker News"))
+	require.NoError(t, err)
+
+	out, err := captureOpenOutput(t, append(base, "--json", "search", "--since", "1h", "chronicle"))
+	require.NoError(t, err)
+	var found jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &found))
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "Show HN: Chronicle", found.Results[0].Title)
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", found.Results[0].ID))
+	require.NoError(t, err)
+	assert.Contains(t, out, "Title:     Show HN: Chronicle\nRaw title: Show HN: Chronicle | Hacker News\n")
+
+	out, err = captureOpenOutput(t, append(base, "open", "--id", found.Results[0].ID, "--format", "md"))
+	require.NoError(t, err)
+	assert.Contains(t, out, "raw_title: Show HN: Chronicle | Hacker News\n")
+}
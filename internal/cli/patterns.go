@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/fabric"
+)
+
+// patternJSON is the JSON output structure for patterns entries.
+type patternJSON struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Execute implements the go-flags Commander interface for PatternsCommand.
+func (c *PatternsCommand) Execute(args []string) error {
+	return c.executeWithConfig(loadConfig(c.globals))
+}
+
+// executeWithConfig lists patterns from the given config (used by tests).
+func (c *PatternsCommand) executeWithConfig(cfg *config.Config) error {
+	dir, err := cfg.Fabric.PatternsPath()
+	if err != nil {
+		return err
+	}
+	patterns, err := fabric.ListPatterns(dir)
+	if err != nil {
+		return fmt.Errorf("%w (set fabric.patterns_dir in config, or run fabric --setup)", err)
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]patternJSON, len(patterns))
+		for i, p := range patterns {
+			out[i] = patternJSON{Name: p.Name, Description: p.Description}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(patterns) == 0 {
+		infof(c.globals, "No patterns found in %s\n", dir)
+		return nil
+	}
+
+	width := 0
+	for _, p := range patterns {
+		if len(p.Name) > width {
+			width = len(p.Name)
+		}
+	}
+	for _, p := range patterns {
+		fmt.Printf("%-*s  %s\n", width, p.Name, p.Description)
+	}
+	word := "patterns"
+	if len(patterns) == 1 {
+		word = "pattern"
+	}
+	infof(c.globals, "\n%d %s. Use one with: chronicle summarize --id <id> --pattern <name>\n", len(patterns), word)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func patternsConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	prompts := map[string]string{
+		"summarize":      "# IDENTITY and PURPOSE\n\nYou are an expert content summarizer. More.\n",
+		"extract_wisdom": "# IDENTITY\n\nYou extract surprising insights.\n",
+	}
+	for name, prompt := range prompts {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "system.md"), []byte(prompt), 0644))
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Fabric.PatternsDir = dir
+	return cfg
+}
+
+func TestPatterns_ListsWithDescriptions(t *testing.T) {
+	cmd := &PatternsCommand{globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithConfig(patternsConfig(t)))
+	})
+
+	assert.Contains(t, output, "extract_wisdom  You extract surprising insights.\n")
+	assert.Contains(t, output, "summarize       You are an expert content summarizer.\n")
+	assert.Contains(t, output, "2 patterns.")
+}
+
+func TestPatterns_JSON(t *testing.T) {
+	cmd := &PatternsCommand{globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithConfig(patternsConfig(t)))
+	})
+
+	var out []patternJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out, 2)
+	assert.Equal(t, "extract_wisdom", out[0].Name)
+	assert.Equal(t, "You extract surprising insights.", out[0].Description)
+}
+
+func TestPatterns_MissingDir(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fabric.PatternsDir = filepath.Join(t.TempDir(), "missing")
+
+	cmd := &PatternsCommand{globals: &GlobalFlags{}}
+	err := cmd.executeWithConfig(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fabric.patterns_dir")
+}
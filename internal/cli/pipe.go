@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/fabric"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Execute implements the go-flags Commander interface for PipeCommand.
+func (c *PipeCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, args)
+}
+
+// executeWithStore runs the search and streams results against a provided
+// store (used by tests).
+func (c *PipeCommand) executeWithStore(store *storage.SQLiteStore, args []string) error {
+	if c.Exec != "" && c.Pattern != "" {
+		return fmt.Errorf("--exec and --pattern cannot be combined")
+	}
+
+	query := c.Query
+	if query == "" && len(args) > 0 {
+		query = strings.Join(args, " ")
+	}
+
+	now := time.Now()
+	if err := applyDayFilter(c.Today, c.On, &c.Since, &c.Until, now); err != nil {
+		return err
+	}
+	since, until, err := resolveTimeRange(c.Since, c.Until, now)
+	if err != nil {
+		return err
+	}
+
+	sq := storage.SearchQuery{
+		Query:   query,
+		Source:  c.Source,
+		Since:   since,
+		Until:   until,
+		Limit:   c.Limit,
+		Domains: domainValues(c.Domain),
+	}
+	if c.HasBody {
+		sq.HasBody = &c.HasBody
+	}
+
+	ctx := context.Background()
+	if err := checkFilters(ctx, store, c.Source, nil); err != nil {
+		return err
+	}
+	results, err := store.SearchEvents(ctx, sq)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	if len(results) == 0 {
+		noticef(c.globals, "No matching events.\n")
+		return nil
+	}
+
+	if c.Pattern != "" {
+		return c.runPattern(ctx, store, results)
+	}
+	if c.Exec == "" {
+		return writePipeStream(ctx, store, os.Stdout, results, nil)
+	}
+
+	cmd := exec.Command("sh", "-c", c.Exec)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("open pipe to %q: %w", c.Exec, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %q: %w", c.Exec, err)
+	}
+
+	// stdout belongs to the command, so progress goes to stderr.
+	prog := newProgress(c.globals, "Piping", len(results))
+	writeErr := writePipeStream(ctx, store, stdin, results, prog)
+	stdin.Close()
+	prog.Finish()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("command %q failed: %w", c.Exec, err)
+	}
+	return writeErr
+}
+
+// runPattern renders the stream and runs it through the fabric pattern,
+// printing fabric's output.
+func (c *PipeCommand) runPattern(ctx context.Context, store *storage.SQLiteStore, results []storage.Event) error {
+	if c.cfg == nil {
+		c.cfg = loadConfig(c.globals)
+	}
+	fab, err := fabric.New(c.cfg.Fabric)
+	if err != nil {
+		return err
+	}
+
+	var stream strings.Builder
+	if err := writePipeStream(ctx, store, &stream, results, nil); err != nil {
+		return err
+	}
+	out, err := fab.Run(ctx, c.Pattern, stream.String())
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// writePipeStream writes each event as a markdown document (frontmatter
+// header plus body), separated by blank lines. prog, if non-nil, is
+// advanced per event.
+func writePipeStream(ctx context.Context, store *storage.SQLiteStore, w io.Writer, events []storage.Event, prog *progress) error {
+	bw := bufio.NewWriter(w)
+	first := true
+	return eachWithBody(ctx, store, events, func(e *storage.Event, body string) error {
+		if !first {
+			fmt.Fprintln(bw)
+		}
+		first = false
+		writeEventMarkdown(bw, e, body)
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		prog.Add(1)
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func seedPipeEvents(t *testing.T, store *storage.SQLiteStore) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	withBody := &storage.Event{URL: "https://kubernetes.io/docs/", Title: "Kubernetes Docs", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "Pods are the smallest deployable units."))
+
+	noBody := &storage.Event{URL: "https://example.com/kubernetes-news", Title: "Kubernetes News", Source: "extension", Timestamp: now.Add(-2 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, noBody))
+
+	old := &storage.Event{URL: "https://old.com/kubernetes", Title: "Old Kubernetes", Source: "extension", Timestamp: now.Add(-20 * 24 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, old))
+}
+
+func TestPipe_StreamsBodiesWithHeaders(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"kubernetes"}))
+	})
+
+	assert.Equal(t, 2, strings.Count(output, "\n---\n\n"), "each event should have a frontmatter header")
+	assert.Contains(t, output, "title: Kubernetes Docs")
+	assert.Contains(t, output, "Pods are the smallest deployable units.")
+	assert.Contains(t, output, "title: Kubernetes News")
+	assert.NotContains(t, output, "Old Kubernetes")
+}
+
+func TestPipe_HasBodyFilter(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, HasBody: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"kubernetes"}))
+	})
+
+	assert.Contains(t, output, "Kubernetes Docs")
+	assert.NotContains(t, output, "Kubernetes News")
+}
+
+func TestPipe_ExecStreamsIntoCommand(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, HasBody: true, Exec: "wc -l | tr -d ' '", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"kubernetes"}))
+	})
+
+	assert.NotContains(t, output, "Kubernetes Docs")
+	assert.Equal(t, "11", strings.TrimSpace(output))
+}
+
+func TestPipe_ExecFailureReturnsError(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, Exec: "cat >/dev/null; exit 3", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, []string{"kubernetes"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed")
+}
+
+func TestPipe_NoResultsWritesNothing(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"nonexistentterm"}))
+	})
+
+	assert.Empty(t, output)
+}
+
+func TestPipe_PatternRunsFabric(t *testing.T) {
+	store := setupSearchStore(t)
+	seedPipeEvents(t, store)
+
+	cmd := &PipeCommand{Since: "7d", Limit: 20, HasBody: true, Pattern: "extract_wisdom", globals: &GlobalFlags{}, cfg: fakeFabric(t)}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"kubernetes"}))
+	})
+
+	assert.True(t, strings.HasPrefix(output, "pattern=extract_wisdom\n"))
+	assert.Contains(t, output, "Pods are the smallest deployable units.")
+}
+
+func TestPipe_PatternAndExecConflict(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &PipeCommand{Pattern: "summarize", Exec: "cat", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined")
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// profileJSON is the JSON output structure for profile list entries.
+type profileJSON struct {
+	Name       string `json:"name"`
+	ConfigPath string `json:"config_path"`
+	Active     bool   `json:"active"`
+}
+
+// Execute implements the go-flags Commander interface for ProfileListCommand.
+func (c *ProfileListCommand) Execute(args []string) error {
+	names, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	active := ""
+	if c.globals != nil {
+		active = c.globals.Profile
+	}
+
+	out := make([]profileJSON, 0, len(names))
+	for _, name := range names {
+		path, _ := config.ProfileConfigPath(name)
+		out = append(out, profileJSON{Name: name, ConfigPath: path, Active: name == active})
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(out) == 0 {
+		infof(c.globals, "No profiles. Create one with: chronicle profile create <name>\n")
+		return nil
+	}
+	for _, p := range out {
+		marker := " "
+		if p.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, p.Name)
+	}
+	return nil
+}
+
+// Execute implements the go-flags Commander interface for ProfileCreateCommand.
+func (c *ProfileCreateCommand) Execute(args []string) error {
+	path, err := config.CreateProfile(c.Args.Name)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(profileJSON{Name: c.Args.Name, ConfigPath: path})
+	}
+
+	infof(c.globals, "Created profile %q (%s)\n", c.Args.Name, path)
+	infof(c.globals, "Use it with --profile %s or CHRONICLE_PROFILE=%s\n", c.Args.Name, c.Args.Name)
+	return nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_CreateAndList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	output := captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"profile", "create", "work"}))
+	})
+	assert.Contains(t, output, `Created profile "work"`)
+
+	output = captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"--profile", "work", "--json", "profile", "list"}))
+	})
+	var profiles []profileJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &profiles))
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "work", profiles[0].Name)
+	assert.True(t, profiles[0].Active)
+}
+
+func TestProfile_SelectsSeparateDatabase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CHRONICLE_PROFILE", "")
+
+	captureOutput(t, func() {
+		require.NoError(t, RunWithArgs("test", []string{"profile", "create", "work"}))
+	})
+
+	dbPath, err := resolveDBPath(&GlobalFlags{Profile: "work"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config/fabric/chronicle/profiles/work/chronicle.db"), dbPath)
+
+	defaultPath, err := resolveDBPath(&GlobalFlags{})
+	require.NoError(t, err)
+	assert.NotEqual(t, dbPath, defaultPath)
+}
+
+func TestProfile_EnvVar(t *testing.T) {
+	t.Setenv("CHRONICLE_PROFILE", "personal")
+	parser, globals, _ := buildParser("test")
+	captureOutput(t, func() {
+		_, err := parser.ParseArgs([]string{"completion"})
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "personal", globals.Profile)
+}
+
+func TestProfile_MissingProfileErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := resolveDBPath(&GlobalFlags{Profile: "nope"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+
+	_, err = resolveDBPath(&GlobalFlags{Profile: "../escape"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	progressBarWidth = 30
+	// progressLogEvery throttles plain-log fallback lines.
+	progressLogEvery = 2 * time.Second
+)
+
+// progress reports advancement of a long-running operation on stderr. On a
+// terminal it redraws a bar with ETA in place; otherwise (or under --json)
+// it falls back to occasional plain log lines. A nil *progress is a no-op,
+// as is one created under --quiet.
+type progress struct {
+	w     io.Writer
+	label string
+	total int
+	done  int
+	tty   bool
+	start time.Time
+	last  time.Time
+	now   func() time.Time
+}
+
+// newProgress starts progress reporting for total units of work. total may
+// be 0 when unknown, in which case only a count is shown.
+func newProgress(globals *GlobalFlags, label string, total int) *progress {
+	if globals != nil && globals.Quiet {
+		return nil
+	}
+	tty := isTerminal(os.Stderr) && (globals == nil || !globals.JSON)
+	return startProgress(os.Stderr, label, total, tty, time.Now)
+}
+
+func startProgress(w io.Writer, label string, total int, tty bool, now func() time.Time) *progress {
+	t := now()
+	return &progress{w: w, label: label, total: total, tty: tty, start: t, last: t, now: now}
+}
+
+// Add records n more completed units.
+func (p *progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.done += n
+	t := p.now()
+
+	if p.tty {
+		fmt.Fprintf(p.w, "\r\033[K%s", p.line(t))
+		return
+	}
+	if t.Sub(p.last) >= progressLogEvery {
+		fmt.Fprintln(p.w, p.line(t))
+		p.last = t
+	}
+}
+
+// Finish ends the bar and prints a one-line summary.
+func (p *progress) Finish() {
+	if p == nil {
+		return
+	}
+	elapsed := p.now().Sub(p.start).Round(100 * time.Millisecond)
+	if p.tty {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+	fmt.Fprintf(p.w, "%s: %d done in %s\n", p.label, p.done, elapsed)
+}
+
+// line renders the current state: a bar, counts, percentage and ETA when
+// the total is known, just the count otherwise.
+func (p *progress) line(t time.Time) string {
+	if p.total <= 0 {
+		return fmt.Sprintf("%s: %d", p.label, p.done)
+	}
+
+	frac := float64(p.done) / float64(p.total)
+	if frac > 1 {
+		frac = 1
+	}
+
+	eta := "?"
+	if elapsed := t.Sub(p.start); p.done > 0 && elapsed > 0 {
+		remaining := time.Duration(float64(elapsed) * (1 - frac) / frac)
+		eta = remaining.Round(time.Second).String()
+	}
+
+	if !p.tty {
+		return fmt.Sprintf("%s: %d/%d (%.0f%%) ETA %s", p.label, p.done, p.total, frac*100, eta)
+	}
+
+	filled := int(frac * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	return fmt.Sprintf("%s [%s] %d/%d %3.0f%% ETA %s", p.label, bar, p.done, p.total, frac*100, eta)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock returns a clock that advances by step on every call.
+func fakeClock(step time.Duration) func() time.Time {
+	t := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(step)
+		return t
+	}
+}
+
+func TestProgress_TTYDrawsBar(t *testing.T) {
+	var buf bytes.Buffer
+	p := startProgress(&buf, "Export", 4, true, fakeClock(time.Second))
+
+	p.Add(2)
+	assert.Contains(t, buf.String(), "Export [===============               ] 2/4  50% ETA")
+
+	p.Add(2)
+	p.Finish()
+	assert.True(t, strings.HasSuffix(buf.String(), "Export: 4 done in 3s\n"))
+}
+
+func TestProgress_PlainLogIsThrottled(t *testing.T) {
+	var buf bytes.Buffer
+	p := startProgress(&buf, "Import", 100, false, fakeClock(time.Second))
+
+	for i := 0; i < 4; i++ {
+		p.Add(1)
+	}
+	p.Finish()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		"Import: 2/100 (2%) ETA 1m38s",
+		"Import: 4/100 (4%) ETA 1m36s",
+		"Import: 4 done in 5s",
+	}, lines)
+	assert.NotContains(t, buf.String(), "\r")
+}
+
+func TestProgress_UnknownTotal(t *testing.T) {
+	var buf bytes.Buffer
+	p := startProgress(&buf, "Dedupe", 0, false, fakeClock(3*time.Second))
+	p.Add(7)
+	assert.Equal(t, "Dedupe: 7\n", buf.String())
+}
+
+func TestProgress_NilAndQuiet(t *testing.T) {
+	p := newProgress(&GlobalFlags{Quiet: true}, "x", 10)
+	assert.Nil(t, p)
+	p.Add(1)
+	p.Finish()
+}
@@ -5,9 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
 )
 
 // pruneJSON is the JSON output structure for the prune command.
@@ -15,10 +19,83 @@ type pruneJSON struct {
 	Pruned   int64  `json:"pruned"`
 	OlderThan string `json:"older_than"`
 	DryRun   bool   `json:"dry_run"`
+	Domain   string `json:"domain,omitempty"`
+}
+
+// pruneScheduleJSON is the JSON output structure for prune --show-schedule.
+type pruneScheduleJSON struct {
+	LastPruneAt    string `json:"last_prune_at,omitempty"`
+	LastPruneCount int64  `json:"last_prune_count"`
+	NextPruneAt    string `json:"next_prune_at,omitempty"`
+	IntervalHours  int    `json:"interval_hours"`
+	Job            string `json:"job,omitempty"`
+	Schedule       string `json:"schedule,omitempty"`
+}
+
+// pruneSchedule pairs the last recorded prune with the daemon job that
+// reapplies retention (see pruneJob).
+type pruneSchedule struct {
+	Last          *storage.PruneRecord
+	IntervalHours int    // retention.prune_interval_hours
+	Job           string // the prune job's name; "" if there is none
+	Schedule      string // the prune job's schedule
+	next          time.Time
+}
+
+// loadPruneSchedule reads the last prune result from store and works out
+// when the daemon's prune job next runs.
+func loadPruneSchedule(ctx context.Context, store storage.Store, cfg *config.Config) (pruneSchedule, error) {
+	last, err := store.LastPrune(ctx)
+	if err != nil {
+		return pruneSchedule{}, fmt.Errorf("read last prune: %w", err)
+	}
+	p := pruneSchedule{Last: last, IntervalHours: cfg.Retention.PruneIntervalHours}
+	jc, implicit, ok := pruneJob(cfg)
+	if !ok {
+		return p, nil
+	}
+	p.Job, p.Schedule = jc.Name, jc.Schedule
+	if p.next, err = nextPrune(jc, implicit, last, time.Now()); err != nil {
+		return pruneSchedule{}, err
+	}
+	return p, nil
+}
+
+// Next returns when the daemon will next apply retention, or the zero time
+// if it has no prune job or runs it as soon as it starts.
+func (p pruneSchedule) Next() time.Time {
+	return p.next
+}
+
+// describeLast renders the last prune for human output.
+func (p pruneSchedule) describeLast() string {
+	if p.Last == nil {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%d events)", p.Last.At.Local().Format("2006-01-02 15:04"), p.Last.Count)
+}
+
+// describeNext renders the next scheduled prune for human output.
+func (p pruneSchedule) describeNext(now time.Time) string {
+	if p.Job == "" {
+		return "not scheduled (set retention.prune_interval_hours or add a prune job)"
+	}
+	next := p.Next()
+	if next.IsZero() {
+		return "when the daemon next starts"
+	}
+	if !next.After(now) {
+		return "due now"
+	}
+	return next.Local().Format("2006-01-02 15:04")
 }
 
 // Execute implements the go-flags Commander interface for PruneCommand.
 func (c *PruneCommand) Execute(args []string) error {
+	if c.ShowSchedule {
+		return c.showSchedule()
+	}
+
 	// Determine the retention duration.
 	var retention time.Duration
 	var olderThanLabel string
@@ -31,17 +108,23 @@ func (c *PruneCommand) Execute(args []string) error {
 		retention = d
 		olderThanLabel = c.OlderThan
 	} else {
-		retention = time.Duration(defaultRetentionDays) * 24 * time.Hour
-		olderThanLabel = fmt.Sprintf("%dd", defaultRetentionDays)
+		days := retentionDays(c.config())
+		retention = time.Duration(days) * 24 * time.Hour
+		olderThanLabel = fmt.Sprintf("%dd", days)
 	}
 
 	cutoff := time.Now().Add(-retention)
 	humanDur := formatDurationHuman(retention)
+	scope := ""
+	if c.Domain != "" {
+		scope = " from " + string(c.Domain)
+	}
+	filter := storage.PurgeFilter{Domain: string(c.Domain), Before: cutoff}
 
 	// Open store (use injected store for tests, default DB otherwise).
 	store := c.store
 	if store == nil {
-		s, db, err := openDefaultStore()
+		s, db, err := openStore(c.globals)
 		if err != nil {
 			return err
 		}
@@ -53,21 +136,33 @@ func (c *PruneCommand) Execute(args []string) error {
 	ctx := context.Background()
 
 	// Count events that would be pruned.
-	count, err := store.CountExpired(ctx, cutoff)
+	var count int64
+	var err error
+	if c.Domain != "" {
+		count, err = store.CountMatching(ctx, filter)
+	} else {
+		count, err = store.CountExpired(ctx, cutoff)
+	}
 	if err != nil {
 		return fmt.Errorf("count expired events: %w", err)
 	}
 
 	// Nothing to prune.
 	if count == 0 {
+		if !c.DryRun {
+			if err := c.record(ctx, store, 0); err != nil {
+				return err
+			}
+		}
 		if c.globals != nil && c.globals.JSON {
 			return json.NewEncoder(os.Stdout).Encode(pruneJSON{
 				Pruned:    0,
 				OlderThan: olderThanLabel,
 				DryRun:    c.DryRun,
+				Domain:    string(c.Domain),
 			})
 		}
-		fmt.Printf("No events to prune (older than %s).\n", humanDur)
+		infof(c.globals, "No events%s to prune (older than %s).\n", scope, humanDur)
 		return nil
 	}
 
@@ -78,15 +173,16 @@ func (c *PruneCommand) Execute(args []string) error {
 				Pruned:    count,
 				OlderThan: olderThanLabel,
 				DryRun:    true,
+				Domain:    string(c.Domain),
 			})
 		}
-		fmt.Printf("[DRY RUN] Would prune %d events older than %s.\n", count, humanDur)
+		fmt.Printf("[DRY RUN] Would prune %d events%s older than %s.\n", count, scope, humanDur)
 		return nil
 	}
 
 	// Confirmation prompt (unless --force).
 	if !c.Force {
-		fmt.Printf("Pruning events older than %s...\n", humanDur)
+		fmt.Printf("Pruning events%s older than %s...\n", scope, humanDur)
 		fmt.Printf("Found %d events to prune.\n", count)
 		fmt.Print("Proceed? [y/N] ")
 
@@ -104,19 +200,101 @@ func (c *PruneCommand) Execute(args []string) error {
 	}
 
 	// Execute prune.
-	pruned, err := store.PruneExpired(ctx, cutoff)
+	var pruned int64
+	if c.Domain != "" {
+		pruned, err = store.PurgeMatching(ctx, filter)
+	} else {
+		pruned, err = store.PruneExpired(ctx, cutoff)
+	}
 	if err != nil {
 		return fmt.Errorf("prune failed: %w", err)
 	}
+	slog.Info("pruned events", "count", pruned, "older_than", cutoff, "domain", c.Domain)
+	if err := c.record(ctx, store, pruned); err != nil {
+		return err
+	}
+	if c.Domain == "" {
+		notifyPrune(c.globals, c.config().Notifications, pruned)
+	}
 
 	if c.globals != nil && c.globals.JSON {
 		return json.NewEncoder(os.Stdout).Encode(pruneJSON{
 			Pruned:    pruned,
 			OlderThan: olderThanLabel,
 			DryRun:    false,
+			Domain:    string(c.Domain),
 		})
 	}
 
-	fmt.Printf("Pruned %d events older than %s.\n", pruned, humanDur)
+	infof(c.globals, "Pruned %d events%s older than %s.\n", pruned, scope, humanDur)
+	return nil
+}
+
+// config returns the injected config (tests) or the one selected by the
+// global flags.
+func (c *PruneCommand) config() *config.Config {
+	if c.cfg == nil {
+		c.cfg = loadConfig(c.globals)
+	}
+	return c.cfg
+}
+
+// retentionDays returns the configured retention.days, or the built-in
+// default when unset or invalid.
+func retentionDays(cfg *config.Config) int {
+	if cfg.Retention.Days > 0 {
+		return cfg.Retention.Days
+	}
+	return defaultRetentionDays
+}
+
+// record stores the result of a prune run. Domain-scoped prunes are not
+// the retention policy, so they are not recorded.
+func (c *PruneCommand) record(ctx context.Context, store storage.Store, pruned int64) error {
+	if c.Domain != "" {
+		return nil
+	}
+	if err := store.RecordPrune(ctx, storage.PruneRecord{At: time.Now(), Count: pruned}); err != nil {
+		return fmt.Errorf("record prune: %w", err)
+	}
+	return nil
+}
+
+// showSchedule prints when retention was last applied and when the daemon
+// will next apply it.
+func (c *PruneCommand) showSchedule() error {
+	store := c.store
+	if store == nil {
+		s, db, err := openStore(c.globals)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		defer s.Close()
+		store = s
+	}
+
+	sched, err := loadPruneSchedule(context.Background(), store, c.config())
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := pruneScheduleJSON{IntervalHours: sched.IntervalHours, Job: sched.Job, Schedule: sched.Schedule}
+		if sched.Last != nil {
+			out.LastPruneAt = sched.Last.At.UTC().Format(time.RFC3339)
+			out.LastPruneCount = sched.Last.Count
+		}
+		if next := sched.Next(); !next.IsZero() {
+			out.NextPruneAt = next.UTC().Format(time.RFC3339)
+		}
+		return json.NewEncoder(os.Stdout).Encode(out)
+	}
+
+	fmt.Printf("Last prune:  %s\n", sched.describeLast())
+	fmt.Printf("Next prune:  %s\n", sched.describeNext(time.Now()))
+	if sched.Job != "" {
+		fmt.Printf("Schedule:    %s (job %s)\n", sched.Schedule, sched.Job)
+	}
 	return nil
 }
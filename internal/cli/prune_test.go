@@ -10,6 +10,7 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -61,6 +62,7 @@ func setupPruneTest(t *testing.T, oldCount, recentCount int) (*PruneCommand, *st
 		globals: globals,
 		version: "test",
 		store:   store,
+		cfg:     config.DefaultConfig(),
 	}
 
 	return cmd, store
@@ -248,6 +250,130 @@ func TestPrune_InvalidOlderThan(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid duration")
 }
 
+// --- Domain-scoped prune ---
+
+func TestPrune_DomainScoped(t *testing.T) {
+	cmd, store := setupPruneTest(t, 0, 0)
+	ctx := context.Background()
+	now := time.Now()
+
+	events := []*storage.Event{
+		{URL: "https://reddit.com/r/a", Title: "Old noisy", Source: "extension", Timestamp: now.Add(-5 * 24 * time.Hour)},
+		{URL: "https://old.reddit.com/r/b", Title: "Old noisy sub", Source: "extension", Timestamp: now.Add(-5 * 24 * time.Hour)},
+		{URL: "https://reddit.com/r/c", Title: "New noisy", Source: "extension", Timestamp: now.Add(-1 * time.Hour)},
+		{URL: "https://go.dev/doc", Title: "Old other", Source: "extension", Timestamp: now.Add(-5 * 24 * time.Hour)},
+	}
+	for _, e := range events {
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+
+	cmd.Domain = "reddit.com"
+	cmd.OlderThan = "3d"
+	cmd.Force = true
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Contains(t, output, "Pruned 2 events from reddit.com older than 3 days")
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalEvents)
+}
+
+func TestPrune_DomainJSONDryRun(t *testing.T) {
+	cmd, _ := setupPruneTest(t, 5, 3)
+	cmd.Domain = "old1.com"
+	cmd.DryRun = true
+	cmd.globals.JSON = true
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(output)), &result))
+	assert.Equal(t, float64(1), result["pruned"])
+	assert.Equal(t, "old1.com", result["domain"])
+}
+
+// --- Prune schedule ---
+
+func TestPrune_RecordsLastPrune(t *testing.T) {
+	cmd, store := setupPruneTest(t, 5, 3)
+	cmd.Force = true
+
+	captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	rec, err := store.LastPrune(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, int64(5), rec.Count)
+	assert.WithinDuration(t, time.Now(), rec.At, time.Minute)
+}
+
+func TestPrune_DryRunDoesNotRecord(t *testing.T) {
+	cmd, store := setupPruneTest(t, 5, 3)
+	cmd.DryRun = true
+
+	captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	rec, err := store.LastPrune(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestPrune_ShowSchedule(t *testing.T) {
+	cmd, store := setupPruneTest(t, 0, 0)
+	cmd.cfg = config.DefaultConfig()
+	cmd.ShowSchedule = true
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Contains(t, output, "Last prune:  never")
+	assert.Contains(t, output, "Next prune:  when the daemon next starts")
+	assert.Contains(t, output, "Schedule:    @every 24h (job retention)")
+
+	last := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, store.RecordPrune(context.Background(), storage.PruneRecord{At: last, Count: 12}))
+
+	cmd.globals.JSON = true
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	var result pruneScheduleJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, int64(12), result.LastPruneCount)
+	assert.Equal(t, 24, result.IntervalHours)
+	next, err := time.Parse(time.RFC3339, result.NextPruneAt)
+	require.NoError(t, err)
+	assert.WithinDuration(t, last.Add(24*time.Hour), next, time.Second)
+	assert.Equal(t, "retention", result.Job)
+
+	// A configured prune job replaces the implicit one.
+	cmd.cfg.Jobs = []config.JobConfig{{Name: "nightly", Type: "prune", Schedule: "0 3 * * *"}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	result = pruneScheduleJSON{}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "nightly", result.Job)
+	assert.Equal(t, "0 3 * * *", result.Schedule)
+
+	cmd.cfg.Jobs, cmd.cfg.Retention.PruneIntervalHours = nil, 0
+	cmd.globals.JSON = false
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Contains(t, output, "Next prune:  not scheduled")
+}
+
 // --- parseDuration tests ---
 
 func TestPruneParseDuration_Days(t *testing.T) {
@@ -289,3 +415,28 @@ func TestPruneDryRunAndOlderThanFlags(t *testing.T) {
 	assert.True(t, c.Prune.DryRun)
 	assert.Equal(t, "14d", c.Prune.OlderThan)
 }
+
+// --- Configured retention ---
+
+func TestPrune_UsesConfiguredRetention(t *testing.T) {
+	cmd, store := setupPruneTest(t, 0, 0)
+	ctx := context.Background()
+	now := time.Now()
+
+	// 10 days old: kept under the 30-day default, pruned under 7 days.
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://a.com/", Title: "A", Source: "extension", Timestamp: now.Add(-10 * 24 * time.Hour)}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://b.com/", Title: "B", Source: "extension", Timestamp: now.Add(-1 * time.Hour)}))
+
+	cmd.cfg.Retention.Days = 7
+	cmd.Force = true
+	cmd.globals.JSON = true
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	var result pruneJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, int64(1), result.Pruned)
+	assert.Equal(t, "7d", result.OlderThan)
+}
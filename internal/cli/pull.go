@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/source"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// pullBatchSize is how many pulled events are stored per transaction.
+const pullBatchSize = 100
+
+// errPulled marks an event the store already holds.
+var errPulled = errors.New("already pulled")
+
+// pullResult summarizes one pull.
+type pullResult struct {
+	Source   string `json:"source"`
+	Fetched  int    `json:"fetched"`
+	Added    int    `json:"added"`
+	Existing int    `json:"existing"`
+	Skipped  int    `json:"skipped"`
+}
+
+// Execute implements the go-flags Commander interface for PullCommand.
+func (c *PullCommand) Execute(args []string) error {
+	cfg := loadConfig(c.globals)
+	c.webhooks = cfg.Webhooks
+	c.notifications = cfg.Notifications
+	c.hooks = cfg.Hooks
+	if c.List {
+		return c.list(cfg)
+	}
+	src, err := c.newSource(cfg)
+	if err != nil {
+		return err
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithSource(store, src)
+}
+
+// newSource builds the source named by --source: the configured source of
+// that name, else an unconfigured one of that adapter type, with the
+// --option values laid over its options.
+func (c *PullCommand) newSource(cfg *config.Config) (source.Source, error) {
+	if c.Source == "" {
+		return nil, fmt.Errorf("--source is required (see --list)")
+	}
+	typ, options := c.Source, map[string]string{}
+	for _, sc := range cfg.Sources {
+		if sc.Name != c.Source {
+			continue
+		}
+		if sc.Type != "" {
+			typ = sc.Type
+		}
+		for k, v := range sc.Options {
+			options[k] = v
+		}
+		break
+	}
+	for _, opt := range c.Options {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid --option %q (want key=value)", opt)
+		}
+		options[k] = v
+	}
+	return source.New(typ, c.Source, options)
+}
+
+// executeWithSource pulls src into a provided store (used by tests). The
+// events are checked and prepared by a pool of workers and stored in
+// order, pullBatchSize per transaction.
+func (c *PullCommand) executeWithSource(store *storage.SQLiteStore, src source.Source) error {
+	ctx := context.Background()
+	events, err := src.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("pull %s: %w", src.Name(), err)
+	}
+
+	pipeline := newPipeline(c.globals, store, c.hooks, c.webhooks, c.notifications)
+	res := pullResult{Source: src.Name(), Fetched: len(events)}
+
+	// The workers look for each event in the store, so one pulled twice in
+	// this fetch is counted here, as the store would not hold it yet.
+	var todo []source.CaptureEvent
+	seen := map[string]bool{}
+	for _, ce := range events {
+		key := fmt.Sprintf("%d %s", ce.Timestamp.Unix(), normalizeDupURL(ce.URL))
+		switch {
+		case ce.Timestamp.IsZero():
+			res.Skipped++
+		case seen[key]:
+			res.Existing++
+		default:
+			seen[key] = true
+			todo = append(todo, ce)
+		}
+	}
+	prog := newProgress(c.globals, "Pulling", len(events))
+	prog.Add(len(events) - len(todo))
+	records := make(chan source.CaptureEvent)
+	go func() {
+		defer close(records)
+		for _, ce := range todo {
+			records <- ce
+		}
+	}()
+
+	parse := func(ctx context.Context, ce source.CaptureEvent) (*storage.Event, string, error) {
+		exists, err := pulledEventExists(ctx, store, &ce)
+		if err != nil {
+			return nil, "", err
+		}
+		if exists {
+			return nil, "", errPulled
+		}
+		return &storage.Event{URL: ce.URL, Title: ce.Title, Timestamp: ce.Timestamp, Source: src.Name()}, ce.Body, nil
+	}
+	var storeErr error
+	capture.Import(ctx, pipeline, records, 0, pullBatchSize, parse, func(ce source.CaptureEvent, e *storage.Event, err error) {
+		prog.Add(1)
+		switch {
+		case errors.Is(err, errPulled):
+			res.Existing++
+		case errors.Is(err, hook.ErrVetoed), errors.Is(err, capture.ErrExcluded):
+			res.Skipped++
+		case err != nil:
+			if storeErr == nil {
+				storeErr = err
+			}
+		default:
+			res.Added++
+		}
+	})
+	prog.Finish()
+	if storeErr != nil {
+		return storeErr
+	}
+	slog.Info("pull finished", "source", res.Source, "fetched", res.Fetched, "added", res.Added,
+		"existing", res.Existing, "skipped", res.Skipped)
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	infof(c.globals, "Pulled %d new events from %s (%d already present)\n", res.Added, res.Source, res.Existing)
+	if res.Skipped > 0 {
+		infof(c.globals, "Skipped %d events that were excluded, vetoed by a hook, or without a time\n", res.Skipped)
+	}
+	return nil
+}
+
+// pulledEventExists reports whether the store already holds ce: an event
+// at the same second with the same normalized URL.
+func pulledEventExists(ctx context.Context, store *storage.SQLiteStore, ce *source.CaptureEvent) (bool, error) {
+	candidates, err := store.EventsAt(ctx, ce.Timestamp)
+	if err != nil {
+		return false, err
+	}
+	key := normalizeDupURL(ce.URL)
+	for _, e := range candidates {
+		if normalizeDupURL(e.URL) == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// list prints the configured sources and the adapter types.
+func (c *PullCommand) list(cfg *config.Config) error {
+	type sourceJSON struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	out := struct {
+		Sources []sourceJSON `json:"sources"`
+		Types   []string     `json:"types"`
+	}{Sources: []sourceJSON{}, Types: source.Types()}
+	for _, sc := range cfg.Sources {
+		typ := sc.Type
+		if typ == "" {
+			typ = sc.Name
+		}
+		out.Sources = append(out.Sources, sourceJSON{Name: sc.Name, Type: typ})
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(out.Sources) == 0 {
+		fmt.Println("No sources configured.")
+	} else {
+		fmt.Println("Configured sources:")
+		for _, s := range out.Sources {
+			fmt.Printf("  %-20s %s\n", s.Name, s.Type)
+		}
+	}
+	fmt.Printf("Adapter types: %s\n", strings.Join(out.Types, ", "))
+	return nil
+}
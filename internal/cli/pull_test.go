@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/source"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+type fakeSource struct {
+	name   string
+	events []source.CaptureEvent
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Fetch(ctx context.Context) ([]source.CaptureEvent, error) {
+	return f.events, nil
+}
+
+func TestPull_AddsNewEventsOnce(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	src := &fakeSource{name: "feed", events: []source.CaptureEvent{
+		{URL: "https://example.com/tides", Title: "Tides", Timestamp: at, Body: "The sea rises twice a day."},
+		{URL: "https://example.com/moon", Title: "Moon", Timestamp: at.Add(time.Hour)},
+		{URL: "https://example.com/undated", Title: "Undated"},
+	}}
+
+	cmd := &PullCommand{globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithSource(store, src))
+	})
+	assert.Contains(t, output, "Pulled 2 new events from feed (0 already present)")
+	assert.Contains(t, output, "Skipped 1 events that were excluded, vetoed by a hook, or without a time")
+
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Source: "feed", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		assert.Equal(t, e.URL == "https://example.com/tides", e.HasBody, e.URL)
+	}
+
+	cmd.globals.JSON = true
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithSource(store, src))
+	})
+	var res pullResult
+	require.NoError(t, json.Unmarshal([]byte(output), &res))
+	assert.Equal(t, pullResult{Source: "feed", Fetched: 3, Added: 0, Existing: 2, Skipped: 1}, res)
+}
+
+func TestPull_NewSource(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, os.WriteFile(history, []byte(": 1772443800:0;open https://example.com\n"), 0600))
+	cfg := config.DefaultConfig()
+	cfg.Sources = []config.SourceConfig{
+		{Name: "work-shell", Type: "shell", Options: map[string]string{"path": "/nonexistent"}},
+	}
+
+	cmd := &PullCommand{Source: "work-shell", Options: []string{"path=" + history}}
+	src, err := cmd.newSource(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "work-shell", src.Name())
+	events, err := src.Fetch(context.Background())
+	require.NoError(t, err, "--option overrides the configured path")
+	assert.Len(t, events, 1)
+
+	_, err = (&PullCommand{Source: "shell", Options: []string{"path"}}).newSource(cfg)
+	assert.ErrorContains(t, err, `invalid --option "path" (want key=value)`)
+	_, err = (&PullCommand{Source: "nope"}).newSource(cfg)
+	assert.ErrorContains(t, err, `unknown source type "nope"`)
+	_, err = (&PullCommand{}).newSource(cfg)
+	assert.ErrorContains(t, err, "--source is required")
+}
+
+func TestPull_List(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sources = []config.SourceConfig{{Name: "rss", Options: map[string]string{"url": "https://example.com/feed"}}}
+
+	cmd := &PullCommand{List: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.list(cfg))
+	})
+	assert.Contains(t, output, "Configured sources:\n  rss                  rss\n")
+	assert.Contains(t, output, "Adapter types: rss, shell\n")
+}
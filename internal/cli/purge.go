@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -19,8 +20,16 @@ func (c *PurgeCommand) setDB(db *sql.DB) {
 
 // Execute implements the go-flags Commander interface for PurgeCommand.
 func (c *PurgeCommand) Execute(args []string) error {
-	if !c.All {
-		return fmt.Errorf("purge requires --all flag for safety")
+	selective := c.Domain != "" || c.Source != ""
+	if c.All && selective {
+		return fmt.Errorf("--all cannot be combined with --domain or --source")
+	}
+	if !c.All && !selective {
+		return fmt.Errorf("purge requires --all flag for safety (or --domain/--source for a selective purge)")
+	}
+
+	if selective {
+		return c.executeSelective()
 	}
 
 	// Confirmation prompt unless --force
@@ -32,43 +41,22 @@ func (c *PurgeCommand) Execute(args []string) error {
 		fmt.Println()
 		fmt.Println("This action cannot be undone.")
 		fmt.Println()
-		fmt.Print(`Type "PURGE" to confirm: `)
-
-		scanner := bufio.NewScanner(os.Stdin)
-		if !scanner.Scan() {
-			return fmt.Errorf("aborted: no input received")
-		}
-		input := strings.TrimSpace(scanner.Text())
-		if input != "PURGE" {
-			return fmt.Errorf("aborted: confirmation text did not match")
+		if err := confirmPurge(); err != nil {
+			return err
 		}
 	}
 
-	// Open or use injected DB
-	var store *storage.SQLiteStore
-	var db *sql.DB
-	if c.db != nil {
-		db = c.db
-		var err error
-		store, err = storage.NewSQLiteStore(db)
-		if err != nil {
-			return fmt.Errorf("init store: %w", err)
-		}
-		defer store.Close()
-	} else {
-		var err error
-		store, db, err = openDefaultStore()
-		if err != nil {
-			return err
-		}
-		defer db.Close()
-		defer store.Close()
+	store, cleanup, err := c.openStore()
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
 	ctx := context.Background()
 	if err := store.PurgeAll(ctx); err != nil {
 		return fmt.Errorf("purge failed: %w", err)
 	}
+	slog.Info("purged all data")
 
 	// Output
 	if c.globals.JSON {
@@ -80,7 +68,111 @@ func (c *PurgeCommand) Execute(args []string) error {
 		return enc.Encode(out)
 	}
 
-	fmt.Println("Purged all data. Chronicle is empty.")
+	infof(c.globals, "Purged all data. Chronicle is empty.\n")
 	return nil
 }
 
+// executeSelective purges only the events matching --domain/--source.
+func (c *PurgeCommand) executeSelective() error {
+	filter := storage.PurgeFilter{Domain: string(c.Domain), Source: c.Source}
+	label := purgeFilterLabel(filter)
+
+	store, cleanup, err := c.openStore()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := checkFilters(ctx, store, c.Source, nil); err != nil {
+		return err
+	}
+	count, err := store.CountMatching(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count matching events: %w", err)
+	}
+
+	if count > 0 && !c.Force {
+		fmt.Printf("\u26a0 WARNING: This will permanently delete %d events matching %s,\n", count, label)
+		fmt.Println("  along with their captured content and embeddings.")
+		fmt.Println()
+		fmt.Println("This action cannot be undone.")
+		fmt.Println()
+		if err := confirmPurge(); err != nil {
+			return err
+		}
+	}
+
+	var purged int64
+	if count > 0 {
+		purged, err = store.PurgeMatching(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("purge failed: %w", err)
+		}
+	}
+	slog.Info("purged matching events", "count", purged, "filter", label)
+
+	if c.globals.JSON {
+		out := map[string]interface{}{
+			"purged":  true,
+			"deleted": purged,
+			"domain":  c.Domain,
+			"source":  c.Source,
+		}
+		enc := json.NewEncoder(os.Stdout)
+		return enc.Encode(out)
+	}
+
+	if purged == 0 {
+		infof(c.globals, "No events match %s.\n", label)
+		return nil
+	}
+	infof(c.globals, "Purged %d events matching %s.\n", purged, label)
+	return nil
+}
+
+// openStore returns a store over the injected DB, or the database selected
+// by the global flags when none was injected, plus a cleanup function.
+func (c *PurgeCommand) openStore() (*storage.SQLiteStore, func(), error) {
+	if c.db != nil {
+		store, err := storage.NewSQLiteStore(c.db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("init store: %w", err)
+		}
+		return store, func() { store.Close() }, nil
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, func() { store.Close(); db.Close() }, nil
+}
+
+// confirmPurge asks the user to type PURGE on stdin.
+func confirmPurge() error {
+	fmt.Print(`Type "PURGE" to confirm: `)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return fmt.Errorf("aborted: no input received")
+	}
+	input := strings.TrimSpace(scanner.Text())
+	if input != "PURGE" {
+		return fmt.Errorf("aborted: confirmation text did not match")
+	}
+	return nil
+}
+
+// purgeFilterLabel describes a purge filter for messages, e.g.
+// "domain=example.com source=import".
+func purgeFilterLabel(f storage.PurgeFilter) string {
+	var parts []string
+	if f.Domain != "" {
+		parts = append(parts, "domain="+f.Domain)
+	}
+	if f.Source != "" {
+		parts = append(parts, "source="+f.Source)
+	}
+	return strings.Join(parts, " ")
+}
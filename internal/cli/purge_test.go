@@ -155,3 +155,78 @@ func TestPurge_DBIsEmptyAfterPurge(t *testing.T) {
 	assert.Equal(t, 0, eventCount, "events table should be empty")
 	assert.Equal(t, 0, contentCount, "content table should be empty")
 }
+
+func TestPurge_SelectiveByDomain(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, row := range []struct{ id, domain, source string }{
+		{"CHR-dd01", "example.com", "extension"},
+		{"CHR-dd02", "www.example.com", "import"},
+		{"CHR-dd03", "other.org", "import"},
+	} {
+		_, err := db.Exec(`INSERT INTO events (id, ts, url, title, domain, browser, source, has_body, has_embedding)
+			VALUES (?, '2025-01-01T00:00:00Z', ?, 'Test', ?, 'chrome', ?, 0, 0)`,
+			row.id, "https://"+row.domain+"/", row.domain, row.source)
+		require.NoError(t, err)
+	}
+
+	cmd := &PurgeCommand{Domain: "example.com", Force: true, globals: &GlobalFlags{JSON: true}}
+	cmd.setDB(db)
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, float64(2), result["deleted"])
+	assert.Equal(t, "example.com", result["domain"])
+
+	var remaining string
+	require.NoError(t, db.QueryRow("SELECT id FROM events").Scan(&remaining))
+	assert.Equal(t, "CHR-dd03", remaining)
+}
+
+func TestPurge_SelectiveBySource(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, row := range []struct{ id, source string }{
+		{"CHR-ss01", "import"},
+		{"CHR-ss02", "manual"},
+	} {
+		_, err := db.Exec(`INSERT INTO events (id, ts, url, title, domain, browser, source, has_body, has_embedding)
+			VALUES (?, '2025-01-01T00:00:00Z', 'https://example.com', 'Test', 'example.com', 'chrome', ?, 0, 0)`,
+			row.id, row.source)
+		require.NoError(t, err)
+	}
+
+	cmd := &PurgeCommand{Source: "import", Force: true, globals: &GlobalFlags{}}
+	cmd.setDB(db)
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Contains(t, output, "Purged 1 events matching source=import")
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM events").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestPurge_SelectiveNoMatches(t *testing.T) {
+	db := openTestDB(t)
+
+	cmd := &PurgeCommand{Domain: "nowhere.com", globals: &GlobalFlags{}}
+	cmd.setDB(db)
+
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.Execute(nil))
+	})
+	assert.Contains(t, output, "No events match domain=nowhere.com")
+}
+
+func TestPurge_AllWithFilterErrors(t *testing.T) {
+	err := RunWithArgs("test", []string{"purge", "--all", "--domain", "example.com"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined")
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/replicate"
+)
+
+// snapshotJSON is the JSON form of one replica snapshot.
+type snapshotJSON struct {
+	Name string `json:"name"`
+	Time string `json:"time"`
+}
+
+// Execute implements the go-flags Commander interface for ReplicateCommand.
+func (c *ReplicateCommand) Execute(args []string) error {
+	cfg := loadConfig(c.globals)
+	dest := c.To
+	if dest == "" {
+		dest = cfg.Storage.ReplicaURL
+	}
+	replica, err := replicate.Open(dest)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if c.List || c.Restore != "" {
+		return c.executeWithReplica(ctx, replica, nil, cfg, time.Now())
+	}
+
+	db, _, err := openExistingDB(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return c.executeWithReplica(ctx, replica, db, cfg, time.Now())
+}
+
+// executeWithReplica runs the command against a provided replica and
+// database (used by tests). Replication runs until ctx is cancelled unless
+// --once is set.
+func (c *ReplicateCommand) executeWithReplica(ctx context.Context, replica replicate.Replica, db *sql.DB, cfg *config.Config, now time.Time) error {
+	if c.At != "" && c.Restore == "" {
+		return fmt.Errorf("--at requires --restore")
+	}
+	switch {
+	case c.List:
+		return c.list(ctx, replica)
+	case c.Restore != "":
+		return c.restore(ctx, replica, now)
+	}
+
+	interval := time.Duration(cfg.Storage.ReplicaIntervalMinutes) * time.Minute
+	if c.Interval != "" {
+		d, err := parseDuration(c.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid --interval: %w", err)
+		}
+		interval = d
+	}
+	if interval <= 0 && !c.Once {
+		return fmt.Errorf("replication interval must be positive")
+	}
+
+	r := replicate.New(db, replica, cfg.Storage.ReplicaRetain)
+	if c.Once {
+		return c.snapshot(ctx, r, now)
+	}
+
+	noticef(c.globals, "Replicating every %s (Ctrl-C to stop)\n", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.snapshot(ctx, r, time.Now()); err != nil {
+			// Keep going: the next attempt may succeed (e.g. network back).
+			slog.Warn("snapshot failed", "err", err)
+			noticef(c.globals, "Warning: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ReplicateCommand) snapshot(ctx context.Context, r *replicate.Replicator, now time.Time) error {
+	snap, uploaded, err := r.Snapshot(ctx, now)
+	if err != nil {
+		return err
+	}
+	if !uploaded {
+		infof(c.globals, "No changes since the last snapshot\n")
+		return nil
+	}
+	slog.Info("snapshot replicated", "name", snap.Name)
+	infof(c.globals, "Snapshot %s\n", snap.Name)
+	return nil
+}
+
+func (c *ReplicateCommand) list(ctx context.Context, replica replicate.Replica) error {
+	snaps, err := replicate.List(ctx, replica)
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		out := make([]snapshotJSON, len(snaps))
+		for i, s := range snaps {
+			out[i] = snapshotJSON{Name: s.Name, Time: s.Time.Format(time.RFC3339)}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(snaps) == 0 {
+		infof(c.globals, "No snapshots in the replica.\n")
+		return nil
+	}
+	for _, s := range snaps {
+		fmt.Printf("%s  %s\n", s.Time.Local().Format("2006-01-02 15:04:05"), s.Name)
+	}
+	return nil
+}
+
+func (c *ReplicateCommand) restore(ctx context.Context, replica replicate.Replica, now time.Time) error {
+	var at time.Time
+	if c.At != "" {
+		t, err := time.Parse(time.RFC3339, c.At)
+		if err != nil {
+			d, derr := parseDuration(c.At)
+			if derr != nil {
+				return fmt.Errorf("invalid --at %q: use RFC 3339 or a duration like 2h", c.At)
+			}
+			t = now.Add(-d)
+		}
+		at = t
+	}
+
+	snap, err := replicate.Restore(ctx, replica, at, c.Restore)
+	if errors.Is(err, replicate.ErrNoSnapshot) {
+		if at.IsZero() {
+			return fmt.Errorf("the replica has no snapshots")
+		}
+		return fmt.Errorf("no snapshot at or before %s", at.Local().Format("2006-01-02 15:04:05"))
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]string{"snapshot": snap.Name, "time": snap.Time.Format(time.RFC3339), "path": c.Restore})
+	}
+	infof(c.globals, "Restored %s to %s\n", snap.Name, c.Restore)
+	return nil
+}
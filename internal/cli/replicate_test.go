@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/replicate"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// replicaTestDB creates a seeded database file and opens it read-only, the
+// way replicate does.
+func replicaTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "live.db")
+	store, rw, err := openStoreAt(dbPath, storage.Pragmas{})
+	require.NoError(t, err)
+	seedDigestEvents(t, store, time.Now())
+	store.Close()
+	rw.Close()
+
+	db, _, err := openExistingDB(&GlobalFlags{DBPath: dbPath})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestReplicate_OnceListRestore(t *testing.T) {
+	db := replicaTestDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	replica := replicate.NewDir(t.TempDir())
+	cfg := config.DefaultConfig()
+	ctx := context.Background()
+
+	cmd := &ReplicateCommand{Once: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithReplica(ctx, replica, db, cfg, now))
+	})
+	assert.Equal(t, "Snapshot snapshots/20260301T120000Z.db.gz\n", output)
+
+	cmd = &ReplicateCommand{List: true, globals: &GlobalFlags{JSON: true}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithReplica(ctx, replica, nil, cfg, now))
+	})
+	var snaps []snapshotJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &snaps))
+	require.Len(t, snaps, 1)
+	assert.Equal(t, "2026-03-01T12:00:00Z", snaps[0].Time)
+
+	restored := filepath.Join(t.TempDir(), "restored.db")
+	cmd = &ReplicateCommand{Restore: restored, At: "2026-03-01T12:30:00Z", globals: &GlobalFlags{}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithReplica(ctx, replica, nil, cfg, now))
+	})
+	assert.Contains(t, output, "Restored snapshots/20260301T120000Z.db.gz to "+restored)
+
+	rdb, err := sql.Open("sqlite3", restored)
+	require.NoError(t, err)
+	defer rdb.Close()
+	var n int
+	require.NoError(t, rdb.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n))
+	assert.Equal(t, 4, n)
+
+	cmd = &ReplicateCommand{Restore: filepath.Join(t.TempDir(), "x.db"), At: "1h", globals: &GlobalFlags{}}
+	err = cmd.executeWithReplica(ctx, replica, nil, cfg, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no snapshot at or before")
+}
+
+func TestReplicate_LoopStopsOnCancel(t *testing.T) {
+	db := replicaTestDB(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	replica := replicate.NewDir(t.TempDir())
+	cmd := &ReplicateCommand{Interval: "1h", globals: &GlobalFlags{Quiet: true}}
+	require.NoError(t, cmd.executeWithReplica(ctx, replica, db, config.DefaultConfig(), time.Now()))
+
+	snaps, err := replicate.List(context.Background(), replica)
+	require.NoError(t, err)
+	assert.Len(t, snaps, 1, "a snapshot is taken before waiting")
+}
+
+func TestReplicate_Validation(t *testing.T) {
+	ctx := context.Background()
+	replica := replicate.NewDir(t.TempDir())
+	cfg := config.DefaultConfig()
+
+	err := (&ReplicateCommand{At: "2h", globals: &GlobalFlags{}}).executeWithReplica(ctx, replica, nil, cfg, time.Now())
+	assert.EqualError(t, err, "--at requires --restore")
+
+	err = (&ReplicateCommand{Restore: filepath.Join(t.TempDir(), "r.db"), globals: &GlobalFlags{}}).executeWithReplica(ctx, replica, nil, cfg, time.Now())
+	assert.EqualError(t, err, "the replica has no snapshots")
+
+	err = (&ReplicateCommand{Interval: "soon", globals: &GlobalFlags{}}).executeWithReplica(ctx, replica, nil, cfg, time.Now())
+	assert.Error(t, err)
+}
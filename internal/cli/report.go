@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// reportJSON is the JSON output structure for the report command.
+type reportJSON struct {
+	Path       string `json:"path"`
+	Period     string `json:"period"`
+	Since      string `json:"since"`
+	Until      string `json:"until"`
+	Events     int64  `json:"events"`
+	Domains    int    `json:"domains"`
+	NewDomains int    `json:"new_domains"`
+	LongReads  int    `json:"long_reads"`
+	Pinned     int    `json:"pinned"`
+}
+
+// report holds everything a report renders.
+type report struct {
+	Period    string
+	Since     time.Time
+	Until     time.Time
+	Days      []storage.DayCount
+	Domains   []storage.DomainCount
+	New       []storage.DomainCount
+	LongReads []storage.LongRead
+	Pinned    []storage.Event
+	Limit     int
+}
+
+// Execute implements the go-flags Commander interface for ReportCommand.
+func (c *ReportCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore builds the report for the period ending at now against a
+// provided store (used by tests).
+func (c *ReportCommand) executeWithStore(store *storage.SQLiteStore, now time.Time) error {
+	period, err := digestPeriod(c.Period)
+	if err != nil {
+		return err
+	}
+	if c.Limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	r, err := buildReport(context.Background(), store, c.Period, now.Add(-period), now, c.Limit)
+	if err != nil {
+		return err
+	}
+
+	path := c.Out
+	if path == "" {
+		path = fmt.Sprintf("chronicle-report-%s-%s.md", c.Period, now.Local().Format("2006-01-02"))
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	writeReport(w, r)
+
+	if path == "-" {
+		return nil
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reportJSON{
+			Path:       path,
+			Period:     c.Period,
+			Since:      r.Since.UTC().Format(time.RFC3339),
+			Until:      r.Until.UTC().Format(time.RFC3339),
+			Events:     r.total(),
+			Domains:    len(r.Domains),
+			NewDomains: len(r.New),
+			LongReads:  len(r.LongReads),
+			Pinned:     len(r.Pinned),
+		})
+	}
+
+	infof(c.globals, "Wrote %s report (%d events, %d domains) to %s\n", c.Period, r.total(), len(r.Domains), path)
+	return nil
+}
+
+// buildReport gathers the report sections for since through until.
+func buildReport(ctx context.Context, store *storage.SQLiteStore, period string, since, until time.Time, limit int) (*report, error) {
+	r := &report{Period: period, Since: since, Until: until, Limit: limit}
+
+	var err error
+	if r.Days, err = store.EventsPerDay(ctx, since, until); err != nil {
+		return nil, err
+	}
+	if r.Domains, err = store.DomainCounts(ctx, since, until, 0); err != nil {
+		return nil, err
+	}
+	if r.New, err = store.NewDomains(ctx, since, until); err != nil {
+		return nil, err
+	}
+	if r.LongReads, err = store.LongestReads(ctx, since, until, limit); err != nil {
+		return nil, err
+	}
+	if r.Pinned, err = store.PinnedEvents(ctx, since, until, limit); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// total is the number of events in the report period.
+func (r *report) total() int64 {
+	var n int64
+	for _, d := range r.Days {
+		n += d.Count
+	}
+	return n
+}
+
+// writeReport renders the report as markdown.
+func writeReport(w io.Writer, r *report) {
+	fmt.Fprintf(w, "# Chronicle %s report: %s – %s\n\n", r.Period,
+		r.Since.Local().Format("2006-01-02"), r.Until.Local().Format("2006-01-02"))
+	fmt.Fprintf(w, "%s captures across %d domains, %d of them new.\n\n", formatNumber(r.total()), len(r.Domains), len(r.New))
+
+	if r.total() == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## Activity")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Day | Captures |")
+	fmt.Fprintln(w, "|-----|---------:|")
+	for _, d := range r.Days {
+		fmt.Fprintf(w, "| %s | %s |\n", d.Day.Format("Mon 2006-01-02"), formatNumber(d.Count))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "## Top domains")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Domain | Captures |")
+	fmt.Fprintln(w, "|--------|---------:|")
+	for i, d := range r.Domains {
+		if i == r.Limit {
+			break
+		}
+		fmt.Fprintf(w, "| %s | %s |\n", reportDomain(d.Domain), formatNumber(d.Count))
+	}
+	fmt.Fprintln(w)
+
+	if len(r.LongReads) > 0 {
+		fmt.Fprintln(w, "## Long reads")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "By estimated reading time of the captured text; time spent on a page is not recorded.")
+		fmt.Fprintln(w)
+		for _, e := range r.LongReads {
+			fmt.Fprintf(w, "- %s — %s · %s\n", reportLink(&e.Event), reportDomain(e.Domain), formatReadTime(e.ReadTime))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.New) > 0 {
+		fmt.Fprintln(w, "## New domains")
+		fmt.Fprintln(w)
+		for i, d := range r.New {
+			if i == r.Limit {
+				fmt.Fprintf(w, "- …and %d more\n", len(r.New)-r.Limit)
+				break
+			}
+			fmt.Fprintf(w, "- %s (%s)\n", reportDomain(d.Domain), formatNumber(d.Count))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.Pinned) > 0 {
+		fmt.Fprintln(w, "## Pinned")
+		fmt.Fprintln(w)
+		for i := range r.Pinned {
+			e := &r.Pinned[i]
+			fmt.Fprintf(w, "- %s — %s · %s\n", reportLink(e), reportDomain(e.Domain), e.Timestamp.Local().Format("Mon 2006-01-02"))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// reportLink renders e as a markdown link titled with its title, or its
+// URL when it has none.
+func reportLink(e *storage.Event) string {
+	title := e.Title
+	if title == "" {
+		title = e.URL
+	}
+	return fmt.Sprintf("[%s](%s)", title, e.URL)
+}
+
+// reportDomain renders a domain for the report, naming the empty one.
+func reportDomain(domain string) string {
+	if domain == "" {
+		return "(no domain)"
+	}
+	return domain
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestReport_WeekSections(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	long := &storage.Event{URL: "https://blog.example.com/essay", Title: "An essay", Source: "extension", Timestamp: now.Add(-5 * time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, long, strings.Repeat("word ", 3000)))
+
+	out := filepath.Join(t.TempDir(), "report.md")
+	cmd := &ReportCommand{Period: "week", Out: out, Limit: 10, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Contains(t, output, "4 events, 3 domains")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	md := string(data)
+
+	assert.Contains(t, md, "# Chronicle week report")
+	assert.Contains(t, md, "4 captures across 3 domains, 3 of them new.")
+	assert.Contains(t, md, "## Activity")
+	assert.Contains(t, md, "| github.com | 2 |")
+	assert.Contains(t, md, "## Long reads\n\nBy estimated reading time of the captured text; time spent on a page is not recorded.\n\n- [An essay](https://blog.example.com/essay) — blog.example.com · 13 min read")
+	assert.NotContains(t, md, "## Pinned", "no section without pinned pages")
+	assert.Contains(t, md, "## New domains\n\n- github.com (2)\n- blog.example.com (1)\n- news.ycombinator.com (1)\n")
+	assert.NotContains(t, md, "old.example.com", "captures before the period are excluded")
+	assert.Less(t, strings.Index(md, "| github.com"), strings.Index(md, "| blog.example.com"))
+}
+
+func TestReport_Pinned(t *testing.T) {
+	store, db := setupStatusTest(t)
+	ctx := context.Background()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	pinned := &storage.Event{URL: "https://example.com/keep", Title: "Keep this", Source: "extension", Timestamp: now.Add(-3 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, pinned))
+	_, err := db.Exec(`UPDATE events SET pinned = 1 WHERE id = ?`, pinned.ID)
+	require.NoError(t, err)
+
+	cmd := &ReportCommand{Period: "week", Out: "-", Limit: 10, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Contains(t, output, "## Pinned\n\n- [Keep this](https://example.com/keep) — example.com · ")
+	assert.Less(t, strings.Index(output, "## New domains"), strings.Index(output, "## Pinned"))
+	assert.Equal(t, 1, strings.Count(output, "Keep this"), "pinned pages are listed once")
+}
+
+func TestReport_StdoutAndJSON(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	now := time.Now()
+	seedDigestEvents(t, store, now)
+
+	cmd := &ReportCommand{Period: "day", Out: "-", Limit: 10, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.True(t, strings.HasPrefix(output, "# Chronicle day report"))
+	assert.Contains(t, output, "1 captures across 1 domains")
+
+	out := filepath.Join(t.TempDir(), "report.md")
+	cmd = &ReportCommand{Period: "week", Out: out, Limit: 10, globals: &GlobalFlags{JSON: true}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var res reportJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &res))
+	assert.Equal(t, int64(3), res.Events)
+	assert.Equal(t, 2, res.Domains)
+	assert.Equal(t, out, res.Path)
+}
+
+func TestReport_InvalidPeriod(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cmd := &ReportCommand{Period: "year", Out: "-", Limit: 10, globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --period")
+}
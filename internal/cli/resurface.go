@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// maxResurfaceScan caps how many past pages are weighed per run.
+const maxResurfaceScan = 20000
+
+type resurfaceJSON struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Domain    string `json:"domain"`
+	Timestamp string `json:"timestamp"`
+	Visits    int    `json:"visits"`
+	BodyBytes int64  `json:"body_bytes"`
+}
+
+// Execute implements the go-flags Commander interface for ResurfaceCommand.
+func (c *ResurfaceCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore resurfaces captures from a provided store (used by
+// tests).
+func (c *ResurfaceCommand) executeWithStore(store *storage.SQLiteStore, now time.Time) error {
+	if c.Count <= 0 {
+		return fmt.Errorf("--count must be positive")
+	}
+	since, _, err := resolveTimeRange(c.Since, "", now)
+	if err != nil {
+		return err
+	}
+	minAge, err := parseDuration(c.MinAge)
+	if err != nil {
+		return fmt.Errorf("invalid --min-age: %w", err)
+	}
+	until := now.Add(-minAge)
+	if !until.After(since) {
+		return fmt.Errorf("--min-age %s reaches back past --since %s; nothing to resurface", c.MinAge, c.Since)
+	}
+
+	pages, err := store.PastCaptures(context.Background(), since, until, maxResurfaceScan)
+	if err != nil {
+		return err
+	}
+	seed := c.Seed
+	if seed == 0 {
+		seed = now.UnixNano()
+	}
+	picked := weightedSample(pages, c.Count, rand.New(rand.NewSource(seed)))
+
+	if c.globals != nil && c.globals.JSON {
+		out := []resurfaceJSON{}
+		for _, p := range picked {
+			out = append(out, resurfaceJSON{
+				ID:        p.ID,
+				URL:       p.URL,
+				Title:     p.Title,
+				Domain:    p.Domain,
+				Timestamp: p.Timestamp.UTC().Format(time.RFC3339),
+				Visits:    p.Visits,
+				BodyBytes: p.Bytes,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(picked) == 0 {
+		infof(c.globals, "Nothing to resurface between %s and %s ago\n", c.Since, c.MinAge)
+		return nil
+	}
+	infof(c.globals, "From your history (%s to %s ago):\n\n", c.Since, c.MinAge)
+	for i, p := range picked {
+		fmt.Printf("%d. %s", i+1, p.Title)
+		if p.Domain != "" {
+			fmt.Printf(" — %s", p.Domain)
+		}
+		fmt.Printf("\n   %s\n", p.PageURL())
+		meta := fmt.Sprintf("%s (%s ago)", p.Timestamp.Local().Format("2006-01-02"), formatDurationHuman(now.Sub(p.Timestamp)))
+		if p.ReadTime > 0 {
+			meta += " · " + formatReadTime(p.ReadTime)
+		}
+		if p.Visits > 1 {
+			meta += fmt.Sprintf(" · %d visits", p.Visits)
+		}
+		fmt.Printf("   %s\n", meta)
+		if i < len(picked)-1 {
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// resurfaceWeight favours pages that held attention: a captured body,
+// more so the longer it is (dwell time is not captured, so size stands in
+// for it as in report's long reads), and repeat visits.
+func resurfaceWeight(p storage.PastCapture) float64 {
+	w := 1.0
+	if p.HasBody {
+		w += 1 + math.Log2(1+float64(p.Bytes)/1024)
+	}
+	if p.Visits > 1 {
+		w += math.Log2(float64(p.Visits))
+	}
+	return w
+}
+
+// weightedSample draws up to n pages without replacement, each with
+// probability proportional to its resurfaceWeight (Efraimidis–Spirakis:
+// keep the n largest u^(1/w)), in the order drawn.
+func weightedSample(pages []storage.PastCapture, n int, rng *rand.Rand) []storage.PastCapture {
+	type keyed struct {
+		key  float64
+		page storage.PastCapture
+	}
+	all := make([]keyed, len(pages))
+	for i, p := range pages {
+		all[i] = keyed{math.Pow(rng.Float64(), 1/resurfaceWeight(p)), p}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].key > all[j].key })
+	if len(all) > n {
+		all = all[:n]
+	}
+	out := make([]storage.PastCapture, len(all))
+	for i, k := range all {
+		out[i] = k.page
+	}
+	return out
+}
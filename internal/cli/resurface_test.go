@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedResurfaceEvents(t *testing.T, store *storage.SQLiteStore, now time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	day := 24 * time.Hour
+	add := func(url, title string, age time.Duration, body string) {
+		e := &storage.Event{URL: url, Title: title, Source: "manual", Timestamp: now.Add(-age)}
+		if body != "" {
+			require.NoError(t, store.AddEventWithContent(ctx, e, body))
+		} else {
+			require.NoError(t, store.AddEvent(ctx, e))
+		}
+	}
+	add("https://example.com/recent", "Too recent", 3*day, strings.Repeat("word ", 2000))
+	add("https://example.com/ancient", "Too old", 500*day, strings.Repeat("word ", 2000))
+	add("https://research.example.com/paper", "Long paper", 120*day, strings.Repeat("word ", 4000))
+	add("https://research.example.com/paper", "Long paper", 90*day, "")
+	add("https://example.com/glance", "Quick glance", 60*day, "")
+}
+
+func TestResurface_SamplesOlderPages(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local)
+	seedResurfaceEvents(t, store, now)
+
+	cmd := &ResurfaceCommand{Since: "1y", MinAge: "30d", Count: 5, Seed: 1, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var out []resurfaceJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+
+	titles := map[string]resurfaceJSON{}
+	for _, r := range out {
+		titles[r.Title] = r
+	}
+	assert.Len(t, out, 2, "one result per URL, within the window")
+	assert.Contains(t, titles, "Quick glance")
+	paper := titles["Long paper"]
+	assert.Equal(t, 2, paper.Visits)
+	assert.Equal(t, now.Add(-90*24*time.Hour).UTC().Format(time.RFC3339), paper.Timestamp, "the latest visit is shown")
+}
+
+func TestResurface_HumanOutput(t *testing.T) {
+	store := setupSearchStore(t)
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local)
+	seedResurfaceEvents(t, store, now)
+
+	cmd := &ResurfaceCommand{Since: "1y", MinAge: "100d", Count: 3, Seed: 7, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Contains(t, output, "From your history (1y to 100d ago):")
+	assert.Contains(t, output, "1. Long paper — research.example.com")
+	assert.Contains(t, output, "(120 days ago) · 17 min read")
+
+	cmd = &ResurfaceCommand{Since: "30d", MinAge: "60d", Count: 3, globals: &GlobalFlags{}}
+	assert.ErrorContains(t, cmd.executeWithStore(store, now), "nothing to resurface")
+}
+
+func TestWeightedSample_FavoursAttention(t *testing.T) {
+	pages := []storage.PastCapture{
+		{Event: storage.Event{ID: "glance"}, Visits: 1},
+		{Event: storage.Event{ID: "read", HasBody: true}, Bytes: 64 * 1024, Visits: 4},
+	}
+	rng := rand.New(rand.NewSource(42))
+	first := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		first[weightedSample(pages, 1, rng)[0].ID]++
+	}
+	assert.Greater(t, first["read"], 4*first["glance"])
+	assert.Positive(t, first["glance"], "light pages still come up sometimes")
+
+	assert.Len(t, weightedSample(pages, 5, rng), 2)
+}
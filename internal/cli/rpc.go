@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/runnerr0/chronicle/internal/rpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Execute implements the go-flags Commander interface for RPCCommand.
+// All protocol traffic uses stdout, so nothing else may be printed there.
+func (c *RPCCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := loadConfig(c.globals)
+	srv := rpc.NewServer(storage.WithStatsCache(store, statsCacheTTL), c.version)
+	srv.SetPipeline(newPipeline(c.globals, store, cfg.Hooks, cfg.Webhooks, cfg.Notifications))
+	return srv.Serve(ctx, os.Stdin, os.Stdout)
+}
@@ -8,21 +8,45 @@ import (
 	"strings"
 	"time"
 
+	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 )
 
 // Execute implements the go-flags Commander interface for SearchCommand.
 func (c *SearchCommand) Execute(args []string) error {
-	store, db, err := openDefaultStore()
+	store, db, err := openStore(c.globals)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
 	defer store.Close()
 
+	cfg := loadConfig(c.globals)
+	c.applyDefaults(cfg.Search)
+	c.history = cfg.Search.RecordHistory
+	c.synonyms = cfg.Search.Synonyms
+	c.embedCfg = cfg.Embeddings
+	c.style = newPalette(c.globals, cfg, os.Stdout)
 	return c.executeWithStore(store, args)
 }
 
+// applyDefaults fills flags the user did not give from the search config
+// section.
+func (c *SearchCommand) applyDefaults(d config.SearchConfig) {
+	if c.Limit == 0 {
+		c.Limit = d.DefaultLimit
+	}
+	if c.Since == "" {
+		c.Since = d.DefaultSince
+	}
+	if c.Sort == "" {
+		c.Sort = d.DefaultSort
+	}
+	if c.Output == "" {
+		c.Output = d.DefaultOutput
+	}
+}
+
 // executeWithStore runs the search against a provided store (for testing).
 func (c *SearchCommand) executeWithStore(store *storage.SQLiteStore, args []string) error {
 	query := c.Query
@@ -30,64 +54,278 @@ func (c *SearchCommand) executeWithStore(store *storage.SQLiteStore, args []stri
 		query = strings.Join(args, " ")
 	}
 
-	if c.Semantic {
-		fmt.Fprintln(os.Stderr, "Note: semantic search not yet implemented, falling back to keyword search.")
+	if c.BodyOnly {
+		if len(c.In) > 0 {
+			return fmt.Errorf("--body-only cannot be combined with --in")
+		}
+		c.In = []string{storage.FieldBody}
 	}
 
-	now := time.Now()
-	var since time.Time
-	if c.Since != "" {
-		dur, err := parseDuration(c.Since)
-		if err != nil {
-			return fmt.Errorf("invalid --since value %q: %w", c.Since, err)
-		}
-		since = now.Add(-dur)
+	if c.Semantic && c.Hybrid {
+		return fmt.Errorf("--semantic cannot be combined with --hybrid")
+	}
+	if c.Semantic && strings.TrimSpace(query) == "" {
+		return fmt.Errorf("--semantic needs a query")
+	}
+	if c.Hybrid && strings.TrimSpace(query) == "" {
+		return fmt.Errorf("--hybrid needs a query")
 	}
 
-	var until time.Time
-	if c.Until != "" {
-		dur, err := parseDuration(c.Until)
-		if err != nil {
-			return fmt.Errorf("invalid --until value %q: %w", c.Until, err)
+	now := time.Now()
+	if err := applyDayFilter(c.Today, c.On, &c.Since, &c.Until, now); err != nil {
+		return err
+	}
+	since, until, err := resolveTimeRange(c.Since, c.Until, now)
+	if err != nil {
+		return err
+	}
+	var minReadTime time.Duration
+	if c.MinReadTime != "" {
+		if minReadTime, err = parseDuration(c.MinReadTime); err != nil {
+			return fmt.Errorf("invalid --min-read-time: %w", err)
 		}
-		until = now.Add(-dur)
 	}
 
 	sq := storage.SearchQuery{
-		Query:        query,
-		Source:       c.Source,
-		Since:        since,
-		Until:        until,
-		Limit:        c.Limit,
-		Offset:       c.Offset,
-		HasBody:      c.HasBody,
-		HasEmbedding: c.HasEmbedding,
+		Query:       query,
+		Source:      c.Source,
+		Lang:        strings.ToLower(c.Lang),
+		Keyword:     storage.NormalizeKeyword(c.Keyword),
+		MinReadTime: minReadTime,
+		Since:       since,
+		Until:       until,
+		Limit:       c.Limit,
+		Offset:      c.Offset,
+		Domains:     domainValues(c.Domain),
+		Browsers:    c.Browser,
+		Sort:        c.Sort,
+		Fields:      c.In,
 	}
-	if len(c.Domain) > 0 {
-		sq.Domain = c.Domain[0]
+	if c.HasBody {
+		sq.HasBody = &c.HasBody
 	}
-	if len(c.Browser) > 0 {
-		sq.Browser = c.Browser[0]
+	if c.HasEmbedding {
+		sq.HasEmbedding = &c.HasEmbedding
+	}
+	if !c.NoSynonyms {
+		sq.Synonyms = c.synonyms
+	}
+	if c.UniqueURL {
+		// Collapsing shrinks the page, so fetch extra and page afterwards.
+		sq.Limit, sq.Offset = (c.Offset+c.Limit)*uniqueURLOverfetch, 0
 	}
 
 	ctx := context.Background()
-	results, err := store.SearchEvents(ctx, sq)
+	if err := checkFilters(ctx, store, c.Source, c.Browser); err != nil {
+		return err
+	}
+	if err := checkLang(ctx, store, sq.Lang); err != nil {
+		return err
+	}
+	if c.Explain {
+		return c.explain(ctx, store, sq)
+	}
+
+	var results []storage.Event
+	switch {
+	case c.Semantic:
+		results, err = c.semanticSearch(ctx, store, query, sq)
+	case c.Hybrid:
+		results, err = c.hybridSearch(ctx, store, query, sq)
+	default:
+		results, err = store.SearchEvents(ctx, sq)
+	}
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
+	if c.UniqueURL {
+		results, c.visits = collapseURLs(results)
+		results = page(results, c.Offset, c.Limit)
+	} else if !c.Semantic && !c.Hybrid {
+		if c.total, err = store.CountEvents(ctx, sq); err != nil {
+			return fmt.Errorf("count results: %w", err)
+		}
+	}
 
-	if c.globals != nil && c.globals.JSON {
+	if c.history {
+		if query != "" && len(sq.Domains) == 0 && (c.Sort == "" || c.Sort == storage.SortRelevance) {
+			results = boostHintedDomains(ctx, store, results)
+		}
+		rec := &storage.SearchRecord{
+			Query:   query,
+			Source:  sq.Source,
+			Since:   c.Since,
+			Results: len(results),
+		}
+		// History keeps one domain and browser per search.
+		if len(sq.Domains) == 1 {
+			rec.Domain = sq.Domains[0]
+		}
+		if len(sq.Browsers) == 1 {
+			rec.Browser = sq.Browsers[0]
+		}
+		if err := store.RecordSearch(ctx, rec); err != nil {
+			noticef(c.globals, "Warning: %v\n", err)
+		}
+	}
+
+	ids := make([]string, len(results))
+	for i, e := range results {
+		ids[i] = e.ID
+	}
+	if c.summaries, err = store.GetSummaries(ctx, ids); err != nil {
+		return err
+	}
+
+	if len(results) == 0 && query != "" {
+		if c.suggestion, err = store.SuggestQuery(ctx, query); err != nil {
+			noticef(c.globals, "Warning: %v\n", err)
+		}
+	}
+
+	switch {
+	case c.Output == "alfred":
+		return printAlfred(query, results)
+	case c.Output == "raycast":
+		return printRaycast(results)
+	case c.globals != nil && c.globals.JSON, c.Output == "json":
 		return c.printJSON(query, results)
+	case c.Output == "urls":
+		for _, e := range results {
+			fmt.Println(e.PageURL())
+		}
+		return nil
 	}
 	return c.printHuman(query, results)
 }
 
+// semanticSearch embeds query and returns the events matching sq's
+// filters whose embeddings are nearest to it. Only events embedded by the
+// same model are found (see chronicle embed).
+func (c *SearchCommand) semanticSearch(ctx context.Context, store *storage.SQLiteStore, query string, sq storage.SearchQuery) ([]storage.Event, error) {
+	model, vector, err := embedQuery(ctx, c.embedder, c.embedCfg, query)
+	if err != nil {
+		return nil, err
+	}
+	return store.NearestEvents(ctx, sq, model, vector)
+}
+
+// hybridSearch ranks the events matching sq by keyword and by embedding
+// together (see storage.HybridSearch), keeping each result's score for
+// --json.
+func (c *SearchCommand) hybridSearch(ctx context.Context, store *storage.SQLiteStore, query string, sq storage.SearchQuery) ([]storage.Event, error) {
+	model, vector, err := embedQuery(ctx, c.embedder, c.embedCfg, query)
+	if err != nil {
+		return nil, err
+	}
+	found, err := store.HybridSearch(ctx, sq, model, vector)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]storage.Event, len(found))
+	c.scores = make(map[string]storage.HybridScore, len(found))
+	for i, r := range found {
+		results[i] = r.Event
+		c.scores[r.Event.ID] = r.Score
+	}
+	return results, nil
+}
+
+// uniqueURLOverfetch is how many results --unique-url fetches per result
+// shown, so pages stay full after repeat visits collapse.
+const uniqueURLOverfetch = 5
+
+// urlVisits summarizes the visits --unique-url collapsed into one result.
+type urlVisits struct {
+	Count    int
+	LastSeen time.Time
+}
+
+// collapseURLs keeps the first result for each normalized page URL (see
+// normalizeDupURL and Event.PageURL), in order, and counts the visits
+// folded into it, including those deduplicated at capture.
+func collapseURLs(results []storage.Event) ([]storage.Event, map[string]urlVisits) {
+	first := map[string]string{} // normalized URL -> kept event ID
+	visits := map[string]urlVisits{}
+	var kept []storage.Event
+	for _, e := range results {
+		key := normalizeDupURL(e.PageURL())
+		id, ok := first[key]
+		if !ok {
+			id = e.ID
+			first[key] = id
+			kept = append(kept, e)
+		}
+		v := visits[id]
+		v.Count += max(e.Visits, 1)
+		if e.Timestamp.After(v.LastSeen) {
+			v.LastSeen = e.Timestamp
+		}
+		visits[id] = v
+	}
+	if kept == nil {
+		kept = []storage.Event{}
+	}
+	return kept, visits
+}
+
+// page returns results[offset:offset+limit], clamped to the slice.
+func page(results []storage.Event, offset, limit int) []storage.Event {
+	if offset >= len(results) {
+		return results[:0]
+	}
+	results = results[offset:]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// searchHintDomains is how many of the most searched-within domains get a
+// ranking boost.
+const searchHintDomains = 5
+
+// boostHintedDomains moves results from domains the user often narrows
+// searches to ahead of the rest, keeping relevance order within each group.
+func boostHintedDomains(ctx context.Context, store *storage.SQLiteStore, results []storage.Event) []storage.Event {
+	hints, err := store.SearchDomainHints(ctx, searchHintDomains)
+	if err != nil || len(hints) == 0 {
+		return results
+	}
+	hinted := make(map[string]bool, len(hints))
+	for _, h := range hints {
+		hinted[h.Domain] = true
+	}
+
+	boosted := make([]storage.Event, 0, len(results))
+	var rest []storage.Event
+	for _, e := range results {
+		if hinted[e.Domain] {
+			boosted = append(boosted, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	return append(boosted, rest...)
+}
+
+// window describes the searched time range for the summary line.
+func (c *SearchCommand) window() string {
+	if c.Until == c.Since && isDateSpec(c.Since) {
+		return "on " + c.Since
+	}
+	return "since " + c.Since
+}
+
 func (c *SearchCommand) printHuman(query string, results []storage.Event) error {
 	if len(results) == 0 {
 		if query != "" {
-			fmt.Printf("No results found for %q (since %s)\n", query, c.Since)
+			infof(c.globals, "No results found for %q (%s)\n", query, c.window())
+			if c.suggestion != "" {
+				infof(c.globals, "Did you mean: %s?\n", c.suggestion)
+			}
 		} else {
-			fmt.Printf("No results found (since %s)\n", c.Since)
+			infof(c.globals, "No results found (%s)\n", c.window())
 		}
 		return nil
 	}
@@ -96,20 +334,25 @@ func (c *SearchCommand) printHuman(query string, results []storage.Event) error
 	if len(results) == 1 {
 		resultWord = "result"
 	}
+	found := fmt.Sprintf("%d %s", len(results), resultWord)
+	if c.total > int64(len(results)) {
+		found = fmt.Sprintf("%d of %d results", len(results), c.total)
+	}
 	if query != "" {
-		fmt.Printf("Found %d %s for %q (since %s)\n\n", len(results), resultWord, query, c.Since)
+		infof(c.globals, "Found %s for %q (%s)\n\n", found, query, c.window())
 	} else {
-		fmt.Printf("Found %d %s (since %s)\n\n", len(results), resultWord, c.Since)
+		infof(c.globals, "Found %s (%s)\n\n", found, c.window())
 	}
 
+	terms := highlightTerms(query)
 	for i, e := range results {
-		fmt.Printf("%d. %s", i+1+c.Offset, e.Title)
+		fmt.Printf("%d. %s", i+1+c.Offset, c.style.title(e.Title, terms))
 		if e.Domain != "" {
-			fmt.Printf(" \u2014 %s", e.Domain)
+			fmt.Printf(" \u2014 %s", c.style.domain(e.Domain))
 		}
 		fmt.Println()
 
-		fmt.Printf("   %s\n", e.URL)
+		fmt.Printf("   %s\n", e.PageURL())
 
 		ts := e.Timestamp.Local().Format("2006-01-02 15:04")
 		meta := ts
@@ -119,7 +362,18 @@ func (c *SearchCommand) printHuman(query string, results []storage.Event) error
 		if e.Browser != "" {
 			meta += " \u00b7 " + e.Browser
 		}
+		if e.ReadTime > 0 {
+			meta += " \u00b7 " + formatReadTime(e.ReadTime)
+		}
+		if v := c.visits[e.ID]; v.Count > 1 {
+			meta += fmt.Sprintf(" \u00b7 %d visits, last %s", v.Count, v.LastSeen.Local().Format("2006-01-02 15:04"))
+		} else if e.Visits > 1 {
+			meta += fmt.Sprintf(" \u00b7 %d visits", e.Visits)
+		}
 		fmt.Printf("   %s\n", meta)
+		if s := c.summaries[e.ID]; s != "" {
+			fmt.Printf("   %s\n", strings.Join(strings.Fields(s), " "))
+		}
 
 		if i < len(results)-1 {
 			fmt.Println()
@@ -130,26 +384,46 @@ func (c *SearchCommand) printHuman(query string, results []storage.Event) error
 }
 
 type jsonResult struct {
-	ID        string `json:"id"`
-	URL       string `json:"url"`
-	Title     string `json:"title"`
-	Domain    string `json:"domain"`
-	Timestamp string `json:"timestamp"`
-	Source    string `json:"source"`
-	Browser  string `json:"browser,omitempty"`
+	ID        string     `json:"id"`
+	URL       string     `json:"url"`
+	Title     string     `json:"title"`
+	Domain    string     `json:"domain"`
+	Timestamp string     `json:"timestamp"`
+	Source    string     `json:"source"`
+	Browser   string     `json:"browser,omitempty"`
+	Lang      string     `json:"lang,omitempty"`
+	Canonical string     `json:"canonical_url,omitempty"`
+	Summary   string     `json:"summary,omitempty"`
+	ReadSecs  int64      `json:"read_seconds,omitempty"`
+	Visits    int        `json:"visits,omitempty"`
+	LastSeen  string     `json:"last_seen,omitempty"`
+	Score     *jsonScore `json:"score,omitempty"`
+}
+
+// jsonScore breaks down a --hybrid result's rank (see storage.HybridScore).
+type jsonScore struct {
+	Total        float64  `json:"total"`
+	KeywordRank  int      `json:"keyword_rank,omitempty"`
+	BM25         *float64 `json:"bm25,omitempty"`
+	SemanticRank int      `json:"semantic_rank,omitempty"`
+	Similarity   *float64 `json:"similarity,omitempty"`
 }
 
 type jsonSearchOutput struct {
-	Count   int          `json:"count"`
-	Query   string       `json:"query"`
-	Results []jsonResult `json:"results"`
+	Count      int          `json:"count"`
+	Total      int64        `json:"total,omitempty"`
+	Query      string       `json:"query"`
+	Suggestion string       `json:"suggestion,omitempty"`
+	Results    []jsonResult `json:"results"`
 }
 
 func (c *SearchCommand) printJSON(query string, results []storage.Event) error {
 	out := jsonSearchOutput{
-		Count:   len(results),
-		Query:   query,
-		Results: make([]jsonResult, len(results)),
+		Count:      len(results),
+		Total:      c.total,
+		Query:      query,
+		Suggestion: c.suggestion,
+		Results:    make([]jsonResult, len(results)),
 	}
 
 	for i, e := range results {
@@ -160,7 +434,26 @@ func (c *SearchCommand) printJSON(query string, results []storage.Event) error {
 			Domain:    e.Domain,
 			Timestamp: e.Timestamp.UTC().Format(time.RFC3339),
 			Source:    e.Source,
-			Browser:  e.Browser,
+			Browser:   e.Browser,
+			Lang:      e.Lang,
+			Canonical: e.CanonicalURL,
+			Summary:   c.summaries[e.ID],
+			ReadSecs:  int64(e.ReadTime / time.Second),
+			Visits:    e.Visits,
+		}
+		if v, ok := c.visits[e.ID]; ok {
+			out.Results[i].Visits = v.Count
+			out.Results[i].LastSeen = v.LastSeen.UTC().Format(time.RFC3339)
+		}
+		if sc, ok := c.scores[e.ID]; ok {
+			score := &jsonScore{Total: sc.Score, KeywordRank: sc.KeywordRank, SemanticRank: sc.SemanticRank}
+			if sc.KeywordRank > 0 {
+				score.BM25 = &sc.BM25
+			}
+			if sc.SemanticRank > 0 {
+				score.Similarity = &sc.Similarity
+			}
+			out.Results[i].Score = score
 		}
 	}
 
@@ -168,3 +461,35 @@ func (c *SearchCommand) printJSON(query string, results []storage.Event) error {
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
+
+// explainJSON is the JSON output structure for search --explain.
+type explainJSON struct {
+	SQL  string        `json:"sql"`
+	Args []interface{} `json:"args"`
+	Plan []string      `json:"plan"`
+}
+
+// explain prints the SQL, bound parameters, and query plan for sq.
+func (c *SearchCommand) explain(ctx context.Context, store *storage.SQLiteStore, sq storage.SearchQuery) error {
+	plan, err := store.ExplainSearch(ctx, sq)
+	if err != nil {
+		return fmt.Errorf("explain failed: %w", err)
+	}
+	query := strings.Join(strings.Fields(plan.SQL), " ")
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explainJSON{SQL: query, Args: plan.Args, Plan: plan.Plan})
+	}
+
+	fmt.Printf("SQL:\n  %s\n\nParameters:\n", query)
+	for i, arg := range plan.Args {
+		fmt.Printf("  %d: %#v\n", i+1, arg)
+	}
+	fmt.Println("\nQuery plan:")
+	for _, line := range plan.Plan {
+		fmt.Printf("  %s\n", line)
+	}
+	return nil
+}
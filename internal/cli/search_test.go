@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	goflags "github.com/jessevdk/go-flags"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -153,7 +157,7 @@ func TestSearch_DomainFilter(t *testing.T) {
 
 	cmd := &SearchCommand{
 		Since:   "30d",
-		Domain:  []string{"github.com"},
+		Domain:  []Domain{"github.com"},
 		Limit:   10,
 		globals: &GlobalFlags{},
 	}
@@ -233,34 +237,123 @@ func TestSearch_Pagination(t *testing.T) {
 		}
 	}
 	assert.Equal(t, 2, resultCount, "should show exactly 2 results with limit=2")
+	assert.Contains(t, output, "Found 2 of 5 results")
 }
 
-func TestSearch_SemanticFallback(t *testing.T) {
+func TestSearch_JSONTotal(t *testing.T) {
 	store := setupSearchStore(t)
 	seedSearchEvents(t, store)
 
-	oldStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
+	cmd := &SearchCommand{Since: "30d", Limit: 1, Offset: 1, globals: &GlobalFlags{JSON: true}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB"}))
+	})
 
-	cmd := &SearchCommand{
-		Since:    "30d",
-		Limit:    10,
-		Semantic: true,
-		globals:  &GlobalFlags{},
-	}
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, 1, out.Count)
+	assert.Equal(t, int64(2), out.Total)
+}
 
-	_ = captureSearchOutput(t, func() {
-		err := cmd.executeWithStore(store, []string{"LanceDB"})
-		require.NoError(t, err)
+func TestSearch_Semantic(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	embedder := &topicEmbedder{}
+	embed := &EmbedCommand{globals: &GlobalFlags{Quiet: true}, embedder: embedder}
+	require.NoError(t, embed.executeWithStore(store, config.DefaultConfig().Embeddings))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 2, Semantic: true, Output: "urls", globals: &GlobalFlags{}, embedder: embedder}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"vector databases"}))
+	})
+	assert.ElementsMatch(t, []string{
+		"https://lancedb.github.io/lancedb/basic/",
+		"https://blog.example.com/chromadb-vs-lancedb",
+	}, strings.Fields(output), "no keyword in common, but the same topic")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Semantic: true, Source: "import", Output: "urls", globals: &GlobalFlags{}, embedder: embedder}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"programming"}))
 	})
+	assert.Equal(t, "https://docs.python.org/3/\n", output, "filters still apply")
 
-	w.Close()
-	os.Stderr = oldStderr
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Semantic: true, globals: &GlobalFlags{}, embedder: embedder}
+	assert.EqualError(t, cmd.executeWithStore(store, nil), "--semantic needs a query")
 
-	var buf bytes.Buffer
-	buf.ReadFrom(r)
-	assert.Contains(t, buf.String(), "semantic search not yet implemented")
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Semantic: true, globals: &GlobalFlags{}}
+	assert.ErrorContains(t, cmd.executeWithStore(store, []string{"go"}), "embeddings are disabled")
+}
+
+func TestSearch_SemanticFlatVectorStore(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	cfg := config.DefaultConfig()
+	cfg.Storage.VectorStore = "flat"
+	cfg.Storage.VectorDir = t.TempDir()
+	require.NoError(t, setupVectorStore(store, cfg, t.TempDir()))
+
+	embedder := &topicEmbedder{}
+	embed := &EmbedCommand{globals: &GlobalFlags{Quiet: true}, embedder: embedder}
+	require.NoError(t, embed.executeWithStore(store, cfg.Embeddings))
+	assert.FileExists(t, filepath.Join(cfg.Storage.VectorDir, "vectors.dat"))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 2, Semantic: true, Output: "urls", globals: &GlobalFlags{}, embedder: embedder}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"vector databases"}))
+	})
+	assert.ElementsMatch(t, []string{
+		"https://lancedb.github.io/lancedb/basic/",
+		"https://blog.example.com/chromadb-vs-lancedb",
+	}, strings.Fields(output))
+
+	// The stock config works once embeddings are turned on.
+	stock := config.DefaultConfig()
+	stock.Embeddings.Enabled = true
+	assert.NoError(t, setupVectorStore(store, stock, t.TempDir()))
+
+	cfg.Storage.VectorStore = "lancedb"
+	cfg.Embeddings.Enabled = false
+	assert.NoError(t, setupVectorStore(store, cfg, t.TempDir()))
+	cfg.Embeddings.Enabled = true
+	assert.EqualError(t, setupVectorStore(store, cfg, t.TempDir()), "storage.vector_store lancedb is not supported yet; set it to sqlite or flat")
+
+	cfg.Storage.VectorStore = "chroma"
+	assert.EqualError(t, setupVectorStore(store, cfg, t.TempDir()), `invalid storage.vector_store "chroma" (want sqlite or flat)`)
+}
+
+func TestSearch_Hybrid(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	embedder := &topicEmbedder{}
+	embed := &EmbedCommand{globals: &GlobalFlags{Quiet: true}, embedder: embedder}
+	require.NoError(t, embed.executeWithStore(store, config.DefaultConfig().Embeddings))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 3, Hybrid: true, globals: &GlobalFlags{JSON: true}, embedder: embedder}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"lancedb"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 3)
+	for _, r := range out.Results[:2] {
+		assert.Contains(t, r.Title, "LanceDB")
+		require.NotNil(t, r.Score)
+		assert.NotZero(t, r.Score.KeywordRank)
+		assert.NotNil(t, r.Score.BM25)
+		assert.NotZero(t, r.Score.SemanticRank)
+		assert.Greater(t, r.Score.Total, 0.0)
+	}
+	// The rest rank by embedding alone.
+	third := out.Results[2].Score
+	require.NotNil(t, third)
+	assert.Zero(t, third.KeywordRank)
+	assert.Nil(t, third.BM25)
+	assert.Less(t, third.Total, out.Results[1].Score.Total)
+
+	cmd = &SearchCommand{Since: "30d", Limit: 3, Hybrid: true, Semantic: true, globals: &GlobalFlags{}, embedder: embedder}
+	assert.EqualError(t, cmd.executeWithStore(store, []string{"lancedb"}), "--semantic cannot be combined with --hybrid")
+	cmd = &SearchCommand{Since: "30d", Limit: 3, Hybrid: true, globals: &GlobalFlags{}, embedder: embedder}
+	assert.EqualError(t, cmd.executeWithStore(store, nil), "--hybrid needs a query")
 }
 
 func TestSearch_BrowserFilter(t *testing.T) {
@@ -283,3 +376,402 @@ func TestSearch_BrowserFilter(t *testing.T) {
 	assert.NotContains(t, output, "firefox")
 	assert.NotContains(t, output, "safari")
 }
+
+func TestSearch_RepeatedDomainAndBrowser(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{
+		Since:   "30d",
+		Domain:  []Domain{"github.com", "docs.python.org", "news.ycombinator.com"},
+		Browser: []string{"firefox", "safari"},
+		Limit:   10,
+		globals: &GlobalFlags{},
+	}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+	assert.Contains(t, output, "Found 2 results")
+	assert.Contains(t, output, "Go Programming Language")
+	assert.Contains(t, output, "Python 3 Docs")
+	assert.NotContains(t, output, "Hacker News")
+}
+
+// --- Config-driven defaults ---
+
+func TestSearch_ApplyDefaults(t *testing.T) {
+	cmd := &SearchCommand{Limit: 5}
+	cmd.applyDefaults(config.SearchConfig{DefaultLimit: 25, DefaultSince: "90d", DefaultOutput: "urls", DefaultSort: "newest"})
+
+	assert.Equal(t, 5, cmd.Limit, "explicit flags win")
+	assert.Equal(t, "90d", cmd.Since)
+	assert.Equal(t, "urls", cmd.Output)
+	assert.Equal(t, "newest", cmd.Sort)
+}
+
+func TestSearch_URLsOutputSortedOldest(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Sort: "oldest", Output: "urls", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB"}))
+	})
+
+	assert.Equal(t, "https://blog.example.com/chromadb-vs-lancedb\nhttps://lancedb.github.io/lancedb/basic/\n", output)
+}
+
+func TestSearchExplain(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Query: "lancedb", Since: "7d", Limit: 5, Domain: []Domain{"lancedb.github.io"}, Explain: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+
+	assert.Contains(t, output, "SQL:\n  SELECT e.id")
+	assert.Contains(t, output, "events_fts MATCH ?")
+	assert.Contains(t, output, `1: "\"lancedb\"*"`)
+	assert.Contains(t, output, `2: "lancedb.github.io"`)
+	assert.Contains(t, output, "Query plan:\n")
+	assert.NotContains(t, output, "LanceDB Getting Started", "explain should not run the search")
+}
+
+func TestSearchExplainJSON(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &SearchCommand{Source: "manual", Since: "7d", Limit: 5, Explain: true, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, nil))
+	})
+
+	var out explainJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Contains(t, out.SQL, "source = ?")
+	assert.Equal(t, "manual", out.Args[0])
+	require.NotEmpty(t, out.Plan)
+	assert.Contains(t, strings.Join(out.Plan, "\n"), "idx_events_source_ts")
+}
+
+func TestSearchExplainFlagHidden(t *testing.T) {
+	p, _, _ := buildParser("test")
+	opt := p.Find("search").FindOptionByLongName("explain")
+	require.NotNil(t, opt)
+	assert.True(t, opt.Hidden)
+}
+
+func TestSearch_AlfredOutput(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Output: "alfred", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"golang"}))
+	})
+
+	var out struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Items, 1)
+	item := out.Items[0]
+	assert.Equal(t, "Go Programming Language", item["title"])
+	assert.Equal(t, "https://github.com/golang/go", item["arg"])
+	assert.Contains(t, item["subtitle"], "github.com · ")
+	assert.NotEmpty(t, item["uid"])
+	assert.Equal(t, map[string]interface{}{"path": "icon.png"}, item["icon"])
+}
+
+func TestSearch_AlfredNoResults(t *testing.T) {
+	store := setupSearchStore(t)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Output: "alfred", globals: &GlobalFlags{JSON: true}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"nothing"}))
+	})
+
+	var out struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Items, 1)
+	assert.Equal(t, "No results", out.Items[0]["title"])
+	assert.Equal(t, false, out.Items[0]["valid"])
+	assert.NotContains(t, out.Items[0], "arg")
+}
+
+func TestSearch_RaycastOutput(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Output: "raycast", Sort: "oldest", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"LanceDB"}))
+	})
+
+	var out struct {
+		Items []raycastItem `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Items, 2)
+	assert.Equal(t, "ChromaDB vs LanceDB", out.Items[0].Title)
+	assert.Equal(t, "blog.example.com", out.Items[0].Subtitle)
+	assert.Equal(t, "https://blog.example.com/chromadb-vs-lancedb", out.Items[0].Arg)
+	assert.Equal(t, raycastIcon, out.Items[0].Icon)
+	require.Len(t, out.Items[0].Accessories, 1)
+}
+
+func TestSearch_OnDay(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	midnight := startOfDay(time.Now())
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://late.example.com/", Title: "Late night reading", Source: "manual", Timestamp: midnight.Add(-30 * time.Minute)}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://early.example.com/", Title: "Early morning reading", Source: "manual", Timestamp: midnight.Add(30 * time.Minute)}))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, On: "yesterday", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"reading"}))
+	})
+	assert.Contains(t, output, "Found 1 result for \"reading\" (on "+midnight.AddDate(0, 0, -1).Format("2006-01-02")+")")
+	assert.Contains(t, output, "Late night reading")
+	assert.NotContains(t, output, "Early morning reading")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Today: true, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"reading"}))
+	})
+	assert.Contains(t, output, "Early morning reading")
+	assert.NotContains(t, output, "Late night reading")
+}
+
+func TestSearch_InField(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, In: []string{"url"}, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"lancedb"}))
+	})
+	assert.Contains(t, output, "Found 2 results")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, In: []string{"title"}, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"github"}))
+	})
+	assert.Contains(t, output, "No results found")
+}
+
+func TestSearch_BodyOnly(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com/vacuum", Title: "SQLite vacuum", Source: "manual"}))
+	require.NoError(t, store.AddEventWithContent(ctx,
+		&storage.Event{URL: "https://example.com/notes", Title: "Maintenance notes", Source: "manual"},
+		"Run vacuum after a large prune."))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"vacuum"}))
+	})
+	assert.Contains(t, output, "Found 2 results")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, BodyOnly: true, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"vacuum"}))
+	})
+	assert.Contains(t, output, "Found 1 result")
+	assert.Contains(t, output, "Maintenance notes")
+	assert.NotContains(t, output, "SQLite vacuum")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, BodyOnly: true, In: []string{"title"}, globals: &GlobalFlags{}}
+	assert.EqualError(t, cmd.executeWithStore(store, []string{"vacuum"}), "--body-only cannot be combined with --in")
+}
+
+func TestSearch_InFlagChoices(t *testing.T) {
+	parser, _, cmds := buildParser("test")
+	parser.CommandHandler = func(goflags.Commander, []string) error { return nil }
+
+	_, err := parser.ParseArgs([]string{"search", "--in", "url", "--in", "body", "rfc"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"url", "body"}, cmds.Search.In)
+
+	_, err = parser.ParseArgs([]string{"search", "--in", "summary", "rfc"})
+	assert.ErrorContains(t, err, "summary")
+}
+
+func TestSearch_DidYouMean(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"pyhton"}))
+	})
+	assert.Contains(t, output, `No results found for "pyhton"`)
+	assert.Contains(t, output, "Did you mean: python?")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Output: "json", globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"lancdb"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, "lancedb", out.Suggestion)
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"qwxyz"}))
+	})
+	assert.NotContains(t, output, "Did you mean")
+}
+
+func TestSearch_Synonyms(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	groups := [][]string{{"snake", "python"}}
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, synonyms: groups, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"snake"}))
+	})
+	assert.Contains(t, output, "Python 3 Docs")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, NoSynonyms: true, synonyms: groups, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"snake"}))
+	})
+	assert.NotContains(t, output, "Python 3 Docs")
+}
+
+func TestSearch_UniqueURL(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	for i, u := range []string{
+		"https://status.example.com/dashboard",
+		"https://status.example.com/dashboard/?utm_source=mail",
+		"https://www.status.example.com/dashboard#top",
+		"https://status.example.com/incidents",
+	} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: u, Title: "Status dashboard", Source: "extension", Timestamp: now.Add(-time.Duration(4-i) * time.Hour)}))
+	}
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Sort: storage.SortNewest, UniqueURL: true, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"status"}))
+	})
+	assert.Contains(t, output, "Found 2 results")
+	assert.Contains(t, output, "3 visits, last "+now.Add(-2*time.Hour).Local().Format("2006-01-02 15:04"))
+	assert.Contains(t, output, "https://status.example.com/incidents")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 1, Offset: 1, Sort: storage.SortNewest, UniqueURL: true, Output: "json", globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"status"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 1)
+	assert.Equal(t, "https://www.status.example.com/dashboard#top", out.Results[0].URL)
+	assert.Equal(t, 3, out.Results[0].Visits)
+
+	// Without the flag every visit is listed.
+	cmd = &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"status"}))
+	})
+	assert.Contains(t, output, "Found 4 results")
+	assert.NotContains(t, output, "visits")
+}
+
+func TestSearch_ShowsDedupedVisits(t *testing.T) {
+	store := setupSearchStore(t)
+	store.SetDedupeWindow(5 * time.Minute)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://tide.example.com/tables", Title: "Tide tables", Source: "extension", Timestamp: now.Add(time.Duration(i) * time.Minute)}))
+	}
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"tide"}))
+	})
+	assert.Contains(t, output, "Found 1 result")
+	assert.Contains(t, output, "\u00b7 3 visits\n")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Output: "json", globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"tide"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 1)
+	assert.Equal(t, 3, out.Results[0].Visits)
+}
+
+func TestSearch_UniqueURLUsesCanonical(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	for i, u := range []string{"https://news.example.com/a/123?src=rss", "https://amp.news-cdn.example/a/123"} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: u, CanonicalURL: "https://news.example.com/a/123", Title: "Harbor reopens", Source: "extension", Timestamp: now.Add(-time.Duration(2-i) * time.Hour)}))
+	}
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, Sort: storage.SortNewest, UniqueURL: true, Output: "json", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"harbor"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 1)
+	assert.Equal(t, "https://amp.news-cdn.example/a/123", out.Results[0].URL)
+	assert.Equal(t, "https://news.example.com/a/123", out.Results[0].Canonical)
+	assert.Equal(t, 2, out.Results[0].Visits)
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"harbor"}))
+	})
+	assert.Contains(t, output, "https://news.example.com/a/123\n")
+	assert.NotContains(t, output, "https://amp.news-cdn.example", "results link to the canonical URL")
+}
+
+func TestSearch_MinReadTime(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	long := &storage.Event{URL: "https://blog.example/deep-dive", Title: "SQLite deep dive", Source: "extension"}
+	require.NoError(t, store.AddEventWithContent(ctx, long, strings.Repeat("page cache ", 1200)))
+	require.NoError(t, store.AddEventWithContent(ctx, &storage.Event{URL: "https://blog.example/tip", Title: "SQLite tip", Source: "extension"}, "Use WAL."))
+
+	cmd := &SearchCommand{Since: "30d", Limit: 10, MinReadTime: "5m", globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"sqlite"}))
+	})
+	assert.Contains(t, output, "Found 1 result")
+	assert.Contains(t, output, " · 10 min read\n")
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, Output: "json", globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, []string{"sqlite"}))
+	})
+	var out jsonSearchOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	require.Len(t, out.Results, 2)
+	for _, r := range out.Results {
+		if r.ID == long.ID {
+			assert.Equal(t, int64(606), r.ReadSecs, "2400 words at 238 a minute")
+		} else {
+			assert.Equal(t, int64(1), r.ReadSecs)
+		}
+	}
+
+	cmd = &SearchCommand{Since: "30d", Limit: 10, MinReadTime: "five", globals: &GlobalFlags{}}
+	assert.ErrorContains(t, cmd.executeWithStore(store, nil), "invalid --min-read-time")
+}
+
+func TestFormatReadTime(t *testing.T) {
+	assert.Equal(t, "<1 min read", formatReadTime(20*time.Second))
+	assert.Equal(t, "1 min read", formatReadTime(89*time.Second))
+	assert.Equal(t, "15 min read", formatReadTime(14*time.Minute+31*time.Second))
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// searchRecordJSON is the JSON output structure for a recorded search.
+type searchRecordJSON struct {
+	At      string `json:"at"`
+	Query   string `json:"query"`
+	Domain  string `json:"domain,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Browser string `json:"browser,omitempty"`
+	Since   string `json:"since,omitempty"`
+	Results int    `json:"results"`
+}
+
+// queryCountJSON is the JSON output structure for searches --top.
+type queryCountJSON struct {
+	Query       string `json:"query"`
+	Count       int64  `json:"count"`
+	LastAt      string `json:"last_at"`
+	ZeroResults int64  `json:"zero_results"`
+}
+
+// Execute implements the go-flags Commander interface for SearchesCommand.
+func (c *SearchesCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, loadConfig(c.globals))
+}
+
+// executeWithStore runs searches against a provided store and config (used
+// by tests).
+func (c *SearchesCommand) executeWithStore(store *storage.SQLiteStore, cfg *config.Config) error {
+	ctx := context.Background()
+	jsonOut := c.globals != nil && c.globals.JSON
+
+	if c.Clear {
+		n, err := store.ClearSearches(ctx)
+		if err != nil {
+			return err
+		}
+		if jsonOut {
+			return printSearchesJSON(map[string]int64{"cleared": n})
+		}
+		word := "searches"
+		if n == 1 {
+			word = "search"
+		}
+		infof(c.globals, "Cleared %s recorded %s\n", formatNumber(n), word)
+		return nil
+	}
+
+	if c.Limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	if !cfg.Search.RecordHistory {
+		noticef(c.globals, "Search history is off. Enable it with: chronicle config set search.record_history true\n")
+	}
+
+	if c.Top {
+		top, err := store.TopSearches(ctx, c.Limit)
+		if err != nil {
+			return err
+		}
+		if jsonOut {
+			out := make([]queryCountJSON, len(top))
+			for i, q := range top {
+				out[i] = queryCountJSON{Query: q.Query, Count: q.Count, LastAt: q.LastAt.UTC().Format(time.RFC3339), ZeroResults: q.ZeroResults}
+			}
+			return printSearchesJSON(out)
+		}
+		for _, q := range top {
+			line := fmt.Sprintf("%5s  %s  (last %s", formatNumber(q.Count), q.Query, q.LastAt.Local().Format("2006-01-02"))
+			if q.ZeroResults > 0 {
+				line += fmt.Sprintf(", %d with no results", q.ZeroResults)
+			}
+			fmt.Println(line + ")")
+		}
+		return nil
+	}
+
+	recs, err := store.ListSearches(ctx, c.Limit)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		out := make([]searchRecordJSON, len(recs))
+		for i, r := range recs {
+			out[i] = searchRecordJSON{
+				At:      r.At.UTC().Format(time.RFC3339),
+				Query:   r.Query,
+				Domain:  r.Domain,
+				Source:  r.Source,
+				Browser: r.Browser,
+				Since:   r.Since,
+				Results: r.Results,
+			}
+		}
+		return printSearchesJSON(out)
+	}
+	for _, r := range recs {
+		word := "results"
+		if r.Results == 1 {
+			word = "result"
+		}
+		fmt.Printf("%s  %s  %d %s\n", r.At.Local().Format("2006-01-02 15:04"), describeSearch(r), r.Results, word)
+	}
+	return nil
+}
+
+func printSearchesJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// describeSearch renders a recorded search as its query followed by any
+// filters, e.g. `"sqlite wal" domain:sqlite.org since:7d`.
+func describeSearch(r storage.SearchRecord) string {
+	parts := []string{fmt.Sprintf("%q", r.Query)}
+	if r.Query == "" {
+		parts[0] = "(no query)"
+	}
+	for _, f := range []struct{ name, value string }{
+		{"domain", r.Domain}, {"source", r.Source}, {"browser", r.Browser}, {"since", r.Since},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.name+":"+f.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
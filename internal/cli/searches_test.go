@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestSearch_RecordsHistoryOnlyWhenEnabled(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	ctx := context.Background()
+
+	off := &SearchCommand{Query: "lancedb", Since: "30d", Limit: 10, globals: &GlobalFlags{Quiet: true}}
+	captureSearchOutput(t, func() { require.NoError(t, off.executeWithStore(store, nil)) })
+	recs, err := store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, recs, "history is off by default")
+
+	on := &SearchCommand{Query: "lancedb", Since: "30d", Limit: 10, Source: "manual", history: true, globals: &GlobalFlags{Quiet: true}}
+	captureSearchOutput(t, func() { require.NoError(t, on.executeWithStore(store, nil)) })
+	recs, err = store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, "lancedb", recs[0].Query)
+	assert.Equal(t, "manual", recs[0].Source)
+	assert.Equal(t, "30d", recs[0].Since)
+	assert.Equal(t, 1, recs[0].Results)
+}
+
+func TestSearch_BoostsHintedDomains(t *testing.T) {
+	store := setupSearchStore(t)
+	seedSearchEvents(t, store)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, store.RecordSearch(ctx, &storage.SearchRecord{Query: "vectors", Domain: "blog.example.com"}))
+	}
+
+	cmd := &SearchCommand{Query: "lancedb", Since: "30d", Limit: 10, Output: "urls", history: true, globals: &GlobalFlags{}}
+	output := captureSearchOutput(t, func() { require.NoError(t, cmd.executeWithStore(store, nil)) })
+	assert.Equal(t, "https://blog.example.com/chromadb-vs-lancedb\nhttps://lancedb.github.io/lancedb/basic/\n", output)
+
+	cmd = &SearchCommand{Query: "lancedb", Since: "30d", Limit: 10, Output: "urls", Sort: storage.SortNewest, history: true, globals: &GlobalFlags{}}
+	output = captureSearchOutput(t, func() { require.NoError(t, cmd.executeWithStore(store, nil)) })
+	assert.Equal(t, "https://lancedb.github.io/lancedb/basic/\nhttps://blog.example.com/chromadb-vs-lancedb\n", output,
+		"explicit sort orders are left alone")
+}
+
+func TestSearches_ListTopAndClear(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Search.RecordHistory = true
+
+	require.NoError(t, store.RecordSearch(ctx, &storage.SearchRecord{Query: "sqlite wal", Domain: "sqlite.org", Since: "7d", Results: 1}))
+	require.NoError(t, store.RecordSearch(ctx, &storage.SearchRecord{Query: "sqlite wal", Results: 0}))
+
+	cmd := &SearchesCommand{Limit: 20, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() { require.NoError(t, cmd.executeWithStore(store, cfg)) })
+	assert.Contains(t, output, `"sqlite wal" domain:sqlite.org since:7d  1 result`)
+	assert.Contains(t, output, `"sqlite wal"  0 results`)
+
+	cmd = &SearchesCommand{Limit: 20, Top: true, globals: &GlobalFlags{JSON: true}}
+	output = captureOutput(t, func() { require.NoError(t, cmd.executeWithStore(store, cfg)) })
+	var top []queryCountJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &top))
+	require.Len(t, top, 1)
+	assert.Equal(t, int64(2), top[0].Count)
+	assert.Equal(t, int64(1), top[0].ZeroResults)
+
+	cmd = &SearchesCommand{Clear: true, globals: &GlobalFlags{}}
+	output = captureOutput(t, func() { require.NoError(t, cmd.executeWithStore(store, cfg)) })
+	assert.Equal(t, "Cleared 2 recorded searches\n", output)
+	recs, err := store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// statsJSON is the JSON output structure for the stats command.
+type statsJSON struct {
+	Since     string             `json:"since"`
+	Until     string             `json:"until"`
+	Timezone  string             `json:"timezone"`
+	Total     int64              `json:"total"`
+	ByHour    [24]int64          `json:"by_hour"`
+	ByWeekday []weekdayCountJSON `json:"by_weekday"`
+	Heat      []weekdayHoursJSON `json:"heat"`
+	Bodies    int64              `json:"bodies"`
+	Words     int64              `json:"words"`
+	Chars     int64              `json:"chars"`
+}
+
+type weekdayCountJSON struct {
+	Weekday string `json:"weekday"`
+	Count   int64  `json:"count"`
+}
+
+type weekdayHoursJSON struct {
+	Weekday string    `json:"weekday"`
+	Hours   [24]int64 `json:"hours"`
+}
+
+// statsWeekdays is the display order for weekdays, Monday first.
+var statsWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// heatShades are the heat table glyphs, from no events to the busiest hour.
+var heatShades = []rune(" ░▒▓█")
+
+// weekdayBarWidth is the width of the longest weekday bar.
+const weekdayBarWidth = 30
+
+// Execute implements the go-flags Commander interface for StatsCommand.
+func (c *StatsCommand) Execute(args []string) error {
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore runs stats against a provided store (used by tests).
+func (c *StatsCommand) executeWithStore(store storage.Store, now time.Time) error {
+	if c.Heatmap {
+		return c.heatmap(store, now)
+	}
+	if c.SVG != "" {
+		return fmt.Errorf("--svg requires --heatmap")
+	}
+
+	since, _, err := resolveTimeRange(c.Since, "", now)
+	if err != nil {
+		return err
+	}
+
+	activity, err := store.ActivityByTime(context.Background(), since, now)
+	if err != nil {
+		return fmt.Errorf("stats: %w", err)
+	}
+	totals, err := store.ContentTotals(context.Background(), since, now)
+	if err != nil {
+		return fmt.Errorf("stats: %w", err)
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		return printStatsJSON(activity, totals, since, now)
+	}
+	printStatsHuman(activity, totals, c.Since)
+	return nil
+}
+
+func printStatsJSON(a *storage.TimeActivity, totals *storage.ContentTotals, since, until time.Time) error {
+	weekdays := a.ByWeekday()
+	out := statsJSON{
+		Since:    since.Format(time.RFC3339),
+		Until:    until.Format(time.RFC3339),
+		Timezone: since.Location().String(),
+		ByHour:   a.ByHour(),
+		Bodies:   totals.Bodies,
+		Words:    totals.Words,
+		Chars:    totals.Chars,
+	}
+	for _, wd := range statsWeekdays {
+		out.Total += weekdays[wd]
+		out.ByWeekday = append(out.ByWeekday, weekdayCountJSON{Weekday: wd.String(), Count: weekdays[wd]})
+		out.Heat = append(out.Heat, weekdayHoursJSON{Weekday: wd.String(), Hours: a.Counts[wd]})
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func printStatsHuman(a *storage.TimeActivity, totals *storage.ContentTotals, sinceStr string) {
+	weekdays := a.ByWeekday()
+	var total, busiest int64
+	for _, day := range a.Counts {
+		for _, n := range day {
+			total += n
+			if n > busiest {
+				busiest = n
+			}
+		}
+	}
+
+	window := "since " + sinceStr
+	if isDurationSpec(sinceStr) {
+		window = "last " + sinceStr
+	}
+	fmt.Printf("Activity (%s, local time): %s events\n", window, formatNumber(total))
+	if total == 0 {
+		return
+	}
+	if totals.Bodies > 0 {
+		fmt.Printf("Captured: %s words, %s characters in %s bodies\n",
+			formatCompact(totals.Words), formatCompact(totals.Chars), formatNumber(totals.Bodies))
+	}
+
+	fmt.Println()
+	fmt.Print("     ")
+	for h := 0; h < 24; h++ {
+		fmt.Printf("%02d ", h)
+	}
+	fmt.Println()
+	for _, wd := range statsWeekdays {
+		fmt.Printf("%s  ", wd.String()[:3])
+		for _, n := range a.Counts[wd] {
+			shade := string(heatShade(n, busiest))
+			fmt.Print(strings.Repeat(shade, 2) + " ")
+		}
+		fmt.Println()
+	}
+
+	hours := a.ByHour()
+	peak := 0
+	for h, n := range hours {
+		if n > hours[peak] {
+			peak = h
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Hours:     %s\n", sparkline(hours[:]))
+	fmt.Printf("Peak hour: %02d:00 (%s events)\n", peak, formatNumber(hours[peak]))
+
+	var maxDay int64
+	for _, n := range weekdays {
+		if n > maxDay {
+			maxDay = n
+		}
+	}
+	fmt.Println()
+	fmt.Println("Weekdays:")
+	for _, wd := range statsWeekdays {
+		n := weekdays[wd]
+		bar := int(n * weekdayBarWidth / maxDay)
+		if n > 0 && bar == 0 {
+			bar = 1
+		}
+		fmt.Printf("  %s  %-*s  %s\n", wd.String()[:3], weekdayBarWidth, strings.Repeat("█", bar), formatNumber(n))
+	}
+}
+
+// heatShade picks the heat table glyph for n relative to the busiest cell.
+// Any nonzero count gets at least the lightest visible shade.
+func heatShade(n, busiest int64) rune {
+	return heatShades[heatLevel(n, busiest, len(heatShades))]
+}
+
+// heatLevel scales n against busiest into levels 0..levels-1, where 0 means
+// no events and any nonzero count is at least level 1.
+func heatLevel(n, busiest int64, levels int) int {
+	if n <= 0 || busiest <= 0 {
+		return 0
+	}
+	return 1 + int((n*int64(levels-1)-1)/busiest)
+}
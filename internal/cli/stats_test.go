@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestStats_HeatTableAndWeekdays(t *testing.T) {
+	store, _ := setupStatusTest(t)
+	ctx := context.Background()
+
+	// 2026-03-02 is a Monday.
+	for _, at := range []time.Time{
+		time.Date(2026, 3, 2, 9, 15, 0, 0, time.Local),
+		time.Date(2026, 3, 2, 9, 45, 0, 0, time.Local),
+		time.Date(2026, 3, 4, 22, 0, 0, 0, time.Local),
+	} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com", Title: "E", Source: "manual", Timestamp: at}))
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	cmd := &StatsCommand{Since: "30d", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Contains(t, output, "Activity (last 30d, local time): 3 events")
+	assert.Contains(t, output, "Mon  ")
+	assert.Contains(t, output, "██ ", "busiest cell should be fully shaded")
+	assert.Contains(t, output, "Peak hour: 09:00 (2 events)")
+	assert.Contains(t, output, "  Wed  "+"███████████████"+"                 1")
+
+	cmd.globals.JSON = true
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var out statsJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, int64(3), out.Total)
+	assert.Equal(t, int64(2), out.ByHour[9])
+	require.Len(t, out.ByWeekday, 7)
+	assert.Equal(t, weekdayCountJSON{Weekday: "Monday", Count: 2}, out.ByWeekday[0])
+	assert.Equal(t, int64(1), out.Heat[2].Hours[22])
+}
+
+func TestStats_ContentTotals(t *testing.T) {
+	store, _ := setupStatusTest(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	body := strings.Repeat("word ", 12_000)
+	require.NoError(t, store.AddEventWithContent(ctx, &storage.Event{URL: "https://example.com/a", Title: "A", Source: "manual", Timestamp: at}, body))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com/b", Title: "B", Source: "manual", Timestamp: at}))
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	cmd := &StatsCommand{Since: "30d", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	assert.Contains(t, output, "Captured: 12.0K words, 60.0K characters in 1 bodies")
+
+	cmd.globals.JSON = true
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var out statsJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, int64(1), out.Bodies)
+	assert.Equal(t, int64(12_000), out.Words)
+	assert.Equal(t, int64(len(body)), out.Chars)
+}
+
+func TestFormatCompact(t *testing.T) {
+	assert.Equal(t, "950", formatCompact(950))
+	assert.Equal(t, "9,999", formatCompact(9999))
+	assert.Equal(t, "12.3K", formatCompact(12_345))
+	assert.Equal(t, "1.2M", formatCompact(1_200_000))
+	assert.Equal(t, "3.4B", formatCompact(3_400_000_000))
+}
+
+func TestStats_Empty(t *testing.T) {
+	store, _ := setupStatusTest(t)
+
+	cmd := &StatsCommand{Since: "7d", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, time.Now()))
+	})
+	assert.Equal(t, "Activity (last 7d, local time): 0 events\n", output)
+}
+
+func TestStats_InvalidSince(t *testing.T) {
+	store, _ := setupStatusTest(t)
+
+	cmd := &StatsCommand{Since: "soon", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --since")
+}
+
+func TestHeatShade(t *testing.T) {
+	assert.Equal(t, ' ', heatShade(0, 10))
+	assert.Equal(t, '░', heatShade(1, 10))
+	assert.Equal(t, '█', heatShade(10, 10))
+}
+
+func TestStats_Heatmap(t *testing.T) {
+	store, _ := setupStatusTest(t)
+	ctx := context.Background()
+
+	// 2026-03-11 is a Wednesday.
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.Local)
+	for _, at := range []time.Time{
+		now.Add(-time.Hour),
+		now.Add(-2 * time.Hour),
+		now.AddDate(0, 0, -2),
+		now.AddDate(0, -6, 0),
+		now.AddDate(-2, 0, 0),
+	} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com", Title: "E", Source: "manual", Timestamp: at}))
+	}
+
+	svg := filepath.Join(t.TempDir(), "heatmap.svg")
+	cmd := &StatsCommand{Heatmap: true, SVG: svg, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	lines := strings.Split(output, "\n")
+	require.GreaterOrEqual(t, len(lines), 8)
+	assert.Contains(t, lines[0], "Mar")
+	assert.True(t, strings.HasPrefix(lines[1], "Mon  "))
+	assert.True(t, strings.HasSuffix(lines[3], "█"), "today (Wednesday) is the busiest day: %q", lines[3])
+	assert.True(t, strings.HasSuffix(lines[1], "▒"), "Monday has half as many: %q", lines[1])
+	assert.Len(t, []rune(lines[1]), 5+heatmapWeeks)
+	assert.Len(t, []rune(lines[4]), 5+heatmapWeeks-1, "days after today are blank")
+	assert.Contains(t, output, "4 events in the last year")
+
+	data, err := os.ReadFile(svg)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "<svg "))
+	assert.Contains(t, string(data), "<title>2026-03-11: 2 events</title>")
+	assert.Contains(t, string(data), `fill="#216e39"`)
+
+	cmd = &StatsCommand{Heatmap: true, globals: &GlobalFlags{JSON: true}}
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, now))
+	})
+	var out heatmapJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &out))
+	assert.Equal(t, int64(4), out.Total)
+	assert.Equal(t, int64(2), out.Max)
+	assert.Equal(t, "2026-03-11", out.Until)
+	assert.Equal(t, time.Monday, mustParseDate(t, out.Since).Weekday())
+	assert.Len(t, out.Days, 7*(heatmapWeeks-1)+3)
+}
+
+func TestStats_SVGRequiresHeatmap(t *testing.T) {
+	store, _ := setupStatusTest(t)
+
+	cmd := &StatsCommand{Since: "30d", SVG: "out.svg", globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--svg requires --heatmap")
+}
+
+func mustParseDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	require.NoError(t, err)
+	return d
+}
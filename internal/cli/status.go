@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/daemon"
 	"github.com/runnerr0/chronicle/internal/storage"
 )
 
@@ -24,29 +29,191 @@ type statusJSON struct {
 	NewestEvent       string            `json:"newest_event,omitempty"`
 	RetentionDays     int               `json:"retention_days"`
 	TopDomains        []domainCountJSON `json:"top_domains"`
+	Sources           []valueCountJSON  `json:"sources"`
+	Browsers          []valueCountJSON  `json:"browsers"`
 	DaemonRunning     bool              `json:"daemon_running"`
 	EmbeddingsEnabled bool              `json:"embeddings_enabled"`
+	EmbeddingsModel   string            `json:"embeddings_model,omitempty"`
+	LastPruneAt       string            `json:"last_prune_at,omitempty"`
+	LastPruneCount    int64             `json:"last_prune_count"`
+	NextPruneAt       string            `json:"next_prune_at,omitempty"`
+	SchemaVersion     int               `json:"schema_version"`
+	WALSizeBytes      int64             `json:"wal_size_bytes"`
+	FTSIndexBytes     int64             `json:"fts_index_bytes"`
+	DefaultExclusions int64             `json:"default_exclusions"`
+	UserExclusions    int64             `json:"user_exclusions"`
+	MissingBodies     int64             `json:"missing_bodies"`
+	MissingEmbeddings int64             `json:"missing_embeddings"`
+	EventsPerDay      []dayCountJSON    `json:"events_per_day"`
+	Extensions        []extensionJSON   `json:"extensions"`
 }
 
+// storageInfo holds on-disk details reported by status.
+type storageInfo struct {
+	Path          string
+	SizeBytes     int64
+	WALSizeBytes  int64
+	SchemaVersion int
+}
+
+type dayCountJSON struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// activityDays is how many days of capture activity status charts.
+const activityDays = 30
+
+// extensionJSON is a registered browser extension in status output.
+type extensionJSON struct {
+	ID           string `json:"id"`
+	Browser      string `json:"browser"`
+	Version      string `json:"version"`
+	Profile      string `json:"profile,omitempty"`
+	RegisteredAt string `json:"registered_at"`
+	LastSeen     string `json:"last_seen"`
+	Connected    bool   `json:"connected"`
+}
+
+// extensionTimeout is how long after its last heartbeat an extension still
+// counts as connected.
+const extensionTimeout = 3 * daemon.HeartbeatInterval
+
 type domainCountJSON struct {
 	Domain string `json:"domain"`
 	Count  int64  `json:"count"`
 }
 
+type valueCountJSON struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func toValueCountsJSON(counts []storage.ValueCount) []valueCountJSON {
+	out := make([]valueCountJSON, len(counts))
+	for i, vc := range counts {
+		out[i] = valueCountJSON{Name: vc.Value, Count: vc.Count}
+	}
+	return out
+}
+
+// describeCounts lists values with their counts, busiest first, e.g.
+// "extension 1,204, manual 3".
+func describeCounts(counts []storage.ValueCount) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(counts))
+	for i, vc := range counts {
+		parts[i] = vc.Value + " " + formatNumber(vc.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // Execute implements the go-flags Commander interface for StatusCommand.
 func (c *StatusCommand) Execute(args []string) error {
-	store, db, err := openDefaultStore()
+	store, db, err := openStore(c.globals)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
 	defer store.Close()
 
-	return c.executeWithStore(store, db)
+	cfg := loadConfig(c.globals)
+
+	if c.Watch == "" {
+		return c.executeWithStore(store, db, cfg)
+	}
+
+	// go-flags only binds optional values given as --watch=5s, so accept
+	// "--watch 5s" by taking the interval from the positional argument.
+	raw := c.Watch
+	if len(args) > 0 {
+		raw = args[0]
+	}
+	interval, err := parseWatchInterval(raw)
+	if err != nil {
+		return err
+	}
+	if c.globals != nil && c.globals.JSON {
+		return fmt.Errorf("--watch cannot be combined with --json")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return c.watch(ctx, store, db, cfg, interval)
+}
+
+// parseWatchInterval parses a --watch interval, accepting Go durations
+// ("10s", "1m") or a bare number of seconds.
+func parseWatchInterval(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		secs, serr := strconv.Atoi(s)
+		if serr != nil {
+			return 0, fmt.Errorf("invalid --watch interval %q: %w", s, err)
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d < time.Second {
+		return 0, fmt.Errorf("invalid --watch interval %q: must be at least 1s", s)
+	}
+	return d, nil
 }
 
-// executeWithStore runs status against a provided store and db (for testing).
-func (c *StatusCommand) executeWithStore(store *storage.SQLiteStore, db *sql.DB) error {
+// watch redraws status every interval until ctx is cancelled, adding the
+// ingest rate measured between refreshes.
+func (c *StatusCommand) watch(ctx context.Context, store *storage.SQLiteStore, db *sql.DB, cfg *config.Config, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prevCount int64
+	var prevAt time.Time
+
+	for {
+		stats, err := store.GetStats(context.Background())
+		if err != nil {
+			return fmt.Errorf("get stats: %w", err)
+		}
+		now := time.Now()
+
+		// Move the cursor home and clear the screen to redraw in place.
+		fmt.Print("\033[H\033[2J")
+		if err := c.executeWithStore(store, db, cfg); err != nil {
+			return err
+		}
+
+		fmt.Println()
+		if prevAt.IsZero() {
+			fmt.Println("Ingest rate:   measuring...")
+		} else {
+			fmt.Printf("Ingest rate:   %.1f events/min\n", ingestRate(stats.TotalEvents-prevCount, now.Sub(prevAt)))
+		}
+		fmt.Printf("\nRefreshing every %s (Ctrl-C to exit)\n", interval)
+
+		prevCount, prevAt = stats.TotalEvents, now
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ingestRate converts an event delta over elapsed into events per minute.
+// A negative delta (events pruned meanwhile) counts as zero.
+func ingestRate(delta int64, elapsed time.Duration) float64 {
+	if delta <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(delta) / elapsed.Minutes()
+}
+
+// executeWithStore runs status against a provided store, db and config (for
+// testing).
+func (c *StatusCommand) executeWithStore(store *storage.SQLiteStore, db *sql.DB, cfg *config.Config) error {
 	ctx := context.Background()
 
 	stats, err := store.GetStats(ctx)
@@ -54,27 +221,55 @@ func (c *StatusCommand) executeWithStore(store *storage.SQLiteStore, db *sql.DB)
 		return fmt.Errorf("get stats: %w", err)
 	}
 
-	// Database size
-	dbPath := defaultDBPath()
-	dbSize := getDatabaseSize(db, dbPath)
+	// Database size, WAL size and schema version
+	dbPath, err := resolveDBPath(c.globals)
+	if err != nil {
+		return err
+	}
+	disk := storageInfo{
+		Path:         dbPath,
+		SizeBytes:    getDatabaseSize(db, dbPath),
+		WALSizeBytes: getFileSize(dbPath + "-wal"),
+	}
+	if v, err := storage.SchemaVersion(db); err == nil {
+		disk.SchemaVersion = v
+	}
 
 	// Daemon check
-	daemonRunning := checkDaemon()
+	daemonRunning := checkDaemon(cfg)
 
-	// Retention (default 30 days)
-	retentionDays := 30
+	retention := retentionDays(cfg)
+
+	sched, err := loadPruneSchedule(ctx, store, cfg)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	activity, err := store.EventsPerDay(ctx, now.AddDate(0, 0, -(activityDays-1)), now)
+	if err != nil {
+		return err
+	}
+
+	exts, err := store.ListExtensions(ctx)
+	if err != nil {
+		return err
+	}
 
 	if c.globals != nil && c.globals.JSON {
-		return c.printStatusJSON(stats, dbPath, dbSize, daemonRunning, retentionDays)
+		return c.printStatusJSON(stats, disk, daemonRunning, cfg.Embeddings, retention, sched, activity, exts)
 	}
-	return c.printStatusHuman(stats, dbPath, dbSize, daemonRunning, retentionDays)
+	return c.printStatusHuman(stats, disk, daemonRunning, cfg.Embeddings, retention, sched, activity, exts)
 }
 
-func (c *StatusCommand) printStatusHuman(stats *storage.Stats, dbPath string, dbSize int64, daemonRunning bool, retentionDays int) error {
+func (c *StatusCommand) printStatusHuman(stats *storage.Stats, disk storageInfo, daemonRunning bool, embeddings config.EmbeddingsConfig, retentionDays int, sched pruneSchedule, activity []storage.DayCount, exts []storage.Extension) error {
 	fmt.Println("Chronicle Status")
 	fmt.Println("================")
 	fmt.Printf("Version:       %s\n", c.version)
-	fmt.Printf("Database:      %s (%s)\n", dbPath, formatBytes(dbSize))
+	fmt.Printf("Database:      %s (%s)\n", disk.Path, formatBytes(disk.SizeBytes))
+	fmt.Printf("Schema:        v%d\n", disk.SchemaVersion)
+	fmt.Printf("WAL:           %s\n", formatBytes(disk.WALSizeBytes))
+	fmt.Printf("FTS index:     %s\n", formatBytes(stats.FTSIndexBytes))
 	fmt.Printf("Events:        %s\n", formatNumber(stats.TotalEvents))
 
 	// Content with percentage
@@ -91,7 +286,15 @@ func (c *StatusCommand) printStatusHuman(stats *storage.Stats, dbPath string, db
 		fmt.Printf("Newest:        %s\n", stats.NewestEvent.Local().Format("2006-01-02"))
 	}
 
+	fmt.Printf("No body:       %s\n", formatNumber(stats.MissingBodies))
+	fmt.Printf("No embedding:  %s\n", formatNumber(stats.MissingEmbeddings))
+	fmt.Printf("Exclusions:    %d default, %d user\n", stats.DefaultExclusions, stats.UserExclusions)
 	fmt.Printf("Retention:     %d days\n", retentionDays)
+	fmt.Printf("Last prune:    %s\n", sched.describeLast())
+	fmt.Printf("Next prune:    %s\n", sched.describeNext(time.Now()))
+	fmt.Printf("Activity:      %s\n", describeActivity(activity))
+	fmt.Printf("Sources:       %s\n", describeCounts(stats.Sources))
+	fmt.Printf("Browsers:      %s\n", describeCounts(stats.Browsers))
 
 	// Top domains
 	if len(stats.TopDomains) > 0 {
@@ -108,38 +311,159 @@ func (c *StatusCommand) printStatusHuman(stats *storage.Stats, dbPath string, db
 	} else {
 		fmt.Println("Daemon:        not running")
 	}
-	fmt.Println("Embeddings:    disabled")
+	fmt.Printf("Embeddings:    %s\n", describeEmbeddings(embeddings))
+
+	if len(exts) > 0 {
+		now := time.Now()
+		fmt.Println()
+		fmt.Println("Extensions:")
+		for _, e := range exts {
+			fmt.Printf("  %-28s %s\n", describeExtension(e), describeLastSeen(e, now))
+		}
+	}
 
 	return nil
 }
 
-func (c *StatusCommand) printStatusJSON(stats *storage.Stats, dbPath string, dbSize int64, daemonRunning bool, retentionDays int) error {
+// describeEmbeddings reports whether embeddings are on and, if so, with
+// which model, e.g. "enabled (nomic-embed-text via ollama)".
+func describeEmbeddings(e config.EmbeddingsConfig) string {
+	if !e.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("enabled (%s via %s)", e.Model, e.Provider)
+}
+
+// describeExtension names an extension as browser, version and profile,
+// e.g. "chrome 1.3.0 (Work)".
+func describeExtension(e storage.Extension) string {
+	s := e.Browser
+	if e.Version != "" {
+		s += " " + e.Version
+	}
+	if e.Profile != "" {
+		s += " (" + e.Profile + ")"
+	}
+	return s
+}
+
+// describeLastSeen reports when e last checked in, and whether that was
+// recent enough to count it as connected.
+func describeLastSeen(e storage.Extension, now time.Time) string {
+	seen := "last seen " + e.LastSeen.Local().Format("2006-01-02 15:04")
+	if extensionConnected(e, now) {
+		return "connected, " + seen
+	}
+	return seen
+}
+
+func extensionConnected(e storage.Extension, now time.Time) bool {
+	return now.Sub(e.LastSeen) <= extensionTimeout
+}
+
+func (c *StatusCommand) printStatusJSON(stats *storage.Stats, disk storageInfo, daemonRunning bool, embeddings config.EmbeddingsConfig, retentionDays int, sched pruneSchedule, activity []storage.DayCount, exts []storage.Extension) error {
 	out := statusJSON{
 		Version:           c.version,
-		DatabasePath:      dbPath,
-		DatabaseSizeBytes: dbSize,
+		DatabasePath:      disk.Path,
+		DatabaseSizeBytes: disk.SizeBytes,
 		TotalEvents:       stats.TotalEvents,
 		TotalContent:      stats.TotalContent,
 		RetentionDays:     retentionDays,
 		TopDomains:        make([]domainCountJSON, len(stats.TopDomains)),
+		Sources:           toValueCountsJSON(stats.Sources),
+		Browsers:          toValueCountsJSON(stats.Browsers),
 		DaemonRunning:     daemonRunning,
-		EmbeddingsEnabled: false,
+		EmbeddingsEnabled: embeddings.Enabled,
+		SchemaVersion:     disk.SchemaVersion,
+		WALSizeBytes:      disk.WALSizeBytes,
+		FTSIndexBytes:     stats.FTSIndexBytes,
+		DefaultExclusions: stats.DefaultExclusions,
+		UserExclusions:    stats.UserExclusions,
+		MissingBodies:     stats.MissingBodies,
+		MissingEmbeddings: stats.MissingEmbeddings,
+		EventsPerDay:      make([]dayCountJSON, len(activity)),
+		Extensions:        make([]extensionJSON, len(exts)),
 	}
 
+	if embeddings.Enabled {
+		out.EmbeddingsModel = embeddings.Model
+	}
 	if stats.TotalEvents > 0 {
 		out.OldestEvent = stats.OldestEvent.UTC().Format(time.RFC3339)
 		out.NewestEvent = stats.NewestEvent.UTC().Format(time.RFC3339)
 	}
 
+	if sched.Last != nil {
+		out.LastPruneAt = sched.Last.At.UTC().Format(time.RFC3339)
+		out.LastPruneCount = sched.Last.Count
+	}
+	if next := sched.Next(); !next.IsZero() {
+		out.NextPruneAt = next.UTC().Format(time.RFC3339)
+	}
+
 	for i, d := range stats.TopDomains {
 		out.TopDomains[i] = domainCountJSON{Domain: d.Domain, Count: d.Count}
 	}
 
+	for i, d := range activity {
+		out.EventsPerDay[i] = dayCountJSON{Date: d.Day.Format("2006-01-02"), Count: d.Count}
+	}
+
+	now := time.Now()
+	for i, e := range exts {
+		out.Extensions[i] = extensionJSON{
+			ID:           e.ID,
+			Browser:      e.Browser,
+			Version:      e.Version,
+			Profile:      e.Profile,
+			RegisteredAt: e.RegisteredAt.UTC().Format(time.RFC3339),
+			LastSeen:     e.LastSeen.UTC().Format(time.RFC3339),
+			Connected:    extensionConnected(e, now),
+		}
+	}
+
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
+// describeActivity renders daily counts as a sparkline with the span and
+// total, e.g. "▁▃█▅ (last 4 days, 12 events)".
+func describeActivity(days []storage.DayCount) string {
+	counts := make([]int64, len(days))
+	var total int64
+	for i, d := range days {
+		counts[i] = d.Count
+		total += d.Count
+	}
+	return fmt.Sprintf("%s (last %d days, %s events)", sparkline(counts), len(days), formatNumber(total))
+}
+
+// sparkBlocks are the bar glyphs used by sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders counts as one bar per value, scaled to the largest.
+// Zero always renders as the lowest bar; any nonzero count as at least the
+// second, so quiet days stay distinguishable from empty ones.
+func sparkline(counts []int64) string {
+	var max int64
+	for _, n := range counts {
+		if n > max {
+			max = n
+		}
+	}
+
+	bars := make([]rune, len(counts))
+	for i, n := range counts {
+		level := 0
+		if n > 0 {
+			level = 1 + int((n*int64(len(sparkBlocks)-1)-1)/max)
+		}
+		bars[i] = sparkBlocks[level]
+	}
+	return string(bars)
+}
+
 // getDatabaseSize returns the database file size in bytes.
 // For on-disk databases, it uses os.Stat. For in-memory databases,
 // it queries page_count * page_size.
@@ -160,11 +484,21 @@ func getDatabaseSize(db *sql.DB, dbPath string) int64 {
 	return pageCount * pageSize
 }
 
-// checkDaemon attempts an HTTP GET to the default daemon endpoint.
+// getFileSize returns the size of path in bytes, or 0 if it does not exist.
+func getFileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// checkDaemon reports whether the daemon configured in cfg answers
+// GET /status.
 // Returns true if the daemon responds within 1 second.
-func checkDaemon() bool {
+func checkDaemon(cfg *config.Config) bool {
 	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Get("http://localhost:7773/status")
+	resp, err := client.Get("http://" + daemonAddr(cfg) + "/status")
 	if err != nil {
 		return false
 	}
@@ -186,6 +520,20 @@ func formatBytes(b int64) string {
 	}
 }
 
+// formatCompact formats a large count in short form: 950, 12.3K, 1.2M.
+func formatCompact(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(n)/1e9)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 10_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1e3)
+	default:
+		return formatNumber(n)
+	}
+}
+
 // formatNumber formats an int64 with comma separators.
 func formatNumber(n int64) string {
 	s := fmt.Sprintf("%d", n)
@@ -8,8 +8,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/runnerr0/chronicle/internal/config"
 	"github.com/runnerr0/chronicle/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -59,7 +61,7 @@ func TestStatus_EmptyDB(t *testing.T) {
 	}
 
 	output := captureStatusOutput(t, func() {
-		err := cmd.executeWithStore(store, db)
+		err := cmd.executeWithStore(store, db, config.DefaultConfig())
 		require.NoError(t, err)
 	})
 
@@ -108,7 +110,7 @@ func TestStatus_WithData(t *testing.T) {
 	}
 
 	output := captureStatusOutput(t, func() {
-		err := cmd.executeWithStore(store, db)
+		err := cmd.executeWithStore(store, db, config.DefaultConfig())
 		require.NoError(t, err)
 	})
 
@@ -140,7 +142,7 @@ func TestStatus_TopDomainsSorted(t *testing.T) {
 	}
 
 	output := captureStatusOutput(t, func() {
-		err := cmd.executeWithStore(store, db)
+		err := cmd.executeWithStore(store, db, config.DefaultConfig())
 		require.NoError(t, err)
 	})
 
@@ -169,7 +171,7 @@ func TestStatus_JSONOutput(t *testing.T) {
 	}
 
 	output := captureStatusOutput(t, func() {
-		err := cmd.executeWithStore(store, db)
+		err := cmd.executeWithStore(store, db, config.DefaultConfig())
 		require.NoError(t, err)
 	})
 
@@ -206,7 +208,7 @@ func TestStatus_DatabaseSizeReported(t *testing.T) {
 	}
 
 	output := captureStatusOutput(t, func() {
-		err := cmd.executeWithStore(store, db)
+		err := cmd.executeWithStore(store, db, config.DefaultConfig())
 		require.NoError(t, err)
 	})
 
@@ -217,3 +219,186 @@ func TestStatus_DatabaseSizeReported(t *testing.T) {
 	// In-memory DBs report 0 for page_count, so we accept >= 0
 	assert.GreaterOrEqual(t, result.DatabaseSizeBytes, int64(0))
 }
+
+func TestStatus_LastPrune(t *testing.T) {
+	store, db := setupStatusTest(t)
+	ctx := context.Background()
+
+	cmd := &StatusCommand{globals: &GlobalFlags{}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+	assert.Contains(t, output, "Last prune:    never")
+
+	require.NoError(t, store.RecordPrune(ctx, storage.PruneRecord{At: time.Now(), Count: 4}))
+
+	cmd.globals.JSON = true
+	output = captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, int64(4), result.LastPruneCount)
+	assert.NotEmpty(t, result.LastPruneAt)
+	assert.NotEmpty(t, result.NextPruneAt)
+}
+
+func TestParseWatchInterval(t *testing.T) {
+	d, err := parseWatchInterval("10s")
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, d)
+
+	d, err = parseWatchInterval("3")
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, d)
+
+	_, err = parseWatchInterval("100ms")
+	assert.Error(t, err)
+
+	_, err = parseWatchInterval("soon")
+	assert.Error(t, err)
+}
+
+func TestIngestRate(t *testing.T) {
+	assert.Equal(t, 12.0, ingestRate(6, 30*time.Second))
+	assert.Equal(t, 0.0, ingestRate(-3, time.Minute))
+	assert.Equal(t, 0.0, ingestRate(5, 0))
+}
+
+func TestStatus_WatchStopsOnCancel(t *testing.T) {
+	store, db := setupStatusTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := &StatusCommand{globals: &GlobalFlags{}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.watch(ctx, store, db, config.DefaultConfig(), time.Second))
+	})
+
+	assert.Contains(t, output, "Chronicle Status")
+	assert.Contains(t, output, "Ingest rate:   measuring...")
+}
+
+func TestStatus_ExtendedJSON(t *testing.T) {
+	store, db := setupStatusTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com/a", Title: "A", Source: "manual"}))
+
+	cmd := &StatusCommand{globals: &GlobalFlags{JSON: true}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, storage.NewMigrationRunner(nil).LatestVersion(), result.SchemaVersion)
+	assert.Equal(t, int64(1), result.MissingBodies)
+	assert.Equal(t, int64(1), result.MissingEmbeddings)
+	assert.Greater(t, result.DefaultExclusions, int64(0))
+	assert.Equal(t, int64(0), result.UserExclusions)
+}
+
+func TestStatus_ReportsConfiguredRetention(t *testing.T) {
+	store, db := setupStatusTest(t)
+	cfg := config.DefaultConfig()
+	cfg.Retention.Days = 90
+
+	cmd := &StatusCommand{globals: &GlobalFlags{JSON: true}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, cfg))
+	})
+
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 90, result.RetentionDays)
+}
+
+func TestStatus_ReportsEmbeddings(t *testing.T) {
+	store, db := setupStatusTest(t)
+	cfg := config.DefaultConfig()
+	cfg.Embeddings.Enabled = true
+
+	cmd := &StatusCommand{globals: &GlobalFlags{}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, cfg))
+	})
+	assert.Contains(t, output, "Embeddings:    enabled (nomic-embed-text via ollama)")
+
+	cmd.globals.JSON = true
+	output = captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, cfg))
+	})
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.True(t, result.EmbeddingsEnabled)
+	assert.Equal(t, "nomic-embed-text", result.EmbeddingsModel)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", sparkline(nil))
+	assert.Equal(t, "▁▁▁", sparkline([]int64{0, 0, 0}))
+	assert.Equal(t, "▁▂▅█", sparkline([]int64{0, 1, 50, 100}))
+	assert.Equal(t, "█", sparkline([]int64{1}))
+}
+
+func TestStatus_ShowsActivity(t *testing.T) {
+	store, db := setupStatusTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com/a", Title: "A", Source: "manual"}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://example.com/b", Title: "B", Source: "manual",
+		Timestamp: time.Now().AddDate(0, 0, -3)}))
+
+	cmd := &StatusCommand{globals: &GlobalFlags{}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+	assert.Contains(t, output, "Activity:      ")
+	assert.Contains(t, output, "█▁▁█ (last 30 days, 2 events)")
+
+	cmd.globals.JSON = true
+	output = captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.EventsPerDay, activityDays)
+	assert.Equal(t, time.Now().Format("2006-01-02"), result.EventsPerDay[activityDays-1].Date)
+	assert.Equal(t, int64(1), result.EventsPerDay[activityDays-1].Count)
+	assert.Equal(t, int64(1), result.EventsPerDay[activityDays-4].Count)
+}
+
+func TestStatus_ShowsExtensions(t *testing.T) {
+	store, db := setupStatusTest(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	chrome := &storage.Extension{Browser: "chrome", Version: "1.3.0", Profile: "Work"}
+	require.NoError(t, store.RegisterExtension(ctx, chrome, now.Add(-time.Minute)))
+	firefox := &storage.Extension{Browser: "firefox", Version: "1.1.0"}
+	require.NoError(t, store.RegisterExtension(ctx, firefox, now.Add(-time.Hour)))
+
+	cmd := &StatusCommand{globals: &GlobalFlags{}, version: "dev"}
+	output := captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+	assert.Contains(t, output, "Extensions:")
+	assert.Regexp(t, `chrome 1\.3\.0 \(Work\)\s+connected, last seen `, output)
+	assert.Regexp(t, `firefox 1\.1\.0\s+last seen `, output)
+	assert.NotRegexp(t, `firefox 1\.1\.0\s+connected`, output)
+
+	cmd.globals.JSON = true
+	output = captureStatusOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, db, config.DefaultConfig()))
+	})
+	var result statusJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Extensions, 2)
+	assert.Equal(t, chrome.ID, result.Extensions[0].ID)
+	assert.True(t, result.Extensions[0].Connected)
+	assert.Equal(t, "firefox", result.Extensions[1].Browser)
+	assert.False(t, result.Extensions[1].Connected)
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/fabric"
+	"github.com/runnerr0/chronicle/internal/ollama"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// summarizeJSON is the JSON output structure for the summarize command.
+type summarizeJSON struct {
+	ID      string `json:"id"`
+	Pattern string `json:"pattern"`
+	Result  string `json:"result"`
+	SavedID string `json:"saved_id,omitempty"`
+	Stored  bool   `json:"stored,omitempty"`
+}
+
+// summarizePendingJSON is the JSON output structure for summarize --pending.
+type summarizePendingJSON struct {
+	Model      string `json:"model"`
+	Summarized int    `json:"summarized"`
+	Skipped    int    `json:"skipped"`
+}
+
+// summaryPrompt asks an Ollama model for the summary stored with an event.
+const summaryPrompt = "Summarize the following web page in two or three plain sentences. Reply with the summary alone.\n\n"
+
+// Execute implements the go-flags Commander interface for SummarizeCommand.
+func (c *SummarizeCommand) Execute(args []string) error {
+	if c.ID == "" && !c.Pending {
+		return fmt.Errorf("--id is required for summarize command")
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, loadConfig(c.globals))
+}
+
+// executeWithStore runs the summarize logic against a provided store and
+// config (used by tests).
+func (c *SummarizeCommand) executeWithStore(store *storage.SQLiteStore, cfg *config.Config) error {
+	ctx := context.Background()
+	if c.Pending {
+		if c.ID != "" {
+			return fmt.Errorf("--pending cannot be combined with --id")
+		}
+		return c.summarizePending(ctx, store, cfg)
+	}
+
+	event, err := store.GetEvent(ctx, string(c.ID))
+	if err != nil {
+		return fmt.Errorf("event %w: %s", ErrNotFound, c.ID)
+	}
+
+	content, err := store.GetContent(ctx, string(c.ID))
+	if err != nil || strings.TrimSpace(content.Body) == "" {
+		return fmt.Errorf("no content captured for event %s", c.ID)
+	}
+
+	pattern := c.Pattern
+	if pattern == "" {
+		pattern = "summarize"
+	}
+
+	fab, err := fabric.New(cfg.Fabric)
+	if err != nil {
+		return err
+	}
+
+	result, err := fab.Run(ctx, pattern, content.Body)
+	if err != nil {
+		return err
+	}
+	result = strings.TrimSpace(result)
+
+	var savedID string
+	if c.Save {
+		saved := &storage.Event{
+			URL:       event.URL,
+			Title:     fmt.Sprintf("%s: %s", pattern, event.Title),
+			Source:    "fabric",
+			Timestamp: time.Now(),
+		}
+		if err := store.AddEventWithContent(ctx, saved, result); err != nil {
+			return fmt.Errorf("storing result: %w", err)
+		}
+		savedID = saved.ID
+	}
+	if c.Store {
+		if err := store.SetSummary(ctx, storage.Summary{EventID: event.ID, Text: result, Model: "fabric:" + pattern}); err != nil {
+			return err
+		}
+	}
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summarizeJSON{
+			ID:      event.ID,
+			Pattern: pattern,
+			Result:  result,
+			SavedID: savedID,
+			Stored:  c.Store,
+		})
+	}
+
+	fmt.Println(result)
+	if savedID != "" {
+		noticef(c.globals, "Saved as %s\n", savedID)
+	}
+	if c.Store {
+		noticef(c.globals, "Stored as the summary of %s\n", event.ID)
+	}
+	return nil
+}
+
+// summarizePending generates and stores a summary for each event with a
+// body but none stored, newest first. Summaries are stored as they are
+// made, so an interrupted run resumes where it stopped.
+func (c *SummarizeCommand) summarizePending(ctx context.Context, store *storage.SQLiteStore, cfg *config.Config) error {
+	if c.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	generate, model, err := newSummarizer(cfg)
+	if err != nil {
+		return err
+	}
+	events, err := store.EventsWithoutSummary(ctx, c.Limit)
+	if err != nil {
+		return err
+	}
+
+	out := summarizePendingJSON{Model: model}
+	bar := newProgress(c.globals, "Summarizing", len(events))
+	for _, e := range events {
+		content, err := store.GetContent(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+		text := ""
+		if strings.TrimSpace(content.Body) != "" {
+			if text, err = generate(ctx, content.Body); err != nil {
+				bar.Finish()
+				return fmt.Errorf("summarizing %s: %w", e.ID, err)
+			}
+			text = strings.TrimSpace(text)
+		}
+		bar.Add(1)
+		if text == "" {
+			out.Skipped++
+			continue
+		}
+		if err := store.SetSummary(ctx, storage.Summary{EventID: e.ID, Text: text, Model: model}); err != nil {
+			bar.Finish()
+			return err
+		}
+		out.Summarized++
+	}
+	bar.Finish()
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(events) == 0 {
+		infof(c.globals, "No events need a summary\n")
+		return nil
+	}
+	infof(c.globals, "Summarized %d events with %s", out.Summarized, model)
+	if out.Skipped > 0 {
+		infof(c.globals, " (%d skipped: empty body or summary)", out.Skipped)
+	}
+	infof(c.globals, "\n")
+	return nil
+}
+
+// newSummarizer returns the summaries.provider summary generator and the
+// model name stored with its summaries.
+func newSummarizer(cfg *config.Config) (func(ctx context.Context, body string) (string, error), string, error) {
+	sc := cfg.Summaries
+	switch sc.Provider {
+	case "ollama":
+		client := ollama.New(sc.OllamaURL)
+		return func(ctx context.Context, body string) (string, error) {
+			return client.Generate(ctx, sc.Model, summaryPrompt+body)
+		}, "ollama:" + sc.Model, nil
+	case "fabric":
+		fab, err := fabric.New(cfg.Fabric)
+		if err != nil {
+			return nil, "", err
+		}
+		return func(ctx context.Context, body string) (string, error) {
+			return fab.Run(ctx, sc.Pattern, body)
+		}, "fabric:" + sc.Pattern, nil
+	default:
+		return nil, "", fmt.Errorf("invalid summaries.provider %q (want ollama or fabric)", sc.Provider)
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// fakeFabric writes a shell script that echoes its pattern argument followed
+// by stdin, and returns a config pointing fabric.binary at it.
+func fakeFabric(t *testing.T) *config.Config {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "fabric")
+	script := "#!/bin/sh\necho \"pattern=$2\"\ncat\n"
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0755))
+
+	cfg := config.DefaultConfig()
+	cfg.Fabric.Binary = bin
+	return cfg
+}
+
+func seedSummarizeEvent(t *testing.T, store *storage.SQLiteStore, body string) string {
+	t.Helper()
+	event := &storage.Event{URL: "https://example.com/post", Title: "A Post", Source: "manual"}
+	if body != "" {
+		require.NoError(t, store.AddEventWithContent(context.Background(), event, body))
+	} else {
+		require.NoError(t, store.AddEvent(context.Background(), event))
+	}
+	return event.ID
+}
+
+func TestSummarize_PipesBodyThroughPattern(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "the body text")
+
+	cmd := &SummarizeCommand{ID: EventID(id), Pattern: "extract_wisdom", globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, fakeFabric(t)))
+	})
+
+	assert.Contains(t, output, "pattern=extract_wisdom")
+	assert.Contains(t, output, "the body text")
+}
+
+func TestSummarize_DefaultPattern(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "body")
+
+	cmd := &SummarizeCommand{ID: EventID(id), globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, fakeFabric(t)))
+	})
+
+	assert.Contains(t, output, "pattern=summarize")
+}
+
+func TestSummarize_SaveStoresResult(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "body")
+
+	cmd := &SummarizeCommand{ID: EventID(id), Save: true, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, fakeFabric(t)))
+	})
+
+	var result summarizeJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, id, result.ID)
+	require.NotEmpty(t, result.SavedID)
+
+	ctx := context.Background()
+	saved, err := store.GetEvent(ctx, result.SavedID)
+	require.NoError(t, err)
+	assert.Equal(t, "fabric", saved.Source)
+	assert.Equal(t, "https://example.com/post", saved.URL)
+
+	content, err := store.GetContent(ctx, result.SavedID)
+	require.NoError(t, err)
+	assert.Equal(t, result.Result, content.Body)
+}
+
+func TestSummarize_NoContent(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "")
+
+	cmd := &SummarizeCommand{ID: EventID(id), globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, fakeFabric(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no content captured")
+}
+
+func TestSummarize_MissingFabricBinary(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "body")
+
+	cfg := config.DefaultConfig()
+	cfg.Fabric.Binary = filepath.Join(t.TempDir(), "no-such-fabric")
+
+	cmd := &SummarizeCommand{ID: EventID(id), globals: &GlobalFlags{}}
+	err := cmd.executeWithStore(store, cfg)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "not found"))
+}
+
+func TestSummarizeRequiresID(t *testing.T) {
+	err := RunWithArgs("test", []string{"summarize"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--id is required")
+}
+
+func TestSummarize_StoreKeepsResultAsSummary(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	id := seedSummarizeEvent(t, store, "body")
+
+	cmd := &SummarizeCommand{ID: EventID(id), Pattern: "summarize_micro", Store: true, globals: &GlobalFlags{Quiet: true}}
+	captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, fakeFabric(t)))
+	})
+
+	sum, err := store.GetSummary(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, sum)
+	assert.Equal(t, "pattern=summarize_micro\nbody", sum.Text)
+	assert.Equal(t, "fabric:summarize_micro", sum.Model)
+}
+
+func TestSummarize_PendingWithFabric(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	first := seedSummarizeEvent(t, store, "first body")
+	second := seedSummarizeEvent(t, store, "second body")
+	seedSummarizeEvent(t, store, "")
+	require.NoError(t, store.SetSummary(ctx, storage.Summary{EventID: first, Text: "kept"}))
+
+	cfg := fakeFabric(t)
+	cfg.Summaries.Provider = "fabric"
+	cmd := &SummarizeCommand{Pending: true, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	var result summarizePendingJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, summarizePendingJSON{Model: "fabric:summarize_micro", Summarized: 1}, result)
+
+	all, err := store.GetSummaries(ctx, []string{first, second})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{first: "kept", second: "pattern=summarize_micro\nsecond body"}, all)
+}
+
+func TestSummarize_PendingWithOllama(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	seedSummarizeEvent(t, store, "Tidal forces raise the sea.")
+
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Model, Prompt string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "llama3.2", req.Model)
+		prompt = req.Prompt
+		w.Write([]byte(`{"response":"  The moon moves the sea.  "}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+	cfg := config.DefaultConfig()
+	cfg.Summaries.OllamaURL = srv.URL
+
+	cmd := &SummarizeCommand{Pending: true, globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	assert.Contains(t, output, "Summarized 1 events with ollama:llama3.2")
+	assert.True(t, strings.HasSuffix(prompt, "\n\nTidal forces raise the sea."), prompt)
+
+	output = captureSearchOutput(t, func() {
+		search := &SearchCommand{Since: "1h", Limit: 10, globals: &GlobalFlags{}}
+		require.NoError(t, search.executeWithStore(store, []string{"post"}))
+	})
+	assert.Contains(t, output, "\n   The moon moves the sea.\n", "search results show the summary")
+
+	output = captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, cfg))
+	})
+	assert.Contains(t, output, "No events need a summary")
+}
+
+func TestSummarize_PendingRejectsUnknownProvider(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+
+	cfg := config.DefaultConfig()
+	cfg.Summaries.Provider = "gpt"
+	err := (&SummarizeCommand{Pending: true, globals: &GlobalFlags{}}).executeWithStore(store, cfg)
+	assert.EqualError(t, err, `invalid summaries.provider "gpt" (want ollama or fabric)`)
+
+	err = (&SummarizeCommand{Pending: true, ID: "CHR-x", globals: &GlobalFlags{}}).executeWithStore(store, cfg)
+	assert.EqualError(t, err, "--pending cannot be combined with --id")
+}
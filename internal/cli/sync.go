@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// syncPageSize is how many local events go into one sync file.
+const syncPageSize = 1000
+
+// syncFileLayout names sync files by write time so they sort in order.
+const syncFileLayout = "20060102T150405.000000000Z"
+
+// syncRecord is one event in a sync file, one JSON object per line.
+type syncRecord struct {
+	URL         string    `json:"url"`
+	Canonical   string    `json:"canonical_url,omitempty"`
+	Title       string    `json:"title"`
+	RawTitle    string    `json:"raw_title,omitempty"`
+	Timestamp   time.Time `json:"ts"`
+	Source      string    `json:"source"`
+	Browser     string    `json:"browser,omitempty"`
+	ContentHash string    `json:"content_hash,omitempty"`
+	HasBody     bool      `json:"has_body,omitempty"`
+	Body        string    `json:"body,omitempty"`
+}
+
+// syncResult summarizes one sync run.
+type syncResult struct {
+	Device   string `json:"device"`
+	Pushed   int    `json:"pushed"`
+	Pulled   int    `json:"pulled"`
+	Existing int    `json:"existing"`
+	Skipped  int    `json:"skipped"`
+	Peers    int    `json:"peers"`
+}
+
+// Execute implements the go-flags Commander interface for SyncCommand.
+func (c *SyncCommand) Execute(args []string) error {
+	if c.Dir == "" {
+		c.Dir = loadConfig(c.globals).Sync.Dir
+	}
+
+	store, db, err := openStore(c.globals)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	defer store.Close()
+
+	return c.executeWithStore(store, time.Now())
+}
+
+// executeWithStore syncs a provided store with the shared folder (used by
+// tests).
+func (c *SyncCommand) executeWithStore(store *storage.SQLiteStore, now time.Time) error {
+	if c.Dir == "" {
+		return fmt.Errorf("no sync folder (set --dir or sync.dir)")
+	}
+	ctx := context.Background()
+
+	device, err := store.SyncDeviceID(ctx)
+	if err != nil {
+		return err
+	}
+	res := syncResult{Device: device}
+
+	if res.Pushed, err = pushSync(ctx, store, filepath.Join(c.Dir, device), device, now); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return fmt.Errorf("read sync folder: %w", err)
+	}
+	// A peer's events are copies of its captures, not new visits: folding
+	// one into a local event would mark that event as the peer's.
+	store.SetDedupeWindow(0)
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == device || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		res.Peers++
+		if err := pullSync(ctx, store, filepath.Join(c.Dir, e.Name()), e.Name(), &res); err != nil {
+			return err
+		}
+	}
+	slog.Info("sync finished", "dir", c.Dir, "pushed", res.Pushed, "pulled", res.Pulled,
+		"existing", res.Existing, "skipped", res.Skipped, "peers", res.Peers)
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	peerWord := "devices"
+	if res.Peers == 1 {
+		peerWord = "device"
+	}
+	infof(c.globals, "Pushed %d events, pulled %d new from %d other %s (%d already present)\n",
+		res.Pushed, res.Pulled, res.Peers, peerWord, res.Existing)
+	if res.Skipped > 0 {
+		infof(c.globals, "Skipped %d events from excluded domains\n", res.Skipped)
+	}
+	return nil
+}
+
+// pushSync writes this device's events that have not been shared yet into
+// dir, one file per page, and marks them synced.
+func pushSync(ctx context.Context, store *storage.SQLiteStore, dir, device string, now time.Time) (int, error) {
+	n := 0
+	for page := 0; ; page++ {
+		events, err := store.UnsyncedEvents(ctx, syncPageSize)
+		if err != nil {
+			return n, err
+		}
+		if len(events) == 0 {
+			return n, nil
+		}
+
+		name := fmt.Sprintf("%s-%04d.jsonl", now.UTC().Format(syncFileLayout), page)
+		if err := writeSyncFile(ctx, store, filepath.Join(dir, name), events); err != nil {
+			return n, err
+		}
+		ids := make([]string, len(events))
+		for i, e := range events {
+			ids[i] = e.ID
+		}
+		if err := store.MarkSynced(ctx, device, ids); err != nil {
+			return n, err
+		}
+		n += len(events)
+	}
+}
+
+// writeSyncFile writes events to path atomically, so peers never read a
+// partial file.
+func writeSyncFile(ctx context.Context, store *storage.SQLiteStore, path string, events []storage.Event) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create sync folder: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create sync file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	bw := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(bw)
+	err = eachWithBody(ctx, store, events, func(e *storage.Event, body string) error {
+		rec := syncRecord{
+			URL:         e.URL,
+			Canonical:   e.CanonicalURL,
+			Title:       e.Title,
+			RawTitle:    e.RawTitle,
+			Timestamp:   e.Timestamp.UTC(),
+			Source:      e.Source,
+			Browser:     e.Browser,
+			ContentHash: e.ContentHash,
+			HasBody:     e.HasBody,
+			Body:        body,
+		}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("write sync file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write sync file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write sync file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// pullSync imports the files in a peer's folder that are newer than the
+// peer's cursor, advancing the cursor after each file.
+func pullSync(ctx context.Context, store *storage.SQLiteStore, dir, peer string, res *syncResult) error {
+	cursor, err := store.SyncCursor(ctx, peer)
+	if err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		name := filepath.Base(path)
+		if name <= cursor {
+			continue
+		}
+		if err := importSyncFile(ctx, store, path, peer, res); err != nil {
+			return fmt.Errorf("import %s: %w", path, err)
+		}
+		if err := store.SetSyncCursor(ctx, peer, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importSyncFile(ctx context.Context, store *storage.SQLiteStore, path, peer string, res *syncResult) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var rec syncRecord
+		if err := dec.Decode(&rec); err != nil {
+			return err
+		}
+
+		exists, err := syncEventExists(ctx, store, &rec)
+		if err != nil {
+			return err
+		}
+		if exists {
+			res.Existing++
+			continue
+		}
+
+		e := &storage.Event{
+			URL:          rec.URL,
+			Title:        rec.Title,
+			RawTitle:     rec.RawTitle,
+			Timestamp:    rec.Timestamp,
+			Source:       rec.Source,
+			Browser:      rec.Browser,
+			ContentHash:  rec.ContentHash,
+			CanonicalURL: rec.Canonical,
+		}
+		if rec.HasBody {
+			err = store.AddEventWithContent(ctx, e, rec.Body)
+		} else {
+			err = store.AddEvent(ctx, e)
+		}
+		if err != nil {
+			return err
+		}
+		if e.ID == "" {
+			res.Skipped++
+			continue
+		}
+		if err := store.MarkSynced(ctx, peer, []string{e.ID}); err != nil {
+			return err
+		}
+		res.Pulled++
+	}
+	return nil
+}
+
+// syncEventExists reports whether the store already holds rec: an event at
+// the same second with the same content hash and normalized URL.
+func syncEventExists(ctx context.Context, store *storage.SQLiteStore, rec *syncRecord) (bool, error) {
+	candidates, err := store.EventsAt(ctx, rec.Timestamp)
+	if err != nil {
+		return false, err
+	}
+	key := normalizeDupURL(rec.URL)
+	for _, e := range candidates {
+		if e.ContentHash == rec.ContentHash && normalizeDupURL(e.URL) == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func runSync(t *testing.T, store *storage.SQLiteStore, dir string) syncResult {
+	t.Helper()
+	cmd := &SyncCommand{Dir: dir, globals: &GlobalFlags{JSON: true}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, time.Now()))
+	})
+	var res syncResult
+	require.NoError(t, json.Unmarshal([]byte(output), &res))
+	return res
+}
+
+func allURLs(t *testing.T, store *storage.SQLiteStore) []string {
+	t.Helper()
+	events, err := store.SearchEvents(context.Background(), storage.SearchQuery{Limit: 100, Sort: storage.SortOldest})
+	require.NoError(t, err)
+	urls := make([]string, len(events))
+	for i, e := range events {
+		urls[i] = e.URL
+	}
+	return urls
+}
+
+func TestSync_TwoDevicesConverge(t *testing.T) {
+	laptop, cleanupA := testStore(t)
+	defer cleanupA()
+	desktop, cleanupB := testStore(t)
+	defer cleanupB()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, laptop.AddEventWithContent(ctx, &storage.Event{URL: "https://go.dev/doc", Title: "Go docs", Source: "extension", Timestamp: base}, "go body"))
+	require.NoError(t, laptop.AddEvent(ctx, &storage.Event{URL: "https://www.example.com/shared/", Title: "Shared", Source: "extension", Timestamp: base.Add(time.Hour)}))
+	// The same visit, captured on both machines with a slightly different URL.
+	require.NoError(t, desktop.AddEvent(ctx, &storage.Event{URL: "https://example.com/shared?utm_source=x", Title: "Shared", Source: "extension", Timestamp: base.Add(time.Hour)}))
+	require.NoError(t, desktop.AddEvent(ctx, &storage.Event{URL: "https://sqlite.org/wal.html", Title: "WAL", Source: "manual", Timestamp: base.Add(2 * time.Hour)}))
+
+	res := runSync(t, laptop, dir)
+	assert.Equal(t, 2, res.Pushed)
+	assert.Equal(t, 0, res.Peers)
+
+	res = runSync(t, desktop, dir)
+	assert.Equal(t, 2, res.Pushed)
+	assert.Equal(t, 1, res.Peers)
+	assert.Equal(t, 1, res.Pulled)
+	assert.Equal(t, 1, res.Existing)
+
+	res = runSync(t, laptop, dir)
+	assert.Equal(t, 0, res.Pushed, "imported events are not pushed back")
+	assert.Equal(t, 1, res.Pulled)
+	assert.Equal(t, 1, res.Existing)
+
+	assert.Len(t, allURLs(t, laptop), 3)
+	assert.Len(t, allURLs(t, desktop), 3)
+	assert.Contains(t, allURLs(t, laptop), "https://sqlite.org/wal.html")
+
+	// The body travels with the event.
+	events, err := desktop.SearchEvents(ctx, storage.SearchQuery{Domain: "go.dev", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].HasBody)
+	content, err := desktop.GetContent(ctx, events[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "go body", content.Body)
+	assert.True(t, events[0].Timestamp.Equal(base))
+
+	// Nothing new: both sides are no-ops.
+	res = runSync(t, desktop, dir)
+	assert.Equal(t, syncResult{Device: res.Device, Peers: 1}, res)
+	res = runSync(t, laptop, dir)
+	assert.Equal(t, syncResult{Device: res.Device, Peers: 1}, res)
+}
+
+func TestSync_ImportsWithoutFolding(t *testing.T) {
+	laptop, cleanupA := testStore(t)
+	defer cleanupA()
+	desktop, cleanupB := testStore(t)
+	defer cleanupB()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Within the dedupe window, the desktop's capture of the same body
+	// under another URL would fold into the laptop's.
+	laptop.SetDedupeWindow(time.Hour)
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	local := &storage.Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "extension", Timestamp: base, ContentHash: "h1"}
+	require.NoError(t, laptop.AddEventWithContent(ctx, local, "posts"))
+	require.NoError(t, desktop.AddEventWithContent(ctx, &storage.Event{URL: "https://mirror.example.com/blog", Title: "Mirror", Source: "extension", Timestamp: base.Add(time.Minute), ContentHash: "h1"}, "posts"))
+
+	runSync(t, desktop, dir)
+	res := runSync(t, laptop, dir)
+	assert.Equal(t, 1, res.Pulled)
+	assert.Equal(t, []string{"https://go.dev/blog", "https://mirror.example.com/blog"}, allURLs(t, laptop))
+	got, err := laptop.GetEvent(ctx, local.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Visits)
+
+	// The local event is still the laptop's to push.
+	res = runSync(t, laptop, dir)
+	assert.Equal(t, 0, res.Pulled)
+	res = runSync(t, desktop, dir)
+	assert.Equal(t, 1, res.Pulled)
+	assert.Len(t, allURLs(t, desktop), 2)
+}
+
+func TestSync_HumanOutputAndMissingDir(t *testing.T) {
+	store, cleanup := testStore(t)
+	defer cleanup()
+	seedDigestEvents(t, store, time.Now())
+
+	cmd := &SyncCommand{Dir: filepath.Join(t.TempDir(), "shared"), globals: &GlobalFlags{}}
+	output := captureOutput(t, func() {
+		require.NoError(t, cmd.executeWithStore(store, time.Now()))
+	})
+	assert.Equal(t, "Pushed 4 events, pulled 0 new from 0 other devices (0 already present)\n", output)
+
+	files, err := os.ReadDir(cmd.Dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "one folder for this device")
+
+	err = (&SyncCommand{globals: &GlobalFlags{}}).executeWithStore(store, time.Now())
+	assert.EqualError(t, err, "no sync folder (set --dir or sync.dir)")
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"testing"
@@ -9,6 +10,22 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// TestMain gives the tests a home directory of their own, so commands run
+// without --config or --db-path (and the log every command opens next to
+// its database) never touch the real ~/.config/fabric/chronicle.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "chronicle-home-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+	os.Unsetenv("CHRONICLE_PROFILE")
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
 // captureOutput captures stdout during fn execution and returns it as a string.
 func captureOutput(t *testing.T, fn func()) string {
 	t.Helper()
@@ -26,3 +43,25 @@ func captureOutput(t *testing.T, fn func()) string {
 	_, _ = io.Copy(&buf, r)
 	return buf.String()
 }
+
+// captureStderr captures stderr during fn execution and returns it as a string.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stderr
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stderr = w
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	fn()
+
+	w.Close()
+	os.Stderr = old
+	return <-done
+}
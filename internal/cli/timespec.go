@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeSpecHelp lists the forms parseTimeSpec accepts, for error messages.
+const timeSpecHelp = "use a duration like 7d or 24h, a date like 2025-01-01 or 2025-02-01T12:00, or a phrase like yesterday, last week, monday, tuesday 3pm, or 2 weeks ago"
+
+// dateLayout is the date-only form of an absolute time.
+const dateLayout = "2006-01-02"
+
+// localLayouts are the absolute forms without a UTC offset, read in now's
+// location. RFC 3339 times with an offset are accepted as well.
+var localLayouts = []string{
+	dateLayout,
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+}
+
+// parseTimeSpec resolves a --since/--until value to an absolute time
+// relative to now. It accepts a date or date and time (see localLayouts),
+// an RFC 3339 timestamp, a duration (see parseDuration) meaning that long
+// ago, or a phrase: now, today, yesterday, this/last week, month or
+// year, a weekday name (the most recent one, today included), last
+// <weekday> (today excluded), or "N <unit>s ago" (also "last N <unit>s").
+// Calendar phrases resolve to the start of the day, week (Monday), month
+// or year in now's location. A phrase may add a time of day (see
+// parseClock), as in "tuesday 3pm", "yesterday at 14:30" or "noon on
+// friday"; a time alone means today.
+func parseTimeSpec(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, ok := parseAbsoluteTime(s, now.Location()); ok {
+		return t, nil
+	}
+	s = strings.ToLower(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if isDurationSpec(s) {
+		d, err := parseDuration(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+
+	words := strings.Fields(s)
+	if hour, min, rest, ok := splitClock(words); ok {
+		day := now
+		if len(rest) > 0 {
+			var err error
+			if day, err = parseTimeSpec(strings.Join(rest, " "), now); err != nil {
+				return time.Time{}, err
+			}
+		}
+		y, m, d := day.Date()
+		return time.Date(y, m, d, hour, min, 0, 0, now.Location()), nil
+	}
+
+	today := startOfDay(now)
+	switch len(words) {
+	case 1:
+		switch words[0] {
+		case "now":
+			return now, nil
+		case "today":
+			return today, nil
+		case "yesterday":
+			return today.AddDate(0, 0, -1), nil
+		}
+		if wd, ok := parseWeekday(words[0]); ok {
+			return today.AddDate(0, 0, -daysSince(today.Weekday(), wd)), nil
+		}
+	case 2:
+		if words[0] != "this" && words[0] != "last" {
+			break
+		}
+		back := 0
+		if words[0] == "last" {
+			back = 1
+		}
+		switch words[1] {
+		case "week":
+			monday := today.AddDate(0, 0, -daysSince(today.Weekday(), time.Monday))
+			return monday.AddDate(0, 0, -7*back), nil
+		case "month":
+			return time.Date(now.Year(), now.Month()-time.Month(back), 1, 0, 0, 0, 0, now.Location()), nil
+		case "year":
+			return time.Date(now.Year()-back, time.January, 1, 0, 0, 0, 0, now.Location()), nil
+		}
+		if wd, ok := parseWeekday(words[1]); ok && back == 1 {
+			days := daysSince(today.Weekday(), wd)
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, -days), nil
+		}
+	case 3:
+		if words[2] == "ago" {
+			return parseAgo(words[0], words[1], now)
+		}
+		if words[0] == "last" {
+			return parseAgo(words[1], words[2], now)
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time (%s)", timeSpecHelp)
+}
+
+// splitClock finds a time of day at the end of words ("tuesday 3pm",
+// "tuesday at 3pm") or at the start ("3pm tuesday", "3pm on tuesday") and
+// returns it with the remaining words.
+func splitClock(words []string) (hour, min int, rest []string, ok bool) {
+	n := len(words)
+	if n == 0 {
+		return 0, 0, nil, false
+	}
+	// "3 pm" is one time.
+	if n >= 2 && (words[n-1] == "am" || words[n-1] == "pm") {
+		words = append(words[:n-2:n-2], words[n-2]+words[n-1])
+		n--
+	}
+	if hour, min, ok = parseClock(words[n-1]); ok {
+		rest = words[:n-1]
+		if len(rest) > 0 && rest[len(rest)-1] == "at" {
+			rest = rest[:len(rest)-1]
+		}
+		return hour, min, rest, true
+	}
+	if hour, min, ok = parseClock(words[0]); ok {
+		rest = words[1:]
+		if len(rest) > 0 && rest[0] == "on" {
+			rest = rest[1:]
+		}
+		return hour, min, rest, true
+	}
+	return 0, 0, nil, false
+}
+
+// parseClock parses a time of day: noon, midnight, 3pm, 3:30pm, or 15:04.
+func parseClock(s string) (hour, min int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	for _, layout := range []string{"3pm", "3:04pm", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour(), t.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
+
+// parseAbsoluteTime parses s as an RFC 3339 timestamp or one of
+// localLayouts in loc.
+func parseAbsoluteTime(s string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isDateSpec reports whether s is a date without a time of day.
+func isDateSpec(s string) bool {
+	_, err := time.Parse(dateLayout, strings.TrimSpace(s))
+	return err == nil
+}
+
+// isDurationSpec reports whether s is a duration such as 7d: digits and a
+// unit letter.
+func isDurationSpec(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return false
+	}
+	for _, r := range s[:len(s)-1] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	unit := s[len(s)-1]
+	return unit >= 'a' && unit <= 'z' || unit >= 'A' && unit <= 'Z'
+}
+
+// parseAgo resolves "<count> <unit> ago", where count is a number, "a" or
+// "an".
+func parseAgo(count, unit string, now time.Time) (time.Time, error) {
+	n := 1
+	if count != "a" && count != "an" {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid count %q", count)
+		}
+	}
+	switch strings.TrimSuffix(unit, "s") {
+	case "second", "sec":
+		return now.Add(-time.Duration(n) * time.Second), nil
+	case "minute", "min":
+		return now.Add(-time.Duration(n) * time.Minute), nil
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case "day":
+		return now.AddDate(0, 0, -n), nil
+	case "week":
+		return now.AddDate(0, 0, -7*n), nil
+	case "month":
+		return now.AddDate(0, -n, 0), nil
+	case "year":
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown unit %q (use seconds, minutes, hours, days, weeks, months, or years)", unit)
+	}
+}
+
+// parseWeekday parses a full or three-letter weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// daysSince returns how many days back from "from" the most recent "to"
+// falls, 0 when they are the same day.
+func daysSince(from, to time.Weekday) int {
+	return (int(from) - int(to) + 7) % 7
+}
+
+// startOfDay returns midnight at the start of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// applyDayFilter rewrites since and until to cover the single local day
+// chosen by --today or --on (any parseTimeSpec value, e.g. 2025-03-01 or
+// yesterday), replacing whatever bounds they held. It does nothing when
+// neither flag is given.
+func applyDayFilter(today bool, on string, since, until *string, now time.Time) error {
+	if today && on != "" {
+		return fmt.Errorf("--today and --on cannot be combined")
+	}
+	if today {
+		on = "today"
+	}
+	if on == "" {
+		return nil
+	}
+	t, err := parseTimeSpec(on, now)
+	if err != nil {
+		return fmt.Errorf("invalid --on value %q: %w", on, err)
+	}
+	day := startOfDay(t.In(now.Location())).Format(dateLayout)
+	*since, *until = day, day
+	return nil
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeSpec(t *testing.T) {
+	// Wednesday afternoon.
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	cases := map[string]time.Time{
+		"7d":             now.Add(-7 * 24 * time.Hour),
+		"24h":            now.Add(-24 * time.Hour),
+		"now":            now,
+		"today":          day(2026, 3, 18),
+		"yesterday":      day(2026, 3, 17),
+		" Yesterday ":    day(2026, 3, 17),
+		"this week":      day(2026, 3, 16),
+		"last week":      day(2026, 3, 9),
+		"this month":     day(2026, 3, 1),
+		"last month":     day(2026, 2, 1),
+		"this year":      day(2026, 1, 1),
+		"last year":      day(2025, 1, 1),
+		"monday":         day(2026, 3, 16),
+		"mon":            day(2026, 3, 16),
+		"wednesday":      day(2026, 3, 18),
+		"last wed":       day(2026, 3, 11),
+		"thursday":       day(2026, 3, 12),
+		"last sunday":    day(2026, 3, 15),
+		"2 weeks ago":    day(2026, 3, 4).Add(15*time.Hour + 30*time.Minute),
+		"an hour ago":    now.Add(-time.Hour),
+		"3 months ago":   day(2025, 12, 18).Add(15*time.Hour + 30*time.Minute),
+		"1 year ago":     day(2025, 3, 18).Add(15*time.Hour + 30*time.Minute),
+		"last 3 days":    day(2026, 3, 15).Add(15*time.Hour + 30*time.Minute),
+		"90 minutes ago": now.Add(-90 * time.Minute),
+	}
+	for in, want := range cases {
+		got, err := parseTimeSpec(in, now)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, want, got, in)
+		}
+	}
+}
+
+func TestParseTimeSpec_UsesNowsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	now := time.Date(2026, 3, 18, 1, 0, 0, 0, loc) // 06:00 UTC
+	got, err := parseTimeSpec("today", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 18, 0, 0, 0, 0, loc), got)
+}
+
+func TestParseTimeSpec_Invalid(t *testing.T) {
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+	for _, in := range []string{"", "7x", "someday", "last fortnight", "two weeks ago", "3 eons ago", "this monday"} {
+		_, err := parseTimeSpec(in, now)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestResolveTimeRange_Phrases(t *testing.T) {
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+	since, until, err := resolveTimeRange("last week", "yesterday", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC), until)
+
+	_, _, err = resolveTimeRange("someday", "", now)
+	assert.EqualError(t, err, `invalid --since value "someday": unrecognized time (`+timeSpecHelp+`)`)
+}
+
+func TestParseTimeSpec_Absolute(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*3600)
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, loc)
+
+	cases := map[string]time.Time{
+		"2025-01-01":                time.Date(2025, 1, 1, 0, 0, 0, 0, loc),
+		"2025-02-01T12:00":          time.Date(2025, 2, 1, 12, 0, 0, 0, loc),
+		"2025-02-01 12:00:30":       time.Date(2025, 2, 1, 12, 0, 30, 0, loc),
+		"2025-02-01T12:00:00Z":      time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		"2025-02-01T12:00:00-05:00": time.Date(2025, 2, 1, 17, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, err := parseTimeSpec(in, now)
+		if assert.NoError(t, err, in) {
+			assert.True(t, want.Equal(got), "%s: got %v, want %v", in, got, want)
+		}
+	}
+
+	_, err := parseTimeSpec("2025-13-01", now)
+	assert.Error(t, err)
+}
+
+func TestResolveTimeRange_Until(t *testing.T) {
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+
+	// A date includes the whole day.
+	_, until, err := resolveTimeRange("", "2025-02-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-01T23:59:59Z", until.Format(time.RFC3339))
+
+	// A time is taken as given.
+	_, until, err = resolveTimeRange("", "2025-02-01T12:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC), until)
+
+	// A duration counts forward from --since.
+	since, until, err := resolveTimeRange("2025-01-01", "7d", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), until)
+
+	// ... so it needs one.
+	_, _, err = resolveTimeRange("", "7d", now)
+	assert.ErrorContains(t, err, "a duration is measured from --since")
+
+	// Phrases still mean a point in the past.
+	_, until, err = resolveTimeRange("", "7 days ago", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7), until)
+
+	_, _, err = resolveTimeRange("2025-02-01", "2025-01-01", now)
+	assert.EqualError(t, err, `--until "2025-01-01" is before --since "2025-02-01"`)
+}
+
+func TestApplyDayFilter(t *testing.T) {
+	// 21:30 on March 18 in UTC-5 is already March 19 in UTC.
+	loc := time.FixedZone("UTC-5", -5*3600)
+	now := time.Date(2026, 3, 18, 21, 30, 0, 0, loc)
+
+	since, until := "30d", ""
+	require.NoError(t, applyDayFilter(true, "", &since, &until, now))
+	assert.Equal(t, "2026-03-18", since)
+	assert.Equal(t, "2026-03-18", until)
+	from, to, err := resolveTimeRange(since, until, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-18T05:00:00Z", from.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2026-03-19T04:59:59Z", to.UTC().Format(time.RFC3339))
+
+	since, until = "30d", "1d"
+	require.NoError(t, applyDayFilter(false, "2025-03-01", &since, &until, now))
+	assert.Equal(t, "2025-03-01", since)
+	assert.Equal(t, "2025-03-01", until)
+
+	require.NoError(t, applyDayFilter(false, "yesterday", &since, &until, now))
+	assert.Equal(t, "2026-03-17", since)
+
+	since, until = "30d", ""
+	require.NoError(t, applyDayFilter(false, "", &since, &until, now))
+	assert.Equal(t, "30d", since, "no day filter leaves the bounds alone")
+
+	assert.EqualError(t, applyDayFilter(true, "2025-03-01", &since, &until, now), "--today and --on cannot be combined")
+	assert.ErrorContains(t, applyDayFilter(false, "someday", &since, &until, now), `invalid --on value "someday"`)
+}
+
+func TestParseTimeSpec_TimeOfDay(t *testing.T) {
+	// Wednesday afternoon.
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+	at := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, time.UTC) }
+
+	cases := map[string]time.Time{
+		"3pm":                 at(18, 15, 0),
+		"tuesday 3pm":         at(17, 15, 0),
+		"Tuesday 3 PM":        at(17, 15, 0),
+		"tuesday at 3:30pm":   at(17, 15, 30),
+		"yesterday at 14:30":  at(17, 14, 30),
+		"noon on monday":      at(16, 12, 0),
+		"9am yesterday":       at(17, 9, 0),
+		"last thursday 12pm":  at(12, 12, 0),
+		"2026-03-01 3pm":      time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC),
+		"midnight":            at(18, 0, 0),
+		"2 days ago at 08:15": at(16, 8, 15),
+	}
+	for in, want := range cases {
+		got, err := parseTimeSpec(in, now)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, want, got, in)
+		}
+	}
+
+	for _, in := range []string{"someday 3pm", "25:00", "tuesday 13pm"} {
+		_, err := parseTimeSpec(in, now)
+		assert.Error(t, err, in)
+	}
+}
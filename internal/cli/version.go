@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Build metadata, set at link time:
+//
+//	-X github.com/runnerr0/chronicle/internal/cli.Commit=<sha>
+//	-X github.com/runnerr0/chronicle/internal/cli.BuildDate=<date>
+//
+// When unset, Commit and BuildDate fall back to the VCS info embedded by
+// the Go toolchain.
+var (
+	Commit    = ""
+	BuildDate = ""
+)
+
+// versionJSON is the JSON output structure for the version command.
+type versionJSON struct {
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	BuildDate     string `json:"build_date"`
+	GoVersion     string `json:"go_version"`
+	Platform      string `json:"platform"`
+	SQLiteVersion string `json:"sqlite_version"`
+	SchemaVersion int    `json:"schema_version"`
+	SchemaLatest  int    `json:"schema_latest"`
+	ConfigPath    string `json:"config_path"`
+	DatabasePath  string `json:"database_path"`
+}
+
+// Execute implements the go-flags Commander interface for VersionCommand.
+func (c *VersionCommand) Execute(args []string) error {
+	info := c.collect()
+
+	if c.globals != nil && c.globals.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	fmt.Printf("chronicle %s\n", info.Version)
+	fmt.Printf("  Commit:    %s\n", info.Commit)
+	fmt.Printf("  Built:     %s\n", info.BuildDate)
+	fmt.Printf("  Go:        %s (%s)\n", info.GoVersion, info.Platform)
+	fmt.Printf("  SQLite:    %s\n", info.SQLiteVersion)
+	if info.SchemaVersion == 0 {
+		fmt.Printf("  Schema:    none (latest %d)\n", info.SchemaLatest)
+	} else {
+		fmt.Printf("  Schema:    %d (latest %d)\n", info.SchemaVersion, info.SchemaLatest)
+	}
+	fmt.Printf("  Config:    %s\n", info.ConfigPath)
+	fmt.Printf("  Database:  %s\n", info.DatabasePath)
+	return nil
+}
+
+// collect gathers version information. It never creates or migrates the
+// database: a missing database reports schema version 0.
+func (c *VersionCommand) collect() versionJSON {
+	sqliteVersion, _, _ := sqlite3.Version()
+
+	info := versionJSON{
+		Version:       c.version,
+		Commit:        Commit,
+		BuildDate:     BuildDate,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		SQLiteVersion: sqliteVersion,
+		SchemaLatest:  storage.NewMigrationRunner(nil).LatestVersion(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+
+	if path, err := configFilePath(c.globals); err == nil {
+		info.ConfigPath = path
+	}
+
+	globals := c.globals
+	if globals == nil {
+		globals = &GlobalFlags{}
+	}
+	db, dbPath, err := openExistingDB(globals)
+	info.DatabasePath = dbPath
+	if err != nil {
+		return info
+	}
+	defer db.Close()
+	if v, err := storage.SchemaVersion(db); err == nil {
+		info.SchemaVersion = v
+	}
+
+	return info
+}
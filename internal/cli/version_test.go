@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestVersionCommand_JSON(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "chronicle.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+	db.Close()
+
+	output, err := captureOpenOutput(t, []string{"--json", "--config", "/tmp/chronicle-test.yaml", "--db-path", dbPath, "version"})
+	require.NoError(t, err)
+
+	var info versionJSON
+	require.NoError(t, json.Unmarshal([]byte(output), &info))
+	assert.Equal(t, "test", info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.SQLiteVersion)
+	assert.NotEmpty(t, info.Commit)
+	assert.Equal(t, 19, info.SchemaVersion)
+	assert.Equal(t, info.SchemaLatest, info.SchemaVersion)
+	assert.Equal(t, "/tmp/chronicle-test.yaml", info.ConfigPath)
+	assert.Equal(t, dbPath, info.DatabasePath)
+}
+
+func TestVersionCommand_MissingDatabaseIsNotCreated(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "missing.db")
+
+	output, err := captureOpenOutput(t, []string{"--db-path", dbPath, "version"})
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "chronicle test")
+	assert.Contains(t, output, "Schema:    none")
+	assert.NoFileExists(t, dbPath)
+}
@@ -0,0 +1,79 @@
+// Package clipboard reads the system clipboard using the platform's own
+// tool: pbpaste on macOS, wl-paste, xclip or xsel on Linux and the BSDs,
+// and PowerShell on Windows.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// run executes the clipboard command and returns its output; tests
+// replace it.
+var run = func(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			if msg := strings.TrimSpace(string(exit.Stderr)); msg != "" {
+				return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+			}
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// lookPath reports whether a tool is installed; tests replace it.
+var lookPath = exec.LookPath
+
+// Available reports why the clipboard cannot be read on this system, or
+// nil if it can.
+func Available() error {
+	_, _, err := command(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "")
+	return err
+}
+
+// Read returns the text on the clipboard.
+func Read() (string, error) {
+	name, args, err := command(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "")
+	if err != nil {
+		return "", err
+	}
+	return run(name, args...)
+}
+
+// command returns the command that prints the clipboard on goos. On
+// Linux and the BSDs it is the first installed of wl-paste (under
+// Wayland), xclip and xsel.
+func command(goos string, wayland bool) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "pbpaste", nil, nil
+	case "windows":
+		return "powershell", []string{"-NoProfile", "-Command", "Get-Clipboard -Raw"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tools := [][]string{
+			{"xclip", "-selection", "clipboard", "-out"},
+			{"xsel", "--clipboard", "--output"},
+		}
+		if wayland {
+			tools = append([][]string{{"wl-paste", "--no-newline"}}, tools...)
+		}
+		for _, t := range tools {
+			if _, err := lookPath(t[0]); err == nil {
+				return t[0], t[1:], nil
+			}
+		}
+		if wayland {
+			return "", nil, fmt.Errorf("reading the clipboard needs wl-paste, xclip or xsel")
+		}
+		return "", nil, fmt.Errorf("reading the clipboard needs xclip or xsel")
+	default:
+		return "", nil, fmt.Errorf("reading the clipboard is not supported on %s", goos)
+	}
+}
@@ -0,0 +1,84 @@
+package clipboard
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installed replaces lookPath so that only tools are found.
+func installed(t *testing.T, tools ...string) {
+	t.Helper()
+	old := lookPath
+	t.Cleanup(func() { lookPath = old })
+	lookPath = func(name string) (string, error) {
+		for _, tool := range tools {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("darwin", false)
+	require.NoError(t, err)
+	assert.Equal(t, "pbpaste", name)
+	assert.Empty(t, args)
+
+	installed(t, "xclip", "xsel", "wl-paste")
+	name, args, err = command("linux", false)
+	require.NoError(t, err)
+	assert.Equal(t, "xclip", name)
+	assert.Equal(t, []string{"-selection", "clipboard", "-out"}, args)
+
+	name, args, err = command("linux", true)
+	require.NoError(t, err)
+	assert.Equal(t, "wl-paste", name)
+	assert.Equal(t, []string{"--no-newline"}, args)
+
+	installed(t, "xsel")
+	name, _, err = command("freebsd", true)
+	require.NoError(t, err)
+	assert.Equal(t, "xsel", name)
+
+	installed(t)
+	_, _, err = command("linux", false)
+	assert.EqualError(t, err, "reading the clipboard needs xclip or xsel")
+
+	name, _, err = command("windows", false)
+	require.NoError(t, err)
+	assert.Equal(t, "powershell", name)
+
+	_, _, err = command("plan9", false)
+	assert.Error(t, err)
+}
+
+func TestRead(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("clipboard command lookup differs on " + runtime.GOOS)
+	}
+	installed(t, "xclip", "wl-paste")
+	var got string
+	old := run
+	t.Cleanup(func() { run = old })
+	run = func(name string, args ...string) (string, error) {
+		got = name
+		return "https://go.dev/doc/\n", nil
+	}
+
+	require.NoError(t, Available())
+	text, err := Read()
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/doc/\n", text)
+	assert.NotEmpty(t, got)
+
+	run = func(string, ...string) (string, error) { return "", errors.New("no display") }
+	_, err = Read()
+	assert.EqualError(t, err, "no display")
+}
@@ -13,13 +13,24 @@ const DefaultConfigPath = "~/.config/fabric/chronicle/config.yaml"
 
 // Config holds all Chronicle configuration.
 type Config struct {
-	Retention  RetentionConfig  `yaml:"retention"`
-	Capture    CaptureConfig    `yaml:"capture"`
-	Embeddings EmbeddingsConfig `yaml:"embeddings"`
-	Storage    StorageConfig    `yaml:"storage"`
-	Daemon     DaemonConfig     `yaml:"daemon"`
-	Logging    LoggingConfig    `yaml:"logging"`
-	Fabric     FabricConfig     `yaml:"fabric"`
+	Retention     RetentionConfig     `yaml:"retention"`
+	Capture       CaptureConfig       `yaml:"capture"`
+	Embeddings    EmbeddingsConfig    `yaml:"embeddings"`
+	Storage       StorageConfig       `yaml:"storage"`
+	Daemon        DaemonConfig        `yaml:"daemon"`
+	API           APIConfig           `yaml:"api"`
+	GRPC          GRPCConfig          `yaml:"grpc"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Fabric        FabricConfig        `yaml:"fabric"`
+	Summaries     SummariesConfig     `yaml:"summaries"`
+	Output        OutputConfig        `yaml:"output"`
+	Search        SearchConfig        `yaml:"search"`
+	Webhooks      []WebhookConfig     `yaml:"webhooks"`
+	Sync          SyncConfig          `yaml:"sync"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Sources       []SourceConfig      `yaml:"sources"`
+	Hooks         HooksConfig         `yaml:"hooks"`
+	Jobs          []JobConfig         `yaml:"jobs"`
 }
 
 type RetentionConfig struct {
@@ -28,13 +39,15 @@ type RetentionConfig struct {
 }
 
 type CaptureConfig struct {
-	Mode                  string   `yaml:"mode"`
+	Mode                  string   `yaml:"mode"` // metadata_only (bodies only from body_capture_domains) or full
 	ExcludeIncognito      bool     `yaml:"exclude_incognito"`
-	AllowlistDomains      []string `yaml:"allowlist_domains"`
-	DenylistDomains       []string `yaml:"denylist_domains"`
-	DenylistRegex         []string `yaml:"denylist_regex"`
-	BodyCaptureDomains    []string `yaml:"body_capture_domains"`
-	DedupeIntervalSeconds int      `yaml:"dedupe_interval_seconds"`
+	AllowlistDomains      []string `yaml:"allowlist_domains"`       // when set, only these domains and their subdomains are captured
+	DenylistDomains       []string `yaml:"denylist_domains"`        // never captured, with their subdomains
+	DenylistRegex         []string `yaml:"denylist_regex"`          // never captured: URLs matching any
+	BodyCaptureDomains    []string `yaml:"body_capture_domains"`    // keep bodies in metadata_only mode
+	DedupeIntervalSeconds int      `yaml:"dedupe_interval_seconds"` // repeat captures this close count as visits; 0 disables
+	MaxBodyBytes          int      `yaml:"max_body_bytes"`          // 0 means no limit
+	BodyOverflow          string   `yaml:"body_overflow"`           // truncate or reject
 }
 
 type EmbeddingsConfig struct {
@@ -49,9 +62,30 @@ type EmbeddingsConfig struct {
 type StorageConfig struct {
 	Path              string `yaml:"path"`
 	SQLiteFile        string `yaml:"sqlite_file"`
-	VectorStore       string `yaml:"vector_store"`
-	VectorDir         string `yaml:"vector_dir"`
+	VectorStore       string `yaml:"vector_store"` // sqlite (in the database, the default) or flat (files in vector_dir); lancedb is not supported yet
+	VectorDir         string `yaml:"vector_dir"`   // relative to the database's directory
 	SQLiteJournalMode string `yaml:"sqlite_journal_mode"`
+
+	// Per-connection SQLite tuning; 0 or "" keeps SQLite's default.
+	SQLiteCacheSizeMB int    `yaml:"sqlite_cache_size_mb"`
+	SQLiteMmapSizeMB  int    `yaml:"sqlite_mmap_size_mb"`
+	SQLiteSynchronous string `yaml:"sqlite_synchronous"` // off, normal, full, extra
+	SQLiteTempStore   string `yaml:"sqlite_temp_store"`  // default, file, memory
+
+	// Off-machine snapshots taken by chronicle replicate.
+	ReplicaURL             string `yaml:"replica_url"`              // directory, file:// or s3://bucket/prefix
+	ReplicaIntervalMinutes int    `yaml:"replica_interval_minutes"` // between snapshots
+	ReplicaRetain          int    `yaml:"replica_retain"`           // snapshots kept; 0 keeps all
+
+	// At-rest encryption of captured bodies; see chronicle migrate-encrypt.
+	Encryption EncryptionConfig `yaml:"encryption"`
+}
+
+type EncryptionConfig struct {
+	Enabled         bool   `yaml:"enabled"`          // encrypt the bodies of a new or unencrypted database
+	KeySource       string `yaml:"key_source"`       // passphrase (from passphrase_env) or keychain
+	PassphraseEnv   string `yaml:"passphrase_env"`   // environment variable holding the passphrase
+	KeychainService string `yaml:"keychain_service"` // keychain item holding the passphrase
 }
 
 type DaemonConfig struct {
@@ -61,24 +95,120 @@ type DaemonConfig struct {
 	MaxRequestSize int    `yaml:"max_request_size"`
 }
 
+type APIConfig struct {
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`
+	AuthToken string `yaml:"auth_token"`
+}
+
+// GRPCConfig configures chronicle grpc. gRPC requires HTTP/2, which is
+// served over TLS; without a certificate a self-signed one is generated.
+type GRPCConfig struct {
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`
+	AuthToken string `yaml:"auth_token"`
+	CertFile  string `yaml:"cert_file"` // PEM certificate; empty generates a self-signed one per run
+	KeyFile   string `yaml:"key_file"`  // PEM private key for cert_file
+}
+
 type LoggingConfig struct {
-	Level      string `yaml:"level"`
-	File       string `yaml:"file"`
+	Level      string `yaml:"level"`  // debug, info, warn, error
+	Format     string `yaml:"format"` // text or json
+	File       string `yaml:"file"`   // relative to the database directory; "stderr", or "" to disable
 	AuditLog   bool   `yaml:"audit_log"`
-	MaxSize    int    `yaml:"max_size"`
-	MaxBackups int    `yaml:"max_backups"`
+	MaxSize    int    `yaml:"max_size"`    // bytes before the log is rotated and gzipped; 0 never rotates
+	MaxBackups int    `yaml:"max_backups"` // rotated logs kept; 0 keeps all
 }
 
 type FabricConfig struct {
-	PatternsDir string `yaml:"patterns_dir"`
-	Binary      string `yaml:"binary"`
+	PatternsDir    string `yaml:"patterns_dir"`
+	Binary         string `yaml:"binary"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // per pattern run; 0 means no limit
+}
+
+// SummariesConfig configures the stored summaries chronicle summarize
+// --pending generates for captured bodies.
+type SummariesConfig struct {
+	Provider  string `yaml:"provider"`   // ollama or fabric
+	OllamaURL string `yaml:"ollama_url"` // ollama server
+	Model     string `yaml:"model"`      // ollama model
+	Pattern   string `yaml:"pattern"`    // fabric pattern
+}
+
+type OutputConfig struct {
+	Color string `yaml:"color"` // auto, always, never
+	Theme string `yaml:"theme"` // default, light, mono
+}
+
+// SearchConfig holds defaults for search flags that were not given.
+type SearchConfig struct {
+	DefaultLimit  int        `yaml:"default_limit"`
+	DefaultSince  string     `yaml:"default_since"`
+	DefaultOutput string     `yaml:"default_output"` // human, json, urls, alfred, raycast
+	DefaultSort   string     `yaml:"default_sort"`   // relevance, newest, oldest
+	RecordHistory bool       `yaml:"record_history"` // keep a local log of searches (off by default)
+	Synonyms      [][]string `yaml:"synonyms"`       // groups of interchangeable words, e.g. [js, javascript]
+}
+
+// WebhookConfig is an endpoint that captured events are POSTed to. Empty
+// filters match every event. Events carry no tags, so there is no tag
+// filter; sources narrows deliveries by where events came from instead.
+type WebhookConfig struct {
+	URL            string   `yaml:"url"`
+	Secret         string   `yaml:"secret"`          // signs payloads with HMAC-SHA256; optional
+	Domains        []string `yaml:"domains"`         // only these domains and their subdomains
+	Sources        []string `yaml:"sources"`         // only these sources (extension, manual, import)
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // per delivery; 0 means 5s
+	Template       string   `yaml:"template"`        // Go text/template over the payload fields; empty sends the JSON payload
+	ContentType    string   `yaml:"content_type"`    // of templated bodies; empty means application/json
+}
+
+// SyncConfig holds defaults for chronicle sync.
+type SyncConfig struct {
+	Dir string `yaml:"dir"` // shared folder exchanged between devices
+}
+
+// NotificationsConfig controls desktop notifications (notify-send on Linux,
+// osascript on macOS).
+type NotificationsConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Domains        []string `yaml:"domains"`         // notify when these domains or their subdomains are captured
+	PruneThreshold int64    `yaml:"prune_threshold"` // notify when a prune removes more events than this; 0 disables
+}
+
+// SourceConfig is an ingestion source run by `chronicle pull --source NAME`.
+type SourceConfig struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`    // adapter: rss or shell; defaults to name
+	Options map[string]string `yaml:"options"` // adapter settings, e.g. url for rss, path for shell
+}
+
+// HooksConfig lists executables run around each capture with the event as
+// JSON on stdin. Pre-capture hooks can veto the capture (non-zero exit) or
+// rewrite the event (JSON on stdout); post-capture hooks run once it is
+// stored.
+type HooksConfig struct {
+	PreCapture     []string `yaml:"pre_capture"`
+	PostCapture    []string `yaml:"post_capture"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // per hook run; 0 means 10s
+}
+
+// JobConfig schedules a maintenance job run by the daemon.
+type JobConfig struct {
+	Name     string            `yaml:"name"`     // defaults to the type
+	Type     string            `yaml:"type"`     // prune, backup, vacuum, embed or digest
+	Schedule string            `yaml:"schedule"` // cron expression such as "0 3 * * *", @daily, @weekly, or @every 6h
+	Options  map[string]string `yaml:"options"`  // digest: dir (required) and period (day or week)
 }
 
 // Load reads a YAML config file at path and merges it with defaults.
 // Returns an error if the file cannot be read or contains invalid YAML.
 func Load(path string) (*Config, error) {
-	cfg := DefaultConfig()
+	return loadOver(path, DefaultConfig())
+}
 
+// loadOver reads the YAML config file at path on top of base.
+func loadOver(path string, cfg *Config) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading config file: %w", err)
@@ -106,10 +236,43 @@ func expandPath(path string) (string, error) {
 	return path, nil
 }
 
+// DBPath returns the SQLite database file path: storage.path (with ~
+// expanded) joined with storage.sqlite_file.
+func (c *Config) DBPath() (string, error) {
+	dir, err := expandPath(c.Storage.Path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, c.Storage.SQLiteFile), nil
+}
+
+// VectorPath returns storage.vector_dir with ~ expanded, taking a relative
+// one to be under dbDir, the database's directory.
+func (c *Config) VectorPath(dbDir string) (string, error) {
+	dir, err := expandPath(c.Storage.VectorDir)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(dbDir, dir)
+	}
+	return dir, nil
+}
+
+// PatternsPath returns fabric.patterns_dir with ~ expanded.
+func (f FabricConfig) PatternsPath() (string, error) {
+	return expandPath(f.PatternsDir)
+}
+
+// DefaultPath returns DefaultConfigPath with ~ expanded.
+func DefaultPath() (string, error) {
+	return expandPath(DefaultConfigPath)
+}
+
 // LoadOrCreate loads the config from the default path. If the file does
 // not exist, it creates the directory structure and writes defaults.
 func LoadOrCreate() (*Config, error) {
-	path, err := expandPath(DefaultConfigPath)
+	path, err := DefaultPath()
 	if err != nil {
 		return nil, err
 	}
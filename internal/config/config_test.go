@@ -24,19 +24,37 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 16, cfg.Embeddings.BatchSize)
 	assert.Equal(t, "~/.config/fabric/chronicle", cfg.Storage.Path)
 	assert.Equal(t, "chronicle.db", cfg.Storage.SQLiteFile)
-	assert.Equal(t, "lancedb", cfg.Storage.VectorStore)
+	assert.Equal(t, "sqlite", cfg.Storage.VectorStore)
 	assert.Equal(t, "vectors", cfg.Storage.VectorDir)
 	assert.Equal(t, "wal", cfg.Storage.SQLiteJournalMode)
 	assert.Equal(t, "127.0.0.1", cfg.Daemon.Host)
 	assert.Equal(t, 8721, cfg.Daemon.Port)
 	assert.Equal(t, 10485760, cfg.Daemon.MaxRequestSize)
+	assert.Equal(t, "127.0.0.1", cfg.API.Host)
+	assert.Equal(t, 8722, cfg.API.Port)
+	assert.Empty(t, cfg.API.AuthToken)
 	assert.Equal(t, "info", cfg.Logging.Level)
 	assert.Equal(t, "chronicle.log", cfg.Logging.File)
 	assert.True(t, cfg.Logging.AuditLog)
 	assert.Equal(t, 10485760, cfg.Logging.MaxSize)
 	assert.Equal(t, 3, cfg.Logging.MaxBackups)
+	assert.Equal(t, "auto", cfg.Output.Color)
+	assert.Equal(t, "default", cfg.Output.Theme)
+	assert.Equal(t, 10, cfg.Search.DefaultLimit)
+	assert.Equal(t, "30d", cfg.Search.DefaultSince)
+	assert.Equal(t, "human", cfg.Search.DefaultOutput)
+	assert.Equal(t, "relevance", cfg.Search.DefaultSort)
+	assert.False(t, cfg.Search.RecordHistory)
 	assert.Equal(t, "~/.config/fabric/patterns", cfg.Fabric.PatternsDir)
 	assert.Empty(t, cfg.Fabric.Binary)
+	assert.Equal(t, 120, cfg.Fabric.TimeoutSeconds)
+	assert.Equal(t, "full", cfg.Storage.SQLiteSynchronous)
+	assert.Equal(t, 5242880, cfg.Capture.MaxBodyBytes)
+	assert.Equal(t, "truncate", cfg.Capture.BodyOverflow)
+	assert.Equal(t, "default", cfg.Storage.SQLiteTempStore)
+	assert.False(t, cfg.Storage.Encryption.Enabled)
+	assert.Equal(t, "passphrase", cfg.Storage.Encryption.KeySource)
+	assert.Equal(t, "CHRONICLE_PASSPHRASE", cfg.Storage.Encryption.PassphraseEnv)
 }
 
 func TestDefaultDenylistIsPopulated(t *testing.T) {
@@ -185,3 +203,48 @@ capture:
 	require.NoError(t, err)
 	assert.Equal(t, []string{"example.com", "secret.org"}, cfg.Capture.DenylistDomains)
 }
+
+func TestConfigDBPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	path, err := cfg.DBPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config/fabric/chronicle/chronicle.db"), path)
+
+	cfg.Storage.Path = "/data/chronicle"
+	cfg.Storage.SQLiteFile = "history.db"
+	path, err = cfg.DBPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/data/chronicle/history.db", path)
+}
+
+func TestVectorPath(t *testing.T) {
+	cfg := DefaultConfig()
+	dir, err := cfg.VectorPath("/data/chronicle")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/chronicle/vectors", dir)
+
+	cfg.Storage.VectorDir = "/var/lib/chronicle/vectors"
+	dir, err = cfg.VectorPath("/data/chronicle")
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/chronicle/vectors", dir)
+}
+
+func TestLoadSearchSynonyms(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("search:\n  synonyms:\n    - [js, javascript]\n    - [k8s, kubernetes]\n"), 0644))
+
+	cfg, err := Load(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"js", "javascript"}, {"k8s", "kubernetes"}}, cfg.Search.Synonyms)
+
+	leaves, err := Leaves(cfg)
+	require.NoError(t, err)
+	values := map[string]string{}
+	for _, kv := range leaves {
+		values[kv[0]] = kv[1]
+	}
+	assert.Equal(t, "[[js, javascript], [k8s, kubernetes]]", values["search.synonyms"])
+}
@@ -15,6 +15,8 @@ func DefaultConfig() *Config {
 			DenylistRegex:         []string{},
 			BodyCaptureDomains:    []string{},
 			DedupeIntervalSeconds: 300,
+			MaxBodyBytes:          5242880,
+			BodyOverflow:          "truncate",
 		},
 		Embeddings: EmbeddingsConfig{
 			Enabled:     false,
@@ -27,9 +29,24 @@ func DefaultConfig() *Config {
 		Storage: StorageConfig{
 			Path:              "~/.config/fabric/chronicle",
 			SQLiteFile:        "chronicle.db",
-			VectorStore:       "lancedb",
+			VectorStore:       "sqlite",
 			VectorDir:         "vectors",
 			SQLiteJournalMode: "wal",
+			SQLiteCacheSizeMB: 0,
+			SQLiteMmapSizeMB:  0,
+			SQLiteSynchronous: "full",
+			SQLiteTempStore:   "default",
+
+			ReplicaURL:             "",
+			ReplicaIntervalMinutes: 10,
+			ReplicaRetain:          144,
+
+			Encryption: EncryptionConfig{
+				Enabled:         false,
+				KeySource:       "passphrase",
+				PassphraseEnv:   "CHRONICLE_PASSPHRASE",
+				KeychainService: "chronicle",
+			},
 		},
 		Daemon: DaemonConfig{
 			Host:           "127.0.0.1",
@@ -37,16 +54,62 @@ func DefaultConfig() *Config {
 			AuthToken:      "",
 			MaxRequestSize: 10485760,
 		},
+		API: APIConfig{
+			Host:      "127.0.0.1",
+			Port:      8722,
+			AuthToken: "",
+		},
+		GRPC: GRPCConfig{
+			Host:      "127.0.0.1",
+			Port:      8723,
+			AuthToken: "",
+			CertFile:  "",
+			KeyFile:   "",
+		},
 		Logging: LoggingConfig{
 			Level:      "info",
+			Format:     "text",
 			File:       "chronicle.log",
 			AuditLog:   true,
 			MaxSize:    10485760,
 			MaxBackups: 3,
 		},
 		Fabric: FabricConfig{
-			PatternsDir: "~/.config/fabric/patterns",
-			Binary:      "",
+			PatternsDir:    "~/.config/fabric/patterns",
+			Binary:         "",
+			TimeoutSeconds: 120,
+		},
+		Summaries: SummariesConfig{
+			Provider:  "ollama",
+			OllamaURL: "http://localhost:11434",
+			Model:     "llama3.2",
+			Pattern:   "summarize_micro",
+		},
+		Output: OutputConfig{
+			Color: "auto",
+			Theme: "default",
+		},
+		Search: SearchConfig{
+			DefaultLimit:  10,
+			DefaultSince:  "30d",
+			DefaultOutput: "human",
+			DefaultSort:   "relevance",
+			RecordHistory: false,
+		},
+		Webhooks: []WebhookConfig{},
+		Sync: SyncConfig{
+			Dir: "",
+		},
+		Notifications: NotificationsConfig{
+			Enabled:        false,
+			Domains:        []string{},
+			PruneThreshold: 1000,
+		},
+		Sources: []SourceConfig{},
+		Hooks: HooksConfig{
+			PreCapture:  []string{},
+			PostCapture: []string{},
 		},
+		Jobs: []JobConfig{},
 	}
 }
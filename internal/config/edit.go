@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set updates the dotted key (e.g. "retention.days") in the YAML config
+// file at path. The file is edited as a yaml.Node tree, so comments, key
+// order, and unrelated values are preserved (blank lines are not; yaml.v3
+// does not keep them). A missing file is created
+// from defaults first. List values are given comma-separated.
+func Set(path, key, value string) error {
+	field, err := lookupField(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if _, err := LoadOrCreateAt(path); err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a YAML mapping", path)
+	}
+
+	newValue, err := valueNode(field, key, value)
+	if err != nil {
+		return err
+	}
+	setPath(doc.Content[0], strings.Split(key, "."), newValue)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	enc.Close()
+
+	// Reject edits that would leave the file unloadable.
+	if err := yaml.Unmarshal(buf.Bytes(), DefaultConfig()); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
+}
+
+// lookupField resolves a dotted key against the yaml tags of Config and
+// returns the type of the leaf field.
+func lookupField(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+		found := false
+		for j := 0; j < t.NumField(); j++ {
+			f := t.Field(j)
+			if strings.Split(f.Tag.Get("yaml"), ",")[0] == part {
+				t = f.Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+		if i == len(parts)-1 && t.Kind() == reflect.Struct {
+			return nil, fmt.Errorf("config key %q is a section; set one of its fields", key)
+		}
+	}
+	return t, nil
+}
+
+// valueNode builds the YAML node for value, validated against field's type.
+func valueNode(field reflect.Type, key, value string) (*yaml.Node, error) {
+	switch field.Kind() {
+	case reflect.Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("%s must be an integer, got %q", key, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}, nil
+	case reflect.Slice:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return seq, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+}
+
+// setPath sets path under mapping m to value, creating intermediate
+// mappings as needed. An existing node keeps its comments.
+func setPath(m *yaml.Node, path []string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i], m.Content[i+1]
+		if k.Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			value.HeadComment = v.HeadComment
+			value.LineComment = v.LineComment
+			value.FootComment = v.FootComment
+			m.Content[i+1] = value
+			return
+		}
+		if v.Kind != yaml.MappingNode {
+			v = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: v.LineComment}
+			m.Content[i+1] = v
+		}
+		setPath(v, path[1:], value)
+		return
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		m.Content = append(m.Content, keyNode, value)
+		return
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, keyNode, child)
+	setPath(child, path[1:], value)
+}
+
+// detectIndent returns the indentation width of the first indented line in
+// data, defaulting to yaml.v3's 4.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if n := len(line) - len(trimmed); n > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return n
+		}
+	}
+	return 4
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentedConfig = `# Chronicle config
+retention:
+  # keep a month of history
+  days: 30 # tweak me
+  prune_interval_hours: 24
+
+capture:
+  mode: metadata_only
+`
+
+func TestSetPreservesCommentsAndOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(commentedConfig), 0644))
+
+	require.NoError(t, Set(path, "retention.days", "90"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Chronicle config
+retention:
+  # keep a month of history
+  days: 90 # tweak me
+  prune_interval_hours: 24
+capture:
+  mode: metadata_only
+`, string(data), "comments and order survive; yaml.v3 drops blank lines")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 90, cfg.Retention.Days)
+}
+
+func TestSetAddsMissingKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(commentedConfig), 0644))
+
+	require.NoError(t, Set(path, "fabric.binary", "/usr/local/bin/fabric"))
+	require.NoError(t, Set(path, "capture.denylist_domains", "reddit.com, news.ycombinator.com"))
+	require.NoError(t, Set(path, "embeddings.enabled", "true"))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/local/bin/fabric", cfg.Fabric.Binary)
+	assert.Equal(t, []string{"reddit.com", "news.ycombinator.com"}, cfg.Capture.DenylistDomains)
+	assert.True(t, cfg.Embeddings.Enabled)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# keep a month of history")
+}
+
+func TestSetCreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.yaml")
+
+	require.NoError(t, Set(path, "api.port", "9000"))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.API.Port)
+	assert.Equal(t, 30, cfg.Retention.Days)
+}
+
+func TestSetRejectsBadInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(commentedConfig), 0644))
+
+	assert.ErrorContains(t, Set(path, "retention.weeks", "2"), "unknown config key")
+	assert.ErrorContains(t, Set(path, "retention", "2"), "is a section")
+	assert.ErrorContains(t, Set(path, "retention.days", "lots"), "must be an integer")
+	assert.ErrorContains(t, Set(path, "embeddings.enabled", "maybe"), "true or false")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, commentedConfig, string(data), "failed sets must not touch the file")
+}
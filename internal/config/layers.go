@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Well-known config layers, lowest precedence first: built-in defaults,
+// then the system file, the user (or profile) file, and finally a
+// per-directory file in the working directory.
+const (
+	SystemConfigPath = "/etc/chronicle/config.yaml"
+	LocalConfigName  = ".chronicle.yaml"
+)
+
+// LocalConfigKeys are the sections a per-directory file may set. The file
+// comes from whatever directory chronicle runs in, such as an untrusted
+// checkout, so it is limited to how results are searched and shown: it
+// cannot run programs (hooks, fabric.binary), move the database, or send
+// history anywhere (webhooks, sources, sync).
+var LocalConfigKeys = []string{"search", "output"}
+
+// Layer is one config file in a layered load.
+type Layer struct {
+	Name  string // "system", "user", "local", ...
+	Path  string
+	Allow []string // keys or sections the file may set; nil allows all
+}
+
+// String renders the layer for origin reporting.
+func (l Layer) String() string {
+	if l.Path == "" {
+		return l.Name
+	}
+	return l.Name + " (" + l.Path + ")"
+}
+
+// DefaultLayer is the origin of values no file overrides.
+var DefaultLayer = Layer{Name: "default"}
+
+// Origins maps dotted config keys (e.g. "retention.days") to the layer
+// that last set them.
+type Origins map[string]Layer
+
+// Of returns the layer that set key, or DefaultLayer.
+func (o Origins) Of(key string) Layer {
+	if l, ok := o[key]; ok {
+		return l
+	}
+	return DefaultLayer
+}
+
+// StandardLayers returns the system, user, and per-directory layers, with
+// userPath as the user layer.
+func StandardLayers(userPath string) []Layer {
+	return []Layer{
+		{Name: "system", Path: SystemConfigPath},
+		{Name: "user", Path: userPath},
+		{Name: "local", Path: LocalConfigName, Allow: LocalConfigKeys},
+	}
+}
+
+// LoadLayers merges each existing layer over base in order and records
+// which layer set each value. Missing layer files are skipped; unreadable
+// or invalid ones are errors.
+func LoadLayers(base *Config, layers []Layer) (*Config, Origins, error) {
+	cfg := base
+	origins := Origins{}
+
+	for _, layer := range layers {
+		data, err := os.ReadFile(layer.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s config: %w", layer.Name, err)
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("parsing %s config %s: %w", layer.Name, layer.Path, err)
+		}
+		if layer.Allow != nil && doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+			for _, key := range leafKeys(doc.Content[0], "") {
+				if !keyAllowed(key, layer.Allow) {
+					return nil, nil, fmt.Errorf("%s config %s cannot set %s (only %s)",
+						layer.Name, layer.Path, key, strings.Join(layer.Allow, ", "))
+				}
+			}
+		}
+		if err := doc.Decode(cfg); err != nil && doc.Kind != 0 {
+			return nil, nil, fmt.Errorf("parsing %s config %s: %w", layer.Name, layer.Path, err)
+		}
+		if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+			for _, key := range leafKeys(doc.Content[0], "") {
+				origins[key] = layer
+			}
+		}
+	}
+
+	// ExcludeIncognito is always true regardless of config files.
+	cfg.Capture.ExcludeIncognito = true
+
+	return cfg, origins, nil
+}
+
+// Leaves returns every leaf value of cfg as dotted key / rendered value
+// pairs, in struct order.
+func Leaves(cfg *Config) ([][2]string, error) {
+	var doc yaml.Node
+	if err := doc.Encode(cfg); err != nil {
+		return nil, err
+	}
+
+	var out [][2]string
+	var walk func(n *yaml.Node, prefix string)
+	walk = func(n *yaml.Node, prefix string) {
+		if n.Kind != yaml.MappingNode {
+			out = append(out, [2]string{prefix, renderLeaf(n)})
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			walk(n.Content[i+1], joinKey(prefix, n.Content[i].Value))
+		}
+	}
+	walk(&doc, "")
+	return out, nil
+}
+
+// keyAllowed reports whether the dotted key is one of allow or inside one
+// of its sections.
+func keyAllowed(key string, allow []string) bool {
+	for _, a := range allow {
+		if key == a || strings.HasPrefix(key, a+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// leafKeys lists the dotted keys of every non-mapping value under n.
+func leafKeys(n *yaml.Node, prefix string) []string {
+	if n.Kind != yaml.MappingNode {
+		return []string{prefix}
+	}
+	var keys []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys = append(keys, leafKeys(n.Content[i+1], joinKey(prefix, n.Content[i].Value))...)
+	}
+	return keys
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// renderLeaf formats a scalar or sequence node on one line.
+func renderLeaf(n *yaml.Node) string {
+	if n.Kind != yaml.SequenceNode {
+		return n.Value
+	}
+	items := make([]string, len(n.Content))
+	for i, c := range n.Content {
+		if c.Kind == yaml.ScalarNode {
+			items[i] = c.Value
+			continue
+		}
+		// Structured items (e.g. webhooks) render as flow YAML.
+		c.Style = yaml.FlowStyle
+		out, _ := yaml.Marshal(c)
+		items[i] = strings.TrimSpace(string(out))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
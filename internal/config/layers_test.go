@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLayersPrecedenceAndOrigins(t *testing.T) {
+	dir := t.TempDir()
+	system := filepath.Join(dir, "system.yaml")
+	user := filepath.Join(dir, "user.yaml")
+	local := filepath.Join(dir, "local.yaml")
+
+	require.NoError(t, os.WriteFile(system, []byte("retention:\n  days: 60\napi:\n  port: 9000\n"), 0644))
+	require.NoError(t, os.WriteFile(user, []byte("retention:\n  days: 14\n"), 0644))
+	require.NoError(t, os.WriteFile(local, []byte("search:\n  default_limit: 25\n"), 0644))
+
+	layers := []Layer{
+		{Name: "system", Path: system},
+		{Name: "user", Path: user},
+		{Name: "local", Path: local},
+		{Name: "missing", Path: filepath.Join(dir, "nope.yaml")},
+	}
+	cfg, origins, err := LoadLayers(DefaultConfig(), layers)
+	require.NoError(t, err)
+
+	assert.Equal(t, 14, cfg.Retention.Days)
+	assert.Equal(t, 9000, cfg.API.Port)
+	assert.Equal(t, 25, cfg.Search.DefaultLimit)
+	assert.Equal(t, 24, cfg.Retention.PruneIntervalHours)
+
+	assert.Equal(t, "user", origins.Of("retention.days").Name)
+	assert.Equal(t, "system", origins.Of("api.port").Name)
+	assert.Equal(t, "local", origins.Of("search.default_limit").Name)
+	assert.Equal(t, DefaultLayer, origins.Of("retention.prune_interval_hours"))
+}
+
+func TestLoadLayersLocalAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, LocalConfigName)
+	layers := StandardLayers(filepath.Join(dir, "user.yaml"))
+	layers[0].Path = filepath.Join(dir, "system.yaml")
+	layers[2].Path = local
+
+	require.NoError(t, os.WriteFile(local, []byte("search:\n  default_limit: 25\noutput:\n  color: never\n"), 0644))
+	cfg, _, err := LoadLayers(DefaultConfig(), layers)
+	require.NoError(t, err)
+	assert.Equal(t, 25, cfg.Search.DefaultLimit)
+	assert.Equal(t, "never", cfg.Output.Color)
+
+	for _, doc := range []string{
+		"hooks:\n  pre_capture: [/tmp/evil.sh]\n",
+		"fabric:\n  binary: /tmp/evil\n",
+		"storage:\n  path: /tmp/elsewhere\n",
+		"webhooks:\n  - url: https://attacker.example.com\n",
+		"searchx: 1\n",
+	} {
+		require.NoError(t, os.WriteFile(local, []byte(doc), 0644))
+		_, _, err := LoadLayers(DefaultConfig(), layers)
+		assert.ErrorContains(t, err, "(only search, output)", doc)
+	}
+}
+
+func TestLoadLayersInvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("retention: [unclosed"), 0644))
+
+	_, _, err := LoadLayers(DefaultConfig(), []Layer{{Name: "user", Path: path}})
+	assert.Error(t, err)
+}
+
+func TestLoadLayersEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.yaml")
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+
+	cfg, origins, err := LoadLayers(DefaultConfig(), []Layer{{Name: "local", Path: path}})
+	require.NoError(t, err)
+	assert.Equal(t, 30, cfg.Retention.Days)
+	assert.Empty(t, origins)
+}
+
+func TestLeaves(t *testing.T) {
+	leaves, err := Leaves(DefaultConfig())
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, kv := range leaves {
+		values[kv[0]] = kv[1]
+	}
+	assert.Equal(t, "30", values["retention.days"])
+	assert.Equal(t, "[]", values["capture.denylist_domains"])
+	assert.Equal(t, "retention.days", leaves[0][0])
+	assert.Equal(t, "[]", values["webhooks"])
+}
+
+func TestLeaves_StructuredList(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Webhooks = []WebhookConfig{{URL: "https://hooks.example.com/x", Domains: []string{"github.com"}}}
+	leaves, err := Leaves(cfg)
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, kv := range leaves {
+		values[kv[0]] = kv[1]
+	}
+	assert.Equal(t, "[{url: 'https://hooks.example.com/x', secret: \"\", domains: [github.com], sources: [], timeout_seconds: 0, template: \"\", content_type: \"\"}]", values["webhooks"])
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilesDir holds one subdirectory per profile, each with its own
+// config.yaml, database, and vector directory.
+const ProfilesDir = "~/.config/fabric/chronicle/profiles"
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateProfileName rejects names that are not safe directory names.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// ProfileDir returns the expanded directory for profile name.
+func ProfileDir(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	dir, err := expandPath(ProfilesDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// ProfileConfigPath returns the config file path for profile name.
+func ProfileConfigPath(name string) (string, error) {
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// ProfileDefaults returns the default config for profile name: the global
+// defaults with storage rooted in the profile's directory.
+func ProfileDefaults(name string) *Config {
+	cfg := DefaultConfig()
+	cfg.Storage.Path = ProfilesDir + "/" + name
+	return cfg
+}
+
+// ProfileExists reports whether profile name has been created.
+func ProfileExists(name string) bool {
+	path, err := ProfileConfigPath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// LoadProfile loads the config for profile name, merged over the profile's
+// defaults. It fails if the profile has not been created.
+func LoadProfile(name string) (*Config, error) {
+	path, err := ProfileConfigPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("profile %q does not exist (create it with: chronicle profile create %s)", name, name)
+	}
+	return loadOver(path, ProfileDefaults(name))
+}
+
+// CreateProfile creates profile name with a default config and returns the
+// config path. It fails if the profile already exists.
+func CreateProfile(name string) (string, error) {
+	path, err := ProfileConfigPath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("profile %q already exists", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("creating profile directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(ProfileDefaults(name))
+	if err != nil {
+		return "", fmt.Errorf("marshaling profile config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("writing profile config: %w", err)
+	}
+	return path, nil
+}
+
+// ListProfiles returns the names of all created profiles, sorted.
+func ListProfiles() ([]string, error) {
+	dir, err := expandPath(ProfilesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading profiles directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && ProfileExists(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
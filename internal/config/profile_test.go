@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, ValidateProfileName("work"))
+	assert.NoError(t, ValidateProfileName("client_a-2"))
+	assert.Error(t, ValidateProfileName(""))
+	assert.Error(t, ValidateProfileName("../etc"))
+	assert.Error(t, ValidateProfileName("-x"))
+}
+
+func TestCreateAndLoadProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	_, err := LoadProfile("work")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+
+	path, err := CreateProfile("work")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config/fabric/chronicle/profiles/work/config.yaml"), path)
+
+	_, err = CreateProfile("work")
+	assert.Error(t, err)
+
+	cfg, err := LoadProfile("work")
+	require.NoError(t, err)
+	assert.Equal(t, "~/.config/fabric/chronicle/profiles/work", cfg.Storage.Path)
+	assert.Equal(t, "chronicle.db", cfg.Storage.SQLiteFile)
+}
+
+func TestListProfiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	for _, name := range []string{"personal", "work"} {
+		_, err := CreateProfile(name)
+		require.NoError(t, err)
+	}
+	// A stray directory without a config is not a profile.
+	dir, err := ProfileDir("stray")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+
+	names, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal", "work"}, names)
+}
@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// eventRequest is a page capture sent by an extension.
+type eventRequest struct {
+	URL           string    `json:"url"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body,omitempty"`
+	Browser       string    `json:"browser,omitempty"`
+	Incognito     bool      `json:"incognito,omitempty"`
+	Timestamp     time.Time `json:"timestamp,omitempty"`
+	CanonicalURL  string    `json:"canonical_url,omitempty"`
+	OGTitle       string    `json:"og_title,omitempty"`
+	OGDescription string    `json:"og_description,omitempty"`
+	OGImage       string    `json:"og_image,omitempty"`
+	Author        string    `json:"author,omitempty"`
+	Published     time.Time `json:"published,omitempty"`
+}
+
+// pageMeta returns the request's page metadata, or nil without any.
+func (req *eventRequest) pageMeta() *storage.PageMeta {
+	m := storage.PageMeta{
+		OGTitle:       req.OGTitle,
+		OGDescription: req.OGDescription,
+		OGImage:       req.OGImage,
+		Author:        req.Author,
+		Published:     req.Published,
+	}
+	if m.IsZero() {
+		return nil
+	}
+	return &m
+}
+
+// eventResponse describes the stored event.
+type eventResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Domain       string `json:"domain"`
+	Timestamp    string `json:"timestamp"`
+	Source       string `json:"source"`
+	Browser      string `json:"browser,omitempty"`
+	HasBody      bool   `json:"has_body"`
+	HasEmbedding bool   `json:"has_embedding"`
+	Visits       int    `json:"visits"`
+}
+
+// SetMaxRequestSize caps POST /events bodies at n bytes; n <= 0 leaves
+// the default.
+func (s *Server) SetMaxRequestSize(n int) {
+	if n > 0 {
+		s.maxEventBytes = int64(n)
+	}
+}
+
+// SetPipeline stores captures through p, with its hooks and webhooks,
+// rather than a bare pipeline over the store.
+func (s *Server) SetPipeline(p *capture.Pipeline) {
+	s.pipeline = p
+}
+
+// SetCapturePolicy applies cfg to the pages extensions send: pages that
+// are incognito (with exclude_incognito), denylisted or outside a
+// non-empty allowlist are refused, and in metadata_only mode only pages
+// on body_capture_domains keep their bodies.
+func (s *Server) SetCapturePolicy(cfg config.CaptureConfig) error {
+	p, err := newCapturePolicy(cfg)
+	if err != nil {
+		return err
+	}
+	s.policy = p
+	return nil
+}
+
+// handleEvent stores a page capture. Pages the capture policy refuses,
+// excluded domains and hook vetoes get 403, so the extension does not
+// retry them.
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	var req eventRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxEventBytes)).Decode(&req); err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooBig.Limit))
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.URL == "" || req.Title == "" {
+		writeError(w, http.StatusBadRequest, "url and title are required")
+		return
+	}
+	parsed, err := url.ParseRequestURI(req.URL)
+	if err != nil || parsed.Host == "" {
+		writeError(w, http.StatusBadRequest, "invalid URL: "+req.URL)
+		return
+	}
+	if s.policy != nil {
+		domain := parsed.Hostname()
+		if reason := s.policy.reject(req.URL, domain, req.Incognito); reason != "" {
+			slog.Debug("capture refused", "url", req.URL, "reason", reason)
+			writeError(w, http.StatusForbidden, reason)
+			return
+		}
+		if !s.policy.keepBody(domain) {
+			req.Body = ""
+		}
+	}
+
+	ctx := r.Context()
+	event := &storage.Event{
+		URL:          req.URL,
+		Title:        req.Title,
+		Browser:      req.Browser,
+		Source:       "extension",
+		Timestamp:    req.Timestamp,
+		Meta:         req.pageMeta(),
+		CanonicalURL: req.CanonicalURL,
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = s.now()
+	}
+	err = s.pipeline.Capture(ctx, event, req.Body)
+	switch {
+	case errors.Is(err, hook.ErrVetoed):
+		slog.Info("capture vetoed", "url", event.URL, "err", err)
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, capture.ErrExcluded):
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, storage.ErrBodyTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case err != nil:
+		slog.Error("capture event", "url", event.URL, "err", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	slog.Debug("event captured", "id", event.ID, "domain", event.Domain, "body", event.HasBody)
+
+	writeJSON(w, http.StatusCreated, eventResponse{
+		ID:        event.ID,
+		URL:       event.URL,
+		Title:     event.Title,
+		Domain:    event.Domain,
+		Timestamp: event.Timestamp.UTC().Format(time.RFC3339),
+		Source:    event.Source,
+		Browser:   event.Browser,
+		HasBody:   event.HasBody,
+		Visits:    event.Visits,
+	})
+}
@@ -0,0 +1,149 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func TestEvents_Capture(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var captured []string
+	srv, store := setupServer(t, "secret", now, func(s *Server) {
+		p := capture.New(s.store)
+		p.SetOnCapture(func(ctx context.Context, e *storage.Event, body string) {
+			captured = append(captured, e.ID+" "+body)
+		})
+		s.SetPipeline(p)
+	})
+
+	req := map[string]interface{}{
+		"url":     "https://go.dev/blog/loopvar",
+		"title":   "Fixing For Loops in Go 1.22",
+		"body":    "Go 1.22 changes the semantics of loop variables.",
+		"browser": "firefox",
+		"author":  "David Chase",
+	}
+	assert.Equal(t, 401, post(t, srv, "", "/events", req, nil))
+
+	var resp eventResponse
+	require.Equal(t, 201, post(t, srv, "secret", "/events", req, &resp))
+	assert.Equal(t, "go.dev", resp.Domain)
+	assert.Equal(t, "extension", resp.Source)
+	assert.Equal(t, "2026-03-02T09:00:00Z", resp.Timestamp)
+	assert.True(t, resp.HasBody)
+	assert.Equal(t, []string{resp.ID + " Go 1.22 changes the semantics of loop variables."}, captured)
+
+	e, err := store.GetEvent(context.Background(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "firefox", e.Browser)
+	assert.NotEmpty(t, e.ContentHash)
+	meta, err := store.GetPageMeta(context.Background(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "David Chase", meta.Author)
+
+	req = map[string]interface{}{"url": "https://go.dev/doc/", "title": "Docs", "timestamp": "2026-03-01T18:30:00Z"}
+	require.Equal(t, 201, post(t, srv, "secret", "/events", req, &resp))
+	assert.Equal(t, "2026-03-01T18:30:00Z", resp.Timestamp)
+	assert.False(t, resp.HasBody)
+}
+
+func TestEvents_Rejected(t *testing.T) {
+	srv, store := setupServer(t, "", time.Now(), func(s *Server) {
+		s.SetMaxRequestSize(200)
+	})
+
+	assert.Equal(t, 400, post(t, srv, "", "/events", map[string]string{"url": "https://go.dev/"}, nil))
+	assert.Equal(t, 400, post(t, srv, "", "/events", map[string]string{"url": "go.dev", "title": "Go"}, nil))
+
+	var errResp map[string]string
+	assert.Equal(t, 403, post(t, srv, "", "/events", map[string]string{"url": "https://chase.com/login", "title": "Bank"}, &errResp))
+	assert.Contains(t, errResp["error"], "excluded")
+
+	big := map[string]string{"url": "https://go.dev/", "title": "Go", "body": strings.Repeat("x", 300)}
+	assert.Equal(t, 413, post(t, srv, "", "/events", big, &errResp))
+	assert.Equal(t, "request body exceeds 200 bytes", errResp["error"])
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+}
+
+func TestEvents_CapturePolicy(t *testing.T) {
+	cfg := config.DefaultConfig().Capture
+	cfg.AllowlistDomains = []string{"go.dev", "example.com"}
+	cfg.DenylistDomains = []string{"private.example.com"}
+	cfg.DenylistRegex = []string{`/login\b`}
+	cfg.BodyCaptureDomains = []string{"go.dev"}
+	srv, store := setupServer(t, "", time.Now(), func(s *Server) {
+		require.NoError(t, s.SetCapturePolicy(cfg))
+	})
+
+	page := func(url string) map[string]interface{} {
+		return map[string]interface{}{"url": url, "title": "Page", "body": "Page text"}
+	}
+	var resp eventResponse
+	require.Equal(t, 201, post(t, srv, "", "/events", page("https://blog.go.dev/post"), &resp))
+	assert.True(t, resp.HasBody, "body_capture_domains keep bodies in metadata_only mode")
+	require.Equal(t, 201, post(t, srv, "", "/events", page("https://www.example.com/"), &resp))
+	assert.False(t, resp.HasBody)
+
+	var errResp map[string]string
+	assert.Equal(t, 403, post(t, srv, "", "/events", page("https://news.ycombinator.com/"), &errResp))
+	assert.Equal(t, `domain "news.ycombinator.com" is not in capture.allowlist_domains`, errResp["error"])
+	assert.Equal(t, 403, post(t, srv, "", "/events", page("https://private.example.com/notes"), &errResp))
+	assert.Contains(t, errResp["error"], "capture.denylist_domains")
+	assert.Equal(t, 403, post(t, srv, "", "/events", page("https://go.dev/login?next=/"), &errResp))
+	assert.Contains(t, errResp["error"], "capture.denylist_regex")
+	incognito := page("https://go.dev/doc/")
+	incognito["incognito"] = true
+	assert.Equal(t, 403, post(t, srv, "", "/events", incognito, &errResp))
+	assert.Equal(t, "incognito pages are not captured", errResp["error"])
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalEvents)
+
+	cfg = config.DefaultConfig().Capture
+	cfg.Mode = "full"
+	srv, _ = setupServer(t, "", time.Now(), func(s *Server) {
+		require.NoError(t, s.SetCapturePolicy(cfg))
+	})
+	require.Equal(t, 201, post(t, srv, "", "/events", page("https://news.ycombinator.com/"), &resp))
+	assert.True(t, resp.HasBody)
+
+	cfg.Mode = "everything"
+	assert.EqualError(t, NewServer(store, "").SetCapturePolicy(cfg), `invalid capture.mode "everything" (want metadata_only or full)`)
+	cfg.Mode = "full"
+	cfg.DenylistRegex = []string{"("}
+	assert.ErrorContains(t, NewServer(store, "").SetCapturePolicy(cfg), `invalid capture.denylist_regex "("`)
+}
+
+func TestEvents_HookVeto(t *testing.T) {
+	veto := filepath.Join(t.TempDir(), "veto")
+	require.NoError(t, os.WriteFile(veto, []byte("#!/bin/sh\necho 'private page' >&2\nexit 1\n"), 0755))
+	srv, store := setupServer(t, "", time.Now(), func(s *Server) {
+		p := capture.New(s.store)
+		p.SetHooks(hook.New(config.HooksConfig{PreCapture: []string{veto}}))
+		s.SetPipeline(p)
+	})
+
+	var errResp map[string]string
+	assert.Equal(t, 403, post(t, srv, "", "/events", map[string]string{"url": "https://go.dev/", "title": "Go"}, &errResp))
+	assert.Contains(t, errResp["error"], "private page")
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// capturePolicy applies the capture config to the pages extensions send:
+// which are stored at all, and which keep their bodies.
+type capturePolicy struct {
+	metadataOnly     bool     // bodies only from bodyDomains
+	bodyDomains      []string // capture.body_capture_domains
+	allow            []string // capture.allowlist_domains; empty allows all
+	deny             []string // capture.denylist_domains
+	denyRegex        []*regexp.Regexp
+	excludeIncognito bool
+}
+
+// newCapturePolicy builds the policy for cfg. Mode is metadata_only (the
+// default) or full.
+func newCapturePolicy(cfg config.CaptureConfig) (*capturePolicy, error) {
+	p := &capturePolicy{
+		bodyDomains:      lowerAll(cfg.BodyCaptureDomains),
+		allow:            lowerAll(cfg.AllowlistDomains),
+		deny:             lowerAll(cfg.DenylistDomains),
+		excludeIncognito: cfg.ExcludeIncognito,
+	}
+	switch cfg.Mode {
+	case "", "metadata_only":
+		p.metadataOnly = true
+	case "full":
+	default:
+		return nil, fmt.Errorf("invalid capture.mode %q (want metadata_only or full)", cfg.Mode)
+	}
+	for _, expr := range cfg.DenylistRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid capture.denylist_regex %q: %w", expr, err)
+		}
+		p.denyRegex = append(p.denyRegex, re)
+	}
+	return p, nil
+}
+
+// reject returns why the page at rawURL, on domain, is not captured, or
+// "" if it is.
+func (p *capturePolicy) reject(rawURL, domain string, incognito bool) string {
+	if incognito && p.excludeIncognito {
+		return "incognito pages are not captured"
+	}
+	if d, ok := matchDomain(domain, p.deny); ok {
+		return fmt.Sprintf("domain %q is denied by capture.denylist_domains entry %q", domain, d)
+	}
+	for _, re := range p.denyRegex {
+		if re.MatchString(rawURL) {
+			return fmt.Sprintf("URL is denied by capture.denylist_regex %q", re.String())
+		}
+	}
+	if len(p.allow) > 0 {
+		if _, ok := matchDomain(domain, p.allow); !ok {
+			return fmt.Sprintf("domain %q is not in capture.allowlist_domains", domain)
+		}
+	}
+	return ""
+}
+
+// keepBody reports whether a page on domain keeps its body.
+func (p *capturePolicy) keepBody(domain string) bool {
+	if !p.metadataOnly {
+		return true
+	}
+	_, ok := matchDomain(domain, p.bodyDomains)
+	return ok
+}
+
+// matchDomain returns the entry of domains that domain is, or is a
+// subdomain of.
+func matchDomain(domain string, domains []string) (string, bool) {
+	domain = strings.ToLower(domain)
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// lowerAll returns domains lowercased.
+func lowerAll(domains []string) []string {
+	out := make([]string, len(domains))
+	for i, d := range domains {
+		out[i] = strings.ToLower(d)
+	}
+	return out
+}
@@ -0,0 +1,238 @@
+// Package daemon implements Chronicle's local ingest daemon, the HTTP
+// server browser extensions talk to: they register, send heartbeats, and
+// POST the pages they capture to /events.
+package daemon
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/openapi"
+	"github.com/runnerr0/chronicle/internal/scheduler"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// HeartbeatInterval is how often extensions are asked to send a heartbeat.
+const HeartbeatInterval = 60 * time.Second
+
+// maxRegisterBytes caps register and heartbeat request bodies.
+const maxRegisterBytes = 4 << 10
+
+// defaultMaxEventBytes caps POST /events bodies unless SetMaxRequestSize
+// changes it.
+const defaultMaxEventBytes = 10 << 20
+
+// Server handles requests from browser extensions.
+type Server struct {
+	store *storage.SQLiteStore
+	token string
+	jobs  *scheduler.Scheduler // reported by /status; nil without jobs
+	now   func() time.Time
+
+	maxEventBytes int64
+	pipeline      *capture.Pipeline // stores captures
+	policy        *capturePolicy    // nil stores every page with its body
+}
+
+// NewServer creates a daemon server over store. If token is non-empty,
+// every request must present it as a bearer token; ingest always sets
+// one, generating it on first start.
+func NewServer(store *storage.SQLiteStore, token string) *Server {
+	return &Server{store: store, token: token, now: time.Now, maxEventBytes: defaultMaxEventBytes, pipeline: capture.New(store)}
+}
+
+// SetJobs makes /status report the status of jobs' scheduled jobs.
+func (s *Server) SetJobs(jobs *scheduler.Scheduler) {
+	s.jobs = jobs
+}
+
+// Handler returns the HTTP handler with all routes and auth applied.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("POST /register", s.handleRegister)
+	mux.HandleFunc("POST /heartbeat", s.handleHeartbeat)
+	mux.HandleFunc("POST /events", s.handleEvent)
+	mux.HandleFunc("GET "+openapi.Path, openapi.Handler)
+	return rejectCrossSite(s.requireToken(mux))
+}
+
+// extensionSchemes are the Origin schemes of browser extensions, the only
+// pages allowed to call the daemon.
+var extensionSchemes = []string{"chrome-extension", "moz-extension", "safari-web-extension"}
+
+// rejectCrossSite keeps web pages from forging captures through the
+// user's browser. A request with an Origin that is not an extension's
+// gets 403, and a POST whose body is not application/json gets 415: a
+// page can only send that content type after a CORS preflight, which the
+// daemon never approves.
+func rejectCrossSite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !extensionOrigin(origin) {
+			writeError(w, http.StatusForbidden, "origin "+origin+" is not allowed")
+			return
+		}
+		if r.Method == http.MethodPost {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// extensionOrigin reports whether origin is a browser extension's.
+func extensionOrigin(origin string) bool {
+	scheme, _, ok := strings.Cut(origin, "://")
+	if !ok {
+		return false
+	}
+	for _, s := range extensionSchemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireToken rejects requests without a matching bearer token when a
+// token is configured. The OpenAPI document and /status are public;
+// /status leaves out job details for unauthorized callers.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == openapi.Path || r.URL.Path == "/status" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized reports whether r presents the token, or none is needed.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// statusResponse is the body of GET /status.
+type statusResponse struct {
+	Status string                `json:"status"`
+	Jobs   []scheduler.JobStatus `json:"jobs,omitempty"`
+}
+
+// handleStatus reports that the daemon is up, with the scheduled jobs'
+// status for authorized callers.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	resp := statusResponse{Status: "ok"}
+	if s.jobs != nil && s.authorized(r) {
+		resp.Jobs = s.jobs.Status()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// registerRequest is sent by an extension on startup. ID is the one it was
+// given by an earlier registration, if any.
+type registerRequest struct {
+	ID      string `json:"id,omitempty"`
+	Browser string `json:"browser"`
+	Version string `json:"version"`
+	Profile string `json:"profile,omitempty"`
+}
+
+type registerResponse struct {
+	ID                       string `json:"id"`
+	HeartbeatIntervalSeconds int    `json:"heartbeat_interval_seconds"`
+}
+
+type heartbeatRequest struct {
+	ID string `json:"id"`
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Browser == "" {
+		writeError(w, http.StatusBadRequest, "browser is required")
+		return
+	}
+
+	ext := &storage.Extension{ID: req.ID, Browser: req.Browser, Version: req.Version, Profile: req.Profile}
+	if err := s.store.RegisterExtension(r.Context(), ext, s.now()); err != nil {
+		slog.Error("register extension", "browser", req.Browser, "err", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	slog.Info("extension registered", "id", ext.ID, "browser", ext.Browser, "version", ext.Version, "profile", ext.Profile)
+	writeJSON(w, http.StatusCreated, registerResponse{
+		ID:                       ext.ID,
+		HeartbeatIntervalSeconds: int(HeartbeatInterval / time.Second),
+	})
+}
+
+// handleHeartbeat marks an extension as seen. Unknown IDs get 404 so the
+// extension knows to register again.
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var req heartbeatRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.ID == "" {
+		writeError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	err := s.store.TouchExtension(r.Context(), req.ID, s.now())
+	switch {
+	case errors.Is(err, storage.ErrUnknownExtension):
+		slog.Info("heartbeat from unknown extension", "id", req.ID)
+		writeError(w, http.StatusNotFound, "unknown extension; register again")
+	case err != nil:
+		slog.Error("heartbeat", "id", req.ID, "err", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		slog.Debug("heartbeat", "id", req.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// decodeBody decodes a JSON request body into v, writing a 400 and
+// returning false if it is malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRegisterBytes))
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
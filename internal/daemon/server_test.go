@@ -0,0 +1,219 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/openapi"
+	"github.com/runnerr0/chronicle/internal/scheduler"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// setupServer creates a migrated in-memory store and a test HTTP server in
+// front of it whose clock is fixed at now. configure, if given, adjusts the
+// server first.
+func setupServer(t *testing.T, token string, now time.Time, configure ...func(*Server)) (*httptest.Server, *storage.SQLiteStore) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	s := NewServer(store, token)
+	s.now = func() time.Time { return now }
+	for _, fn := range configure {
+		fn(s)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func post(t *testing.T, srv *httptest.Server, token, path string, body, out interface{}) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestRegisterAndHeartbeat(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv, store := setupServer(t, "", now)
+
+	var reg registerResponse
+	code := post(t, srv, "", "/register", registerRequest{Browser: "firefox", Version: "1.4.0", Profile: "default"}, &reg)
+	require.Equal(t, http.StatusCreated, code)
+	assert.NotEmpty(t, reg.ID)
+	assert.Equal(t, 60, reg.HeartbeatIntervalSeconds)
+
+	assert.Equal(t, http.StatusNoContent, post(t, srv, "", "/heartbeat", heartbeatRequest{ID: reg.ID}, nil))
+
+	exts, err := store.ListExtensions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, exts, 1)
+	assert.Equal(t, "firefox", exts[0].Browser)
+	assert.Equal(t, "1.4.0", exts[0].Version)
+	assert.Equal(t, "default", exts[0].Profile)
+	assert.Equal(t, now, exts[0].LastSeen)
+}
+
+func TestRegister_KeepsExistingID(t *testing.T) {
+	srv, store := setupServer(t, "", time.Now())
+
+	var first, second registerResponse
+	post(t, srv, "", "/register", registerRequest{Browser: "chrome", Version: "1.0.0"}, &first)
+	post(t, srv, "", "/register", registerRequest{ID: first.ID, Browser: "chrome", Version: "1.1.0"}, &second)
+	assert.Equal(t, first.ID, second.ID)
+
+	exts, err := store.ListExtensions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, exts, 1)
+	assert.Equal(t, "1.1.0", exts[0].Version)
+}
+
+func TestRegister_RequiresBrowser(t *testing.T) {
+	srv, _ := setupServer(t, "", time.Now())
+
+	var body map[string]string
+	assert.Equal(t, http.StatusBadRequest, post(t, srv, "", "/register", registerRequest{Version: "1.0.0"}, &body))
+	assert.Contains(t, body["error"], "browser")
+}
+
+func TestHeartbeat_UnknownID(t *testing.T) {
+	srv, _ := setupServer(t, "", time.Now())
+
+	assert.Equal(t, http.StatusNotFound, post(t, srv, "", "/heartbeat", heartbeatRequest{ID: "EXT-deadbeef"}, nil))
+	assert.Equal(t, http.StatusBadRequest, post(t, srv, "", "/heartbeat", heartbeatRequest{}, nil))
+}
+
+func TestRequireToken(t *testing.T) {
+	srv, _ := setupServer(t, "secret", time.Now())
+
+	req := registerRequest{Browser: "chrome"}
+	assert.Equal(t, http.StatusUnauthorized, post(t, srv, "", "/register", req, nil))
+	assert.Equal(t, http.StatusUnauthorized, post(t, srv, "wrong", "/register", req, nil))
+	assert.Equal(t, http.StatusCreated, post(t, srv, "secret", "/register", req, nil))
+}
+
+func TestRejectCrossSite(t *testing.T) {
+	srv, store := setupServer(t, "", time.Now())
+
+	send := func(contentType, origin string) int {
+		t.Helper()
+		body := strings.NewReader(`{"url":"https://go.dev/","title":"Go"}`)
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/events", body)
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// What a page can send without a CORS preflight.
+	assert.Equal(t, http.StatusUnsupportedMediaType, send("text/plain", ""))
+	assert.Equal(t, http.StatusUnsupportedMediaType, send("", ""))
+	assert.Equal(t, http.StatusForbidden, send("text/plain", "https://evil.example"))
+	assert.Equal(t, http.StatusForbidden, send("application/json", "https://evil.example"))
+	assert.Equal(t, http.StatusForbidden, send("application/json", "null"))
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+
+	assert.Equal(t, http.StatusCreated, send("application/json; charset=utf-8", ""))
+	assert.Equal(t, http.StatusCreated, send("application/json", "chrome-extension://abcdefghijklmnop"))
+	assert.Equal(t, http.StatusCreated, send("application/json", "moz-extension://1b2c3d4e-0000-4000-8000-000000000000"))
+}
+
+func TestOpenAPI_PublicAndRouted(t *testing.T) {
+	srv, _ := setupServer(t, "secret", time.Now())
+
+	resp, err := http.Get(srv.URL + openapi.Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	// Every documented capture endpoint must be routed by this server.
+	for path, item := range doc.Paths {
+		if _, ok := item["post"]; !ok {
+			continue
+		}
+		code := post(t, srv, "secret", path, map[string]string{}, nil)
+		assert.Equal(t, http.StatusBadRequest, code, path)
+	}
+}
+
+func TestStatus_JobsNeedToken(t *testing.T) {
+	jobs := scheduler.New()
+	require.NoError(t, jobs.Add("nightly-prune", "prune", "0 3 * * *", func(ctx context.Context) (string, error) {
+		return "pruned 0 events", nil
+	}))
+	s := NewServer(nil, "secret")
+	s.SetJobs(jobs)
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	get := func(token string) statusResponse {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/status", nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var out statusResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return out
+	}
+
+	anon := get("")
+	assert.Equal(t, "ok", anon.Status)
+	assert.Empty(t, anon.Jobs)
+
+	authed := get("secret")
+	require.Len(t, authed.Jobs, 1)
+	assert.Equal(t, "nightly-prune", authed.Jobs[0].Name)
+	assert.NotNil(t, authed.Jobs[0].Next)
+}
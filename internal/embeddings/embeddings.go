@@ -0,0 +1,49 @@
+// Package embeddings turns event text into vectors for semantic search.
+package embeddings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/ollama"
+)
+
+// Embedder generates embeddings.
+type Embedder interface {
+	// Embed returns the embedding of each of texts, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model names what generates the embeddings, e.g.
+	// "ollama:nomic-embed-text". Only embeddings from the same model can
+	// be compared.
+	Model() string
+}
+
+// New returns the embedder configured by the embeddings config section.
+func New(cfg config.EmbeddingsConfig) (Embedder, error) {
+	switch cfg.Provider {
+	case "ollama":
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("embeddings.model is required")
+		}
+		return &Ollama{Client: ollama.New(cfg.OllamaURL), Name: cfg.Model}, nil
+	default:
+		return nil, fmt.Errorf("invalid embeddings.provider %q (want ollama)", cfg.Provider)
+	}
+}
+
+// Ollama embeds with a model served by Ollama.
+type Ollama struct {
+	Client *ollama.Client
+	Name   string // model name, e.g. nomic-embed-text
+}
+
+// Embed implements Embedder.
+func (o *Ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return o.Client.Embed(ctx, o.Name, texts)
+}
+
+// Model implements Embedder.
+func (o *Ollama) Model() string {
+	return "ollama:" + o.Name
+}
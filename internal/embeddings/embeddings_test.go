@@ -0,0 +1,45 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Ollama(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		assert.Equal(t, []string{"hello"}, req.Input)
+		w.Write([]byte(`{"embeddings":[[0.1,0.2,0.3]]}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultConfig().Embeddings
+	cfg.OllamaURL = srv.URL
+	e, err := New(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ollama:nomic-embed-text", e.Model())
+
+	got, err := e.Embed(context.Background(), []string{"hello"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.1, 0.2, 0.3}}, got)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(config.EmbeddingsConfig{Provider: "openai", Model: "x"})
+	assert.EqualError(t, err, `invalid embeddings.provider "openai" (want ollama)`)
+
+	_, err = New(config.EmbeddingsConfig{Provider: "ollama"})
+	assert.EqualError(t, err, "embeddings.model is required")
+}
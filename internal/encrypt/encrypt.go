@@ -0,0 +1,100 @@
+// Package encrypt seals captured page bodies for storage with AES-256-GCM
+// under a key derived from a passphrase with PBKDF2-HMAC-SHA256.
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// KeyIterations is the PBKDF2 iteration count used by DeriveKey.
+const KeyIterations = 600_000
+
+// SaltSize is the length of the salt made by NewSalt.
+const SaltSize = 16
+
+// SealedPrefix marks a sealed value and its format version, so sealed and
+// plaintext values can share a column while a database is being converted.
+const SealedPrefix = "chr-enc1:"
+
+// ErrDecrypt is returned when a sealed value cannot be opened: the key is
+// wrong or the value was altered.
+var ErrDecrypt = errors.New("cannot decrypt: wrong key or corrupted data")
+
+// NewSalt returns a random salt for DeriveKey.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// DeriveKey stretches passphrase into a 32-byte key with PBKDF2-HMAC-SHA256
+// (RFC 8018) over salt for iterations rounds.
+func DeriveKey(passphrase string, salt []byte, iterations int) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New)
+}
+
+// Cipher seals and opens values with one key.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New returns a Cipher for a 32-byte key from DeriveKey.
+func New(key []byte) (*Cipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key is %d bytes, want 32", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts plaintext under a fresh random nonce and returns it as
+// text: the format prefix, then the nonce and ciphertext in base64.
+func (c *Cipher) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return SealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value from Seal. Values that were never sealed are
+// returned unchanged.
+func (c *Cipher) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(value[len(SealedPrefix):])
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether value came from Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, SealedPrefix)
+}
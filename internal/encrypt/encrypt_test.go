@@ -0,0 +1,55 @@
+package encrypt
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveKey_PBKDF2Vectors(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vectors for P="password", S="salt".
+	cases := map[int]string{
+		1:    "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b",
+		2:    "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43",
+		4096: "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+	}
+	for iterations, want := range cases {
+		assert.Equal(t, want, hex.EncodeToString(DeriveKey("password", []byte("salt"), iterations)), "%d iterations", iterations)
+	}
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	salt, err := NewSalt()
+	require.NoError(t, err)
+	c, err := New(DeriveKey("correct horse", salt, 1000))
+	require.NoError(t, err)
+
+	sealed, err := c.Seal("Tide tables for the harbor")
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, sealed, "harbor")
+	again, err := c.Seal("Tide tables for the harbor")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "each seal uses a fresh nonce")
+
+	plain, err := c.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "Tide tables for the harbor", plain)
+
+	plain, err = c.Open("stored before encryption")
+	require.NoError(t, err)
+	assert.Equal(t, "stored before encryption", plain)
+
+	wrong, err := New(DeriveKey("wrong horse", salt, 1000))
+	require.NoError(t, err)
+	_, err = wrong.Open(sealed)
+	assert.ErrorIs(t, err, ErrDecrypt)
+	_, err = c.Open(strings.TrimSuffix(sealed, sealed[len(sealed)-4:]) + "AAAA")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = New([]byte("short"))
+	assert.EqualError(t, err, "encryption key is 5 bytes, want 32")
+}
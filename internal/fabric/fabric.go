@@ -0,0 +1,214 @@
+package fabric
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// ErrNotFound is returned when the fabric binary cannot be located.
+var ErrNotFound = errors.New("fabric binary not found")
+
+// Client runs fabric patterns through the fabric binary.
+type Client struct {
+	Binary      string        // resolved path to the fabric executable
+	PatternsDir string        // fabric patterns directory, ~ expanded
+	Timeout     time.Duration // per run; zero means no limit
+}
+
+// New builds a Client from the fabric config section, locating the binary.
+func New(cfg config.FabricConfig) (*Client, error) {
+	bin, err := Locate(cfg.Binary)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := cfg.PatternsPath()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		Binary:      bin,
+		PatternsDir: dir,
+		Timeout:     time.Duration(cfg.TimeoutSeconds) * time.Second,
+	}, nil
+}
+
+// Locate resolves the fabric executable: binary if set, otherwise "fabric"
+// from PATH.
+func Locate(binary string) (string, error) {
+	if binary == "" {
+		binary = "fabric"
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q (set fabric.binary in config): %v", ErrNotFound, binary, err)
+	}
+	return path, nil
+}
+
+// Run pipes input through the named pattern and returns fabric's stdout.
+// Failures include fabric's stderr; a run exceeding Timeout is killed.
+func (c *Client) Run(ctx context.Context, pattern, input string) (string, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, c.Binary, "--pattern", pattern)
+	cmd.Stdin = strings.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("fabric pattern %q timed out after %s", pattern, c.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("fabric pattern %q failed: %w: %s", pattern, err, msg)
+		}
+		return "", fmt.Errorf("fabric pattern %q failed: %w", pattern, err)
+	}
+
+	return stdout.String(), nil
+}
+
+// Pattern is one fabric pattern found in a patterns directory.
+type Pattern struct {
+	Name        string
+	Description string // first sentence of the prompt, may be empty
+}
+
+// maxDescription caps pattern descriptions for one-line listings.
+const maxDescription = 100
+
+// Patterns lists the patterns in PatternsDir, sorted by name.
+func (c *Client) Patterns() ([]Pattern, error) {
+	return ListPatterns(c.PatternsDir)
+}
+
+// ListPatterns lists the patterns in dir, sorted by name. A pattern is a
+// directory containing a system.md prompt.
+func ListPatterns(dir string) ([]Pattern, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading patterns directory: %w", err)
+	}
+
+	var patterns []Pattern
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		prompt, err := os.ReadFile(filepath.Join(dir, e.Name(), "system.md"))
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, Pattern{Name: e.Name(), Description: describe(string(prompt))})
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].Name < patterns[j].Name })
+	return patterns, nil
+}
+
+// describe returns the first sentence of the first prose paragraph of a
+// pattern prompt, skipping markdown headings.
+func describe(prompt string) string {
+	var para []string
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			if len(para) > 0 {
+				break
+			}
+			continue
+		}
+		para = append(para, line)
+	}
+
+	desc := strings.Join(para, " ")
+	if i := strings.Index(desc, ". "); i >= 0 {
+		desc = desc[:i+1]
+	}
+	if r := []rune(desc); len(r) > maxDescription {
+		desc = strings.TrimSpace(string(r[:maxDescription-3])) + "..."
+	}
+	return desc
+}
+
+// ExtensionName is the name Chronicle registers under in fabric.
+const ExtensionName = "chronicle"
+
+// ExtensionConfigPath returns where the extension config is written, next
+// to fabric's own extension configs.
+func ExtensionConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "fabric", "extensions", "configs", ExtensionName+".yaml"), nil
+}
+
+// ExtensionConfig renders a fabric extension config that runs
+// `chronicle context` for the "context" operation, so patterns can pull
+// history with {{ext:chronicle:context:QUERY}}. extraArgs (e.g. --profile
+// work) are passed before the subcommand.
+func ExtensionConfig(executable, version string, extraArgs []string) []byte {
+	cmd := "{{executable}}"
+	for _, a := range extraArgs {
+		cmd += " " + shellQuote(a)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", ExtensionName)
+	fmt.Fprintf(&b, "executable: %q\n", executable)
+	b.WriteString("type: executable\n")
+	b.WriteString("timeout: \"30s\"\n")
+	b.WriteString("description: \"Relevant browsing history from Chronicle\"\n")
+	fmt.Fprintf(&b, "version: %q\n", version)
+	b.WriteString("env: []\n\n")
+	b.WriteString("operations:\n")
+	b.WriteString("  context:\n")
+	fmt.Fprintf(&b, "    cmd_template: %q\n", cmd+" context --query {{value}}")
+	b.WriteString("  search:\n")
+	fmt.Fprintf(&b, "    cmd_template: %q\n", cmd+" search --output urls {{value}}")
+	b.WriteString("\nconfig:\n")
+	b.WriteString("  output:\n")
+	b.WriteString("    method: stdout\n")
+	return []byte(b.String())
+}
+
+// shellQuote single-quotes s if it contains anything beyond a safe set.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Register runs `fabric --addextension path` so fabric picks up the
+// extension config.
+func (c *Client) Register(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, c.Binary, "--addextension", path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("registering fabric extension: %w: %s", err, msg)
+		}
+		return fmt.Errorf("registering fabric extension: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package fabric
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// script writes an executable shell script and returns its path.
+func script(t *testing.T, body string) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "fabric")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\n"+body), 0755))
+	return bin
+}
+
+func TestNew(t *testing.T) {
+	bin := script(t, "cat\n")
+	c, err := New(config.FabricConfig{Binary: bin, PatternsDir: "/tmp/patterns", TimeoutSeconds: 5})
+	require.NoError(t, err)
+	assert.Equal(t, bin, c.Binary)
+	assert.Equal(t, "/tmp/patterns", c.PatternsDir)
+	assert.Equal(t, 5*time.Second, c.Timeout)
+}
+
+func TestLocateMissing(t *testing.T) {
+	_, err := Locate(filepath.Join(t.TempDir(), "no-such-fabric"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestRun(t *testing.T) {
+	c := &Client{Binary: script(t, "echo \"pattern=$2\"\ncat\n")}
+	out, err := c.Run(context.Background(), "summarize", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "pattern=summarize\nhello", out)
+}
+
+func TestRunFailureIncludesStderr(t *testing.T) {
+	c := &Client{Binary: script(t, "echo 'no such pattern' >&2\nexit 1\n")}
+	_, err := c.Run(context.Background(), "nope", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `fabric pattern "nope" failed`)
+	assert.Contains(t, err.Error(), "no such pattern")
+}
+
+func TestRunTimeout(t *testing.T) {
+	c := &Client{Binary: script(t, "exec sleep 5\n"), Timeout: 100 * time.Millisecond}
+	_, err := c.Run(context.Background(), "slow", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+}
+
+func TestListPatterns(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"summarize", "extract_wisdom"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "system.md"), []byte("# IDENTITY\n"), 0644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), nil, 0644))
+
+	patterns, err := ListPatterns(dir)
+	require.NoError(t, err)
+	require.Len(t, patterns, 2)
+	assert.Equal(t, "extract_wisdom", patterns[0].Name)
+	assert.Equal(t, "summarize", patterns[1].Name)
+}
+
+func TestListPatternsMissingDir(t *testing.T) {
+	_, err := ListPatterns(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestDescribe(t *testing.T) {
+	prompt := "# IDENTITY and PURPOSE\n\nYou are an expert content summarizer.\nYou take content in and output a summary. More text.\n\n# STEPS\n\n- Read it.\n"
+	assert.Equal(t, "You are an expert content summarizer.", describe(prompt))
+
+	assert.Equal(t, "", describe("# ONLY HEADINGS\n\n# HERE\n"))
+
+	long := strings.Repeat("word ", 40)
+	desc := describe(long)
+	assert.Len(t, []rune(desc), maxDescription)
+	assert.True(t, strings.HasSuffix(desc, "..."))
+}
+
+func TestExtensionConfigQuotesArgs(t *testing.T) {
+	data := string(ExtensionConfig("/opt/my tools/chronicle", "1.0", []string{"--config", "/home/me/my config.yaml"}))
+	assert.Contains(t, data, `executable: "/opt/my tools/chronicle"`)
+	assert.Contains(t, data, `{{executable}} --config '/home/me/my config.yaml' context --query {{value}}`)
+}
@@ -0,0 +1,214 @@
+// Chronicle gRPC service, served by chronicle grpc. The Go code in
+// chroniclepb is generated from this file (see generate.go); regenerate it
+// after any change here. Timestamps are Unix seconds.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: chronicle.proto
+
+package chroniclepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Chronicle_Capture_FullMethodName = "/chronicle.v1.Chronicle/Capture"
+	Chronicle_Search_FullMethodName  = "/chronicle.v1.Chronicle/Search"
+	Chronicle_Watch_FullMethodName   = "/chronicle.v1.Chronicle/Watch"
+)
+
+// ChronicleClient is the client API for Chronicle service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChronicleClient interface {
+	// Capture records one event and returns it with its assigned ID.
+	Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*Event, error)
+	// Search streams matching events.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// Watch streams events as they are captured, until the call is cancelled.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type chronicleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChronicleClient(cc grpc.ClientConnInterface) ChronicleClient {
+	return &chronicleClient{cc}
+}
+
+func (c *chronicleClient) Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, Chronicle_Capture_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chronicleClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Chronicle_ServiceDesc.Streams[0], Chronicle_Search_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chronicle_SearchClient = grpc.ServerStreamingClient[Event]
+
+func (c *chronicleClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Chronicle_ServiceDesc.Streams[1], Chronicle_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chronicle_WatchClient = grpc.ServerStreamingClient[Event]
+
+// ChronicleServer is the server API for Chronicle service.
+// All implementations must embed UnimplementedChronicleServer
+// for forward compatibility.
+type ChronicleServer interface {
+	// Capture records one event and returns it with its assigned ID.
+	Capture(context.Context, *CaptureRequest) (*Event, error)
+	// Search streams matching events.
+	Search(*SearchRequest, grpc.ServerStreamingServer[Event]) error
+	// Watch streams events as they are captured, until the call is cancelled.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedChronicleServer()
+}
+
+// UnimplementedChronicleServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChronicleServer struct{}
+
+func (UnimplementedChronicleServer) Capture(context.Context, *CaptureRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Capture not implemented")
+}
+func (UnimplementedChronicleServer) Search(*SearchRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedChronicleServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedChronicleServer) mustEmbedUnimplementedChronicleServer() {}
+func (UnimplementedChronicleServer) testEmbeddedByValue()                   {}
+
+// UnsafeChronicleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChronicleServer will
+// result in compilation errors.
+type UnsafeChronicleServer interface {
+	mustEmbedUnimplementedChronicleServer()
+}
+
+func RegisterChronicleServer(s grpc.ServiceRegistrar, srv ChronicleServer) {
+	// If the following call pancis, it indicates UnimplementedChronicleServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Chronicle_ServiceDesc, srv)
+}
+
+func _Chronicle_Capture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChronicleServer).Capture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chronicle_Capture_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChronicleServer).Capture(ctx, req.(*CaptureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chronicle_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChronicleServer).Search(m, &grpc.GenericServerStream[SearchRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chronicle_SearchServer = grpc.ServerStreamingServer[Event]
+
+func _Chronicle_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChronicleServer).Watch(m, &grpc.GenericServerStream[WatchRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chronicle_WatchServer = grpc.ServerStreamingServer[Event]
+
+// Chronicle_ServiceDesc is the grpc.ServiceDesc for Chronicle service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Chronicle_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chronicle.v1.Chronicle",
+	HandlerType: (*ChronicleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Capture",
+			Handler:    _Chronicle_Capture_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Search",
+			Handler:       _Chronicle_Search_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Chronicle_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "chronicle.proto",
+}
@@ -0,0 +1,5 @@
+package grpcapi
+
+// Regenerating chroniclepb needs protoc with protoc-gen-go v1.36.5 and
+// protoc-gen-go-grpc v1.5.1 on the PATH.
+//go:generate protoc --go_out=chroniclepb --go_opt=paths=source_relative --go-grpc_out=chroniclepb --go-grpc_opt=paths=source_relative chronicle.proto
@@ -0,0 +1,263 @@
+// Package grpcapi implements Chronicle's gRPC service (chronicle.proto):
+// Capture, streaming Search, and Watch. It runs on grpc-go, with the
+// message types and service stubs generated into chroniclepb.
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/grpcapi/chroniclepb"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Limits on streamed search results.
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 10000
+)
+
+// watchInterval is how often Watch polls the store for new events.
+var watchInterval = time.Second
+
+// Server serves the Chronicle gRPC service over a Store.
+type Server struct {
+	chroniclepb.UnimplementedChronicleServer
+
+	store    storage.Store
+	pipeline *capture.Pipeline // stores Capture's events
+	token    string
+}
+
+// NewServer creates a gRPC server. Every call must present token as a
+// bearer token in its authorization metadata.
+func NewServer(store storage.Store, token string) *Server {
+	return &Server{store: store, pipeline: capture.New(store), token: token}
+}
+
+// SetPipeline makes Capture store events through p, with its hooks and
+// webhooks, rather than a bare pipeline over the store.
+func (s *Server) SetPipeline(p *capture.Pipeline) {
+	s.pipeline = p
+}
+
+// GRPCServer returns a grpc.Server with the service registered and the
+// token checked on every call. opts are passed on, e.g. the transport
+// credentials.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := s.authorize(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.authorize(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	g := grpc.NewServer(opts...)
+	chroniclepb.RegisterChronicleServer(g, s)
+	return g
+}
+
+// authorize checks the call's bearer token.
+func (s *Server) authorize(ctx context.Context) error {
+	var got string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		got = strings.TrimPrefix(v[0], "Bearer ")
+	}
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+	return nil
+}
+
+// Capture stores one event through the capture pipeline.
+func (s *Server) Capture(ctx context.Context, req *chroniclepb.CaptureRequest) (*chroniclepb.Event, error) {
+	if req.GetUrl() == "" || req.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url and title are required")
+	}
+	if parsed, err := url.ParseRequestURI(req.GetUrl()); err != nil || parsed.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid URL: %s", req.GetUrl())
+	}
+
+	event := &storage.Event{
+		URL:       req.GetUrl(),
+		Title:     req.GetTitle(),
+		Browser:   req.GetBrowser(),
+		Source:    req.GetSource(),
+		Timestamp: unixTime(req.GetTimestamp()),
+		Meta: &storage.PageMeta{
+			OGTitle:       req.GetOgTitle(),
+			OGDescription: req.GetOgDescription(),
+			OGImage:       req.GetOgImage(),
+			Author:        req.GetAuthor(),
+			Published:     unixTime(req.GetPublished()),
+		},
+		CanonicalURL: req.GetCanonicalUrl(),
+	}
+	if event.Source == "" {
+		event.Source = "manual"
+	}
+
+	err := s.pipeline.Capture(ctx, event, req.GetBody())
+	switch {
+	case errors.Is(err, hook.ErrVetoed), errors.Is(err, capture.ErrExcluded):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrBodyTooLarge):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toEventMsg(event), nil
+}
+
+// Search streams the events matching req.
+func (s *Server) Search(req *chroniclepb.SearchRequest, stream grpc.ServerStreamingServer[chroniclepb.Event]) error {
+	q := storage.SearchQuery{
+		Query:   req.GetQuery(),
+		Domain:  req.GetDomain(),
+		Source:  req.GetSource(),
+		Browser: req.GetBrowser(),
+		Since:   unixTime(req.GetSince()),
+		Until:   unixTime(req.GetUntil()),
+		Limit:   int(req.GetLimit()),
+		Offset:  int(req.GetOffset()),
+	}
+	switch req.GetSort() {
+	case "", "relevance":
+	case storage.SortNewest, storage.SortOldest:
+		q.Sort = req.GetSort()
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid sort %q (want relevance, newest, or oldest)", req.GetSort())
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return status.Error(codes.InvalidArgument, "limit and offset must be non-negative")
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultSearchLimit
+	}
+	if q.Limit > maxSearchLimit {
+		q.Limit = maxSearchLimit
+	}
+
+	events, err := s.store.SearchEvents(stream.Context(), q)
+	if err != nil {
+		return status.Errorf(codes.Internal, "search failed: %v", err)
+	}
+	for i := range events {
+		if err := stream.Send(toEventMsg(&events[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch streams events timestamped after the call starts, polling the
+// store every watchInterval until the client cancels. The response headers
+// are sent once the watch has started. Timestamps have
+// one-second resolution, so the events in the cursor's second are
+// remembered to avoid sending them twice.
+func (s *Server) Watch(req *chroniclepb.WatchRequest, stream grpc.ServerStreamingServer[chroniclepb.Event]) error {
+	ctx := stream.Context()
+	q := storage.SearchQuery{
+		Domain: req.GetDomain(),
+		Source: req.GetSource(),
+		Since:  time.Now().Truncate(time.Second),
+		Sort:   storage.SortOldest,
+		Limit:  maxSearchLimit,
+	}
+	seen := map[string]bool{}
+
+	// Events already in the starting second predate the call.
+	existing, err := s.store.SearchEvents(ctx, q)
+	if err != nil {
+		return status.Errorf(codes.Internal, "watch: %v", err)
+	}
+	for _, e := range existing {
+		advance(&q.Since, seen, &e)
+	}
+	// The headers tell the client the watch has started: anything stored
+	// from now on is sent.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		events, err := s.store.SearchEvents(ctx, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return status.Errorf(codes.Internal, "watch: %v", err)
+		}
+		for i := range events {
+			e := &events[i]
+			if seen[e.ID] {
+				continue
+			}
+			advance(&q.Since, seen, e)
+			if err := stream.Send(toEventMsg(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// advance moves the watch cursor to e and records it as sent. seen only
+// holds events at the cursor's second.
+func advance(cursor *time.Time, seen map[string]bool, e *storage.Event) {
+	if e.Timestamp.After(*cursor) {
+		*cursor = e.Timestamp
+		clear(seen)
+	}
+	seen[e.ID] = true
+}
+
+// toEventMsg converts e to its message.
+func toEventMsg(e *storage.Event) *chroniclepb.Event {
+	return &chroniclepb.Event{
+		Id:           e.ID,
+		Url:          e.URL,
+		Title:        e.Title,
+		Domain:       e.Domain,
+		Timestamp:    e.Timestamp.Unix(),
+		Source:       e.Source,
+		Browser:      e.Browser,
+		HasBody:      e.HasBody,
+		HasEmbedding: e.HasEmbed,
+		CanonicalUrl: e.CanonicalURL,
+	}
+}
+
+// unixTime converts a Unix-seconds field to a time, with 0 as the zero time.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
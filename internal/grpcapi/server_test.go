@@ -0,0 +1,164 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/runnerr0/chronicle/internal/grpcapi/chroniclepb"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+const testToken = "secret-token"
+
+// setupServer creates a migrated in-memory store, serves the service in
+// front of it on a local port, and returns a client connected to it.
+func setupServer(t *testing.T) (chroniclepb.ChronicleClient, *storage.SQLiteStore) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := NewServer(store, testToken).GRPCServer()
+	go srv.Serve(ln) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return chroniclepb.NewChronicleClient(conn), store
+}
+
+// withToken returns ctx carrying token as the call's bearer token.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// receive reads a response stream to its end and returns its events and
+// final status code.
+func receive(stream grpc.ServerStreamingClient[chroniclepb.Event], err error) ([]*chroniclepb.Event, codes.Code) {
+	if err != nil {
+		return nil, status.Code(err)
+	}
+	var events []*chroniclepb.Event
+	for {
+		e, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return events, codes.OK
+		}
+		if err != nil {
+			return events, status.Code(err)
+		}
+		events = append(events, e)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	client, store := setupServer(t)
+	ctx := withToken(context.Background(), testToken)
+
+	got, err := client.Capture(ctx, &chroniclepb.CaptureRequest{Url: "https://go.dev/blog", Title: "Go Blog", Body: "blog body", Browser: "firefox", Timestamp: 1767225600})
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.GetId())
+	assert.Equal(t, "go.dev", got.GetDomain())
+	assert.Equal(t, "manual", got.GetSource())
+	assert.Equal(t, int64(1767225600), got.GetTimestamp())
+	assert.True(t, got.GetHasBody())
+
+	content, err := store.GetContent(ctx, got.GetId())
+	require.NoError(t, err)
+	assert.Equal(t, "blog body", content.Body)
+	meta, err := store.GetPageMeta(ctx, got.GetId())
+	require.NoError(t, err)
+	assert.Nil(t, meta, "no metadata sent")
+
+	got, err = client.Capture(ctx, &chroniclepb.CaptureRequest{Url: "https://go.dev/blog/loopvar?utm_source=feed", Title: "Fixing For Loops", Author: "David Chase", OgImage: "https://go.dev/images/go-logo.png", Published: 1695081600, CanonicalUrl: "/blog/loopvar"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/blog/loopvar", got.GetCanonicalUrl(), "resolved against the page URL")
+	meta, err = store.GetPageMeta(ctx, got.GetId())
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	assert.Equal(t, "David Chase", meta.Author)
+	assert.Equal(t, "https://go.dev/images/go-logo.png", meta.OGImage)
+	assert.Equal(t, int64(1695081600), meta.Published.Unix())
+
+	_, err = client.Capture(ctx, &chroniclepb.CaptureRequest{Url: "nope", Title: "x"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "invalid URL")
+
+	_, err = client.Capture(ctx, &chroniclepb.CaptureRequest{Url: "https://chase.com/accounts", Title: "Accounts"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestSearch_Streams(t *testing.T) {
+	client, store := setupServer(t)
+	ctx := withToken(context.Background(), testToken)
+
+	now := time.Now()
+	for i, u := range []string{"https://go.dev/a", "https://go.dev/b", "https://sqlite.org/c"} {
+		require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: u, Title: "Page " + u, Source: "extension", Timestamp: now.Add(-time.Duration(i) * time.Hour)}))
+	}
+
+	got, code := receive(client.Search(ctx, &chroniclepb.SearchRequest{Domain: "go.dev", Sort: "oldest"}))
+	require.Equal(t, codes.OK, code)
+	require.Len(t, got, 2)
+	assert.Equal(t, "https://go.dev/b", got[0].GetUrl())
+	assert.Equal(t, "https://go.dev/a", got[1].GetUrl())
+
+	_, code = receive(client.Search(ctx, &chroniclepb.SearchRequest{Sort: "sideways"}))
+	assert.Equal(t, codes.InvalidArgument, code)
+}
+
+func TestWatch(t *testing.T) {
+	old := watchInterval
+	watchInterval = 20 * time.Millisecond
+	t.Cleanup(func() { watchInterval = old })
+
+	client, store := setupServer(t)
+	ctx := context.Background()
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://go.dev/old", Title: "Old", Source: "extension"}))
+
+	watchCtx, cancel := context.WithTimeout(withToken(ctx, testToken), 500*time.Millisecond)
+	defer cancel()
+	stream, err := client.Watch(watchCtx, &chroniclepb.WatchRequest{Domain: "go.dev"})
+	require.NoError(t, err)
+	_, err = stream.Header() // the watch has started
+	require.NoError(t, err)
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://sqlite.org/skip", Title: "Other domain", Source: "extension", Timestamp: time.Now().Add(time.Second)}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://go.dev/new", Title: "New", Source: "extension", Timestamp: time.Now().Add(time.Second)}))
+	got, _ := receive(stream, nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, "https://go.dev/new", got[0].GetUrl())
+}
+
+func TestAuth(t *testing.T) {
+	client, _ := setupServer(t)
+	ctx := context.Background()
+
+	_, code := receive(client.Search(ctx, &chroniclepb.SearchRequest{}))
+	assert.Equal(t, codes.Unauthenticated, code)
+	_, code = receive(client.Search(withToken(ctx, "wrong"), &chroniclepb.SearchRequest{}))
+	assert.Equal(t, codes.Unauthenticated, code)
+	_, err := client.Capture(ctx, &chroniclepb.CaptureRequest{Url: "https://go.dev/", Title: "Go"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,153 @@
+// Package hook runs user executables around captures, as an extension
+// point that needs no fork. Each hook reads the event as JSON on stdin.
+// A pre-capture hook runs before the event is stored: exiting non-zero
+// vetoes the capture, and printing the event JSON back, changed, rewrites
+// it. A post-capture hook runs once the event is stored, e.g. to forward
+// it elsewhere; its output is ignored.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// ErrVetoed is returned, wrapped with the hook's reason, when a
+// pre-capture hook rejects an event.
+var ErrVetoed = errors.New("vetoed by hook")
+
+// defaultTimeout applies when hooks.timeout_seconds is unset.
+const defaultTimeout = 10 * time.Second
+
+// Event is the JSON a hook reads on stdin, and a pre-capture hook may
+// print back. Post-capture hooks also get the stored event's ID and domain.
+type Event struct {
+	ID        string `json:"id,omitempty"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Domain    string `json:"domain,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	Browser   string `json:"browser,omitempty"`
+	Body      string `json:"body,omitempty"`
+}
+
+// Runner runs the configured hooks.
+type Runner struct {
+	pre, post []string
+	timeout   time.Duration
+}
+
+// New returns a Runner for the hooks config. Empty entries are ignored.
+func New(cfg config.HooksConfig) *Runner {
+	r := &Runner{timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}
+	if r.timeout <= 0 {
+		r.timeout = defaultTimeout
+	}
+	for _, h := range cfg.PreCapture {
+		if h = strings.TrimSpace(h); h != "" {
+			r.pre = append(r.pre, h)
+		}
+	}
+	for _, h := range cfg.PostCapture {
+		if h = strings.TrimSpace(h); h != "" {
+			r.post = append(r.post, h)
+		}
+	}
+	return r
+}
+
+// Pre runs the pre-capture hooks in order on e and its body, each seeing
+// the previous one's changes. A hook that prints nothing leaves the event
+// as it was; one that prints JSON replaces its URL, title, browser and
+// body. Any failure stops the capture: a non-zero exit wraps ErrVetoed
+// with the hook's stderr as the reason, and a hook that cannot run, times
+// out or prints invalid JSON is an error too, so a broken filter never
+// lets events through.
+func (r *Runner) Pre(ctx context.Context, e *storage.Event, body *string) error {
+	for _, h := range r.pre {
+		out, err := r.run(ctx, h, toJSON(e, *body))
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%w %w", ErrVetoed, err)
+		}
+		if err != nil {
+			return fmt.Errorf("pre-capture hook %w", err)
+		}
+		if len(bytes.TrimSpace(out)) == 0 {
+			continue
+		}
+		var changed Event
+		if err := json.Unmarshal(out, &changed); err != nil {
+			return fmt.Errorf("pre-capture hook %s: invalid event JSON: %w", h, err)
+		}
+		if changed.URL == "" {
+			return fmt.Errorf("pre-capture hook %s: event JSON has no url", h)
+		}
+		e.URL, e.Title, e.Browser, *body = changed.URL, changed.Title, changed.Browser, changed.Body
+	}
+	return nil
+}
+
+// Post runs the post-capture hooks on the stored event e. The event is
+// stored either way, so failures are returned for reporting, one per
+// failed hook.
+func (r *Runner) Post(ctx context.Context, e *storage.Event, body string) []error {
+	var errs []error
+	for _, h := range r.post {
+		if _, err := r.run(ctx, h, toJSON(e, body)); err != nil {
+			errs = append(errs, fmt.Errorf("post-capture hook %w", err))
+		}
+	}
+	return errs
+}
+
+// run executes hook with in on stdin and returns its stdout. Errors start
+// with the hook's path; a non-zero exit wraps *exec.ExitError with the
+// hook's stderr.
+func (r *Runner) run(ctx context.Context, hook string, in []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook)
+	cmd.Stdin = bytes.NewReader(in)
+	// Don't wait on children that outlive a killed hook and hold its output.
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %s", hook, r.timeout)
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return nil, fmt.Errorf("%s: %w: %s", hook, err, msg)
+	}
+	return nil, fmt.Errorf("%s: %w", hook, err)
+}
+
+func toJSON(e *storage.Event, body string) []byte {
+	data, _ := json.Marshal(Event{
+		ID:        e.ID,
+		URL:       e.URL,
+		Title:     e.Title,
+		Domain:    e.Domain,
+		Timestamp: e.Timestamp.UTC().Format(time.RFC3339),
+		Source:    e.Source,
+		Browser:   e.Browser,
+		Body:      body,
+	})
+	return data
+}
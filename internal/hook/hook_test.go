@@ -0,0 +1,90 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// script writes an executable shell script into a temp dir.
+func script(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755))
+	return path
+}
+
+func testEvent() *storage.Event {
+	return &storage.Event{
+		URL:       "https://example.com/a",
+		Title:     "A",
+		Source:    "manual",
+		Timestamp: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPre_RewritesEvent(t *testing.T) {
+	rewrite := script(t, `sed -e 's/"title":"A"/"title":"Rewritten"/' -e 's/"body":"secret"/"body":"[redacted]"/'`+"\n")
+	silent := script(t, "cat >/dev/null\n")
+	r := New(config.HooksConfig{PreCapture: []string{silent, rewrite, " "}})
+
+	e, body := testEvent(), "secret"
+	require.NoError(t, r.Pre(context.Background(), e, &body))
+	assert.Equal(t, "Rewritten", e.Title)
+	assert.Equal(t, "https://example.com/a", e.URL)
+	assert.Equal(t, "[redacted]", body)
+}
+
+func TestPre_Veto(t *testing.T) {
+	veto := script(t, "echo 'no tracking pages' >&2\nexit 1\n")
+	never := filepath.Join(t.TempDir(), "ran")
+	after := script(t, "touch "+never+"\n")
+	r := New(config.HooksConfig{PreCapture: []string{veto, after}})
+
+	body := ""
+	err := r.Pre(context.Background(), testEvent(), &body)
+	require.ErrorIs(t, err, ErrVetoed)
+	assert.Contains(t, err.Error(), "no tracking pages")
+	assert.NoFileExists(t, never, "later hooks don't run after a veto")
+}
+
+func TestPre_Errors(t *testing.T) {
+	body := ""
+	bad := New(config.HooksConfig{PreCapture: []string{script(t, "echo not json\n")}})
+	err := bad.Pre(context.Background(), testEvent(), &body)
+	assert.ErrorContains(t, err, "invalid event JSON")
+	assert.NotErrorIs(t, err, ErrVetoed)
+
+	missing := New(config.HooksConfig{PreCapture: []string{filepath.Join(t.TempDir(), "nope")}})
+	assert.Error(t, missing.Pre(context.Background(), testEvent(), &body))
+
+	slow := New(config.HooksConfig{PreCapture: []string{script(t, "sleep 5\n")}, TimeoutSeconds: 1})
+	assert.ErrorContains(t, slow.Pre(context.Background(), testEvent(), &body), "timed out after 1s")
+}
+
+func TestPost_ForwardsEvent(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event.json")
+	forward := script(t, "cat > "+out+"\n")
+	failing := script(t, "exit 2\n")
+	r := New(config.HooksConfig{PostCapture: []string{forward, failing}})
+
+	e := testEvent()
+	e.ID, e.Domain = "CHR-1", "example.com"
+	errs := r.Post(context.Background(), e, "text")
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "post-capture hook")
+	assert.NotErrorIs(t, errs[0], ErrVetoed)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"CHR-1","url":"https://example.com/a","title":"A","domain":"example.com",
+		"timestamp":"2026-03-02T09:00:00Z","source":"manual","body":"text"}`, string(data))
+}
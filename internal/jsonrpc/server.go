@@ -0,0 +1,168 @@
+// Package jsonrpc serves JSON-RPC 2.0 over a stream of newline-delimited
+// messages, the transport of Chronicle's RPC and MCP servers. What each
+// method does is up to the Handler.
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes. Codes from -32000 to -32099 are reserved for
+// implementation-defined server errors.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// maxMessage is the largest message Serve reads.
+const maxMessage = 16 * 1024 * 1024
+
+// Request is an incoming JSON-RPC 2.0 message. Notifications have no ID.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is an outgoing JSON-RPC 2.0 message.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errorf returns an Error with code and a formatted message.
+func Errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Handler runs method with its raw params and returns the result, or the
+// error to answer with.
+type Handler func(ctx context.Context, method string, params json.RawMessage) (interface{}, *Error)
+
+// Server answers JSON-RPC requests read line-by-line from an input stream.
+type Server struct {
+	handler Handler
+
+	mu  sync.Mutex // guards out
+	out io.Writer
+}
+
+// NewServer creates a server that answers requests with handler.
+func NewServer(handler Handler) *Server {
+	return &Server{handler: handler}
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled. Batches (JSON
+// arrays of requests) are answered with an array of responses.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] == '[' {
+			s.handleBatch(ctx, line)
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.write(errorResponse(json.RawMessage("null"), CodeParseError, "parse error"))
+			continue
+		}
+		if resp := s.handle(ctx, req); resp != nil {
+			s.write(resp)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// handleBatch answers a batch of requests with one array of responses.
+// A batch of only notifications gets no reply.
+func (s *Server) handleBatch(ctx context.Context, line []byte) {
+	var reqs []Request
+	if err := json.Unmarshal(line, &reqs); err != nil {
+		s.write(errorResponse(json.RawMessage("null"), CodeParseError, "parse error"))
+		return
+	}
+	if len(reqs) == 0 {
+		s.write(errorResponse(json.RawMessage("null"), CodeInvalidRequest, "empty batch"))
+		return
+	}
+
+	var out []*Response
+	for _, req := range reqs {
+		if resp := s.handle(ctx, req); resp != nil {
+			out = append(out, resp)
+		}
+	}
+	if len(out) > 0 {
+		s.write(out)
+	}
+}
+
+// handle runs a single request and returns its response, or nil for a
+// notification.
+func (s *Server) handle(ctx context.Context, req Request) *Response {
+	isNotification := len(req.ID) == 0
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if isNotification {
+			return nil
+		}
+		return errorResponse(req.ID, CodeInvalidRequest, "invalid request")
+	}
+
+	result, err := s.handler(ctx, req.Method, req.Params)
+	if isNotification {
+		return nil
+	}
+	if err != nil {
+		return errorResponse(req.ID, err.Code, err.Message)
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: msg}}
+}
+
+func (s *Server) write(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(errorResponse(json.RawMessage("null"), CodeInternalError, "marshal response"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out.Write(append(data, '\n')) //nolint:errcheck
+}
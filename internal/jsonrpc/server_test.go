@@ -0,0 +1,76 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echo answers "echo" with its params and counts every call.
+type echo struct{ calls int }
+
+func (e *echo) handle(ctx context.Context, method string, params json.RawMessage) (interface{}, *Error) {
+	e.calls++
+	if method != "echo" {
+		return nil, Errorf(CodeMethodNotFound, "method not found: %s", method)
+	}
+	return params, nil
+}
+
+// serve feeds lines to a server and returns each response line.
+func serve(t *testing.T, h Handler, lines ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, NewServer(h).Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out))
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestServe(t *testing.T) {
+	e := &echo{}
+	out := serve(t, e.handle,
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"q":"go"}}`,
+		`{"jsonrpc":"2.0","method":"echo"}`, // notification
+		`{"jsonrpc":"2.0","method":"nope"}`, // failing notification
+		``,
+		`{"jsonrpc":"2.0","id":"a","method":"nope"}`,
+		`{not json`,
+		`{"id":2,"method":"echo"}`,
+	)
+	assert.Equal(t, []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"q":"go"}}`,
+		`{"jsonrpc":"2.0","id":"a","error":{"code":-32601,"message":"method not found: nope"}}`,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`,
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32600,"message":"invalid request"}}`,
+	}, out)
+	assert.Equal(t, 4, e.calls)
+}
+
+func TestServe_Batch(t *testing.T) {
+	e := &echo{}
+	out := serve(t, e.handle,
+		`[{"jsonrpc":"2.0","id":1,"method":"echo","params":1},{"jsonrpc":"2.0","method":"echo"},{"jsonrpc":"2.0","id":2,"method":"nope"}]`,
+		`[{"jsonrpc":"2.0","method":"echo"}]`,
+		`[]`,
+	)
+	require.Len(t, out, 2)
+	var resps []Response
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &resps))
+	require.Len(t, resps, 2)
+	assert.JSONEq(t, "1", string(resps[0].ID))
+	assert.Nil(t, resps[0].Error)
+	assert.Equal(t, &Error{Code: CodeMethodNotFound, Message: "method not found: nope"}, resps[1].Error)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}`, out[1])
+}
+
+func TestServe_LargeMessage(t *testing.T) {
+	e := &echo{}
+	big := strings.Repeat("x", 1<<20)
+	out := serve(t, e.handle, `{"jsonrpc":"2.0","id":1,"method":"echo","params":"`+big+`"}`)
+	require.Len(t, out, 1)
+	assert.Contains(t, out[0], big)
+}
@@ -0,0 +1,61 @@
+// Package keychain reads secrets from the operating system's credential
+// store using the platform's own tool: security on macOS and secret-tool
+// (libsecret) on Linux and the BSDs.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// run executes the keychain command and returns its output; tests replace
+// it.
+var run = func(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			if msg := strings.TrimSpace(string(exit.Stderr)); msg != "" {
+				return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+			}
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// Lookup returns the secret stored for service: the password of the
+// generic password item with that service name on macOS (security
+// add-generic-password -s service -a chronicle -w), or the secret with
+// attribute service=service elsewhere (secret-tool store --label=chronicle
+// service service).
+func Lookup(service string) (string, error) {
+	name, args, err := command(runtime.GOOS, service)
+	if err != nil {
+		return "", err
+	}
+	out, err := run(name, args...)
+	if err != nil {
+		return "", fmt.Errorf("keychain item %q: %w", service, err)
+	}
+	secret := strings.TrimRight(out, "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("keychain item %q is empty", service)
+	}
+	return secret, nil
+}
+
+// command returns the command that prints the secret for service on goos.
+func command(goos, service string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "security", []string{"find-generic-password", "-s", service, "-w"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "secret-tool", []string{"lookup", "service", service}, nil
+	default:
+		return "", nil, fmt.Errorf("reading the keychain is not supported on %s", goos)
+	}
+}
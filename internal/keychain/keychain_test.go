@@ -0,0 +1,52 @@
+package keychain
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("darwin", "chronicle")
+	require.NoError(t, err)
+	assert.Equal(t, "security", name)
+	assert.Equal(t, []string{"find-generic-password", "-s", "chronicle", "-w"}, args)
+
+	name, args, err = command("linux", "chronicle")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-tool", name)
+	assert.Equal(t, []string{"lookup", "service", "chronicle"}, args)
+
+	_, _, err = command("windows", "chronicle")
+	assert.EqualError(t, err, "reading the keychain is not supported on windows")
+}
+
+func TestLookup(t *testing.T) {
+	if _, _, err := command(runtime.GOOS, "x"); err != nil {
+		t.Skip(err)
+	}
+	old := run
+	t.Cleanup(func() { run = old })
+
+	run = func(name string, args ...string) (string, error) {
+		return "correct horse\n", nil
+	}
+	secret, err := Lookup("chronicle")
+	require.NoError(t, err)
+	assert.Equal(t, "correct horse", secret)
+
+	run = func(name string, args ...string) (string, error) {
+		return "\n", nil
+	}
+	_, err = Lookup("chronicle")
+	assert.EqualError(t, err, `keychain item "chronicle" is empty`)
+
+	run = func(name string, args ...string) (string, error) {
+		return "", errors.New("exit status 44")
+	}
+	_, err = Lookup("chronicle")
+	assert.EqualError(t, err, `keychain item "chronicle": exit status 44`)
+}
@@ -0,0 +1,134 @@
+// Package logging builds Chronicle's slog logger from the logging config.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+// ParseLevel parses a logging.level value: debug, info, warn (or warning)
+// or error. Empty means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn, or error)", s)
+	}
+}
+
+// New returns a logger writing to w at level, formatted as text (the
+// default) or json.
+func New(w io.Writer, format string, level slog.Leveler) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (want text or json)", format)
+	}
+}
+
+// Path resolves cfg.File against dir, the database directory. It returns
+// "" when file logging is disabled.
+func Path(cfg config.LoggingConfig, dir string) string {
+	switch {
+	case cfg.File == "", cfg.File == "stderr":
+		return cfg.File
+	case filepath.IsAbs(cfg.File):
+		return cfg.File
+	default:
+		return filepath.Join(dir, cfg.File)
+	}
+}
+
+// Open returns a logger configured by cfg, writing to cfg.File under dir.
+// The file is created on the first log record, so commands that log
+// nothing leave no file behind, and rotated as cfg.MaxSize and
+// cfg.MaxBackups direct. Close the returned io.Closer when done.
+func Open(cfg config.LoggingConfig, dir string) (*slog.Logger, io.Closer, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var w io.WriteCloser
+	switch path := Path(cfg, dir); path {
+	case "":
+		w = nopCloser{io.Discard}
+	case "stderr":
+		w = nopCloser{os.Stderr}
+	default:
+		w = &rotatingFile{path: path, maxSize: int64(cfg.MaxSize), maxBackups: cfg.MaxBackups, now: time.Now}
+	}
+
+	logger, err := New(w, cfg.Format, level)
+	if err != nil {
+		return nil, nil, err
+	}
+	return logger, w, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// Tee returns a handler passing each record to every one of handlers that
+// is enabled for its level.
+func Tee(handlers ...slog.Handler) slog.Handler {
+	return teeHandler(handlers)
+}
+
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
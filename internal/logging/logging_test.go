@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+)
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	} {
+		got, err := ParseLevel(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseLevel("loud")
+	assert.ErrorContains(t, err, "invalid log level")
+}
+
+func TestNew_Formats(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", slog.LevelInfo)
+	require.NoError(t, err)
+	logger.Debug("hidden")
+	logger.Info("pruned events", "count", 3)
+
+	var rec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "pruned events", rec["msg"])
+	assert.Equal(t, float64(3), rec["count"])
+
+	buf.Reset()
+	logger, err = New(&buf, "", slog.LevelInfo)
+	require.NoError(t, err)
+	logger.Info("pruned events", "count", 3)
+	assert.Contains(t, buf.String(), `msg="pruned events" count=3`)
+
+	_, err = New(&buf, "xml", slog.LevelInfo)
+	assert.ErrorContains(t, err, "invalid log format")
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/data", "chronicle.log"), Path(config.LoggingConfig{File: "chronicle.log"}, "/data"))
+	assert.Equal(t, "/var/log/chronicle.log", Path(config.LoggingConfig{File: "/var/log/chronicle.log"}, "/data"))
+	assert.Equal(t, "stderr", Path(config.LoggingConfig{File: "stderr"}, "/data"))
+	assert.Equal(t, "", Path(config.LoggingConfig{}, "/data"))
+}
+
+func TestOpen_CreatesFileOnFirstRecord(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	logger, closer, err := Open(config.LoggingConfig{Level: "warn", File: "chronicle.log"}, dir)
+	require.NoError(t, err)
+
+	logger.Info("below the level")
+	require.NoError(t, closer.Close())
+	_, err = os.Stat(filepath.Join(dir, "chronicle.log"))
+	assert.True(t, os.IsNotExist(err), "nothing logged, so no file")
+
+	logger.Warn("snapshot failed", "err", "boom")
+	require.NoError(t, closer.Close())
+	data, err := os.ReadFile(filepath.Join(dir, "chronicle.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `level=WARN msg="snapshot failed" err=boom`)
+}
+
+func TestOpen_InvalidConfig(t *testing.T) {
+	_, _, err := Open(config.LoggingConfig{Level: "chatty"}, t.TempDir())
+	assert.Error(t, err)
+	_, _, err = Open(config.LoggingConfig{Format: "yaml"}, t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestTee(t *testing.T) {
+	var info, debug bytes.Buffer
+	logger := slog.New(Tee(
+		slog.NewTextHandler(&info, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)).With("command", "add")
+
+	logger.Debug("resolved database", "path", "/data/chronicle.db")
+	logger.Info("pruned events", "count", 3)
+
+	assert.NotContains(t, info.String(), "resolved database")
+	assert.Contains(t, info.String(), `msg="pruned events" command=add count=3`)
+	assert.Contains(t, debug.String(), `msg="resolved database" command=add path=/data/chronicle.db`)
+	assert.Contains(t, debug.String(), `msg="pruned events" command=add count=3`)
+}
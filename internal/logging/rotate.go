@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files, e.g. chronicle-20260301T120000.000.log.gz.
+// It sorts chronologically.
+const backupTimeFormat = "20060102T150405.000"
+
+// rotatingFile appends to path, creating it and its directory on first
+// write. Once a write would take the file past maxSize bytes, the file is
+// renamed with a timestamp, gzipped, and a new one started; only the newest
+// maxBackups rotated files are kept. maxSize <= 0 disables rotation and
+// maxBackups <= 0 keeps every backup.
+//
+// Renaming rather than renumbering backups keeps rotation safe when several
+// chronicle processes share a log: a process still holding the old file
+// just finishes writing into the backup.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	now        func() time.Time
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	err  error
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil && r.err == nil {
+		r.err = r.open()
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// open opens (or creates) the log for appending and notes its size.
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// rotate moves the current file aside, compresses it, removes backups
+// beyond maxBackups and starts a new file. Failing to compress or clean up
+// leaves extra files behind but does not stop logging.
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	r.f = nil
+
+	dir, prefix, ext := r.parts()
+	backup := filepath.Join(dir, prefix+r.now().UTC().Format(backupTimeFormat)+ext)
+	if err := os.Rename(r.path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	if err := compress(backup); err == nil {
+		os.Remove(backup) //nolint:errcheck
+	}
+	r.removeOldBackups()
+	return nil
+}
+
+// parts splits path into its directory, the backup name prefix and the
+// extension: /d/chronicle.log gives /d, "chronicle-" and ".log".
+func (r *rotatingFile) parts() (dir, prefix, ext string) {
+	dir, name := filepath.Split(r.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backups lists rotated files, oldest first.
+func (r *rotatingFile) backups() []string {
+	dir, prefix, ext := r.parts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			names = append(names, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *rotatingFile) removeOldBackups() {
+	if r.maxBackups <= 0 {
+		return
+	}
+	names := r.backups()
+	for len(names) > r.maxBackups {
+		os.Remove(names[0]) //nolint:errcheck
+		names = names[1:]
+	}
+}
+
+// compress writes path.gz from path.
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz") //nolint:errcheck
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz") //nolint:errcheck
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRotatingFile returns a rotatingFile in a temp dir whose clock
+// advances a second per call.
+func testRotatingFile(t *testing.T, maxSize int64, maxBackups int) *rotatingFile {
+	t.Helper()
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &rotatingFile{
+		path:       filepath.Join(t.TempDir(), "chronicle.log"),
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		now: func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		},
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRotatingFile_RotatesAndCompresses(t *testing.T) {
+	r := testRotatingFile(t, 20, 0)
+
+	for _, line := range []string{"first line\n", "second line\n", "third line\n"} {
+		_, err := r.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	current, err := os.ReadFile(r.path)
+	require.NoError(t, err)
+	assert.Equal(t, "third line\n", string(current))
+
+	backups := r.backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, "chronicle-20260301T120001.000.log.gz", filepath.Base(backups[0]))
+	assert.Equal(t, "first line\n", readGzip(t, backups[0]))
+	assert.Equal(t, "second line\n", readGzip(t, backups[1]))
+
+	// Only compressed backups remain.
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.True(t, e.Name() == "chronicle.log" || strings.HasSuffix(e.Name(), ".gz"), e.Name())
+	}
+}
+
+func TestRotatingFile_KeepsMaxBackups(t *testing.T) {
+	r := testRotatingFile(t, 10, 2)
+
+	for i := 0; i < 5; i++ {
+		_, err := r.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+
+	backups := r.backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, "chronicle-20260301T120003.000.log.gz", filepath.Base(backups[0]))
+	assert.Equal(t, "chronicle-20260301T120004.000.log.gz", filepath.Base(backups[1]))
+}
+
+func TestRotatingFile_CountsExistingSize(t *testing.T) {
+	r := testRotatingFile(t, 20, 0)
+	require.NoError(t, os.WriteFile(r.path, []byte("from an earlier run\n"), 0o600))
+
+	_, err := r.Write([]byte("new\n"))
+	require.NoError(t, err)
+
+	require.Len(t, r.backups(), 1)
+	assert.Equal(t, "from an earlier run\n", readGzip(t, r.backups()[0]))
+	current, err := os.ReadFile(r.path)
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(current))
+}
+
+func TestRotatingFile_NoRotationWithoutMaxSize(t *testing.T) {
+	r := testRotatingFile(t, 0, 1)
+	for i := 0; i < 100; i++ {
+		_, err := r.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+	assert.Empty(t, r.backups())
+}
+
+func TestRotatingFile_IgnoresUnrelatedFiles(t *testing.T) {
+	r := testRotatingFile(t, 10, 1)
+	dir := filepath.Dir(r.path)
+	for _, name := range []string{"chronicle.db", "chronicle-notes.log", "other-20260301T120000.000.log.gz"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := r.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+	assert.Len(t, r.backups(), 1)
+	for _, name := range []string{"chronicle.db", "chronicle-notes.log", "other-20260301T120000.000.log.gz"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+}
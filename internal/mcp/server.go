@@ -0,0 +1,97 @@
+// Package mcp implements a Model Context Protocol server over stdio that
+// exposes Chronicle history to agents as tools.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/jsonrpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// ProtocolVersion is the MCP protocol revision this server implements.
+const ProtocolVersion = "2024-11-05"
+
+// Server answers MCP requests read line-by-line from an input stream.
+type Server struct {
+	tools   *toolSet
+	version string
+}
+
+// NewServer creates an MCP server whose tools operate on store.
+func NewServer(store storage.Store, version string) *Server {
+	return &Server{
+		tools:   newToolSet(store),
+		version: version,
+	}
+}
+
+// SetPipeline makes add_note capture through p, with its hooks and
+// webhooks, rather than a bare pipeline over the store.
+func (s *Server) SetPipeline(p *capture.Pipeline) {
+	s.tools.pipeline = p
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	return jsonrpc.NewServer(s.call).Serve(ctx, r, w)
+}
+
+// call runs an MCP method.
+func (s *Server) call(ctx context.Context, method string, params json.RawMessage) (interface{}, *jsonrpc.Error) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    "chronicle",
+				"version": s.version,
+			},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools.list()}, nil
+	case "tools/call":
+		return s.callTool(ctx, params)
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeMethodNotFound, "method not found: %s", method)
+	}
+}
+
+// callTool decodes tools/call params and runs the named tool. Tool failures
+// are reported in-band with isError so the agent can see them.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil || params.Name == "" {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "tools/call requires a tool name")
+	}
+
+	text, err := s.tools.call(ctx, params.Name, params.Arguments)
+	if err == errUnknownTool {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "unknown tool: %s", params.Name)
+	}
+	if err != nil {
+		return toolResult(err.Error(), true), nil
+	}
+	return toolResult(text, false), nil
+}
+
+func toolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": text},
+		},
+		"isError": isError,
+	}
+}
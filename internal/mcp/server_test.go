@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/jsonrpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// openTestStore creates a migrated in-memory Store for testing.
+func openTestStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+// roundtrip feeds the given request lines to a server and decodes every
+// response line.
+func roundtrip(t *testing.T, store storage.Store, lines ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	srv := NewServer(store, "test")
+	require.NoError(t, srv.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out))
+
+	var responses []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &resp), line)
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+// toolText extracts the text content and isError flag from a tools/call response.
+func toolText(t *testing.T, resp map[string]interface{}) (string, bool) {
+	t.Helper()
+	result, ok := resp["result"].(map[string]interface{})
+	require.True(t, ok, "response should have a result: %v", resp)
+	content := result["content"].([]interface{})
+	require.Len(t, content, 1)
+	return content[0].(map[string]interface{})["text"].(string), result["isError"].(bool)
+}
+
+func TestServer_Initialize(t *testing.T) {
+	resps := roundtrip(t, openTestStore(t),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+
+	require.Len(t, resps, 1, "notifications must not get a response")
+	result := resps[0]["result"].(map[string]interface{})
+	assert.Equal(t, ProtocolVersion, result["protocolVersion"])
+	assert.Equal(t, "chronicle", result["serverInfo"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(1), resps[0]["id"])
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	resps := roundtrip(t, openTestStore(t), `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+
+	require.Len(t, resps, 1)
+	tools := resps[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	var names []string
+	for _, tl := range tools {
+		names = append(names, tl.(map[string]interface{})["name"].(string))
+	}
+	assert.ElementsMatch(t, []string{"search_history", "get_event", "add_note"}, names)
+}
+
+func TestServer_SearchHistory(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://go.dev/doc", Title: "Go documentation", Source: "extension", Timestamp: time.Now()}))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://rust-lang.org", Title: "Rust", Source: "extension", Timestamp: time.Now()}))
+
+	resps := roundtrip(t, store,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_history","arguments":{"query":"documentation"}}}`,
+	)
+
+	text, isErr := toolText(t, resps[0])
+	assert.False(t, isErr)
+	var events []eventJSON
+	require.NoError(t, json.Unmarshal([]byte(text), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "Go documentation", events[0].Title)
+}
+
+func TestServer_GetEventIncludesBody(t *testing.T) {
+	store := openTestStore(t)
+	event := &storage.Event{URL: "https://example.com/a", Title: "A", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(context.Background(), event, "stored body"))
+
+	resps := roundtrip(t, store,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_event","arguments":{"id":"`+event.ID+`"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"get_event","arguments":{"id":"CHR-missing"}}}`,
+	)
+
+	text, isErr := toolText(t, resps[0])
+	assert.False(t, isErr)
+	var got eventJSON
+	require.NoError(t, json.Unmarshal([]byte(text), &got))
+	assert.Equal(t, "stored body", got.Body)
+
+	text, isErr = toolText(t, resps[1])
+	assert.True(t, isErr)
+	assert.Contains(t, text, "not found")
+}
+
+func TestServer_AddNote(t *testing.T) {
+	store := openTestStore(t)
+
+	resps := roundtrip(t, store,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"add_note","arguments":{"url":"https://example.com/x","title":"Agent note","body":"remember this"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"add_note","arguments":{"url":"https://chase.com/","title":"Bank"}}}`,
+	)
+
+	text, isErr := toolText(t, resps[0])
+	require.False(t, isErr, text)
+	var added eventJSON
+	require.NoError(t, json.Unmarshal([]byte(text), &added))
+
+	content, err := store.GetContent(context.Background(), added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "remember this", content.Body)
+
+	text, isErr = toolText(t, resps[1])
+	assert.True(t, isErr)
+	assert.Contains(t, text, "excluded")
+}
+
+func TestServer_Errors(t *testing.T) {
+	resps := roundtrip(t, openTestStore(t),
+		`not json`,
+		`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"drop_tables"}}`,
+	)
+
+	require.Len(t, resps, 3)
+	codes := []float64{jsonrpc.CodeParseError, jsonrpc.CodeMethodNotFound, jsonrpc.CodeInvalidParams}
+	for i, resp := range resps {
+		rpcErr := resp["error"].(map[string]interface{})
+		assert.Equal(t, codes[i], rpcErr["code"])
+	}
+}
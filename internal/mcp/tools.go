@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// errUnknownTool is returned by toolSet.call for unregistered tool names.
+var errUnknownTool = errors.New("unknown tool")
+
+// tool describes one MCP tool as advertised by tools/list.
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// toolSet holds the Chronicle tools and the store they operate on.
+type toolSet struct {
+	store    storage.Store
+	pipeline *capture.Pipeline // stores add_note's notes
+}
+
+func newToolSet(store storage.Store) *toolSet {
+	return &toolSet{store: store, pipeline: capture.New(store)}
+}
+
+func (t *toolSet) list() []tool {
+	return []tool{
+		{
+			Name:        "search_history",
+			Description: "Search the user's locally captured browsing history by keyword. Returns matching events with IDs, titles, URLs, and capture times.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"query":      prop("string", "Keywords to search titles and URLs for"),
+				"domain":     prop("string", "Only return events from this domain"),
+				"since_days": prop("integer", "Only return events captured within this many days (default 30)"),
+				"limit":      prop("integer", "Maximum number of results (default 10)"),
+			}),
+		},
+		{
+			Name:        "get_event",
+			Description: "Fetch a single browsing event by its Chronicle ID (CHR-xxxxxxxx), including stored page content when available.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id": prop("string", "Chronicle event ID"),
+			}, "id"),
+		},
+		{
+			Name:        "add_note",
+			Description: "Record a note in the user's Chronicle history, attached to a URL.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"url":   prop("string", "URL the note refers to"),
+				"title": prop("string", "Short title for the note"),
+				"body":  prop("string", "Note text"),
+			}, "url", "title"),
+		},
+	}
+}
+
+// call runs the named tool with raw JSON arguments and returns its text output.
+func (t *toolSet) call(ctx context.Context, name string, args json.RawMessage) (string, error) {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	switch name {
+	case "search_history":
+		return t.searchHistory(ctx, args)
+	case "get_event":
+		return t.getEvent(ctx, args)
+	case "add_note":
+		return t.addNote(ctx, args)
+	default:
+		return "", errUnknownTool
+	}
+}
+
+// eventJSON is the tool-facing representation of an event.
+type eventJSON struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Domain   string `json:"domain"`
+	Captured string `json:"captured"`
+	Source   string `json:"source"`
+	HasBody  bool   `json:"has_body"`
+	Body     string `json:"body,omitempty"`
+}
+
+func toEventJSON(e *storage.Event) eventJSON {
+	return eventJSON{
+		ID:       e.ID,
+		Title:    e.Title,
+		URL:      e.URL,
+		Domain:   e.Domain,
+		Captured: e.Timestamp.UTC().Format(time.RFC3339),
+		Source:   e.Source,
+		HasBody:  e.HasBody,
+	}
+}
+
+func (t *toolSet) searchHistory(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Query     string `json:"query"`
+		Domain    string `json:"domain"`
+		SinceDays int    `json:"since_days"`
+		Limit     int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.SinceDays <= 0 {
+		args.SinceDays = 30
+	}
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+
+	events, err := t.store.SearchEvents(ctx, storage.SearchQuery{
+		Query:  args.Query,
+		Domain: args.Domain,
+		Since:  time.Now().Add(-time.Duration(args.SinceDays) * 24 * time.Hour),
+		Limit:  args.Limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("search failed: %w", err)
+	}
+
+	out := make([]eventJSON, len(events))
+	for i := range events {
+		out[i] = toEventJSON(&events[i])
+	}
+	return marshalText(out)
+}
+
+func (t *toolSet) getEvent(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.ID == "" {
+		return "", fmt.Errorf("id is required")
+	}
+
+	event, err := t.store.GetEvent(ctx, args.ID)
+	if err != nil {
+		return "", err
+	}
+
+	out := toEventJSON(event)
+	if event.HasBody {
+		if content, err := t.store.GetContent(ctx, event.ID); err == nil {
+			out.Body = content.Body
+		}
+	}
+	return marshalText(out)
+}
+
+func (t *toolSet) addNote(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.URL == "" || args.Title == "" {
+		return "", fmt.Errorf("url and title are required")
+	}
+	if parsed, err := url.ParseRequestURI(args.URL); err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid URL: %s", args.URL)
+	}
+
+	event := &storage.Event{
+		URL:       args.URL,
+		Title:     args.Title,
+		Source:    "manual",
+		Browser:   "mcp",
+		Timestamp: time.Now(),
+	}
+
+	if err := t.pipeline.Capture(ctx, event, args.Body); err != nil {
+		return "", fmt.Errorf("storing note: %w", err)
+	}
+
+	return marshalText(toEventJSON(event))
+}
+
+func marshalText(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func objectSchema(props map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func prop(typ, description string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "description": description}
+}
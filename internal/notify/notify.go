@@ -0,0 +1,54 @@
+// Package notify shows desktop notifications using the platform's own
+// tool: osascript on macOS and notify-send on Linux and the BSDs.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// appName is shown as the notification's sender where supported.
+const appName = "Chronicle"
+
+// run executes the notification command; tests replace it.
+var run = func(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Send shows a notification with title and message.
+func Send(title, message string) error {
+	name, args, err := command(runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	return run(name, args...)
+}
+
+// command returns the notification command for goos.
+func command(goos, title, message string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=" + appName, title, message}, nil
+	default:
+		return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("linux", "Captured", "go.dev: Go docs")
+	require.NoError(t, err)
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"--app-name=Chronicle", "Captured", "go.dev: Go docs"}, args)
+
+	name, args, err = command("darwin", `Say "hi"`, `C:\path`)
+	require.NoError(t, err)
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "C:\\path" with title "Say \"hi\""`}, args)
+
+	_, _, err = command("windows", "a", "b")
+	assert.Error(t, err)
+}
+
+func TestSend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no notification command on windows")
+	}
+	var got []string
+	old := run
+	t.Cleanup(func() { run = old })
+	run = func(name string, args ...string) error {
+		got = append([]string{name}, args...)
+		return nil
+	}
+
+	require.NoError(t, Send("Title", "Body"))
+	require.NotEmpty(t, got)
+	assert.Contains(t, got[len(got)-1], "Body")
+
+	run = func(string, ...string) error { return errors.New("no display") }
+	assert.EqualError(t, Send("Title", "Body"), "no display")
+}
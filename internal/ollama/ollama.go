@@ -0,0 +1,88 @@
+// Package ollama is a minimal client for a local Ollama server's HTTP API.
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client talks to the Ollama server at URL.
+type Client struct {
+	URL  string       // e.g. http://localhost:11434
+	HTTP *http.Client // nil means http.DefaultClient
+}
+
+// New returns a Client for the server at url.
+func New(url string) *Client {
+	return &Client{URL: strings.TrimRight(url, "/")}
+}
+
+// Generate runs prompt through model and returns the whole response.
+func (c *Client) Generate(ctx context.Context, model, prompt string) (string, error) {
+	var out struct {
+		Response string `json:"response"`
+	}
+	req := map[string]interface{}{"model": model, "prompt": prompt, "stream": false}
+	if err := c.post(ctx, "/api/generate", req, &out); err != nil {
+		return "", err
+	}
+	return out.Response, nil
+}
+
+// Embed returns model's embedding of each of input, in order.
+func (c *Client) Embed(ctx context.Context, model string, input []string) ([][]float32, error) {
+	var out struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	req := map[string]interface{}{"model": model, "input": input}
+	if err := c.post(ctx, "/api/embed", req, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Embeddings) != len(input) {
+		return nil, fmt.Errorf("ollama /api/embed: got %d embeddings for %d inputs", len(out.Embeddings), len(input))
+	}
+	return out.Embeddings, nil
+}
+
+// post sends in as JSON to path and decodes the JSON reply into out.
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Ollama reports failures as {"error": "..."}.
+		var e struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("ollama %s: %s: %s", path, resp.Status, e.Error)
+		}
+		return fmt.Errorf("ollama %s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ollama %s: decode response: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/generate", r.URL.Path)
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req["model"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"model 'missing' not found"}`)) //nolint:errcheck
+			return
+		}
+		assert.Equal(t, false, req["stream"])
+		json.NewEncoder(w).Encode(map[string]string{"response": "echo: " + req["prompt"].(string)}) //nolint:errcheck
+	}))
+	defer srv.Close()
+	c := New(srv.URL + "/")
+
+	got, err := c.Generate(context.Background(), "llama3.2", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", got)
+
+	_, err = c.Generate(context.Background(), "missing", "hello")
+	assert.EqualError(t, err, "ollama /api/generate: 404 Not Found: model 'missing' not found")
+}
+
+func TestEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		out := make([][]float32, 0, len(req.Input))
+		for _, in := range req.Input {
+			if in == "short" {
+				continue
+			}
+			out = append(out, []float32{float32(len(in)), 0.5})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"embeddings": out}) //nolint:errcheck
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+
+	got, err := c.Embed(context.Background(), "nomic-embed-text", []string{"a", "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0.5}, {3, 0.5}}, got)
+
+	_, err = c.Embed(context.Background(), "nomic-embed-text", []string{"a", "short"})
+	assert.EqualError(t, err, "ollama /api/embed: got 1 embeddings for 2 inputs")
+}
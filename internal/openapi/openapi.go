@@ -0,0 +1,395 @@
+// Package openapi builds the OpenAPI 3 document describing Chronicle's HTTP
+// endpoints: the capture endpoints served by the ingest daemon and the
+// query endpoints served by chronicle api.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Version is the version of the HTTP contract described by the document.
+// Bump it when an endpoint or schema changes incompatibly.
+const Version = "1.0.0"
+
+// Path is where both servers serve the document.
+const Path = "/openapi.json"
+
+// Default server URLs, matching the daemon and api defaults in config.
+const (
+	captureServer = "http://127.0.0.1:8721"
+	queryServer   = "http://127.0.0.1:8722"
+)
+
+type object = map[string]interface{}
+
+// Document returns the OpenAPI document as a JSON-encodable value.
+func Document() map[string]interface{} {
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "Chronicle",
+			"version":     Version,
+			"description": "Local browsing history capture and query API. Capture endpoints are served by chronicle ingest, query endpoints by chronicle api; each listens on its own configurable host and port.",
+		},
+		"paths": object{
+			"/register": object{
+				"servers": captureServers(),
+				"post": object{
+					"tags":        []string{"capture"},
+					"summary":     "Register a browser extension",
+					"description": "Sent by an extension on startup. Pass the id from an earlier registration to keep it.",
+					"operationId": "registerExtension",
+					"requestBody": jsonBody("RegisterRequest"),
+					"responses": object{
+						"201": jsonResponse("Registered", "RegisterResponse"),
+						"400": errorResponse("Malformed request"),
+						"401": errorResponse("Missing or invalid token"),
+					},
+				},
+			},
+			"/heartbeat": object{
+				"servers": captureServers(),
+				"post": object{
+					"tags":        []string{"capture"},
+					"summary":     "Report that a registered extension is still running",
+					"operationId": "heartbeat",
+					"requestBody": jsonBody("HeartbeatRequest"),
+					"responses": object{
+						"204": object{"description": "Recorded"},
+						"400": errorResponse("Malformed request"),
+						"401": errorResponse("Missing or invalid token"),
+						"404": errorResponse("Unknown extension; register again"),
+					},
+				},
+			},
+			"/status": object{
+				"servers": captureServers(),
+				"get": object{
+					"tags":        []string{"capture"},
+					"summary":     "Check that the daemon is up",
+					"description": "Public. Callers presenting the token also get the status of the scheduled jobs.",
+					"operationId": "getDaemonStatus",
+					"security":    []object{},
+					"responses": object{
+						"200": jsonResponse("The daemon is up", "DaemonStatus"),
+					},
+				},
+			},
+			"/events": object{
+				// Each server has its own operation on this path.
+				"get": object{
+					"servers":     queryServers(),
+					"tags":        []string{"query"},
+					"summary":     "List events, newest first",
+					"operationId": "listEvents",
+					"parameters":  filterParams(),
+					"responses":   listResponses(),
+				},
+				"post": object{
+					"servers":     captureServers(),
+					"tags":        []string{"capture"},
+					"summary":     "Capture a page",
+					"description": "Sent by an extension for each page visited. The capture settings apply: denylisted pages, pages outside a non-empty allowlist and incognito pages are refused, and in metadata_only mode the body is dropped unless the domain is in capture.body_capture_domains. Pre- and post-capture hooks run as for chronicle add.",
+					"operationId": "captureEvent",
+					"requestBody": jsonBody("EventRequest"),
+					"responses": object{
+						"201": jsonResponse("Stored", "Event"),
+						"400": errorResponse("Malformed request"),
+						"401": errorResponse("Missing or invalid token"),
+						"403": errorResponse("Page refused by the capture settings, domain excluded, capture vetoed by a hook, or Origin not a browser extension; do not retry"),
+						"413": errorResponse("Request or body too large (daemon.max_request_size, storage body limit)"),
+						"415": errorResponse("Content-Type is not application/json"),
+					},
+				},
+			},
+			"/events/{id}": object{
+				"servers": queryServers(),
+				"get": object{
+					"tags":        []string{"query"},
+					"summary":     "Get one event, including its captured body",
+					"operationId": "getEvent",
+					"parameters": []object{{
+						"name": "id", "in": "path", "required": true,
+						"schema": object{"type": "string"}, "example": "CHR-1a2b3c4d",
+					}},
+					"responses": object{
+						"200": jsonResponse("The event", "Event"),
+						"401": errorResponse("Missing or invalid token"),
+						"404": errorResponse("No such event"),
+					},
+				},
+			},
+			"/search": object{
+				"servers": queryServers(),
+				"get": object{
+					"tags":        []string{"query"},
+					"summary":     "Full-text search over titles, URLs and bodies",
+					"operationId": "searchEvents",
+					"parameters": append([]object{{
+						"name": "q", "in": "query", "required": true,
+						"schema": object{"type": "string"}, "description": "Search terms",
+					}}, filterParams()...),
+					"responses": listResponses(),
+				},
+			},
+			"/stats": object{
+				"servers": queryServers(),
+				"get": object{
+					"tags":        []string{"query"},
+					"summary":     "Database totals and top domains",
+					"operationId": "getStats",
+					"responses": object{
+						"200": jsonResponse("Statistics", "Stats"),
+						"401": errorResponse("Missing or invalid token"),
+					},
+				},
+			},
+			"/feed.atom": object{
+				"servers": queryServers(),
+				"get": object{
+					"tags":        []string{"query"},
+					"summary":     "Atom feed of recent captures",
+					"description": "Also accepts the token as a token query parameter, for feed readers that cannot send headers.",
+					"operationId": "getFeed",
+					"parameters": append([]object{{
+						"name": "token", "in": "query",
+						"schema": object{"type": "string"}, "description": "Alternative to the Authorization header",
+					}, {
+						"name": "q", "in": "query",
+						"schema": object{"type": "string"}, "description": "Only events matching these search terms",
+					}}, filterParams()...),
+					"responses": object{
+						"200": object{
+							"description": "Atom 1.0 feed",
+							"content":     object{"application/atom+xml": object{"schema": object{"type": "string"}}},
+						},
+						"400": errorResponse("Invalid parameter"),
+						"401": errorResponse("Missing or invalid token"),
+					},
+				},
+			},
+		},
+		"components": object{
+			"securitySchemes": object{
+				"bearer": object{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "daemon.auth_token for capture endpoints (generated on the daemon's first start), api.auth_token for query endpoints",
+				},
+			},
+			"schemas": schemas(),
+		},
+		"security": []object{{"bearer": []string{}}},
+	}
+}
+
+// Handler serves the document as JSON.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(Document()) //nolint:errcheck
+}
+
+func captureServers() []object {
+	return []object{{"url": captureServer, "description": "chronicle ingest (daemon.host, daemon.port)"}}
+}
+
+func queryServers() []object {
+	return []object{{"url": queryServer, "description": "chronicle api (api.host, api.port)"}}
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonBody(schema string) object {
+	return object{
+		"required": true,
+		"content":  object{"application/json": object{"schema": ref(schema)}},
+	}
+}
+
+func jsonResponse(desc, schema string) object {
+	return object{
+		"description": desc,
+		"content":     object{"application/json": object{"schema": ref(schema)}},
+	}
+}
+
+func errorResponse(desc string) object {
+	return jsonResponse(desc, "Error")
+}
+
+func listResponses() object {
+	return object{
+		"200": jsonResponse("Matching events", "EventList"),
+		"400": errorResponse("Invalid parameter"),
+		"401": errorResponse("Missing or invalid token"),
+	}
+}
+
+// filterParams are the query parameters shared by the event listing
+// endpoints.
+func filterParams() []object {
+	str := func(name, desc string) object {
+		return object{"name": name, "in": "query", "schema": object{"type": "string"}, "description": desc}
+	}
+	dateTime := func(name, desc string) object {
+		return object{"name": name, "in": "query", "schema": object{"type": "string", "format": "date-time"}, "description": desc}
+	}
+	integer := func(name, desc string, max int) object {
+		schema := object{"type": "integer", "minimum": 0}
+		if max > 0 {
+			schema["maximum"] = max
+		}
+		return object{"name": name, "in": "query", "schema": schema, "description": desc}
+	}
+	return []object{
+		str("domain", "Only this domain"),
+		str("source", "Only this source (extension, manual, import)"),
+		str("browser", "Only this browser"),
+		str("lang", "Only events whose body is in this language (ISO 639-1 code, e.g. de)"),
+		str("keyword", "Only events with this extracted keyword, or a keyphrase containing it"),
+		dateTime("since", "Only events at or after this time (RFC 3339)"),
+		dateTime("until", "Only events at or before this time (RFC 3339)"),
+		integer("limit", "Maximum events to return (default 50)", 500),
+		integer("offset", "Events to skip", 0),
+	}
+}
+
+func schemas() object {
+	str := object{"type": "string"}
+	dateTime := object{"type": "string", "format": "date-time"}
+	boolean := object{"type": "boolean"}
+	integer := object{"type": "integer", "format": "int64"}
+
+	return object{
+		"Error": object{
+			"type":       "object",
+			"required":   []string{"error"},
+			"properties": object{"error": str},
+		},
+		"Event": object{
+			"type":     "object",
+			"required": []string{"id", "url", "title", "domain", "timestamp", "source", "has_body", "has_embedding"},
+			"properties": object{
+				"id":            str,
+				"url":           object{"type": "string", "format": "uri"},
+				"title":         str,
+				"raw_title":     object{"type": "string", "description": "The title as captured, when a site-name suffix was stripped from title"},
+				"domain":        str,
+				"timestamp":     dateTime,
+				"source":        str,
+				"browser":       str,
+				"lang":          object{"type": "string", "description": "ISO 639-1 code of the body's language, when detected"},
+				"canonical_url": object{"type": "string", "description": "The page's declared canonical URL, when it differs from url"},
+				"read_seconds":  object{"type": "integer", "description": "Estimated reading time of the body, in seconds; omitted without a body"},
+				"visits":        object{"type": "integer", "description": "Captures of the page counted as this event by deduplication, including the first"},
+				"has_body":      boolean,
+				"has_embedding": boolean,
+				"body":          object{"type": "string", "description": "Captured page text; only returned by GET /events/{id}"},
+			},
+		},
+		"EventList": object{
+			"type":     "object",
+			"required": []string{"count", "total", "events"},
+			"properties": object{
+				"count":  integer,
+				"total":  object{"type": "integer", "description": "Events matching the filters, ignoring limit and offset"},
+				"query":  str,
+				"events": object{"type": "array", "items": ref("Event")},
+			},
+		},
+		"Stats": object{
+			"type":     "object",
+			"required": []string{"total_events", "total_content", "top_domains", "sources", "browsers"},
+			"properties": object{
+				"total_events":  integer,
+				"total_content": integer,
+				"oldest_event":  dateTime,
+				"newest_event":  dateTime,
+				"top_domains": object{
+					"type": "array",
+					"items": object{
+						"type":       "object",
+						"properties": object{"domain": str, "count": integer},
+					},
+				},
+				"sources":  object{"type": "array", "items": ref("ValueCount")},
+				"browsers": object{"type": "array", "items": ref("ValueCount")},
+			},
+		},
+		"ValueCount": object{
+			"type":       "object",
+			"properties": object{"name": str, "count": integer},
+		},
+		"RegisterRequest": object{
+			"type":     "object",
+			"required": []string{"browser"},
+			"properties": object{
+				"id":      object{"type": "string", "description": "ID from an earlier registration, to keep it"},
+				"browser": object{"type": "string", "example": "firefox"},
+				"version": object{"type": "string", "description": "Extension version"},
+				"profile": object{"type": "string", "description": "Browser profile name"},
+			},
+		},
+		"RegisterResponse": object{
+			"type":     "object",
+			"required": []string{"id", "heartbeat_interval_seconds"},
+			"properties": object{
+				"id":                         str,
+				"heartbeat_interval_seconds": object{"type": "integer"},
+			},
+		},
+		"EventRequest": object{
+			"type":     "object",
+			"required": []string{"url", "title"},
+			"properties": object{
+				"url":            object{"type": "string", "format": "uri"},
+				"title":          str,
+				"body":           object{"type": "string", "description": "Page text"},
+				"browser":        object{"type": "string", "example": "firefox"},
+				"incognito":      object{"type": "boolean", "description": "Visited in a private window; refused with capture.exclude_incognito"},
+				"timestamp":      object{"type": "string", "format": "date-time", "description": "When the page was visited; default now"},
+				"canonical_url":  object{"type": "string", "description": "The page's <link rel=canonical> href"},
+				"og_title":       str,
+				"og_description": str,
+				"og_image":       str,
+				"author":         str,
+				"published":      dateTime,
+			},
+		},
+		"HeartbeatRequest": object{
+			"type":       "object",
+			"required":   []string{"id"},
+			"properties": object{"id": str},
+		},
+		"DaemonStatus": object{
+			"type":     "object",
+			"required": []string{"status"},
+			"properties": object{
+				"status": object{"type": "string", "example": "ok"},
+				"jobs":   object{"type": "array", "items": ref("JobStatus")},
+			},
+		},
+		"JobStatus": object{
+			"type":     "object",
+			"required": []string{"name", "type", "schedule", "running", "runs", "failures"},
+			"properties": object{
+				"name":          str,
+				"type":          object{"type": "string", "enum": []string{"prune", "backup", "vacuum", "digest"}},
+				"schedule":      object{"type": "string", "example": "0 3 * * *"},
+				"next":          dateTime,
+				"running":       boolean,
+				"runs":          integer,
+				"failures":      integer,
+				"last_run":      dateTime,
+				"last_duration": object{"type": "string", "example": "1.25s"},
+				"last_result":   str,
+				"last_error":    str,
+			},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decoded returns the document as it appears on the wire.
+func decoded(t *testing.T) map[string]interface{} {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	return doc
+}
+
+func TestDocument(t *testing.T) {
+	doc := decoded(t)
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Equal(t, Version, doc["info"].(map[string]interface{})["version"])
+
+	paths := doc["paths"].(map[string]interface{})
+	for _, p := range []string{"/status", "/register", "/heartbeat", "/events", "/events/{id}", "/search", "/stats", "/feed.atom"} {
+		assert.Contains(t, paths, p)
+	}
+}
+
+func TestDocument_RefsResolve(t *testing.T) {
+	doc := decoded(t)
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	var refs []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if k == "$ref" {
+					refs = append(refs, child.(string))
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	require.NotEmpty(t, refs)
+	for _, r := range refs {
+		name := strings.TrimPrefix(r, "#/components/schemas/")
+		assert.Contains(t, schemas, name, "unresolved $ref %s", r)
+	}
+}
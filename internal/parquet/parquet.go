@@ -0,0 +1,306 @@
+// Package parquet writes Apache Parquet files for loading Chronicle
+// exports into DuckDB, pandas and similar tools. It implements only what
+// export needs: flat schemas of required columns, PLAIN encoding, no
+// compression, and one data page per column per row group.
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Type is a column's logical type.
+type Type int
+
+const (
+	String    Type = iota // UTF-8 BYTE_ARRAY
+	Int64                 // INT64
+	Bool                  // BOOLEAN
+	Timestamp             // INT64 milliseconds since the Unix epoch, UTC
+)
+
+// Column describes one column of the schema.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Row groups are flushed when either limit is reached.
+const (
+	maxRowGroupRows  = 100000
+	maxRowGroupBytes = 64 << 20
+)
+
+const magic = "PAR1"
+
+// Parquet physical types, converted types and enum values.
+const (
+	physBoolean   = 0
+	physInt64     = 2
+	physByteArray = 6
+
+	convUTF8            = 0
+	convTimestampMillis = 9
+
+	repRequired = 0
+
+	encPlain = 0
+	encRLE   = 3
+
+	pageData = 0
+
+	codecUncompressed = 0
+)
+
+// Writer writes rows to a Parquet file. Close must be called to write the
+// footer; it does not close the underlying writer.
+type Writer struct {
+	w       io.Writer
+	offset  int64
+	columns []Column
+
+	values [][]byte // PLAIN-encoded values per column, current row group
+	bools  [][]bool // pending BOOLEAN values per column, current row group
+	rows   int64
+	size   int
+
+	totalRows int64
+	groups    []rowGroup
+	closed    bool
+}
+
+type rowGroup struct {
+	rows   int64
+	chunks []chunk
+}
+
+type chunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+// NewWriter writes the file header to w and returns a Writer for rows with
+// the given columns.
+func NewWriter(w io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: no columns")
+	}
+	pw := &Writer{
+		w:       w,
+		columns: columns,
+		values:  make([][]byte, len(columns)),
+		bools:   make([][]bool, len(columns)),
+	}
+	if err := pw.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+// Write appends one row. Values must match the column types: string, int64,
+// bool, or time.Time for Timestamp.
+func (w *Writer) Write(row ...interface{}) error {
+	if w.closed {
+		return fmt.Errorf("parquet: write after close")
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, schema has %d columns", len(row), len(w.columns))
+	}
+
+	for i, col := range w.columns {
+		v := row[i]
+		var ok bool
+		switch col.Type {
+		case String:
+			var s string
+			if s, ok = v.(string); ok {
+				var n [4]byte
+				binary.LittleEndian.PutUint32(n[:], uint32(len(s)))
+				w.values[i] = append(append(w.values[i], n[:]...), s...)
+				w.size += 4 + len(s)
+			}
+		case Int64:
+			var n int64
+			if n, ok = v.(int64); ok {
+				w.values[i] = binary.LittleEndian.AppendUint64(w.values[i], uint64(n))
+				w.size += 8
+			}
+		case Timestamp:
+			var t time.Time
+			if t, ok = v.(time.Time); ok {
+				w.values[i] = binary.LittleEndian.AppendUint64(w.values[i], uint64(t.UnixMilli()))
+				w.size += 8
+			}
+		case Bool:
+			var b bool
+			if b, ok = v.(bool); ok {
+				w.bools[i] = append(w.bools[i], b)
+				w.size++
+			}
+		}
+		if !ok {
+			return fmt.Errorf("parquet: column %s: unexpected value %T", col.Name, v)
+		}
+	}
+
+	w.rows++
+	if w.rows >= maxRowGroupRows || w.size >= maxRowGroupBytes {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close flushes buffered rows and writes the footer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	footer := w.footer()
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(footer)))
+	return w.write(append(append(footer, n[:]...), magic...))
+}
+
+func (w *Writer) write(p []byte) error {
+	n, err := w.w.Write(p)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("parquet: %w", err)
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group of one data page per
+// column.
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := rowGroup{rows: w.rows}
+	for i, col := range w.columns {
+		data := w.values[i]
+		if col.Type == Bool {
+			data = packBools(w.bools[i])
+		}
+
+		var h compact
+		h.begin()
+		h.i32(1, pageData)
+		h.i32(2, int32(len(data)))
+		h.i32(3, int32(len(data)))
+		h.beginField(5)
+		h.i32(1, int32(w.rows))
+		h.i32(2, encPlain)
+		h.i32(3, encRLE)
+		h.i32(4, encRLE)
+		h.end()
+		h.end()
+
+		c := chunk{offset: w.offset, values: w.rows}
+		if err := w.write(h.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := w.write(data); err != nil {
+			return err
+		}
+		c.size = w.offset - c.offset
+		group.chunks = append(group.chunks, c)
+
+		w.values[i] = w.values[i][:0]
+		w.bools[i] = w.bools[i][:0]
+	}
+
+	w.groups = append(w.groups, group)
+	w.totalRows += w.rows
+	w.rows, w.size = 0, 0
+	return nil
+}
+
+// footer encodes the FileMetaData struct.
+func (w *Writer) footer() []byte {
+	var m compact
+	m.begin()
+	m.i32(1, 1)
+
+	m.list(2, tStruct, len(w.columns)+1)
+	m.begin()
+	m.str(4, "schema")
+	m.i32(5, int32(len(w.columns)))
+	m.end()
+	for _, col := range w.columns {
+		m.begin()
+		m.i32(1, physical(col.Type))
+		m.i32(3, repRequired)
+		m.str(4, col.Name)
+		switch col.Type {
+		case String:
+			m.i32(6, convUTF8)
+		case Timestamp:
+			m.i32(6, convTimestampMillis)
+		}
+		m.end()
+	}
+
+	m.i64(3, w.totalRows)
+
+	m.list(4, tStruct, len(w.groups))
+	for _, g := range w.groups {
+		m.begin()
+		m.list(1, tStruct, len(g.chunks))
+		var total int64
+		for i, c := range g.chunks {
+			m.begin()
+			m.i64(2, c.offset)
+			m.beginField(3)
+			m.i32(1, physical(w.columns[i].Type))
+			m.i32List(2, encPlain, encRLE)
+			m.strList(3, w.columns[i].Name)
+			m.i32(4, codecUncompressed)
+			m.i64(5, c.values)
+			m.i64(6, c.size)
+			m.i64(7, c.size)
+			m.i64(9, c.offset)
+			m.end()
+			m.end()
+			total += c.size
+		}
+		m.i64(2, total)
+		m.i64(3, g.rows)
+		m.end()
+	}
+
+	m.str(6, "chronicle")
+	m.end()
+	return m.buf.Bytes()
+}
+
+func physical(t Type) int32 {
+	switch t {
+	case Int64, Timestamp:
+		return physInt64
+	case Bool:
+		return physBoolean
+	default:
+		return physByteArray
+	}
+}
+
+// packBools PLAIN-encodes booleans: one bit each, least significant first.
+func packBools(bs []bool) []byte {
+	out := make([]byte, (len(bs)+7)/8)
+	for i, b := range bs {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
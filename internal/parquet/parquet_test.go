@@ -0,0 +1,250 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decoder reads Thrift compact structs generically, so tests can check the
+// footer and page headers without a Parquet library. Structs decode to
+// map[int16]interface{}, integers to int64, binaries to string and lists
+// to []interface{}.
+type decoder struct {
+	r *bytes.Reader
+}
+
+func (d *decoder) varint() int64 {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		panic(err)
+	}
+	return int64(v)
+}
+
+func (d *decoder) zigzag() int64 {
+	v := uint64(d.varint())
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *decoder) byte() byte {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (d *decoder) value(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case tI32, tI64:
+		return d.zigzag()
+	case tBinary:
+		b := make([]byte, d.varint())
+		if _, err := d.r.Read(b); err != nil && len(b) > 0 {
+			panic(err)
+		}
+		return string(b)
+	case tList:
+		h := d.byte()
+		size, elem := int64(h>>4), h&0x0F
+		if size == 15 {
+			size = d.varint()
+		}
+		out := make([]interface{}, size)
+		for i := range out {
+			out[i] = d.value(elem)
+		}
+		return out
+	case tStruct:
+		return d.structure()
+	}
+	panic(fmt.Sprintf("unexpected thrift type %d", typ))
+}
+
+func (d *decoder) structure() map[int16]interface{} {
+	out := map[int16]interface{}{}
+	var last int16
+	for {
+		h := d.byte()
+		if h == 0 {
+			return out
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(d.zigzag())
+		}
+		out[id] = d.value(h & 0x0F)
+		last = id
+	}
+}
+
+// readFile checks the framing of a Parquet file and returns its decoded
+// FileMetaData.
+func readFile(t *testing.T, data []byte) map[int16]interface{} {
+	t.Helper()
+	require.GreaterOrEqual(t, len(data), 12)
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+
+	n := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-n : len(data)-8]
+	d := &decoder{r: bytes.NewReader(footer)}
+	meta := d.structure()
+	assert.Zero(t, d.r.Len(), "footer fully consumed")
+	return meta
+}
+
+// readChunk decodes the data page of a column chunk, returning its header
+// and values.
+func readChunk(t *testing.T, data []byte, chunk map[int16]interface{}) (map[int16]interface{}, []byte) {
+	t.Helper()
+	md := chunk[3].(map[int16]interface{})
+	offset := md[9].(int64)
+	size := md[7].(int64)
+
+	r := bytes.NewReader(data[offset : offset+size])
+	header := (&decoder{r: r}).structure()
+	page := make([]byte, r.Len())
+	_, _ = r.Read(page)
+	require.Equal(t, header[2], int64(len(page)))
+	return header, page
+}
+
+func plainStrings(t *testing.T, page []byte) []string {
+	t.Helper()
+	var out []string
+	for len(page) > 0 {
+		n := binary.LittleEndian.Uint32(page)
+		out = append(out, string(page[4:4+n]))
+		page = page[4+n:]
+	}
+	return out
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{
+		{Name: "id", Type: String},
+		{Name: "ts", Type: Timestamp},
+		{Name: "visits", Type: Int64},
+		{Name: "starred", Type: Bool},
+	})
+	require.NoError(t, err)
+
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write("a", ts, int64(3), true))
+	require.NoError(t, w.Write("bb", ts.Add(time.Second), int64(-1), false))
+	require.NoError(t, w.Write("", ts.Add(time.Minute), int64(0), true))
+	require.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	meta := readFile(t, data)
+	assert.Equal(t, int64(1), meta[1])
+	assert.Equal(t, int64(3), meta[3])
+	assert.Equal(t, "chronicle", meta[6])
+
+	schema := meta[2].([]interface{})
+	require.Len(t, schema, 5)
+	root := schema[0].(map[int16]interface{})
+	assert.Equal(t, "schema", root[4])
+	assert.Equal(t, int64(4), root[5])
+
+	ids := schema[1].(map[int16]interface{})
+	assert.Equal(t, "id", ids[4])
+	assert.Equal(t, int64(physByteArray), ids[1])
+	assert.Equal(t, int64(convUTF8), ids[6])
+	tsCol := schema[2].(map[int16]interface{})
+	assert.Equal(t, int64(physInt64), tsCol[1])
+	assert.Equal(t, int64(convTimestampMillis), tsCol[6])
+	assert.Equal(t, int64(physBoolean), schema[4].(map[int16]interface{})[1])
+
+	groups := meta[4].([]interface{})
+	require.Len(t, groups, 1)
+	group := groups[0].(map[int16]interface{})
+	assert.Equal(t, int64(3), group[3])
+	chunks := group[1].([]interface{})
+	require.Len(t, chunks, 4)
+
+	header, page := readChunk(t, data, chunks[0].(map[int16]interface{}))
+	assert.Equal(t, int64(pageData), header[1])
+	assert.Equal(t, int64(3), header[5].(map[int16]interface{})[1])
+	assert.Equal(t, []string{"a", "bb", ""}, plainStrings(t, page))
+
+	_, page = readChunk(t, data, chunks[1].(map[int16]interface{}))
+	require.Len(t, page, 24)
+	assert.Equal(t, ts.UnixMilli(), int64(binary.LittleEndian.Uint64(page)))
+	assert.Equal(t, ts.Add(time.Minute).UnixMilli(), int64(binary.LittleEndian.Uint64(page[16:])))
+
+	_, page = readChunk(t, data, chunks[2].(map[int16]interface{}))
+	assert.Equal(t, int64(-1), int64(binary.LittleEndian.Uint64(page[8:])))
+
+	_, page = readChunk(t, data, chunks[3].(map[int16]interface{}))
+	assert.Equal(t, []byte{0b101}, page)
+}
+
+func TestWriter_SplitsRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "n", Type: Int64}})
+	require.NoError(t, err)
+	for i := 0; i < maxRowGroupRows+5; i++ {
+		require.NoError(t, w.Write(int64(i)))
+	}
+	require.NoError(t, w.Close())
+
+	meta := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(maxRowGroupRows+5), meta[3])
+	groups := meta[4].([]interface{})
+	require.Len(t, groups, 2)
+	assert.Equal(t, int64(5), groups[1].(map[int16]interface{})[3])
+
+	chunk := groups[1].(map[int16]interface{})[1].([]interface{})[0].(map[int16]interface{})
+	_, page := readChunk(t, buf.Bytes(), chunk)
+	assert.Equal(t, int64(maxRowGroupRows), int64(binary.LittleEndian.Uint64(page)))
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "id", Type: String}})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	meta := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+}
+
+func TestWriter_RejectsMismatchedRows(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "id", Type: String}, {Name: "n", Type: Int64}})
+	require.NoError(t, err)
+
+	err = w.Write("a")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has 1 values")
+
+	err = w.Write("a", 3)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "column n"), err.Error())
+}
+
+func TestCompact_LongListAndFieldJump(t *testing.T) {
+	var c compact
+	c.begin()
+	c.i32(1, 7)
+	c.strList(20, strings.Split("a b c d e f g h i j k l m n o p", " ")...)
+	c.end()
+
+	got := (&decoder{r: bytes.NewReader(c.buf.Bytes())}).structure()
+	assert.Equal(t, int64(7), got[1])
+	assert.Len(t, got[20], 16)
+}
@@ -0,0 +1,99 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type codes used by the Parquet metadata.
+const (
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// compact encodes Thrift structs with the compact protocol, which is how
+// Parquet serializes page headers and the file footer. Only the pieces the
+// writer needs are implemented.
+type compact struct {
+	buf  bytes.Buffer
+	last []int16 // last field id per open struct
+}
+
+func (c *compact) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	c.buf.Write(tmp[:n])
+}
+
+func (c *compact) zigzag(v int64) {
+	c.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (c *compact) field(id int16, typ byte) {
+	top := len(c.last) - 1
+	if delta := id - c.last[top]; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.zigzag(int64(id))
+	}
+	c.last[top] = id
+}
+
+// begin opens a struct: the top-level message, a list element, or (with a
+// field id) a struct-typed field.
+func (c *compact) begin() { c.last = append(c.last, 0) }
+
+func (c *compact) beginField(id int16) {
+	c.field(id, tStruct)
+	c.begin()
+}
+
+func (c *compact) end() {
+	c.buf.WriteByte(0)
+	c.last = c.last[:len(c.last)-1]
+}
+
+func (c *compact) i32(id int16, v int32) {
+	c.field(id, tI32)
+	c.zigzag(int64(v))
+}
+
+func (c *compact) i64(id int16, v int64) {
+	c.field(id, tI64)
+	c.zigzag(v)
+}
+
+func (c *compact) str(id int16, s string) {
+	c.field(id, tBinary)
+	c.varint(uint64(len(s)))
+	c.buf.WriteString(s)
+}
+
+func (c *compact) list(id int16, elem byte, size int) {
+	c.field(id, tList)
+	if size < 15 {
+		c.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		c.buf.WriteByte(0xF0 | elem)
+		c.varint(uint64(size))
+	}
+}
+
+func (c *compact) i32List(id int16, vs ...int32) {
+	c.list(id, tI32, len(vs))
+	for _, v := range vs {
+		c.zigzag(int64(v))
+	}
+}
+
+func (c *compact) strList(id int16, vs ...string) {
+	c.list(id, tBinary, len(vs))
+	for _, s := range vs {
+		c.varint(uint64(len(s)))
+		c.buf.WriteString(s)
+	}
+}
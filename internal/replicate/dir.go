@@ -0,0 +1,80 @@
+package replicate
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is a replica in a local directory, such as a mounted network share
+// or a synced folder.
+type Dir struct {
+	root string
+}
+
+// NewDir returns a replica rooted at root, which is created on first Put.
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+func (d *Dir) path(name string) string {
+	return filepath.Join(d.root, filepath.FromSlash(name))
+}
+
+// Put writes name atomically: to a temp file first, then renamed.
+func (d *Dir) Put(ctx context.Context, name string, r io.ReadSeeker) error {
+	dst := d.path(name)
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (d *Dir) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(d.path(name))
+}
+
+// List returns the names under prefix. A missing root lists as empty.
+func (d *Dir) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(d.root, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == d.root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(d.root, path)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names, err
+}
+
+func (d *Dir) Delete(ctx context.Context, name string) error {
+	return os.Remove(d.path(name))
+}
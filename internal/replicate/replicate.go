@@ -0,0 +1,259 @@
+// Package replicate copies the Chronicle database to off-machine storage
+// as a series of compressed point-in-time snapshots, and restores them.
+//
+// Each snapshot is a consistent copy taken with VACUUM INTO, so capture can
+// continue while it runs. Restore picks the newest snapshot at or before a
+// requested time; recovery granularity is therefore the snapshot interval.
+package replicate
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSnapshot is returned by Restore when no snapshot is old enough.
+var ErrNoSnapshot = errors.New("no snapshot at or before the requested time")
+
+// snapshotDir is where snapshots live under the replica root.
+const snapshotDir = "snapshots"
+
+// snapshotLayout names snapshots by their UTC time so they sort
+// chronologically.
+const snapshotLayout = "20060102T150405Z"
+
+const snapshotExt = ".db.gz"
+
+// Replica is object storage holding snapshots. Names are slash-separated
+// and relative to the replica root.
+type Replica interface {
+	Put(ctx context.Context, name string, r io.ReadSeeker) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// Open returns the replica at dest: a directory path, a file:// URL or an
+// s3://bucket/prefix URL.
+func Open(dest string) (Replica, error) {
+	if dest == "" {
+		return nil, fmt.Errorf("no replica location (set --to or storage.replica_url)")
+	}
+	if !strings.Contains(dest, "://") {
+		return NewDir(dest), nil
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica URL %q: %w", dest, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewDir(u.Path), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid replica URL %q: missing bucket", dest)
+		}
+		return NewS3FromEnv(u.Host, strings.Trim(u.Path, "/"))
+	default:
+		return nil, fmt.Errorf("unsupported replica scheme %q (want file or s3)", u.Scheme)
+	}
+}
+
+// Snapshot is one stored copy of the database.
+type Snapshot struct {
+	Name string
+	Time time.Time
+}
+
+// Replicator takes snapshots of db into a replica.
+type Replicator struct {
+	db      *sql.DB
+	replica Replica
+	retain  int
+
+	lastHash string // digest of the last uploaded copy, to skip no-op snapshots
+}
+
+// New returns a Replicator. retain is how many snapshots to keep; zero
+// keeps all.
+func New(db *sql.DB, replica Replica, retain int) *Replicator {
+	return &Replicator{db: db, replica: replica, retain: retain}
+}
+
+// Snapshot copies the database to the replica, named for now. It returns
+// false without uploading when nothing changed since the previous snapshot
+// taken by this Replicator.
+func (r *Replicator) Snapshot(ctx context.Context, now time.Time) (*Snapshot, bool, error) {
+	dir, err := os.MkdirTemp("", "chronicle-replicate-")
+	if err != nil {
+		return nil, false, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	raw := filepath.Join(dir, "snapshot.db")
+	if _, err := r.db.ExecContext(ctx, "VACUUM INTO ?", raw); err != nil {
+		return nil, false, fmt.Errorf("snapshot database: %w", err)
+	}
+
+	hash, err := fileHash(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	if hash == r.lastHash {
+		return nil, false, nil
+	}
+
+	gz := raw + ".gz"
+	if err := compressFile(raw, gz); err != nil {
+		return nil, false, err
+	}
+	f, err := os.Open(gz)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	snap := &Snapshot{Time: now.UTC().Truncate(time.Second)}
+	snap.Name = snapshotDir + "/" + snap.Time.Format(snapshotLayout) + snapshotExt
+	if err := r.replica.Put(ctx, snap.Name, f); err != nil {
+		return nil, false, fmt.Errorf("upload snapshot: %w", err)
+	}
+	r.lastHash = hash
+
+	if err := r.prune(ctx); err != nil {
+		return snap, true, err
+	}
+	return snap, true, nil
+}
+
+// prune deletes the oldest snapshots beyond the retention count.
+func (r *Replicator) prune(ctx context.Context) error {
+	if r.retain <= 0 {
+		return nil
+	}
+	snaps, err := List(ctx, r.replica)
+	if err != nil {
+		return err
+	}
+	for len(snaps) > r.retain {
+		if err := r.replica.Delete(ctx, snaps[0].Name); err != nil {
+			return fmt.Errorf("delete old snapshot %s: %w", snaps[0].Name, err)
+		}
+		snaps = snaps[1:]
+	}
+	return nil
+}
+
+// List returns the snapshots in replica, oldest first. Objects that do not
+// look like snapshots are ignored.
+func List(ctx context.Context, replica Replica) ([]Snapshot, error) {
+	names, err := replica.List(ctx, snapshotDir+"/")
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+	var snaps []Snapshot
+	for _, name := range names {
+		base := strings.TrimPrefix(name, snapshotDir+"/")
+		if !strings.HasSuffix(base, snapshotExt) {
+			continue
+		}
+		t, err := time.Parse(snapshotLayout, strings.TrimSuffix(base, snapshotExt))
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{Name: name, Time: t})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+// Restore writes the newest snapshot taken at or before at (any snapshot
+// if at is zero) to path, which must not exist yet.
+func Restore(ctx context.Context, replica Replica, at time.Time, path string) (*Snapshot, error) {
+	snaps, err := List(ctx, replica)
+	if err != nil {
+		return nil, err
+	}
+	var pick *Snapshot
+	for i := range snaps {
+		if at.IsZero() || !snaps[i].Time.After(at) {
+			pick = &snaps[i]
+		}
+	}
+	if pick == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	rc, err := replica.Get(ctx, pick.Name)
+	if err != nil {
+		return nil, fmt.Errorf("download snapshot %s: %w", pick.Name, err)
+	}
+	defer rc.Close()
+
+	zr, err := gzip.NewReader(rc)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot %s: %w", pick.Name, err)
+	}
+	defer zr.Close()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("create restore file: %w", err)
+	}
+	if _, err := io.Copy(f, zr); err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("restore snapshot %s: %w", pick.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("write restore file: %w", err)
+	}
+	return pick, nil
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return fmt.Errorf("compress snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return fmt.Errorf("compress snapshot: %w", err)
+	}
+	return out.Close()
+}
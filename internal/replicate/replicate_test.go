@@ -0,0 +1,133 @@
+package replicate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "live.db")+"?_journal_mode=WAL")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE events (id TEXT PRIMARY KEY, url TEXT)`)
+	require.NoError(t, err)
+	return db
+}
+
+func countRows(t *testing.T, path string) int {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n))
+	return n
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	replica := NewDir(t.TempDir())
+	r := New(db, replica, 0)
+
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	_, err := db.Exec(`INSERT INTO events VALUES ('a', 'https://a.example')`)
+	require.NoError(t, err)
+	snap, ok, err := r.Snapshot(ctx, t0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "snapshots/20260301T120000Z.db.gz", snap.Name)
+
+	_, ok, err = r.Snapshot(ctx, t0.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok, "unchanged database is not uploaded again")
+
+	_, err = db.Exec(`INSERT INTO events VALUES ('b', 'https://b.example')`)
+	require.NoError(t, err)
+	_, ok, err = r.Snapshot(ctx, t0.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	snaps, err := List(ctx, replica)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.True(t, snaps[0].Time.Equal(t0))
+
+	out := t.TempDir()
+	got, err := Restore(ctx, replica, t0.Add(30*time.Minute), filepath.Join(out, "pit.db"))
+	require.NoError(t, err)
+	assert.True(t, got.Time.Equal(t0))
+	assert.Equal(t, 1, countRows(t, filepath.Join(out, "pit.db")))
+
+	_, err = Restore(ctx, replica, time.Time{}, filepath.Join(out, "latest.db"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, countRows(t, filepath.Join(out, "latest.db")))
+
+	_, err = Restore(ctx, replica, t0.Add(-time.Hour), filepath.Join(out, "early.db"))
+	assert.True(t, errors.Is(err, ErrNoSnapshot))
+
+	_, err = Restore(ctx, replica, time.Time{}, filepath.Join(out, "latest.db"))
+	assert.Error(t, err, "restore never overwrites an existing file")
+}
+
+func TestSnapshot_PrunesToRetain(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	replica := NewDir(t.TempDir())
+	r := New(db, replica, 2)
+
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		_, err := db.Exec(`INSERT INTO events VALUES (?, '')`, string(rune('a'+i)))
+		require.NoError(t, err)
+		_, _, err = r.Snapshot(ctx, t0.Add(time.Duration(i)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	snaps, err := List(ctx, replica)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.True(t, snaps[0].Time.Equal(t0.Add(2*time.Hour)))
+}
+
+func TestList_EmptyReplica(t *testing.T) {
+	snaps, err := List(context.Background(), NewDir(filepath.Join(t.TempDir(), "missing")))
+	require.NoError(t, err)
+	assert.Empty(t, snaps)
+}
+
+func TestOpen(t *testing.T) {
+	r, err := Open("/tmp/replica")
+	require.NoError(t, err)
+	assert.IsType(t, &Dir{}, r)
+
+	r, err = Open("file:///tmp/replica")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/replica", r.(*Dir).root)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AK")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SK")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL_S3", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	r, err = Open("s3://bucket/some/prefix/")
+	require.NoError(t, err)
+	s3 := r.(*S3)
+	assert.Equal(t, "bucket", s3.Bucket)
+	assert.Equal(t, "some/prefix", s3.Prefix)
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com", s3.Endpoint)
+
+	_, err = Open("ftp://host/x")
+	assert.Error(t, err)
+	_, err = Open("")
+	assert.Error(t, err)
+}
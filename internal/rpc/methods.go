@@ -0,0 +1,279 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/jsonrpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Limits on search results.
+const (
+	defaultLimit = 20
+	maxLimit     = 500
+)
+
+// methodSet holds the RPC methods and the store they operate on.
+type methodSet struct {
+	store    storage.Store
+	pipeline *capture.Pipeline // stores add's events
+	version  string
+}
+
+// call runs the named method with raw JSON params.
+func (m *methodSet) call(ctx context.Context, method string, params json.RawMessage) (interface{}, *jsonrpc.Error) {
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	}
+
+	switch method {
+	case "ping":
+		return map[string]interface{}{"version": m.version}, nil
+	case "search":
+		return m.search(ctx, params)
+	case "get":
+		return m.get(ctx, params)
+	case "add":
+		return m.add(ctx, params)
+	case "stats":
+		return m.stats(ctx)
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeMethodNotFound, "method not found: %s", method)
+	}
+}
+
+// eventJSON is the RPC representation of an event, matching the HTTP API.
+type eventJSON struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Domain       string `json:"domain"`
+	Timestamp    string `json:"timestamp"`
+	Source       string `json:"source"`
+	Browser      string `json:"browser,omitempty"`
+	HasBody      bool   `json:"has_body"`
+	HasEmbedding bool   `json:"has_embedding"`
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	Body         string `json:"body,omitempty"`
+}
+
+func toEventJSON(e *storage.Event) eventJSON {
+	return eventJSON{
+		ID:           e.ID,
+		URL:          e.URL,
+		Title:        e.Title,
+		Domain:       e.Domain,
+		Timestamp:    e.Timestamp.UTC().Format(time.RFC3339),
+		Source:       e.Source,
+		Browser:      e.Browser,
+		HasBody:      e.HasBody,
+		HasEmbedding: e.HasEmbed,
+		CanonicalURL: e.CanonicalURL,
+	}
+}
+
+// decodeParams unmarshals named params into v. Positional (array) params
+// are not supported.
+func decodeParams(raw json.RawMessage, v interface{}) *jsonrpc.Error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: expected an object (%v)", err)
+	}
+	return nil
+}
+
+type searchParams struct {
+	Query   string `json:"query"`
+	Domain  string `json:"domain"`
+	Source  string `json:"source"`
+	Browser string `json:"browser"`
+	Since   string `json:"since"` // RFC 3339
+	Until   string `json:"until"` // RFC 3339
+	Sort    string `json:"sort"`  // relevance (default), newest, oldest
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+type searchResult struct {
+	Count  int         `json:"count"`
+	Events []eventJSON `json:"events"`
+}
+
+func (m *methodSet) search(ctx context.Context, raw json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var p searchParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+
+	q := storage.SearchQuery{
+		Query:   p.Query,
+		Domain:  p.Domain,
+		Source:  p.Source,
+		Browser: p.Browser,
+		Limit:   p.Limit,
+		Offset:  p.Offset,
+	}
+	for _, t := range []struct {
+		name, raw string
+		dst       *time.Time
+	}{{"since", p.Since, &q.Since}, {"until", p.Until, &q.Until}} {
+		if t.raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t.raw)
+		if err != nil {
+			return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid %s: use RFC 3339 (e.g., 2026-01-02T15:04:05Z)", t.name)
+		}
+		*t.dst = parsed
+	}
+	switch p.Sort {
+	case "", "relevance":
+	case "newest":
+		q.Sort = storage.SortNewest
+	case "oldest":
+		q.Sort = storage.SortOldest
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid sort %q (want relevance, newest, or oldest)", p.Sort)
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "limit and offset must be non-negative")
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultLimit
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+
+	events, err := m.store.SearchEvents(ctx, q)
+	if err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInternalError, "search failed: %v", err)
+	}
+
+	out := searchResult{Count: len(events), Events: make([]eventJSON, len(events))}
+	for i := range events {
+		out.Events[i] = toEventJSON(&events[i])
+	}
+	return out, nil
+}
+
+func (m *methodSet) get(ctx context.Context, raw json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var p struct {
+		ID string `json:"id"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.ID == "" {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "id is required")
+	}
+
+	event, err := m.store.GetEvent(ctx, p.ID)
+	if err != nil {
+		return nil, jsonrpc.Errorf(codeNotFound, "event %s not found", p.ID)
+	}
+
+	out := toEventJSON(event)
+	if event.HasBody {
+		if content, err := m.store.GetContent(ctx, event.ID); err == nil {
+			out.Body = content.Body
+		}
+	}
+	return out, nil
+}
+
+func (m *methodSet) add(ctx context.Context, raw json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var p struct {
+		URL     string `json:"url"`
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		Browser string `json:"browser"`
+
+		OGTitle       string `json:"og_title"`
+		OGDescription string `json:"og_description"`
+		OGImage       string `json:"og_image"`
+		Author        string `json:"author"`
+		Published     string `json:"published"` // RFC 3339
+		CanonicalURL  string `json:"canonical_url"`
+	}
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.URL == "" || p.Title == "" {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "url and title are required")
+	}
+	if parsed, err := url.ParseRequestURI(p.URL); err != nil || parsed.Host == "" {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid URL: %s", p.URL)
+	}
+	if p.Browser == "" {
+		p.Browser = "rpc"
+	}
+	meta := &storage.PageMeta{OGTitle: p.OGTitle, OGDescription: p.OGDescription, OGImage: p.OGImage, Author: p.Author}
+	if p.Published != "" {
+		t, err := time.Parse(time.RFC3339, p.Published)
+		if err != nil {
+			return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid published: use RFC 3339 (e.g., 2026-01-02T15:04:05Z)")
+		}
+		meta.Published = t
+	}
+
+	event := &storage.Event{
+		URL:          p.URL,
+		Title:        p.Title,
+		Browser:      p.Browser,
+		Source:       "manual",
+		Timestamp:    time.Now(),
+		Meta:         meta,
+		CanonicalURL: p.CanonicalURL,
+	}
+
+	err := m.pipeline.Capture(ctx, event, p.Body)
+	switch {
+	case errors.Is(err, hook.ErrVetoed), errors.Is(err, capture.ErrExcluded):
+		return nil, jsonrpc.Errorf(codeExcluded, "%v", err)
+	case errors.Is(err, storage.ErrBodyTooLarge):
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "%v", err)
+	case err != nil:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInternalError, "%v", err)
+	}
+	return toEventJSON(event), nil
+}
+
+type domainCountJSON struct {
+	Domain string `json:"domain"`
+	Count  int64  `json:"count"`
+}
+
+type statsJSON struct {
+	TotalEvents  int64             `json:"total_events"`
+	TotalContent int64             `json:"total_content"`
+	OldestEvent  string            `json:"oldest_event,omitempty"`
+	NewestEvent  string            `json:"newest_event,omitempty"`
+	TopDomains   []domainCountJSON `json:"top_domains"`
+}
+
+func (m *methodSet) stats(ctx context.Context) (interface{}, *jsonrpc.Error) {
+	stats, err := m.store.GetStats(ctx)
+	if err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInternalError, "stats failed: %v", err)
+	}
+
+	out := statsJSON{
+		TotalEvents:  stats.TotalEvents,
+		TotalContent: stats.TotalContent,
+		TopDomains:   make([]domainCountJSON, len(stats.TopDomains)),
+	}
+	if stats.TotalEvents > 0 {
+		out.OldestEvent = stats.OldestEvent.UTC().Format(time.RFC3339)
+		out.NewestEvent = stats.NewestEvent.UTC().Format(time.RFC3339)
+	}
+	for i, d := range stats.TopDomains {
+		out.TopDomains[i] = domainCountJSON{Domain: d.Domain, Count: d.Count}
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+// Package rpc implements a JSON-RPC 2.0 server over stdio exposing
+// Chronicle's search, get, add and stats operations, for editor plugins and
+// scripts that would rather not manage an HTTP daemon.
+package rpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/jsonrpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// Server error codes, from the range JSON-RPC 2.0 leaves to
+// implementations.
+const (
+	codeNotFound = -32001 // no event with the requested ID
+	codeExcluded = -32002 // add refused by exclusion rules or a hook
+)
+
+// Server answers JSON-RPC requests read line-by-line from an input stream.
+type Server struct {
+	methods *methodSet
+}
+
+// NewServer creates a JSON-RPC server whose methods operate on store.
+func NewServer(store storage.Store, version string) *Server {
+	return &Server{
+		methods: &methodSet{store: store, version: version, pipeline: capture.New(store)},
+	}
+}
+
+// SetPipeline makes add capture through p, with its hooks and webhooks,
+// rather than a bare pipeline over the store.
+func (s *Server) SetPipeline(p *capture.Pipeline) {
+	s.methods.pipeline = p
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled. Batches (JSON
+// arrays of requests) are answered with an array of responses.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	return jsonrpc.NewServer(s.methods.call).Serve(ctx, r, w)
+}
@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/capture"
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/hook"
+	"github.com/runnerr0/chronicle/internal/jsonrpc"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// openTestStore creates a migrated in-memory Store for testing.
+func openTestStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, storage.NewMigrationRunner(db).Run())
+
+	store, err := storage.NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+// roundtrip feeds the given request lines to a server and returns every
+// response line.
+func roundtrip(t *testing.T, store storage.Store, lines ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	srv := NewServer(store, "test")
+	require.NoError(t, srv.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out))
+
+	var responses []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line != "" {
+			responses = append(responses, line)
+		}
+	}
+	return responses
+}
+
+// call sends one request and decodes its response.
+func call(t *testing.T, store storage.Store, method, params string) jsonrpc.Response {
+	t.Helper()
+	line := `{"jsonrpc":"2.0","id":1,"method":"` + method + `"`
+	if params != "" {
+		line += `,"params":` + params
+	}
+	out := roundtrip(t, store, line+"}")
+	require.Len(t, out, 1)
+
+	var resp jsonrpc.Response
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &resp))
+	return resp
+}
+
+// result re-decodes a successful response's result into v.
+func result(t *testing.T, resp jsonrpc.Response, v interface{}) {
+	t.Helper()
+	require.Nil(t, resp.Error, "unexpected error: %+v", resp.Error)
+	data, err := json.Marshal(resp.Result)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+func seed(t *testing.T, store *storage.SQLiteStore) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, store.AddEventWithContent(ctx, &storage.Event{URL: "https://go.dev/blog/generics", Title: "Go Generics", Source: "extension", Timestamp: now.Add(-time.Hour)}, "type parameters"))
+	require.NoError(t, store.AddEvent(ctx, &storage.Event{URL: "https://sqlite.org/wal.html", Title: "SQLite WAL", Source: "extension", Timestamp: now.Add(-2 * time.Hour)}))
+}
+
+func TestPing(t *testing.T) {
+	var out map[string]string
+	result(t, call(t, openTestStore(t), "ping", ""), &out)
+	assert.Equal(t, "test", out["version"])
+}
+
+func TestSearch(t *testing.T) {
+	store := openTestStore(t)
+	seed(t, store)
+
+	var out searchResult
+	result(t, call(t, store, "search", `{"query":"generics"}`), &out)
+	require.Equal(t, 1, out.Count)
+	assert.Equal(t, "Go Generics", out.Events[0].Title)
+	assert.Equal(t, "go.dev", out.Events[0].Domain)
+
+	result(t, call(t, store, "search", `{"sort":"oldest"}`), &out)
+	require.Equal(t, 2, out.Count)
+	assert.Equal(t, "SQLite WAL", out.Events[0].Title)
+
+	resp := call(t, store, "search", `{"since":"yesterday"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+}
+
+func TestGet(t *testing.T) {
+	store := openTestStore(t)
+	seed(t, store)
+
+	var found searchResult
+	result(t, call(t, store, "search", `{"query":"generics"}`), &found)
+
+	var ev eventJSON
+	result(t, call(t, store, "get", `{"id":"`+found.Events[0].ID+`"}`), &ev)
+	assert.Equal(t, "type parameters", ev.Body)
+
+	resp := call(t, store, "get", `{"id":"CHR-00000000"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, codeNotFound, resp.Error.Code)
+
+	resp = call(t, store, "get", `{}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+}
+
+func TestAdd(t *testing.T) {
+	store := openTestStore(t)
+
+	var ev eventJSON
+	result(t, call(t, store, "add", `{"url":"https://example.com/note","title":"A note","body":"hello"}`), &ev)
+	assert.NotEmpty(t, ev.ID)
+	assert.Equal(t, "manual", ev.Source)
+	assert.Equal(t, "rpc", ev.Browser)
+	assert.True(t, ev.HasBody)
+
+	stored, err := store.GetEvent(context.Background(), ev.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored.ContentHash)
+
+	result(t, call(t, store, "add", `{"url":"https://example.com/post","title":"A post","author":"Ada","og_description":"About engines","published":"2025-06-01T08:00:00Z"}`), &ev)
+	meta, err := store.GetPageMeta(context.Background(), ev.ID)
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	assert.Equal(t, storage.PageMeta{Author: "Ada", OGDescription: "About engines", Published: time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)}, *meta)
+
+	result(t, call(t, store, "add", `{"url":"https://m.example.com/post","title":"A post","canonical_url":"https://example.com/post"}`), &ev)
+	assert.Equal(t, "https://example.com/post", ev.CanonicalURL)
+
+	resp := call(t, store, "add", `{"url":"https://example.com/x","title":"x","published":"June 1st"}`)
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "invalid published")
+
+	resp = call(t, store, "add", `{"url":"not a url","title":"x"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.CodeInvalidParams, resp.Error.Code)
+}
+
+func TestAdd_Excluded(t *testing.T) {
+	store := openTestStore(t)
+
+	// chase.com is excluded by default.
+	resp := call(t, store, "add", `{"url":"https://chase.com/accounts","title":"Accounts"}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, codeExcluded, resp.Error.Code)
+}
+
+func TestAdd_HookVeto(t *testing.T) {
+	store := openTestStore(t)
+	veto := filepath.Join(t.TempDir(), "veto")
+	require.NoError(t, os.WriteFile(veto, []byte("#!/bin/sh\necho 'private page' >&2\nexit 1\n"), 0755))
+	p := capture.New(store)
+	p.SetHooks(hook.New(config.HooksConfig{PreCapture: []string{veto}}))
+
+	var out bytes.Buffer
+	srv := NewServer(store, "test")
+	srv.SetPipeline(p)
+	in := `{"jsonrpc":"2.0","id":1,"method":"add","params":{"url":"https://go.dev/","title":"Go"}}` + "\n"
+	require.NoError(t, srv.Serve(context.Background(), strings.NewReader(in), &out))
+	var resp jsonrpc.Response
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, codeExcluded, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "private page")
+
+	stats, err := store.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.TotalEvents)
+}
+
+func TestStats(t *testing.T) {
+	store := openTestStore(t)
+	seed(t, store)
+
+	var out statsJSON
+	result(t, call(t, store, "stats", ""), &out)
+	assert.Equal(t, int64(2), out.TotalEvents)
+	assert.Equal(t, int64(1), out.TotalContent)
+	assert.Len(t, out.TopDomains, 2)
+}
+
+func TestProtocolErrors(t *testing.T) {
+	store := openTestStore(t)
+	out := roundtrip(t, store,
+		`not json`,
+		`{"jsonrpc":"1.0","id":2,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"nope"}`,
+		`{"jsonrpc":"2.0","method":"ping"}`, // notification: no reply
+		`{"jsonrpc":"2.0","id":4,"method":"get","params":["CHR-1"]}`,
+	)
+	require.Len(t, out, 4)
+
+	codes := make([]int, len(out))
+	for i, line := range out {
+		var resp jsonrpc.Response
+		require.NoError(t, json.Unmarshal([]byte(line), &resp))
+		require.NotNil(t, resp.Error, line)
+		codes[i] = resp.Error.Code
+	}
+	assert.Equal(t, []int{jsonrpc.CodeParseError, jsonrpc.CodeInvalidRequest, jsonrpc.CodeMethodNotFound, jsonrpc.CodeInvalidParams}, codes)
+}
+
+func TestBatch(t *testing.T) {
+	store := openTestStore(t)
+	out := roundtrip(t, store,
+		`[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"ping"},{"jsonrpc":"2.0","id":2,"method":"stats"}]`,
+		`[{"jsonrpc":"2.0","method":"ping"}]`,
+	)
+	require.Len(t, out, 1)
+
+	var resps []jsonrpc.Response
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &resps))
+	require.Len(t, resps, 2)
+	assert.JSONEq(t, "1", string(resps[0].ID))
+	assert.JSONEq(t, "2", string(resps[1].ID))
+}
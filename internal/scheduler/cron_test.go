@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	// 2026-03-02 is a Monday.
+	from := time.Date(2026, 3, 2, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 3 * * *", time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * 0", time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * 7", time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 3", time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)}, // day of month or weekday
+		{"0 12 * 6,12 *", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", from.Add(6 * time.Hour)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, s.Next(from), tt.spec)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "x * * * *", "@every", "@every -1h", "@yearly"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,219 @@
+// Package scheduler runs maintenance jobs (prune, backup, vacuum, embed, digest)
+// on cron-like schedules inside the daemon, and keeps each job's last
+// result for status reporting.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RunFunc runs a job once and returns a one-line summary of what it did.
+type RunFunc func(ctx context.Context) (string, error)
+
+// JobStatus is a job's schedule and the outcome of its last run.
+type JobStatus struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	Schedule     string     `json:"schedule"`
+	Next         *time.Time `json:"next,omitempty"`
+	Running      bool       `json:"running"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastResult   string     `json:"last_result,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+type job struct {
+	status   JobStatus
+	schedule Schedule
+	run      RunFunc
+}
+
+// Scheduler runs jobs when they are due, one at a time, so maintenance
+// never competes with itself for the database.
+type Scheduler struct {
+	mu    sync.Mutex
+	jobs  []*job
+	runMu sync.Mutex // held while a job runs
+	now   func() time.Time
+}
+
+// New returns a Scheduler without jobs.
+func New() *Scheduler {
+	return &Scheduler{now: time.Now}
+}
+
+// Add schedules run as the job name of type typ, at spec (see Parse).
+func (s *Scheduler) Add(name, typ, spec string, run RunFunc) error {
+	return s.AddFirst(name, typ, spec, time.Time{}, run)
+}
+
+// AddFirst is Add with the job's first run at first rather than when spec
+// next falls due; a first run already past runs as soon as Run starts. A
+// zero first is Add.
+func (s *Scheduler) AddFirst(name, typ, spec string, first time.Time, run RunFunc) error {
+	sched, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.status.Name == name {
+			return fmt.Errorf("job %s is defined twice", name)
+		}
+	}
+	j := &job{status: JobStatus{Name: name, Type: typ, Schedule: spec}, schedule: sched, run: run}
+	if first.IsZero() {
+		first = sched.Next(s.now())
+	}
+	j.setNext(first)
+	s.jobs = append(s.jobs, j)
+	return nil
+}
+
+// Run runs jobs as they fall due until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		next := s.nextDue()
+		wait := time.Duration(1<<63 - 1)
+		if !next.IsZero() {
+			wait = next.Sub(s.now())
+		}
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := s.now()
+		for _, j := range s.due(now) {
+			if ctx.Err() != nil {
+				return
+			}
+			s.runJob(ctx, j)
+		}
+	}
+}
+
+// RunNow runs the named job immediately and returns its status after.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (JobStatus, error) {
+	s.mu.Lock()
+	var found *job
+	for _, j := range s.jobs {
+		if j.status.Name == name {
+			found = j
+		}
+	}
+	s.mu.Unlock()
+	if found == nil {
+		return JobStatus{}, fmt.Errorf("no job named %q", name)
+	}
+	s.runJob(ctx, found)
+	return s.statusOf(found), nil
+}
+
+// Status returns every job's status, by name.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, copyStatus(j.status))
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j *job) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.mu.Lock()
+	j.status.Running = true
+	s.mu.Unlock()
+
+	start := s.now()
+	slog.Info("job started", "job", j.status.Name, "type", j.status.Type)
+	result, err := j.run(ctx)
+	elapsed := s.now().Sub(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.status.Running = false
+	j.status.Runs++
+	j.status.LastRun = &start
+	j.status.LastDuration = elapsed.Round(time.Millisecond).String()
+	j.status.LastResult, j.status.LastError = result, ""
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+		slog.Warn("job failed", "job", j.status.Name, "elapsed", elapsed, "err", err)
+	} else {
+		slog.Info("job finished", "job", j.status.Name, "elapsed", elapsed, "result", result)
+	}
+	j.setNext(j.schedule.Next(s.now()))
+}
+
+// nextDue returns the earliest next run, or the zero time if none.
+func (s *Scheduler) nextDue() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, j := range s.jobs {
+		if j.status.Next != nil && (next.IsZero() || j.status.Next.Before(next)) {
+			next = *j.status.Next
+		}
+	}
+	return next
+}
+
+// due returns the jobs whose next run is at or before now.
+func (s *Scheduler) due(now time.Time) []*job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*job
+	for _, j := range s.jobs {
+		if j.status.Next != nil && !j.status.Next.After(now) {
+			due = append(due, j)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) statusOf(j *job) JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyStatus(j.status)
+}
+
+// setNext records the next run; a zero time means the job never runs.
+func (j *job) setNext(t time.Time) {
+	if t.IsZero() {
+		j.status.Next = nil
+		return
+	}
+	j.status.Next = &t
+}
+
+// copyStatus copies st so callers can't change the scheduler's times.
+func copyStatus(st JobStatus) JobStatus {
+	if st.Next != nil {
+		next := *st.Next
+		st.Next = &next
+	}
+	if st.LastRun != nil {
+		last := *st.LastRun
+		st.LastRun = &last
+	}
+	return st
+}
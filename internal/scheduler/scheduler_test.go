@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunNowAndStatus(t *testing.T) {
+	s := New()
+	calls := 0
+	require.NoError(t, s.Add("nightly-prune", "prune", "0 3 * * *", func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 2 {
+			return "", errors.New("database is locked")
+		}
+		return "pruned 4 events", nil
+	}))
+	assert.ErrorContains(t, s.Add("nightly-prune", "vacuum", "@daily", nil), "defined twice")
+	assert.ErrorContains(t, s.Add("bad", "vacuum", "soon", nil), "job bad: invalid schedule")
+
+	st, err := s.RunNow(context.Background(), "nightly-prune")
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.Runs)
+	assert.Equal(t, "pruned 4 events", st.LastResult)
+	assert.Empty(t, st.LastError)
+	require.NotNil(t, st.LastRun)
+	require.NotNil(t, st.Next)
+	assert.Equal(t, 3, st.Next.Hour())
+
+	st, err = s.RunNow(context.Background(), "nightly-prune")
+	require.NoError(t, err)
+	assert.Equal(t, 2, st.Runs)
+	assert.Equal(t, 1, st.Failures)
+	assert.Equal(t, "database is locked", st.LastError)
+	assert.Empty(t, st.LastResult)
+
+	_, err = s.RunNow(context.Background(), "nope")
+	assert.ErrorContains(t, err, `no job named "nope"`)
+
+	all := s.Status()
+	require.Len(t, all, 1)
+	assert.Equal(t, "prune", all[0].Type)
+	assert.False(t, all[0].Running)
+}
+
+func TestScheduler_RunsDueJobs(t *testing.T) {
+	s := New()
+	var runs atomic.Int32
+	require.NoError(t, s.Add("tick", "vacuum", "@every 20ms", func(ctx context.Context) (string, error) {
+		runs.Add(1)
+		return "ok", nil
+	}))
+	require.NoError(t, s.Add("later", "prune", "@every 1h", func(ctx context.Context) (string, error) {
+		t.Error("job ran before it was due")
+		return "", nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	assert.GreaterOrEqual(t, runs.Load(), int32(3))
+	for _, st := range s.Status() {
+		if st.Name == "tick" {
+			assert.Equal(t, int(runs.Load()), st.Runs)
+		}
+	}
+}
+
+func TestScheduler_AddFirst(t *testing.T) {
+	s := New()
+	var runs atomic.Int32
+	// Overdue: runs as soon as Run starts, then on its schedule.
+	require.NoError(t, s.AddFirst("retention", "prune", "@every 1h", time.Now().Add(-time.Minute), func(ctx context.Context) (string, error) {
+		runs.Add(1)
+		return "ok", nil
+	}))
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, s.AddFirst("later", "vacuum", "@every 1m", later, func(ctx context.Context) (string, error) {
+		t.Error("job ran before its first run")
+		return "", nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	assert.Equal(t, int32(1), runs.Load())
+	all := s.Status()
+	require.Len(t, all, 2)
+	assert.Equal(t, later, *all[0].Next)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *all[1].Next, time.Minute)
+}
+
+func TestScheduler_RunWithoutJobsStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		New().Run(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -0,0 +1,157 @@
+package source
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/webpage"
+)
+
+func init() {
+	Register("rss", newRSS)
+}
+
+// rssTimeout bounds one feed fetch.
+const rssTimeout = 30 * time.Second
+
+// RSS is a source that reads the items of an RSS 2.0 or Atom feed. Each
+// item is captured with its publication date, and its description or
+// content as the body.
+type RSS struct {
+	name string
+	url  string
+	HTTP *http.Client
+}
+
+// newRSS builds an RSS source; the url option names the feed.
+func newRSS(name string, options map[string]string) (Source, error) {
+	url := options["url"]
+	if url == "" {
+		return nil, fmt.Errorf("source %s: rss needs the url option", name)
+	}
+	return &RSS{name: name, url: url, HTTP: &http.Client{Timeout: rssTimeout}}, nil
+}
+
+func (r *RSS) Name() string { return r.name }
+
+// Fetch downloads and parses the feed. Items without a link or a date
+// are skipped.
+func (r *RSS) Fetch(ctx context.Context) ([]CaptureEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	req.Header.Set("User-Agent", "chronicle")
+
+	resp, err := r.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", r.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetch %s: %s", r.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, webpage.MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", r.url, err)
+	}
+	events, err := parseFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", r.url, err)
+	}
+	return events, nil
+}
+
+// feed decodes both formats: RSS items sit under channel, Atom entries
+// under the root.
+type feed struct {
+	Items   []feedItem `xml:"channel>item"`
+	Entries []feedItem `xml:"entry"`
+}
+
+type feedItem struct {
+	Title       string     `xml:"title"`
+	Links       []feedLink `xml:"link"`
+	PubDate     string     `xml:"pubDate"`
+	Published   string     `xml:"published"`
+	Updated     string     `xml:"updated"`
+	Description string     `xml:"description"`
+	Summary     string     `xml:"summary"`
+	Content     string     `xml:"content"`
+	Encoded     string     `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+// feedLink is an RSS <link>, which holds the URL as text, or an Atom one,
+// which has it in href.
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedTimeLayouts are the date formats feeds use: RFC 822 variants for
+// RSS, RFC 3339 for Atom.
+var feedTimeLayouts = []string{
+	time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC3339,
+}
+
+func parseFeed(data []byte) ([]CaptureEvent, error) {
+	var f feed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	var events []CaptureEvent
+	for _, item := range append(f.Items, f.Entries...) {
+		link := ""
+		for _, l := range item.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = strings.TrimSpace(l.Href + l.Text)
+				break
+			}
+		}
+		ts := parseFeedTime(item.PubDate, item.Published, item.Updated)
+		if link == "" || ts.IsZero() {
+			continue
+		}
+		body := item.Encoded
+		for _, b := range []string{item.Content, item.Description, item.Summary} {
+			if body == "" {
+				body = b
+			}
+		}
+		if body != "" {
+			body = webpage.Parse(body).Text
+		}
+		events = append(events, CaptureEvent{
+			URL:       link,
+			Title:     strings.TrimSpace(item.Title),
+			Timestamp: ts,
+			Body:      body,
+		})
+	}
+	return events, nil
+}
+
+// parseFeedTime returns the first of values that parses as a feed date.
+func parseFeedTime(values ...string) time.Time {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		for _, layout := range feedTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,87 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+  <title>Example</title>
+  <item>
+    <title>Tides explained</title>
+    <link>https://example.com/tides</link>
+    <pubDate>Mon, 02 Mar 2026 09:30:00 +0000</pubDate>
+    <description>&lt;p&gt;Short &lt;b&gt;summary&lt;/b&gt;&lt;/p&gt;</description>
+    <content:encoded>&lt;p&gt;The full article.&lt;/p&gt;</content:encoded>
+  </item>
+  <item>
+    <title>No date</title>
+    <link>https://example.com/undated</link>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <entry>
+    <title>Moon phases</title>
+    <link rel="self" href="https://example.com/feed/moon"/>
+    <link rel="alternate" href="https://example.com/moon"/>
+    <updated>2026-03-03T10:00:00Z</updated>
+    <summary>Why the moon changes.</summary>
+  </entry>
+</feed>`
+
+func TestParseFeed_RSS(t *testing.T) {
+	events, err := parseFeed([]byte(rssFeed))
+	require.NoError(t, err)
+	require.Len(t, events, 1, "items without a date are skipped")
+	assert.Equal(t, "https://example.com/tides", events[0].URL)
+	assert.Equal(t, "Tides explained", events[0].Title)
+	assert.True(t, events[0].Timestamp.Equal(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "The full article.", events[0].Body)
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	events, err := parseFeed([]byte(atomFeed))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "https://example.com/moon", events[0].URL)
+	assert.True(t, events[0].Timestamp.Equal(time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Why the moon changes.", events[0].Body)
+}
+
+func TestRSS_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(rssFeed))
+	}))
+	defer srv.Close()
+
+	src, err := New("rss", "example", map[string]string{"url": srv.URL + "/feed.xml"})
+	require.NoError(t, err)
+	events, err := src.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	src, err = New("rss", "example", map[string]string{"url": srv.URL + "/missing"})
+	require.NoError(t, err)
+	_, err = src.Fetch(context.Background())
+	assert.ErrorContains(t, err, "404")
+
+	_, err = New("rss", "example", nil)
+	assert.ErrorContains(t, err, "rss needs the url option")
+}
@@ -0,0 +1,131 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("shell", newShell)
+}
+
+// maxShellTitle caps the command kept as a pulled URL's title.
+const maxShellTitle = 200
+
+// Shell is a source that reads the http(s) URLs in shell commands from a
+// bash or zsh history file, with the command as the title. Only commands
+// with a recorded time are read, since pulls dedup by it: zsh needs
+// EXTENDED_HISTORY and bash HISTTIMEFORMAT.
+type Shell struct {
+	name string
+	path string
+}
+
+// newShell builds a shell history source. The path option names the
+// history file; the default is $HISTFILE, else ~/.zsh_history when it
+// exists, else ~/.bash_history.
+func newShell(name string, options map[string]string) (Source, error) {
+	path := options["path"]
+	if path == "" {
+		path = os.Getenv("HISTFILE")
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", name, err)
+		}
+		path = filepath.Join(home, ".bash_history")
+		if zsh := filepath.Join(home, ".zsh_history"); fileExists(zsh) {
+			path = zsh
+		}
+	} else if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", name, err)
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	return &Shell{name: name, path: path}, nil
+}
+
+func (s *Shell) Name() string { return s.name }
+
+var (
+	// zshEntry is a zsh EXTENDED_HISTORY line: ": <start>:<elapsed>;<command>".
+	zshEntry = regexp.MustCompile(`^: (\d+):\d+;(.*)$`)
+	// bashTime is the "#<epoch>" line bash writes before a command when
+	// HISTTIMEFORMAT is set.
+	bashTime   = regexp.MustCompile(`^#(\d{9,})$`)
+	shellURL   = regexp.MustCompile(`https?://[^\s'"<>\x60|;]+`)
+	urlTrailer = ".,:)]}"
+)
+
+// Fetch reads the history file.
+func (s *Shell) Fetch(ctx context.Context) ([]CaptureEvent, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []CaptureEvent
+	var pending time.Time // bash: time for the next command
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		var ts time.Time
+		var cmd string
+		if m := bashTime.FindStringSubmatch(line); m != nil {
+			pending = unixTime(m[1])
+			continue
+		}
+		if m := zshEntry.FindStringSubmatch(line); m != nil {
+			ts, cmd = unixTime(m[1]), m[2]
+		} else {
+			ts, cmd = pending, line
+		}
+		pending = time.Time{}
+		if ts.IsZero() {
+			continue
+		}
+		for _, u := range shellURL.FindAllString(cmd, -1) {
+			events = append(events, CaptureEvent{
+				URL:       strings.TrimRight(u, urlTrailer),
+				Title:     truncateTitle(strings.TrimSpace(cmd)),
+				Timestamp: ts,
+			})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", s.path, err)
+	}
+	return events, nil
+}
+
+func unixTime(s string) time.Time {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func truncateTitle(s string) string {
+	if r := []rune(s); len(r) > maxShellTitle {
+		return string(r[:maxShellTitle-1]) + "…"
+	}
+	return s
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Zsh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".zsh_history")
+	require.NoError(t, os.WriteFile(path, []byte(
+		": 1772443800:0;curl -s 'https://api.example.com/v1/items?page=2'\n"+
+			": 1772443860:3;git status\n"+
+			"open https://untimed.example.com\n"+
+			": 1772443900:0;echo see https://a.example/x, and (https://b.example/y).\n"), 0600))
+
+	src, err := New("shell", "zsh", map[string]string{"path": path})
+	require.NoError(t, err)
+	events, err := src.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "https://api.example.com/v1/items?page=2", events[0].URL)
+	assert.Equal(t, "curl -s 'https://api.example.com/v1/items?page=2'", events[0].Title)
+	assert.True(t, events[0].Timestamp.Equal(time.Unix(1772443800, 0)))
+	assert.Equal(t, "https://a.example/x", events[1].URL)
+	assert.Equal(t, "https://b.example/y", events[2].URL)
+}
+
+func TestShell_BashTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".bash_history")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"wget https://old.example.com/file\n"+
+			"#1772443800\n"+
+			"wget https://new.example.com/file\n"), 0600))
+
+	src, err := New("shell", "bash", map[string]string{"path": path})
+	require.NoError(t, err)
+	events, err := src.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1, "commands without a time are skipped")
+	assert.Equal(t, "https://new.example.com/file", events[0].URL)
+	assert.True(t, events[0].Timestamp.Equal(time.Unix(1772443800, 0)))
+}
+
+func TestShell_MissingFile(t *testing.T) {
+	src, err := New("shell", "bash", map[string]string{"path": filepath.Join(t.TempDir(), "nope")})
+	require.NoError(t, err)
+	_, err = src.Fetch(context.Background())
+	assert.Error(t, err)
+}
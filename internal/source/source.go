@@ -0,0 +1,79 @@
+// Package source defines ingestion adapters: inputs other than the browser
+// extension that find pages worth capturing, such as RSS feeds or the URLs
+// in shell history. Each adapter type registers a Factory; `chronicle pull
+// --source NAME` builds the source configured under NAME and stores what
+// its Fetch returns.
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CaptureEvent is a page a source found.
+type CaptureEvent struct {
+	URL       string
+	Title     string
+	Timestamp time.Time // when the page was seen or published; required, as pulls dedup by it
+	Body      string    // page text, when the source carries it; "" stores no body
+}
+
+// Source is an ingestion adapter.
+type Source interface {
+	// Name is the name the source was configured under; pulled events are
+	// stored with it as their source.
+	Name() string
+	// Fetch returns the events the source holds now. Pulls run it again
+	// each time, so it may return events already stored.
+	Fetch(ctx context.Context) ([]CaptureEvent, error)
+}
+
+// Factory builds a source called name from its adapter options.
+type Factory func(name string, options map[string]string) (Source, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register makes an adapter type available to New. Like
+// database/sql.Register, it panics if typ is registered twice or factory
+// is nil.
+func Register(typ string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if factory == nil {
+		panic("source: Register factory is nil")
+	}
+	if _, dup := factories[typ]; dup {
+		panic("source: Register called twice for type " + typ)
+	}
+	factories[typ] = factory
+}
+
+// New builds a source of adapter type typ called name.
+func New(typ, name string, options map[string]string) (Source, error) {
+	mu.RLock()
+	factory, ok := factories[typ]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown source type %q (have %s)", typ, strings.Join(Types(), ", "))
+	}
+	return factory(name, options)
+}
+
+// Types returns the registered adapter types, sorted.
+func Types() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	types := make([]string, 0, len(factories))
+	for typ := range factories {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
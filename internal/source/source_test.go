@@ -0,0 +1,36 @@
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSource struct{ name string }
+
+func (s staticSource) Name() string { return s.name }
+
+func (s staticSource) Fetch(ctx context.Context) ([]CaptureEvent, error) { return nil, nil }
+
+func TestRegistry(t *testing.T) {
+	Register("static-test", func(name string, options map[string]string) (Source, error) {
+		return staticSource{name: name}, nil
+	})
+	assert.Contains(t, Types(), "static-test")
+	assert.Contains(t, Types(), "rss")
+	assert.Contains(t, Types(), "shell")
+
+	src, err := New("static-test", "mine", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mine", src.Name())
+
+	_, err = New("nope", "mine", nil)
+	assert.ErrorContains(t, err, `unknown source type "nope" (have rss, shell, static-test)`)
+
+	assert.Panics(t, func() {
+		Register("static-test", func(string, map[string]string) (Source, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { Register("nil-test", nil) })
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EventsPerDay counts events per calendar day from since through until,
+// inclusive. Days are taken in since's location and every day in the range
+// is present, so days without captures have a zero count.
+func (s *SQLiteStore) EventsPerDay(ctx context.Context, since, until time.Time) ([]DayCount, error) {
+	if until.Before(since) {
+		return nil, fmt.Errorf("events per day: until %s is before since %s",
+			until.Format(time.RFC3339), since.Format(time.RFC3339))
+	}
+
+	loc := since.Location()
+	first := startOfDay(since)
+	last := startOfDay(until.In(loc))
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT date(ts, ?) AS day, COUNT(*) FROM events
+		WHERE ts >= ? AND ts <= ?
+		GROUP BY day`,
+		zoneModifier(since),
+		since.UTC().Format(time.RFC3339),
+		until.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events per day: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var day string
+		var n int64
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, err
+		}
+		counts[day] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var days []DayCount
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		days = append(days, DayCount{Day: d, Count: counts[d.Format("2006-01-02")]})
+	}
+	return days, nil
+}
+
+// ActivityByTime counts events from since through until by weekday and hour
+// of day, in since's location.
+func (s *SQLiteStore) ActivityByTime(ctx context.Context, since, until time.Time) (*TimeActivity, error) {
+	if until.Before(since) {
+		return nil, fmt.Errorf("activity by time: until %s is before since %s",
+			until.Format(time.RFC3339), since.Format(time.RFC3339))
+	}
+
+	mod := zoneModifier(since)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT CAST(strftime('%w', ts, ?) AS INTEGER) AS wd,
+		        CAST(strftime('%H', ts, ?) AS INTEGER) AS hr,
+		        COUNT(*)
+		FROM events
+		WHERE ts >= ? AND ts <= ?
+		GROUP BY wd, hr`,
+		mod, mod,
+		since.UTC().Format(time.RFC3339),
+		until.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("activity by time: %w", err)
+	}
+	defer rows.Close()
+
+	a := &TimeActivity{}
+	for rows.Next() {
+		var wd, hr int
+		var n int64
+		if err := rows.Scan(&wd, &hr, &n); err != nil {
+			return nil, err
+		}
+		if wd >= 0 && wd < 7 && hr >= 0 && hr < 24 {
+			a.Counts[wd][hr] = n
+		}
+	}
+	return a, rows.Err()
+}
+
+// ContentTotals sums the word, character and byte counts of the bodies
+// captured between since and until (inclusive).
+func (s *SQLiteStore) ContentTotals(ctx context.Context, since, until time.Time) (*ContentTotals, error) {
+	t := &ContentTotals{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(c.word_count), 0), COALESCE(SUM(c.char_count), 0), COALESCE(SUM(c.byte_size), 0)
+		FROM content c
+		JOIN events e ON e.id = c.event_id
+		WHERE e.ts >= ? AND e.ts <= ?`,
+		since.UTC().Format(time.RFC3339),
+		until.UTC().Format(time.RFC3339),
+	).Scan(&t.Bodies, &t.Words, &t.Chars, &t.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("content totals: %w", err)
+	}
+	return t, nil
+}
+
+// startOfDay returns midnight at the start of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// zoneModifier returns a SQLite date modifier shifting UTC timestamps into
+// t's zone. The offset in effect at t is used for the whole range, so days
+// either side of a DST change may be off by an hour at their edges.
+func zoneModifier(t time.Time) string {
+	_, offset := t.Zone()
+	return fmt.Sprintf("%+d seconds", offset)
+}
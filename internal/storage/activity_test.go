@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsPerDay(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	for _, ts := range []string{"2026-03-01T03:00:00Z", "2026-03-01T12:00:00Z", "2026-03-03T12:00:00Z", "2026-03-09T12:00:00Z"} {
+		at, err := time.Parse(time.RFC3339, ts)
+		require.NoError(t, err)
+		require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com", Title: "A", Source: "manual", Timestamp: at}))
+	}
+
+	// UTC-5: the 03:00Z capture falls on the previous local day.
+	loc := time.FixedZone("EST", -5*3600)
+	since := time.Date(2026, 2, 28, 0, 0, 0, 0, loc)
+	until := time.Date(2026, 3, 3, 23, 59, 59, 0, loc)
+
+	days, err := store.EventsPerDay(ctx, since, until)
+	require.NoError(t, err)
+	require.Len(t, days, 4)
+
+	var got []int64
+	for _, d := range days {
+		got = append(got, d.Count)
+	}
+	assert.Equal(t, []int64{1, 1, 0, 1}, got)
+	assert.Equal(t, since, days[0].Day)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, loc), days[3].Day)
+}
+
+func TestEventsPerDay_InvalidRange(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+
+	_, err := store.EventsPerDay(context.Background(), now, now.Add(-time.Hour))
+	assert.Error(t, err)
+}
+
+func TestActivityByTime(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	// 2026-03-02 is a Monday.
+	for _, ts := range []string{"2026-03-02T14:10:00Z", "2026-03-02T14:50:00Z", "2026-03-03T02:00:00Z", "2026-04-01T00:00:00Z"} {
+		at, err := time.Parse(time.RFC3339, ts)
+		require.NoError(t, err)
+		require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com", Title: "A", Source: "manual", Timestamp: at}))
+	}
+
+	loc := time.FixedZone("EST", -5*3600)
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	until := time.Date(2026, 3, 31, 0, 0, 0, 0, loc)
+
+	a, err := store.ActivityByTime(ctx, since, until)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), a.Counts[time.Monday][9])
+	// 02:00Z Tuesday is 21:00 Monday in UTC-5.
+	assert.Equal(t, int64(1), a.Counts[time.Monday][21])
+
+	weekdays := a.ByWeekday()
+	assert.Equal(t, int64(3), weekdays[time.Monday])
+	assert.Equal(t, int64(0), weekdays[time.Tuesday])
+	hours := a.ByHour()
+	assert.Equal(t, int64(2), hours[9])
+	assert.Equal(t, int64(0), hours[14])
+}
+
+func TestContentTotals(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddEventWithContent(ctx, &Event{URL: "https://a.com", Title: "A", Source: "manual", Timestamp: at}, "Tides rise twice daily."))
+	require.NoError(t, store.AddEventWithContent(ctx, &Event{URL: "https://b.com", Title: "B", Source: "manual", Timestamp: at}, "潮汐 tides"))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://c.com", Title: "C", Source: "manual", Timestamp: at}))
+	require.NoError(t, store.AddEventWithContent(ctx, &Event{URL: "https://d.com", Title: "D", Source: "manual", Timestamp: at.AddDate(0, 1, 0)}, "Outside the window."))
+
+	totals, err := store.ContentTotals(ctx, at.AddDate(0, 0, -1), at.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totals.Bodies)
+	assert.Equal(t, int64(4+3), totals.Words)
+	assert.Equal(t, int64(23+8), totals.Chars)
+	assert.Equal(t, int64(23+12), totals.Bytes)
+
+	empty, err := store.ContentTotals(ctx, at.AddDate(1, 0, 0), at.AddDate(1, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, &ContentTotals{}, empty)
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchSizes returns the row counts to benchmark search and prune at.
+// Override with CHRONICLE_BENCH_ROWS, e.g. "100000,1000000,10000000".
+func benchSizes(b *testing.B) []int {
+	spec := os.Getenv("CHRONICLE_BENCH_ROWS")
+	if spec == "" {
+		return []int{1000, 10000}
+	}
+	var sizes []int
+	for _, s := range strings.Split(spec, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			b.Fatalf("invalid CHRONICLE_BENCH_ROWS %q: %v", spec, err)
+		}
+		sizes = append(sizes, n)
+	}
+	return sizes
+}
+
+// openBenchStore creates a migrated file-backed store, so timings include
+// real disk I/O rather than an in-memory database.
+func openBenchStore(b *testing.B) *SQLiteStore {
+	b.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(b.TempDir(), "bench.db")+"?_foreign_keys=on")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	if err := NewMigrationRunner(db).Run(); err != nil {
+		b.Fatal(err)
+	}
+	store, err := NewSQLiteStore(db)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { store.Close() })
+	return store
+}
+
+// seedBenchStore inserts n fake events.
+func seedBenchStore(b *testing.B, store *SQLiteStore, gen *FakeGenerator, n int) {
+	b.Helper()
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		event, body := gen.Next()
+		var err error
+		if body != "" {
+			err = store.AddEventWithContent(ctx, event, body)
+		} else {
+			err = store.AddEvent(ctx, event)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAddEvent(b *testing.B) {
+	store := openBenchStore(b)
+	gen := NewFakeGenerator(1, time.Now())
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		event, _ := gen.Next()
+		if err := store.AddEvent(ctx, event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAddEventWithContent(b *testing.B) {
+	store := openBenchStore(b)
+	gen := NewFakeGenerator(1, time.Now())
+	ctx := context.Background()
+	body := strings.Repeat("lorem ipsum dolor sit amet ", 200)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		event, _ := gen.Next()
+		if err := store.AddEventWithContent(ctx, event, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchFTS(b *testing.B) {
+	for _, n := range benchSizes(b) {
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			store := openBenchStore(b)
+			gen := NewFakeGenerator(1, time.Now())
+			seedBenchStore(b, store, gen, n)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := store.SearchEvents(ctx, SearchQuery{Query: gen.Query(), Limit: 10}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPruneExpired(b *testing.B) {
+	for _, n := range benchSizes(b) {
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			ctx := context.Background()
+			cutoff := time.Now().Add(-30 * 24 * time.Hour)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				store := openBenchStore(b)
+				seedBenchStore(b, store, NewFakeGenerator(int64(i), time.Now()), n)
+				b.StartTimer()
+
+				if _, err := store.PruneExpired(ctx, cutoff); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
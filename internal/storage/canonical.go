@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+)
+
+// cleanCanonicalURL resolves a page's declared canonical URL against the
+// URL it was captured at. It returns "" when there is none, when it is not
+// an absolute http(s) URL, or when it is the captured URL itself, so
+// CanonicalURL is only set when it says something.
+func cleanCanonicalURL(pageURL, canonical string) string {
+	canonical = strings.TrimSpace(canonical)
+	if canonical == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(canonical)
+	if err != nil {
+		return ""
+	}
+	u := base.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return ""
+	}
+	u.Fragment, u.RawFragment = "", ""
+	if s := u.String(); s != pageURL {
+		return s
+	}
+	return ""
+}
+
+// pageKeySQL is the SQL expression for Event.PageURL.
+const pageKeySQL = "COALESCE(NULLIF(canonical_url, ''), url)"
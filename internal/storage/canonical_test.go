@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanCanonicalURL(t *testing.T) {
+	page := "https://m.example.com/story?utm_source=x#top"
+	assert.Equal(t, "https://example.com/story", cleanCanonicalURL(page, " https://example.com/story#c "))
+	assert.Equal(t, "https://m.example.com/story", cleanCanonicalURL(page, "/story"), "relative to the page")
+	assert.Equal(t, "", cleanCanonicalURL(page, ""))
+	assert.Equal(t, "", cleanCanonicalURL(page, "ftp://example.com/story"))
+	assert.Equal(t, "", cleanCanonicalURL("https://example.com/story", "https://example.com/story"), "the page itself")
+}
+
+func TestCanonicalURL(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	add := func(url, canonical string, at time.Time) *Event {
+		e := &Event{URL: url, CanonicalURL: canonical, Title: url, Source: "manual", Timestamp: at}
+		require.NoError(t, store.AddEvent(ctx, e))
+		return e
+	}
+	amp := add("https://example.com/story/amp", "https://example.com/story", now.AddDate(0, 0, -20))
+	add("https://m.example.com/story", "https://example.com/story", now.AddDate(0, 0, -15))
+	self := add("https://example.com/story", "https://example.com/story", now.AddDate(0, 0, -10))
+	add("https://other.example/", "", now.AddDate(0, 0, -12))
+
+	got, err := store.GetEvent(ctx, amp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/story", got.CanonicalURL)
+	assert.Equal(t, "https://example.com/story", got.PageURL())
+
+	got, err = store.GetEvent(ctx, self.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.CanonicalURL, "dropped when it is the URL itself")
+	assert.Equal(t, "https://example.com/story", got.PageURL())
+
+	pages, err := store.PastCaptures(ctx, now.AddDate(0, 0, -60), now, 10)
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, self.ID, pages[0].ID)
+	assert.Equal(t, 3, pages[0].Visits, "variants collapse to one page")
+	assert.Equal(t, "https://other.example/", pages[1].PageURL())
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SetDedupeWindow makes later captures that repeat an event stored within
+// window of them count as another visit to it rather than a new event: the
+// same page (see Event.PageURL) with no body or the same body, or the same
+// body under another URL. Zero or less turns deduplication off.
+func (s *SQLiteStore) SetDedupeWindow(window time.Duration) {
+	s.dedupeWindow = max(window, 0)
+}
+
+// foldDuplicate looks in tx for an event that event repeats under the
+// dedupe window and, if there is one, bumps its visit count and points
+// event at it, reporting whether it did.
+func (s *SQLiteStore) foldDuplicate(ctx context.Context, tx *sql.Tx, event *Event) (bool, error) {
+	from := event.Timestamp.Add(-s.dedupeWindow).UTC().Format(time.RFC3339)
+	to := event.Timestamp.Add(s.dedupeWindow).UTC().Format(time.RFC3339)
+
+	var id string
+	var visits int
+	err := tx.QueryRowContext(ctx,
+		`SELECT id, visit_count FROM events
+		WHERE ts BETWEEN ? AND ?
+		  AND ((`+pageKeySQL+` = ? AND (? = '' OR content_hash = ?))
+		    OR (? != '' AND content_hash = ?))
+		ORDER BY ts DESC
+		LIMIT 1`,
+		from, to, event.PageURL(), event.ContentHash, event.ContentHash, event.ContentHash, event.ContentHash,
+	).Scan(&id, &visits)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find duplicate: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET visit_count = visit_count + 1 WHERE id = ?`, id); err != nil {
+		return false, fmt.Errorf("count visit: %w", err)
+	}
+	event.ID, event.Visits = id, visits+1
+	return true, nil
+}
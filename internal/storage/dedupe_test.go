@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupe_FoldsRepeatVisits(t *testing.T) {
+	store := openTestStore(t)
+	store.SetDedupeWindow(5 * time.Minute)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	first := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "extension", Timestamp: base}
+	require.NoError(t, store.AddEvent(ctx, first))
+	assert.Equal(t, 1, first.Visits)
+
+	again := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "extension", Timestamp: base.Add(2 * time.Minute)}
+	require.NoError(t, store.AddEvent(ctx, again))
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, 2, again.Visits)
+
+	// A body the first capture lacked is worth keeping.
+	withBody := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "extension", Timestamp: base.Add(3 * time.Minute), ContentHash: "h1"}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "posts"))
+	assert.NotEqual(t, first.ID, withBody.ID)
+
+	// The same body under another URL is the same page.
+	mirror := &Event{URL: "https://mirror.example.com/blog", Title: "Mirror", Source: "extension", Timestamp: base.Add(4 * time.Minute), ContentHash: "h1"}
+	require.NoError(t, store.AddEventWithContent(ctx, mirror, "posts"))
+	assert.Equal(t, withBody.ID, mirror.ID)
+	assert.Equal(t, 2, mirror.Visits)
+
+	// Outside the window it is a new event.
+	later := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "extension", Timestamp: base.Add(time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, later))
+	assert.NotEqual(t, first.ID, later.ID)
+	assert.Equal(t, 1, later.Visits)
+
+	got, err := store.GetEvent(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Visits)
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "blog"})
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	total, err := store.CountEvents(ctx, SearchQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+}
+
+func TestDedupe_OffByDefault(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	a := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "manual", Timestamp: ts}
+	b := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "manual", Timestamp: ts}
+	require.NoError(t, store.AddEvent(ctx, a))
+	require.NoError(t, store.AddEvent(ctx, b))
+	assert.NotEqual(t, a.ID, b.ID)
+	assert.Equal(t, 1, b.Visits)
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/vectorstore"
+)
+
+// SetEmbedding stores vector as eventID's embedding generated by model,
+// replacing any earlier one, and marks the event as embedded.
+func (s *SQLiteStore) SetEmbedding(ctx context.Context, eventID, model string, vector []float32) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("set embedding: empty vector")
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, `UPDATE events SET has_embedding = 1 WHERE id = ?`, eventID)
+	if err != nil {
+		return fmt.Errorf("set embedding: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("event %s not found", eventID)
+	}
+	if s.vectors != nil {
+		// Stored before the metadata commits, so a failed write leaves the
+		// event to be embedded again.
+		if err := s.vectors.Put(ctx, model, eventID, vector); err != nil {
+			return fmt.Errorf("set embedding: %w", err)
+		}
+	} else if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO embeddings (event_id, vector) VALUES (?, ?)`,
+		eventID, encodeVector(vector),
+	); err != nil {
+		return fmt.Errorf("set embedding: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO embedding_metadata (event_id, model_name, dimensions, embedded_at) VALUES (?, ?, ?, ?)`,
+		eventID, model, len(vector), time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("set embedding metadata: %w", err)
+	}
+	return tx.Commit()
+}
+
+// EventsWithoutEmbedding returns up to limit events with no embedding from
+// model, newest first; with contentOnly, only events with a body. limit <= 0
+// returns every one.
+func (s *SQLiteStore) EventsWithoutEmbedding(ctx context.Context, model string, contentOnly bool, limit int) ([]Event, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	where := "id NOT IN (SELECT event_id FROM embedding_metadata WHERE model_name = ?)"
+	if s.vectors == nil {
+		// Vectors left behind in a vector store no longer in use are made
+		// again.
+		where = `id NOT IN (SELECT m.event_id FROM embedding_metadata m
+			JOIN embeddings v ON v.event_id = m.event_id WHERE m.model_name = ?)`
+	}
+	if contentOnly {
+		where += " AND has_body = 1"
+	}
+	return s.scanEvents(ctx,
+		`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+		FROM events
+		WHERE `+where+`
+		ORDER BY ts DESC
+		LIMIT ?`,
+		model, limit,
+	)
+}
+
+// NearestEvents returns the events matching q's filters whose embeddings
+// from model are most similar to vector, most similar first, paged by q's
+// Limit and Offset. q's Query and Fields are ignored; a newest or oldest
+// Sort orders the page by time instead. Similarity is cosine similarity,
+// computed over every candidate vector.
+func (s *SQLiteStore) NearestEvents(ctx context.Context, q SearchQuery, model string, vector []float32) ([]Event, error) {
+	switch q.Sort {
+	case "", SortRelevance, SortNewest, SortOldest:
+	default:
+		return nil, fmt.Errorf("invalid sort %q", q.Sort)
+	}
+
+	candidates, err := s.nearest(ctx, q, model, vector)
+	if err != nil {
+		return nil, err
+	}
+	if q.Offset >= len(candidates) {
+		return []Event{}, nil
+	}
+	candidates = candidates[q.Offset:]
+	if q.Limit > 0 && len(candidates) > q.Limit {
+		candidates = candidates[:q.Limit]
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return s.eventsInOrder(ctx, ids, q.Sort)
+}
+
+// scoredID is a search candidate's event ID and score.
+type scoredID struct {
+	id    string
+	score float64
+}
+
+// nearest returns every event matching q's filters with an embedding from
+// model, with its cosine similarity to vector, most similar first.
+func (s *SQLiteStore) nearest(ctx context.Context, q SearchQuery, model string, vector []float32) ([]scoredID, error) {
+	if s.vectors != nil {
+		return s.nearestInStore(ctx, q, model, vector)
+	}
+	clauses := []string{"m.model_name = ?"}
+	args := []interface{}{model}
+	filters, filterArgs := filterClauses(q, "e.")
+	clauses = append(clauses, filters...)
+	args = append(args, filterArgs...)
+	query := `SELECT v.event_id, v.vector
+		FROM embeddings v
+		JOIN embedding_metadata m ON m.event_id = v.event_id
+		JOIN events e ON e.id = v.event_id
+		WHERE ` + strings.Join(clauses, " AND ")
+
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("nearest events: %w", err)
+	}
+	defer rows.Close()
+	var candidates []scoredID
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return nil, fmt.Errorf("nearest events: %w", err)
+		}
+		if v := decodeVector(blob); len(v) == len(vector) {
+			candidates = append(candidates, scoredID{id, vectorstore.Cosine(vector, v)})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("nearest events: %w", err)
+	}
+	traceQuery(ctx, query, start, "rows", len(candidates))
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	return candidates, nil
+}
+
+// eventsInOrder loads the events with ids, in that order unless sort is
+// newest or oldest. An event deleted since its ID was read is left out.
+func (s *SQLiteStore) eventsInOrder(ctx context.Context, ids []string, sortBy string) ([]Event, error) {
+	byID := make(map[string]Event, len(ids))
+	for i := 0; i < len(ids); i += contentBatchSize {
+		batch := ids[i:min(len(ids), i+contentBatchSize)]
+		args := make([]interface{}, len(batch))
+		for j, id := range batch {
+			args[j] = id
+		}
+		events, err := s.scanEvents(ctx,
+			`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+			FROM events WHERE id IN (?`+strings.Repeat(", ?", len(args)-1)+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			byID[e.ID] = e
+		}
+	}
+
+	out := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			out = append(out, e)
+		}
+	}
+	switch sortBy {
+	case SortNewest:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.After(out[j].Timestamp) })
+	case SortOldest:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	}
+	return out, nil
+}
+
+// encodeVector packs v as little-endian float32s.
+func encodeVector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b
+}
+
+// decodeVector unpacks a vector packed by encodeVector.
+func decodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
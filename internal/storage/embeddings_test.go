@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddings(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	tides := &Event{URL: "https://a.example/tides", Title: "Tides", Source: "manual", Timestamp: now.Add(-3 * time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, tides, "A long page about tides."))
+	moon := &Event{URL: "https://b.example/moon", Title: "Moon", Source: "manual", Timestamp: now.Add(-2 * time.Hour)}
+	require.NoError(t, store.AddEventWithContent(ctx, moon, "A long page about the moon."))
+	bare := &Event{URL: "https://c.example/", Title: "Bare", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, bare))
+
+	pending, err := store.EventsWithoutEmbedding(ctx, "m1", false, 0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+	pending, err = store.EventsWithoutEmbedding(ctx, "m1", true, 0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2, "only events with bodies")
+
+	require.NoError(t, store.SetEmbedding(ctx, tides.ID, "m1", []float32{1, 0, 0}))
+	require.NoError(t, store.SetEmbedding(ctx, moon.ID, "m1", []float32{0.6, 0.8, 0}))
+	require.NoError(t, store.SetEmbedding(ctx, bare.ID, "m1", []float32{0, 0, 1}))
+	assert.EqualError(t, store.SetEmbedding(ctx, "CHR-missing", "m1", []float32{1}), "event CHR-missing not found")
+
+	got, err := store.GetEvent(ctx, tides.ID)
+	require.NoError(t, err)
+	assert.True(t, got.HasEmbed)
+	pending, err = store.EventsWithoutEmbedding(ctx, "m1", false, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = store.EventsWithoutEmbedding(ctx, "m2", false, 1)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1, "a new model embeds everything again")
+
+	ids := func(q SearchQuery, model string, v []float32) []string {
+		t.Helper()
+		results, err := store.NearestEvents(ctx, q, model, v)
+		require.NoError(t, err)
+		var out []string
+		for _, e := range results {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	query := []float32{0.9, 0.1, 0}
+	assert.Equal(t, []string{tides.ID, moon.ID, bare.ID}, ids(SearchQuery{}, "m1", query))
+	assert.Equal(t, []string{moon.ID}, ids(SearchQuery{Limit: 1, Offset: 1}, "m1", query))
+	assert.Equal(t, []string{bare.ID}, ids(SearchQuery{Source: "extension"}, "m1", query))
+	assert.Equal(t, []string{moon.ID, tides.ID}, ids(SearchQuery{Limit: 2, Sort: SortNewest}, "m1", query))
+	assert.Empty(t, ids(SearchQuery{}, "m2", query), "other models' vectors are not compared")
+	assert.Empty(t, ids(SearchQuery{}, "m1", []float32{1, 0}), "nor vectors of another length")
+
+	// Replacing an embedding moves the event.
+	require.NoError(t, store.SetEmbedding(ctx, bare.ID, "m1", []float32{1, 0.05, 0}))
+	assert.Equal(t, []string{bare.ID}, ids(SearchQuery{Limit: 1}, "m1", query))
+
+	require.NoError(t, store.DeleteEvent(ctx, bare.ID))
+	assert.Equal(t, []string{tides.ID, moon.ID}, ids(SearchQuery{}, "m1", query))
+
+	_, err = store.NearestEvents(ctx, SearchQuery{Sort: "random"}, "m1", query)
+	assert.EqualError(t, err, `invalid sort "random"`)
+}
+
+func TestVectorEncoding(t *testing.T) {
+	v := []float32{0.25, -1.5, 3e-7}
+	assert.Equal(t, v, decodeVector(encodeVector(v)))
+}
+
+func TestHybridSearch(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	both := &Event{URL: "https://a.example/wal", Title: "SQLite WAL mode", Source: "manual"}
+	keyword := &Event{URL: "https://b.example/wal", Title: "WAL checkpoints", Source: "manual"}
+	semantic := &Event{URL: "https://c.example/journal", Title: "Journaling in databases", Source: "extension"}
+	other := &Event{URL: "https://d.example/", Title: "Gardening", Source: "manual"}
+	for _, e := range []*Event{both, keyword, semantic, other} {
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+	require.NoError(t, store.SetEmbedding(ctx, both.ID, "m1", []float32{1, 0.1}))
+	require.NoError(t, store.SetEmbedding(ctx, semantic.ID, "m1", []float32{1, 0.3}))
+	require.NoError(t, store.SetEmbedding(ctx, other.ID, "m1", []float32{0, 1}))
+
+	results, err := store.HybridSearch(ctx, SearchQuery{Query: "wal", Limit: 10}, "m1", []float32{1, 0})
+	require.NoError(t, err)
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.Event.ID)
+	}
+	require.Len(t, ids, 4)
+	assert.Equal(t, both.ID, ids[0], "ranked by both")
+	assert.ElementsMatch(t, []string{keyword.ID, semantic.ID}, ids[1:3])
+	assert.Equal(t, other.ID, ids[3])
+
+	top := results[0].Score
+	assert.Equal(t, 1, top.SemanticRank)
+	assert.NotZero(t, top.KeywordRank)
+	assert.Less(t, top.BM25, 0.0)
+	assert.InDelta(t, 1/float64(rrfK+top.KeywordRank)+1/float64(rrfK+1), top.Score, 1e-12)
+	for _, r := range results {
+		switch r.Event.ID {
+		case keyword.ID:
+			assert.Zero(t, r.Score.SemanticRank, "no embedding")
+		case semantic.ID:
+			assert.Zero(t, r.Score.KeywordRank, "no keyword match")
+			assert.Equal(t, 2, r.Score.SemanticRank)
+		}
+	}
+
+	results, err = store.HybridSearch(ctx, SearchQuery{Query: "wal", Source: "extension", Limit: 10}, "m1", []float32{1, 0})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, semantic.ID, results[0].Event.ID)
+
+	results, err = store.HybridSearch(ctx, SearchQuery{Query: "wal", Limit: 1, Offset: 3}, "m1", []float32{1, 0})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, other.ID, results[0].Event.ID)
+
+	_, err = store.HybridSearch(ctx, SearchQuery{Limit: 10}, "m1", []float32{1, 0})
+	assert.EqualError(t, err, "hybrid search needs a query")
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/encrypt"
+)
+
+// Config table keys holding a database's encryption parameters. The check
+// value is a known plaintext sealed under the key, so a wrong passphrase is
+// caught when the database is opened rather than on the first body read.
+const (
+	configEncryptionSalt       = "encryption_salt"
+	configEncryptionIterations = "encryption_iterations"
+	configEncryptionCheck      = "encryption_check"
+)
+
+// encryptionCheckText is the plaintext sealed as the check value.
+const encryptionCheckText = "chronicle"
+
+// ErrWrongPassphrase is returned when a passphrase does not unlock the
+// database.
+var ErrWrongPassphrase = errors.New("wrong encryption passphrase")
+
+// Encrypted reports whether db's bodies are encrypted, so opening it needs
+// the passphrase.
+func Encrypted(ctx context.Context, db *sql.DB) (bool, error) {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM config WHERE key = ?`, configEncryptionSalt).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("read encryption settings: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UnlockEncryption derives db's body encryption key from passphrase and
+// returns a cipher for SetCipher. It fails with ErrWrongPassphrase if the
+// passphrase is not the one the database was encrypted with.
+func UnlockEncryption(ctx context.Context, db *sql.DB, passphrase string) (*encrypt.Cipher, error) {
+	var saltStr, iterStr, check sql.NullString
+	err := db.QueryRowContext(ctx,
+		`SELECT
+			(SELECT value FROM config WHERE key = ?),
+			(SELECT value FROM config WHERE key = ?),
+			(SELECT value FROM config WHERE key = ?)`,
+		configEncryptionSalt, configEncryptionIterations, configEncryptionCheck,
+	).Scan(&saltStr, &iterStr, &check)
+	if err != nil {
+		return nil, fmt.Errorf("read encryption settings: %w", err)
+	}
+	if !saltStr.Valid {
+		return nil, fmt.Errorf("database is not encrypted")
+	}
+	salt, err := base64.StdEncoding.DecodeString(saltStr.String)
+	if err != nil {
+		return nil, fmt.Errorf("read encryption salt: %w", err)
+	}
+	iterations, err := strconv.Atoi(iterStr.String)
+	if err != nil || iterations <= 0 {
+		return nil, fmt.Errorf("invalid encryption iteration count %q", iterStr.String)
+	}
+
+	c, err := encrypt.New(encrypt.DeriveKey(passphrase, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+	if text, err := c.Open(check.String); err != nil || text != encryptionCheckText {
+		return nil, ErrWrongPassphrase
+	}
+	return c, nil
+}
+
+// InitEncryption sets db up for body encryption under passphrase, with a
+// fresh salt, and returns its cipher. A database that is already encrypted
+// is unlocked instead. Bodies stored earlier stay plaintext until
+// EncryptBodies converts them.
+func InitEncryption(ctx context.Context, db *sql.DB, passphrase string) (*encrypt.Cipher, error) {
+	return initEncryption(ctx, db, passphrase, encrypt.KeyIterations)
+}
+
+// initEncryption is InitEncryption with a chosen PBKDF2 iteration count;
+// tests use a low one.
+func initEncryption(ctx context.Context, db *sql.DB, passphrase string, iterations int) (*encrypt.Cipher, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("encryption passphrase is empty")
+	}
+	encrypted, err := Encrypted(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		return UnlockEncryption(ctx, db, passphrase)
+	}
+
+	salt, err := encrypt.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	c, err := encrypt.New(encrypt.DeriveKey(passphrase, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+	check, err := c.Seal(encryptionCheckText)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	values := [][2]string{
+		{configEncryptionSalt, base64.StdEncoding.EncodeToString(salt)},
+		{configEncryptionIterations, strconv.Itoa(iterations)},
+		{configEncryptionCheck, check},
+	}
+	for _, kv := range values {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO config (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`, kv[0], kv[1],
+		); err != nil {
+			return nil, fmt.Errorf("save encryption settings: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("save encryption settings: %w", err)
+	}
+	return c, nil
+}
+
+// SetCipher makes the store encrypt the bodies and summaries it stores
+// with c and decrypt those it reads. Nothing else is derived from an
+// encrypted body: it is left out of the full-text index, so keyword search
+// matches only titles and URLs, and no keywords or outbound links are
+// extracted from it.
+func (s *SQLiteStore) SetCipher(c *encrypt.Cipher) {
+	s.cipher = c
+}
+
+// sealBody encrypts body for storage when the store has a cipher.
+func (s *SQLiteStore) sealBody(body string) (string, error) {
+	if s.cipher == nil {
+		return body, nil
+	}
+	sealed, err := s.cipher.Seal(body)
+	if err != nil {
+		return "", fmt.Errorf("encrypt body: %w", err)
+	}
+	return sealed, nil
+}
+
+// openBody decrypts a stored body. Plaintext bodies are returned as they
+// are, and an encrypted one read without a cipher is an error.
+func (s *SQLiteStore) openBody(body string) (string, error) {
+	if !encrypt.IsSealed(body) {
+		return body, nil
+	}
+	if s.cipher == nil {
+		return "", fmt.Errorf("body is encrypted and no passphrase was given")
+	}
+	return s.cipher.Open(body)
+}
+
+// PlaintextBodies counts the bodies stored as plaintext, which
+// EncryptBodies would encrypt.
+func (s *SQLiteStore) PlaintextBodies(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM content WHERE substr(body, 1, ?) != ?`,
+		len(encrypt.SealedPrefix), encrypt.SealedPrefix,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count plaintext bodies: %w", err)
+	}
+	return n, nil
+}
+
+// EncryptBodies encrypts every body and summary still stored as
+// plaintext, in one transaction, and drops what was derived from the
+// bodies: their full-text index entries, keywords and outbound links. It
+// returns how many bodies it encrypted, reporting each batch done to
+// progress, if not nil. The plaintext can linger in free pages of the
+// database file until Vacuum.
+func (s *SQLiteStore) EncryptBodies(ctx context.Context, progress func(int)) (int64, error) {
+	if s.cipher == nil {
+		return 0, fmt.Errorf("encrypt bodies: no cipher set")
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	start := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var count int64
+	for {
+		n, err := s.encryptBodyBatch(ctx, tx)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			break
+		}
+		count += n
+		if progress != nil {
+			progress(int(n))
+		}
+	}
+	if err := s.encryptSummaries(ctx, tx); err != nil {
+		return 0, err
+	}
+	for _, stmt := range []string{
+		`UPDATE events_fts SET body = '' WHERE body != ''`,
+		`DELETE FROM keywords`,
+		`DELETE FROM links`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return 0, fmt.Errorf("drop text derived from bodies (%s): %w", stmt, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("encrypt bodies: %w", err)
+	}
+
+	slog.DebugContext(ctx, "bodies encrypted", "count", count, "elapsed", time.Since(start))
+	return count, nil
+}
+
+// encryptBodyBatch encrypts up to contentBatchSize plaintext bodies in tx
+// and returns how many it did.
+func (s *SQLiteStore) encryptBodyBatch(ctx context.Context, tx *sql.Tx) (int64, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT event_id, body FROM content WHERE substr(body, 1, ?) != ? LIMIT ?`,
+		len(encrypt.SealedPrefix), encrypt.SealedPrefix, contentBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("encrypt bodies: %w", err)
+	}
+	bodies := map[string]string{}
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("encrypt bodies: %w", err)
+		}
+		bodies[id] = body
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return 0, fmt.Errorf("encrypt bodies: %w", err)
+	}
+
+	for id, body := range bodies {
+		sealed, err := s.sealBody(body)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE content SET body = ? WHERE event_id = ?`, sealed, id); err != nil {
+			return 0, fmt.Errorf("encrypt bodies: %w", err)
+		}
+	}
+	return int64(len(bodies)), nil
+}
+
+// encryptSummaries encrypts in tx every summary stored as plaintext.
+func (s *SQLiteStore) encryptSummaries(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT event_id, summary FROM summaries WHERE substr(summary, 1, ?) != ?`,
+		len(encrypt.SealedPrefix), encrypt.SealedPrefix,
+	)
+	if err != nil {
+		return fmt.Errorf("encrypt summaries: %w", err)
+	}
+	summaries := map[string]string{}
+	for rows.Next() {
+		var id, text string
+		if err := rows.Scan(&id, &text); err != nil {
+			rows.Close()
+			return fmt.Errorf("encrypt summaries: %w", err)
+		}
+		summaries[id] = text
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("encrypt summaries: %w", err)
+	}
+
+	for id, text := range summaries {
+		sealed, err := s.sealBody(text)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE summaries SET summary = ? WHERE event_id = ?`, sealed, id); err != nil {
+			return fmt.Errorf("encrypt summaries: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/encrypt"
+)
+
+func TestEncryption_SealsBodies(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	encrypted, err := Encrypted(ctx, store.db)
+	require.NoError(t, err)
+	assert.False(t, encrypted)
+
+	c, err := initEncryption(ctx, store.db, "correct horse", 1000)
+	require.NoError(t, err)
+	store.SetCipher(c)
+	encrypted, err = Encrypted(ctx, store.db)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+
+	e := &Event{URL: "https://tides.example.com", Title: "Tide tables", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, e, "High water at the harbor is at noon"))
+
+	var stored string
+	require.NoError(t, store.db.QueryRow(`SELECT body FROM content WHERE event_id = ?`, e.ID).Scan(&stored))
+	assert.True(t, encrypt.IsSealed(stored))
+	assert.NotContains(t, stored, "harbor")
+
+	content, err := store.GetContent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "High water at the harbor is at noon", content.Body)
+	preview, err := store.GetContentPreview(ctx, e.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "High water", preview.Body)
+	previews, err := store.GetContentPreviews(ctx, []string{e.ID}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "High water", previews[e.ID].Body)
+	text, err := store.EmbeddingText(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Tide tables\n\nHigh water at the harbor is at noon", text)
+
+	// Bodies are kept out of the index; titles are still searchable.
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "harbor"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "tide"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	_, err = store.RebuildFTS(ctx)
+	require.NoError(t, err)
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "harbor"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// Nor are keywords or links taken from bodies, and summaries are sealed.
+	linked := &Event{URL: "https://tides.example.com/charts", Title: "Charts", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, linked, "Harbor charts are at [the survey](https://survey.example.com/)"))
+	assert.Empty(t, linked.Keywords)
+	assert.Zero(t, derivedRows(t, store))
+	require.NoError(t, store.SetSummary(ctx, Summary{EventID: e.ID, Text: "When the harbor floods", Model: "test"}))
+	require.NoError(t, store.db.QueryRow(`SELECT summary FROM summaries WHERE event_id = ?`, e.ID).Scan(&stored))
+	assert.True(t, encrypt.IsSealed(stored))
+	sum, err := store.GetSummary(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "When the harbor floods", sum.Text)
+	summaries, err := store.GetSummaries(ctx, []string{e.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "When the harbor floods", summaries[e.ID])
+
+	store.SetCipher(nil)
+	_, err = store.GetContent(ctx, e.ID)
+	assert.EqualError(t, err, "get content: body is encrypted and no passphrase was given")
+}
+
+// derivedRows counts the keywords and links stored for all events.
+func derivedRows(t *testing.T, store *SQLiteStore) int {
+	t.Helper()
+	var n int
+	require.NoError(t, store.db.QueryRow(`SELECT (SELECT COUNT(*) FROM keywords) + (SELECT COUNT(*) FROM links)`).Scan(&n))
+	return n
+}
+
+func TestEncryption_Unlock(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	_, err := UnlockEncryption(ctx, store.db, "correct horse")
+	assert.EqualError(t, err, "database is not encrypted")
+	_, err = initEncryption(ctx, store.db, "", 1000)
+	assert.EqualError(t, err, "encryption passphrase is empty")
+
+	c, err := initEncryption(ctx, store.db, "correct horse", 1000)
+	require.NoError(t, err)
+	sealed, err := c.Seal("secret")
+	require.NoError(t, err)
+
+	_, err = UnlockEncryption(ctx, store.db, "wrong horse")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+	_, err = initEncryption(ctx, store.db, "wrong horse", 1000)
+	assert.ErrorIs(t, err, ErrWrongPassphrase, "an encrypted database keeps its key")
+
+	unlocked, err := UnlockEncryption(ctx, store.db, "correct horse")
+	require.NoError(t, err)
+	plain, err := unlocked.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", plain)
+}
+
+func TestEncryptBodies_ConvertsPlaintext(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	before := &Event{URL: "https://tides.example.com", Title: "Tide tables", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, before, "High water at the harbor is at noon, says [the survey](https://survey.example.com/)"))
+	require.NoError(t, store.SetSummary(ctx, Summary{EventID: before.ID, Text: "When the harbor floods", Model: "test"}))
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "harbor"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotZero(t, derivedRows(t, store))
+
+	_, err = store.EncryptBodies(ctx, nil)
+	assert.EqualError(t, err, "encrypt bodies: no cipher set")
+
+	c, err := initEncryption(ctx, store.db, "correct horse", 1000)
+	require.NoError(t, err)
+	store.SetCipher(c)
+	after := &Event{URL: "https://go.dev/blog", Title: "The Go Blog", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, after, "posts"))
+
+	plain, err := store.PlaintextBodies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), plain)
+	var reported int
+	n, err := store.EncryptBodies(ctx, func(n int) { reported += n })
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "bodies already sealed are left alone")
+	assert.Equal(t, 1, reported)
+	plain, err = store.PlaintextBodies(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, plain)
+
+	var stored string
+	require.NoError(t, store.db.QueryRow(`SELECT body FROM content WHERE event_id = ?`, before.ID).Scan(&stored))
+	assert.True(t, encrypt.IsSealed(stored))
+	content, err := store.GetContent(ctx, before.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "High water at the harbor is at noon, says [the survey](https://survey.example.com/)", content.Body)
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "harbor"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Zero(t, derivedRows(t, store), "keywords and links taken from plaintext bodies are dropped")
+	require.NoError(t, store.db.QueryRow(`SELECT summary FROM summaries WHERE event_id = ?`, before.ID).Scan(&stored))
+	assert.True(t, encrypt.IsSealed(stored))
+	sum, err := store.GetSummary(ctx, before.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "When the harbor floods", sum.Text)
+
+	n, err = store.EncryptBodies(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
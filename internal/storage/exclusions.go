@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Exclusion rule types: a domain rule matches one hostname exactly, a regex
+// rule any hostname it matches.
+const (
+	ExclusionDomain = "domain"
+	ExclusionRegex  = "regex"
+)
+
+// ErrExclusionExists is returned when adding a rule that is already there.
+var ErrExclusionExists = errors.New("exclusion rule already exists")
+
+// ErrExclusionNotFound is returned when removing a rule that isn't there.
+var ErrExclusionNotFound = errors.New("exclusion rule not found")
+
+// Exclusion is a rule keeping matching domains out of the history.
+type Exclusion struct {
+	Type      string // ExclusionDomain or ExclusionRegex
+	Value     string
+	Reason    string
+	Default   bool // seeded by the schema rather than added by the user
+	CreatedAt time.Time
+}
+
+// Exclusions returns every exclusion rule, defaults first, then by type
+// and value.
+func (s *SQLiteStore) Exclusions(ctx context.Context) ([]Exclusion, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT rule_type, rule_value, reason, is_default, created_at FROM exclusions
+		ORDER BY is_default DESC, rule_type, rule_value`)
+	if err != nil {
+		return nil, fmt.Errorf("list exclusions: %w", err)
+	}
+	defer rows.Close()
+
+	out := []Exclusion{}
+	for rows.Next() {
+		var x Exclusion
+		var created string
+		if err := rows.Scan(&x.Type, &x.Value, &x.Reason, &x.Default, &created); err != nil {
+			return nil, fmt.Errorf("list exclusions: %w", err)
+		}
+		x.CreatedAt, _ = parseTimestamp(created)
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+// AddExclusion stores x as a user rule and applies it to later captures.
+// Domains are matched lowercased, so domain rules are stored that way.
+func (s *SQLiteStore) AddExclusion(ctx context.Context, x Exclusion) error {
+	switch x.Type {
+	case ExclusionDomain:
+		x.Value = strings.ToLower(strings.TrimSpace(x.Value))
+	case ExclusionRegex:
+		if _, err := regexp.Compile(x.Value); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid exclusion type %q (want domain or regex)", x.Type)
+	}
+	if x.Value == "" {
+		return fmt.Errorf("exclusion %s is empty", x.Type)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO exclusions (rule_type, rule_value, reason, is_default) VALUES (?, ?, ?, 0)`,
+		x.Type, x.Value, x.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("add exclusion: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s %q: %w", x.Type, x.Value, ErrExclusionExists)
+	}
+	return s.loadExclusions()
+}
+
+// RemoveExclusion deletes the rule of ruleType with value, default or not,
+// and stops applying it.
+func (s *SQLiteStore) RemoveExclusion(ctx context.Context, ruleType, value string) error {
+	if ruleType == ExclusionDomain {
+		value = strings.ToLower(strings.TrimSpace(value))
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM exclusions WHERE rule_type = ? AND rule_value = ?`, ruleType, value)
+	if err != nil {
+		return fmt.Errorf("remove exclusion: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s %q: %w", ruleType, value, ErrExclusionNotFound)
+	}
+	return s.loadExclusions()
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusions_AddRemove(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	rules, err := store.Exclusions(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, rules)
+	assert.True(t, rules[0].Default, "seeded defaults come first")
+
+	require.NoError(t, store.AddExclusion(ctx, Exclusion{Type: ExclusionDomain, Value: " Intranet.Example.com ", Reason: "work"}))
+	require.NoError(t, store.AddExclusion(ctx, Exclusion{Type: ExclusionRegex, Value: `\.internal$`}))
+	assert.True(t, store.IsExcluded("intranet.example.com"), "applied without reopening the store")
+	rule, ok := store.ExclusionRule("wiki.internal")
+	assert.True(t, ok)
+	assert.Equal(t, `\.internal$`, rule)
+
+	e := &Event{URL: "https://intranet.example.com/payroll", Title: "Payroll", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, e))
+	assert.Empty(t, e.ID, "excluded captures are skipped")
+
+	rules, err = store.Exclusions(ctx)
+	require.NoError(t, err)
+	last := rules[len(rules)-1]
+	assert.Equal(t, Exclusion{Type: ExclusionRegex, Value: `\.internal$`, CreatedAt: last.CreatedAt}, last)
+
+	assert.ErrorIs(t, store.AddExclusion(ctx, Exclusion{Type: ExclusionDomain, Value: "intranet.example.com"}), ErrExclusionExists)
+	assert.ErrorContains(t, store.AddExclusion(ctx, Exclusion{Type: ExclusionRegex, Value: "("}), "invalid regex")
+	assert.EqualError(t, store.AddExclusion(ctx, Exclusion{Type: "path", Value: "/x"}), `invalid exclusion type "path" (want domain or regex)`)
+
+	require.NoError(t, store.RemoveExclusion(ctx, ExclusionDomain, "INTRANET.example.com"))
+	assert.False(t, store.IsExcluded("intranet.example.com"))
+	require.NoError(t, store.RemoveExclusion(ctx, ExclusionDomain, "chase.com"), "defaults can be removed too")
+	assert.False(t, store.IsExcluded("chase.com"))
+	assert.ErrorIs(t, store.RemoveExclusion(ctx, ExclusionDomain, "chase.com"), ErrExclusionNotFound)
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownExtension is returned for an extension ID that has not
+// registered (or whose registration was cleared).
+var ErrUnknownExtension = errors.New("unknown extension")
+
+// RegisterExtension records ext and marks it seen at now. An empty ID is
+// assigned a new EXT- ID; re-registering an existing ID updates its browser,
+// version and profile but keeps the original registration time.
+func (s *SQLiteStore) RegisterExtension(ctx context.Context, ext *Extension, now time.Time) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if ext.ID == "" {
+		b := make([]byte, 4)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("generate extension id: %w", err)
+		}
+		ext.ID = "EXT-" + hex.EncodeToString(b)
+	}
+	ts := now.UTC().Format(time.RFC3339)
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO extensions (id, browser, version, profile, registered_at, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			browser = excluded.browser, version = excluded.version,
+			profile = excluded.profile, last_seen = excluded.last_seen`,
+		ext.ID, ext.Browser, ext.Version, ext.Profile, ts, ts,
+	); err != nil {
+		return fmt.Errorf("register extension: %w", err)
+	}
+
+	var registered string
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT registered_at FROM extensions WHERE id = ?`, ext.ID,
+	).Scan(&registered); err != nil {
+		return fmt.Errorf("register extension: %w", err)
+	}
+	ext.RegisteredAt, _ = parseTimestamp(registered)
+	ext.LastSeen, _ = parseTimestamp(ts)
+	return nil
+}
+
+// TouchExtension marks the extension id as seen at now. It returns
+// ErrUnknownExtension if id has not registered.
+func (s *SQLiteStore) TouchExtension(ctx context.Context, id string, now time.Time) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE extensions SET last_seen = ? WHERE id = ?`,
+		now.UTC().Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return fmt.Errorf("touch extension: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("touch extension: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("extension %s: %w", id, ErrUnknownExtension)
+	}
+	return nil
+}
+
+// ListExtensions returns every registered extension, most recently seen
+// first.
+func (s *SQLiteStore) ListExtensions(ctx context.Context) ([]Extension, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, browser, version, profile, registered_at, last_seen
+		FROM extensions ORDER BY last_seen DESC, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list extensions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Extension
+	for rows.Next() {
+		var e Extension
+		var registered, seen string
+		if err := rows.Scan(&e.ID, &e.Browser, &e.Version, &e.Profile, &registered, &seen); err != nil {
+			return nil, err
+		}
+		e.RegisteredAt, _ = parseTimestamp(registered)
+		e.LastSeen, _ = parseTimestamp(seen)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
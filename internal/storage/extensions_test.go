@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensions(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	chrome := &Extension{Browser: "chrome", Version: "1.2.0", Profile: "Work"}
+	require.NoError(t, store.RegisterExtension(ctx, chrome, base))
+	assert.Regexp(t, `^EXT-[0-9a-f]{8}$`, chrome.ID)
+	assert.Equal(t, base, chrome.RegisteredAt)
+
+	firefox := &Extension{Browser: "firefox", Version: "1.1.0"}
+	require.NoError(t, store.RegisterExtension(ctx, firefox, base.Add(time.Minute)))
+
+	// Re-registering keeps the ID and registration time but updates the rest.
+	upgraded := &Extension{ID: chrome.ID, Browser: "chrome", Version: "1.3.0", Profile: "Work"}
+	require.NoError(t, store.RegisterExtension(ctx, upgraded, base.Add(time.Hour)))
+	assert.Equal(t, base, upgraded.RegisteredAt)
+
+	require.NoError(t, store.TouchExtension(ctx, firefox.ID, base.Add(2*time.Hour)))
+	err := store.TouchExtension(ctx, "EXT-missing", base)
+	assert.ErrorIs(t, err, ErrUnknownExtension)
+
+	list, err := store.ListExtensions(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, firefox.ID, list[0].ID)
+	assert.Equal(t, base.Add(2*time.Hour), list[0].LastSeen)
+	assert.Equal(t, Extension{
+		ID: chrome.ID, Browser: "chrome", Version: "1.3.0", Profile: "Work",
+		RegisteredAt: base, LastSeen: base.Add(time.Hour),
+	}, list[1])
+}
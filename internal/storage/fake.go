@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Vocabulary for synthetic events. Kept small so generated titles share
+// terms and searches return realistic result counts.
+var (
+	fakeDomains = []string{
+		"github.com", "stackoverflow.com", "news.ycombinator.com", "en.wikipedia.org",
+		"go.dev", "docs.python.org", "developer.mozilla.org", "reddit.com",
+		"youtube.com", "medium.com", "arxiv.org", "lwn.net", "sqlite.org", "kubernetes.io",
+	}
+	fakeTopics = []string{
+		"kubernetes", "golang", "sqlite", "rust", "postgres", "react", "linux", "docker",
+		"terraform", "python", "networking", "compilers", "databases", "security", "graphql",
+		"webassembly", "observability", "concurrency", "caching", "search",
+	}
+	fakeTitles = []string{
+		"%s best practices", "Understanding %s internals", "How to debug %s in production",
+		"A gentle introduction to %s", "Why we moved away from %s", "%s performance tuning guide",
+		"Ask HN: How do you learn %s?", "%s vs %s: a comparison", "Notes on %s and %s",
+	}
+	fakeBrowsers = []string{"chrome", "firefox", "safari", "edge"}
+)
+
+// FakeGenerator produces deterministic, realistic-looking synthetic events
+// for benchmarks and load testing. It is not safe for concurrent use.
+type FakeGenerator struct {
+	r      *rand.Rand
+	now    time.Time
+	window time.Duration
+	seq    int
+}
+
+// NewFakeGenerator returns a generator seeded with seed whose events fall
+// within the 90 days before now.
+func NewFakeGenerator(seed int64, now time.Time) *FakeGenerator {
+	return &FakeGenerator{r: rand.New(rand.NewSource(seed)), now: now, window: 90 * 24 * time.Hour}
+}
+
+// Next returns a new event and, for roughly a quarter of events, a body
+// (empty otherwise).
+func (g *FakeGenerator) Next() (*Event, string) {
+	g.seq++
+	domain := g.pick(fakeDomains)
+	a, b := g.pick(fakeTopics), g.pick(fakeTopics)
+
+	title := g.pick(fakeTitles)
+	if strings.Count(title, "%s") == 2 {
+		title = fmt.Sprintf(title, a, b)
+	} else {
+		title = fmt.Sprintf(title, a)
+	}
+
+	event := &Event{
+		URL:       fmt.Sprintf("https://%s/%s/%s-%d", domain, a, b, g.seq),
+		Title:     title,
+		Timestamp: g.now.Add(-time.Duration(g.r.Int63n(int64(g.window)))),
+		Source:    "extension",
+		Browser:   g.pick(fakeBrowsers),
+	}
+
+	if g.r.Intn(4) != 0 {
+		return event, ""
+	}
+	words := make([]string, 50+g.r.Intn(150))
+	for i := range words {
+		words[i] = g.pick(fakeTopics)
+	}
+	return event, strings.Join(words, " ")
+}
+
+// Query returns a one- or two-term search query drawn from the same
+// vocabulary as generated titles.
+func (g *FakeGenerator) Query() string {
+	if g.r.Intn(2) == 0 {
+		return g.pick(fakeTopics)
+	}
+	return g.pick(fakeTopics) + " " + g.pick(fakeTopics)
+}
+
+func (g *FakeGenerator) pick(from []string) string {
+	return from[g.r.Intn(len(from))]
+}
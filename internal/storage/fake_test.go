@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeGeneratorDeterministic(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	a, b := NewFakeGenerator(42, now), NewFakeGenerator(42, now)
+
+	withBody := 0
+	for i := 0; i < 200; i++ {
+		ea, ba := a.Next()
+		eb, bb := b.Next()
+		assert.Equal(t, ea, eb)
+		assert.Equal(t, ba, bb)
+
+		assert.True(t, ea.Timestamp.Before(now))
+		assert.True(t, ea.Timestamp.After(now.Add(-91*24*time.Hour)))
+		assert.NotEmpty(t, ea.Title)
+		if ba != "" {
+			withBody++
+		}
+	}
+	assert.Greater(t, withBody, 20)
+	assert.Less(t, withBody, 100)
+	assert.Equal(t, a.Query(), b.Query())
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/encrypt"
+)
+
+// FTSCheck compares the full-text index with the events table.
+type FTSCheck struct {
+	Events   int64 // events in the database
+	Missing  int64 // events with no index entry, so keyword search can't find them
+	Orphaned int64 // index entries for events that no longer exist
+}
+
+// OK reports whether every event is indexed and nothing else is.
+func (c FTSCheck) OK() bool {
+	return c.Missing == 0 && c.Orphaned == 0
+}
+
+// CheckFTS counts the events missing from the full-text index and the
+// index entries left behind by deleted events. Databases written before
+// captures were committed in one transaction can have either.
+func (s *SQLiteStore) CheckFTS(ctx context.Context) (FTSCheck, error) {
+	var c FTSCheck
+	err := s.db.QueryRowContext(ctx, `SELECT
+		(SELECT COUNT(*) FROM events),
+		(SELECT COUNT(*) FROM events WHERE id NOT IN (SELECT event_id FROM events_fts)),
+		(SELECT COUNT(*) FROM events_fts WHERE event_id NOT IN (SELECT id FROM events))`,
+	).Scan(&c.Events, &c.Missing, &c.Orphaned)
+	if err != nil {
+		return FTSCheck{}, fmt.Errorf("check FTS: %w", err)
+	}
+	return c, nil
+}
+
+// RebuildFTS replaces the full-text index with one built from the events
+// and their stored bodies, in one transaction, and returns how many events
+// it indexed.
+func (s *SQLiteStore) RebuildFTS(ctx context.Context) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	start := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM events_fts`); err != nil {
+		return 0, fmt.Errorf("rebuild FTS: %w", err)
+	}
+	// Encrypted bodies stay out of the index.
+	res, err := tx.ExecContext(ctx, `INSERT INTO events_fts (event_id, title, url, body)
+		SELECT e.id, e.title, e.url,
+			CASE WHEN substr(c.body, 1, ?) = ? THEN '' ELSE COALESCE(c.body, '') END
+		FROM events e LEFT JOIN content c ON c.event_id = e.id`,
+		len(encrypt.SealedPrefix), encrypt.SealedPrefix,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("rebuild FTS: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("rebuild FTS: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	slog.DebugContext(ctx, "FTS rebuilt", "events", n, "elapsed", time.Since(start))
+	return n, err
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildFTS_RepairsDrift(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	kept := &Event{URL: "https://go.dev/doc/effective_go", Title: "Effective Go", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, kept, "goroutines and channels"))
+	gone := &Event{URL: "https://example.com/gone", Title: "Gone", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, gone))
+
+	check, err := store.CheckFTS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FTSCheck{Events: 2}, check)
+	assert.True(t, check.OK())
+
+	// Simulate a crash between the two inserts of an older version, and a
+	// delete that left its index entry behind.
+	_, err = store.db.Exec(`DELETE FROM events_fts WHERE event_id = ?`, kept.ID)
+	require.NoError(t, err)
+	_, err = store.db.Exec(`DELETE FROM events WHERE id = ?`, gone.ID)
+	require.NoError(t, err)
+
+	check, err = store.CheckFTS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FTSCheck{Events: 1, Missing: 1, Orphaned: 1}, check)
+	assert.False(t, check.OK())
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "channels"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	n, err := store.RebuildFTS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	check, err = store.CheckFTS(ctx)
+	require.NoError(t, err)
+	assert.True(t, check.OK())
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "channels"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, kept.ID, results[0].ID)
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rrfK damps reciprocal rank fusion so the top few ranks of either list
+// don't dominate: a result scores 1/(rrfK+rank) per list it appears in.
+const rrfK = 60
+
+// hybridDepth is how many of the best keyword and semantic matches each a
+// hybrid search fuses, at least; deeper pages fuse more.
+const hybridDepth = 100
+
+// HybridScore breaks down how a hybrid search ranked a result.
+type HybridScore struct {
+	Score        float64 // reciprocal rank fusion of the two ranks; higher is better
+	KeywordRank  int     // 1-based rank among keyword matches; 0 if the query's words didn't match
+	BM25         float64 // FTS5 rank of the keyword match, BM25 (more negative is better)
+	SemanticRank int     // 1-based rank by similarity; 0 without an embedding from the model
+	Similarity   float64 // cosine similarity of the event's embedding to the query's
+}
+
+// HybridResult is an event found by HybridSearch, with its score.
+type HybridResult struct {
+	Event Event
+	Score HybridScore
+}
+
+// HybridSearch ranks the events matching q's filters by both q's Query,
+// through the full-text index, and similarity of their embeddings from
+// model to vector, the query's embedding, merging the two rankings by
+// reciprocal rank fusion. An event found by only one ranking can still
+// place. Results are paged by q's Limit and Offset; a newest or oldest
+// Sort orders the page by time instead.
+func (s *SQLiteStore) HybridSearch(ctx context.Context, q SearchQuery, model string, vector []float32) ([]HybridResult, error) {
+	switch q.Sort {
+	case "", SortRelevance, SortNewest, SortOldest:
+	default:
+		return nil, fmt.Errorf("invalid sort %q", q.Sort)
+	}
+	if strings.TrimSpace(q.Query) == "" {
+		return nil, fmt.Errorf("hybrid search needs a query")
+	}
+	depth := -1 // SQLite: no limit
+	if q.Limit > 0 {
+		depth = max(hybridDepth, q.Offset+q.Limit)
+	}
+
+	keyword, err := s.keywordRanks(ctx, q, depth)
+	if err != nil {
+		return nil, err
+	}
+	semantic, err := s.nearest(ctx, q, model, vector)
+	if err != nil {
+		return nil, err
+	}
+	if depth >= 0 && len(semantic) > depth {
+		semantic = semantic[:depth]
+	}
+
+	scores := map[string]*HybridScore{}
+	var ids []string
+	score := func(id string) *HybridScore {
+		sc, ok := scores[id]
+		if !ok {
+			sc = &HybridScore{}
+			scores[id] = sc
+			ids = append(ids, id)
+		}
+		return sc
+	}
+	for i, c := range keyword {
+		sc := score(c.id)
+		sc.KeywordRank, sc.BM25 = i+1, c.score
+		sc.Score += 1 / float64(rrfK+i+1)
+	}
+	for i, c := range semantic {
+		sc := score(c.id)
+		sc.SemanticRank, sc.Similarity = i+1, c.score
+		sc.Score += 1 / float64(rrfK+i+1)
+	}
+	// Ties go to the event more similar to the query.
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := scores[ids[i]], scores[ids[j]]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Similarity > b.Similarity
+	})
+
+	if q.Offset >= len(ids) {
+		return []HybridResult{}, nil
+	}
+	ids = ids[q.Offset:]
+	if q.Limit > 0 && len(ids) > q.Limit {
+		ids = ids[:q.Limit]
+	}
+	events, err := s.eventsInOrder(ctx, ids, q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]HybridResult, len(events))
+	for i, e := range events {
+		out[i] = HybridResult{Event: e, Score: *scores[e.ID]}
+	}
+	return out, nil
+}
+
+// keywordRanks returns up to limit events matching q's query and filters
+// with their FTS5 rank, best first. limit < 0 returns every one.
+func (s *SQLiteStore) keywordRanks(ctx context.Context, q SearchQuery, limit int) ([]scoredID, error) {
+	columns, err := searchColumns(q.Fields)
+	if err != nil {
+		return nil, err
+	}
+	clauses := []string{"events_fts MATCH ?"}
+	args := []interface{}{scopedFTSQuery(queryTerms(q), columns)}
+	filters, filterArgs := filterClauses(q, "e.")
+	clauses = append(clauses, filters...)
+	args = append(args, filterArgs...)
+	query := `SELECT f.event_id, f.rank
+		FROM events_fts f
+		JOIN events e ON e.id = f.event_id
+		WHERE ` + strings.Join(clauses, " AND ") + `
+		ORDER BY rank
+		LIMIT ?`
+
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("keyword ranks: %w", err)
+	}
+	defer rows.Close()
+	var out []scoredID
+	for rows.Next() {
+		var c scoredID
+		if err := rows.Scan(&c.id, &c.score); err != nil {
+			return nil, fmt.Errorf("keyword ranks: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("keyword ranks: %w", err)
+	}
+	traceQuery(ctx, query, start, "rows", len(out))
+	return out, nil
+}
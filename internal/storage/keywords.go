@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// keywordsPerEvent is how many keywords are kept for one body.
+const keywordsPerEvent = 10
+
+// keywordSampleBytes is how much of a body ExtractKeywords reads.
+const keywordSampleBytes = 64 << 10
+
+// maxKeywordWords is the longest keyphrase ExtractKeywords keeps; longer
+// runs between stopwords are usually sentence fragments, not phrases.
+const maxKeywordWords = 3
+
+// keywordStopwords are common words that make poor keywords in any text,
+// on top of the language's own stopwords.
+var keywordStopwords = wordSet("about after all also any because before being between both could did does doing down during each even every few first get got here how however into just like made make many may more most much must new now only other our out over said same should since some still such than them then these those through too two under until upon use used using very want way well what when where while who why within without yes yet your")
+
+// ExtractKeywords picks up to n keyphrases from text with RAKE (Rapid
+// Automatic Keyword Extraction): stopwords, punctuation, numbers and words
+// under three letters split the text into candidate phrases of up to
+// maxKeywordWords words; each word scores its degree (the words it shares
+// phrases with) over its frequency, and a phrase scores the sum of its
+// words for each time it appears. The stopwords are lang's, or English
+// ones when lang has none. Phrases come back lowercased, best first; only
+// the first keywordSampleBytes are read.
+func ExtractKeywords(text, lang string, n int) []string {
+	if len(text) > keywordSampleBytes {
+		text = truncateUTF8(text, keywordSampleBytes)
+	}
+	stop := stopwords[lang]
+	if stop == nil {
+		stop = stopwords["en"]
+	}
+	isStop := func(w string) bool {
+		return stop[w] || keywordStopwords[w] || utf8.RuneCountInString(w) < 3 || strings.IndexFunc(w, unicode.IsLetter) < 0
+	}
+
+	// Split into phrases: runs of non-stopwords not crossing punctuation.
+	var phrases [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 && len(current) <= maxKeywordWords {
+			phrases = append(phrases, current)
+		}
+		current = nil
+	}
+	word := strings.Builder{}
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.Trim(word.String(), "-'’")
+		word.Reset()
+		if w == "" || isStop(w) {
+			flush()
+			return
+		}
+		current = append(current, w)
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '\'' || r == '’':
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			flush()
+		}
+	}
+	endWord()
+	flush()
+
+	freq := map[string]int{}
+	degree := map[string]int{}
+	for _, p := range phrases {
+		for _, w := range p {
+			freq[w]++
+			degree[w] += len(p)
+		}
+	}
+	type candidate struct {
+		phrase string
+		score  float64
+		count  int
+	}
+	index := map[string]int{}
+	var candidates []candidate
+	for _, p := range phrases {
+		key := strings.Join(p, " ")
+		if i, ok := index[key]; ok {
+			candidates[i].count++
+			continue
+		}
+		c := candidate{phrase: key, count: 1}
+		for _, w := range p {
+			c.score += float64(degree[w]) / float64(freq[w])
+		}
+		index[key] = len(candidates)
+		candidates = append(candidates, c)
+	}
+	// Counting every occurrence ranks a phrase the page keeps using above
+	// one it mentions in passing; ties go alphabetically, for stability.
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.score*float64(a.count) != b.score*float64(b.count) {
+			return a.score*float64(a.count) > b.score*float64(b.count)
+		}
+		return a.phrase < b.phrase
+	})
+
+	keywords := []string{}
+	for _, c := range candidates {
+		if len(keywords) == n {
+			break
+		}
+		keywords = append(keywords, c.phrase)
+	}
+	return keywords
+}
+
+// NormalizeKeyword lowercases a keyword and collapses its whitespace, the
+// form keywords are stored and matched in.
+func NormalizeKeyword(keyword string) string {
+	return strings.Join(strings.Fields(strings.ToLower(keyword)), " ")
+}
+
+// KeywordsFor returns the keywords stored for an event, best first.
+func (s *SQLiteStore) KeywordsFor(ctx context.Context, eventID string) ([]string, error) {
+	start := time.Now()
+	query := `SELECT keyword FROM keywords WHERE event_id = ? ORDER BY position`
+	rows, err := s.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("keywords for: %w", err)
+	}
+	defer rows.Close()
+
+	keywords := []string{}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	traceQuery(ctx, query, start, "rows", len(keywords))
+	return keywords, nil
+}
+
+// insertKeywords stores an event's keywords inside tx.
+func insertKeywords(tx *sql.Tx, eventID string, keywords []string) error {
+	for i, k := range keywords {
+		if _, err := tx.Exec(`INSERT INTO keywords (event_id, position, keyword) VALUES (?, ?, ?)`, eventID, i, k); err != nil {
+			return fmt.Errorf("insert keyword: %w", err)
+		}
+	}
+	return nil
+}
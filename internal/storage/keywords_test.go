@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tideText = `Tidal forces are the reason the sea rises twice a day. The moon pulls
+the oceans toward it, and tidal forces on the far side of the Earth pull
+the water away. Spring tides happen at full moon and new moon; neap tides
+happen in between. Tidal forces also slow the Earth's rotation.`
+
+func TestExtractKeywords(t *testing.T) {
+	got := ExtractKeywords(tideText, "en", 4)
+	require.Len(t, got, 4)
+	assert.Equal(t, "tidal forces", got[0], "repeated phrases rank first")
+	assert.Contains(t, got, "sea rises twice")
+
+	assert.Equal(t, []string{"mond zieht", "gezeiten"}, ExtractKeywords("Der Mond zieht die Gezeiten. Die Gezeiten und der Mond zieht.", "de", 5))
+	assert.Empty(t, ExtractKeywords("It is 42, and it was in 2024.", "en", 5), "stopwords, numbers and short words are dropped")
+}
+
+func TestKeywords(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	tides := &Event{URL: "https://a.example/tides", Title: "Tides", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, tides, tideText))
+	assert.NotEmpty(t, tides.Keywords)
+	other := &Event{URL: "https://b.example/go", Title: "Go", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, other, "Goroutines and channels make concurrent programs simple. Goroutines are cheap."))
+
+	stored, err := store.KeywordsFor(ctx, tides.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tides.Keywords, stored)
+
+	for _, keyword := range []string{"tidal forces", "forces"} {
+		events, err := store.SearchEvents(ctx, SearchQuery{Keyword: keyword})
+		require.NoError(t, err)
+		require.Len(t, events, 1, keyword)
+		assert.Equal(t, tides.ID, events[0].ID)
+	}
+	events, err := store.SearchEvents(ctx, SearchQuery{Query: "tides", Keyword: "goroutines"})
+	require.NoError(t, err)
+	assert.Empty(t, events, "the keyword filter applies to full-text search too")
+	events, err = store.SearchEvents(ctx, SearchQuery{Keyword: "tidal%"})
+	require.NoError(t, err)
+	assert.Empty(t, events, "LIKE wildcards are literal")
+
+	require.NoError(t, store.DeleteEvent(ctx, tides.ID))
+	stored, err = store.KeywordsFor(ctx, tides.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored, "keywords go with their event")
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// langSampleBytes is how much of a body DetectLanguage reads; the opening
+// of a page says as much about its language as the whole of it.
+const langSampleBytes = 16 << 10
+
+// minLangStopwords is how many stopwords of one language a Latin-script
+// text needs before DetectLanguage names it.
+const minLangStopwords = 3
+
+// languageNames maps each language DetectLanguage can report, by ISO 639-1
+// code, to its English name.
+var languageNames = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"he": "Hebrew",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"th": "Thai",
+	"zh": "Chinese",
+}
+
+// LanguageName returns the English name of an ISO 639-1 code DetectLanguage
+// reports, or the code itself for any other.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// stopwords holds the most common function words of each Latin-script
+// language DetectLanguage tells apart. They pick the language of a body and
+// are dropped from queries filtered to it (see queryTerms).
+var stopwords = map[string]map[string]bool{
+	"en": wordSet("the and of to in is that for it with as was on are be this by not or have from at which but an they you were their has had its can will would there been"),
+	"de": wordSet("der die das und ist nicht ein eine einen dem den des mit sich auf für von zu im auch es wird sind als wie bei nach noch aber oder wir ich sie werden kann"),
+	"fr": wordSet("le la les et est une des du dans que qui pour pas sur au avec il elle sont ce cette nous vous par plus ont mais ou aux été être leur"),
+	"es": wordSet("el la los las y es una que del en por con para no se su al lo como más pero sus le ya fue este esta son entre cuando muy sin sobre"),
+	"it": wordSet("il la le gli di che è e un una per non sono del della nel con si da anche come più ma alla questo questa ha dei delle nella"),
+	"pt": wordSet("o os as e é um uma que do da dos das no na em para com não se por mais mas ao foi são como seu sua pelo pela isso"),
+	"nl": wordSet("de het een en van is dat niet op te in zijn voor met die er aan ook als bij maar om dan zo wordt naar nog wel uit deze"),
+}
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// IsStopword reports whether word, compared case-insensitively, is a
+// stopword of lang. Languages without a stopword list have none.
+func IsStopword(lang, word string) bool {
+	return stopwords[lang][strings.ToLower(word)]
+}
+
+// DetectLanguage guesses the language of text, returning an ISO 639-1 code
+// or "" when it cannot tell. Texts mostly in a script used by one language
+// (or, for Han with kana, Japanese) are named by script; Latin-script text
+// is named by whichever language's stopwords it uses most, provided there
+// are at least minLangStopwords of them and no other language ties. Only
+// the first langSampleBytes are read.
+func DetectLanguage(text string) string {
+	if len(text) > langSampleBytes {
+		text = truncateUTF8(text, langSampleBytes)
+	}
+
+	scripts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			scripts["latin"]++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			scripts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			scripts["zh"]++
+		case unicode.Is(unicode.Hangul, r):
+			scripts["ko"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["ru"]++
+		case unicode.Is(unicode.Greek, r):
+			scripts["el"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["ar"]++
+		case unicode.Is(unicode.Hebrew, r):
+			scripts["he"]++
+		case unicode.Is(unicode.Thai, r):
+			scripts["th"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Japanese mixes kana with Han; Chinese has no kana at all.
+	if scripts["ja"] > 0 && scripts["ja"]+scripts["zh"] > letters/2 {
+		return "ja"
+	}
+	for _, code := range []string{"zh", "ko", "ru", "el", "ar", "he", "th"} {
+		if scripts[code] > letters/2 {
+			return code
+		}
+	}
+	if scripts["latin"] <= letters/2 {
+		return ""
+	}
+
+	hits := map[string]int{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for lang, set := range stopwords {
+			if set[w] {
+				hits[lang]++
+			}
+		}
+	}
+	best, bestHits, tied := "", 0, false
+	for lang, n := range hits {
+		switch {
+		case n > bestHits:
+			best, bestHits, tied = lang, n, false
+		case n == bestHits:
+			tied = true
+		}
+	}
+	if tied || bestHits < minLangStopwords {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		name, text, want string
+	}{
+		{"english", "The quick brown fox jumps over the lazy dog, and it was not the first time that this happened.", "en"},
+		{"german", "Der schnelle braune Fuchs springt über den faulen Hund, und es ist nicht das erste Mal, dass er das tut.", "de"},
+		{"french", "Le renard brun rapide saute par-dessus le chien paresseux, et ce n'est pas la première fois qu'il le fait dans cette forêt.", "fr"},
+		{"spanish", "El rápido zorro marrón salta sobre el perro perezoso, y no es la primera vez que lo hace en este bosque.", "es"},
+		{"italian", "La volpe veloce salta sopra il cane pigro, e non è la prima volta che lo fa nella foresta della valle.", "it"},
+		{"dutch", "De snelle bruine vos springt over de luie hond, en het is niet de eerste keer dat hij dat doet.", "nl"},
+		{"portuguese", "A rápida raposa marrom pula sobre o cão preguiçoso, e não é a primeira vez que isso acontece com ele na floresta.", "pt"},
+		{"russian", "Быстрая коричневая лиса прыгает через ленивую собаку.", "ru"},
+		{"japanese", "素早い茶色の狐がのろまな犬を飛び越える。", "ja"},
+		{"chinese", "敏捷的棕色狐狸跳过了懒狗。", "zh"},
+		{"korean", "빠른 갈색 여우가 게으른 개를 뛰어넘는다.", "ko"},
+		{"too few stopwords", "Kubernetes operator reconcile loop", ""},
+		{"no letters", "12345 !!! 67890", ""},
+		{"empty", "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, DetectLanguage(tc.text))
+		})
+	}
+}
+
+func TestDetectLanguage_ReadsOnlySample(t *testing.T) {
+	text := strings.Repeat("the cat and the dog ", langSampleBytes/20) + strings.Repeat("der Hund und die Katze ", 10000)
+	assert.Equal(t, "en", DetectLanguage(text))
+}
+
+func TestIsStopword(t *testing.T) {
+	assert.True(t, IsStopword("de", "Und"))
+	assert.False(t, IsStopword("de", "the"))
+	assert.False(t, IsStopword("ja", "the"), "languages without a list have no stopwords")
+	assert.Equal(t, "German", LanguageName("de"))
+	assert.Equal(t, "xx", LanguageName("xx"))
+}
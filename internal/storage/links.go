@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLinksPerEvent caps how many outbound links are kept for one body.
+const maxLinksPerEvent = 1000
+
+// Link is an outbound link found in a captured body.
+type Link struct {
+	URL  string
+	Text string // anchor text; "" for a bare URL
+}
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]\n]*)\]\(\s*<?([^()\s<>]+)>?(?:\s+["'][^"'\n]*["'])?\s*\)`)
+	anchorPattern       = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*(?:"([^"]*)"|'([^']*)')[^>]*>(.*?)</a>`)
+	bareURLPattern      = regexp.MustCompile(`https?://[^\s<>"'()\[\]{}]+`)
+	markupPattern       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ExtractLinks finds the links in body, the captured text of the page at
+// pageURL: markdown links, HTML anchors, and bare http(s) URLs. Relative
+// links are resolved against pageURL, fragments are dropped, and only
+// http(s) links to other pages are kept, each once, in order of first
+// appearance. A link's text is the first anchor text given for it.
+func ExtractLinks(pageURL, body string) []Link {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	self := normalizeLinkURL(base)
+
+	type found struct {
+		at        int
+		raw, text string
+	}
+	var all []found
+	for _, m := range anchorPattern.FindAllStringSubmatchIndex(body, -1) {
+		href := body[max(m[2], m[4]):max(m[3], m[5])]
+		text := strings.Join(strings.Fields(html.UnescapeString(markupPattern.ReplaceAllString(body[m[6]:m[7]], " "))), " ")
+		all = append(all, found{m[0], href, text})
+	}
+	for _, m := range markdownLinkPattern.FindAllStringSubmatchIndex(body, -1) {
+		all = append(all, found{m[0], body[m[4]:m[5]], strings.TrimSpace(body[m[2]:m[3]])})
+	}
+	// Bare URLs also match inside anchors and markdown links; those repeat
+	// a link already found.
+	for _, m := range bareURLPattern.FindAllStringIndex(body, -1) {
+		all = append(all, found{m[0], strings.TrimRight(body[m[0]:m[1]], ".,;:!?*_"), ""})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].at < all[j].at })
+
+	var links []Link
+	index := map[string]int{}
+	for _, f := range all {
+		ref, err := url.Parse(strings.TrimSpace(html.UnescapeString(f.raw)))
+		if err != nil {
+			continue
+		}
+		target := base.ResolveReference(ref)
+		if target.Scheme != "http" && target.Scheme != "https" || target.Host == "" {
+			continue
+		}
+		u := normalizeLinkURL(target)
+		if u == self {
+			continue
+		}
+		if i, ok := index[u]; ok {
+			if links[i].Text == "" {
+				links[i].Text = f.text
+			}
+			continue
+		}
+		if len(links) == maxLinksPerEvent {
+			break
+		}
+		index[u] = len(links)
+		links = append(links, Link{URL: u, Text: f.text})
+	}
+	return links
+}
+
+// normalizeLinkURL drops u's fragment and lowercases its scheme and host,
+// so links to the same page compare equal.
+func normalizeLinkURL(u *url.URL) string {
+	n := *u
+	n.Fragment, n.RawFragment = "", ""
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	return n.String()
+}
+
+// NormalizeLinkURL normalizes a URL the way stored links are (see
+// ExtractLinks), for looking them up. Unparseable URLs are returned as is.
+func NormalizeLinkURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	return normalizeLinkURL(u)
+}
+
+// LinksFrom returns the outbound links stored for an event, in the order
+// they appear in its body.
+func (s *SQLiteStore) LinksFrom(ctx context.Context, eventID string) ([]Link, error) {
+	start := time.Now()
+	query := `SELECT url, text FROM links WHERE event_id = ? ORDER BY position`
+	rows, err := s.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("links from: %w", err)
+	}
+	defer rows.Close()
+
+	links := []Link{}
+	for rows.Next() {
+		var l Link
+		if err := rows.Scan(&l.URL, &l.Text); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	traceQuery(ctx, query, start, "rows", len(links))
+	return links, nil
+}
+
+// LinksTo returns up to limit captured events whose bodies link to
+// targetURL (normalized as by NormalizeLinkURL), newest first. limit <= 0
+// returns every one.
+func (s *SQLiteStore) LinksTo(ctx context.Context, targetURL string, limit int) ([]Event, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	return s.scanEvents(ctx,
+		`SELECT e.id, e.ts, e.url, e.title, e.domain, e.browser, e.source, e.has_body, e.has_embedding, e.content_hash, e.lang, e.canonical_url, e.read_seconds, e.raw_title, e.visit_count
+		FROM links l JOIN events e ON e.id = l.event_id
+		WHERE l.url = ?
+		ORDER BY e.ts DESC
+		LIMIT ?`,
+		NormalizeLinkURL(targetURL), limit,
+	)
+}
+
+// insertLinks stores an event's outbound links inside tx.
+func insertLinks(tx *sql.Tx, eventID string, links []Link) error {
+	for i, l := range links {
+		if _, err := tx.Exec(`INSERT INTO links (event_id, position, url, text) VALUES (?, ?, ?, ?)`, eventID, i, l.URL, l.Text); err != nil {
+			return fmt.Errorf("insert link: %w", err)
+		}
+	}
+	return nil
+}
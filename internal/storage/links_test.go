@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLinks(t *testing.T) {
+	body := `See [the docs](https://Go.dev/doc/ "Docs") and [install](/dl/#linux).
+<p>Read <a class="x" href='https://sqlite.org/fts5.html'>the <b>FTS5</b> page</a>.</p>
+Plain: https://example.com/a, and again https://go.dev/doc/#top.
+Skip [mail](mailto:a@b.c), [self](#section) and [js](javascript:void(0)).`
+
+	assert.Equal(t, []Link{
+		{URL: "https://go.dev/doc/", Text: "the docs"},
+		{URL: "https://blog.example/dl/", Text: "install"},
+		{URL: "https://sqlite.org/fts5.html", Text: "the FTS5 page"},
+		{URL: "https://example.com/a"},
+	}, ExtractLinks("https://blog.example/post#intro", body))
+
+	assert.Empty(t, ExtractLinks("https://blog.example/", "no links here"))
+}
+
+func TestLinks(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	a := &Event{URL: "https://a.example/post", Title: "A", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, a, "Links to [target](https://t.example/page) and [other](https://o.example/)."))
+	b := &Event{URL: "https://b.example/post", Title: "B", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, b, "Also https://T.example/page#frag"))
+
+	links, err := store.LinksFrom(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []Link{{"https://t.example/page", "target"}, {"https://o.example/", "other"}}, links)
+
+	from, err := store.LinksTo(ctx, "https://t.example/page#anchor", 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{a.ID, b.ID}, []string{from[0].ID, from[1].ID})
+
+	require.NoError(t, store.DeleteEvent(ctx, a.ID))
+	links, err = store.LinksFrom(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Empty(t, links, "links go with their event")
+}
@@ -0,0 +1,31 @@
+package storage
+
+import "database/sql"
+
+// migrateV002 adds composite indexes for the hot filtered-search paths:
+// a domain or source filter ordered by time. Leading with the equality
+// column and then ts lets SQLite walk the index in order and stop at the
+// LIMIT, instead of collecting every match and sorting it. The trailing
+// flag and browser columns let the remaining filters be checked without
+// touching the table row.
+func migrateV002(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE INDEX IF NOT EXISTS idx_events_domain_ts ON events(domain, ts DESC, source, browser, has_body, has_embedding)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_source_ts ON events(source, ts DESC, domain, browser, has_body, has_embedding)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertV002 drops the covering search indexes.
+func revertV002(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP INDEX IF EXISTS idx_events_domain_ts`,
+		`DROP INDEX IF EXISTS idx_events_source_ts`,
+	)
+}
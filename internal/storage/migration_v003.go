@@ -0,0 +1,29 @@
+package storage
+
+import "database/sql"
+
+// migrateV003 records body truncation on content rows: truncated is set
+// when the stored body was cut to capture.max_body_bytes, and
+// original_size keeps the byte size before truncation.
+func migrateV003(tx *sql.Tx) error {
+	stmts := []string{
+		`ALTER TABLE content ADD COLUMN truncated BOOLEAN NOT NULL DEFAULT 0`,
+		`ALTER TABLE content ADD COLUMN original_size INTEGER NOT NULL DEFAULT 0`,
+		`UPDATE content SET original_size = byte_size`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertV003 drops the truncation columns from content.
+func revertV003(tx *sql.Tx) error {
+	return execAll(tx,
+		`ALTER TABLE content DROP COLUMN truncated`,
+		`ALTER TABLE content DROP COLUMN original_size`,
+	)
+}
@@ -0,0 +1,34 @@
+package storage
+
+import "database/sql"
+
+// migrateV004 adds search_history, which records executed searches when
+// search.record_history is enabled. Rows live only in the local database
+// and are removed by purge and retention pruning like events.
+func migrateV004(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS search_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ts DATETIME NOT NULL,
+			query TEXT NOT NULL DEFAULT '',
+			domain TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			browser TEXT NOT NULL DEFAULT '',
+			since TEXT NOT NULL DEFAULT '',
+			result_count INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_history_ts ON search_history(ts)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertV004 drops search_history and the searches recorded in it.
+func revertV004(tx *sql.Tx) error {
+	return execAll(tx, `DROP TABLE IF EXISTS search_history`)
+}
@@ -0,0 +1,29 @@
+package storage
+
+import "database/sql"
+
+// migrateV005 adds sync_events, which records for each event the device it
+// was exchanged with by chronicle sync: this device's own ID once exported,
+// or the originating device's ID when imported. Events without a row have
+// not been shared yet.
+func migrateV005(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS sync_events (
+			event_id TEXT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			device   TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_events_device ON sync_events(device)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertV005 drops sync_events, so every event counts as unshared again.
+func revertV005(tx *sql.Tx) error {
+	return execAll(tx, `DROP TABLE IF EXISTS sync_events`)
+}
@@ -0,0 +1,30 @@
+package storage
+
+import "database/sql"
+
+// migrateV006 adds extensions, the browser extensions that have registered
+// with the ingest daemon and when each was last heard from.
+func migrateV006(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS extensions (
+			id            TEXT PRIMARY KEY,
+			browser       TEXT NOT NULL,
+			version       TEXT NOT NULL DEFAULT '',
+			profile       TEXT NOT NULL DEFAULT '',
+			registered_at TEXT NOT NULL,
+			last_seen     TEXT NOT NULL
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertV006 drops extensions.
+func revertV006(tx *sql.Tx) error {
+	return execAll(tx, `DROP TABLE IF EXISTS extensions`)
+}
@@ -0,0 +1,26 @@
+package storage
+
+import "database/sql"
+
+// migrateV007 adds per-event visit state for features built on it:
+// visit_count (times the page was seen, folded into one event), pinned
+// (kept regardless of retention), deleted_at (soft deletion; NULL while
+// live), and duration (seconds the page was in view, 0 when unknown).
+func migrateV007(tx *sql.Tx) error {
+	return execAll(tx,
+		`ALTER TABLE events ADD COLUMN visit_count INTEGER NOT NULL DEFAULT 1`,
+		`ALTER TABLE events ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT 0`,
+		`ALTER TABLE events ADD COLUMN deleted_at DATETIME`,
+		`ALTER TABLE events ADD COLUMN duration INTEGER NOT NULL DEFAULT 0`,
+	)
+}
+
+// revertV007 drops the visit state columns.
+func revertV007(tx *sql.Tx) error {
+	return execAll(tx,
+		`ALTER TABLE events DROP COLUMN visit_count`,
+		`ALTER TABLE events DROP COLUMN pinned`,
+		`ALTER TABLE events DROP COLUMN deleted_at`,
+		`ALTER TABLE events DROP COLUMN duration`,
+	)
+}
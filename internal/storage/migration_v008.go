@@ -0,0 +1,48 @@
+package storage
+
+import "database/sql"
+
+// dayLayout is the form of domain_days.day, as SQLite's date() writes it.
+const dayLayout = "2006-01-02"
+
+// migrateV008 adds domain_days, a rollup of event counts per domain per UTC
+// day, so range analytics over long histories sum a few rows per day
+// instead of scanning events. Triggers on events keep it current through
+// every insert, delete and edit path (captures, sync imports, prune and
+// purge), and existing events are counted once here.
+func migrateV008(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS domain_days (
+			day    TEXT NOT NULL,
+			domain TEXT NOT NULL,
+			events INTEGER NOT NULL,
+			PRIMARY KEY (day, domain)
+		) WITHOUT ROWID`,
+		`INSERT INTO domain_days (day, domain, events)
+			SELECT date(ts), domain, COUNT(*) FROM events GROUP BY date(ts), domain`,
+		`CREATE TRIGGER IF NOT EXISTS domain_days_insert AFTER INSERT ON events BEGIN
+			INSERT INTO domain_days (day, domain, events) VALUES (date(NEW.ts), NEW.domain, 1)
+				ON CONFLICT (day, domain) DO UPDATE SET events = events + 1;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS domain_days_delete AFTER DELETE ON events BEGIN
+			UPDATE domain_days SET events = events - 1 WHERE day = date(OLD.ts) AND domain = OLD.domain;
+			DELETE FROM domain_days WHERE day = date(OLD.ts) AND domain = OLD.domain AND events <= 0;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS domain_days_update AFTER UPDATE OF ts, domain ON events BEGIN
+			UPDATE domain_days SET events = events - 1 WHERE day = date(OLD.ts) AND domain = OLD.domain;
+			DELETE FROM domain_days WHERE day = date(OLD.ts) AND domain = OLD.domain AND events <= 0;
+			INSERT INTO domain_days (day, domain, events) VALUES (date(NEW.ts), NEW.domain, 1)
+				ON CONFLICT (day, domain) DO UPDATE SET events = events + 1;
+		END`,
+	)
+}
+
+// revertV008 drops the rollup and its triggers.
+func revertV008(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP TRIGGER IF EXISTS domain_days_insert`,
+		`DROP TRIGGER IF EXISTS domain_days_delete`,
+		`DROP TRIGGER IF EXISTS domain_days_update`,
+		`DROP TABLE IF EXISTS domain_days`,
+	)
+}
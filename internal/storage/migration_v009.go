@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrateV009 adds events.lang, the ISO 639-1 code DetectLanguage gives an
+// event's body ("" when unknown or there is no body), with an index for
+// language-filtered searches, and detects it for bodies already stored.
+func migrateV009(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`ALTER TABLE events ADD COLUMN lang TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX IF NOT EXISTS idx_events_lang_ts ON events(lang, ts)`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT event_id, CAST(substr(CAST(body AS BLOB), 1, ?) AS TEXT) FROM content`, langSampleBytes)
+	if err != nil {
+		return fmt.Errorf("read bodies: %w", err)
+	}
+	detected := map[string]string{}
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return err
+		}
+		if lang := DetectLanguage(body); lang != "" {
+			detected[id] = lang
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, lang := range detected {
+		if _, err := tx.Exec(`UPDATE events SET lang = ? WHERE id = ?`, lang, id); err != nil {
+			return fmt.Errorf("set language: %w", err)
+		}
+	}
+	return nil
+}
+
+// revertV009 drops the language column and its index.
+func revertV009(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP INDEX IF EXISTS idx_events_lang_ts`,
+		`ALTER TABLE events DROP COLUMN lang`,
+	)
+}
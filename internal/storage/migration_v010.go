@@ -0,0 +1,23 @@
+package storage
+
+import "database/sql"
+
+// migrateV010 adds page_meta, the OpenGraph and article metadata a page
+// declared when it was captured. Events without any have no row.
+func migrateV010(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS page_meta (
+			event_id       TEXT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			og_title       TEXT NOT NULL DEFAULT '',
+			og_description TEXT NOT NULL DEFAULT '',
+			og_image       TEXT NOT NULL DEFAULT '',
+			author         TEXT NOT NULL DEFAULT '',
+			published      DATETIME
+		)`,
+	)
+}
+
+// revertV010 drops page_meta.
+func revertV010(tx *sql.Tx) error {
+	return execAll(tx, `DROP TABLE IF EXISTS page_meta`)
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrateV011 adds links, the outbound links found in each stored body
+// (see ExtractLinks), with an index for finding the pages that link to a
+// URL, and extracts them from bodies already stored.
+func migrateV011(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`CREATE TABLE IF NOT EXISTS links (
+			event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			url      TEXT NOT NULL,
+			text     TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (event_id, url)
+		) WITHOUT ROWID`,
+		`CREATE INDEX IF NOT EXISTS idx_links_url ON links(url)`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT e.id, e.url, c.body FROM content c JOIN events e ON e.id = c.event_id`)
+	if err != nil {
+		return fmt.Errorf("read bodies: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, pageURL, body string
+		if err := rows.Scan(&id, &pageURL, &body); err != nil {
+			return err
+		}
+		if err := insertLinks(tx, id, ExtractLinks(pageURL, body)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// revertV011 drops links.
+func revertV011(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP INDEX IF EXISTS idx_links_url`,
+		`DROP TABLE IF EXISTS links`,
+	)
+}
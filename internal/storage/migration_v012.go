@@ -0,0 +1,16 @@
+package storage
+
+import "database/sql"
+
+// migrateV012 adds events.canonical_url, the <link rel=canonical> URL a
+// page declared when it differs from the URL captured; "" otherwise.
+func migrateV012(tx *sql.Tx) error {
+	return execAll(tx,
+		`ALTER TABLE events ADD COLUMN canonical_url TEXT NOT NULL DEFAULT ''`,
+	)
+}
+
+// revertV012 drops the canonical URL column.
+func revertV012(tx *sql.Tx) error {
+	return execAll(tx, `ALTER TABLE events DROP COLUMN canonical_url`)
+}
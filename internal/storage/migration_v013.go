@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrateV013 adds keywords, the top keyphrases of each stored body (see
+// ExtractKeywords), with an index for finding events by keyword, and
+// extracts them from bodies already stored.
+func migrateV013(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`CREATE TABLE IF NOT EXISTS keywords (
+			event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			keyword  TEXT NOT NULL,
+			PRIMARY KEY (event_id, keyword)
+		) WITHOUT ROWID`,
+		`CREATE INDEX IF NOT EXISTS idx_keywords_keyword ON keywords(keyword)`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT e.id, e.lang, c.body FROM content c JOIN events e ON e.id = c.event_id`)
+	if err != nil {
+		return fmt.Errorf("read bodies: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, lang, body string
+		if err := rows.Scan(&id, &lang, &body); err != nil {
+			return err
+		}
+		if err := insertKeywords(tx, id, ExtractKeywords(body, lang, keywordsPerEvent)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// revertV013 drops keywords.
+func revertV013(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP INDEX IF EXISTS idx_keywords_keyword`,
+		`DROP TABLE IF EXISTS keywords`,
+	)
+}
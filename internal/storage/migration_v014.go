@@ -0,0 +1,21 @@
+package storage
+
+import "database/sql"
+
+// migrateV014 adds summaries, the short generated summary kept for an
+// event's body (see chronicle summarize --pending).
+func migrateV014(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS summaries (
+			event_id   TEXT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			summary    TEXT NOT NULL,
+			model      TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		)`,
+	)
+}
+
+// revertV014 drops summaries.
+func revertV014(tx *sql.Tx) error {
+	return execAll(tx, `DROP TABLE IF EXISTS summaries`)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migrateV015 adds events.read_seconds, the estimated reading time of an
+// event's body (see ReadingTime; 0 when there is none), and estimates it
+// for bodies already stored. Truncated bodies are scaled up to the size
+// they had when captured.
+func migrateV015(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`ALTER TABLE events ADD COLUMN read_seconds INTEGER NOT NULL DEFAULT 0`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT event_id, body, byte_size, original_size FROM content`)
+	if err != nil {
+		return fmt.Errorf("read bodies: %w", err)
+	}
+	estimated := map[string]int64{}
+	for rows.Next() {
+		var id, body string
+		var size, original int64
+		if err := rows.Scan(&id, &body, &size, &original); err != nil {
+			rows.Close()
+			return err
+		}
+		words := int64(countWords(body))
+		if original > size && size > 0 {
+			words = words * original / size
+		}
+		if seconds := int64(ReadingTime(int(words)) / time.Second); seconds > 0 {
+			estimated[id] = seconds
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, seconds := range estimated {
+		if _, err := tx.Exec(`UPDATE events SET read_seconds = ? WHERE id = ?`, seconds, id); err != nil {
+			return fmt.Errorf("set reading time: %w", err)
+		}
+	}
+	return nil
+}
+
+// revertV015 drops the reading time column.
+func revertV015(tx *sql.Tx) error {
+	return execAll(tx, `ALTER TABLE events DROP COLUMN read_seconds`)
+}
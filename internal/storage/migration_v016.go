@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"unicode/utf8"
+)
+
+// migrateV016 adds content.word_count and content.char_count, counted from
+// the stored body like byte_size (see countWords), and counts them for
+// bodies already stored.
+func migrateV016(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`ALTER TABLE content ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE content ADD COLUMN char_count INTEGER NOT NULL DEFAULT 0`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT event_id, body FROM content`)
+	if err != nil {
+		return fmt.Errorf("read bodies: %w", err)
+	}
+	type counts struct{ words, chars int }
+	counted := map[string]counts{}
+	for rows.Next() {
+		var id, body string
+		if err := rows.Scan(&id, &body); err != nil {
+			rows.Close()
+			return err
+		}
+		counted[id] = counts{countWords(body), utf8.RuneCountInString(body)}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, c := range counted {
+		if _, err := tx.Exec(`UPDATE content SET word_count = ?, char_count = ? WHERE event_id = ?`, c.words, c.chars, id); err != nil {
+			return fmt.Errorf("set counts: %w", err)
+		}
+	}
+	return nil
+}
+
+// revertV016 drops the word and character counts.
+func revertV016(tx *sql.Tx) error {
+	return execAll(tx,
+		`ALTER TABLE content DROP COLUMN char_count`,
+		`ALTER TABLE content DROP COLUMN word_count`,
+	)
+}
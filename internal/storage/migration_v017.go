@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrateV017 adds events.raw_title, the title as captured when cleanTitle
+// stripped a site-name suffix from it, and cleans the titles already
+// stored, in the full-text index too.
+func migrateV017(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`ALTER TABLE events ADD COLUMN raw_title TEXT NOT NULL DEFAULT ''`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT id, title, domain FROM events`)
+	if err != nil {
+		return fmt.Errorf("read titles: %w", err)
+	}
+	cleaned := map[string]string{}
+	for rows.Next() {
+		var id, title, domain string
+		if err := rows.Scan(&id, &title, &domain); err != nil {
+			rows.Close()
+			return err
+		}
+		if t := cleanTitle(title, domain); t != title {
+			cleaned[id] = t
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// The index is created by the store, so a new database has none yet.
+	var hasFTS int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'events_fts'`).Scan(&hasFTS); err != nil {
+		return err
+	}
+	for id, title := range cleaned {
+		if _, err := tx.Exec(`UPDATE events SET raw_title = title, title = ? WHERE id = ?`, title, id); err != nil {
+			return fmt.Errorf("clean title: %w", err)
+		}
+		if hasFTS > 0 {
+			if _, err := tx.Exec(`UPDATE events_fts SET title = ? WHERE event_id = ?`, title, id); err != nil {
+				return fmt.Errorf("reindex title: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// revertV017 restores the raw titles, in the full-text index too, and
+// drops the column.
+func revertV017(tx *sql.Tx) error {
+	var hasFTS int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'events_fts'`).Scan(&hasFTS); err != nil {
+		return err
+	}
+	if hasFTS > 0 {
+		if err := execAll(tx,
+			`UPDATE events_fts SET title = (SELECT raw_title FROM events WHERE id = event_id)
+			WHERE event_id IN (SELECT id FROM events WHERE raw_title != '')`,
+		); err != nil {
+			return err
+		}
+	}
+	return execAll(tx,
+		`UPDATE events SET title = raw_title WHERE raw_title != ''`,
+		`ALTER TABLE events DROP COLUMN raw_title`,
+	)
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"database/sql"
+)
+
+// ftsRank weights BM25 by column (event_id, title, url, body) so a match in
+// the title or URL outranks the same words deep in a captured body.
+const ftsRank = `bm25(0.0, 10.0, 5.0, 1.0)`
+
+// migrateV018 rebuilds the full-text index with a body column holding each
+// event's captured content, so keyword search matches bodies through FTS5
+// rather than a LIKE scan of the content table.
+func migrateV018(tx *sql.Tx) error {
+	var hasFTS int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'events_fts'`).Scan(&hasFTS); err != nil {
+		return err
+	}
+	// The index is created by the store, so a new database has none yet.
+	if hasFTS == 0 {
+		return nil
+	}
+	return execAll(tx,
+		`DROP TABLE IF EXISTS events_fts_vocab`,
+		`DROP TABLE events_fts`,
+		`CREATE VIRTUAL TABLE events_fts USING fts5(
+			event_id UNINDEXED,
+			title,
+			url,
+			body,
+			tokenize='unicode61'
+		)`,
+		`INSERT INTO events_fts(events_fts, rank) VALUES('rank', '`+ftsRank+`')`,
+		`INSERT INTO events_fts (event_id, title, url, body)
+			SELECT e.id, e.title, e.url, COALESCE(c.body, '')
+			FROM events e LEFT JOIN content c ON c.event_id = e.id`,
+		`CREATE VIRTUAL TABLE events_fts_vocab USING fts5vocab(events_fts, 'row')`,
+	)
+}
+
+// revertV018 rebuilds the full-text index over titles and URLs only.
+func revertV018(tx *sql.Tx) error {
+	var hasFTS int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'events_fts'`).Scan(&hasFTS); err != nil {
+		return err
+	}
+	if hasFTS == 0 {
+		return nil
+	}
+	return execAll(tx,
+		`DROP TABLE IF EXISTS events_fts_vocab`,
+		`DROP TABLE events_fts`,
+		`CREATE VIRTUAL TABLE events_fts USING fts5(
+			event_id UNINDEXED,
+			title,
+			url,
+			tokenize='unicode61'
+		)`,
+		`INSERT INTO events_fts (event_id, title, url) SELECT id, title, url FROM events`,
+		`CREATE VIRTUAL TABLE events_fts_vocab USING fts5vocab(events_fts, 'row')`,
+	)
+}
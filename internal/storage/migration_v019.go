@@ -0,0 +1,23 @@
+package storage
+
+import "database/sql"
+
+// migrateV019 adds the embeddings table holding each event's embedding
+// vector, described by its embedding_metadata row.
+func migrateV019(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE embeddings (
+			event_id TEXT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			vector   BLOB NOT NULL
+		)`,
+	)
+}
+
+// revertV019 drops the vectors, and with them every event's embedding.
+func revertV019(tx *sql.Tx) error {
+	return execAll(tx,
+		`DROP TABLE embeddings`,
+		`DELETE FROM embedding_metadata`,
+		`UPDATE events SET has_embedding = 0 WHERE has_embedding = 1`,
+	)
+}
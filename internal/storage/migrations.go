@@ -3,13 +3,25 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"time"
 )
 
-// migration represents a single schema migration.
+// migration represents a single schema migration. Revert undoes Apply; it
+// is nil for migrations that cannot be rolled back.
 type migration struct {
 	Version int
 	Name    string
 	Apply   func(tx *sql.Tx) error
+	Revert  func(tx *sql.Tx) error
+}
+
+// MigrationStatus reports whether one known migration has been applied.
+type MigrationStatus struct {
+	Version    int
+	Name       string
+	Applied    bool
+	AppliedAt  time.Time // zero when pending
+	Reversible bool
 }
 
 // MigrationRunner applies pending migrations to a SQLite database.
@@ -24,6 +36,24 @@ func NewMigrationRunner(db *sql.DB) *MigrationRunner {
 		db: db,
 		migrations: []migration{
 			{Version: 1, Name: "initial_schema", Apply: migrateV001},
+			{Version: 2, Name: "covering_search_indexes", Apply: migrateV002, Revert: revertV002},
+			{Version: 3, Name: "content_truncation", Apply: migrateV003, Revert: revertV003},
+			{Version: 4, Name: "search_history", Apply: migrateV004, Revert: revertV004},
+			{Version: 5, Name: "sync_events", Apply: migrateV005, Revert: revertV005},
+			{Version: 6, Name: "extensions", Apply: migrateV006, Revert: revertV006},
+			{Version: 7, Name: "event_visit_state", Apply: migrateV007, Revert: revertV007},
+			{Version: 8, Name: "domain_day_rollups", Apply: migrateV008, Revert: revertV008},
+			{Version: 9, Name: "event_language", Apply: migrateV009, Revert: revertV009},
+			{Version: 10, Name: "page_metadata", Apply: migrateV010, Revert: revertV010},
+			{Version: 11, Name: "outbound_links", Apply: migrateV011, Revert: revertV011},
+			{Version: 12, Name: "canonical_urls", Apply: migrateV012, Revert: revertV012},
+			{Version: 13, Name: "keywords", Apply: migrateV013, Revert: revertV013},
+			{Version: 14, Name: "summaries", Apply: migrateV014, Revert: revertV014},
+			{Version: 15, Name: "reading_time", Apply: migrateV015, Revert: revertV015},
+			{Version: 16, Name: "content_counts", Apply: migrateV016, Revert: revertV016},
+			{Version: 17, Name: "raw_titles", Apply: migrateV017, Revert: revertV017},
+			{Version: 18, Name: "fts_body", Apply: migrateV018, Revert: revertV018},
+			{Version: 19, Name: "embedding_vectors", Apply: migrateV019, Revert: revertV019},
 		},
 	}
 }
@@ -32,6 +62,112 @@ func NewMigrationRunner(db *sql.DB) *MigrationRunner {
 // foreign keys, creates the schema_migrations tracking table, then applies
 // each migration that hasn't been recorded yet.
 func (r *MigrationRunner) Run() error {
+	return r.Up(r.LatestVersion())
+}
+
+// Up is Run limited to migrations up to and including version target.
+func (r *MigrationRunner) Up(target int) error {
+	if err := r.prepare(); err != nil {
+		return err
+	}
+
+	for _, m := range r.migrations {
+		if m.Version > target {
+			break
+		}
+		applied, err := r.isApplied(m.Version)
+		if err != nil {
+			return fmt.Errorf("check migration %d: %w", m.Version, err)
+		}
+		if applied {
+			continue
+		}
+
+		if err := r.apply(m); err != nil {
+			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts every applied migration newer than version target, newest
+// first, leaving the schema at target. Nothing is reverted if any of them
+// cannot be.
+func (r *MigrationRunner) Down(target int) error {
+	if err := r.prepare(); err != nil {
+		return err
+	}
+
+	var pending []migration
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		m := r.migrations[i]
+		if m.Version <= target {
+			break
+		}
+		applied, err := r.isApplied(m.Version)
+		if err != nil {
+			return fmt.Errorf("check migration %d: %w", m.Version, err)
+		}
+		if !applied {
+			continue
+		}
+		if m.Revert == nil {
+			return fmt.Errorf("migration %d (%s) cannot be reverted", m.Version, m.Name)
+		}
+		pending = append(pending, m)
+	}
+
+	for _, m := range pending {
+		if err := r.revert(m); err != nil {
+			return fmt.Errorf("revert migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Status lists every migration known to this binary, oldest first, with
+// whether and when it was applied.
+func (r *MigrationRunner) Status() ([]MigrationStatus, error) {
+	if err := r.prepare(); err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+	rows, err := r.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version], _ = parseTimestamp(at)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, len(r.migrations))
+	for i, m := range r.migrations {
+		at, ok := applied[m.Version]
+		out[i] = MigrationStatus{
+			Version:    m.Version,
+			Name:       m.Name,
+			Applied:    ok,
+			AppliedAt:  at,
+			Reversible: m.Revert != nil,
+		}
+	}
+	return out, nil
+}
+
+// prepare enables WAL mode and foreign keys and creates the
+// schema_migrations table.
+func (r *MigrationRunner) prepare() error {
 	// Enable WAL mode for concurrent read performance.
 	if _, err := r.db.Exec("PRAGMA journal_mode = WAL"); err != nil {
 		return fmt.Errorf("set WAL mode: %w", err)
@@ -52,22 +188,40 @@ func (r *MigrationRunner) Run() error {
 	`); err != nil {
 		return fmt.Errorf("create schema_migrations table: %w", err)
 	}
+	return nil
+}
 
+// LatestVersion returns the highest migration version known to this binary.
+func (r *MigrationRunner) LatestVersion() int {
+	latest := 0
 	for _, m := range r.migrations {
-		applied, err := r.isApplied(m.Version)
-		if err != nil {
-			return fmt.Errorf("check migration %d: %w", m.Version, err)
-		}
-		if applied {
-			continue
+		if m.Version > latest {
+			latest = m.Version
 		}
+	}
+	return latest
+}
 
-		if err := r.apply(m); err != nil {
-			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
-		}
+// SchemaVersion returns the highest migration version recorded in db, or 0
+// if no migrations have been applied.
+func SchemaVersion(db *sql.DB) (int, error) {
+	var exists int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+	).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, nil
 	}
 
-	return nil
+	var version int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
 }
 
 // isApplied checks whether a migration version has already been recorded.
@@ -103,3 +257,32 @@ func (r *MigrationRunner) apply(m migration) error {
 
 	return tx.Commit()
 }
+
+// revert undoes a migration inside a transaction and removes its record.
+func (r *MigrationRunner) revert(m migration) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := m.Revert(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+		return fmt.Errorf("remove migration record: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// execAll runs stmts in order within tx.
+func execAll(tx *sql.Tx, stmts ...string) error {
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
@@ -120,11 +122,11 @@ func TestMigrationRunner_Idempotent(t *testing.T) {
 	require.NoError(t, runner.Run())
 	require.NoError(t, runner.Run())
 
-	// Should still have exactly 1 migration recorded
+	// Each migration should be recorded exactly once
 	var count int
 	err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
 	require.NoError(t, err)
-	assert.Equal(t, 1, count, "should have exactly 1 migration recorded after double-run")
+	assert.Equal(t, len(runner.migrations), count, "each migration should be recorded once after double-run")
 
 	// Should still have exactly 24 default exclusions (not doubled)
 	err = db.QueryRow("SELECT COUNT(*) FROM exclusions WHERE is_default = 1").Scan(&count)
@@ -145,6 +147,21 @@ func TestMigrationRunner_SchemaMigrationsTracking(t *testing.T) {
 	assert.Equal(t, "initial_schema", name)
 }
 
+func TestSchemaVersion(t *testing.T) {
+	db := openTestDB(t)
+
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version, "fresh DB should report version 0")
+
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Run())
+
+	version, err = SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, runner.LatestVersion(), version)
+}
+
 func TestMigrationRunner_WALMode(t *testing.T) {
 	db := openTestDB(t)
 	runner := NewMigrationRunner(db)
@@ -206,3 +223,357 @@ func TestMigrationRunner_EventsTableColumns(t *testing.T) {
 	assert.False(t, hasBody)
 	assert.False(t, hasEmbedding)
 }
+
+func TestMigrationV002_CoveringIndexes(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, NewMigrationRunner(db).Run())
+
+	for _, idx := range []string{"idx_events_domain_ts", "idx_events_source_ts"} {
+		var name string
+		err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='index' AND name=?", idx).Scan(&name)
+		require.NoError(t, err, "index %s should exist", idx)
+	}
+}
+
+// queryPlan returns the EXPLAIN QUERY PLAN detail lines for query.
+func queryPlan(t *testing.T, db *sql.DB, query string, args ...interface{}) string {
+	t.Helper()
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		require.NoError(t, rows.Scan(&id, &parent, &notused, &detail))
+		lines = append(lines, detail)
+	}
+	require.NoError(t, rows.Err())
+	return strings.Join(lines, "\n")
+}
+
+func TestFilteredSearchPlansUseIndexes(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, NewMigrationRunner(db).Run())
+
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	yes := true
+	cases := []struct {
+		name  string
+		q     SearchQuery
+		index string
+	}{
+		{"domain", SearchQuery{Domain: "github.com", Since: since, Limit: 10}, "idx_events_domain_ts"},
+		{"domain with flags", SearchQuery{Domain: "github.com", HasBody: &yes, Browser: "chrome", Limit: 10}, "idx_events_domain_ts"},
+		{"source", SearchQuery{Source: "extension", Since: since, Limit: 10}, "idx_events_source_ts"},
+		{"source oldest", SearchQuery{Source: "manual", Sort: SortOldest, Limit: 10}, "idx_events_source_ts"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args := filteredSQL(tc.q)
+			plan := queryPlan(t, db, query, args...)
+			assert.Contains(t, plan, tc.index, plan)
+			assert.NotContains(t, plan, "SCAN events", plan)
+			assert.NotContains(t, plan, "TEMP B-TREE", plan)
+		})
+	}
+}
+
+func TestMigrationV007_VisitStateDefaults(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, NewMigrationRunner(db).Run())
+
+	_, err := db.Exec(`INSERT INTO events (id, url) VALUES ('CHR-v7', 'https://example.com')`)
+	require.NoError(t, err)
+
+	var visits, duration int
+	var pinned bool
+	var deletedAt sql.NullString
+	err = db.QueryRow("SELECT visit_count, pinned, deleted_at, duration FROM events WHERE id = 'CHR-v7'").
+		Scan(&visits, &pinned, &deletedAt, &duration)
+	require.NoError(t, err)
+	assert.Equal(t, 1, visits)
+	assert.False(t, pinned)
+	assert.False(t, deletedAt.Valid)
+	assert.Equal(t, 0, duration)
+}
+
+func TestMigrationRunner_DownAndUp(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Run())
+	_, err := db.Exec(`INSERT INTO events (id, url) VALUES ('CHR-keep', 'https://example.com')`)
+	require.NoError(t, err)
+
+	require.NoError(t, runner.Down(3))
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name IN ('search_history', 'sync_events', 'extensions')").Scan(&n))
+	assert.Zero(t, n, "tables added after v3 should be dropped")
+	_, err = db.Exec("SELECT visit_count FROM events")
+	assert.Error(t, err, "v7 columns should be dropped")
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM events").Scan(&n))
+	assert.Equal(t, 1, n, "events survive a rollback")
+
+	require.NoError(t, runner.Up(5))
+	version, err = SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, 5, version)
+
+	require.NoError(t, runner.Run())
+	version, err = SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, runner.LatestVersion(), version)
+}
+
+func TestMigrationRunner_DownRefusesIrreversible(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Run())
+
+	err := runner.Down(0)
+	assert.EqualError(t, err, "migration 1 (initial_schema) cannot be reverted")
+
+	version, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, runner.LatestVersion(), version, "nothing is reverted when any step cannot be")
+}
+
+func TestMigrationRunner_Status(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(2))
+
+	status, err := runner.Status()
+	require.NoError(t, err)
+	require.Len(t, status, runner.LatestVersion())
+	assert.Equal(t, MigrationStatus{Version: 1, Name: "initial_schema", Applied: true, AppliedAt: status[0].AppliedAt}, status[0])
+	assert.False(t, status[0].AppliedAt.IsZero())
+	assert.True(t, status[1].Applied)
+	assert.True(t, status[1].Reversible)
+	assert.False(t, status[2].Applied)
+	assert.True(t, status[2].AppliedAt.IsZero())
+}
+
+func TestMigrationV008_BackfillsDomainDays(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(7))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, domain, ts) VALUES
+		('CHR-a', 'https://a.example/1', 'a.example', '2026-01-01T09:00:00Z'),
+		('CHR-b', 'https://a.example/2', 'a.example', '2026-01-01T23:59:59Z'),
+		('CHR-c', 'https://a.example/3', 'a.example', '2026-01-02T00:00:00Z'),
+		('CHR-d', 'https://b.example/1', 'b.example', '2026-01-01T12:00:00Z')`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	assert.Equal(t, map[string]int{
+		"2026-01-01 a.example": 2,
+		"2026-01-02 a.example": 1,
+		"2026-01-01 b.example": 1,
+	}, domainDays(t, db))
+
+	require.NoError(t, runner.Down(7))
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'domain_days%'").Scan(&n))
+	assert.Zero(t, n, "table and triggers should be dropped")
+}
+
+// domainDays reads the domain_days rollup keyed by "day domain".
+func domainDays(t *testing.T, db *sql.DB) map[string]int {
+	t.Helper()
+	rows, err := db.Query("SELECT day, domain, events FROM domain_days")
+	require.NoError(t, err)
+	defer rows.Close()
+	out := map[string]int{}
+	for rows.Next() {
+		var day, domain string
+		var n int
+		require.NoError(t, rows.Scan(&day, &domain, &n))
+		out[day+" "+domain] = n
+	}
+	require.NoError(t, rows.Err())
+	return out
+}
+
+func TestMigrationV009_DetectsStoredBodies(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(8))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, has_body) VALUES ('CHR-fr', 'https://a.example', 1), ('CHR-none', 'https://b.example', 0)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size) VALUES ('CHR-fr', 'Le train est en retard et il ne sont pas dans la gare avec les autres.', 70)`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	langs := map[string]string{}
+	rows, err := db.Query("SELECT id, lang FROM events")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var id, lang string
+		require.NoError(t, rows.Scan(&id, &lang))
+		langs[id] = lang
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, map[string]string{"CHR-fr": "fr", "CHR-none": ""}, langs)
+}
+
+func TestMigrationV011_ExtractsStoredLinks(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(10))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, has_body) VALUES ('CHR-l', 'https://a.example/post', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size) VALUES ('CHR-l', 'See [b](/b) and https://c.example/', 30)`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var urls []string
+	rows, err := db.Query("SELECT url FROM links WHERE event_id = 'CHR-l' ORDER BY position")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var u string
+		require.NoError(t, rows.Scan(&u))
+		urls = append(urls, u)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"https://a.example/b", "https://c.example/"}, urls)
+}
+
+func TestMigrationV013_ExtractsStoredKeywords(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(12))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, has_body, lang) VALUES ('CHR-k', 'https://a.example/tides', 1, 'en')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size) VALUES ('CHR-k', ?, ?)`, tideText, len(tideText))
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var first string
+	require.NoError(t, db.QueryRow("SELECT keyword FROM keywords WHERE event_id = 'CHR-k' AND position = 0").Scan(&first))
+	assert.Equal(t, "tidal forces", first)
+}
+
+func TestMigrationV015_EstimatesStoredReadingTimes(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(14))
+
+	body := strings.Repeat("word ", wordsPerMinute)
+	_, err := db.Exec(`INSERT INTO events (id, url, has_body) VALUES ('CHR-r', 'https://a.example/', 1), ('CHR-t', 'https://b.example/', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size, original_size) VALUES ('CHR-r', ?, ?, ?), ('CHR-t', ?, ?, ?)`,
+		body, len(body), len(body), body, len(body), 3*len(body))
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var full, truncated int
+	require.NoError(t, db.QueryRow("SELECT read_seconds FROM events WHERE id = 'CHR-r'").Scan(&full))
+	require.NoError(t, db.QueryRow("SELECT read_seconds FROM events WHERE id = 'CHR-t'").Scan(&truncated))
+	assert.Equal(t, 60, full)
+	assert.Equal(t, 180, truncated, "scaled to the captured size")
+}
+
+func TestMigrationV016_CountsStoredBodies(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(15))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, has_body) VALUES ('CHR-c', 'https://a.example/', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size) VALUES ('CHR-c', 'Écrire deux mots', 17)`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var words, chars int
+	require.NoError(t, db.QueryRow("SELECT word_count, char_count FROM content WHERE event_id = 'CHR-c'").Scan(&words, &chars))
+	assert.Equal(t, 3, words)
+	assert.Equal(t, 16, chars)
+}
+
+func TestMigrationV017_CleansStoredTitles(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(16))
+
+	_, err := db.Exec(`INSERT INTO events (id, url, title, domain) VALUES
+		('CHR-m', 'https://medium.com/a', 'Why SQLite works — Medium', 'medium.com'),
+		('CHR-p', 'https://example.org/', 'Rise - and fall', 'example.org')`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var title, raw string
+	require.NoError(t, db.QueryRow("SELECT title, raw_title FROM events WHERE id = 'CHR-m'").Scan(&title, &raw))
+	assert.Equal(t, "Why SQLite works", title)
+	assert.Equal(t, "Why SQLite works — Medium", raw)
+	require.NoError(t, db.QueryRow("SELECT title, raw_title FROM events WHERE id = 'CHR-p'").Scan(&title, &raw))
+	assert.Equal(t, "Rise - and fall", title)
+	assert.Empty(t, raw)
+
+	require.NoError(t, runner.Down(16))
+	require.NoError(t, db.QueryRow("SELECT title FROM events WHERE id = 'CHR-m'").Scan(&title))
+	assert.Equal(t, "Why SQLite works — Medium", title)
+}
+
+func TestMigrationV018_IndexesStoredBodies(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Up(17))
+
+	_, err := db.Exec(`CREATE VIRTUAL TABLE events_fts USING fts5(event_id UNINDEXED, title, url, tokenize='unicode61')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO events (id, url, title, has_body) VALUES
+		('CHR-b', 'https://a.example/', 'Notes', 1),
+		('CHR-n', 'https://b.example/', 'Bookmarks', 0)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO content (event_id, body, byte_size) VALUES ('CHR-b', 'Tuning the write-ahead log', 26)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO events_fts (event_id, title, url) SELECT id, title, url FROM events`)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run())
+
+	var id string
+	require.NoError(t, db.QueryRow(`SELECT event_id FROM events_fts WHERE events_fts MATCH 'body : ahead'`).Scan(&id))
+	assert.Equal(t, "CHR-b", id)
+	require.NoError(t, db.QueryRow(`SELECT event_id FROM events_fts WHERE events_fts MATCH 'bookmarks'`).Scan(&id))
+	assert.Equal(t, "CHR-n", id)
+
+	require.NoError(t, runner.Down(17))
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM events_fts WHERE events_fts MATCH 'notes OR bookmarks'`).Scan(&n))
+	assert.Equal(t, 2, n)
+	_, err = db.Exec(`SELECT body FROM events_fts`)
+	assert.Error(t, err, "the body column is dropped")
+}
+
+func TestMigrationV019_RevertDropsEmbeddings(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewMigrationRunner(db)
+	require.NoError(t, runner.Run())
+
+	_, err := db.Exec(`INSERT INTO events (id, url, has_embedding) VALUES ('CHR-e', 'https://a.example/', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO embeddings (event_id, vector) VALUES ('CHR-e', x'0000803f')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO embedding_metadata (event_id, model_name, dimensions) VALUES ('CHR-e', 'ollama:test', 1)`)
+	require.NoError(t, err)
+
+	require.NoError(t, runner.Down(18))
+	var embedded bool
+	var metadata int
+	require.NoError(t, db.QueryRow("SELECT has_embedding FROM events WHERE id = 'CHR-e'").Scan(&embedded))
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM embedding_metadata").Scan(&metadata))
+	assert.False(t, embedded)
+	assert.Zero(t, metadata)
+}
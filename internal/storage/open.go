@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Pragmas are per-connection SQLite performance settings. Zero values leave
+// SQLite's defaults in place.
+type Pragmas struct {
+	CacheSizeMB int    // page cache per connection
+	MmapSizeMB  int    // memory-mapped I/O window; 0 disables
+	Synchronous string // off, normal, full, or extra
+	TempStore   string // default, file, or memory
+}
+
+// statements returns the PRAGMA statements for p, validating enum values.
+func (p Pragmas) statements() ([]string, error) {
+	var stmts []string
+	if p.CacheSizeMB > 0 {
+		// Negative cache_size is in KiB rather than pages.
+		stmts = append(stmts, fmt.Sprintf("PRAGMA cache_size = -%d", p.CacheSizeMB*1024))
+	}
+	if p.MmapSizeMB > 0 {
+		stmts = append(stmts, fmt.Sprintf("PRAGMA mmap_size = %d", int64(p.MmapSizeMB)<<20))
+	}
+	if p.Synchronous != "" {
+		v := strings.ToLower(p.Synchronous)
+		switch v {
+		case "off", "normal", "full", "extra":
+		default:
+			return nil, fmt.Errorf("invalid synchronous level %q (want off, normal, full, or extra)", p.Synchronous)
+		}
+		stmts = append(stmts, "PRAGMA synchronous = "+v)
+	}
+	if p.TempStore != "" {
+		v := strings.ToLower(p.TempStore)
+		switch v {
+		case "default", "file", "memory":
+		default:
+			return nil, fmt.Errorf("invalid temp_store %q (want default, file, or memory)", p.TempStore)
+		}
+		stmts = append(stmts, "PRAGMA temp_store = "+v)
+	}
+	return stmts, nil
+}
+
+// OpenDB opens the SQLite database at path with foreign keys on, applying
+// pragmas to every pooled connection (they are per-connection settings, so
+// a one-off Exec would only reach one of them). Run migrations before use.
+func OpenDB(path string, pragmas Pragmas) (*sql.DB, error) {
+	stmts, err := pragmas.statements()
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(&pragmaConnector{
+		dsn:     path + "?_foreign_keys=on",
+		pragmas: stmts,
+		driver:  &sqlite3.SQLiteDriver{},
+	}), nil
+}
+
+// pragmaConnector opens sqlite3 connections and applies pragmas to each.
+type pragmaConnector struct {
+	dsn     string
+	pragmas []string
+	driver  *sqlite3.SQLiteDriver
+}
+
+func (c *pragmaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range c.pragmas {
+		if _, err := conn.(*sqlite3.SQLiteConn).Exec(stmt, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	return conn, nil
+}
+
+func (c *pragmaConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDB_AppliesPragmasToEveryConnection(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "p.db"), Pragmas{
+		CacheSizeMB: 32,
+		MmapSizeMB:  64,
+		Synchronous: "NORMAL",
+		TempStore:   "memory",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	// Hold two connections at once so the pool must open both.
+	c1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer c1.Close()
+	c2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer c2.Close()
+
+	for _, conn := range []*sql.Conn{c1, c2} {
+		var cache, mmap int64
+		var sync, temp, fk int
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&cache))
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA mmap_size").Scan(&mmap))
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&sync))
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA temp_store").Scan(&temp))
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&fk))
+		assert.Equal(t, int64(-32*1024), cache)
+		assert.Equal(t, int64(64<<20), mmap)
+		assert.Equal(t, 1, sync) // NORMAL
+		assert.Equal(t, 2, temp) // MEMORY
+		assert.Equal(t, 1, fk)
+	}
+}
+
+func TestOpenDB_InvalidPragmas(t *testing.T) {
+	_, err := OpenDB(filepath.Join(t.TempDir(), "p.db"), Pragmas{Synchronous: "sometimes"})
+	assert.ErrorContains(t, err, "invalid synchronous level")
+
+	_, err = OpenDB(filepath.Join(t.TempDir(), "p.db"), Pragmas{TempStore: "ram"})
+	assert.ErrorContains(t, err, "invalid temp_store")
+}
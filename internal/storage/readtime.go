@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"time"
+	"unicode"
+)
+
+// wordsPerMinute is the silent reading speed ReadingTime assumes, the
+// average adults reach on non-fiction.
+const wordsPerMinute = 238
+
+// ReadingTime estimates how long words take to read, rounded up to the
+// second.
+func ReadingTime(words int) time.Duration {
+	return time.Duration((words*60+wordsPerMinute-1)/wordsPerMinute) * time.Second
+}
+
+// countWords counts the words in text: whitespace-separated runs holding a
+// letter or digit. Han, kana and Thai, written without spaces, count one
+// word per character, which reads at about the same speed.
+func countWords(text string) int {
+	n, inWord := 0, false
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			inWord = false
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai):
+			n++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				n++
+				inWord = true
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountWords(t *testing.T) {
+	assert.Equal(t, 0, countWords(""))
+	assert.Equal(t, 6, countWords("Don't panic — it's only 42 words.\n"))
+	assert.Equal(t, 3, countWords("潮汐 tides"), "one word per Han character")
+}
+
+func TestReadingTime(t *testing.T) {
+	assert.Equal(t, time.Duration(0), ReadingTime(0))
+	assert.Equal(t, time.Second, ReadingTime(1), "rounded up")
+	assert.Equal(t, time.Minute, ReadingTime(wordsPerMinute))
+}
+
+func TestSearchEvents_MinReadTime(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	long := &Event{URL: "https://a.example/long", Title: "Long read", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, long, strings.Repeat("word ", 10*wordsPerMinute)))
+	short := &Event{URL: "https://a.example/short", Title: "Short note", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, short, strings.Repeat("word ", 50)))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.example/bare", Title: "Bare", Source: "manual"}))
+
+	got, err := store.GetEvent(ctx, long.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, got.ReadTime)
+
+	events, err := store.SearchEvents(ctx, SearchQuery{MinReadTime: 5 * time.Minute})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, long.ID, events[0].ID)
+	assert.Equal(t, 10*time.Minute, events[0].ReadTime)
+
+	events, err = store.SearchEvents(ctx, SearchQuery{Query: "read", MinReadTime: time.Second})
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "applies to text search too")
+}
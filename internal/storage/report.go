@@ -0,0 +1,267 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DomainCounts counts events per domain from since through until, busiest
+// first. limit <= 0 returns every domain. Whole UTC days in the range are
+// read from the domain_days rollup; only the partial days at either end
+// scan events.
+func (s *SQLiteStore) DomainCounts(ctx context.Context, since, until time.Time, limit int) ([]DomainCount, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	sinceStr, untilStr := since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339)
+
+	// Whole days run from the first midnight at or after since up to the
+	// last midnight at or before until.
+	first := since.UTC().Truncate(24 * time.Hour)
+	if first.Before(since) {
+		first = first.AddDate(0, 0, 1)
+	}
+	end := until.UTC().Truncate(24 * time.Hour)
+	if !first.Before(end) {
+		return s.domainCounts(ctx, "domain counts",
+			`SELECT domain, COUNT(*) AS n FROM events
+			WHERE ts >= ? AND ts <= ?
+			GROUP BY domain
+			ORDER BY n DESC, domain
+			LIMIT ?`,
+			sinceStr, untilStr, limit,
+		)
+	}
+
+	firstStr, endStr := first.Format(time.RFC3339), end.Format(time.RFC3339)
+	return s.domainCounts(ctx, "domain counts",
+		`SELECT domain, SUM(n) AS n FROM (
+			SELECT domain, events AS n FROM domain_days WHERE day >= ? AND day < ?
+			UNION ALL
+			SELECT domain, COUNT(*) AS n FROM events
+			WHERE (ts >= ? AND ts < ?) OR (ts >= ? AND ts <= ?)
+			GROUP BY domain
+		)
+		GROUP BY domain
+		ORDER BY n DESC, domain
+		LIMIT ?`,
+		first.Format(dayLayout), end.Format(dayLayout),
+		sinceStr, firstStr, endStr, untilStr, limit,
+	)
+}
+
+// NewDomains returns domains first visited between since and until, with
+// their event counts in that range, busiest first.
+func (s *SQLiteStore) NewDomains(ctx context.Context, since, until time.Time) ([]DomainCount, error) {
+	sinceStr := since.UTC().Format(time.RFC3339)
+	return s.domainCounts(ctx, "new domains",
+		`SELECT domain, COUNT(*) AS n FROM events
+		WHERE ts >= ? AND ts <= ?
+		  AND domain NOT IN (SELECT domain FROM events WHERE ts < ?)
+		GROUP BY domain
+		ORDER BY n DESC, domain`,
+		sinceStr, until.UTC().Format(time.RFC3339), sinceStr,
+	)
+}
+
+// ListDomains returns every captured domain starting with prefix (case-
+// insensitive), with its event count, busiest first. An empty prefix lists
+// all domains.
+func (s *SQLiteStore) ListDomains(ctx context.Context, prefix string) ([]DomainCount, error) {
+	prefix = strings.ToLower(prefix)
+	return s.domainCounts(ctx, "list domains",
+		`SELECT domain, SUM(events) AS n FROM domain_days
+		WHERE domain >= ? AND (? = '' OR domain < ?)
+		GROUP BY domain
+		ORDER BY n DESC, domain`,
+		prefix, prefix, prefix+"\U0010FFFF",
+	)
+}
+
+// ListSources returns each capture source in the database with its event
+// count, busiest first.
+func (s *SQLiteStore) ListSources(ctx context.Context) ([]ValueCount, error) {
+	return s.valueCounts(ctx, "list sources",
+		"SELECT source, COUNT(*) AS n FROM events WHERE source != '' GROUP BY source ORDER BY n DESC, source")
+}
+
+// ListBrowsers returns each browser in the database with its event count,
+// busiest first. Events without a browser are not counted.
+func (s *SQLiteStore) ListBrowsers(ctx context.Context) ([]ValueCount, error) {
+	return s.valueCounts(ctx, "list browsers",
+		"SELECT browser, COUNT(*) AS n FROM events WHERE browser != '' GROUP BY browser ORDER BY n DESC, browser")
+}
+
+// ListLanguages returns each detected body language in the database, by
+// ISO 639-1 code, with its event count, busiest first.
+func (s *SQLiteStore) ListLanguages(ctx context.Context) ([]ValueCount, error) {
+	return s.valueCounts(ctx, "list languages",
+		"SELECT lang, COUNT(*) AS n FROM events WHERE lang != '' GROUP BY lang ORDER BY n DESC, lang")
+}
+
+func (s *SQLiteStore) valueCounts(ctx context.Context, what, query string) ([]ValueCount, error) {
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var out []ValueCount
+	for rows.Next() {
+		var vc ValueCount
+		if err := rows.Scan(&vc.Value, &vc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, vc)
+	}
+	traceQuery(ctx, query, start, "rows", len(out))
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) domainCounts(ctx context.Context, what, query string, args ...interface{}) ([]DomainCount, error) {
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var out []DomainCount
+	for rows.Next() {
+		var dc DomainCount
+		if err := rows.Scan(&dc.Domain, &dc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, dc)
+	}
+	traceQuery(ctx, query, start, "rows", len(out))
+	return out, rows.Err()
+}
+
+// LongestReads returns up to limit events from since through until with a
+// captured body, longest estimated reading time (see ReadingTime) first.
+func (s *SQLiteStore) LongestReads(ctx context.Context, since, until time.Time, limit int) ([]LongRead, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.id, e.ts, e.url, e.title, e.domain, e.browser, e.source,
+		        e.has_body, e.has_embedding, e.content_hash, e.read_seconds, c.original_size
+		FROM events e
+		JOIN content c ON c.event_id = e.id
+		WHERE e.ts >= ? AND e.ts <= ?
+		ORDER BY e.read_seconds DESC, c.original_size DESC, e.ts DESC
+		LIMIT ?`,
+		since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("longest reads: %w", err)
+	}
+	defer rows.Close()
+
+	var out []LongRead
+	for rows.Next() {
+		var r LongRead
+		var contentHash sql.NullString
+		var tsStr string
+		var readSeconds int64
+		if err := rows.Scan(
+			&r.ID, &tsStr, &r.URL, &r.Title, &r.Domain,
+			&r.Browser, &r.Source, &r.HasBody, &r.HasEmbed, &contentHash, &readSeconds, &r.Bytes,
+		); err != nil {
+			return nil, fmt.Errorf("scan long read: %w", err)
+		}
+		r.Timestamp, _ = parseTimestamp(tsStr)
+		r.ReadTime = time.Duration(readSeconds) * time.Second
+		r.ContentHash = contentHash.String
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// PinnedEvents returns up to limit pinned events captured from since
+// through until, newest first.
+func (s *SQLiteStore) PinnedEvents(ctx context.Context, since, until time.Time, limit int) ([]Event, error) {
+	return s.scanEvents(ctx,
+		`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+		FROM events
+		WHERE pinned = 1 AND ts >= ? AND ts <= ?
+		ORDER BY ts DESC
+		LIMIT ?`,
+		since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339), limit,
+	)
+}
+
+// PastCaptures returns up to limit pages captured from since through
+// until, newest first, one per page (see Event.PageURL): its latest
+// capture, with that capture's body size and the number of captures of the
+// page in the range.
+func (s *SQLiteStore) PastCaptures(ctx context.Context, since, until time.Time, limit int) ([]PastCapture, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.id, e.ts, e.url, e.title, e.domain, e.browser, e.source,
+		        e.has_body, e.has_embedding, e.content_hash, e.canonical_url, e.read_seconds,
+		        COALESCE(c.original_size, 0), v.visits
+		FROM (
+			SELECT `+pageKeySQL+` AS page, MAX(ts) AS ts, COUNT(*) AS visits
+			FROM events
+			WHERE ts >= ? AND ts <= ?
+			GROUP BY page
+		) v
+		JOIN events e ON e.ts = v.ts AND COALESCE(NULLIF(e.canonical_url, ''), e.url) = v.page
+		LEFT JOIN content c ON c.event_id = e.id
+		ORDER BY e.ts DESC
+		LIMIT ?`,
+		since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("past captures: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PastCapture
+	seen := map[string]bool{}
+	for rows.Next() {
+		var p PastCapture
+		var contentHash sql.NullString
+		var tsStr string
+		var readSeconds int64
+		if err := rows.Scan(
+			&p.ID, &tsStr, &p.URL, &p.Title, &p.Domain,
+			&p.Browser, &p.Source, &p.HasBody, &p.HasEmbed, &contentHash, &p.CanonicalURL, &readSeconds, &p.Bytes, &p.Visits,
+		); err != nil {
+			return nil, fmt.Errorf("scan past capture: %w", err)
+		}
+		// Two captures of a page in the same second both match MAX(ts).
+		if seen[p.PageURL()] {
+			continue
+		}
+		seen[p.PageURL()] = true
+		p.Timestamp, _ = parseTimestamp(tsStr)
+		p.ContentHash = contentHash.String
+		p.ReadTime = time.Duration(readSeconds) * time.Second
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// SharedContentEvents returns events at or after since whose content_hash
+// is shared with another such event, grouped by hash and oldest first
+// within each group. A zero since means no lower bound.
+func (s *SQLiteStore) SharedContentEvents(ctx context.Context, since time.Time) ([]Event, error) {
+	sinceStr := ""
+	if !since.IsZero() {
+		sinceStr = since.UTC().Format(time.RFC3339)
+	}
+	return s.scanEvents(ctx,
+		`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+		FROM events
+		WHERE ts >= ? AND content_hash IN (
+			SELECT content_hash FROM events
+			WHERE content_hash IS NOT NULL AND content_hash != '' AND ts >= ?
+			GROUP BY content_hash HAVING COUNT(*) > 1
+		)
+		ORDER BY content_hash, ts`,
+		sinceStr, sinceStr,
+	)
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportQueries(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -7)
+
+	add := func(url string, at time.Time, body string) {
+		e := &Event{URL: url, Title: url, Source: "manual", Timestamp: at}
+		if body == "" {
+			require.NoError(t, store.AddEvent(ctx, e))
+		} else {
+			require.NoError(t, store.AddEventWithContent(ctx, e, body))
+		}
+	}
+	add("https://old.example/a", now.AddDate(0, 0, -20), strings.Repeat("x", 5000))
+	add("https://old.example/b", now.AddDate(0, 0, -1), "short")
+	add("https://fresh.example/a", now.AddDate(0, 0, -2), strings.Repeat("word ", 60))
+	add("https://fresh.example/b", now.AddDate(0, 0, -3), "")
+	add("https://other.example/", now.AddDate(0, 0, -4), "")
+
+	top, err := store.DomainCounts(ctx, since, now, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{"fresh.example", 2}, {"old.example", 1}, {"other.example", 1}}, top)
+
+	top, err = store.DomainCounts(ctx, since, now, 1)
+	require.NoError(t, err)
+	assert.Len(t, top, 1)
+
+	fresh, err := store.NewDomains(ctx, since, now)
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{"fresh.example", 2}, {"other.example", 1}}, fresh)
+
+	reads, err := store.LongestReads(ctx, since, now, 10)
+	require.NoError(t, err)
+	require.Len(t, reads, 2, "only bodies captured in range")
+	assert.Equal(t, "https://fresh.example/a", reads[0].URL)
+	assert.Equal(t, int64(300), reads[0].Bytes)
+	assert.Equal(t, ReadingTime(60), reads[0].ReadTime)
+	assert.Equal(t, "https://old.example/b", reads[1].URL)
+}
+
+func TestDomainDaysRollup(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	add := func(url string, at time.Time) {
+		require.NoError(t, store.AddEvent(ctx, &Event{URL: url, Title: url, Source: "manual", Timestamp: at}))
+	}
+	for d := 0; d < 5; d++ {
+		add("https://a.example/morning", day.AddDate(0, 0, d).Add(6*time.Hour))
+		add("https://a.example/evening", day.AddDate(0, 0, d).Add(20*time.Hour))
+		if d%2 == 0 {
+			add("https://b.example/", day.AddDate(0, 0, d).Add(12*time.Hour))
+		}
+	}
+	assert.Equal(t, map[string]int{
+		"2026-03-01 a.example": 2, "2026-03-02 a.example": 2, "2026-03-03 a.example": 2,
+		"2026-03-04 a.example": 2, "2026-03-05 a.example": 2,
+		"2026-03-01 b.example": 1, "2026-03-03 b.example": 1, "2026-03-05 b.example": 1,
+	}, domainDays(t, store.db))
+
+	// Ranges that start and end mid-day mix rollup days with raw edges.
+	ranges := []struct{ since, until time.Time }{
+		{day, day.AddDate(0, 0, 5)},
+		{day.Add(10 * time.Hour), day.AddDate(0, 0, 4).Add(10 * time.Hour)},
+		{day.Add(13 * time.Hour), day.Add(21 * time.Hour)},
+		{day.AddDate(0, 0, 2).Add(12 * time.Hour), day.AddDate(0, 0, 2).Add(12 * time.Hour)},
+	}
+	for _, r := range ranges {
+		got, err := store.DomainCounts(ctx, r.since, r.until, 0)
+		require.NoError(t, err)
+		assert.Equal(t, rawDomainCounts(t, store, r.since, r.until), got, "%s to %s", r.since, r.until)
+	}
+
+	_, err := store.PruneExpired(ctx, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	_, err = store.PurgeMatching(ctx, PurgeFilter{Domain: "b.example"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"2026-03-02 a.example": 2, "2026-03-03 a.example": 2,
+		"2026-03-04 a.example": 2, "2026-03-05 a.example": 2,
+	}, domainDays(t, store.db))
+
+	domains, err := store.ListDomains(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{"a.example", 8}}, domains)
+
+	require.NoError(t, store.PurgeAll(ctx))
+	assert.Empty(t, domainDays(t, store.db))
+}
+
+// rawDomainCounts counts events per domain in range straight from events,
+// for checking DomainCounts against.
+func rawDomainCounts(t *testing.T, store *SQLiteStore, since, until time.Time) []DomainCount {
+	t.Helper()
+	counts, err := store.domainCounts(context.Background(), "raw domain counts",
+		`SELECT domain, COUNT(*) AS n FROM events WHERE ts >= ? AND ts <= ?
+		GROUP BY domain ORDER BY n DESC, domain`,
+		since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339),
+	)
+	require.NoError(t, err)
+	return counts
+}
+
+func TestSharedContentEvents(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	add := func(url, hash string, ago time.Duration) {
+		require.NoError(t, store.AddEvent(ctx, &Event{URL: url, Title: url, Source: "manual", ContentHash: hash, Timestamp: now.Add(-ago)}))
+	}
+	add("https://a.example/1", "h1", time.Hour)
+	add("https://a.example/2", "h1", 2*time.Hour)
+	add("https://b.example/", "h2", time.Hour)
+	add("https://c.example/", "", time.Hour)
+	add("https://d.example/", "", time.Hour)
+	add("https://e.example/", "h3", time.Hour)
+	add("https://e.example/old", "h3", 100*24*time.Hour)
+
+	events, err := store.SharedContentEvents(ctx, time.Time{})
+	require.NoError(t, err)
+	var urls []string
+	for _, e := range events {
+		urls = append(urls, e.URL)
+	}
+	assert.Equal(t, []string{"https://a.example/2", "https://a.example/1", "https://e.example/old", "https://e.example/"}, urls)
+
+	events, err = store.SharedContentEvents(ctx, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.Len(t, events, 2, "the h3 pair straddles the window")
+}
+
+func TestPinnedEvents(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	pin := func(url string, at time.Time, pinned bool) {
+		e := &Event{URL: url, Title: url, Source: "manual", Timestamp: at}
+		require.NoError(t, store.AddEvent(ctx, e))
+		_, err := store.db.Exec(`UPDATE events SET pinned = ? WHERE id = ?`, pinned, e.ID)
+		require.NoError(t, err)
+	}
+	pin("https://a.example/", now.AddDate(0, 0, -3), true)
+	pin("https://b.example/", now.AddDate(0, 0, -1), true)
+	pin("https://c.example/", now.AddDate(0, 0, -2), false)
+	pin("https://d.example/", now.AddDate(0, 0, -10), true) // outside the range
+
+	events, err := store.PinnedEvents(ctx, now.AddDate(0, 0, -7), now, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "https://b.example/", events[0].URL)
+	assert.Equal(t, "https://a.example/", events[1].URL)
+
+	events, err = store.PinnedEvents(ctx, now.AddDate(0, 0, -7), now, 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestPastCaptures(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	add := func(url string, at time.Time, body string) {
+		e := &Event{URL: url, Title: url, Source: "manual", Timestamp: at}
+		if body == "" {
+			require.NoError(t, store.AddEvent(ctx, e))
+		} else {
+			require.NoError(t, store.AddEventWithContent(ctx, e, body))
+		}
+	}
+	add("https://a.example/", now.AddDate(0, 0, -30), "")
+	add("https://a.example/", now.AddDate(0, 0, -20), strings.Repeat("x", 5000))
+	add("https://a.example/", now.AddDate(0, 0, -1), "") // outside the range
+	add("https://b.example/", now.AddDate(0, 0, -25), "")
+
+	pages, err := store.PastCaptures(ctx, now.AddDate(0, 0, -60), now.AddDate(0, 0, -7), 10)
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "https://a.example/", pages[0].URL)
+	assert.Equal(t, now.AddDate(0, 0, -20), pages[0].Timestamp)
+	assert.Equal(t, int64(5000), pages[0].Bytes)
+	assert.Equal(t, 2, pages[0].Visits)
+	assert.Equal(t, "https://b.example/", pages[1].URL)
+	assert.Equal(t, int64(0), pages[1].Bytes)
+	assert.Equal(t, 1, pages[1].Visits)
+}
+
+func TestListDomains(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	for _, u := range []string{
+		"https://go.dev/blog", "https://go.dev/doc", "https://github.com/x",
+		"https://github.com/y", "https://github.com/z", "https://news.ycombinator.com/",
+	} {
+		require.NoError(t, store.AddEvent(ctx, &Event{URL: u, Title: u, Source: "manual"}))
+	}
+
+	all, err := store.ListDomains(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{"github.com", 3}, {"go.dev", 2}, {"news.ycombinator.com", 1}}, all)
+
+	g, err := store.ListDomains(ctx, "G")
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{"github.com", 3}, {"go.dev", 2}}, g)
+
+	none, err := store.ListDomains(ctx, "zzz")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestListSourcesAndBrowsers(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	for _, e := range []*Event{
+		{URL: "https://a.example/", Title: "A", Source: "extension", Browser: "chrome"},
+		{URL: "https://b.example/", Title: "B", Source: "extension", Browser: "firefox"},
+		{URL: "https://c.example/", Title: "C", Source: "extension", Browser: "chrome"},
+		{URL: "https://d.example/", Title: "D", Source: "manual"},
+	} {
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+
+	sources, err := store.ListSources(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ValueCount{{"extension", 3}, {"manual", 1}}, sources)
+
+	browsers, err := store.ListBrowsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ValueCount{{"chrome", 2}, {"firefox", 1}}, browsers, "events without a browser are not counted")
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, sources, stats.Sources)
+	assert.Equal(t, browsers, stats.Browsers)
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RecordSearch appends rec to the search history. A zero At is set to now.
+func (s *SQLiteStore) RecordSearch(ctx context.Context, rec *SearchRecord) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if rec.At.IsZero() {
+		rec.At = time.Now()
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO search_history (ts, query, domain, source, browser, since, result_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.At.UTC().Format(time.RFC3339), rec.Query, rec.Domain, rec.Source, rec.Browser, rec.Since, rec.Results,
+	)
+	if err != nil {
+		return fmt.Errorf("record search: %w", err)
+	}
+	rec.ID, err = res.LastInsertId()
+	return err
+}
+
+// ListSearches returns up to limit recorded searches, newest first.
+func (s *SQLiteStore) ListSearches(ctx context.Context, limit int) ([]SearchRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, ts, query, domain, source, browser, since, result_count
+		FROM search_history ORDER BY ts DESC, id DESC LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list searches: %w", err)
+	}
+	defer rows.Close()
+
+	var recs []SearchRecord
+	for rows.Next() {
+		var r SearchRecord
+		var ts string
+		if err := rows.Scan(&r.ID, &ts, &r.Query, &r.Domain, &r.Source, &r.Browser, &r.Since, &r.Results); err != nil {
+			return nil, err
+		}
+		r.At, _ = parseTimestamp(ts)
+		recs = append(recs, r)
+	}
+	return recs, rows.Err()
+}
+
+// TopSearches returns the most repeated non-empty queries, most frequent
+// first.
+func (s *SQLiteStore) TopSearches(ctx context.Context, limit int) ([]QueryCount, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT query, COUNT(*) AS n, MAX(ts),
+		        SUM(CASE WHEN result_count = 0 THEN 1 ELSE 0 END)
+		FROM search_history
+		WHERE query != ''
+		GROUP BY query
+		ORDER BY n DESC, MAX(ts) DESC
+		LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("top searches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []QueryCount
+	for rows.Next() {
+		var q QueryCount
+		var ts string
+		if err := rows.Scan(&q.Query, &q.Count, &ts, &q.ZeroResults); err != nil {
+			return nil, err
+		}
+		q.LastAt, _ = parseTimestamp(ts)
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+// SearchDomainHints returns the domains searches have most often been
+// narrowed to with a domain filter, as a signal of where the user expects
+// to find things.
+func (s *SQLiteStore) SearchDomainHints(ctx context.Context, limit int) ([]DomainCount, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT domain, COUNT(*) AS n FROM search_history
+		WHERE domain != ''
+		GROUP BY domain
+		ORDER BY n DESC, domain
+		LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search domain hints: %w", err)
+	}
+	defer rows.Close()
+
+	var out []DomainCount
+	for rows.Next() {
+		var dc DomainCount
+		if err := rows.Scan(&dc.Domain, &dc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, dc)
+	}
+	return out, rows.Err()
+}
+
+// ClearSearches deletes all recorded searches and returns how many there
+// were.
+func (s *SQLiteStore) ClearSearches(ctx context.Context) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM search_history")
+	if err != nil {
+		return 0, fmt.Errorf("clear searches: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHistory(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recs := []*SearchRecord{
+		{At: base, Query: "sqlite wal", Results: 3},
+		{At: base.Add(time.Hour), Query: "sqlite wal", Domain: "sqlite.org", Results: 0},
+		{At: base.Add(2 * time.Hour), Query: "go generics", Domain: "go.dev", Results: 5},
+		{At: base.Add(3 * time.Hour), Query: "", Domain: "sqlite.org", Results: 9},
+	}
+	for _, r := range recs {
+		require.NoError(t, store.RecordSearch(ctx, r))
+		assert.NotZero(t, r.ID)
+	}
+
+	list, err := store.ListSearches(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "sqlite.org", list[0].Domain)
+	assert.Equal(t, "go generics", list[1].Query)
+	assert.Equal(t, base.Add(2*time.Hour), list[1].At)
+
+	top, err := store.TopSearches(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, QueryCount{Query: "sqlite wal", Count: 2, LastAt: base.Add(time.Hour), ZeroResults: 1}, top[0])
+
+	hints, err := store.SearchDomainHints(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []DomainCount{{Domain: "sqlite.org", Count: 2}, {Domain: "go.dev", Count: 1}}, hints)
+
+	n, err := store.ClearSearches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+	list, err = store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestSearchHistory_PrunedAndPurged(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, store.RecordSearch(ctx, &SearchRecord{At: now.AddDate(0, 0, -60), Query: "old"}))
+	require.NoError(t, store.RecordSearch(ctx, &SearchRecord{At: now, Query: "new"}))
+
+	_, err := store.PruneExpired(ctx, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	list, err := store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "new", list[0].Query)
+
+	require.NoError(t, store.PurgeAll(ctx))
+	list, err = store.ListSearches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
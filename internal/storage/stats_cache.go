@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// statsCachingStore wraps a Store so GetStats results are reused for a
+// short time. GetStats runs several aggregate queries, which get slow on
+// large databases; long-running servers call it far more often than the
+// numbers meaningfully change.
+type statsCachingStore struct {
+	Store
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	cached *Stats
+	at     time.Time
+}
+
+// WithStatsCache returns store with GetStats cached for ttl. Other methods
+// pass straight through.
+func WithStatsCache(store Store, ttl time.Duration) Store {
+	return &statsCachingStore{Store: store, ttl: ttl, now: time.Now}
+}
+
+// GetStats returns cached stats if younger than the TTL, otherwise queries
+// the underlying store. Concurrent callers on expiry share one refresh.
+func (s *statsCachingStore) GetStats(ctx context.Context) (*Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.now().Sub(s.at) < s.ttl {
+		stats := *s.cached
+		return &stats, nil
+	}
+
+	stats, err := s.Store.GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cached, s.at = stats, s.now()
+
+	copied := *stats
+	return &copied, nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStatsCache(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cached := WithStatsCache(store, 10*time.Second).(*statsCachingStore)
+	cached.now = func() time.Time { return clock }
+
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com", Title: "A", Source: "manual"}))
+	stats, err := cached.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalEvents)
+
+	// Writes within the TTL are not reflected.
+	require.NoError(t, cached.AddEvent(ctx, &Event{URL: "https://b.com", Title: "B", Source: "manual"}))
+	clock = clock.Add(5 * time.Second)
+	stats, err = cached.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalEvents)
+
+	// Mutating a returned value does not corrupt the cache.
+	stats.TotalEvents = 99
+	stats, err = cached.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalEvents)
+
+	clock = clock.Add(10 * time.Second)
+	stats, err = cached.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalEvents)
+}
@@ -5,25 +5,52 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/runnerr0/chronicle/internal/encrypt"
+	"github.com/runnerr0/chronicle/internal/vectorstore"
 )
 
 // Store defines the interface for Chronicle data operations.
 type Store interface {
 	AddEvent(ctx context.Context, event *Event) error
 	AddEventWithContent(ctx context.Context, event *Event, body string) error
+	AddEvents(ctx context.Context, captures []Capture) []error
 	GetEvent(ctx context.Context, id string) (*Event, error)
 	SearchEvents(ctx context.Context, query SearchQuery) ([]Event, error)
+	CountEvents(ctx context.Context, query SearchQuery) (int64, error)
 	DeleteEvent(ctx context.Context, id string) error
 	GetContent(ctx context.Context, eventID string) (*Content, error)
+	GetContentPreview(ctx context.Context, eventID string, n int) (*Content, error)
+	GetContents(ctx context.Context, eventIDs []string) (map[string]*Content, error)
+	GetContentPreviews(ctx context.Context, eventIDs []string, n int) (map[string]*Content, error)
+	GetPageMeta(ctx context.Context, eventID string) (*PageMeta, error)
 	CountExpired(ctx context.Context, olderThan time.Time) (int64, error)
 	PruneExpired(ctx context.Context, olderThan time.Time) (int64, error)
 	PurgeAll(ctx context.Context) error
+	CountMatching(ctx context.Context, filter PurgeFilter) (int64, error)
+	PurgeMatching(ctx context.Context, filter PurgeFilter) (int64, error)
+	RecordPrune(ctx context.Context, rec PruneRecord) error
+	LastPrune(ctx context.Context) (*PruneRecord, error)
 	GetStats(ctx context.Context) (*Stats, error)
+	EventsPerDay(ctx context.Context, since, until time.Time) ([]DayCount, error)
+	ActivityByTime(ctx context.Context, since, until time.Time) (*TimeActivity, error)
+	ContentTotals(ctx context.Context, since, until time.Time) (*ContentTotals, error)
+	ListDomains(ctx context.Context, prefix string) ([]DomainCount, error)
+	ListSources(ctx context.Context) ([]ValueCount, error)
+	ListBrowsers(ctx context.Context) ([]ValueCount, error)
+	ListLanguages(ctx context.Context) ([]ValueCount, error)
 	Close() error
 }
 
@@ -34,11 +61,35 @@ type SQLiteStore struct {
 	// Prepared statements
 	insertEvent   *sql.Stmt
 	insertContent *sql.Stmt
+	insertFTS     *sql.Stmt
 	getEvent      *sql.Stmt
 	deleteEvent   *sql.Stmt
 	getContent    *sql.Stmt
+	getPreview    *sql.Stmt
+
+	// Single writer: captures are queued to a goroutine that batches them
+	// into transactions, and writeMu serializes every other write with it.
+	writes   chan *writeReq
+	quit     chan struct{}
+	stopped  chan struct{}
+	writeMu  sync.Mutex
+	stopOnce sync.Once
+
+	// Body size cap applied to captured content
+	bodyLimit BodyLimit
+
+	// Repeat captures this close together count as visits (0 disables)
+	dedupeWindow time.Duration
+
+	// Encrypts stored bodies when set (see SetCipher)
+	cipher *encrypt.Cipher
+
+	// Holds embedding vectors instead of the embeddings table when set
+	// (see SetVectorStore)
+	vectors vectorstore.Store
 
-	// Cached exclusion rules (loaded once at init)
+	// Cached exclusion rules, loaded at init and reloaded when they change
+	exclusionsMu     sync.RWMutex
 	domainExclusions []string
 	regexExclusions  []*regexp.Regexp
 }
@@ -47,18 +98,20 @@ type SQLiteStore struct {
 func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
 	s := &SQLiteStore{db: db}
 
-	if err := s.prepareStatements(); err != nil {
-		return nil, fmt.Errorf("prepare statements: %w", err)
-	}
-
+	// The FTS table must exist before its insert can be prepared.
 	if err := s.initFTS(); err != nil {
 		return nil, fmt.Errorf("init FTS: %w", err)
 	}
 
+	if err := s.prepareStatements(); err != nil {
+		return nil, fmt.Errorf("prepare statements: %w", err)
+	}
+
 	if err := s.loadExclusions(); err != nil {
 		return nil, fmt.Errorf("load exclusions: %w", err)
 	}
 
+	s.startWriter()
 	return s, nil
 }
 
@@ -66,23 +119,30 @@ func (s *SQLiteStore) prepareStatements() error {
 	var err error
 
 	s.insertEvent, err = s.db.Prepare(`
-		INSERT INTO events (id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO events (id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
 	}
 
 	s.insertContent, err = s.db.Prepare(`
-		INSERT INTO content (event_id, body, byte_size)
-		VALUES (?, ?, ?)
+		INSERT INTO content (event_id, body, byte_size, truncated, original_size, word_count, char_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+
+	s.insertFTS, err = s.db.Prepare(`
+		INSERT INTO events_fts (event_id, title, url, body) VALUES (?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
 	}
 
 	s.getEvent, err = s.db.Prepare(`
-		SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash
+		SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
 		FROM events WHERE id = ?
 	`)
 	if err != nil {
@@ -95,7 +155,16 @@ func (s *SQLiteStore) prepareStatements() error {
 	}
 
 	s.getContent, err = s.db.Prepare(`
-		SELECT event_id, body FROM content WHERE event_id = ?
+		SELECT event_id, body, truncated, original_size FROM content WHERE event_id = ?
+	`)
+	if err != nil {
+		return err
+	}
+
+	// substr on a BLOB counts bytes, so only the prefix leaves SQLite.
+	s.getPreview, err = s.db.Prepare(`
+		SELECT event_id, CAST(substr(CAST(body AS BLOB), 1, ?) AS TEXT), truncated, original_size
+		FROM content WHERE event_id = ?
 	`)
 	if err != nil {
 		return err
@@ -104,20 +173,36 @@ func (s *SQLiteStore) prepareStatements() error {
 	return nil
 }
 
-// initFTS creates the FTS5 virtual table for full-text search if it doesn't exist.
+// initFTS creates the FTS5 virtual table for full-text search, and the
+// vocabulary table over it used for query suggestions, if they don't exist.
 func (s *SQLiteStore) initFTS() error {
-	_, err := s.db.Exec(`
-		CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(
-			event_id UNINDEXED,
-			title,
-			url,
-			tokenize='unicode61'
-		)
-	`)
+	var exists int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'events_fts'`).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		_, err := s.db.Exec(`
+			CREATE VIRTUAL TABLE events_fts USING fts5(
+				event_id UNINDEXED,
+				title,
+				url,
+				body,
+				tokenize='unicode61'
+			)
+		`)
+		if err != nil {
+			return err
+		}
+		if _, err := s.db.Exec(`INSERT INTO events_fts(events_fts, rank) VALUES('rank', '` + ftsRank + `')`); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS events_fts_vocab USING fts5vocab(events_fts, 'row')`)
 	return err
 }
 
-// loadExclusions loads domain and regex exclusion rules from the database.
+// loadExclusions loads domain and regex exclusion rules from the database,
+// replacing the cached ones.
 func (s *SQLiteStore) loadExclusions() error {
 	rows, err := s.db.Query("SELECT rule_type, rule_value FROM exclusions")
 	if err != nil {
@@ -125,41 +210,95 @@ func (s *SQLiteStore) loadExclusions() error {
 	}
 	defer rows.Close()
 
+	var domains []string
+	var regexes []*regexp.Regexp
 	for rows.Next() {
 		var ruleType, ruleValue string
 		if err := rows.Scan(&ruleType, &ruleValue); err != nil {
 			return err
 		}
 		switch ruleType {
-		case "domain":
-			s.domainExclusions = append(s.domainExclusions, ruleValue)
-		case "regex":
+		case ExclusionDomain:
+			domains = append(domains, ruleValue)
+		case ExclusionRegex:
 			re, err := regexp.Compile(ruleValue)
 			if err != nil {
 				continue // skip invalid regex
 			}
-			s.regexExclusions = append(s.regexExclusions, re)
+			regexes = append(regexes, re)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-	return rows.Err()
+	s.exclusionsMu.Lock()
+	s.domainExclusions, s.regexExclusions = domains, regexes
+	s.exclusionsMu.Unlock()
+	return nil
 }
 
-// IsExcluded checks if a domain is blocked by exclusion rules.
+// ErrBodyTooLarge is returned when a body exceeds a rejecting BodyLimit.
+var ErrBodyTooLarge = errors.New("body exceeds size limit")
+
+// SetBodyLimit sets the size cap for bodies stored by later captures.
+func (s *SQLiteStore) SetBodyLimit(limit BodyLimit) {
+	s.bodyLimit = limit
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a rune.
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// IsExcluded checks if a domain is blocked by exclusion rules, tracing
+// matches at debug level.
 func (s *SQLiteStore) IsExcluded(domain string) bool {
+	rule, ok := s.ExclusionRule(domain)
+	if ok {
+		slog.Debug("domain excluded", "domain", domain, "rule", rule)
+	}
+	return ok
+}
+
+// ExclusionRule returns the first exclusion rule matching domain: a
+// domain rule equal to it, else a regex rule matching it.
+func (s *SQLiteStore) ExclusionRule(domain string) (string, bool) {
+	s.exclusionsMu.RLock()
+	defer s.exclusionsMu.RUnlock()
 	for _, d := range s.domainExclusions {
 		if d == domain {
-			return true
+			return d, true
 		}
 	}
 	for _, re := range s.regexExclusions {
 		if re.MatchString(domain) {
-			return true
+			return re.String(), true
 		}
 	}
-	return false
+	return "", false
+}
+
+// traceQuery logs how long a statement took at debug level, with its SQL
+// collapsed onto one line.
+func traceQuery(ctx context.Context, query string, start time.Time, attrs ...any) {
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs = append([]any{"sql", strings.Join(strings.Fields(query), " "), "elapsed", time.Since(start)}, attrs...)
+	slog.DebugContext(ctx, "query", attrs...)
 }
 
+// newEventID is the ID source for inserts; tests replace it to force
+// collisions.
+var newEventID = generateID
+
 // generateID creates a Chronicle event ID: CHR- + 8 random hex chars.
 func generateID() (string, error) {
 	b := make([]byte, 4)
@@ -169,17 +308,66 @@ func generateID() (string, error) {
 	return "CHR-" + hex.EncodeToString(b), nil
 }
 
-// ftsQuery converts a user search string into a valid FTS5 query.
-// Each word becomes a quoted prefix token joined with OR.
-func ftsQuery(input string) string {
-	words := strings.Fields(input)
-	if len(words) == 0 {
+// queryTerms splits q.Query into words and adds every word that shares a
+// q.Synonyms group with one of them, compared case-insensitively. Each term
+// appears once. With q.Lang set, that language's stopwords are dropped
+// unless the query is nothing but stopwords.
+func queryTerms(q SearchQuery) []string {
+	words := strings.Fields(q.Query)
+	if q.Lang != "" {
+		var kept []string
+		for _, w := range words {
+			if !IsStopword(q.Lang, w) {
+				kept = append(kept, w)
+			}
+		}
+		if len(kept) > 0 {
+			words = kept
+		}
+	}
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	add := func(t string) {
+		if key := strings.ToLower(t); !seen[key] {
+			seen[key] = true
+			terms = append(terms, t)
+		}
+	}
+	for _, w := range words {
+		add(w)
+	}
+	for _, w := range words {
+		for _, group := range q.Synonyms {
+			if !containsFold(group, w) {
+				continue
+			}
+			for _, syn := range group {
+				add(syn)
+			}
+		}
+	}
+	return terms
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ftsQuery converts search terms into a valid FTS5 query. Each term becomes
+// a quoted prefix token (a phrase, for multi-word synonyms) joined with OR.
+func ftsQuery(terms []string) string {
+	if len(terms) == 0 {
 		return ""
 	}
 	var parts []string
-	for _, w := range words {
+	for _, w := range terms {
 		// Quote each term, add prefix wildcard for partial matching
-		parts = append(parts, `"`+w+`"*`)
+		parts = append(parts, `"`+strings.ReplaceAll(w, `"`, `""`)+`"*`)
 	}
 	return strings.Join(parts, " OR ")
 }
@@ -210,101 +398,202 @@ func extractDomain(rawURL string) string {
 	return u.Hostname()
 }
 
-// AddEvent inserts a new event into the database. The event's ID and Domain
-// fields are populated automatically. If the domain is excluded, the event
-// is silently skipped (ID remains empty, no error).
-func (s *SQLiteStore) AddEvent(ctx context.Context, event *Event) error {
-	event.Domain = extractDomain(event.URL)
+// maxIDAttempts bounds retries when a generated ID collides with an
+// existing event. IDs have 32 random bits, so collisions become likely
+// once the table holds millions of rows.
+const maxIDAttempts = 5
 
-	if s.IsExcluded(event.Domain) {
-		return nil // silently skip
-	}
+// insertEventRow assigns event a fresh ID and inserts it with stmt (the
+// prepared events insert), retrying with a new ID on collision.
+func insertEventRow(ctx context.Context, stmt *sql.Stmt, event *Event) error {
+	tsFormatted := event.Timestamp.UTC().Format(time.RFC3339)
+	for attempt := 1; ; attempt++ {
+		id, err := newEventID()
+		if err != nil {
+			return fmt.Errorf("generate ID: %w", err)
+		}
+		event.ID = id
 
-	id, err := generateID()
-	if err != nil {
-		return fmt.Errorf("generate ID: %w", err)
+		_, err = stmt.ExecContext(ctx,
+			event.ID, tsFormatted, event.URL, event.Title, event.Domain,
+			event.Browser, event.Source, event.HasBody, event.HasEmbed, event.ContentHash, event.Lang, event.CanonicalURL, int64(event.ReadTime/time.Second),
+			event.RawTitle,
+		)
+		if err == nil {
+			return nil
+		}
+		var sqliteErr sqlite3.Error
+		if attempt < maxIDAttempts && errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			continue
+		}
+		event.ID = ""
+		return fmt.Errorf("insert event: %w", err)
 	}
-	event.ID = id
+}
 
-	if event.Timestamp.IsZero() {
-		event.Timestamp = time.Now()
+// AddEvent inserts a new event into the database. The event's ID and Domain
+// fields are populated automatically. The event row and its FTS entry are
+// written in one transaction, batched with any concurrent captures by the
+// store's single writer. If the domain is excluded, the event is silently
+// skipped (ID remains empty, no error).
+func (s *SQLiteStore) AddEvent(ctx context.Context, event *Event) error {
+	req, err := s.prepareCapture(ctx, event, "", false)
+	if req == nil {
+		return err
 	}
+	return s.enqueue(req)
+}
 
-	tsFormatted := event.Timestamp.UTC().Format(time.RFC3339)
-	_, err = s.insertEvent.ExecContext(ctx,
-		event.ID, tsFormatted, event.URL, event.Title, event.Domain,
-		event.Browser, event.Source, event.HasBody, event.HasEmbed, event.ContentHash,
-	)
-	if err != nil {
-		return fmt.Errorf("insert event: %w", err)
+// AddEventWithContent inserts an event and its body content in a single transaction.
+func (s *SQLiteStore) AddEventWithContent(ctx context.Context, event *Event, body string) error {
+	req, err := s.prepareCapture(ctx, event, body, true)
+	if req == nil {
+		return err
 	}
+	return s.enqueue(req)
+}
 
-	// Index in FTS
-	_, err = s.db.ExecContext(ctx,
-		"INSERT INTO events_fts (event_id, title, url) VALUES (?, ?, ?)",
-		event.ID, event.Title, event.URL,
-	)
-	if err != nil {
-		return fmt.Errorf("insert FTS: %w", err)
+// Capture is one event for AddEvents, stored with Body when WithBody is
+// set.
+type Capture struct {
+	Event    *Event
+	Body     string
+	WithBody bool
+}
+
+// AddEvents stores captures as AddEvent and AddEventWithContent would, but
+// commits them together rather than waiting for concurrent captures to
+// share a transaction. It returns each capture's error, in order; a
+// failing capture does not keep the others out.
+func (s *SQLiteStore) AddEvents(ctx context.Context, captures []Capture) []error {
+	errs := make([]error, len(captures))
+	var batch []*writeReq
+	var index []int
+	for i, c := range captures {
+		req, err := s.prepareCapture(ctx, c.Event, c.Body, c.WithBody)
+		if req == nil {
+			errs[i] = err
+			continue
+		}
+		req.done = make(chan error, 1)
+		batch = append(batch, req)
+		index = append(index, i)
 	}
 
-	return nil
+	for len(batch) > 0 {
+		n := min(len(batch), maxWriteBatch)
+		select {
+		case <-s.quit:
+			for j := range batch {
+				errs[index[j]] = ErrStoreClosed
+			}
+			return errs
+		default:
+		}
+		s.writeBatch(batch[:n])
+		for j, req := range batch[:n] {
+			errs[index[j]] = <-req.done
+		}
+		batch, index = batch[n:], index[n:]
+	}
+	return errs
 }
 
-// AddEventWithContent inserts an event and its body content in a single transaction.
-func (s *SQLiteStore) AddEventWithContent(ctx context.Context, event *Event, body string) error {
+// prepareCapture fills in event's derived fields and returns the write
+// request for it, or nil (with any error) if it must not be stored.
+func (s *SQLiteStore) prepareCapture(ctx context.Context, event *Event, body string, withBody bool) (*writeReq, error) {
 	event.Domain = extractDomain(event.URL)
+	event.CanonicalURL = cleanCanonicalURL(event.URL, event.CanonicalURL)
+	normalizeTitle(event)
 
 	if s.IsExcluded(event.Domain) {
-		return nil
+		return nil, nil // silently skip
 	}
 
-	id, err := generateID()
-	if err != nil {
-		return fmt.Errorf("generate ID: %w", err)
+	if withBody {
+		if limit := s.bodyLimit; limit.Reject && limit.MaxBytes > 0 && len(body) > limit.MaxBytes {
+			return nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrBodyTooLarge, len(body), limit.MaxBytes)
+		}
+
+		event.HasBody = true
+		if event.Lang == "" {
+			event.Lang = DetectLanguage(body)
+		}
+		event.ReadTime = ReadingTime(countWords(body))
 	}
-	event.ID = id
-	event.HasBody = true
 
 	if event.Timestamp.IsZero() {
 		event.Timestamp = time.Now()
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+	return &writeReq{ctx: ctx, event: event, body: body, withBody: withBody}, nil
+}
+
+// insertCapture writes one capture (event, optional content, FTS entry)
+// inside tx.
+func (s *SQLiteStore) insertCapture(tx *sql.Tx, req *writeReq) error {
+	ctx, event := req.ctx, req.event
+
+	if s.dedupeWindow > 0 {
+		folded, err := s.foldDuplicate(ctx, tx, event)
+		if err != nil || folded {
+			return err
+		}
 	}
-	defer tx.Rollback() //nolint:errcheck
 
-	tsFormatted := event.Timestamp.UTC().Format(time.RFC3339)
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO events (id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		event.ID, tsFormatted, event.URL, event.Title, event.Domain,
-		event.Browser, event.Source, true, event.HasEmbed, event.ContentHash,
-	)
-	if err != nil {
-		return fmt.Errorf("insert event: %w", err)
+	if err := insertEventRow(ctx, tx.StmtContext(ctx, s.insertEvent), event); err != nil {
+		return err
 	}
+	event.Visits = 1
 
-	_, err = tx.ExecContext(ctx,
-		"INSERT INTO content (event_id, body, byte_size) VALUES (?, ?, ?)",
-		event.ID, body, len(body),
-	)
-	if err != nil {
-		return fmt.Errorf("insert content: %w", err)
+	var indexed string // the stored body, which the full-text index covers
+	if req.withBody {
+		body, truncated := req.body, false
+		if max := s.bodyLimit.MaxBytes; max > 0 && len(body) > max {
+			body, truncated = truncateUTF8(body, max), true
+		}
+		stored, err := s.sealBody(body)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.StmtContext(ctx, s.insertContent).ExecContext(ctx,
+			event.ID, stored, len(body), truncated, len(req.body), countWords(body), utf8.RuneCountInString(body),
+		); err != nil {
+			return fmt.Errorf("insert content: %w", err)
+		}
+		// An encrypted body leaves nothing derived from it in plaintext:
+		// no index entry, links or keywords.
+		if s.cipher == nil {
+			indexed = body
+			// Links come from the whole body, even when only part is stored.
+			if err := insertLinks(tx, event.ID, ExtractLinks(event.URL, req.body)); err != nil {
+				return err
+			}
+			event.Keywords = ExtractKeywords(req.body, event.Lang, keywordsPerEvent)
+			if err := insertKeywords(tx, event.ID, event.Keywords); err != nil {
+				return err
+			}
+		}
 	}
 
-	// FTS index with body included
-	_, err = tx.ExecContext(ctx,
-		"INSERT INTO events_fts (event_id, title, url) VALUES (?, ?, ?)",
-		event.ID, event.Title, event.URL,
-	)
-	if err != nil {
-		return fmt.Errorf("insert FTS: %w", err)
+	if m := event.Meta; m != nil && !m.IsZero() {
+		var published interface{}
+		if !m.Published.IsZero() {
+			published = m.Published.UTC().Format(time.RFC3339)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO page_meta (event_id, og_title, og_description, og_image, author, published) VALUES (?, ?, ?, ?, ?, ?)`,
+			event.ID, m.OGTitle, m.OGDescription, m.OGImage, m.Author, published,
+		); err != nil {
+			return fmt.Errorf("insert page metadata: %w", err)
+		}
 	}
 
-	return tx.Commit()
+	// Index in FTS
+	if _, err := tx.StmtContext(ctx, s.insertFTS).ExecContext(ctx, event.ID, event.Title, event.URL, indexed); err != nil {
+		return fmt.Errorf("insert FTS: %w", err)
+	}
+	return nil
 }
 
 // GetEvent retrieves a single event by ID.
@@ -312,10 +601,11 @@ func (s *SQLiteStore) GetEvent(ctx context.Context, id string) (*Event, error) {
 	var e Event
 	var contentHash sql.NullString
 	var tsStr string
+	var readSeconds int64
 
 	err := s.getEvent.QueryRowContext(ctx, id).Scan(
 		&e.ID, &tsStr, &e.URL, &e.Title, &e.Domain,
-		&e.Browser, &e.Source, &e.HasBody, &e.HasEmbed, &contentHash,
+		&e.Browser, &e.Source, &e.HasBody, &e.HasEmbed, &contentHash, &e.Lang, &e.CanonicalURL, &readSeconds, &e.RawTitle, &e.Visits,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -325,6 +615,7 @@ func (s *SQLiteStore) GetEvent(ctx context.Context, id string) (*Event, error) {
 	}
 
 	e.Timestamp, _ = parseTimestamp(tsStr)
+	e.ReadTime = time.Duration(readSeconds) * time.Second
 
 	if contentHash.Valid {
 		e.ContentHash = contentHash.String
@@ -335,123 +626,264 @@ func (s *SQLiteStore) GetEvent(ctx context.Context, id string) (*Event, error) {
 
 // SearchEvents queries events with optional filters.
 func (s *SQLiteStore) SearchEvents(ctx context.Context, q SearchQuery) ([]Event, error) {
+	query, args, err := searchSQL(q)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanEvents(ctx, query, args...)
+}
+
+// CountEvents returns how many events match q, ignoring its Limit, Offset
+// and Sort.
+func (s *SQLiteStore) CountEvents(ctx context.Context, q SearchQuery) (int64, error) {
+	query, args, err := matchSQL(q)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	var count int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+query+")", args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count events: %w", err)
+	}
+	traceQuery(ctx, query, start, "rows", count)
+	return count, nil
+}
+
+// searchSQL validates q and builds the SQL and arguments that run it.
+func searchSQL(q SearchQuery) (string, []interface{}, error) {
 	if q.Limit <= 0 {
 		q.Limit = 50
 	}
+	query, args, err := matchSQL(q)
+	if err != nil {
+		return "", nil, err
+	}
+	return query + " LIMIT ? OFFSET ?", append(args, q.Limit, q.Offset), nil
+}
+
+// matchSQL validates q and builds the SQL and arguments that select every
+// matching event in order, without paging.
+func matchSQL(q SearchQuery) (string, []interface{}, error) {
+	switch q.Sort {
+	case "", SortRelevance, SortNewest, SortOldest:
+	default:
+		return "", nil, fmt.Errorf("invalid sort %q", q.Sort)
+	}
+	columns, err := searchColumns(q.Fields)
+	if err != nil {
+		return "", nil, err
+	}
 
 	// If there's a text query, use FTS
 	if q.Query != "" {
-		return s.searchFTS(ctx, q)
+		query, args := ftsSQL(q, columns)
+		return query, args, nil
 	}
 
-	return s.searchFiltered(ctx, q)
+	query, args := filteredSQL(q)
+	return query, args, nil
 }
 
-// searchFTS uses the FTS5 index for keyword search, then joins with events table for filtering.
-func (s *SQLiteStore) searchFTS(ctx context.Context, q SearchQuery) ([]Event, error) {
-	var clauses []string
-	var args []interface{}
+// searchColumns validates fields, the SearchQuery.Fields to match a query
+// in, and returns the FTS columns they name.
+func searchColumns(fields []string) ([]string, error) {
+	var columns []string
+	for _, f := range fields {
+		switch f {
+		case FieldTitle, FieldURL, FieldBody:
+			columns = append(columns, f)
+		default:
+			return nil, fmt.Errorf("invalid search field %q (want title, url, or body)", f)
+		}
+	}
+	return columns, nil
+}
+
+// SearchPlan describes how a search would run, for diagnosing slow queries.
+type SearchPlan struct {
+	SQL  string
+	Args []interface{}
+	Plan []string // EXPLAIN QUERY PLAN detail lines, indented by depth
+}
 
+// ExplainSearch returns the SQL, bound arguments, and query plan for q
+// without running the search.
+func (s *SQLiteStore) ExplainSearch(ctx context.Context, q SearchQuery) (*SearchPlan, error) {
+	query, args, err := searchSQL(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("explain query plan: %w", err)
+	}
+	defer rows.Close()
+
+	plan := &SearchPlan{SQL: query, Args: args}
+	depth := map[int]int{}
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			return nil, err
+		}
+		depth[id] = depth[parent] + 1
+		plan.Plan = append(plan.Plan, strings.Repeat("  ", depth[id]-1)+detail)
+	}
+	return plan, rows.Err()
+}
+
+// ftsSQL builds the SQL and arguments for a keyword search: the FTS5 index
+// finds matches, then the join with events applies the filters. columns
+// limits matching to those FTS columns; empty means all of them.
+func ftsSQL(q SearchQuery, columns []string) (string, []interface{}) {
 	baseQuery := `
 		SELECT e.id, e.ts, e.url, e.title, e.domain, e.browser, e.source,
-		       e.has_body, e.has_embedding, e.content_hash
+		       e.has_body, e.has_embedding, e.content_hash, e.lang, e.canonical_url, e.read_seconds, e.raw_title, e.visit_count
 		FROM events_fts f
 		JOIN events e ON e.id = f.event_id
 	`
 
 	// Quote each word for FTS5 prefix matching
-	clauses = append(clauses, "events_fts MATCH ?")
-	args = append(args, ftsQuery(q.Query))
+	clauses := []string{"events_fts MATCH ?"}
+	args := []interface{}{scopedFTSQuery(queryTerms(q), columns)}
+
+	filters, filterArgs := filterClauses(q, "e.")
+	clauses = append(clauses, filters...)
+	args = append(args, filterArgs...)
 
-	if q.Domain != "" {
-		clauses = append(clauses, "e.domain = ?")
-		args = append(args, q.Domain)
+	where := " WHERE " + strings.Join(clauses, " AND ")
+
+	order := "rank"
+	switch q.Sort {
+	case SortNewest:
+		order = "e.ts DESC"
+	case SortOldest:
+		order = "e.ts ASC"
+	}
+
+	return baseQuery + where + " ORDER BY " + order, args
+}
+
+// likeEscaper escapes LIKE wildcards for use with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// scopedFTSQuery is ftsQuery limited to columns, when any are given.
+func scopedFTSQuery(terms []string, columns []string) string {
+	match := ftsQuery(terms)
+	if len(columns) == 0 {
+		return match
+	}
+	return "{" + strings.Join(columns, " ") + "} : (" + match + ")"
+}
+
+// filterClauses returns the WHERE clauses and arguments for q's filters
+// other than the text query, with columns qualified by prefix.
+func filterClauses(q SearchQuery, prefix string) ([]string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if clause, inArgs := inClause(prefix+"domain", q.Domain, q.Domains); clause != "" {
+		clauses = append(clauses, clause)
+		args = append(args, inArgs...)
 	}
 	if q.Source != "" {
-		clauses = append(clauses, "e.source = ?")
+		clauses = append(clauses, prefix+"source = ?")
 		args = append(args, q.Source)
 	}
 	if !q.Since.IsZero() {
-		clauses = append(clauses, "e.ts >= ?")
+		clauses = append(clauses, prefix+"ts >= ?")
 		args = append(args, q.Since.UTC().Format(time.RFC3339))
 	}
 	if !q.Until.IsZero() {
-		clauses = append(clauses, "e.ts <= ?")
+		clauses = append(clauses, prefix+"ts <= ?")
 		args = append(args, q.Until.UTC().Format(time.RFC3339))
 	}
-	if q.Browser != "" {
-		clauses = append(clauses, "e.browser = ?")
-		args = append(args, q.Browser)
+	if clause, inArgs := inClause(prefix+"browser", q.Browser, q.Browsers); clause != "" {
+		clauses = append(clauses, clause)
+		args = append(args, inArgs...)
 	}
-	if q.HasBody {
-		clauses = append(clauses, "e.has_body = 1")
+	if q.Lang != "" {
+		clauses = append(clauses, prefix+"lang = ?")
+		args = append(args, q.Lang)
 	}
-	if q.HasEmbedding {
-		clauses = append(clauses, "e.has_embedding = 1")
+	if q.MinReadTime > 0 {
+		clauses = append(clauses, prefix+"read_seconds >= ?")
+		args = append(args, int64(q.MinReadTime/time.Second))
 	}
-
-	where := ""
-	if len(clauses) > 0 {
-		where = " WHERE " + strings.Join(clauses, " AND ")
+	if q.Keyword != "" {
+		// A keyword matches a stored keyphrase or any whole word of one.
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM keywords k WHERE k.event_id = `+prefix+`id AND (k.keyword = ? OR ' ' || k.keyword || ' ' LIKE ? ESCAPE '\'))`)
+		args = append(args, q.Keyword, "% "+likeEscaper.Replace(q.Keyword)+" %")
 	}
-
-	fullQuery := baseQuery + where + " ORDER BY rank LIMIT ? OFFSET ?"
-	args = append(args, q.Limit, q.Offset)
-
-	return s.scanEvents(ctx, fullQuery, args...)
+	if q.HasBody != nil {
+		clauses = append(clauses, prefix+"has_body = "+sqlBool(*q.HasBody))
+	}
+	if q.HasEmbedding != nil {
+		clauses = append(clauses, prefix+"has_embedding = "+sqlBool(*q.HasEmbedding))
+	}
+	return clauses, args
 }
 
-// searchFiltered queries events using standard SQL filters (no FTS).
-func (s *SQLiteStore) searchFiltered(ctx context.Context, q SearchQuery) ([]Event, error) {
-	var clauses []string
+// inClause returns the clause matching column against value and values
+// (empty ones ignored), and its arguments; "" when there are none. A
+// single value is compared with = so the planner can use its indexes.
+func inClause(column, value string, values []string) (string, []interface{}) {
 	var args []interface{}
+	seen := map[string]bool{}
+	for _, v := range append([]string{value}, values...) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			args = append(args, v)
+		}
+	}
+	switch len(args) {
+	case 0:
+		return "", nil
+	case 1:
+		return column + " = ?", args
+	default:
+		return column + " IN (?" + strings.Repeat(", ?", len(args)-1) + ")", args
+	}
+}
+
+// sqlBool is b as an SQLite boolean literal. Flag filters are inlined
+// rather than bound so the planner can match them to indexes.
+func sqlBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
 
+// filteredSQL builds the SQL and arguments for a search using standard SQL
+// filters (no FTS).
+func filteredSQL(q SearchQuery) (string, []interface{}) {
 	baseQuery := `
 		SELECT id, ts, url, title, domain, browser, source,
-		       has_body, has_embedding, content_hash
+		       has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
 		FROM events
 	`
 
-	if q.Domain != "" {
-		clauses = append(clauses, "domain = ?")
-		args = append(args, q.Domain)
-	}
-	if q.Source != "" {
-		clauses = append(clauses, "source = ?")
-		args = append(args, q.Source)
-	}
-	if !q.Since.IsZero() {
-		clauses = append(clauses, "ts >= ?")
-		args = append(args, q.Since.UTC().Format(time.RFC3339))
-	}
-	if !q.Until.IsZero() {
-		clauses = append(clauses, "ts <= ?")
-		args = append(args, q.Until.UTC().Format(time.RFC3339))
-	}
-	if q.Browser != "" {
-		clauses = append(clauses, "browser = ?")
-		args = append(args, q.Browser)
-	}
-	if q.HasBody {
-		clauses = append(clauses, "has_body = 1")
-	}
-	if q.HasEmbedding {
-		clauses = append(clauses, "has_embedding = 1")
-	}
+	clauses, args := filterClauses(q, "")
 
 	where := ""
 	if len(clauses) > 0 {
 		where = " WHERE " + strings.Join(clauses, " AND ")
 	}
 
-	fullQuery := baseQuery + where + " ORDER BY ts DESC LIMIT ? OFFSET ?"
-	args = append(args, q.Limit, q.Offset)
+	order := "ts DESC"
+	if q.Sort == SortOldest {
+		order = "ts ASC"
+	}
 
-	return s.scanEvents(ctx, fullQuery, args...)
+	return baseQuery + where + " ORDER BY " + order, args
 }
 
 // scanEvents executes a query and scans results into Event slices.
 func (s *SQLiteStore) scanEvents(ctx context.Context, query string, args ...interface{}) ([]Event, error) {
+	start := time.Now()
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query events: %w", err)
@@ -463,13 +895,15 @@ func (s *SQLiteStore) scanEvents(ctx context.Context, query string, args ...inte
 		var e Event
 		var contentHash sql.NullString
 		var tsStr string
+		var readSeconds int64
 		if err := rows.Scan(
 			&e.ID, &tsStr, &e.URL, &e.Title, &e.Domain,
-			&e.Browser, &e.Source, &e.HasBody, &e.HasEmbed, &contentHash,
+			&e.Browser, &e.Source, &e.HasBody, &e.HasEmbed, &contentHash, &e.Lang, &e.CanonicalURL, &readSeconds, &e.RawTitle, &e.Visits,
 		); err != nil {
 			return nil, fmt.Errorf("scan event: %w", err)
 		}
 		e.Timestamp, _ = parseTimestamp(tsStr)
+		e.ReadTime = time.Duration(readSeconds) * time.Second
 		if contentHash.Valid {
 			e.ContentHash = contentHash.String
 		}
@@ -480,6 +914,8 @@ func (s *SQLiteStore) scanEvents(ctx context.Context, query string, args ...inte
 		return nil, err
 	}
 
+	traceQuery(ctx, query, start, "rows", len(events))
+
 	// Return empty slice rather than nil
 	if events == nil {
 		events = []Event{}
@@ -488,8 +924,12 @@ func (s *SQLiteStore) scanEvents(ctx context.Context, query string, args ...inte
 	return events, nil
 }
 
-// DeleteEvent removes an event by ID. Content is cascade-deleted by the schema.
+// DeleteEvent removes an event by ID. Content is cascade-deleted by the
+// schema, and a vector store drops the event's vectors.
 func (s *SQLiteStore) DeleteEvent(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	// Also clean up FTS
 	_, err := s.db.ExecContext(ctx,
 		"DELETE FROM events_fts WHERE event_id = ?", id,
@@ -511,22 +951,162 @@ func (s *SQLiteStore) DeleteEvent(ctx context.Context, id string) error {
 		return fmt.Errorf("event %s not found", id)
 	}
 
-	return nil
+	return s.retainVectors(ctx)
 }
 
 // GetContent retrieves the stored body for an event.
 func (s *SQLiteStore) GetContent(ctx context.Context, eventID string) (*Content, error) {
 	var c Content
-	err := s.getContent.QueryRowContext(ctx, eventID).Scan(&c.EventID, &c.Body)
+	err := s.getContent.QueryRowContext(ctx, eventID).Scan(&c.EventID, &c.Body, &c.Truncated, &c.OriginalSize)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("content for event %s not found", eventID)
 		}
 		return nil, fmt.Errorf("get content: %w", err)
 	}
+	if c.Body, err = s.openBody(c.Body); err != nil {
+		return nil, fmt.Errorf("get content: %w", err)
+	}
 	return &c, nil
 }
 
+// GetPageMeta retrieves the page metadata stored with an event, or nil if
+// none was captured.
+func (s *SQLiteStore) GetPageMeta(ctx context.Context, eventID string) (*PageMeta, error) {
+	var m PageMeta
+	var published sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT og_title, og_description, og_image, author, published FROM page_meta WHERE event_id = ?`, eventID,
+	).Scan(&m.OGTitle, &m.OGDescription, &m.OGImage, &m.Author, &published)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get page metadata: %w", err)
+	}
+	if published.Valid {
+		m.Published, _ = parseTimestamp(published.String)
+	}
+	return &m, nil
+}
+
+// GetContentPreview retrieves at most the first n bytes of an event's body,
+// cut back to a UTF-8 boundary, for snippet display without loading the
+// whole body.
+func (s *SQLiteStore) GetContentPreview(ctx context.Context, eventID string, n int) (*Content, error) {
+	if n < 0 {
+		n = 0
+	}
+	if s.cipher != nil {
+		// Encrypted bodies can only be cut once decrypted.
+		c, err := s.GetContent(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		c.Body = truncateUTF8(c.Body, n)
+		return c, nil
+	}
+	var c Content
+	err := s.getPreview.QueryRowContext(ctx, n, eventID).Scan(&c.EventID, &c.Body, &c.Truncated, &c.OriginalSize)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("content for event %s not found", eventID)
+		}
+		return nil, fmt.Errorf("get content preview: %w", err)
+	}
+	c.Body = trimPartialRune(c.Body)
+	return &c, nil
+}
+
+// contentBatchSize caps how many event IDs one batch content query binds,
+// well under SQLite's host parameter limit.
+const contentBatchSize = 500
+
+// GetContents retrieves the bodies of many events in as few queries as
+// possible, keyed by event ID. Events without content are left out.
+func (s *SQLiteStore) GetContents(ctx context.Context, eventIDs []string) (map[string]*Content, error) {
+	return s.getContents(ctx, "body", eventIDs)
+}
+
+// GetContentPreviews is GetContentPreview for many events at once, keyed
+// by event ID. Events without content are left out.
+func (s *SQLiteStore) GetContentPreviews(ctx context.Context, eventIDs []string, n int) (map[string]*Content, error) {
+	if n < 0 {
+		n = 0
+	}
+	if s.cipher != nil {
+		out, err := s.getContents(ctx, "body", eventIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range out {
+			c.Body = truncateUTF8(c.Body, n)
+		}
+		return out, nil
+	}
+	out, err := s.getContents(ctx, fmt.Sprintf("CAST(substr(CAST(body AS BLOB), 1, %d) AS TEXT)", n), eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range out {
+		c.Body = trimPartialRune(c.Body)
+	}
+	return out, nil
+}
+
+// getContents selects bodyExpr for eventIDs, contentBatchSize at a time.
+func (s *SQLiteStore) getContents(ctx context.Context, bodyExpr string, eventIDs []string) (map[string]*Content, error) {
+	out := make(map[string]*Content, len(eventIDs))
+	for len(eventIDs) > 0 {
+		batch := eventIDs[:min(len(eventIDs), contentBatchSize)]
+		eventIDs = eventIDs[len(batch):]
+
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT event_id, " + bodyExpr + ", truncated, original_size FROM content WHERE event_id IN (?" +
+			strings.Repeat(", ?", len(batch)-1) + ")"
+		start := time.Now()
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("get contents: %w", err)
+		}
+		for rows.Next() {
+			var c Content
+			if err := rows.Scan(&c.EventID, &c.Body, &c.Truncated, &c.OriginalSize); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("get contents: %w", err)
+			}
+			if c.Body, err = s.openBody(c.Body); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("get contents: %w", err)
+			}
+			out[c.EventID] = &c
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("get contents: %w", err)
+		}
+		traceQuery(ctx, query, start, "ids", len(batch))
+	}
+	return out, nil
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence left at the end of s
+// by a byte-based cut.
+func trimPartialRune(s string) string {
+	for i := 0; i < utf8.UTFMax-1 && s != ""; i++ {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
 // CountExpired returns the number of events with timestamps before olderThan.
 func (s *SQLiteStore) CountExpired(ctx context.Context, olderThan time.Time) (int64, error) {
 	tsFormatted := olderThan.UTC().Format(time.RFC3339)
@@ -538,9 +1118,14 @@ func (s *SQLiteStore) CountExpired(ctx context.Context, olderThan time.Time) (in
 	return count, nil
 }
 
-// PruneExpired deletes events with timestamps before olderThan.
+// PruneExpired deletes events with timestamps before olderThan, and a
+// vector store drops their vectors.
 func (s *SQLiteStore) PruneExpired(ctx context.Context, olderThan time.Time) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	tsFormatted := olderThan.UTC().Format(time.RFC3339)
+	start := time.Now()
 
 	// Clean FTS entries first
 	_, err := s.db.ExecContext(ctx,
@@ -557,15 +1142,34 @@ func (s *SQLiteStore) PruneExpired(ctx context.Context, olderThan time.Time) (in
 		return 0, fmt.Errorf("prune events: %w", err)
 	}
 
-	return res.RowsAffected()
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM search_history WHERE ts < ?", tsFormatted); err != nil {
+		return 0, fmt.Errorf("prune search history: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "prune committed", "events", n, "elapsed", time.Since(start))
+	if n > 0 {
+		if err := s.retainVectors(ctx); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
 }
 
-// PurgeAll deletes all events and content.
+// PurgeAll deletes all events, content and search history, and a vector
+// store drops every vector.
 func (s *SQLiteStore) PurgeAll(ctx context.Context) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	stmts := []string{
 		"DROP TABLE IF EXISTS events_fts",
 		"DELETE FROM content",
 		"DELETE FROM events",
+		"DELETE FROM search_history",
 	}
 	for _, stmt := range stmts {
 		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
@@ -573,7 +1177,182 @@ func (s *SQLiteStore) PurgeAll(ctx context.Context) error {
 		}
 	}
 	// Recreate FTS table
-	return s.initFTS()
+	if err := s.initFTS(); err != nil {
+		return err
+	}
+	return s.retainVectors(ctx)
+}
+
+// Vacuum rebuilds the database file to return the space freed by deleted
+// events to the filesystem, and refreshes the query planner's statistics.
+// A vector store is compacted too, dropping the vectors of models events
+// were embedded by before.
+func (s *SQLiteStore) Vacuum(ctx context.Context) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if err := s.retainVectors(ctx); err != nil {
+		return err
+	}
+
+	for _, stmt := range []string{"VACUUM", "PRAGMA optimize"} {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("vacuum (%s): %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// purgeWhere builds the WHERE clause (without the keyword) selecting events
+// that match filter.
+func purgeWhere(filter PurgeFilter) (string, []interface{}, error) {
+	var clauses []string
+	var args []interface{}
+
+	if filter.Domain != "" {
+		clauses = append(clauses, "(domain = ? OR domain LIKE ?)")
+		args = append(args, filter.Domain, "%."+filter.Domain)
+	}
+	if filter.Source != "" {
+		clauses = append(clauses, "source = ?")
+		args = append(args, filter.Source)
+	}
+	if !filter.Before.IsZero() {
+		clauses = append(clauses, "ts < ?")
+		args = append(args, filter.Before.UTC().Format(time.RFC3339))
+	}
+
+	if len(clauses) == 0 {
+		return "", nil, fmt.Errorf("purge filter is empty")
+	}
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// CountMatching returns the number of events matching filter.
+func (s *SQLiteStore) CountMatching(ctx context.Context, filter PurgeFilter) (int64, error) {
+	where, args, err := purgeWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE "+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count matching: %w", err)
+	}
+	return count, nil
+}
+
+// PurgeMatching deletes events matching filter along with their content,
+// FTS entries, and embedding metadata, in a single transaction, then drops
+// their vectors from a vector store. The rest of the history is left
+// intact.
+func (s *SQLiteStore) PurgeMatching(ctx context.Context, filter PurgeFilter) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	where, args, err := purgeWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	matching := "SELECT id FROM events WHERE " + where
+	for _, table := range []struct{ name, col string }{
+		{"events_fts", "event_id"},
+		{"content", "event_id"},
+		{"embedding_metadata", "event_id"},
+		{"embeddings", "event_id"},
+	} {
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", table.name, table.col, matching)
+		if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+			return 0, fmt.Errorf("purge %s: %w", table.name, err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM events WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("purge events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "purge committed", "events", n, "where", where, "elapsed", time.Since(start))
+	if n > 0 {
+		if err := s.retainVectors(ctx); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Keys in the config table holding the last prune result.
+const (
+	configLastPruneAt    = "last_prune_at"
+	configLastPruneCount = "last_prune_count"
+)
+
+// RecordPrune stores rec as the most recent prune result.
+func (s *SQLiteStore) RecordPrune(ctx context.Context, rec PruneRecord) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	values := map[string]string{
+		configLastPruneAt:    rec.At.UTC().Format(time.RFC3339),
+		configLastPruneCount: strconv.FormatInt(rec.Count, 10),
+	}
+	for key, value := range values {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO config (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+			 ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+			key, value,
+		); err != nil {
+			return fmt.Errorf("record prune: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LastPrune returns the most recent prune result, or nil if retention has
+// never been applied.
+func (s *SQLiteStore) LastPrune(ctx context.Context) (*PruneRecord, error) {
+	var atStr, countStr sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT
+			(SELECT value FROM config WHERE key = ?),
+			(SELECT value FROM config WHERE key = ?)`,
+		configLastPruneAt, configLastPruneCount,
+	).Scan(&atStr, &countStr)
+	if err != nil {
+		return nil, fmt.Errorf("last prune: %w", err)
+	}
+	if !atStr.Valid {
+		return nil, nil
+	}
+
+	at, err := parseTimestamp(atStr.String)
+	if err != nil {
+		return nil, fmt.Errorf("parse last prune time: %w", err)
+	}
+	count, _ := strconv.ParseInt(countStr.String, 10, 64)
+	return &PruneRecord{At: at, Count: count}, nil
 }
 
 // GetStats returns aggregate statistics about the database.
@@ -603,9 +1382,41 @@ func (s *SQLiteStore) GetStats(ctx context.Context) (*Stats, error) {
 		stats.NewestEvent, _ = parseTimestamp(newestStr)
 	}
 
+	// Backlog of events lacking bodies or embeddings
+	err = s.db.QueryRowContext(ctx,
+		`SELECT
+			COALESCE(SUM(CASE WHEN has_body = 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN has_embedding = 0 THEN 1 ELSE 0 END), 0)
+		FROM events`,
+	).Scan(&stats.MissingBodies, &stats.MissingEmbeddings)
+	if err != nil {
+		return nil, fmt.Errorf("count missing bodies: %w", err)
+	}
+
+	// Exclusion rules, split by origin
+	err = s.db.QueryRowContext(ctx,
+		`SELECT
+			COALESCE(SUM(CASE WHEN is_default = 1 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN is_default = 0 THEN 1 ELSE 0 END), 0)
+		FROM exclusions`,
+	).Scan(&stats.DefaultExclusions, &stats.UserExclusions)
+	if err != nil {
+		return nil, fmt.Errorf("count exclusions: %w", err)
+	}
+
+	// FTS index size, from the FTS5 shadow tables holding the index
+	err = s.db.QueryRowContext(ctx,
+		`SELECT
+			(SELECT COALESCE(SUM(LENGTH(block)), 0) FROM events_fts_data) +
+			(SELECT COALESCE(SUM(LENGTH(term)), 0) FROM events_fts_idx)`,
+	).Scan(&stats.FTSIndexBytes)
+	if err != nil {
+		return nil, fmt.Errorf("fts index size: %w", err)
+	}
+
 	// Top domains
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT domain, COUNT(*) as cnt FROM events GROUP BY domain ORDER BY cnt DESC LIMIT 10",
+		"SELECT domain, SUM(events) AS cnt FROM domain_days GROUP BY domain ORDER BY cnt DESC LIMIT 10",
 	)
 	if err != nil {
 		return nil, fmt.Errorf("top domains: %w", err)
@@ -619,21 +1430,36 @@ func (s *SQLiteStore) GetStats(ctx context.Context) (*Stats, error) {
 		}
 		stats.TopDomains = append(stats.TopDomains, dc)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return stats, rows.Err()
+	if stats.Sources, err = s.ListSources(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Browsers, err = s.ListBrowsers(ctx); err != nil {
+		return nil, err
+	}
+	return stats, nil
 }
 
-// Close releases all prepared statements. The underlying *sql.DB is NOT
+// Close stops the writer and releases all prepared statements. Captures
+// after Close fail with ErrStoreClosed. The underlying *sql.DB is NOT
 // closed — that is the caller's responsibility.
 func (s *SQLiteStore) Close() error {
+	s.stopWriter()
+
 	stmts := []*sql.Stmt{
-		s.insertEvent, s.insertContent, s.getEvent,
-		s.deleteEvent, s.getContent,
+		s.insertEvent, s.insertContent, s.insertFTS, s.getEvent,
+		s.deleteEvent, s.getContent, s.getPreview,
 	}
 	for _, stmt := range stmts {
 		if stmt != nil {
 			stmt.Close()
 		}
 	}
+	if s.vectors != nil {
+		return s.vectors.Close()
+	}
 	return nil
 }
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -165,6 +169,45 @@ func TestSearchEvents_ByDomain(t *testing.T) {
 	}
 }
 
+func TestSearchEvents_MultiValueAndFlagFilters(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	a := &Event{URL: "https://a.com/1", Title: "A", Source: "extension", Browser: "firefox", Timestamp: now.Add(-3 * time.Minute)}
+	b := &Event{URL: "https://b.com/1", Title: "B", Source: "extension", Browser: "chrome", Timestamp: now.Add(-2 * time.Minute)}
+	c := &Event{URL: "https://c.com/1", Title: "C", Source: "extension", Browser: "safari", Timestamp: now.Add(-time.Minute)}
+	require.NoError(t, store.AddEventWithContent(ctx, a, "body"))
+	require.NoError(t, store.AddEvent(ctx, b))
+	require.NoError(t, store.AddEvent(ctx, c))
+	require.NoError(t, store.SetEmbedding(ctx, b.ID, "test-model", []float32{1, 0}))
+
+	ids := func(q SearchQuery) []string {
+		t.Helper()
+		q.Sort = SortOldest
+		results, err := store.SearchEvents(ctx, q)
+		require.NoError(t, err)
+		var out []string
+		for _, r := range results {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+	yes, no := true, false
+
+	assert.Equal(t, []string{a.ID, b.ID}, ids(SearchQuery{Domains: []string{"a.com", "b.com"}}))
+	assert.Equal(t, []string{a.ID, c.ID}, ids(SearchQuery{Domain: "c.com", Domains: []string{"a.com"}}))
+	assert.Equal(t, []string{b.ID, c.ID}, ids(SearchQuery{Browsers: []string{"chrome", "safari", "chrome"}}))
+	assert.Equal(t, []string{a.ID}, ids(SearchQuery{HasBody: &yes}))
+	assert.Equal(t, []string{b.ID, c.ID}, ids(SearchQuery{HasBody: &no}))
+	assert.Equal(t, []string{b.ID}, ids(SearchQuery{HasEmbedding: &yes}))
+	assert.Equal(t, []string{c.ID}, ids(SearchQuery{HasBody: &no, HasEmbedding: &no}))
+
+	count, err := store.CountEvents(ctx, SearchQuery{Domains: []string{"a.com", "c.com"}, Browsers: []string{"safari"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
 func TestSearchEvents_BySource(t *testing.T) {
 	store := openTestStore(t)
 	ctx := context.Background()
@@ -304,6 +347,85 @@ func TestGetContent_NotFound(t *testing.T) {
 	assert.Nil(t, content)
 }
 
+func TestGetContentPreview(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	event := &Event{URL: "https://example.com/long", Title: "Long", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, event, "café society and more"))
+
+	preview, err := store.GetContentPreview(ctx, event.ID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "caf", preview.Body, "partial rune should be dropped")
+
+	preview, err = store.GetContentPreview(ctx, event.ID, 13)
+	require.NoError(t, err)
+	assert.Equal(t, "café society", preview.Body)
+
+	preview, err = store.GetContentPreview(ctx, event.ID, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, "café society and more", preview.Body)
+
+	_, err = store.GetContentPreview(ctx, "CHR-nonexistent", 10)
+	assert.Error(t, err)
+}
+
+func TestGetContents(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < contentBatchSize+2; i++ {
+		e := &Event{URL: fmt.Sprintf("https://example.com/%d", i), Title: "Page", Source: "manual"}
+		require.NoError(t, store.AddEventWithContent(ctx, e, fmt.Sprintf("body %d", i)))
+		ids = append(ids, e.ID)
+	}
+	bare := &Event{URL: "https://example.com/bare", Title: "Bare", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, bare))
+
+	contents, err := store.GetContents(ctx, append(ids, bare.ID, "CHR-nonexistent"))
+	require.NoError(t, err)
+	assert.Len(t, contents, len(ids), "events without content are left out")
+	assert.Equal(t, "body 0", contents[ids[0]].Body)
+	assert.Equal(t, fmt.Sprintf("body %d", contentBatchSize+1), contents[ids[contentBatchSize+1]].Body, "later batches are loaded")
+
+	contents, err = store.GetContents(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+}
+
+func TestGetContentPreviews(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	event := &Event{URL: "https://example.com/long", Title: "Long", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, event, "café society and more"))
+
+	previews, err := store.GetContentPreviews(ctx, []string{event.ID}, 4)
+	require.NoError(t, err)
+	require.Contains(t, previews, event.ID)
+	assert.Equal(t, "caf", previews[event.ID].Body, "partial rune should be dropped")
+	assert.Equal(t, int64(len("café society and more")), previews[event.ID].OriginalSize)
+}
+
+func TestSearchSQL_NeverReadsContent(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	yes := true
+
+	for _, q := range []SearchQuery{
+		{Query: "kubernetes", Limit: 10},
+		{Query: "kubernetes", HasBody: &yes, Sort: SortNewest, Limit: 10},
+		{Domain: "github.com", HasBody: &yes, Limit: 10},
+		{Limit: 10},
+	} {
+		plan, err := store.ExplainSearch(ctx, q)
+		require.NoError(t, err)
+		assert.NotContains(t, plan.SQL, "content ", plan.SQL)
+		assert.NotContains(t, strings.Join(plan.Plan, "\n"), "content", plan.SQL)
+	}
+}
+
 // --- PruneExpired ---
 
 func TestPruneExpired(t *testing.T) {
@@ -357,6 +479,115 @@ func TestPurgeAll(t *testing.T) {
 	assert.Equal(t, 0, len(results), "should have no events after purge")
 }
 
+func TestPurgeAll_ThenAddIsSearchable(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com", Title: "Before purge", Source: "manual"}))
+	require.NoError(t, store.PurgeAll(ctx))
+
+	// The prepared FTS insert must keep working after the table is recreated.
+	e := &Event{URL: "https://b.com", Title: "After purge", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, e))
+	require.NoError(t, store.AddEventWithContent(ctx, &Event{URL: "https://c.com", Title: "After purge too", Source: "manual"}, "body"))
+
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "purge", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+// --- PurgeMatching ---
+
+func TestPurgeMatching_ByDomain(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	e1 := &Event{URL: "https://example.com/a", Title: "Example A", Source: "manual"}
+	e2 := &Event{URL: "https://www.example.com/b", Title: "Example B", Source: "extension"}
+	keep := &Event{URL: "https://notexample.com/", Title: "Keep", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, e1, "Body A"))
+	require.NoError(t, store.AddEvent(ctx, e2))
+	require.NoError(t, store.AddEventWithContent(ctx, keep, "Body keep"))
+
+	count, err := store.CountMatching(ctx, PurgeFilter{Domain: "example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	purged, err := store.PurgeMatching(ctx, PurgeFilter{Domain: "example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	_, err = store.GetContent(ctx, e1.ID)
+	assert.Error(t, err, "content should be purged with its event")
+
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "example"})
+	require.NoError(t, err)
+	assert.Empty(t, results, "FTS entries should be purged")
+
+	got, err := store.GetEvent(ctx, keep.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Keep", got.Title)
+	_, err = store.GetContent(ctx, keep.ID)
+	assert.NoError(t, err)
+}
+
+func TestPurgeMatching_BySourceAndDomain(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com/1", Title: "A1", Source: "import"}))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://a.com/2", Title: "A2", Source: "manual"}))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://b.com/1", Title: "B1", Source: "import"}))
+
+	purged, err := store.PurgeMatching(ctx, PurgeFilter{Source: "import", Domain: "a.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	purged, err = store.PurgeMatching(ctx, PurgeFilter{Source: "import"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalEvents)
+}
+
+func TestPurgeMatching_DomainBefore(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	oldNoisy := &Event{URL: "https://reddit.com/r/a", Title: "Old", Source: "extension", Timestamp: now.Add(-5 * 24 * time.Hour)}
+	newNoisy := &Event{URL: "https://reddit.com/r/b", Title: "New", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	oldOther := &Event{URL: "https://go.dev/", Title: "Other", Source: "extension", Timestamp: now.Add(-5 * 24 * time.Hour)}
+	for _, e := range []*Event{oldNoisy, newNoisy, oldOther} {
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+
+	filter := PurgeFilter{Domain: "reddit.com", Before: now.Add(-3 * 24 * time.Hour)}
+	count, err := store.CountMatching(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	purged, err := store.PurgeMatching(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = store.GetEvent(ctx, oldNoisy.ID)
+	assert.Error(t, err)
+	_, err = store.GetEvent(ctx, newNoisy.ID)
+	assert.NoError(t, err)
+	_, err = store.GetEvent(ctx, oldOther.ID)
+	assert.NoError(t, err)
+}
+
+func TestPurgeMatching_EmptyFilterErrors(t *testing.T) {
+	store := openTestStore(t)
+
+	_, err := store.PurgeMatching(context.Background(), PurgeFilter{})
+	assert.Error(t, err)
+}
+
 // --- Exclusions ---
 
 func TestAddEvent_SkipsExcludedDomains(t *testing.T) {
@@ -439,6 +670,24 @@ func TestGetStats_WithData(t *testing.T) {
 	assert.True(t, len(stats.TopDomains) > 0, "should have top domains")
 }
 
+func TestGetStats_Extended(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEventWithContent(ctx, &Event{URL: "https://a.com", Title: "Alpha", Source: "manual"}, "Body"))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://b.com", Title: "Beta", Source: "manual"}))
+	_, err := store.db.Exec(`INSERT INTO exclusions (rule_type, rule_value) VALUES ('domain', 'mine.example')`)
+	require.NoError(t, err)
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.MissingBodies)
+	assert.Equal(t, int64(2), stats.MissingEmbeddings)
+	assert.Greater(t, stats.DefaultExclusions, int64(0))
+	assert.Equal(t, int64(1), stats.UserExclusions)
+	assert.Greater(t, stats.FTSIndexBytes, int64(0))
+}
+
 // --- Close ---
 
 func TestClose(t *testing.T) {
@@ -446,3 +695,337 @@ func TestClose(t *testing.T) {
 	err := store.Close()
 	assert.NoError(t, err)
 }
+
+func TestLastPrune_NeverPruned(t *testing.T) {
+	store := openTestStore(t)
+
+	rec, err := store.LastPrune(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestRecordPrune_RoundTrip(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, store.RecordPrune(ctx, PruneRecord{At: first, Count: 7}))
+	second := first.Add(24 * time.Hour)
+	require.NoError(t, store.RecordPrune(ctx, PruneRecord{At: second, Count: 3}))
+
+	rec, err := store.LastPrune(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.True(t, second.Equal(rec.At))
+	assert.Equal(t, int64(3), rec.Count)
+}
+
+func TestSearchEvents_Sort(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	older := &Event{URL: "https://a.com/1", Title: "golang tips", Source: "manual", Timestamp: now.Add(-2 * time.Hour)}
+	newer := &Event{URL: "https://a.com/2", Title: "golang news", Source: "manual", Timestamp: now.Add(-1 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, older))
+	require.NoError(t, store.AddEvent(ctx, newer))
+
+	for _, query := range []string{"golang", ""} {
+		results, err := store.SearchEvents(ctx, SearchQuery{Query: query, Sort: SortOldest})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, older.ID, results[0].ID)
+
+		results, err = store.SearchEvents(ctx, SearchQuery{Query: query, Sort: SortNewest})
+		require.NoError(t, err)
+		assert.Equal(t, newer.ID, results[0].ID)
+	}
+
+	_, err := store.SearchEvents(ctx, SearchQuery{Sort: "sideways"})
+	assert.Error(t, err)
+}
+
+func TestAddEvent_RetriesOnIDCollision(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	ids := []string{"CHR-00000001", "CHR-00000001", "CHR-00000001", "CHR-00000002"}
+	orig := newEventID
+	newEventID = func() (string, error) {
+		id := ids[0]
+		ids = ids[1:]
+		return id, nil
+	}
+	t.Cleanup(func() { newEventID = orig })
+
+	first := &Event{URL: "https://example.com/a", Title: "A", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, first))
+	assert.Equal(t, "CHR-00000001", first.ID)
+
+	second := &Event{URL: "https://example.com/b", Title: "B", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, second, "body"))
+	assert.Equal(t, "CHR-00000002", second.ID)
+
+	got, err := store.GetEvent(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "B", got.Title)
+}
+
+// --- Body size limits ---
+
+func TestAddEventWithContent_TruncatesOversizedBody(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	store.SetBodyLimit(BodyLimit{MaxBytes: 10})
+
+	// "é" is two bytes; the cap falls inside the second one.
+	body := "abcdefghié and more"
+	e := &Event{URL: "https://example.com/big", Title: "Big", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, e, body))
+
+	c, err := store.GetContent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghi", c.Body)
+	assert.True(t, c.Truncated)
+	assert.Equal(t, int64(len(body)), c.OriginalSize)
+}
+
+func TestAddEventWithContent_RejectsOversizedBody(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	store.SetBodyLimit(BodyLimit{MaxBytes: 10, Reject: true})
+
+	e := &Event{URL: "https://example.com/big", Title: "Big", Source: "manual"}
+	err := store.AddEventWithContent(ctx, e, "this body is far too long")
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.Empty(t, e.ID)
+
+	small := &Event{URL: "https://example.com/small", Title: "Small", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, small, "short"))
+	c, err := store.GetContent(ctx, small.ID)
+	require.NoError(t, err)
+	assert.False(t, c.Truncated)
+	assert.Equal(t, int64(5), c.OriginalSize)
+}
+
+func TestDebugTraces(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://chase.com/accounts", Title: "Bank", Browser: "chrome", Source: "extension"}))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://go.dev/doc", Title: "Docs", Browser: "chrome", Source: "extension"}))
+	_, err := store.SearchEvents(ctx, SearchQuery{Query: "docs"})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `msg="domain excluded" domain=chase.com rule=chase.com`)
+	assert.Contains(t, out, `msg="capture batch committed" events=1`)
+	assert.Regexp(t, `msg=query sql="SELECT [^"\n]*" elapsed=\S+ rows=1`, out)
+}
+
+func TestDebugTraces_SkippedBelowDebug(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	store := openTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://chase.com/accounts", Title: "Bank", Browser: "chrome", Source: "extension"}))
+	_, err := store.SearchEvents(ctx, SearchQuery{})
+	require.NoError(t, err)
+
+	assert.Empty(t, buf.String())
+}
+
+func TestSearchEvents_Fields(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	inTitle := &Event{URL: "https://blog.example.com/http-semantics", Title: "Reading RFC 9110", Source: "manual"}
+	inURL := &Event{URL: "https://www.rfc-editor.org/info/9110", Title: "HTTP Semantics", Source: "manual"}
+	inBody := &Event{URL: "https://example.com/notes", Title: "Protocol notes", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, inTitle))
+	require.NoError(t, store.AddEvent(ctx, inURL))
+	require.NoError(t, store.AddEventWithContent(ctx, inBody, "As the RFC says, 100% of caches must revalidate."))
+
+	ids := func(fields ...string) []string {
+		t.Helper()
+		results, err := store.SearchEvents(ctx, SearchQuery{Query: "rfc", Fields: fields, Limit: 10})
+		require.NoError(t, err)
+		var out []string
+		for _, e := range results {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{inTitle.ID, inURL.ID, inBody.ID}, ids())
+	assert.Equal(t, []string{inTitle.ID}, ids(FieldTitle))
+	assert.Equal(t, []string{inURL.ID}, ids(FieldURL))
+	assert.Equal(t, []string{inBody.ID}, ids(FieldBody))
+	assert.ElementsMatch(t, []string{inURL.ID, inBody.ID}, ids(FieldURL, FieldBody))
+	assert.ElementsMatch(t, []string{inTitle.ID, inURL.ID, inBody.ID}, ids(FieldTitle, FieldURL, FieldBody))
+
+	// Punctuation in the query separates words rather than acting as a wildcard.
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "100%", Fields: []string{FieldBody}})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "1_0", Fields: []string{FieldBody}})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = store.SearchEvents(ctx, SearchQuery{Query: "rfc", Fields: []string{"summary"}})
+	assert.EqualError(t, err, `invalid search field "summary" (want title, url, or body)`)
+}
+
+func TestSearchEvents_BodyRanksBelowTitle(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	inBody := &Event{URL: "https://example.com/notes", Title: "Weekly notes", Source: "manual"}
+	inTitle := &Event{URL: "https://example.com/wal", Title: "SQLite WAL mode", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, inBody, "Switched the cache to WAL mode, then WAL checkpoints, then more WAL."))
+	require.NoError(t, store.AddEvent(ctx, inTitle))
+
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "wal", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, inTitle.ID, results[0].ID)
+	assert.Equal(t, inBody.ID, results[1].ID)
+}
+
+func TestQueryTerms_Synonyms(t *testing.T) {
+	groups := [][]string{{"js", "javascript"}, {"k8s", "kubernetes", "kube"}, {"ml", "machine learning"}}
+
+	assert.Equal(t, []string{"JS", "tips", "javascript"}, queryTerms(SearchQuery{Query: "JS tips", Synonyms: groups}))
+	assert.Equal(t, []string{"kubernetes", "k8s", "kube"}, queryTerms(SearchQuery{Query: "kubernetes", Synonyms: groups}))
+	assert.Equal(t, []string{"ml", "machine learning"}, queryTerms(SearchQuery{Query: "ml", Synonyms: groups}))
+	assert.Equal(t, []string{"js"}, queryTerms(SearchQuery{Query: "js"}))
+	assert.Equal(t, `"ml"* OR "machine learning"* OR "say ""hi"""*`, ftsQuery([]string{"ml", "machine learning", `say "hi"`}))
+}
+
+func TestSearchEvents_Synonyms(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	k8s := &Event{URL: "https://example.com/k8s-operators", Title: "Writing k8s operators", Source: "manual"}
+	full := &Event{URL: "https://kubernetes.io/docs", Title: "Kubernetes Documentation", Source: "manual"}
+	notes := &Event{URL: "https://example.com/notes", Title: "Cluster notes", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, k8s))
+	require.NoError(t, store.AddEvent(ctx, full))
+	require.NoError(t, store.AddEventWithContent(ctx, notes, "Upgrading the Kubernetes control plane."))
+
+	groups := [][]string{{"k8s", "kubernetes"}}
+	ids := func(q SearchQuery) []string {
+		t.Helper()
+		results, err := store.SearchEvents(ctx, q)
+		require.NoError(t, err)
+		var out []string
+		for _, e := range results {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{k8s.ID}, ids(SearchQuery{Query: "k8s"}))
+	assert.ElementsMatch(t, []string{k8s.ID, full.ID, notes.ID}, ids(SearchQuery{Query: "k8s", Synonyms: groups}))
+	assert.Equal(t, []string{notes.ID}, ids(SearchQuery{Query: "k8s", Synonyms: groups, Fields: []string{FieldBody}}))
+}
+
+func TestCountEvents(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		e := &Event{
+			URL:    "https://example.com/" + string(rune('a'+i)),
+			Title:  "Page " + string(rune('A'+i)),
+			Source: "manual",
+		}
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+	require.NoError(t, store.AddEventWithContent(ctx,
+		&Event{URL: "https://github.com/golang/go", Title: "Go", Source: "extension"}, "The Go page"))
+
+	count := func(q SearchQuery) int64 {
+		t.Helper()
+		n, err := store.CountEvents(ctx, q)
+		require.NoError(t, err)
+		return n
+	}
+
+	assert.Equal(t, int64(6), count(SearchQuery{}))
+	assert.Equal(t, int64(6), count(SearchQuery{Limit: 2, Offset: 4}), "paging is ignored")
+	assert.Equal(t, int64(6), count(SearchQuery{Query: "page"}))
+	assert.Equal(t, int64(1), count(SearchQuery{Domain: "github.com"}))
+	assert.Equal(t, int64(1), count(SearchQuery{Query: "page", Fields: []string{FieldBody}}))
+	assert.Equal(t, int64(1), count(SearchQuery{Query: "page", Source: "extension"}))
+
+	_, err := store.CountEvents(ctx, SearchQuery{Sort: "random"})
+	assert.EqualError(t, err, `invalid sort "random"`)
+}
+
+func TestSearchEvents_Lang(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	de := &Event{URL: "https://zeitung.example/artikel", Title: "Die Bahn und das Wetter", Source: "extension"}
+	require.NoError(t, store.AddEventWithContent(ctx, de, "Die Bahn ist nicht pünktlich, und das Wetter ist auch nicht gut. Es wird ein langer Tag."))
+	en := &Event{URL: "https://news.example/article", Title: "The Bahn and the weather", Source: "extension"}
+	require.NoError(t, store.AddEventWithContent(ctx, en, "The trains are not on time and the weather is bad. It will be a long day."))
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://bahn.example/", Title: "Bahn", Source: "manual"}))
+
+	got, err := store.GetEvent(ctx, de.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "de", got.Lang)
+
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "Bahn", Lang: "de", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, de.ID, results[0].ID)
+	assert.Equal(t, "de", results[0].Lang)
+
+	// "die" and "und" are German stopwords, so only "bahn" has to match;
+	// English stopwords are kept.
+	results, err = store.SearchEvents(ctx, SearchQuery{Query: "die Bahn und", Lang: "de", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, []string{"Bahn"}, queryTerms(SearchQuery{Query: "die Bahn und", Lang: "de"}))
+	assert.Equal(t, []string{"die", "und"}, queryTerms(SearchQuery{Query: "die und", Lang: "de"}), "a query of only stopwords is kept")
+	assert.Equal(t, []string{"the", "Bahn"}, queryTerms(SearchQuery{Query: "the Bahn", Lang: "de"}))
+
+	langs, err := store.ListLanguages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ValueCount{{"de", 1}, {"en", 1}}, langs)
+}
+
+func TestPageMeta(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	published := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	e := &Event{URL: "https://example.com/post", Title: "Post", Source: "manual",
+		Meta: &PageMeta{OGTitle: "A Post", Author: "Ada", Published: published}}
+	require.NoError(t, store.AddEvent(ctx, e))
+	bare := &Event{URL: "https://example.com/bare", Title: "Bare", Source: "manual", Meta: &PageMeta{}}
+	require.NoError(t, store.AddEvent(ctx, bare))
+
+	meta, err := store.GetPageMeta(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &PageMeta{OGTitle: "A Post", Author: "Ada", Published: published}, meta)
+
+	meta, err = store.GetPageMeta(ctx, bare.ID)
+	require.NoError(t, err)
+	assert.Nil(t, meta, "empty metadata is not stored")
+
+	require.NoError(t, store.DeleteEvent(ctx, e.ID))
+	meta, err = store.GetPageMeta(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Nil(t, meta, "metadata goes with its event")
+}
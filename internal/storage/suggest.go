@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SuggestQuery proposes a correction for a text query that found nothing,
+// replacing each word that is not in the search index with the closest
+// indexed term. Candidates come from the FTS5 vocabulary, share the word's
+// first letter, and are at most one edit away for words up to four letters
+// and two edits for longer ones; ties go to the term in more events. It
+// returns "" when every word is indexed or none has a close match.
+func (s *SQLiteStore) SuggestQuery(ctx context.Context, query string) (string, error) {
+	words := strings.Fields(strings.ToLower(query))
+	changed := false
+	for i, w := range words {
+		known, err := s.termIndexed(ctx, w)
+		if err != nil {
+			return "", err
+		}
+		if known {
+			continue
+		}
+		term, err := s.closestTerm(ctx, w)
+		if err != nil {
+			return "", err
+		}
+		if term != "" {
+			words[i], changed = term, true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(words, " "), nil
+}
+
+// termIndexed reports whether any indexed term starts with w, matching how
+// searches treat each word as a prefix.
+func (s *SQLiteStore) termIndexed(ctx context.Context, w string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM (SELECT 1 FROM events_fts_vocab WHERE term >= ? AND term < ? LIMIT 1)",
+		w, w+"\U0010FFFF",
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("look up term: %w", err)
+	}
+	return n > 0, nil
+}
+
+// closestTerm returns the indexed term nearest to w, or "" if none is
+// close enough (see SuggestQuery).
+func (s *SQLiteStore) closestTerm(ctx context.Context, w string) (string, error) {
+	first, size := utf8.DecodeRuneInString(w)
+	if size == 0 {
+		return "", nil
+	}
+	maxDist := 2
+	if utf8.RuneCountInString(w) <= 4 {
+		maxDist = 1
+	}
+	n := utf8.RuneCountInString(w)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT term, doc FROM events_fts_vocab
+		 WHERE term >= ? AND term < ? AND length(term) BETWEEN ? AND ?`,
+		string(first), string(first+1), n-maxDist, n+maxDist,
+	)
+	if err != nil {
+		return "", fmt.Errorf("scan vocabulary: %w", err)
+	}
+	defer rows.Close()
+
+	best, bestDist, bestDocs := "", maxDist+1, 0
+	for rows.Next() {
+		var term string
+		var docs int
+		if err := rows.Scan(&term, &docs); err != nil {
+			return "", err
+		}
+		d := EditDistance(w, term)
+		if d < bestDist || d == bestDist && docs > bestDocs {
+			best, bestDist, bestDocs = term, d, docs
+		}
+	}
+	return best, rows.Err()
+}
+
+// EditDistance returns the optimal string alignment distance between a and
+// b: the Levenshtein distance, counting a swap of adjacent runes as one
+// edit.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
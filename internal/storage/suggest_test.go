@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"kubernetes", "kubernetes", 0},
+		{"kubernets", "kubernetes", 1},
+		{"kuberentes", "kubernetes", 1}, // adjacent swap
+		{"kubrnets", "kubernetes", 2},
+		{"", "go", 2},
+		{"café", "cafe", 1},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, EditDistance(c.a, c.b), "%s -> %s", c.a, c.b)
+	}
+}
+
+func TestSuggestQuery(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	for _, e := range []*Event{
+		{URL: "https://kubernetes.io/docs", Title: "Kubernetes Documentation", Source: "manual"},
+		{URL: "https://kubernetes.io/blog", Title: "Kubernetes Blog", Source: "manual"},
+		{URL: "https://example.com/kubernates", Title: "Kubernates typo page", Source: "manual"},
+		{URL: "https://go.dev/doc", Title: "Go Documentation", Source: "manual"},
+	} {
+		require.NoError(t, store.AddEvent(ctx, e))
+	}
+
+	cases := map[string]string{
+		"kubernets":             "kubernetes",
+		"Kubernetse deployment": "kubernetes deployment",
+		"kubernetes":            "", // indexed
+		"kube":                  "", // prefix of an indexed term
+		"documentaton go":       "documentation go",
+		"zzzzzz":                "", // nothing close
+		"dog":                   "doc",
+	}
+	for in, want := range cases {
+		got, err := store.SuggestQuery(ctx, in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+}
+
+func TestSuggestQuery_AfterPurge(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.AddEvent(ctx, &Event{URL: "https://kubernetes.io/", Title: "Kubernetes", Source: "manual"}))
+	require.NoError(t, store.PurgeAll(ctx))
+
+	got, err := store.SuggestQuery(ctx, "kubernets")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
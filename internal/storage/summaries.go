@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Summary is the short generated summary stored for an event's body.
+type Summary struct {
+	EventID   string
+	Text      string
+	Model     string // what generated it, e.g. "ollama:llama3.2"
+	CreatedAt time.Time
+}
+
+// SetSummary stores sum for its event, replacing any earlier summary. It
+// is encrypted like a body when the store has a cipher.
+func (s *SQLiteStore) SetSummary(ctx context.Context, sum Summary) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if sum.CreatedAt.IsZero() {
+		sum.CreatedAt = time.Now()
+	}
+	text, err := s.sealBody(sum.Text)
+	if err != nil {
+		return fmt.Errorf("set summary: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO summaries (event_id, summary, model, created_at) VALUES (?, ?, ?, ?)`,
+		sum.EventID, text, sum.Model, sum.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("set summary: %w", err)
+	}
+	return nil
+}
+
+// GetSummary returns the summary stored for an event, or nil if it has
+// none.
+func (s *SQLiteStore) GetSummary(ctx context.Context, eventID string) (*Summary, error) {
+	sum := Summary{EventID: eventID}
+	var created string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT summary, model, created_at FROM summaries WHERE event_id = ?`, eventID,
+	).Scan(&sum.Text, &sum.Model, &created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get summary: %w", err)
+	}
+	if sum.Text, err = s.openBody(sum.Text); err != nil {
+		return nil, fmt.Errorf("get summary: %w", err)
+	}
+	sum.CreatedAt, _ = parseTimestamp(created)
+	return &sum, nil
+}
+
+// GetSummaries returns the summary text stored for each of eventIDs that
+// has one, by event ID.
+func (s *SQLiteStore) GetSummaries(ctx context.Context, eventIDs []string) (map[string]string, error) {
+	out := make(map[string]string, len(eventIDs))
+	for len(eventIDs) > 0 {
+		batch := eventIDs[:min(len(eventIDs), contentBatchSize)]
+		eventIDs = eventIDs[len(batch):]
+
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT event_id, summary FROM summaries WHERE event_id IN (?" + strings.Repeat(", ?", len(batch)-1) + ")"
+		start := time.Now()
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("get summaries: %w", err)
+		}
+		for rows.Next() {
+			var id, text string
+			if err := rows.Scan(&id, &text); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("get summaries: %w", err)
+			}
+			if text, err = s.openBody(text); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("get summaries: %w", err)
+			}
+			out[id] = text
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("get summaries: %w", err)
+		}
+		traceQuery(ctx, query, start, "ids", len(batch))
+	}
+	return out, nil
+}
+
+// EventsWithoutSummary returns up to limit events that have a body but no
+// summary, newest first. limit <= 0 returns every one.
+func (s *SQLiteStore) EventsWithoutSummary(ctx context.Context, limit int) ([]Event, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	return s.scanEvents(ctx,
+		`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+		FROM events
+		WHERE has_body = 1 AND id NOT IN (SELECT event_id FROM summaries)
+		ORDER BY ts DESC
+		LIMIT ?`,
+		limit,
+	)
+}
+
+// EmbeddingText returns the text an event's embedding is generated from:
+// its title, then its summary, or its body when it has no summary. A
+// summary states what the page is about in a few sentences, which embeds
+// closer to the questions asked of it than a long body does.
+func (s *SQLiteStore) EmbeddingText(ctx context.Context, eventID string) (string, error) {
+	var title, text string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT e.title, COALESCE(s.summary, c.body, '')
+		FROM events e
+		LEFT JOIN summaries s ON s.event_id = e.id
+		LEFT JOIN content c ON c.event_id = e.id
+		WHERE e.id = ?`, eventID,
+	).Scan(&title, &text)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("event %s not found", eventID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("embedding text: %w", err)
+	}
+	if text, err = s.openBody(text); err != nil {
+		return "", fmt.Errorf("embedding text: %w", err)
+	}
+	return strings.TrimSpace(title + "\n\n" + text), nil
+}
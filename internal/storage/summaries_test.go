@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaries(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	withBody := &Event{URL: "https://a.example/tides", Title: "Tides", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, withBody, "A long page about tides."))
+	other := &Event{URL: "https://b.example/moon", Title: "Moon", Source: "manual"}
+	require.NoError(t, store.AddEventWithContent(ctx, other, "A long page about the moon."))
+	noBody := &Event{URL: "https://c.example/", Title: "Bare", Source: "manual"}
+	require.NoError(t, store.AddEvent(ctx, noBody))
+
+	pending, err := store.EventsWithoutSummary(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2, "only events with bodies")
+
+	got, err := store.GetSummary(ctx, withBody.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	text, err := store.EmbeddingText(ctx, withBody.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Tides\n\nA long page about tides.", text, "the body until there is a summary")
+
+	require.NoError(t, store.SetSummary(ctx, Summary{EventID: withBody.ID, Text: "First.", Model: "test"}))
+	require.NoError(t, store.SetSummary(ctx, Summary{EventID: withBody.ID, Text: "Tides rise twice a day.", Model: "test"}))
+	got, err = store.GetSummary(ctx, withBody.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Tides rise twice a day.", got.Text, "replaced")
+	assert.Equal(t, "test", got.Model)
+	assert.False(t, got.CreatedAt.IsZero())
+
+	text, err = store.EmbeddingText(ctx, withBody.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Tides\n\nTides rise twice a day.", text)
+	text, err = store.EmbeddingText(ctx, noBody.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Bare", text)
+
+	all, err := store.GetSummaries(ctx, []string{withBody.ID, other.ID, noBody.ID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{withBody.ID: "Tides rise twice a day."}, all)
+
+	pending, err = store.EventsWithoutSummary(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, other.ID, pending[0].ID)
+
+	require.NoError(t, store.DeleteEvent(ctx, withBody.ID))
+	got, err = store.GetSummary(ctx, withBody.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "summaries go with their event")
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// config table keys used by sync.
+const (
+	configSyncDevice       = "sync_device_id"
+	configSyncCursorPrefix = "sync_cursor:"
+)
+
+// SyncDeviceID returns this database's sync device ID, creating a random
+// one on first use.
+func (s *SQLiteStore) SyncDeviceID(ctx context.Context) (string, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, configSyncDevice).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("read sync device: %w", err)
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate sync device: %w", err)
+	}
+	id = hex.EncodeToString(b)
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO config (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`,
+		configSyncDevice, id,
+	); err != nil {
+		return "", fmt.Errorf("save sync device: %w", err)
+	}
+	return id, nil
+}
+
+// UnsyncedEvents returns up to limit events not yet exported or imported by
+// sync, oldest first.
+func (s *SQLiteStore) UnsyncedEvents(ctx context.Context, limit int) ([]Event, error) {
+	return s.scanEvents(ctx,
+		`SELECT e.id, e.ts, e.url, e.title, e.domain, e.browser, e.source, e.has_body, e.has_embedding, e.content_hash, e.lang, e.canonical_url, e.read_seconds, e.raw_title, e.visit_count
+		FROM events e LEFT JOIN sync_events s ON s.event_id = e.id
+		WHERE s.event_id IS NULL
+		ORDER BY e.ts, e.id
+		LIMIT ?`,
+		limit,
+	)
+}
+
+// MarkSynced records that the events in ids were exchanged with device.
+func (s *SQLiteStore) MarkSynced(ctx context.Context, device string, ids []string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO sync_events (event_id, device) VALUES (?, ?)`, id, device,
+		); err != nil {
+			return fmt.Errorf("mark synced: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// EventsAt returns the events captured at ts, to the second.
+func (s *SQLiteStore) EventsAt(ctx context.Context, ts time.Time) ([]Event, error) {
+	return s.scanEvents(ctx,
+		`SELECT id, ts, url, title, domain, browser, source, has_body, has_embedding, content_hash, lang, canonical_url, read_seconds, raw_title, visit_count
+		FROM events WHERE ts = ?`,
+		ts.UTC().Format(time.RFC3339),
+	)
+}
+
+// SyncCursor returns the last sync file applied from peer, or "" if none.
+func (s *SQLiteStore) SyncCursor(ctx context.Context, peer string) (string, error) {
+	var cursor string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, configSyncCursorPrefix+peer).Scan(&cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read sync cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SetSyncCursor records file as the last sync file applied from peer.
+func (s *SQLiteStore) SetSyncCursor(ctx context.Context, peer, file string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO config (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		configSyncCursorPrefix+peer, file,
+	); err != nil {
+		return fmt.Errorf("save sync cursor: %w", err)
+	}
+	return nil
+}
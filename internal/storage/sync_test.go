@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncState(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	device, err := store.SyncDeviceID(ctx)
+	require.NoError(t, err)
+	assert.Len(t, device, 16)
+	again, err := store.SyncDeviceID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, device, again, "device ID is stable")
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := &Event{URL: "https://a.example/", Timestamp: base.Add(time.Hour)}
+	b := &Event{URL: "https://b.example/", Timestamp: base}
+	require.NoError(t, store.AddEvent(ctx, a))
+	require.NoError(t, store.AddEvent(ctx, b))
+
+	unsynced, err := store.UnsyncedEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unsynced, 2)
+	assert.Equal(t, b.ID, unsynced[0].ID, "oldest first")
+
+	require.NoError(t, store.MarkSynced(ctx, device, []string{b.ID}))
+	unsynced, err = store.UnsyncedEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unsynced, 1)
+	assert.Equal(t, a.ID, unsynced[0].ID)
+
+	at, err := store.EventsAt(ctx, base.Add(time.Hour).Add(400*time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, at, 1)
+	assert.Equal(t, a.ID, at[0].ID)
+
+	cursor, err := store.SyncCursor(ctx, "peer")
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+	require.NoError(t, store.SetSyncCursor(ctx, "peer", "f1.jsonl"))
+	require.NoError(t, store.SetSyncCursor(ctx, "peer", "f2.jsonl"))
+	cursor, err = store.SyncCursor(ctx, "peer")
+	require.NoError(t, err)
+	assert.Equal(t, "f2.jsonl", cursor)
+
+	require.NoError(t, store.DeleteEvent(ctx, b.ID))
+	var n int
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM sync_events`).Scan(&n))
+	assert.Equal(t, 0, n, "sync state is removed with the event")
+}
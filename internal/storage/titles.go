@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// titleSeparators are what sites put between a page's own title and the
+// site name they append to it.
+var titleSeparators = []string{" — ", " – ", " | ", " · ", " - ", " :: ", " » "}
+
+// siteNames are site-name suffixes stripped from titles on any domain, for
+// sites whose name doesn't match their domain or that are often mirrored.
+var siteNames = map[string]bool{
+	"dev community":  true,
+	"github":         true,
+	"hacker news":    true,
+	"medium":         true,
+	"mdn":            true,
+	"mdn web docs":   true,
+	"reddit":         true,
+	"stack overflow": true,
+	"substack":       true,
+	"the verge":      true,
+	"wikipedia":      true,
+	"youtube":        true,
+}
+
+// cleanTitle strips a site-name suffix ("… — Medium", "… | Hacker News",
+// "… · GitHub") from a page title. The suffix is the text after the last
+// separator, and is only stripped when it is a known site name or spells
+// one of the labels of domain ("The Verge" on theverge.com), so titles
+// that merely contain a dash keep it. A title that is nothing but the
+// suffix is returned unchanged.
+func cleanTitle(title, domain string) string {
+	title = strings.TrimSpace(title)
+	cut, suffix := -1, ""
+	for _, sep := range titleSeparators {
+		if i := strings.LastIndex(title, sep); i > cut {
+			cut, suffix = i, title[i+len(sep):]
+		}
+	}
+	if cut <= 0 || !isSiteName(suffix, domain) {
+		return title
+	}
+	if rest := strings.TrimSpace(title[:cut]); rest != "" {
+		return rest
+	}
+	return title
+}
+
+// isSiteName reports whether a title suffix names the site at domain.
+func isSiteName(suffix, domain string) bool {
+	name := strings.Join(strings.Fields(strings.ToLower(suffix)), " ")
+	if name == "" {
+		return false
+	}
+	if siteNames[name] {
+		return true
+	}
+	squashed := squashName(name)
+	if squashed == "" {
+		return false
+	}
+	if squashed == squashName(domain) {
+		return true
+	}
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for _, label := range labels[:max(len(labels)-1, 0)] {
+		if label != "www" && squashed == squashName(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// squashName keeps only the letters and digits of s, lowercased, so
+// "Stack Overflow" and "stackoverflow" compare equal.
+func squashName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// normalizeTitle strips the site-name suffix from event.Title, keeping the
+// title as captured in RawTitle when that changes it.
+func normalizeTitle(event *Event) {
+	title := cleanTitle(event.Title, event.Domain)
+	if title == event.Title {
+		return
+	}
+	if event.RawTitle == "" {
+		event.RawTitle = event.Title
+	}
+	event.Title = title
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		title, domain, want string
+	}{
+		{"Why SQLite works — Medium", "medium.com", "Why SQLite works"},
+		{"Why SQLite works – Medium", "alice.example", "Why SQLite works"},
+		{"Show HN: Chronicle | Hacker News", "news.ycombinator.com", "Show HN: Chronicle"},
+		{"Fix race · runnerr0/chronicle · GitHub", "github.com", "Fix race · runnerr0/chronicle"},
+		{"Tides - Wikipedia", "en.wikipedia.org", "Tides"},
+		{"New phones reviewed | The Verge", "www.theverge.com", "New phones reviewed"},
+		{"Release notes - Example.com", "example.com", "Release notes"},
+		{"Pricing | Acme", "acme.io", "Pricing"},
+		{"Rise - and fall", "example.com", "Rise - and fall"},
+		{"Notes | Another Site", "acme.io", "Notes | Another Site"},
+		{"GitHub", "github.com", "GitHub"},
+		{" | GitHub", "github.com", "| GitHub"},
+		{"  Plain title  ", "example.com", "Plain title"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, cleanTitle(tt.title, tt.domain), tt.title)
+	}
+}
+
+func TestAddEvent_NormalizesTitle(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	e := &Event{URL: "https://medium.com/@a/sqlite", Title: "Why SQLite works — Medium", Source: "manual", Timestamp: now}
+	require.NoError(t, store.AddEventWithContent(ctx, e, "SQLite is small."))
+	plain := &Event{URL: "https://example.org/", Title: "Plain", Source: "manual", Timestamp: now}
+	require.NoError(t, store.AddEvent(ctx, plain))
+
+	got, err := store.GetEvent(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Why SQLite works", got.Title)
+	assert.Equal(t, "Why SQLite works — Medium", got.RawTitle)
+
+	got, err = store.GetEvent(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Plain", got.Title)
+	assert.Empty(t, got.RawTitle)
+
+	var indexed string
+	require.NoError(t, store.db.QueryRow(`SELECT title FROM events_fts WHERE event_id = ?`, e.ID).Scan(&indexed))
+	assert.Equal(t, "Why SQLite works", indexed)
+}
@@ -4,37 +4,117 @@ import "time"
 
 // Event represents a single browsing event captured by Chronicle.
 type Event struct {
-	ID          string
-	URL         string
-	Title       string
-	Domain      string
-	Timestamp   time.Time
-	Source      string // "extension", "manual", "import"
-	Browser     string
-	ContentHash string
-	HasBody     bool
-	HasEmbed    bool
+	ID           string
+	URL          string
+	Title        string
+	Domain       string
+	Timestamp    time.Time
+	Source       string // "extension", "manual", "import", or the name of a pulled source
+	Browser      string
+	ContentHash  string
+	HasBody      bool
+	HasEmbed     bool
+	Lang         string        // ISO 639-1 code of the body's language; "" if unknown or no body
+	CanonicalURL string        // the page's <link rel=canonical>, when it differs from URL
+	ReadTime     time.Duration // estimated reading time of the whole body (see ReadingTime); 0 without one
+	RawTitle     string        // the title as captured, when a site-name suffix was stripped from Title (see cleanTitle)
+	Meta         *PageMeta     // page metadata to store with the event; not loaded by GetEvent (see GetPageMeta)
+	Keywords     []string      // keyphrases extracted from the body; not loaded by GetEvent (see KeywordsFor)
+	Visits       int           // captures folded into this event by deduplication, counting the first (see SetDedupeWindow)
+}
+
+// PageURL returns the URL that identifies the page: the canonical URL when
+// the page declared one, else the URL it was captured at.
+func (e *Event) PageURL() string {
+	if e.CanonicalURL != "" {
+		return e.CanonicalURL
+	}
+	return e.URL
+}
+
+// PageMeta is metadata a page declares about itself: its OpenGraph title,
+// description and image, and its article author and publication date.
+// Empty fields were not found.
+type PageMeta struct {
+	OGTitle       string
+	OGDescription string
+	OGImage       string
+	Author        string
+	Published     time.Time
+}
+
+// IsZero reports whether m holds no metadata.
+func (m PageMeta) IsZero() bool {
+	return m == PageMeta{}
 }
 
 // Content holds the stored body text for an event.
 type Content struct {
-	EventID     string
-	Body        string
-	ContentHash string
+	EventID      string
+	Body         string
+	ContentHash  string
+	Truncated    bool  // body was cut to the configured size cap
+	OriginalSize int64 // byte size before any truncation
+}
+
+// BodyLimit caps stored body size. Oversized bodies are truncated (at a
+// UTF-8 boundary) unless Reject is set, in which case the capture fails
+// with ErrBodyTooLarge. MaxBytes <= 0 means no limit.
+type BodyLimit struct {
+	MaxBytes int
+	Reject   bool
 }
 
 // SearchQuery defines filters for searching events.
 type SearchQuery struct {
 	Query        string
 	Domain       string
+	Domains      []string // only events from any of these domains or Domain, when set
 	Source       string
 	Browser      string
+	Browsers     []string      // only events from any of these browsers or Browser, when set
+	Lang         string        // ISO 639-1 code; also drops that language's stopwords from Query
+	MinReadTime  time.Duration // only events whose body takes at least this long to read
+	Keyword      string        // only events with this stored keyword (see NormalizeKeyword), or a keyphrase containing it
 	Since        time.Time
 	Until        time.Time
 	Limit        int
 	Offset       int
-	HasBody      bool
-	HasEmbedding bool
+	HasBody      *bool      // only events with (true) or without (false) a body; nil for either
+	HasEmbedding *bool      // only events with (true) or without (false) an embedding; nil for either
+	Sort         string     // SortRelevance (default), SortNewest, or SortOldest
+	Fields       []string   // fields Query matches: FieldTitle, FieldURL, FieldBody; empty means all of them
+	Synonyms     [][]string // groups of interchangeable words; a query word in a group also matches the others
+}
+
+// Fields a text query can be scoped to with SearchQuery.Fields.
+const (
+	FieldTitle = "title"
+	FieldURL   = "url"
+	FieldBody  = "body"
+)
+
+// Result orderings for SearchQuery.Sort. Relevance only applies to text
+// queries; filter-only searches treat it as newest first.
+const (
+	SortRelevance = "relevance"
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+)
+
+// PurgeFilter selects events for a selective purge or scoped prune. Empty
+// fields match anything, but at least one field must be set. Domain also
+// matches subdomains (example.com matches www.example.com).
+type PurgeFilter struct {
+	Domain string
+	Source string
+	Before time.Time // only events with timestamps before this
+}
+
+// PruneRecord describes the most recent application of the retention policy.
+type PruneRecord struct {
+	At    time.Time
+	Count int64
 }
 
 // Stats holds aggregate statistics about the Chronicle database.
@@ -45,6 +125,22 @@ type Stats struct {
 	NewestEvent       time.Time
 	DatabaseSizeBytes int64
 	TopDomains        []DomainCount
+	Sources           []ValueCount
+	Browsers          []ValueCount
+
+	MissingBodies     int64 // events without captured content
+	MissingEmbeddings int64 // events without an embedding
+	DefaultExclusions int64 // built-in exclusion rules
+	UserExclusions    int64 // user-added exclusion rules
+	FTSIndexBytes     int64 // approximate size of the full-text index
+}
+
+// ContentTotals sums the bodies captured over a period.
+type ContentTotals struct {
+	Bodies int64 // events with a stored body
+	Words  int64
+	Chars  int64
+	Bytes  int64
 }
 
 // DomainCount pairs a domain with its event count.
@@ -52,3 +148,87 @@ type DomainCount struct {
 	Domain string
 	Count  int64
 }
+
+// ValueCount pairs a distinct column value, such as a source or browser,
+// with its event count.
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+// DayCount is the number of events captured on one calendar day.
+type DayCount struct {
+	Day   time.Time // midnight at the start of the day
+	Count int64
+}
+
+// TimeActivity counts events by local weekday and hour of day.
+type TimeActivity struct {
+	Counts [7][24]int64 // [weekday][hour], weekday indexed like time.Weekday
+}
+
+// ByHour totals the counts for each hour of the day across weekdays.
+func (a *TimeActivity) ByHour() [24]int64 {
+	var hours [24]int64
+	for _, day := range a.Counts {
+		for h, n := range day {
+			hours[h] += n
+		}
+	}
+	return hours
+}
+
+// ByWeekday totals the counts for each weekday across hours.
+func (a *TimeActivity) ByWeekday() [7]int64 {
+	var days [7]int64
+	for d, day := range a.Counts {
+		for _, n := range day {
+			days[d] += n
+		}
+	}
+	return days
+}
+
+// SearchRecord is one executed search kept in the opt-in search history.
+type SearchRecord struct {
+	ID      int64
+	At      time.Time
+	Query   string
+	Domain  string
+	Source  string
+	Browser string
+	Since   string // the --since window as given, e.g. "30d"
+	Results int
+}
+
+// QueryCount summarizes how often a query has been searched.
+type QueryCount struct {
+	Query       string
+	Count       int64
+	LastAt      time.Time
+	ZeroResults int64 // searches that found nothing
+}
+
+// LongRead is an event with a captured body, ranked for the report.
+type LongRead struct {
+	Event
+	Bytes int64 // body size before any truncation
+}
+
+// PastCapture is the latest visit to a page, with how much attention the
+// page got, for resurfacing.
+type PastCapture struct {
+	Event
+	Bytes  int64 // body size before any truncation; 0 without a body
+	Visits int   // captures of the same page in the range
+}
+
+// Extension is a browser extension registered with the ingest daemon.
+type Extension struct {
+	ID           string
+	Browser      string // chrome, firefox, ...
+	Version      string // extension version
+	Profile      string // browser profile name, if the extension reports one
+	RegisteredAt time.Time
+	LastSeen     time.Time
+}
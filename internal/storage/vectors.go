@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/vectorstore"
+)
+
+// SetVectorStore makes the store keep embedding vectors in vs rather than
+// the embeddings table, first moving any vectors the table holds into it.
+// Which events are embedded, and by which model, is still recorded in the
+// database. The store closes vs on Close.
+func (s *SQLiteStore) SetVectorStore(ctx context.Context, vs vectorstore.Store) error {
+	s.vectors = vs
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	start := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT v.event_id, m.model_name, v.vector
+		FROM embeddings v JOIN embedding_metadata m ON m.event_id = v.event_id`)
+	if err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	moved := 0
+	for rows.Next() {
+		var id, model string
+		var blob []byte
+		if err := rows.Scan(&id, &model, &blob); err != nil {
+			rows.Close()
+			return fmt.Errorf("move embeddings: %w", err)
+		}
+		if err := vs.Put(ctx, model, id, decodeVector(blob)); err != nil {
+			rows.Close()
+			return fmt.Errorf("move embeddings: %w", err)
+		}
+		moved++
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	if moved == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM embeddings`); err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("move embeddings: %w", err)
+	}
+	slog.InfoContext(ctx, "embeddings moved to the vector store", "count", moved, "elapsed", time.Since(start))
+	return nil
+}
+
+// nearestInStore is nearest for a store with a vector store: the vector
+// store ranks the embeddings of the events q's filters select.
+func (s *SQLiteStore) nearestInStore(ctx context.Context, q SearchQuery, model string, vector []float32) ([]scoredID, error) {
+	clauses := []string{"m.model_name = ?"}
+	args := []interface{}{model}
+	filters, filterArgs := filterClauses(q, "e.")
+	clauses = append(clauses, filters...)
+	args = append(args, filterArgs...)
+	query := `SELECT e.id
+		FROM events e
+		JOIN embedding_metadata m ON m.event_id = e.id
+		WHERE ` + strings.Join(clauses, " AND ")
+
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("nearest events: %w", err)
+	}
+	defer rows.Close()
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("nearest events: %w", err)
+		}
+		ids[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("nearest events: %w", err)
+	}
+	traceQuery(ctx, query, start, "rows", len(ids))
+
+	matches, err := s.vectors.Search(ctx, model, vector, func(id string) bool { return ids[id] })
+	if err != nil {
+		return nil, fmt.Errorf("nearest events: %w", err)
+	}
+	candidates := make([]scoredID, len(matches))
+	for i, m := range matches {
+		candidates[i] = scoredID{m.ID, m.Score}
+	}
+	return candidates, nil
+}
+
+// retainVectors drops from the vector store, if there is one, the vectors
+// the database no longer records: those of deleted events and of models
+// an event was embedded by before. The caller holds writeMu.
+func (s *SQLiteStore) retainVectors(ctx context.Context) error {
+	if s.vectors == nil {
+		return nil
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT event_id, model_name FROM embedding_metadata`)
+	if err != nil {
+		return fmt.Errorf("compact vectors: %w", err)
+	}
+	defer rows.Close()
+	models := map[string]string{} // event ID -> model
+	for rows.Next() {
+		var id, model string
+		if err := rows.Scan(&id, &model); err != nil {
+			return fmt.Errorf("compact vectors: %w", err)
+		}
+		models[id] = model
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("compact vectors: %w", err)
+	}
+
+	dropped, err := s.vectors.Retain(ctx, func(model, id string) bool { return models[id] == model })
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "vectors compacted", "dropped", dropped)
+	return nil
+}
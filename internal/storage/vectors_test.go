@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/vectorstore"
+)
+
+func TestVectorStore(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	tides := &Event{URL: "https://a.example/tides", Title: "Tides", Source: "manual", Timestamp: now.Add(-3 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, tides))
+	moon := &Event{URL: "https://b.example/moon", Title: "Moon", Source: "manual", Timestamp: now.Add(-2 * time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, moon))
+	bare := &Event{URL: "https://c.example/", Title: "Bare", Source: "extension", Timestamp: now.Add(-time.Hour)}
+	require.NoError(t, store.AddEvent(ctx, bare))
+
+	// An embedding made before the vector store moves into it.
+	require.NoError(t, store.SetEmbedding(ctx, tides.ID, "m1", []float32{1, 0, 0}))
+
+	dir := t.TempDir()
+	flat, err := vectorstore.OpenFlat(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.SetVectorStore(ctx, flat))
+	var inTable int
+	require.NoError(t, store.db.QueryRow(`SELECT COUNT(*) FROM embeddings`).Scan(&inTable))
+	assert.Zero(t, inTable)
+
+	require.NoError(t, store.SetEmbedding(ctx, moon.ID, "m1", []float32{0.6, 0.8, 0}))
+	require.NoError(t, store.SetEmbedding(ctx, bare.ID, "m1", []float32{0, 0, 1}))
+	pending, err := store.EventsWithoutEmbedding(ctx, "m1", false, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	ids := func(q SearchQuery) []string {
+		t.Helper()
+		results, err := store.NearestEvents(ctx, q, "m1", []float32{0.9, 0.1, 0})
+		require.NoError(t, err)
+		var out []string
+		for _, e := range results {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{tides.ID, moon.ID, bare.ID}, ids(SearchQuery{}))
+	assert.Equal(t, []string{moon.ID}, ids(SearchQuery{Limit: 1, Offset: 1}))
+	assert.Equal(t, []string{bare.ID}, ids(SearchQuery{Source: "extension"}))
+
+	require.NoError(t, store.DeleteEvent(ctx, bare.ID))
+	assert.Equal(t, []string{tides.ID, moon.ID}, ids(SearchQuery{}))
+
+	// Vacuum drops the deleted event's vector and the replaced model's.
+	require.NoError(t, store.SetEmbedding(ctx, moon.ID, "m2", []float32{0, 1}))
+	require.NoError(t, store.Vacuum(ctx))
+	matches, err := flat.Search(ctx, "m1", []float32{1, 0, 0}, nil)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, tides.ID, matches[0].ID)
+
+	// Without the vector store, its vectors count as missing.
+	require.NoError(t, flat.Close())
+	store.vectors = nil
+	pending, err = store.EventsWithoutEmbedding(ctx, "m1", false, 0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+}
+
+func TestVectorStore_DeletesDropVectors(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	flat, err := vectorstore.OpenFlat(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.SetVectorStore(ctx, flat))
+
+	add := func(url, source string, age time.Duration) string {
+		t.Helper()
+		e := &Event{URL: url, Title: url, Source: source, Timestamp: now.Add(-age)}
+		require.NoError(t, store.AddEvent(ctx, e))
+		require.NoError(t, store.SetEmbedding(ctx, e.ID, "m1", []float32{1, 0}))
+		return e.ID
+	}
+	stored := func() []string {
+		t.Helper()
+		matches, err := flat.Search(ctx, "m1", []float32{1, 0}, nil)
+		require.NoError(t, err)
+		var ids []string
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+
+	deleted := add("https://a.example/", "manual", time.Hour)
+	expired := add("https://b.example/", "manual", 60*24*time.Hour)
+	purged := add("https://c.example/", "extension", time.Hour)
+	kept := add("https://d.example/", "manual", time.Hour)
+	assert.ElementsMatch(t, []string{deleted, expired, purged, kept}, stored())
+
+	require.NoError(t, store.DeleteEvent(ctx, deleted))
+	assert.ElementsMatch(t, []string{expired, purged, kept}, stored())
+
+	_, err = store.PruneExpired(ctx, now.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{purged, kept}, stored())
+
+	_, err = store.PurgeMatching(ctx, PurgeFilter{Source: "extension"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{kept}, stored())
+
+	require.NoError(t, store.PurgeAll(ctx))
+	assert.Empty(t, stored())
+}
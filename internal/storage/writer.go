@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrStoreClosed is returned by writes issued after Close.
+var ErrStoreClosed = errors.New("store is closed")
+
+// maxWriteBatch caps how many queued captures share one transaction.
+const maxWriteBatch = 256
+
+// writeReq is one queued capture awaiting the writer.
+type writeReq struct {
+	ctx      context.Context
+	event    *Event
+	body     string
+	withBody bool
+	done     chan error
+}
+
+// startWriter launches the single writer goroutine. SQLite allows one
+// writer at a time; funnelling captures through one goroutine avoids
+// SQLITE_BUSY contention between concurrent HTTP handlers and lets bursts
+// share a transaction.
+func (s *SQLiteStore) startWriter() {
+	s.writes = make(chan *writeReq)
+	s.quit = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.writer()
+}
+
+// stopWriter stops the writer after any batch in progress. Safe to call
+// more than once.
+func (s *SQLiteStore) stopWriter() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+		<-s.stopped
+	})
+}
+
+// enqueue hands req to the writer and waits for its result.
+func (s *SQLiteStore) enqueue(req *writeReq) error {
+	req.done = make(chan error, 1)
+	select {
+	case s.writes <- req:
+	case <-s.quit:
+		return ErrStoreClosed
+	case <-req.ctx.Done():
+		return req.ctx.Err()
+	}
+	return <-req.done
+}
+
+// writer collects whatever captures are waiting and commits them together.
+func (s *SQLiteStore) writer() {
+	defer close(s.stopped)
+	for {
+		select {
+		case <-s.quit:
+			return
+		case req := <-s.writes:
+			batch := []*writeReq{req}
+		drain:
+			for len(batch) < maxWriteBatch {
+				select {
+				case r := <-s.writes:
+					batch = append(batch, r)
+				default:
+					break drain
+				}
+			}
+			s.writeBatch(batch)
+		}
+	}
+}
+
+// writeBatch commits batch in one transaction. If any capture fails, the
+// transaction is rolled back and each capture is retried alone so only the
+// failing ones report errors.
+func (s *SQLiteStore) writeBatch(batch []*writeReq) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if len(batch) > 1 && s.commitBatch(batch) == nil {
+		for _, req := range batch {
+			req.done <- nil
+		}
+		return
+	}
+	for _, req := range batch {
+		err := s.commitBatch([]*writeReq{req})
+		if err != nil {
+			req.event.ID = ""
+		}
+		req.done <- err
+	}
+}
+
+func (s *SQLiteStore) commitBatch(batch []*writeReq) error {
+	start := time.Now()
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, req := range batch {
+		if err := s.insertCapture(tx, req); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	slog.Debug("capture batch committed", "events", len(batch), "elapsed", time.Since(start))
+	return nil
+}
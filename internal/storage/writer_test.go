@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openFileStore creates a migrated file-backed store. Concurrency tests
+// need a file: each pooled connection to :memory: is a separate database.
+func openFileStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on&_busy_timeout=5000")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, NewMigrationRunner(db).Run())
+	store, err := NewSQLiteStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestWriter_ConcurrentCaptures(t *testing.T) {
+	store := openFileStore(t)
+	ctx := context.Background()
+
+	const n = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := &Event{URL: fmt.Sprintf("https://example.com/%d", i), Title: fmt.Sprintf("Concurrent %d", i), Source: "extension"}
+			if i%2 == 0 {
+				errs <- store.AddEventWithContent(ctx, e, "body")
+			} else {
+				errs <- store.AddEvent(ctx, e)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(n), stats.TotalEvents)
+	assert.Equal(t, int64(n/2), stats.TotalContent)
+
+	results, err := store.SearchEvents(ctx, SearchQuery{Query: "concurrent", Limit: 200})
+	require.NoError(t, err)
+	assert.Len(t, results, n)
+}
+
+func TestWriter_FailedCaptureDoesNotSinkBatch(t *testing.T) {
+	store := openFileStore(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	good := &writeReq{ctx: context.Background(), event: &Event{URL: "https://a.com", Title: "Good", Source: "manual"}, done: make(chan error, 1)}
+	bad := &writeReq{ctx: cancelled, event: &Event{URL: "https://b.com", Title: "Bad", Source: "manual"}, done: make(chan error, 1)}
+	store.writeBatch([]*writeReq{good, bad})
+
+	require.NoError(t, <-good.done)
+	assert.Error(t, <-bad.done)
+	assert.Empty(t, bad.event.ID)
+
+	got, err := store.GetEvent(context.Background(), good.event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Good", got.Title)
+}
+
+func TestWriter_AddAfterClose(t *testing.T) {
+	store := openFileStore(t)
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Close(), "Close should be idempotent")
+
+	err := store.AddEvent(context.Background(), &Event{URL: "https://a.com", Title: "Late", Source: "manual"})
+	assert.ErrorIs(t, err, ErrStoreClosed)
+}
+
+func TestAddEvents_ReportsEachCapture(t *testing.T) {
+	store := openFileStore(t)
+	store.SetBodyLimit(BodyLimit{MaxBytes: 10, Reject: true})
+	ctx := context.Background()
+
+	captures := []Capture{
+		{Event: &Event{URL: "https://example.com/plain", Title: "Plain", Source: "import"}},
+		{Event: &Event{URL: "https://example.com/body", Title: "Body", Source: "import"}, Body: "short", WithBody: true},
+		{Event: &Event{URL: "https://chase.com/login", Title: "Excluded", Source: "import"}},
+		{Event: &Event{URL: "https://example.com/big", Title: "Big", Source: "import"}, Body: "far too long a body", WithBody: true},
+	}
+	for i := 0; i < maxWriteBatch; i++ {
+		captures = append(captures, Capture{Event: &Event{URL: fmt.Sprintf("https://example.com/%d", i), Title: "Bulk", Source: "import"}})
+	}
+
+	errs := store.AddEvents(ctx, captures)
+	require.Len(t, errs, len(captures))
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.NoError(t, errs[2])
+	assert.Empty(t, captures[2].Event.ID, "excluded capture is skipped")
+	assert.ErrorIs(t, errs[3], ErrBodyTooLarge)
+	for i, err := range errs[4:] {
+		require.NoError(t, err, i)
+	}
+
+	stats, err := store.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2+maxWriteBatch), stats.TotalEvents)
+	assert.Equal(t, int64(1), stats.TotalContent)
+
+	got, err := store.GetEvent(ctx, captures[1].Event.ID)
+	require.NoError(t, err)
+	assert.True(t, got.HasBody)
+}
@@ -0,0 +1,358 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// flatFile is the Flat store's file name under its directory.
+const flatFile = "vectors.dat"
+
+// flatLock is the file Flat locks around writes, under its directory. It
+// is separate from flatFile, which Retain replaces.
+const flatLock = "vectors.lock"
+
+// flatMagic starts a Flat store's file and names its format version.
+const flatMagic = "CHRVEC1\n"
+
+// Flat is a Store kept in memory and backed by an append-only file, with
+// exact (exhaustive) search. Each Put appends a record, so a vector that
+// is replaced still takes space in the file until Retain rewrites it.
+// Several processes can share the file, as the daemon and the CLI do: each
+// operation first reads what others appended, or reloads the file if
+// another Retain replaced it. Puts hold a shared lock on the directory's
+// lock file and Retain an exclusive one, so no record is appended to a
+// file Retain is about to replace.
+//
+// A record is the model and event ID, each a little-endian uint16 length
+// and its bytes, then the vector: a uint32 dimension count and that many
+// little-endian float32s.
+type Flat struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File                        // open for appending
+	lock    *os.File                        // flatLock, for lockFile
+	read    int64                           // bytes of the file loaded
+	vectors map[string]map[string][]float32 // model -> event ID -> vector
+}
+
+// OpenFlat opens the Flat store in dir, creating it if needed, and loads
+// its vectors. A record cut short by a crash is dropped.
+func OpenFlat(dir string) (*Flat, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create vector directory: %w", err)
+	}
+	f := &Flat{path: filepath.Join(dir, flatFile), vectors: map[string]map[string][]float32{}}
+
+	lock, err := os.OpenFile(filepath.Join(dir, flatLock), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open vector lock: %w", err)
+	}
+	// Creating and repairing the file are writes too.
+	if err := lockFile(lock, true); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("lock vectors: %w", err)
+	}
+	defer unlockFile(lock)
+	if err := f.open(); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	f.lock = lock
+	return f, nil
+}
+
+// open loads the file, creating or repairing it as needed, and opens it
+// for appending. The caller holds the exclusive lock.
+func (f *Flat) open() error {
+	data, err := os.ReadFile(f.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read vectors: %w", err)
+	}
+	if len(data) == 0 {
+		data = []byte(flatMagic)
+		if err := os.WriteFile(f.path, data, 0600); err != nil {
+			return fmt.Errorf("create vectors: %w", err)
+		}
+	}
+	if len(data) < len(flatMagic) || string(data[:len(flatMagic)]) != flatMagic {
+		return fmt.Errorf("%s is not a vector store file", f.path)
+	}
+
+	good := len(flatMagic) + f.load(data[len(flatMagic):])
+	if good < len(data) {
+		slog.Warn("dropping incomplete vector record", "path", f.path, "bytes", len(data)-good)
+		if err := os.Truncate(f.path, int64(good)); err != nil {
+			return fmt.Errorf("repair vectors: %w", err)
+		}
+	}
+	f.read = int64(good)
+
+	if f.file, err = os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return fmt.Errorf("open vectors: %w", err)
+	}
+	return nil
+}
+
+// load reads the records in data into f.vectors and returns the length of
+// the whole records read.
+func (f *Flat) load(data []byte) int {
+	off := 0
+	for off < len(data) {
+		model, id, vector, n := decodeRecord(data[off:])
+		if n == 0 {
+			break
+		}
+		f.set(model, id, vector)
+		off += n
+	}
+	return off
+}
+
+// refresh catches up with changes other processes made to the file. The
+// caller holds f.mu.
+func (f *Flat) refresh() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("read vectors: %w", err)
+	}
+	open, err := f.file.Stat()
+	if err != nil {
+		return fmt.Errorf("read vectors: %w", err)
+	}
+
+	if !os.SameFile(info, open) {
+		// Rewritten by Retain: load it afresh.
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return fmt.Errorf("read vectors: %w", err)
+		}
+		if len(data) < len(flatMagic) || string(data[:len(flatMagic)]) != flatMagic {
+			return fmt.Errorf("%s is not a vector store file", f.path)
+		}
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("open vectors: %w", err)
+		}
+		f.file.Close()
+		f.file, f.vectors = file, map[string]map[string][]float32{}
+		f.read = int64(len(flatMagic) + f.load(data[len(flatMagic):]))
+		return nil
+	}
+
+	if info.Size() <= f.read {
+		return nil
+	}
+	r, err := os.Open(f.path)
+	if err != nil {
+		return fmt.Errorf("read vectors: %w", err)
+	}
+	defer r.Close()
+	data := make([]byte, info.Size()-f.read)
+	if _, err := r.ReadAt(data, f.read); err != nil {
+		return fmt.Errorf("read vectors: %w", err)
+	}
+	f.read += int64(f.load(data))
+	return nil
+}
+
+// set stores vector in memory.
+func (f *Flat) set(model, id string, vector []float32) {
+	byID, ok := f.vectors[model]
+	if !ok {
+		byID = map[string][]float32{}
+		f.vectors[model] = byID
+	}
+	byID[id] = vector
+}
+
+// Put appends the vector to the file, synced before it returns, and
+// stores it in memory.
+func (f *Flat) Put(ctx context.Context, model, id string, vector []float32) error {
+	record, err := encodeRecord(model, id, vector)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := lockFile(f.lock, false); err != nil {
+		return fmt.Errorf("lock vectors: %w", err)
+	}
+	defer unlockFile(f.lock)
+	if err := f.refresh(); err != nil {
+		return err
+	}
+	// The record is loaded back by the next refresh, which keeps f.read in
+	// step with records other processes append meanwhile.
+	if _, err := f.file.Write(record); err != nil {
+		return fmt.Errorf("write vector: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("write vector: %w", err)
+	}
+	f.set(model, id, append([]float32(nil), vector...))
+	return nil
+}
+
+// Search compares query with every vector from model. Equal scores are
+// ordered by ID.
+func (f *Flat) Search(ctx context.Context, model string, query []float32, accept func(id string) bool) ([]Match, error) {
+	f.mu.Lock()
+	if err := f.refresh(); err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	var matches []Match
+	for id, v := range f.vectors[model] {
+		if len(v) == len(query) && (accept == nil || accept(id)) {
+			matches = append(matches, Match{ID: id, Score: Cosine(query, v)})
+		}
+	}
+	f.mu.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches, nil
+}
+
+// Retain rewrites the file with only the vectors kept, replacing it
+// atomically, which also reclaims the space of replaced vectors. Other
+// processes' Puts wait until it is done.
+func (f *Flat) Retain(ctx context.Context, keep func(model, id string) bool) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := lockFile(f.lock, true); err != nil {
+		return 0, fmt.Errorf("lock vectors: %w", err)
+	}
+	defer unlockFile(f.lock)
+	if err := f.refresh(); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("compact vectors: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+
+	buf := []byte(flatMagic)
+	kept := map[string]map[string][]float32{}
+	dropped := 0
+	for model, byID := range f.vectors {
+		for id, v := range byID {
+			if !keep(model, id) {
+				dropped++
+				continue
+			}
+			record, err := encodeRecord(model, id, v)
+			if err != nil {
+				tmp.Close()
+				return 0, err
+			}
+			buf = append(buf, record...)
+			if kept[model] == nil {
+				kept[model] = map[string][]float32{}
+			}
+			kept[model][id] = v
+		}
+	}
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("compact vectors: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("compact vectors: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("compact vectors: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return 0, fmt.Errorf("compact vectors: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("open vectors: %w", err)
+	}
+	f.file.Close()
+	f.file, f.vectors, f.read = file, kept, int64(len(buf))
+	return dropped, nil
+}
+
+// Close closes the file.
+func (f *Flat) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lock.Close()
+	return f.file.Close()
+}
+
+// encodeRecord packs one vector as a file record.
+func encodeRecord(model, id string, vector []float32) ([]byte, error) {
+	if len(model) > math.MaxUint16 || len(id) > math.MaxUint16 {
+		return nil, fmt.Errorf("write vector: model or event ID too long")
+	}
+	b := make([]byte, 0, 2+len(model)+2+len(id)+4+4*len(vector))
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(model)))
+	b = append(b, model...)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(id)))
+	b = append(b, id...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(vector)))
+	for _, x := range vector {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+	}
+	return b, nil
+}
+
+// decodeRecord unpacks the record at the start of b and returns its
+// length, or 0 if b holds only part of one.
+func decodeRecord(b []byte) (model, id string, vector []float32, n int) {
+	field := func() (string, bool) {
+		if len(b)-n < 2 {
+			return "", false
+		}
+		size := int(binary.LittleEndian.Uint16(b[n:]))
+		n += 2
+		if len(b)-n < size {
+			return "", false
+		}
+		s := string(b[n : n+size])
+		n += size
+		return s, true
+	}
+	var ok bool
+	if model, ok = field(); !ok {
+		return "", "", nil, 0
+	}
+	if id, ok = field(); !ok {
+		return "", "", nil, 0
+	}
+	if len(b)-n < 4 {
+		return "", "", nil, 0
+	}
+	dims := int(binary.LittleEndian.Uint32(b[n:]))
+	n += 4
+	if (len(b)-n)/4 < dims {
+		return "", "", nil, 0
+	}
+	vector = make([]float32, dims)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[n:]))
+		n += 4
+	}
+	return model, id, vector, n
+}
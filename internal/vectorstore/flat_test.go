@@ -0,0 +1,183 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlat_PutSearchReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	f, err := OpenFlat(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Put(ctx, "m1", "CHR-a", []float32{1, 0}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-b", []float32{0, 1}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-c", []float32{1, 1}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-d", []float32{1, 1, 1})) // other dimensions
+	require.NoError(t, f.Put(ctx, "m2", "CHR-e", []float32{1, 0}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-b", []float32{-1, 0})) // replaces
+
+	ids := func(matches []Match) []string {
+		var out []string
+		for _, m := range matches {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+	matches, err := f.Search(ctx, "m1", []float32{1, 0}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHR-a", "CHR-c", "CHR-b"}, ids(matches))
+	assert.InDelta(t, 1.0, matches[0].Score, 1e-9)
+	assert.InDelta(t, -1.0, matches[2].Score, 1e-9)
+
+	matches, err = f.Search(ctx, "m1", []float32{1, 0}, func(id string) bool { return id != "CHR-a" })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHR-c", "CHR-b"}, ids(matches))
+	require.NoError(t, f.Close())
+
+	f, err = OpenFlat(dir)
+	require.NoError(t, err)
+	matches, err = f.Search(ctx, "m1", []float32{1, 0}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHR-a", "CHR-c", "CHR-b"}, ids(matches))
+	require.NoError(t, f.Close())
+}
+
+func TestFlat_Retain(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	f, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, f.Put(ctx, "m1", "CHR-a", []float32{1, 0}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-a", []float32{0, 1}))
+	require.NoError(t, f.Put(ctx, "m1", "CHR-b", []float32{1, 1}))
+	require.NoError(t, f.Put(ctx, "m2", "CHR-a", []float32{1, 1}))
+	before, err := os.Stat(filepath.Join(dir, flatFile))
+	require.NoError(t, err)
+
+	dropped, err := f.Retain(ctx, func(model, id string) bool { return model == "m1" && id == "CHR-a" })
+	require.NoError(t, err)
+	assert.Equal(t, 2, dropped)
+	after, err := os.Stat(filepath.Join(dir, flatFile))
+	require.NoError(t, err)
+	assert.Less(t, after.Size(), before.Size())
+
+	// Writes after a rewrite go to the new file.
+	require.NoError(t, f.Put(ctx, "m1", "CHR-c", []float32{1, 0}))
+	require.NoError(t, f.Close())
+	f, err = OpenFlat(dir)
+	require.NoError(t, err)
+	matches, err := f.Search(ctx, "m1", []float32{0, 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Match{{ID: "CHR-a", Score: 1}, {ID: "CHR-c", Score: 0}}, matches)
+	matches, err = f.Search(ctx, "m2", []float32{1, 1}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestFlat_SharedBetweenProcesses(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	a, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, a.Put(ctx, "m1", "CHR-a", []float32{1, 0}))
+	require.NoError(t, b.Put(ctx, "m1", "CHR-b", []float32{0, 1}))
+	matches, err := a.Search(ctx, "m1", []float32{1, 0}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Match{{ID: "CHR-a", Score: 1}, {ID: "CHR-b", Score: 0}}, matches)
+
+	_, err = a.Retain(ctx, func(model, id string) bool { return id == "CHR-b" })
+	require.NoError(t, err)
+	require.NoError(t, b.Put(ctx, "m1", "CHR-c", []float32{1, 1}))
+	matches, err = a.Search(ctx, "m1", []float32{0, 1}, nil)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "CHR-b", matches[0].ID)
+	assert.Equal(t, "CHR-c", matches[1].ID)
+}
+
+func TestFlat_RetainDuringPuts(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	a, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer b.Close()
+
+	// b stands in for another process appending while a compacts.
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 200; i++ {
+			if err := b.Put(ctx, "m1", fmt.Sprintf("CHR-%03d", i), []float32{1, 0}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	for i := 0; i < 50; i++ {
+		_, err := a.Retain(ctx, func(model, id string) bool { return true })
+		require.NoError(t, err)
+	}
+	require.NoError(t, <-done)
+
+	f, err := OpenFlat(dir)
+	require.NoError(t, err)
+	defer f.Close()
+	matches, err := f.Search(ctx, "m1", []float32{1, 0}, nil)
+	require.NoError(t, err)
+	assert.Len(t, matches, 200)
+}
+
+func TestFlat_DropsTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	f, err := OpenFlat(dir)
+	require.NoError(t, err)
+	require.NoError(t, f.Put(ctx, "m1", "CHR-a", []float32{1, 0}))
+	require.NoError(t, f.Close())
+
+	path := filepath.Join(dir, flatFile)
+	good, err := os.ReadFile(path)
+	require.NoError(t, err)
+	record, err := encodeRecord("m1", "CHR-b", []float32{0, 1})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(good, record[:len(record)-3]...), 0600))
+
+	f, err = OpenFlat(dir)
+	require.NoError(t, err)
+	defer f.Close()
+	matches, err := f.Search(ctx, "m1", []float32{1, 0}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Match{{ID: "CHR-a", Score: 1}}, matches)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, good, data)
+
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, flatFile), []byte("not vectors"), 0600))
+	_, err = OpenFlat(other)
+	assert.ErrorContains(t, err, "is not a vector store file")
+}
+
+func TestCosine(t *testing.T) {
+	v := []float32{0.5, -1, 2}
+	assert.InDelta(t, 1.0, Cosine(v, v), 1e-9)
+	assert.Zero(t, Cosine([]float32{0, 0}, []float32{1, 1}))
+}
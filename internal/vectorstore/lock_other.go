@@ -0,0 +1,16 @@
+//go:build !unix
+
+package vectorstore
+
+import "os"
+
+// lockFile does nothing where flock is not supported, so processes
+// sharing a Flat store there must not run Retain while another writes.
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile does nothing, as lockFile took no lock.
+func unlockFile(f *os.File) error {
+	return nil
+}
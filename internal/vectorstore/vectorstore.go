@@ -0,0 +1,51 @@
+// Package vectorstore keeps event embeddings outside the Chronicle database
+// and finds the ones nearest a query embedding.
+//
+// The database records which events are embedded and by which model; a
+// Store holds only the vectors, keyed by model and event ID.
+package vectorstore
+
+import (
+	"context"
+	"math"
+)
+
+// Store holds embedding vectors by model and event ID.
+type Store interface {
+	// Put stores vector as id's embedding from model, replacing any
+	// earlier one.
+	Put(ctx context.Context, model, id string, vector []float32) error
+
+	// Search scores every vector from model of query's length whose ID
+	// accept allows (nil allows all) by cosine similarity to query, most
+	// similar first.
+	Search(ctx context.Context, model string, query []float32, accept func(id string) bool) ([]Match, error)
+
+	// Retain drops every vector keep rejects and returns how many it
+	// dropped.
+	Retain(ctx context.Context, keep func(model, id string) bool) (int, error)
+
+	Close() error
+}
+
+// Match is an event found by Search and its similarity to the query.
+type Match struct {
+	ID    string
+	Score float64
+}
+
+// Cosine returns the cosine similarity of a and b, which have the same
+// length; 0 when either is all zeros.
+func Cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,232 @@
+// Package webhook POSTs captured events to user-configured HTTP endpoints,
+// for feeding Chronicle into automations such as Slack or Notion. Each
+// webhook can be limited to some domains and sources. Chronicle does not
+// tag events, so the source filter stands in for filtering by tag.
+//
+// By default the body is the JSON Payload. A webhook with a template sends
+// that template executed over the Payload instead, so services that expect
+// their own shape (IFTTT, Zapier, Matrix bots) need no adapter, e.g.
+//
+//	template: '{"msgtype": "m.text", "body": {{json (printf "%s %s" .Title .URL)}}}'
+//
+// Besides text/template's builtins, templates can call json, which encodes
+// its argument as a JSON value (quoting and escaping strings).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// EventCaptured is the event type of capture deliveries, sent in the
+// X-Chronicle-Event header and the payload.
+const EventCaptured = "event.captured"
+
+// SignatureHeader carries "sha256=<hex HMAC of the body>" when the webhook
+// has a secret.
+const SignatureHeader = "X-Chronicle-Signature"
+
+// defaultTimeout applies to webhooks without timeout_seconds.
+const defaultTimeout = 5 * time.Second
+
+// Payload is the JSON body POSTed for a captured event.
+type Payload struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Domain    string `json:"domain"`
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	Browser   string `json:"browser,omitempty"`
+	HasBody   bool   `json:"has_body"`
+}
+
+// Dispatcher delivers events to the configured webhooks.
+type Dispatcher struct {
+	hooks  []hook
+	client *http.Client
+}
+
+// hook is a configured webhook with its parsed template, or the error
+// parsing it, which is reported on every delivery.
+type hook struct {
+	config.WebhookConfig
+	tmpl    *template.Template
+	tmplErr error
+}
+
+// New returns a Dispatcher for hooks. Hooks without a URL are ignored.
+func New(hooks []config.WebhookConfig) *Dispatcher {
+	d := &Dispatcher{client: &http.Client{}}
+	for _, h := range hooks {
+		if h.URL == "" {
+			continue
+		}
+		hk := hook{WebhookConfig: h}
+		if h.Template != "" {
+			hk.tmpl, hk.tmplErr = parseTemplate(h.Template)
+		}
+		d.hooks = append(d.hooks, hk)
+	}
+	return d
+}
+
+// parseTemplate parses a webhook payload template.
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Notify POSTs e to every webhook whose filters match it, one after
+// another, and returns one error per failed delivery. A delivery fails on
+// a template error, a transport error or a non-2xx response; it is not
+// retried.
+func (d *Dispatcher) Notify(ctx context.Context, e *storage.Event) []error {
+	payload := payloadFor(e)
+	var plain []byte
+	var errs []error
+	for _, h := range d.hooks {
+		if !Matches(h.WebhookConfig, e) {
+			continue
+		}
+
+		var body []byte
+		var err error
+		if h.Template != "" {
+			body, err = h.render(payload)
+		} else {
+			if plain == nil {
+				plain, err = json.Marshal(payload)
+			}
+			body = plain
+		}
+		if err == nil {
+			err = d.post(ctx, h, body)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.URL, err))
+		}
+	}
+	return errs
+}
+
+// render executes h's template over p.
+func (h hook) render(p Payload) ([]byte, error) {
+	if h.tmplErr != nil {
+		return nil, h.tmplErr
+	}
+	var buf bytes.Buffer
+	if err := h.tmpl.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Dispatcher) post(ctx context.Context, h hook, body []byte) error {
+	timeout := defaultTimeout
+	if h.TimeoutSeconds > 0 {
+		timeout = time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	contentType := "application/json"
+	if h.Template != "" && h.ContentType != "" {
+		contentType = h.ContentType
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "chronicle-webhook")
+	req.Header.Set("X-Chronicle-Event", EventCaptured)
+	if h.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(h.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Matches reports whether e passes h's filters. A domain filter matches the
+// domain itself and its subdomains; empty filters match everything.
+func Matches(h config.WebhookConfig, e *storage.Event) bool {
+	if len(h.Domains) > 0 && !matchesDomain(h.Domains, e.Domain) {
+		return false
+	}
+	if len(h.Sources) > 0 && !contains(h.Sources, e.Source) {
+		return false
+	}
+	return true
+}
+
+func matchesDomain(domains []string, domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "."))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func payloadFor(e *storage.Event) Payload {
+	return Payload{
+		Type:      EventCaptured,
+		ID:        e.ID,
+		URL:       e.URL,
+		Title:     e.Title,
+		Domain:    e.Domain,
+		Timestamp: e.Timestamp.UTC().Format(time.RFC3339),
+		Source:    e.Source,
+		Browser:   e.Browser,
+		HasBody:   e.HasBody,
+	}
+}
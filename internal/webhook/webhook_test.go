@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/config"
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+func testEvent() *storage.Event {
+	return &storage.Event{
+		ID:        "CHR-0000abcd",
+		URL:       "https://docs.github.com/en/actions",
+		Title:     "Actions docs",
+		Domain:    "docs.github.com",
+		Source:    "extension",
+		Browser:   "firefox",
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		HasBody:   true,
+	}
+}
+
+func TestNotify_PostsSignedPayload(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d := New([]config.WebhookConfig{{URL: srv.URL, Secret: "s3cret"}})
+	require.Empty(t, d.Notify(context.Background(), testEvent()))
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, EventCaptured, got.Header.Get("X-Chronicle-Event"))
+	assert.Equal(t, Sign("s3cret", body), got.Header.Get(SignatureHeader))
+
+	var p Payload
+	require.NoError(t, json.Unmarshal(body, &p))
+	assert.Equal(t, EventCaptured, p.Type)
+	assert.Equal(t, "CHR-0000abcd", p.ID)
+	assert.Equal(t, "https://docs.github.com/en/actions", p.URL)
+	assert.Equal(t, "2026-03-01T12:00:00Z", p.Timestamp)
+	assert.True(t, p.HasBody)
+}
+
+func TestNotify_UnsignedWithoutSecret(t *testing.T) {
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig = r.Header.Get(SignatureHeader)
+	}))
+	defer srv.Close()
+
+	require.Empty(t, New([]config.WebhookConfig{{URL: srv.URL}}).Notify(context.Background(), testEvent()))
+	assert.Empty(t, sig)
+}
+
+func TestNotify_ReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var ok int
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { ok++ }))
+	defer good.Close()
+
+	errs := New([]config.WebhookConfig{{URL: srv.URL}, {URL: good.URL}}).Notify(context.Background(), testEvent())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "500")
+	assert.Equal(t, 1, ok, "a failing hook does not stop the others")
+}
+
+func TestNotify_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	errs := New([]config.WebhookConfig{{URL: srv.URL, TimeoutSeconds: 1}}).Notify(context.Background(), testEvent())
+	require.Len(t, errs, 1)
+	assert.Less(t, time.Since(start), 4*time.Second)
+}
+
+func TestNotify_Template(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	e := testEvent()
+	e.Title = `Say "hi"`
+	hooks := []config.WebhookConfig{{
+		URL:      srv.URL,
+		Secret:   "s3cret",
+		Template: `{"msgtype": "m.text", "body": {{json (printf "%s %s" .Title .URL)}}, "body_captured": {{.HasBody}}}`,
+	}}
+	require.Empty(t, New(hooks).Notify(context.Background(), e))
+
+	require.NotNil(t, got)
+	assert.JSONEq(t, `{"msgtype": "m.text", "body": "Say \"hi\" https://docs.github.com/en/actions", "body_captured": true}`, string(body))
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, Sign("s3cret", body), got.Header.Get(SignatureHeader), "the rendered body is what is signed")
+}
+
+func TestNotify_TemplateContentType(t *testing.T) {
+	var contentType string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	hooks := []config.WebhookConfig{{
+		URL:         srv.URL,
+		Template:    `value1={{urlquery .Title}}&value2={{urlquery .URL}}`,
+		ContentType: "application/x-www-form-urlencoded",
+	}}
+	require.Empty(t, New(hooks).Notify(context.Background(), testEvent()))
+	assert.Equal(t, "application/x-www-form-urlencoded", contentType)
+	assert.Equal(t, "value1=Actions+docs&value2=https%3A%2F%2Fdocs.github.com%2Fen%2Factions", string(body))
+}
+
+func TestNotify_TemplateErrors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ }))
+	defer srv.Close()
+
+	hooks := []config.WebhookConfig{
+		{URL: srv.URL, Template: `{{.Title`},
+		{URL: srv.URL, Template: `{{.Nope}}`},
+		{URL: srv.URL},
+	}
+	errs := New(hooks).Notify(context.Background(), testEvent())
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "unclosed action")
+	assert.Contains(t, errs[1].Error(), "Nope")
+	assert.Equal(t, 1, calls, "hooks with broken templates are not delivered")
+}
+
+func TestMatches(t *testing.T) {
+	e := testEvent()
+	tests := []struct {
+		name string
+		hook config.WebhookConfig
+		want bool
+	}{
+		{"no filters", config.WebhookConfig{}, true},
+		{"exact domain", config.WebhookConfig{Domains: []string{"docs.github.com"}}, true},
+		{"parent domain", config.WebhookConfig{Domains: []string{"GitHub.com"}}, true},
+		{"suffix is not a subdomain", config.WebhookConfig{Domains: []string{"hub.com"}}, false},
+		{"other domain", config.WebhookConfig{Domains: []string{"example.com"}}, false},
+		{"source", config.WebhookConfig{Sources: []string{"manual", "extension"}}, true},
+		{"other source", config.WebhookConfig{Sources: []string{"import"}}, false},
+		{"domain and source", config.WebhookConfig{Domains: []string{"github.com"}, Sources: []string{"import"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Matches(tt.hook, e))
+		})
+	}
+}
+
+func TestNew_SkipsHooksWithoutURL(t *testing.T) {
+	d := New([]config.WebhookConfig{{Secret: "x"}})
+	assert.Empty(t, d.Notify(context.Background(), testEvent()))
+}
@@ -0,0 +1,253 @@
+// Package webpage fetches a page and extracts what Chronicle stores about
+// it: the title, the readable text, and the metadata the page declares
+// (OpenGraph tags, article author and publication date, canonical URL).
+//
+// Extraction is a lenient scan of the HTML rather than a full parse: it
+// reads tags and text in order, skipping script, style and similar
+// elements, which is enough for the head metadata and a plain-text body.
+package webpage
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+// MaxBytes caps how much of a response Fetch reads.
+const MaxBytes = 5 << 20
+
+// Page is what Fetch and Parse extract from a document.
+type Page struct {
+	Title     string
+	Text      string
+	Meta      storage.PageMeta
+	Canonical string // <link rel="canonical"> href; absolute once fetched
+}
+
+// Fetch GETs rawURL with client and extracts the page. HTML is parsed (see
+// Parse), with its canonical URL resolved against the final URL after
+// redirects; plain text is kept as the text; other content types are an
+// error.
+func Fetch(ctx context.Context, client *http.Client, rawURL string) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9")
+	req.Header.Set("User-Agent", "chronicle")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", rawURL, err)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "" || mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		page := Parse(string(data))
+		if page.Canonical != "" {
+			if ref, err := url.Parse(page.Canonical); err == nil {
+				page.Canonical = resp.Request.URL.ResolveReference(ref).String()
+			}
+		}
+		return page, nil
+	case mediaType == "text/plain":
+		return &Page{Text: strings.TrimSpace(string(data))}, nil
+	default:
+		return nil, fmt.Errorf("fetch %s: unsupported content type %s", rawURL, mediaType)
+	}
+}
+
+// skipElements hold no readable text.
+var skipElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "iframe": true, "nav": true, "footer": true,
+}
+
+// blockElements end a line of text.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "blockquote": true, "pre": true,
+	"ul": true, "ol": true, "table": true, "hr": true,
+}
+
+var (
+	tagPattern  = regexp.MustCompile(`(?s)<!--.*?-->|<[!?][^>]*>|<(/?)([a-zA-Z][a-zA-Z0-9-]*)([^>]*)>`)
+	attrPattern = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Parse extracts the title, text and metadata from an HTML document. The
+// title is the og:title when <title> is missing; the canonical URL is the
+// first <link rel="canonical"> href, as written. Unrecognized or malformed
+// markup is skipped rather than rejected.
+func Parse(doc string) *Page {
+	p := &Page{}
+	var text, title strings.Builder
+	inHead, inTitle := false, false
+	skipping := "" // element whose content is being skipped
+	last := 0
+
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(doc, -1) {
+		between := doc[last:m[0]]
+		last = m[1]
+		switch {
+		case inTitle:
+			title.WriteString(between)
+		case skipping == "" && !inHead:
+			text.WriteString(strings.ReplaceAll(between, "\n", " "))
+		}
+		if m[4] < 0 {
+			continue // comment, doctype or processing instruction
+		}
+		closing := doc[m[2]:m[3]] == "/"
+		name := strings.ToLower(doc[m[4]:m[5]])
+		attrs := doc[m[6]:m[7]]
+
+		if skipping != "" {
+			if closing && name == skipping {
+				skipping = ""
+			}
+			continue
+		}
+		switch {
+		case name == "title":
+			inTitle = !closing
+		case name == "head":
+			inHead = !closing
+		case name == "body":
+			inHead = false
+		case name == "meta":
+			addMeta(&p.Meta, parseAttrs(attrs))
+		case name == "link" && p.Canonical == "":
+			if a := parseAttrs(attrs); isCanonicalRel(a["rel"]) {
+				p.Canonical = strings.TrimSpace(a["href"])
+			}
+		case skipElements[name] && !closing && !strings.HasSuffix(attrs, "/"):
+			skipping = name
+		case blockElements[name]:
+			text.WriteString("\n")
+		}
+	}
+	if skipping == "" && !inHead {
+		text.WriteString(strings.ReplaceAll(doc[last:], "\n", " "))
+	}
+
+	p.Title = collapseSpace(html.UnescapeString(title.String()))
+	if p.Title == "" {
+		p.Title = p.Meta.OGTitle
+	}
+	p.Text = cleanText(html.UnescapeString(text.String()))
+	return p
+}
+
+// parseAttrs returns a tag's attributes, keyed by lowercase name.
+func parseAttrs(s string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range attrPattern.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// isCanonicalRel reports whether a rel attribute's space-separated link
+// types include "canonical".
+func isCanonicalRel(rel string) bool {
+	for _, t := range strings.Fields(rel) {
+		if strings.EqualFold(t, "canonical") {
+			return true
+		}
+	}
+	return false
+}
+
+// addMeta records a <meta> tag in m if it is one PageMeta holds, unless an
+// earlier tag already filled that field.
+func addMeta(m *storage.PageMeta, attrs map[string]string) {
+	key := attrs["property"]
+	if key == "" {
+		key = attrs["name"]
+	}
+	if key == "" {
+		key = attrs["itemprop"]
+	}
+	value := collapseSpace(attrs["content"])
+	if value == "" {
+		return
+	}
+	set := func(field *string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	switch strings.ToLower(key) {
+	case "og:title":
+		set(&m.OGTitle)
+	case "og:description":
+		set(&m.OGDescription)
+	case "og:image", "og:image:url":
+		set(&m.OGImage)
+	case "author", "article:author", "byl":
+		set(&m.Author)
+	case "article:published_time", "datepublished", "date", "dc.date.issued":
+		if m.Published.IsZero() {
+			m.Published = parsePublished(value)
+		}
+	}
+}
+
+// collapseSpace trims s and turns each run of whitespace into one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cleanText collapses whitespace within the lines block elements ended and
+// drops blank lines.
+func cleanText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = collapseSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// publishedLayouts are the date forms pages use for their publication
+// time, tried in order.
+var publishedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parsePublished parses a publication date, returning the zero time if it
+// is in no known form.
+func parsePublished(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range publishedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,97 @@
+package webpage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runnerr0/chronicle/internal/storage"
+)
+
+const article = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Tides &amp; Moons | Example</title>
+  <link rel="stylesheet" href="/site.css">
+  <link rel="Canonical" href="/tides">
+  <meta property="og:title" content="Tides and Moons">
+  <meta property="og:description" content="Why the sea  rises twice a day.">
+  <meta property="og:image" content="https://example.com/tide.png">
+  <meta name="twitter:title" content="ignored">
+  <meta name="author" content="Ada Lovelace">
+  <meta property="article:published_time" content="2025-06-01T08:30:00+02:00">
+  <style>body { color: red; }</style>
+</head>
+<body>
+  <nav><a href="/">Home</a></nav>
+  <!-- <p>commented out</p> -->
+  <h1>Tides</h1>
+  <p>The moon pulls
+     the oceans.</p>
+  <script>var x = "<p>not text</p>";</script>
+  <p>Twice a day &mdash; mostly.</p>
+</body>
+</html>`
+
+func TestParse(t *testing.T) {
+	p := Parse(article)
+	assert.Equal(t, "Tides & Moons | Example", p.Title)
+	assert.Equal(t, "Tides\nThe moon pulls the oceans.\nTwice a day — mostly.", p.Text)
+	assert.Equal(t, "/tides", p.Canonical)
+	assert.Equal(t, storage.PageMeta{
+		OGTitle:       "Tides and Moons",
+		OGDescription: "Why the sea rises twice a day.",
+		OGImage:       "https://example.com/tide.png",
+		Author:        "Ada Lovelace",
+		Published:     time.Date(2025, 6, 1, 8, 30, 0, 0, time.FixedZone("", 2*60*60)),
+	}, p.Meta)
+}
+
+func TestParse_Fallbacks(t *testing.T) {
+	p := Parse(`<meta property='og:title' content='Only OG'><meta itemprop="datePublished" content="2024-02-29"><p>Body`)
+	assert.Equal(t, "Only OG", p.Title, "og:title stands in for a missing <title>")
+	assert.Equal(t, "Body", p.Text)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), p.Meta.Published)
+
+	p = Parse(`<meta name="date" content="last tuesday"><p>x</p>`)
+	assert.True(t, p.Meta.Published.IsZero(), "unparseable dates are dropped")
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(article)) //nolint:errcheck
+		case "/notes.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("  plain notes\n")) //nolint:errcheck
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	p, err := Fetch(ctx, srv.Client(), srv.URL+"/article")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada Lovelace", p.Meta.Author)
+	assert.Equal(t, srv.URL+"/tides", p.Canonical, "resolved against the fetched URL")
+
+	p, err = Fetch(ctx, srv.Client(), srv.URL+"/notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, &Page{Text: "plain notes"}, p)
+
+	_, err = Fetch(ctx, srv.Client(), srv.URL+"/image.png")
+	assert.ErrorContains(t, err, "unsupported content type image/png")
+
+	_, err = Fetch(ctx, srv.Client(), srv.URL+"/missing")
+	assert.ErrorContains(t, err, "404 Not Found")
+}